@@ -4,11 +4,37 @@ import (
 	"fmt"
 	"os"
 	"log"
+	"strconv"
+	"time"
 	"github.com/joho/godotenv"
+
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
 )
 
 type Config struct {
-	DBUrl string
+	DBUrl     string
+	BrokerURL string
+
+	// Scheduled bookings: pickup must be between ScheduleMinLead and
+	// ScheduleMaxLead away. Bookings are activated ActivationLead before
+	// pickup, checked every SchedulerInterval.
+	ScheduleMinLead   time.Duration
+	ScheduleMaxLead   time.Duration
+	ActivationLead    time.Duration
+	SchedulerInterval time.Duration
+
+	// TaxBps is the tax rate included in fares, in basis points, itemized on
+	// receipts.
+	TaxBps int64
+
+	// DBPool holds the DB_* connection pool settings.
+	DBPool database.PoolConfig
+	// Log holds the LOG_* logging settings.
+	Log logger.Settings
+	// AdminToken is the bearer token of the admin server's debug endpoints
+	// and of QueryAuditLog, which are disabled while it is empty.
+	AdminToken string
 }
 
 func Load() Config {
@@ -26,5 +52,51 @@ func Load() Config {
 	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		dbUser, dbPass, dbHost, dbPort, dbName)
 
-	return Config{DBUrl: dbUrl}
+	dbPool, err := database.PoolConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logSettings, err := logger.SettingsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return Config{
+		DBUrl:             dbUrl,
+		BrokerURL:         os.Getenv("BROKER_URL"),
+		ScheduleMinLead:   getDuration("SCHEDULE_MIN_LEAD", 15*time.Minute),
+		ScheduleMaxLead:   getDuration("SCHEDULE_MAX_LEAD", 7*24*time.Hour),
+		ActivationLead:    getDuration("ACTIVATION_LEAD", 10*time.Minute),
+		SchedulerInterval: getDuration("SCHEDULER_INTERVAL", 30*time.Second),
+		TaxBps:            getTaxBps(),
+
+		DBPool:     dbPool,
+		Log:        logSettings,
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	}
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, value, err)
+	}
+	return d
+}
+
+func getTaxBps() int64 {
+	value := os.Getenv("TAX_BPS")
+	if value == "" {
+		return 0
+	}
+	bps, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || bps < 0 || bps > 10000 {
+		log.Fatalf("Invalid TAX_BPS %q: want basis points between 0 and 10000", value)
+	}
+	return bps
 }
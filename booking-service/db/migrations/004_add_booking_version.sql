@@ -0,0 +1,3 @@
+ALTER TABLE bookings ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+CREATE INDEX bookings_ride_id_idx ON bookings (ride_id);
@@ -0,0 +1,2 @@
+-- Driver assigned by driver-service; NULL while no driver is assigned
+ALTER TABLE bookings ADD COLUMN driver_id INTEGER;
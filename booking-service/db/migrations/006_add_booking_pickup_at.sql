@@ -0,0 +1,4 @@
+-- Pickup time of scheduled bookings; NULL for bookings made for immediate pickup
+ALTER TABLE bookings ADD COLUMN pickup_at TIMESTAMPTZ;
+
+CREATE INDEX bookings_scheduled_pickup_idx ON bookings (pickup_at) WHERE status = 'SCHEDULED';
@@ -0,0 +1,14 @@
+-- Booking times were written as RFC3339 strings into a column without a time
+-- zone, which kept the wall-clock time and dropped the offset. The services
+-- run in UTC, so existing values are read as UTC.
+ALTER TABLE bookings RENAME COLUMN time TO created_at;
+
+UPDATE bookings SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+
+ALTER TABLE bookings
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at SET DEFAULT now(),
+    ALTER COLUMN created_at SET NOT NULL,
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+UPDATE bookings SET updated_at = created_at;
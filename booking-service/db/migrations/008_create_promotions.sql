@@ -0,0 +1,42 @@
+CREATE TABLE promotions (
+  code VARCHAR(32) PRIMARY KEY,
+  description TEXT NOT NULL DEFAULT '',
+  -- PERCENT takes percent_bps basis points off the fare, capped at
+  -- max_discount_minor when it is positive; FIXED takes amount_off_minor off
+  discount_type VARCHAR(16) NOT NULL,
+  percent_bps INTEGER NOT NULL DEFAULT 0,
+  currency CHAR(3) NOT NULL DEFAULT '',
+  amount_off_minor BIGINT NOT NULL DEFAULT 0,
+  max_discount_minor BIGINT NOT NULL DEFAULT 0,
+  starts_at TIMESTAMPTZ,
+  ends_at TIMESTAMPTZ,
+  -- Zero limits and an empty city mean no restriction
+  max_redemptions INTEGER NOT NULL DEFAULT 0,
+  max_per_user INTEGER NOT NULL DEFAULT 0,
+  first_ride_only BOOLEAN NOT NULL DEFAULT FALSE,
+  city_place_id TEXT NOT NULL DEFAULT '',
+  -- Redemptions on bookings that were not cancelled, kept in step with
+  -- promo_redemptions under the promotion's row lock
+  redemptions INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- One row per booking made with a promo code, inserted in the booking's
+-- transaction and deleted when the booking is cancelled
+CREATE TABLE promo_redemptions (
+  booking_id INTEGER PRIMARY KEY REFERENCES bookings (booking_id),
+  code VARCHAR(32) NOT NULL REFERENCES promotions (code),
+  user_id INTEGER NOT NULL,
+  currency CHAR(3) NOT NULL,
+  discount_minor BIGINT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX promo_redemptions_code_user_idx ON promo_redemptions (code, user_id);
+
+-- The discount stays on the booking after a cancellation releases the code
+ALTER TABLE bookings ADD COLUMN promo_code VARCHAR(32);
+ALTER TABLE bookings ADD COLUMN discount_currency CHAR(3);
+ALTER TABLE bookings ADD COLUMN discount_minor BIGINT;
+
+CREATE INDEX bookings_user_idx ON bookings (user_id);
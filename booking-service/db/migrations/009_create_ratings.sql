@@ -0,0 +1,37 @@
+-- One rating per booking, made by its rider once it is completed
+CREATE TABLE ratings (
+  booking_id INTEGER PRIMARY KEY REFERENCES bookings (booking_id),
+  user_id INTEGER NOT NULL,
+  driver_id INTEGER,
+  source_place_id TEXT NOT NULL DEFAULT '',
+  destination_place_id TEXT NOT NULL DEFAULT '',
+  stars SMALLINT NOT NULL CHECK (stars BETWEEN 1 AND 5),
+  comment TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE rating_tags (
+  booking_id INTEGER NOT NULL REFERENCES ratings (booking_id),
+  tag VARCHAR(32) NOT NULL,
+  PRIMARY KEY (booking_id, tag)
+);
+
+-- Running totals per subject ("driver:<id>" or "route:<source>:<destination>"),
+-- updated in the transaction that inserts each rating
+CREATE TABLE rating_stats (
+  subject TEXT PRIMARY KEY,
+  ratings_count BIGINT NOT NULL DEFAULT 0,
+  stars_1 BIGINT NOT NULL DEFAULT 0,
+  stars_2 BIGINT NOT NULL DEFAULT 0,
+  stars_3 BIGINT NOT NULL DEFAULT 0,
+  stars_4 BIGINT NOT NULL DEFAULT 0,
+  stars_5 BIGINT NOT NULL DEFAULT 0,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE rating_tag_stats (
+  subject TEXT NOT NULL,
+  tag VARCHAR(32) NOT NULL,
+  ratings_count BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (subject, tag)
+);
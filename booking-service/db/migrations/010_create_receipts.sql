@@ -0,0 +1,14 @@
+-- Receipts of completed bookings, stored as issued
+CREATE TABLE receipts (
+  booking_id INTEGER PRIMARY KEY REFERENCES bookings (booking_id),
+  invoice_number VARCHAR(32) NOT NULL UNIQUE,
+  issued_at TIMESTAMPTZ NOT NULL,
+  data JSONB NOT NULL
+);
+
+-- Last invoice number issued in each month ("YYYYMM"). The row is locked
+-- until the receipt using the number commits, so numbers have no gaps.
+CREATE TABLE invoice_sequences (
+  period CHAR(6) PRIMARY KEY,
+  last_number BIGINT NOT NULL
+);
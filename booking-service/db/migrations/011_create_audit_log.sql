@@ -0,0 +1,32 @@
+-- Append-only record of mutating calls. Each hash covers the entry's fields
+-- and prev_hash, the hash of the entry before it. before and after are JSON,
+-- not JSONB, so they keep the exact text that was hashed.
+CREATE TABLE audit_log (
+  entry_id BIGSERIAL PRIMARY KEY,
+  method TEXT NOT NULL,
+  actor TEXT NOT NULL,
+  request_id TEXT NOT NULL DEFAULT '',
+  peer TEXT NOT NULL DEFAULT '',
+  before JSON,
+  after JSON,
+  created_at TIMESTAMPTZ NOT NULL,
+  prev_hash TEXT NOT NULL,
+  hash TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX audit_log_actor_idx ON audit_log (actor, entry_id);
+CREATE INDEX audit_log_method_idx ON audit_log (method, entry_id);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update_or_delete
+  BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+  BEFORE TRUNCATE ON audit_log
+  FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
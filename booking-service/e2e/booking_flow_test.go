@@ -0,0 +1,246 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "booking-service/pb/proto/booking"
+	driverpb "driver-service/pb/proto/driver"
+	ridepb "ride-service/pb/proto/ride"
+	userpb "user-service/pb/proto/user"
+
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
+)
+
+func TestBookingFlow_CreateAndGet(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	userID := h.CreateUser(t, "Fatima")
+
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, userID))
+	require.NoError(t, err)
+	assert.Equal(t, userID, booking.UserId)
+	assert.NotZero(t, booking.BookingId)
+	assert.WithinDuration(t, time.Now(), booking.CreatedAt.AsTime(), time.Minute)
+
+	// The ride must have been created through ride-service
+	ride, err := h.RideClient.GetRide(ctx, &ridepb.GetRideRequest{RideId: booking.RideId})
+	require.NoError(t, err)
+	assert.Equal(t, "Karachi", ride.Source)
+
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, "Fatima", details.Name)
+	assert.Equal(t, "Karachi", details.Source)
+	assert.Equal(t, "Lahore", details.Destination)
+	assert.Equal(t, int32(1200), details.Distance)
+	assert.Equal(t, int32(5300), details.Cost)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 530000}, details.Price)
+	// The quote's breakdown is kept with the ride
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 50000}, details.FareBreakdown.BaseFare)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 480000}, details.FareBreakdown.DistanceFare)
+	assert.Equal(t, int32(10000), details.FareBreakdown.SurgeMultiplierBp)
+	assert.True(t, booking.CreatedAt.AsTime().Equal(details.CreatedAt.AsTime()))
+	assert.Equal(t, "ECONOMY", ride.VehicleClass)
+	assert.Equal(t, int32(1), ride.TariffVersion)
+}
+
+func TestBookingFlow_InvalidRequest(t *testing.T) {
+	h := NewHarness(t)
+
+	req := h.NewBookingRequest(t, 1)
+	req.Ride.Distance = 0
+
+	_, err := h.BookingClient.CreateBooking(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, 0, h.Bookings.count())
+}
+
+func TestBookingFlow_UnknownUser(t *testing.T) {
+	h := NewHarness(t)
+
+	_, err := h.BookingClient.CreateBooking(context.Background(), h.NewBookingRequest(t, 42))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "user not found")
+	assert.Equal(t, 0, h.Bookings.count())
+}
+
+func TestBookingFlow_RideUpdateVisibleInBooking(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Ali")))
+	require.NoError(t, err)
+
+	quote := h.Quote(t, "Karachi", "Islamabad", 1400)
+	_, err = h.RideClient.UpdateRide(ctx, &ridepb.UpdateRideRequest{
+		RideId: booking.RideId,
+		Ride: &ridepb.Ride{
+			Source:      "Karachi",
+			Destination: "Islamabad",
+			Distance:    1400,
+		},
+		Quote: quote,
+	})
+	require.NoError(t, err)
+
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, "Islamabad", details.Destination)
+	assert.Equal(t, int32(1400), details.Distance)
+	assert.Equal(t, quote.Price.MinorUnits, details.Price.MinorUnits)
+	assert.Equal(t, quote.Fare, details.Cost)
+}
+
+func TestBookingFlow_DeletedUser(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	userID := h.CreateUser(t, "Hasan")
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, userID))
+	require.NoError(t, err)
+
+	_, err = h.UserClient.DeleteUser(ctx, &userpb.DeleteUserRequest{UserId: userID})
+	require.NoError(t, err)
+
+	_, err = h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: booking.BookingId})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestBookingFlow_BookingNotFound(t *testing.T) {
+	h := NewHarness(t)
+
+	_, err := h.BookingClient.GetBooking(context.Background(), &pb.GetBookingRequest{BookingId: 99})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestBookingFlow_Cancel(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	require.NoError(t, err)
+	assert.Equal(t, "CONFIRMED", booking.Status)
+
+	_, err = h.BookingClient.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, "CANCELLED", details.Status)
+
+	_, err = h.BookingClient.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: booking.BookingId})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestBookingFlow_AssignsNearestDriver(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	// One driver near Karachi's centre and a farther one on the outskirts
+	near := h.CreateOnlineDriver(t, "Imran", &driverpb.LatLng{Lat: 24.87, Lng: 67.01})
+	h.CreateOnlineDriver(t, "Bilal", &driverpb.LatLng{Lat: 24.95, Lng: 67.10})
+
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	require.NoError(t, err)
+	assert.Equal(t, near, booking.DriverId)
+
+	driver, err := h.DriverClient.GetDriver(ctx, &driverpb.GetDriverRequest{DriverId: near})
+	require.NoError(t, err)
+	assert.Equal(t, "ASSIGNED", driver.Status)
+	assert.Equal(t, booking.BookingId, driver.BookingId)
+
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, near, details.DriverId)
+
+	// Cancelling the booking frees the driver
+	_, err = h.BookingClient.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+
+	driver, err = h.DriverClient.GetDriver(ctx, &driverpb.GetDriverRequest{DriverId: near})
+	require.NoError(t, err)
+	assert.Equal(t, "AVAILABLE", driver.Status)
+	assert.Zero(t, driver.BookingId)
+}
+
+func TestBookingFlow_NoDriverAvailable(t *testing.T) {
+	h := NewHarness(t)
+
+	// A driver in Lahore is too far from a Karachi pickup
+	h.CreateOnlineDriver(t, "Imran", &driverpb.LatLng{Lat: 31.5204, Lng: 74.3587})
+
+	booking, err := h.BookingClient.CreateBooking(context.Background(), h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	require.NoError(t, err)
+	assert.Equal(t, "CONFIRMED", booking.Status)
+	assert.Zero(t, booking.DriverId)
+}
+
+func TestBookingFlow_ScheduledBooking(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+	driverID := h.CreateOnlineDriver(t, "Imran", &driverpb.LatLng{Lat: 24.87, Lng: 67.01})
+
+	now := time.Now()
+	pickupAt := now.Add(time.Hour).Truncate(time.Second)
+	req := h.NewBookingRequest(t, h.CreateUser(t, "Fatima"))
+	req.PickupTime = timestamppb.New(pickupAt)
+
+	booking, err := h.BookingClient.CreateBooking(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "SCHEDULED", booking.Status)
+	assert.True(t, pickupAt.Equal(booking.PickupTime.AsTime()))
+	assert.Zero(t, booking.DriverId, "no driver is assigned until the booking is activated")
+
+	// Nothing is due an hour before pickup
+	activated, err := h.Scheduler.ActivateDue(ctx, now)
+	require.NoError(t, err)
+	assert.Zero(t, activated)
+
+	// Within the activation lead the booking is confirmed and gets a driver
+	activated, err = h.Scheduler.ActivateDue(ctx, now.Add(55*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, activated)
+
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, "CONFIRMED", details.Status)
+	assert.Equal(t, driverID, details.DriverId)
+	assert.True(t, pickupAt.Equal(details.PickupTime.AsTime()))
+
+	// A later run does not activate it again
+	activated, err = h.Scheduler.ActivateDue(ctx, now.Add(55*time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, activated)
+}
+
+func TestBookingFlow_PickupTooSoonRejected(t *testing.T) {
+	h := NewHarness(t)
+
+	req := h.NewBookingRequest(t, h.CreateUser(t, "Fatima"))
+	req.PickupTime = timestamppb.New(time.Now().Add(5 * time.Minute))
+
+	_, err := h.BookingClient.CreateBooking(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Zero(t, h.Bookings.count())
+}
+
+func TestBookingFlow_TamperedQuoteRejected(t *testing.T) {
+	h := NewHarness(t)
+
+	req := h.NewBookingRequest(t, h.CreateUser(t, "Hasan"))
+	req.Quote.Price.MinorUnits = 100
+
+	_, err := h.BookingClient.CreateBooking(context.Background(), req)
+	assert.Error(t, err)
+	assert.Contains(t, status.Convert(err).Message(), "invalid fare quote")
+	assert.Equal(t, 0, h.Bookings.count())
+}
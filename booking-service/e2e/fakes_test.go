@@ -0,0 +1,877 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"booking-service/promotions"
+	"booking-service/ratings"
+	"booking-service/receipts"
+	"booking-service/repository"
+	driverrepo "driver-service/repository"
+	"payment-service/ledger"
+	paymentrepo "payment-service/repository"
+	riderepo "ride-service/repository"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+// publishEvent stands in for the outbox relay: it publishes the event envelope
+// straight to the broker once the fake repository has applied a change.
+func publishEvent(broker outbox.Broker, aggregateType string, aggregateID int32, eventType string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		panic(err)
+	}
+	event := outbox.Event{
+		AggregateType: aggregateType,
+		AggregateID:   strconv.Itoa(int(aggregateID)),
+		EventType:     eventType,
+		Payload:       data,
+		OccurredAt:    time.Now(),
+	}
+	envelope, err := json.Marshal(event)
+	if err != nil {
+		panic(err)
+	}
+	broker.Publish(context.Background(), event.Subject(), envelope)
+}
+
+// fakeUserRepository is an in-memory user-service repository that mirrors the
+// error messages of the Postgres implementation.
+type fakeUserRepository struct {
+	mu     sync.Mutex
+	nextID int32
+	users  map[int32]string
+}
+
+func newFakeUserRepository() *fakeUserRepository {
+	return &fakeUserRepository{users: make(map[int32]string)}
+}
+
+func (r *fakeUserRepository) Create(ctx context.Context, name string) (int32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	r.users[r.nextID] = name
+	return r.nextID, nil
+}
+
+func (r *fakeUserRepository) GetByID(ctx context.Context, id int32) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name, ok := r.users[id]
+	if !ok {
+		return "", fmt.Errorf("user not found")
+	}
+	return name, nil
+}
+
+func (r *fakeUserRepository) Delete(ctx context.Context, id int32) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return "", fmt.Errorf("no user found to delete")
+	}
+	delete(r.users, id)
+	return fmt.Sprintf("User with ID %d deleted successfully", id), nil
+}
+
+// fakeRideRepository is an in-memory ride-service repository.
+type fakeRideRepository struct {
+	broker outbox.Broker
+
+	mu     sync.Mutex
+	nextID int32
+	rides  map[int32]riderepo.Ride
+}
+
+func newFakeRideRepository(broker outbox.Broker) *fakeRideRepository {
+	return &fakeRideRepository{broker: broker, rides: make(map[int32]riderepo.Ride)}
+}
+
+func (r *fakeRideRepository) Create(ctx context.Context, ride *riderepo.Ride) (int32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	stored := *ride
+	stored.ID = r.nextID
+	stored.CreatedAt = time.Now()
+	stored.UpdatedAt = stored.CreatedAt
+	r.rides[r.nextID] = stored
+	return r.nextID, nil
+}
+
+func (r *fakeRideRepository) GetByID(ctx context.Context, id int32) (*riderepo.Ride, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ride, ok := r.rides[id]
+	if !ok {
+		return nil, fmt.Errorf("ride not found")
+	}
+	return &ride, nil
+}
+
+func (r *fakeRideRepository) Update(ctx context.Context, id int32, update *riderepo.Ride) (string, error) {
+	legacyCost, err := riderepo.WholeUnits(update.Cost)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	ride, ok := r.rides[id]
+	if ok {
+		ride.Source = update.Source
+		ride.Destination = update.Destination
+		ride.Distance = update.Distance
+		ride.Cost = update.Cost
+		ride.VehicleClass = update.VehicleClass
+		ride.TariffVersion = update.TariffVersion
+		ride.SourceLocation = update.SourceLocation
+		ride.DestinationLocation = update.DestinationLocation
+		ride.SourcePlaceID = update.SourcePlaceID
+		ride.DestinationPlaceID = update.DestinationPlaceID
+		ride.UpdatedAt = time.Now()
+		r.rides[id] = ride
+	}
+	r.mu.Unlock()
+
+	if ok {
+		publishEvent(r.broker, riderepo.AggregateRide, id, riderepo.EventRideUpdated, riderepo.RideEvent{
+			RideID:      id,
+			Source:      update.Source,
+			Destination: update.Destination,
+			Distance:    update.Distance,
+			Cost:        legacyCost,
+			Price:       update.Cost,
+		})
+	}
+	return fmt.Sprintf("Ride %d updated successfully", id), nil
+}
+
+// fakeTariffRepository serves a single flat tariff per vehicle class.
+type fakeTariffRepository struct {
+	tariffs map[string]riderepo.Tariff
+}
+
+func newFakeTariffRepository() *fakeTariffRepository {
+	return &fakeTariffRepository{tariffs: map[string]riderepo.Tariff{
+		"ECONOMY": {VehicleClass: "ECONOMY", Version: 1, Currency: money.PKR, BaseFare: 500, PerKmRate: 4, MinimumFare: 300},
+		"PREMIUM": {VehicleClass: "PREMIUM", Version: 1, Currency: money.PKR, BaseFare: 1000, PerKmRate: 9, MinimumFare: 800},
+	}}
+}
+
+func (r *fakeTariffRepository) GetActive(ctx context.Context, vehicleClass string, at time.Time) (*riderepo.Tariff, error) {
+	tariff, ok := r.tariffs[vehicleClass]
+	if !ok {
+		return nil, fmt.Errorf("tariff not found")
+	}
+	return &tariff, nil
+}
+
+// fakeBookingRepository is an in-memory booking-service repository. It
+// redeems promo codes from promotions.
+type fakeBookingRepository struct {
+	broker     outbox.Broker
+	promotions *fakePromotionRepository
+
+	mu       sync.Mutex
+	nextID   int32
+	bookings map[int32]repository.Booking
+}
+
+func newFakeBookingRepository(broker outbox.Broker, promotions *fakePromotionRepository) *fakeBookingRepository {
+	return &fakeBookingRepository{broker: broker, promotions: promotions, bookings: make(map[int32]repository.Booking)}
+}
+
+func (r *fakeBookingRepository) publish(eventType string, b repository.Booking) {
+	event := repository.BookingEvent{
+		BookingID: b.ID,
+		UserID:    b.UserID,
+		RideID:    b.RideID,
+		Status:    b.Status,
+		DriverID:  b.DriverID,
+		Version:   b.Version,
+		PromoCode: b.PromoCode,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+	if !b.PickupAt.IsZero() {
+		event.PickupAt = &b.PickupAt
+	}
+	publishEvent(r.broker, repository.AggregateBooking, b.ID, eventType, event)
+}
+
+func (r *fakeBookingRepository) Create(ctx context.Context, userID, rideID int32, redemption *repository.Redemption) (*repository.Booking, error) {
+	r.mu.Lock()
+	now := time.Now()
+	booking := repository.Booking{
+		ID:        r.nextID + 1,
+		UserID:    userID,
+		RideID:    rideID,
+		Status:    repository.StatusConfirmed,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := r.redeem(&booking, redemption); err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	r.nextID++
+	r.bookings[booking.ID] = booking
+	r.mu.Unlock()
+
+	r.publish(repository.EventBookingCreated, booking)
+	return &booking, nil
+}
+
+func (r *fakeBookingRepository) Schedule(ctx context.Context, userID, rideID int32, pickupAt time.Time, redemption *repository.Redemption) (*repository.Booking, error) {
+	r.mu.Lock()
+	now := time.Now()
+	booking := repository.Booking{
+		ID:        r.nextID + 1,
+		UserID:    userID,
+		RideID:    rideID,
+		Status:    repository.StatusScheduled,
+		PickupAt:  pickupAt,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := r.redeem(&booking, redemption); err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	r.nextID++
+	r.bookings[booking.ID] = booking
+	r.mu.Unlock()
+
+	r.publish(repository.EventBookingScheduled, booking)
+	return &booking, nil
+}
+
+// redeem applies redemption to a booking about to be stored. r.mu must be
+// held.
+func (r *fakeBookingRepository) redeem(booking *repository.Booking, redemption *repository.Redemption) error {
+	if redemption == nil {
+		return nil
+	}
+
+	r.promotions.mu.Lock()
+	defer r.promotions.mu.Unlock()
+
+	code := promotions.Normalize(redemption.Code)
+	promotion, ok := r.promotions.promotions[code]
+	if !ok {
+		return fmt.Errorf("promotion not found")
+	}
+
+	usage := promotions.Usage{Now: redemption.Now, Fare: redemption.Fare, PickupPlaceID: redemption.PickupPlaceID}
+	for _, b := range r.bookings {
+		if b.UserID != booking.UserID || b.Status == repository.StatusCancelled {
+			continue
+		}
+		usage.PriorBookings++
+		if b.PromoCode == code {
+			usage.UserRedemptions++
+		}
+	}
+
+	discount, err := promotion.Discount(usage)
+	if err != nil {
+		return err
+	}
+	promotion.Redemptions++
+	r.promotions.promotions[code] = promotion
+	booking.PromoCode = code
+	booking.Discount = discount
+	return nil
+}
+
+func (r *fakeBookingRepository) ActivateDue(ctx context.Context, dueBy time.Time, limit int) ([]*repository.Booking, error) {
+	r.mu.Lock()
+	var due []repository.Booking
+	for _, booking := range r.bookings {
+		if booking.Status == repository.StatusScheduled && !booking.PickupAt.After(dueBy) {
+			due = append(due, booking)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].PickupAt.Before(due[j].PickupAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	activated := make([]*repository.Booking, len(due))
+	for i, booking := range due {
+		booking.Status = repository.StatusConfirmed
+		booking.Version++
+		booking.UpdatedAt = time.Now()
+		r.bookings[booking.ID] = booking
+		activated[i] = &booking
+	}
+	r.mu.Unlock()
+
+	for _, booking := range activated {
+		r.publish(repository.EventBookingActivated, *booking)
+	}
+	return activated, nil
+}
+
+func (r *fakeBookingRepository) GetByID(ctx context.Context, id int32) (*repository.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	booking, ok := r.bookings[id]
+	if !ok {
+		return nil, fmt.Errorf("booking not found")
+	}
+	return &booking, nil
+}
+
+func (r *fakeBookingRepository) Cancel(ctx context.Context, id int32) (*repository.Booking, error) {
+	r.mu.Lock()
+	booking, ok := r.bookings[id]
+	if !ok {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("booking not found")
+	}
+	switch booking.Status {
+	case repository.StatusCancelled:
+		r.mu.Unlock()
+		return nil, fmt.Errorf("booking already cancelled")
+	case repository.StatusCompleted, repository.StatusDisputed:
+		r.mu.Unlock()
+		return nil, fmt.Errorf("booking already completed")
+	}
+	booking.Status = repository.StatusCancelled
+	booking.Version++
+	booking.UpdatedAt = time.Now()
+	r.bookings[id] = booking
+	if booking.PromoCode != "" {
+		r.promotions.mu.Lock()
+		promotion := r.promotions.promotions[booking.PromoCode]
+		promotion.Redemptions--
+		r.promotions.promotions[booking.PromoCode] = promotion
+		r.promotions.mu.Unlock()
+	}
+	r.mu.Unlock()
+
+	r.publish(repository.EventBookingCancelled, booking)
+	return &booking, nil
+}
+
+func (r *fakeBookingRepository) MarkRideUpdated(ctx context.Context, rideID int32) (int, error) {
+	r.mu.Lock()
+	var updated []repository.Booking
+	for id, booking := range r.bookings {
+		if booking.RideID == rideID {
+			booking.Version++
+			booking.UpdatedAt = time.Now()
+			r.bookings[id] = booking
+			updated = append(updated, booking)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, booking := range updated {
+		r.publish(repository.EventBookingRideUpdated, booking)
+	}
+	return len(updated), nil
+}
+
+func (r *fakeBookingRepository) AssignDriver(ctx context.Context, id, driverID int32) (*repository.Booking, error) {
+	r.mu.Lock()
+	booking, ok := r.bookings[id]
+	if !ok {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("booking not found")
+	}
+	booking.DriverID = driverID
+	booking.Version++
+	booking.UpdatedAt = time.Now()
+	r.bookings[id] = booking
+	r.mu.Unlock()
+
+	r.publish(repository.EventBookingDriverAssigned, booking)
+	return &booking, nil
+}
+
+func (r *fakeBookingRepository) Complete(ctx context.Context, id int32) (*repository.Booking, error) {
+	return r.transition(id, repository.StatusConfirmed, repository.StatusCompleted, repository.EventBookingCompleted)
+}
+
+func (r *fakeBookingRepository) Dispute(ctx context.Context, id int32) (*repository.Booking, error) {
+	return r.transition(id, repository.StatusCompleted, repository.StatusDisputed, repository.EventBookingDisputed)
+}
+
+func (r *fakeBookingRepository) transition(id int32, from, to, eventType string) (*repository.Booking, error) {
+	r.mu.Lock()
+	booking, ok := r.bookings[id]
+	if !ok {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("booking not found")
+	}
+	switch booking.Status {
+	case to:
+		r.mu.Unlock()
+		return &booking, nil
+	case from:
+	default:
+		r.mu.Unlock()
+		return nil, fmt.Errorf("booking is not %s", strings.ToLower(from))
+	}
+	booking.Status = to
+	booking.Version++
+	booking.UpdatedAt = time.Now()
+	r.bookings[id] = booking
+	r.mu.Unlock()
+
+	r.publish(eventType, booking)
+	return &booking, nil
+}
+
+func (r *fakeBookingRepository) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bookings)
+}
+
+// fakeDriverRepository is an in-memory driver-service repository.
+// fakePromotionRepository is an in-memory promotion repository shared with
+// fakeBookingRepository.
+type fakePromotionRepository struct {
+	mu         sync.Mutex
+	promotions map[string]promotions.Promotion
+}
+
+func newFakePromotionRepository() *fakePromotionRepository {
+	return &fakePromotionRepository{promotions: make(map[string]promotions.Promotion)}
+}
+
+func (r *fakePromotionRepository) Create(ctx context.Context, promotion *promotions.Promotion) (*promotions.Promotion, error) {
+	if err := promotion.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.promotions[promotion.Code]; ok {
+		return nil, fmt.Errorf("promotion already exists")
+	}
+	created := *promotion
+	created.Redemptions = 0
+	created.CreatedAt = time.Now()
+	r.promotions[created.Code] = created
+	return &created, nil
+}
+
+func (r *fakePromotionRepository) GetByCode(ctx context.Context, code string) (*promotions.Promotion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	promotion, ok := r.promotions[promotions.Normalize(code)]
+	if !ok {
+		return nil, fmt.Errorf("promotion not found")
+	}
+	return &promotion, nil
+}
+
+// fakeRatingRepository is an in-memory rating repository reading bookings from
+// fakeBookingRepository.
+type fakeRatingRepository struct {
+	bookings *fakeBookingRepository
+
+	mu      sync.Mutex
+	ratings map[int32]ratings.Rating
+	stats   map[string]ratings.Stats
+}
+
+func newFakeRatingRepository(bookings *fakeBookingRepository) *fakeRatingRepository {
+	return &fakeRatingRepository{bookings: bookings, ratings: make(map[int32]ratings.Rating), stats: make(map[string]ratings.Stats)}
+}
+
+func (r *fakeRatingRepository) Rate(ctx context.Context, rating *ratings.Rating) (*ratings.Rating, error) {
+	if err := rating.Validate(); err != nil {
+		return nil, err
+	}
+	booking, err := r.bookings.GetByID(ctx, rating.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != rating.UserID {
+		return nil, fmt.Errorf("booking belongs to another user")
+	}
+	if booking.Status != repository.StatusCompleted {
+		return nil, fmt.Errorf("booking is not completed")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ratings[rating.BookingID]; ok {
+		return nil, fmt.Errorf("booking already rated")
+	}
+	rated := *rating
+	rated.DriverID = booking.DriverID
+	rated.CreatedAt = time.Now()
+	r.ratings[rated.BookingID] = rated
+
+	for _, subject := range rated.Subjects() {
+		stats := r.stats[subject]
+		stats.Count++
+		stats.Stars[rated.Stars-1]++
+		if stats.Tags == nil {
+			stats.Tags = map[string]int64{}
+		}
+		for _, tag := range rated.Tags {
+			stats.Tags[tag]++
+		}
+		r.stats[subject] = stats
+	}
+	return &rated, nil
+}
+
+func (r *fakeRatingRepository) GetStats(ctx context.Context, subject string) (*ratings.Stats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := r.stats[subject]
+	tags := make(map[string]int64, len(stats.Tags))
+	for tag, count := range stats.Tags {
+		tags[tag] = count
+	}
+	stats.Tags = tags
+	return &stats, nil
+}
+
+// fakeReceiptRepository is an in-memory receipt repository.
+type fakeReceiptRepository struct {
+	mu        sync.Mutex
+	receipts  map[int32]receipts.Receipt
+	sequences map[string]int64
+}
+
+func newFakeReceiptRepository() *fakeReceiptRepository {
+	return &fakeReceiptRepository{receipts: make(map[int32]receipts.Receipt), sequences: make(map[string]int64)}
+}
+
+func (r *fakeReceiptRepository) Issue(ctx context.Context, receipt *receipts.Receipt) (*receipts.Receipt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.receipts[receipt.BookingID]; ok {
+		return &existing, nil
+	}
+	period := receipts.Period(receipt.IssuedAt)
+	r.sequences[period]++
+	issued := *receipt
+	issued.InvoiceNumber = receipts.InvoiceNumber(issued.IssuedAt, r.sequences[period])
+	r.receipts[issued.BookingID] = issued
+	return &issued, nil
+}
+
+func (r *fakeReceiptRepository) GetByBookingID(ctx context.Context, bookingID int32) (*receipts.Receipt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	receipt, ok := r.receipts[bookingID]
+	if !ok {
+		return nil, fmt.Errorf("receipt not found")
+	}
+	return &receipt, nil
+}
+
+type fakeDriverRepository struct {
+	mu      sync.Mutex
+	nextID  int32
+	drivers map[int32]driverrepo.Driver
+}
+
+func newFakeDriverRepository() *fakeDriverRepository {
+	return &fakeDriverRepository{drivers: make(map[int32]driverrepo.Driver)}
+}
+
+func (r *fakeDriverRepository) Create(ctx context.Context, driver *driverrepo.Driver) (int32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	stored := *driver
+	stored.ID = r.nextID
+	stored.Status = driverrepo.StatusOffline
+	r.drivers[r.nextID] = stored
+	return r.nextID, nil
+}
+
+func (r *fakeDriverRepository) GetByID(ctx context.Context, id int32) (*driverrepo.Driver, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	driver, ok := r.drivers[id]
+	if !ok {
+		return nil, fmt.Errorf("driver not found")
+	}
+	return &driver, nil
+}
+
+func (r *fakeDriverRepository) ListAvailable(ctx context.Context) ([]*driverrepo.Driver, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var drivers []*driverrepo.Driver
+	for _, driver := range r.drivers {
+		if driver.Status == driverrepo.StatusAvailable && driver.Location != nil {
+			d := driver
+			drivers = append(drivers, &d)
+		}
+	}
+	return drivers, nil
+}
+
+func (r *fakeDriverRepository) SetAvailability(ctx context.Context, id int32, online bool) (*driverrepo.Driver, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	driver, ok := r.drivers[id]
+	if !ok {
+		return nil, fmt.Errorf("driver not found")
+	}
+	if driver.Status == driverrepo.StatusAssigned {
+		return nil, fmt.Errorf("driver is on a trip")
+	}
+	driver.Status = driverrepo.StatusOffline
+	if online {
+		driver.Status = driverrepo.StatusAvailable
+	}
+	r.drivers[id] = driver
+	return &driver, nil
+}
+
+func (r *fakeDriverRepository) UpdateLocation(ctx context.Context, id int32, location driverrepo.Location) (*driverrepo.Driver, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	driver, ok := r.drivers[id]
+	if !ok {
+		return nil, fmt.Errorf("driver not found")
+	}
+	driver.Location = &location
+	driver.LocationUpdatedAt = time.Now()
+	r.drivers[id] = driver
+	return &driver, nil
+}
+
+func (r *fakeDriverRepository) Assign(ctx context.Context, bookingID int32, pickup driverrepo.Location, candidates []int32) (*driverrepo.Assignment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, driver := range r.drivers {
+		if driver.BookingID == bookingID {
+			return &driverrepo.Assignment{BookingID: bookingID, Driver: &driver}, nil
+		}
+	}
+
+	for _, id := range candidates {
+		driver, ok := r.drivers[id]
+		if !ok || driver.Status != driverrepo.StatusAvailable {
+			continue
+		}
+		driver.Status = driverrepo.StatusAssigned
+		driver.BookingID = bookingID
+		r.drivers[id] = driver
+		return &driverrepo.Assignment{BookingID: bookingID, Driver: &driver}, nil
+	}
+	return nil, fmt.Errorf("no driver available")
+}
+
+func (r *fakeDriverRepository) Release(ctx context.Context, bookingID int32) (*driverrepo.Driver, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, driver := range r.drivers {
+		if driver.BookingID == bookingID {
+			driver.Status = driverrepo.StatusAvailable
+			driver.BookingID = 0
+			r.drivers[id] = driver
+			return &driver, nil
+		}
+	}
+	return nil, fmt.Errorf("assignment not found")
+}
+
+// fakePaymentRepository is an in-memory payment-service repository.
+type fakePaymentRepository struct {
+	mu       sync.Mutex
+	nextID   int32
+	payments map[int32]paymentrepo.Payment
+	ledger   map[string]paymentrepo.LedgerEntry
+}
+
+func newFakePaymentRepository() *fakePaymentRepository {
+	return &fakePaymentRepository{
+		payments: make(map[int32]paymentrepo.Payment),
+		ledger:   make(map[string]paymentrepo.LedgerEntry),
+	}
+}
+
+func (r *fakePaymentRepository) Create(ctx context.Context, bookingID, userID int32, amount, discount money.Money) (*paymentrepo.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if payment, ok := r.payments[bookingID]; ok {
+		return &payment, nil
+	}
+	r.nextID++
+	now := time.Now()
+	zero := money.Money{Currency: amount.Currency}
+	payment := paymentrepo.Payment{
+		ID:        r.nextID,
+		BookingID: bookingID,
+		UserID:    userID,
+		Status:    paymentrepo.StatusPending,
+		Amount:    amount,
+		Discount:  discount,
+		Captured:  zero,
+		Refunded:  zero,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.payments[bookingID] = payment
+	return &payment, nil
+}
+
+func (r *fakePaymentRepository) GetByBookingID(ctx context.Context, bookingID int32) (*paymentrepo.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payment, ok := r.payments[bookingID]
+	if !ok {
+		return nil, fmt.Errorf("payment not found")
+	}
+	return &payment, nil
+}
+
+func (r *fakePaymentRepository) Record(ctx context.Context, bookingID int32, entry paymentrepo.LedgerEntry) (*paymentrepo.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payment, ok := r.payments[bookingID]
+	if !ok {
+		return nil, fmt.Errorf("payment not found")
+	}
+	if _, ok := r.ledger[entry.IdempotencyKey]; ok {
+		return &payment, nil
+	}
+	if err := payment.Apply(entry); err != nil {
+		return nil, err
+	}
+	payment.UpdatedAt = time.Now()
+	r.payments[bookingID] = payment
+	r.ledger[entry.IdempotencyKey] = entry
+	return &payment, nil
+}
+
+// fakeLedgerRepository is an in-memory payment-service ledger. A single lock
+// stands in for the account row locks, so entries post atomically.
+type fakeLedgerRepository struct {
+	mu       sync.Mutex
+	nextID   int64
+	accounts map[string]ledger.Account
+	entries  map[string]ledger.Entry
+}
+
+func newFakeLedgerRepository() *fakeLedgerRepository {
+	return &fakeLedgerRepository{
+		accounts: make(map[string]ledger.Account),
+		entries:  make(map[string]ledger.Entry),
+	}
+}
+
+func (r *fakeLedgerRepository) Post(ctx context.Context, entry ledger.Entry) (*ledger.Entry, error) {
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if posted, ok := r.entries[entry.IdempotencyKey]; ok {
+		return &posted, nil
+	}
+
+	// Apply the postings to copies so a failed entry changes nothing
+	changed := make(map[string]ledger.Account)
+	for _, p := range entry.Postings {
+		account, ok := changed[p.Account]
+		if !ok {
+			if account, ok = r.accounts[p.Account]; !ok {
+				opened, err := ledger.NewAccount(p.Account, p.Amount.Currency)
+				if err != nil {
+					return nil, err
+				}
+				account = *opened
+			}
+		}
+		if err := account.Apply(p); err != nil {
+			return nil, err
+		}
+		changed[p.Account] = account
+	}
+	for code, account := range changed {
+		r.accounts[code] = account
+	}
+
+	r.nextID++
+	entry.ID = r.nextID
+	entry.CreatedAt = time.Now()
+	r.entries[entry.IdempotencyKey] = entry
+	return &entry, nil
+}
+
+func (r *fakeLedgerRepository) GetEntry(ctx context.Context, idempotencyKey string) (*ledger.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[idempotencyKey]
+	if !ok {
+		return nil, fmt.Errorf("entry not found")
+	}
+	return &entry, nil
+}
+
+func (r *fakeLedgerRepository) GetAccount(ctx context.Context, code string) (*ledger.Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	account, ok := r.accounts[code]
+	if !ok {
+		return nil, fmt.Errorf("account not found")
+	}
+	return &account, nil
+}
+
+// CheckInvariants replays every entry into fresh accounts and compares them
+// with the balances kept as entries were posted.
+func (r *fakeLedgerRepository) CheckInvariants(ctx context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var violations []string
+	replayed := make(map[string]*ledger.Account)
+	for key, entry := range r.entries {
+		if err := entry.Validate(); err != nil {
+			violations = append(violations, fmt.Sprintf("entry %s: %v", key, err))
+		}
+		for _, p := range entry.Postings {
+			if replayed[p.Account] == nil {
+				replayed[p.Account], _ = ledger.NewAccount(p.Account, p.Amount.Currency)
+			}
+			// Replay in any order, so overdrafts along the way are not errors
+			balance := replayed[p.Account].Balance
+			if replayed[p.Account].Type == ledger.TypeAsset {
+				balance.Minor += p.Amount.Minor
+			} else {
+				balance.Minor -= p.Amount.Minor
+			}
+			replayed[p.Account].Balance = balance
+		}
+	}
+	for code, account := range r.accounts {
+		if replayed[code] == nil || replayed[code].Balance != account.Balance {
+			violations = append(violations, fmt.Sprintf("account %s has balance %s but postings disagree", code, account.Balance))
+		}
+		if account.Balance.IsNegative() && strings.HasSuffix(code, ":wallet") {
+			violations = append(violations, fmt.Sprintf("wallet %s is overdrawn", code))
+		}
+	}
+	return violations, nil
+}
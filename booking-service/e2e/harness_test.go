@@ -0,0 +1,320 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"booking-service/events"
+	pb "booking-service/pb/proto/booking"
+	"booking-service/scheduler"
+	bookingserver "booking-service/server"
+	"driver-service/geoindex"
+	driverpb "driver-service/pb/proto/driver"
+	driverserver "driver-service/server"
+	paymentpb "payment-service/pb/proto/payment"
+	"payment-service/provider"
+	paymentserver "payment-service/server"
+	"ride-service/gazetteer"
+	"ride-service/geo"
+	ridepb "ride-service/pb/proto/ride"
+	"ride-service/pricing"
+	rideserver "ride-service/server"
+	userpb "user-service/pb/proto/user"
+	userserver "user-service/server"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+const bufSize = 1024 * 1024
+
+// testTaxBps is the tax rate included in fares on the harness's receipts.
+const testTaxBps = 1600
+
+// Harness boots UserServer, RideServer, DriverServer, PaymentServer and
+// BookingServer in-process on bufconn listeners. BookingServer talks to the
+// others through real gRPC clients, so every call crosses the wire exactly as
+// it does between the containers. The fake repositories publish their domain
+// events to a shared in-process broker in place of the outbox relay.
+type Harness struct {
+	Broker *outbox.InProcessBroker
+
+	Users      *fakeUserRepository
+	Rides      *fakeRideRepository
+	Drivers    *fakeDriverRepository
+	Bookings   *fakeBookingRepository
+	Promotions *fakePromotionRepository
+	Ratings    *fakeRatingRepository
+	Receipts   *fakeReceiptRepository
+	Payments   *fakePaymentRepository
+	Ledger     *fakeLedgerRepository
+
+	// PaymentProvider is the fake card provider behind PaymentServer. Tests
+	// make it decline or time out with SetRates.
+	PaymentProvider *provider.Fake
+
+	UserClient    userpb.UserServiceClient
+	RideClient    ridepb.RideServiceClient
+	DriverClient  driverpb.DriverServiceClient
+	PaymentClient paymentpb.PaymentServiceClient
+	BookingClient pb.BookingServiceClient
+
+	// Scheduler activates scheduled bookings when a test calls ActivateDue;
+	// it does not poll on its own.
+	Scheduler *scheduler.Scheduler
+
+	// Calls records the request ID every service saw on each call.
+	Calls *callRecorder
+}
+
+// NewHarness starts all services and registers their shutdown with t.Cleanup.
+func NewHarness(t *testing.T) *Harness {
+	t.Helper()
+
+	broker := outbox.NewInProcessBroker()
+	promotions := newFakePromotionRepository()
+	h := &Harness{
+		Broker:     broker,
+		Users:      newFakeUserRepository(),
+		Rides:      newFakeRideRepository(broker),
+		Drivers:    newFakeDriverRepository(),
+		Bookings:   newFakeBookingRepository(broker, promotions),
+		Promotions: promotions,
+		Receipts:   newFakeReceiptRepository(),
+		Payments:   newFakePaymentRepository(),
+		Ledger:     newFakeLedgerRepository(),
+
+		PaymentProvider: provider.NewFake(provider.FakeRates{}, 1),
+		Calls:           &callRecorder{},
+	}
+	h.Ratings = newFakeRatingRepository(h.Bookings)
+
+	eventLogger := logger.NewLogger("booking-service")
+	if _, err := events.SubscribeRideUpdates(broker, h.Bookings, eventLogger); err != nil {
+		t.Fatalf("failed to subscribe to ride updates: %v", err)
+	}
+	feed := events.NewFeed(eventLogger)
+	if _, err := feed.Start(broker); err != nil {
+		t.Fatalf("failed to start booking feed: %v", err)
+	}
+
+	userConn := startServer(t, h.Calls, func(s *grpc.Server) {
+		userpb.RegisterUserServiceServer(s, userserver.NewUserServer(h.Users))
+	})
+	h.UserClient = userpb.NewUserServiceClient(userConn)
+
+	places, err := gazetteer.Default()
+	if err != nil {
+		t.Fatalf("failed to load gazetteer: %v", err)
+	}
+	pricingEngine := pricing.NewEngine(newFakeTariffRepository(), pricing.NewSigner([]byte("e2e-signing-key")), time.UTC, time.Minute, nil)
+	rideConn := startServer(t, h.Calls, func(s *grpc.Server) {
+		ridepb.RegisterRideServiceServer(s, rideserver.NewRideServer(h.Rides, pricingEngine, geo.HaversineProvider{}, places))
+	})
+	h.RideClient = ridepb.NewRideServiceClient(rideConn)
+
+	driverConn := startServer(t, h.Calls, func(s *grpc.Server) {
+		driverpb.RegisterDriverServiceServer(s, driverserver.NewDriverServer(h.Drivers, geoindex.New(geoindex.DefaultCellSizeDeg)))
+	})
+	h.DriverClient = driverpb.NewDriverServiceClient(driverConn)
+
+	paymentConn := startServer(t, h.Calls, func(s *grpc.Server) {
+		paymentpb.RegisterPaymentServiceServer(s, paymentserver.NewPaymentServer(h.Payments, h.Ledger, h.PaymentProvider,
+			h.DriverClient, paymentserver.WithRetryBackoff(time.Millisecond)))
+	})
+	h.PaymentClient = paymentpb.NewPaymentServiceClient(paymentConn)
+
+	bookingServer := bookingserver.NewBookingServer(
+		h.Bookings,
+		userpb.NewUserServiceClient(userConn),
+		ridepb.NewRideServiceClient(rideConn),
+		driverpb.NewDriverServiceClient(driverConn),
+		paymentpb.NewPaymentServiceClient(paymentConn),
+		bookingserver.WithFeed(feed),
+		bookingserver.WithPromotions(h.Promotions),
+		bookingserver.WithRatings(h.Ratings),
+		bookingserver.WithReceipts(h.Receipts, testTaxBps),
+	)
+	bookingConn := startServer(t, h.Calls, func(s *grpc.Server) {
+		pb.RegisterBookingServiceServer(s, bookingServer)
+	})
+	h.BookingClient = pb.NewBookingServiceClient(bookingConn)
+	h.Scheduler = scheduler.New(h.Bookings, bookingServer, 10*time.Minute, eventLogger)
+
+	return h
+}
+
+// startServer serves a gRPC server on a fresh bufconn listener and returns a
+// client connection dialed through it.
+func startServer(t *testing.T, calls *callRecorder, register func(*grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(bufSize)
+	requestLogger := logger.NewLogger("e2e")
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logger.UnaryServerInterceptor(requestLogger), calls.intercept),
+		grpc.ChainStreamInterceptor(logger.StreamServerInterceptor(requestLogger)),
+	)
+	register(grpcServer)
+
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			t.Logf("bufconn server stopped: %v", err)
+		}
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(logger.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial bufconn: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		grpcServer.Stop()
+	})
+
+	return conn
+}
+
+// call is a call a service handled and the request ID it was tagged with.
+type call struct {
+	Method    string
+	RequestID string
+}
+
+// callRecorder records the calls every service handles.
+type callRecorder struct {
+	mu    sync.Mutex
+	calls []call
+}
+
+func (r *callRecorder) intercept(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, call{Method: info.FullMethod, RequestID: logger.RequestID(ctx)})
+	r.mu.Unlock()
+	return handler(ctx, req)
+}
+
+// WithRequestID returns the calls handled under requestID, in order.
+func (r *callRecorder) WithRequestID(requestID string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var methods []string
+	for _, c := range r.calls {
+		if c.RequestID == requestID {
+			methods = append(methods, c.Method)
+		}
+	}
+	return methods
+}
+
+// CreateUser creates a user through the user-service API and returns its ID.
+func (h *Harness) CreateUser(t *testing.T, name string) int32 {
+	t.Helper()
+
+	res, err := h.UserClient.CreateUser(context.Background(), &userpb.CreateUserRequest{Name: name})
+	if err != nil {
+		t.Fatalf("CreateUser(%q) failed: %v", name, err)
+	}
+	return res.UserId
+}
+
+// CreateOnlineDriver registers an ECONOMY driver through the driver-service
+// API, reports their location and takes them online. It returns the driver ID.
+func (h *Harness) CreateOnlineDriver(t *testing.T, name string, location *driverpb.LatLng) int32 {
+	t.Helper()
+
+	ctx := context.Background()
+	res, err := h.DriverClient.CreateDriver(ctx, &driverpb.CreateDriverRequest{
+		Name:  name,
+		Phone: "+923000000000",
+		Vehicle: &driverpb.Vehicle{
+			VehicleClass: "ECONOMY",
+			Make:         "Suzuki",
+			Model:        "Alto",
+			PlateNumber:  fmt.Sprintf("KHI-%s", name),
+			Color:        "White",
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateDriver(%q) failed: %v", name, err)
+	}
+	if _, err := h.DriverClient.UpdateLocation(ctx, &driverpb.UpdateLocationRequest{DriverId: res.DriverId, Location: location}); err != nil {
+		t.Fatalf("UpdateLocation(%d) failed: %v", res.DriverId, err)
+	}
+	if _, err := h.DriverClient.SetAvailability(ctx, &driverpb.SetAvailabilityRequest{DriverId: res.DriverId, Online: true}); err != nil {
+		t.Fatalf("SetAvailability(%d) failed: %v", res.DriverId, err)
+	}
+	return res.DriverId
+}
+
+// Quote quotes a ride through ride-service.
+func (h *Harness) Quote(t *testing.T, source, destination string, distance int32) *ridepb.FareQuote {
+	t.Helper()
+
+	quote, err := h.RideClient.QuoteFare(context.Background(), &ridepb.QuoteFareRequest{
+		Source:      source,
+		Destination: destination,
+		Distance:    distance,
+	})
+	if err != nil {
+		t.Fatalf("QuoteFare failed: %v", err)
+	}
+	return quote
+}
+
+// NewBookingRequest quotes a Karachi to Lahore ride through ride-service and
+// returns a booking request carrying that quote.
+func (h *Harness) NewBookingRequest(t *testing.T, userID int32) *pb.CreateBookingRequest {
+	t.Helper()
+
+	quote := h.Quote(t, "Karachi", "Lahore", 1200)
+
+	return &pb.CreateBookingRequest{
+		UserId: userID,
+		Ride: &pb.Ride{
+			Source:      quote.Source,
+			Destination: quote.Destination,
+			Distance:    quote.Distance,
+		},
+		Quote: &pb.FareQuote{
+			Source:        quote.Source,
+			Destination:   quote.Destination,
+			Distance:      quote.Distance,
+			VehicleClass:  quote.VehicleClass,
+			Fare:          quote.Fare,
+			Price:         quote.Price,
+			TariffVersion: quote.TariffVersion,
+			ExpiresAt:     quote.ExpiresAt,
+			Signature:     quote.Signature,
+
+			SurgeMultiplierBp: quote.SurgeMultiplierBp,
+			SurgeExpiresAt:    quote.SurgeExpiresAt,
+
+			Breakdown: &pb.FareBreakdown{
+				BaseFare:              quote.Breakdown.BaseFare,
+				DistanceFare:          quote.Breakdown.DistanceFare,
+				MinimumFareAdjustment: quote.Breakdown.MinimumFareAdjustment,
+				TimeOfDayMultiplierBp: quote.Breakdown.TimeOfDayMultiplierBp,
+				TimeOfDayAdjustment:   quote.Breakdown.TimeOfDayAdjustment,
+				SurgeMultiplierBp:     quote.Breakdown.SurgeMultiplierBp,
+				SurgeAdjustment:       quote.Breakdown.SurgeAdjustment,
+			},
+			BreakdownSignature: quote.BreakdownSignature,
+		},
+	}
+}
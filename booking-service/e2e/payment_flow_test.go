@@ -0,0 +1,185 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "booking-service/pb/proto/booking"
+	driverpb "driver-service/pb/proto/driver"
+	paymentpb "payment-service/pb/proto/payment"
+	"payment-service/provider"
+
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
+)
+
+// karachiToLahore is the fare of the harness's booking request.
+var karachiToLahore = &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 530000}
+
+func (h *Harness) getPayment(t *testing.T, bookingID int32) *paymentpb.Payment {
+	t.Helper()
+
+	payment, err := h.PaymentClient.GetPayment(context.Background(), &paymentpb.GetPaymentRequest{BookingId: bookingID})
+	require.NoError(t, err)
+	return payment
+}
+
+func TestPaymentFlow_CaptureOnCompletion(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	driverID := h.CreateOnlineDriver(t, "Imran", &driverpb.LatLng{Lat: 24.87, Lng: 67.01})
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	require.NoError(t, err)
+
+	payment := h.getPayment(t, booking.BookingId)
+	assert.Equal(t, "AUTHORIZED", payment.Status)
+	assert.Equal(t, karachiToLahore, payment.Amount)
+
+	res, err := h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, karachiToLahore, res.Charged)
+	assert.Equal(t, "CAPTURED", h.getPayment(t, booking.BookingId).Status)
+
+	// Completing again does not charge twice
+	res, err = h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, karachiToLahore, res.Charged)
+
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, "COMPLETED", details.Status)
+
+	driver, err := h.DriverClient.GetDriver(ctx, &driverpb.GetDriverRequest{DriverId: driverID})
+	require.NoError(t, err)
+	assert.Equal(t, "AVAILABLE", driver.Status)
+
+	_, err = h.BookingClient.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: booking.BookingId})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestPaymentFlow_VoidOnCancellation(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	require.NoError(t, err)
+
+	_, err = h.BookingClient.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, "VOIDED", h.getPayment(t, booking.BookingId).Status)
+
+	_, err = h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: booking.BookingId})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestPaymentFlow_RefundOnDispute(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	require.NoError(t, err)
+
+	// Only completed bookings can be disputed
+	dispute := &pb.DisputeBookingRequest{BookingId: booking.BookingId, Reason: "driver never arrived"}
+	_, err = h.BookingClient.DisputeBooking(ctx, dispute)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+
+	res, err := h.BookingClient.DisputeBooking(ctx, dispute)
+	require.NoError(t, err)
+	assert.Equal(t, karachiToLahore, res.Refunded)
+
+	payment := h.getPayment(t, booking.BookingId)
+	assert.Equal(t, "REFUNDED", payment.Status)
+	assert.Equal(t, karachiToLahore, payment.Refunded)
+
+	// Disputing again does not refund twice
+	res, err = h.BookingClient.DisputeBooking(ctx, dispute)
+	require.NoError(t, err)
+	assert.Equal(t, karachiToLahore, res.Refunded)
+
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, "DISPUTED", details.Status)
+}
+
+func TestPaymentFlow_DeclinedBookingCancelled(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	driverID := h.CreateOnlineDriver(t, "Imran", &driverpb.LatLng{Lat: 24.87, Lng: 67.01})
+	h.PaymentProvider.SetRates(provider.FakeRates{Decline: 1})
+
+	_, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "insufficient funds")
+
+	// The unpaid booking is cancelled and no driver is held for it
+	require.Equal(t, 1, h.Bookings.count())
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "CANCELLED", details.Status)
+	assert.Equal(t, "DECLINED", h.getPayment(t, 1).Status)
+
+	driver, err := h.DriverClient.GetDriver(ctx, &driverpb.GetDriverRequest{DriverId: driverID})
+	require.NoError(t, err)
+	assert.Equal(t, "AVAILABLE", driver.Status)
+}
+
+func TestPaymentFlow_ProviderDown(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	h.PaymentProvider.SetRates(provider.FakeRates{Timeout: 1})
+
+	_, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "CANCELLED", details.Status)
+
+	// The payment stays PENDING until the provider answers, then is voided
+	assert.Equal(t, "PENDING", h.getPayment(t, 1).Status)
+
+	h.PaymentProvider.SetRates(provider.FakeRates{})
+	payment, err := h.PaymentClient.VoidPayment(ctx, &paymentpb.VoidPaymentRequest{BookingId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "VOIDED", payment.Status)
+}
+
+func TestPaymentFlow_DriverEarnings(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	driverID := h.CreateOnlineDriver(t, "Imran", &driverpb.LatLng{Lat: 24.87, Lng: 67.01})
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	require.NoError(t, err)
+
+	_, err = h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+
+	// The driver earns the fare less 20% commission
+	earnings, err := h.PaymentClient.GetDriverEarnings(ctx, &paymentpb.GetDriverEarningsRequest{DriverId: driverID})
+	require.NoError(t, err)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 424000}, earnings.Balance)
+
+	// A dispute takes the refund back out of the driver's earnings
+	_, err = h.BookingClient.DisputeBooking(ctx, &pb.DisputeBookingRequest{BookingId: booking.BookingId, Reason: "overcharged"})
+	require.NoError(t, err)
+
+	earnings, err = h.PaymentClient.GetDriverEarnings(ctx, &paymentpb.GetDriverEarningsRequest{DriverId: driverID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), earnings.Balance.MinorUnits)
+
+	violations, err := h.Ledger.CheckInvariants(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,167 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "booking-service/pb/proto/booking"
+	driverpb "driver-service/pb/proto/driver"
+	paymentpb "payment-service/pb/proto/payment"
+
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
+)
+
+func (h *Harness) createPromotion(t *testing.T, promotion *pb.Promotion) {
+	t.Helper()
+
+	_, err := h.BookingClient.CreatePromotion(context.Background(), &pb.CreatePromotionRequest{Promotion: promotion})
+	require.NoError(t, err)
+}
+
+func (h *Harness) bookWithCode(t *testing.T, userID int32, code string) (*pb.Booking, error) {
+	t.Helper()
+
+	req := h.NewBookingRequest(t, userID)
+	req.PromoCode = code
+	return h.BookingClient.CreateBooking(context.Background(), req)
+}
+
+func TestPromoFlow_DiscountItemizedAndCharged(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	// 20% off a first ride from Karachi, up to Rs 500
+	h.createPromotion(t, &pb.Promotion{
+		Code:          "karachi20",
+		DiscountType:  "PERCENT",
+		PercentBps:    2000,
+		MaxDiscount:   &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 50000},
+		FirstRideOnly: true,
+		CityPlaceId:   "pk-khi",
+	})
+
+	booking, err := h.bookWithCode(t, h.CreateUser(t, "Fatima"), "Karachi20")
+	require.NoError(t, err)
+	assert.Equal(t, "KARACHI20", booking.PromoCode)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 50000}, booking.Discount)
+
+	// Only the discounted fare is held and charged
+	discounted := &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 480000}
+	assert.Equal(t, discounted, h.getPayment(t, booking.BookingId).Amount)
+
+	details, err := h.BookingClient.GetBooking(ctx, &pb.GetBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, karachiToLahore, details.Price)
+	assert.Equal(t, "KARACHI20", details.PromoCode)
+	assert.Equal(t, booking.Discount, details.Discount)
+	assert.Equal(t, discounted, details.Total)
+
+	res, err := h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, discounted, res.Charged)
+
+	promotion, err := h.BookingClient.GetPromotion(ctx, &pb.GetPromotionRequest{Code: "KARACHI20"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), promotion.Redemptions)
+}
+
+func TestPromoFlow_FreeRideEarnings(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	h.createPromotion(t, &pb.Promotion{
+		Code:         "FREERIDE",
+		DiscountType: "FIXED",
+		AmountOff:    &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 1000000},
+	})
+	driverID := h.CreateOnlineDriver(t, "Imran", &driverpb.LatLng{Lat: 24.87, Lng: 67.01})
+
+	booking, err := h.bookWithCode(t, h.CreateUser(t, "Fatima"), "FREERIDE")
+	require.NoError(t, err)
+	assert.Equal(t, karachiToLahore, booking.Discount)
+
+	res, err := h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res.Charged.MinorUnits)
+
+	// The rider pays nothing, but the driver still earns the fare less 20%
+	// commission, paid for by the platform
+	earnings, err := h.PaymentClient.GetDriverEarnings(ctx, &paymentpb.GetDriverEarningsRequest{DriverId: driverID})
+	require.NoError(t, err)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 424000}, earnings.Balance)
+
+	violations, err := h.Ledger.CheckInvariants(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestPromoFlow_UsageLimits(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	h.createPromotion(t, &pb.Promotion{
+		Code:           "ONCE",
+		DiscountType:   "FIXED",
+		AmountOff:      &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 10000},
+		MaxRedemptions: 1,
+		MaxPerUser:     1,
+	})
+	fatima := h.CreateUser(t, "Fatima")
+	ali := h.CreateUser(t, "Ali")
+
+	booking, err := h.bookWithCode(t, fatima, "ONCE")
+	require.NoError(t, err)
+
+	// The code is used up, and a booking that cannot redeem it is not made
+	_, err = h.bookWithCode(t, ali, "ONCE")
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, 1, h.Bookings.count())
+
+	// Cancelling the booking gives the code back
+	_, err = h.BookingClient.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+	_, err = h.bookWithCode(t, ali, "ONCE")
+	require.NoError(t, err)
+
+	promotion, err := h.BookingClient.GetPromotion(ctx, &pb.GetPromotionRequest{Code: "once"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), promotion.Redemptions)
+}
+
+func TestPromoFlow_Eligibility(t *testing.T) {
+	h := NewHarness(t)
+
+	h.createPromotion(t, &pb.Promotion{Code: "FIRST", DiscountType: "PERCENT", PercentBps: 1000, FirstRideOnly: true})
+	h.createPromotion(t, &pb.Promotion{Code: "LAHORE", DiscountType: "PERCENT", PercentBps: 1000, CityPlaceId: "pk-lhe"})
+	h.createPromotion(t, &pb.Promotion{Code: "TWICE", DiscountType: "PERCENT", PercentBps: 1000, MaxPerUser: 2})
+	userID := h.CreateUser(t, "Fatima")
+
+	_, err := h.bookWithCode(t, userID, "TWICE")
+	require.NoError(t, err)
+	_, err = h.bookWithCode(t, userID, "TWICE")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		code     string
+		expected codes.Code
+	}{
+		{name: "Per User Limit", code: "TWICE", expected: codes.FailedPrecondition},
+		{name: "Not First Ride", code: "FIRST", expected: codes.FailedPrecondition},
+		{name: "Wrong City", code: "LAHORE", expected: codes.FailedPrecondition},
+		{name: "Unknown Code", code: "NOPE", expected: codes.NotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := h.bookWithCode(t, userID, tc.code)
+			assert.Equal(t, tc.expected, status.Code(err))
+		})
+	}
+	assert.Equal(t, 2, h.Bookings.count())
+}
@@ -0,0 +1,65 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "booking-service/pb/proto/booking"
+	driverpb "driver-service/pb/proto/driver"
+)
+
+func TestRatingFlow(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	driverID := h.CreateOnlineDriver(t, "Imran", &driverpb.LatLng{Lat: 24.87, Lng: 67.01})
+	fatima := h.CreateUser(t, "Fatima")
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, fatima))
+	require.NoError(t, err)
+	require.Equal(t, driverID, booking.DriverId)
+
+	// A booking can only be rated once the ride is over
+	rate := &pb.RateBookingRequest{BookingId: booking.BookingId, UserId: fatima, Stars: 4, Tags: []string{"on_time"}}
+	_, err = h.BookingClient.RateBooking(ctx, rate)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+
+	// Only by its rider
+	_, err = h.BookingClient.RateBooking(ctx, &pb.RateBookingRequest{BookingId: booking.BookingId, UserId: h.CreateUser(t, "Ali"), Stars: 1})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	rating, err := h.BookingClient.RateBooking(ctx, rate)
+	require.NoError(t, err)
+	assert.Equal(t, driverID, rating.DriverId)
+	assert.Equal(t, []string{"ON_TIME"}, rating.Tags)
+
+	// And only once
+	_, err = h.BookingClient.RateBooking(ctx, &pb.RateBookingRequest{BookingId: booking.BookingId, UserId: fatima, Stars: 5})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// The rating counts towards both the driver and the route
+	for _, req := range []*pb.GetRatingStatsRequest{
+		{DriverId: driverID},
+		{SourcePlaceId: "pk-khi", DestinationPlaceId: "pk-lhe"},
+	} {
+		stats, err := h.BookingClient.GetRatingStats(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats.Count)
+		assert.Equal(t, 4.0, stats.Average)
+		assert.Equal(t, []int64{0, 0, 0, 1, 0}, stats.Stars)
+		assert.Equal(t, map[string]int64{"ON_TIME": 1}, stats.Tags)
+	}
+
+	// The reverse route has no ratings
+	stats, err := h.BookingClient.GetRatingStats(ctx, &pb.GetRatingStatsRequest{SourcePlaceId: "pk-lhe", DestinationPlaceId: "pk-khi"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), stats.Count)
+	assert.Equal(t, []int64{0, 0, 0, 0, 0}, stats.Stars)
+}
@@ -0,0 +1,76 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "booking-service/pb/proto/booking"
+	"booking-service/receipts"
+
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
+)
+
+func TestReceiptFlow(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	h.createPromotion(t, &pb.Promotion{
+		Code:         "FLAT500",
+		DiscountType: "FIXED",
+		AmountOff:    &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 50000},
+	})
+	fatima := h.CreateUser(t, "Fatima")
+	first, err := h.bookWithCode(t, fatima, "FLAT500")
+	require.NoError(t, err)
+
+	// No receipt until the ride is over
+	_, err = h.BookingClient.GetReceipt(ctx, &pb.GetReceiptRequest{BookingId: first.BookingId})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: first.BookingId})
+	require.NoError(t, err)
+
+	receipt, err := h.BookingClient.GetReceipt(ctx, &pb.GetReceiptRequest{BookingId: first.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("INV-%s-000001", receipts.Period(time.Now())), receipt.InvoiceNumber)
+	assert.Equal(t, "Fatima", receipt.UserName)
+	assert.Equal(t, "Karachi", receipt.Source)
+	assert.Equal(t, "Lahore", receipt.Destination)
+	// One line per fare component, net of 16% tax
+	assert.Equal(t, []*pb.ReceiptItem{
+		{Description: "Base fare (ECONOMY)", Amount: &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 43103}},
+		{Description: "Distance (1200 km)", Amount: &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 413793}},
+		{Description: "Promo code FLAT500", Amount: &moneypb.Money{CurrencyCode: "PKR", MinorUnits: -43103}},
+		{Description: "Tax (16%)", Amount: &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 66207}},
+	}, receipt.Items)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 480000}, receipt.Total)
+	assert.Equal(t, int64(testTaxBps), receipt.TaxRateBps)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 66207}, receipt.Tax)
+	assert.Contains(t, receipt.Html, "PKR 4,800.00")
+	assert.Contains(t, string(receipt.Pdf), "(PKR 4,800.00) Tj")
+
+	// Completing again does not issue another receipt
+	_, err = h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: first.BookingId})
+	require.NoError(t, err)
+	again, err := h.BookingClient.GetReceipt(ctx, &pb.GetReceiptRequest{BookingId: first.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, receipt.InvoiceNumber, again.InvoiceNumber)
+	assert.Equal(t, receipt.IssuedAt.AsTime(), again.IssuedAt.AsTime())
+
+	// The next receipt of the month takes the next number
+	second, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, fatima))
+	require.NoError(t, err)
+	_, err = h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: second.BookingId})
+	require.NoError(t, err)
+	receipt, err = h.BookingClient.GetReceipt(ctx, &pb.GetReceiptRequest{BookingId: second.BookingId})
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("INV-%s-000002", receipts.Period(time.Now())), receipt.InvoiceNumber)
+	assert.Len(t, receipt.Items, 3)
+}
@@ -0,0 +1,44 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+)
+
+func TestRequestID_PropagatesToDownstreamCalls(t *testing.T) {
+	h := NewHarness(t)
+	req := h.NewBookingRequest(t, h.CreateUser(t, "Fatima"))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), logger.RequestIDHeader, "e2e-req-1")
+	var header metadata.MD
+	_, err := h.BookingClient.CreateBooking(ctx, req, grpc.Header(&header))
+	require.NoError(t, err)
+
+	// The caller's ID is echoed back and sent on to user-service and ride-service
+	assert.Equal(t, []string{"e2e-req-1"}, header.Get(logger.RequestIDHeader))
+	calls := h.Calls.WithRequestID("e2e-req-1")
+	assert.Equal(t, "/booking.BookingService/CreateBooking", calls[0])
+	assert.Subset(t, calls, []string{"/user.UserService/GetUser", "/ride.RideService/CreateRide"})
+}
+
+func TestRequestID_GeneratedWhenMissing(t *testing.T) {
+	h := NewHarness(t)
+	req := h.NewBookingRequest(t, h.CreateUser(t, "Ali"))
+
+	var header metadata.MD
+	_, err := h.BookingClient.CreateBooking(context.Background(), req, grpc.Header(&header))
+	require.NoError(t, err)
+
+	require.Len(t, header.Get(logger.RequestIDHeader), 1)
+	requestID := header.Get(logger.RequestIDHeader)[0]
+	assert.Len(t, requestID, 32)
+	assert.Subset(t, h.Calls.WithRequestID(requestID),
+		[]string{"/booking.BookingService/CreateBooking", "/user.UserService/GetUser", "/ride.RideService/CreateRide"})
+}
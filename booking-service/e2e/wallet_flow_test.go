@@ -0,0 +1,154 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "booking-service/pb/proto/booking"
+	driverpb "driver-service/pb/proto/driver"
+	paymentpb "payment-service/pb/proto/payment"
+	"payment-service/provider"
+
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
+)
+
+func pkr(minor int64) *moneypb.Money {
+	return &moneypb.Money{CurrencyCode: "PKR", MinorUnits: minor}
+}
+
+func TestWallet_TopUpAndDebit(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+	userID := h.CreateUser(t, "Fatima")
+
+	_, err := h.PaymentClient.GetWallet(ctx, &paymentpb.GetWalletRequest{UserId: userID})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	topUp := &paymentpb.TopUpWalletRequest{UserId: userID, Amount: pkr(100000), IdempotencyKey: "topup-1"}
+	wallet, err := h.PaymentClient.TopUpWallet(ctx, topUp)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(100000), wallet.Balance)
+
+	// Retrying the top-up does not charge or credit twice
+	wallet, err = h.PaymentClient.TopUpWallet(ctx, topUp)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(100000), wallet.Balance)
+
+	wallet, err = h.PaymentClient.DebitWallet(ctx, &paymentpb.DebitWalletRequest{
+		UserId: userID, Amount: pkr(30000), IdempotencyKey: "debit-1", Reason: "tip",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, pkr(70000), wallet.Balance)
+
+	_, err = h.PaymentClient.DebitWallet(ctx, &paymentpb.DebitWalletRequest{
+		UserId: userID, Amount: pkr(70001), IdempotencyKey: "debit-2",
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	wallet, err = h.PaymentClient.GetWallet(ctx, &paymentpb.GetWalletRequest{UserId: userID})
+	require.NoError(t, err)
+	assert.Equal(t, pkr(70000), wallet.Balance)
+}
+
+func TestWallet_TopUpDeclined(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+	userID := h.CreateUser(t, "Fatima")
+
+	h.PaymentProvider.SetRates(provider.FakeRates{Decline: 1})
+	_, err := h.PaymentClient.TopUpWallet(ctx, &paymentpb.TopUpWalletRequest{
+		UserId: userID, Amount: pkr(100000), IdempotencyKey: "topup-1",
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = h.PaymentClient.GetWallet(ctx, &paymentpb.GetWalletRequest{UserId: userID})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestWallet_ConcurrentDebitsNeverOverdraw(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+	userID := h.CreateUser(t, "Fatima")
+
+	_, err := h.PaymentClient.TopUpWallet(ctx, &paymentpb.TopUpWalletRequest{
+		UserId: userID, Amount: pkr(100000), IdempotencyKey: "topup-1",
+	})
+	require.NoError(t, err)
+
+	// Twenty debits race for a balance that covers ten
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := h.PaymentClient.DebitWallet(ctx, &paymentpb.DebitWalletRequest{
+				UserId: userID, Amount: pkr(10000), IdempotencyKey: fmt.Sprintf("debit-%d", i),
+			})
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			} else {
+				assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, succeeded)
+	wallet, err := h.PaymentClient.GetWallet(ctx, &paymentpb.GetWalletRequest{UserId: userID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), wallet.Balance.MinorUnits)
+
+	violations, err := h.Ledger.CheckInvariants(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestWallet_ConcurrentBookingsAccrueEarnings(t *testing.T) {
+	h := NewHarness(t)
+	ctx := context.Background()
+
+	// Each booking takes the one driver in turn, so complete them one at a
+	// time
+	driverID := h.CreateOnlineDriver(t, "Imran", &driverpb.LatLng{Lat: 24.87, Lng: 67.01})
+	var bookingIDs []int32
+	for i := 0; i < 5; i++ {
+		booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+		require.NoError(t, err)
+		_, err = h.BookingClient.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: booking.BookingId})
+		require.NoError(t, err)
+		bookingIDs = append(bookingIDs, booking.BookingId)
+	}
+
+	// Concurrent capture retries post each booking's earnings once
+	var wg sync.WaitGroup
+	for _, id := range bookingIDs {
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func(id int32) {
+				defer wg.Done()
+				_, err := h.PaymentClient.CapturePayment(ctx, &paymentpb.CapturePaymentRequest{BookingId: id, DriverId: driverID})
+				assert.NoError(t, err)
+			}(id)
+		}
+	}
+	wg.Wait()
+
+	earnings, err := h.PaymentClient.GetDriverEarnings(ctx, &paymentpb.GetDriverEarningsRequest{DriverId: driverID})
+	require.NoError(t, err)
+	assert.Equal(t, pkr(5*424000), earnings.Balance)
+
+	violations, err := h.Ledger.CheckInvariants(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,104 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "booking-service/pb/proto/booking"
+	ridepb "ride-service/pb/proto/ride"
+)
+
+func TestWatchBooking_StreamsStatusAndRideChanges(t *testing.T) {
+	h := NewHarness(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Fatima")))
+	require.NoError(t, err)
+
+	stream, err := h.BookingClient.WatchBooking(ctx, &pb.WatchBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+
+	// The current state is sent first
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), update.Version)
+	assert.Equal(t, "Fatima", update.Booking.Name)
+	assert.Equal(t, "CONFIRMED", update.Booking.Status)
+
+	// A ride change in ride-service reaches the watcher through the broker
+	_, err = h.RideClient.UpdateRide(ctx, &ridepb.UpdateRideRequest{
+		RideId: booking.RideId,
+		Ride: &ridepb.Ride{
+			Source:      "Karachi",
+			Destination: "Islamabad",
+			Distance:    1400,
+		},
+		Quote: h.Quote(t, "Karachi", "Islamabad", 1400),
+	})
+	require.NoError(t, err)
+
+	update, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), update.Version)
+	assert.Equal(t, "Islamabad", update.Booking.Destination)
+
+	_, err = h.BookingClient.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+
+	update, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), update.Version)
+	assert.Equal(t, "CANCELLED", update.Booking.Status)
+}
+
+func TestWatchBooking_ResumeFromVersion(t *testing.T) {
+	h := NewHarness(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	booking, err := h.BookingClient.CreateBooking(ctx, h.NewBookingRequest(t, h.CreateUser(t, "Ali")))
+	require.NoError(t, err)
+	_, err = h.BookingClient.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: booking.BookingId})
+	require.NoError(t, err)
+
+	// A client that last saw version 1 gets the missed cancellation immediately
+	stream, err := h.BookingClient.WatchBooking(ctx, &pb.WatchBookingRequest{
+		BookingId:   booking.BookingId,
+		FromVersion: 1,
+	})
+	require.NoError(t, err)
+
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), update.Version)
+	assert.Equal(t, "CANCELLED", update.Booking.Status)
+
+	// A client that is already up to date receives nothing until the next change
+	upToDateCtx, upToDateCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer upToDateCancel()
+	upToDate, err := h.BookingClient.WatchBooking(upToDateCtx, &pb.WatchBookingRequest{
+		BookingId:   booking.BookingId,
+		FromVersion: 2,
+	})
+	require.NoError(t, err)
+
+	_, err = upToDate.Recv()
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestWatchBooking_NotFound(t *testing.T) {
+	h := NewHarness(t)
+
+	stream, err := h.BookingClient.WatchBooking(context.Background(), &pb.WatchBookingRequest{BookingId: 99})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"booking-service/repository"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+// Feed fans booking events received from the broker out to in-process
+// listeners keyed by booking ID. A single broker subscription serves every
+// WatchBooking stream.
+type Feed struct {
+	logger *logger.Logger
+
+	mu        sync.Mutex
+	listeners map[int32]map[chan struct{}]struct{}
+}
+
+func NewFeed(log *logger.Logger) *Feed {
+	return &Feed{
+		logger:    log,
+		listeners: make(map[int32]map[chan struct{}]struct{}),
+	}
+}
+
+// Start subscribes the feed to all booking events on broker.
+func (f *Feed) Start(broker outbox.Broker) (outbox.Subscription, error) {
+	return broker.Subscribe(repository.AggregateBooking+".>", f.handle)
+}
+
+// Listen returns a channel that receives a signal whenever bookingID changes,
+// and a function that must be called to stop listening. Signals are coalesced:
+// a listener that falls behind sees one pending signal rather than many.
+func (f *Feed) Listen(bookingID int32) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	f.mu.Lock()
+	if f.listeners[bookingID] == nil {
+		f.listeners[bookingID] = make(map[chan struct{}]struct{})
+	}
+	f.listeners[bookingID][ch] = struct{}{}
+	f.mu.Unlock()
+
+	stop := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.listeners[bookingID], ch)
+		if len(f.listeners[bookingID]) == 0 {
+			delete(f.listeners, bookingID)
+		}
+	}
+	return ch, stop
+}
+
+// Notify signals every listener of bookingID.
+func (f *Feed) Notify(bookingID int32) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.listeners[bookingID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (f *Feed) handle(ctx context.Context, msg outbox.Message) {
+	var event outbox.Event
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		f.logger.Error("failed to decode booking event", "error", err, "subject", msg.Subject)
+		return
+	}
+
+	bookingID, err := strconv.ParseInt(event.AggregateID, 10, 32)
+	if err != nil {
+		f.logger.Error("invalid booking event aggregate ID", "error", err, "aggregate_id", event.AggregateID)
+		return
+	}
+
+	f.Notify(int32(bookingID))
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"booking-service/repository"
+	riderepo "ride-service/repository"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+// queueGroup shares ride events among booking-service replicas, so each event
+// is applied once.
+const queueGroup = "booking-service"
+
+// SubscribeRideUpdates consumes RideUpdated events from ride-service and bumps
+// the version of every booking for that ride, which in turn publishes a
+// BookingRideUpdated event through the outbox.
+func SubscribeRideUpdates(broker outbox.Broker, repo repository.BookingRepository, log *logger.Logger) (outbox.Subscription, error) {
+	subject := outbox.Subject(riderepo.AggregateRide, riderepo.EventRideUpdated)
+	return broker.QueueSubscribe(subject, queueGroup, func(ctx context.Context, msg outbox.Message) {
+		var event outbox.Event
+		var payload riderepo.RideEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			log.Error("failed to decode ride event", "error", err)
+			return
+		}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			log.Error("failed to decode ride event payload", "error", err, "event_id", event.ID)
+			return
+		}
+
+		n, err := repo.MarkRideUpdated(ctx, payload.RideID)
+		if err != nil {
+			log.Error("failed to mark bookings for updated ride", "error", err, "ride_id", payload.RideID)
+			return
+		}
+		log.Info("bookings marked for updated ride", "ride_id", payload.RideID, "bookings", n)
+	})
+}
@@ -4,7 +4,6 @@ go 1.24.2
 
 require (
 	github.com/joho/godotenv v1.5.1
-	github.com/prometheus/client_golang v1.22.0
 	github.com/stretchr/testify v1.10.0
 )
 
@@ -12,11 +11,13 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/prometheus/client_golang v1.22.0 // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
+	driver-service v0.0.0
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/hasnain-zafar/go-microservices/common v0.0.0
@@ -30,6 +31,7 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
 	google.golang.org/grpc v1.72.0
 	google.golang.org/protobuf v1.36.5
+	payment-service v0.0.0
 	ride-service v0.0.0
 	user-service v0.0.0
 )
@@ -39,3 +41,7 @@ replace github.com/hasnain-zafar/go-microservices/common => ../common
 replace user-service => ../user-service
 
 replace ride-service => ../ride-service
+
+replace driver-service => ../driver-service
+
+replace payment-service => ../payment-service
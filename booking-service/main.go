@@ -1,77 +1,158 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
 	"log"
 	"net"
-	"net/http"
 
 	_ "github.com/lib/pq"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
 	"booking-service/config"
+	"booking-service/events"
 	pb "booking-service/pb/proto/booking"
 	"booking-service/repository"
+	"booking-service/scheduler"
 	"booking-service/server"
+	driverpb "driver-service/pb/proto/driver"
+	paymentpb "payment-service/pb/proto/payment"
 	ridepb "ride-service/pb/proto/ride"
 	userpb "user-service/pb/proto/user"
 
+	"github.com/hasnain-zafar/go-microservices/common/admin"
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
 	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
 )
 
 func main() {
 	// Initialize Prometheus metrics
 	metrics.Init()
-
-	// Start metrics HTTP server in a goroutine
-	go startMetricsServer("booking-service", 2114)
+	registry := metrics.NewRegistry("booking-service")
 
 	cfg := config.Load()
+	logger.Configure(cfg.Log)
 
-	db, err := sql.Open("postgres", cfg.DBUrl)
+	// Wait for Postgres, which may still be starting
+	db, err := database.Open(context.Background(), "postgres", cfg.DBUrl, cfg.DBPool)
 	if err != nil {
-		log.Fatalf("❌ Failed to connect to DB: %v", err)
-		metrics.IncrementErrorCounter("booking-service", "db_connection")
+		log.Fatalf("❌ DB not reachable: %v", err)
 	}
 	defer db.Close()
+	registry.RegisterDBStats(db, "bookings_db")
+
+	fmt.Println("✅ Connected to bookings_db")
 
-	err = db.Ping()
+	// Serve metrics and, with ADMIN_TOKEN set, the debug endpoints
+	conns := admin.NewConnTracker()
+	adminServer := admin.NewServer("booking-service", cfg.AdminToken, registry,
+		admin.WithConfig(cfg),
+		admin.WithDB(db),
+		admin.WithConnections(conns),
+	)
+	go startAdminServer(adminServer, "booking-service", 2114)
+
+	broker, err := outbox.NewBroker(context.Background(), cfg.BrokerURL)
 	if err != nil {
-		log.Fatalf("❌ Cannot ping DB: %v", err)
-		metrics.IncrementErrorCounter("booking-service", "db_ping")
+		log.Fatalf("❌ Failed to connect to message broker: %v", err)
+	}
+	defer broker.Close()
+
+	// Publish domain events written to the outbox by the repository
+	relay := outbox.NewRelay(db, broker, logger.NewLogger("booking-service"))
+	go relay.Run(context.Background())
+
+	// Calls to other services carry the request ID, and name booking-service
+	// as the caller for their audit logs
+	dialOpts := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(logger.UnaryClientInterceptor(), audit.UnaryClientInterceptor("booking-service")),
 	}
-	fmt.Println("✅ Connected to bookings_db")
 
 	// Update connection from localhost to container names
-	userConn, err := grpc.Dial("user-service:50051", grpc.WithInsecure())
+	userConn, err := grpc.Dial("user-service:50051", dialOpts...)
 	if err != nil {
 		log.Fatalf("❌ Failed to connect to user-service: %v", err)
-		metrics.IncrementErrorCounter("booking-service", "user_service_connection")
+		registry.IncrementError("user_service_connection")
 	}
 	defer userConn.Close()
 	userClient := userpb.NewUserServiceClient(userConn)
 
-	rideConn, err := grpc.Dial("ride-service:50052", grpc.WithInsecure())
+	rideConn, err := grpc.Dial("ride-service:50052", dialOpts...)
 	if err != nil {
 		log.Fatalf("❌ Failed to connect to ride-service: %v", err)
-		metrics.IncrementErrorCounter("booking-service", "ride_service_connection")
+		registry.IncrementError("ride_service_connection")
 	}
 	defer rideConn.Close()
 	rideClient := ridepb.NewRideServiceClient(rideConn)
 
+	driverConn, err := grpc.Dial("driver-service:50054", dialOpts...)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to driver-service: %v", err)
+		registry.IncrementError("driver_service_connection")
+	}
+	defer driverConn.Close()
+	driverClient := driverpb.NewDriverServiceClient(driverConn)
+
+	paymentConn, err := grpc.Dial("payment-service:50055", dialOpts...)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to payment-service: %v", err)
+		registry.IncrementError("payment_service_connection")
+	}
+	defer paymentConn.Close()
+	paymentClient := paymentpb.NewPaymentServiceClient(paymentConn)
+
 	bookingRepo := repository.NewPostgresBookingRepository(db)
+	promotionRepo := repository.NewPostgresPromotionRepository(db)
+	ratingRepo := repository.NewPostgresRatingRepository(db)
+	receiptRepo := repository.NewPostgresReceiptRepository(db)
+
+	// Keep booking versions in step with ride changes published by ride-service
+	eventLogger := logger.NewLogger("booking-service")
+	if _, err := events.SubscribeRideUpdates(broker, bookingRepo, eventLogger); err != nil {
+		log.Fatalf("❌ Failed to subscribe to ride updates: %v", err)
+	}
 
-	bookingServer := server.NewBookingServer(bookingRepo, userClient, rideClient)
+	// Fan booking events out to WatchBooking streams
+	feed := events.NewFeed(eventLogger)
+	if _, err := feed.Start(broker); err != nil {
+		log.Fatalf("❌ Failed to subscribe to booking events: %v", err)
+	}
+
+	bookingServer := server.NewBookingServer(bookingRepo, userClient, rideClient, driverClient, paymentClient,
+		server.WithFeed(feed),
+		server.WithScheduleWindow(cfg.ScheduleMinLead, cfg.ScheduleMaxLead),
+		server.WithPromotions(promotionRepo),
+		server.WithRatings(ratingRepo),
+		server.WithReceipts(receiptRepo, cfg.TaxBps),
+		server.WithAuditLog(audit.NewPostgresLog(db)),
+		server.WithMetrics(registry),
+	)
+
+	// Activate scheduled bookings shortly before pickup
+	bookingScheduler := scheduler.New(bookingRepo, bookingServer, cfg.ActivationLead, eventLogger)
+	go bookingScheduler.Run(context.Background(), cfg.SchedulerInterval)
 
 	listener, err := net.Listen("tcp", ":50053")
 	if err != nil {
 		log.Fatalf("❌ Failed to listen on port 50053: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	// Tag every call with a request ID for its log lines, and with the actor
+	// its caller authenticated as for the audit log
+	requestLogger := logger.NewLogger("booking-service")
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(conns),
+		grpc.ChainUnaryInterceptor(
+			logger.UnaryServerInterceptor(requestLogger),
+			audit.NewAuthenticator(cfg.AdminToken).UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(logger.StreamServerInterceptor(requestLogger)),
+	)
 	pb.RegisterBookingServiceServer(grpcServer, bookingServer)
 
 	reflection.Register(grpcServer)
@@ -82,11 +163,9 @@ func main() {
 	}
 }
 
-func startMetricsServer(serviceName string, port int) {
-	fmt.Printf("📊 Metrics server for %s starting on :%d\n", serviceName, port)
-	http.Handle("/metrics", promhttp.Handler())
-	err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-	if err != nil {
-		log.Fatalf("❌ Failed to start metrics server: %v", err)
+func startAdminServer(adminServer *admin.Server, serviceName string, port int) {
+	fmt.Printf("📊 Admin server for %s starting on :%d\n", serviceName, port)
+	if err := adminServer.ListenAndServe(port); err != nil {
+		log.Fatalf("❌ Failed to start admin server: %v", err)
 	}
 }
@@ -7,8 +7,12 @@
 package pb
 
 import (
+	audit "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+	_ "github.com/hasnain-zafar/go-microservices/common/pb/proto/logging"
+	money "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,19 +25,78 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type Ride struct {
+// LatLng is a WGS84 coordinate in decimal degrees.
+type LatLng struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
-	Destination   string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
-	Distance      int32                  `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
-	Cost          int32                  `protobuf:"varint,4,opt,name=cost,proto3" json:"cost,omitempty"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lng           float64                `protobuf:"fixed64,2,opt,name=lng,proto3" json:"lng,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *LatLng) Reset() {
+	*x = LatLng{}
+	mi := &file_proto_booking_booking_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatLng) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatLng) ProtoMessage() {}
+
+func (x *LatLng) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatLng.ProtoReflect.Descriptor instead.
+func (*LatLng) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LatLng) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *LatLng) GetLng() float64 {
+	if x != nil {
+		return x.Lng
+	}
+	return 0
+}
+
+type Ride struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Source      string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Distance    int32                  `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	// Ignored: the ride's cost is taken from the fare quote.
+	//
+	// Deprecated: Marked as deprecated in proto/booking/booking.proto.
+	Cost int32 `protobuf:"varint,4,opt,name=cost,proto3" json:"cost,omitempty"`
+	// Optional pickup and drop-off coordinates, forwarded to ride-service.
+	SourceLocation      *LatLng `protobuf:"bytes,5,opt,name=source_location,json=sourceLocation,proto3" json:"source_location,omitempty"`
+	DestinationLocation *LatLng `protobuf:"bytes,6,opt,name=destination_location,json=destinationLocation,proto3" json:"destination_location,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
 func (x *Ride) Reset() {
 	*x = Ride{}
-	mi := &file_proto_booking_booking_proto_msgTypes[0]
+	mi := &file_proto_booking_booking_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -45,7 +108,7 @@ func (x *Ride) String() string {
 func (*Ride) ProtoMessage() {}
 
 func (x *Ride) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_booking_booking_proto_msgTypes[0]
+	mi := &file_proto_booking_booking_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -58,7 +121,7 @@ func (x *Ride) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Ride.ProtoReflect.Descriptor instead.
 func (*Ride) Descriptor() ([]byte, []int) {
-	return file_proto_booking_booking_proto_rawDescGZIP(), []int{0}
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Ride) GetSource() string {
@@ -82,6 +145,7 @@ func (x *Ride) GetDistance() int32 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in proto/booking/booking.proto.
 func (x *Ride) GetCost() int32 {
 	if x != nil {
 		return x.Cost
@@ -89,31 +153,51 @@ func (x *Ride) GetCost() int32 {
 	return 0
 }
 
-type Booking struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	BookingId     int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
-	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	RideId        int32                  `protobuf:"varint,3,opt,name=ride_id,json=rideId,proto3" json:"ride_id,omitempty"`
-	Time          string                 `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Ride) GetSourceLocation() *LatLng {
+	if x != nil {
+		return x.SourceLocation
+	}
+	return nil
 }
 
-func (x *Booking) Reset() {
-	*x = Booking{}
-	mi := &file_proto_booking_booking_proto_msgTypes[1]
+func (x *Ride) GetDestinationLocation() *LatLng {
+	if x != nil {
+		return x.DestinationLocation
+	}
+	return nil
+}
+
+// FareBreakdown itemizes a fare. The adjustments are what the minimum fare
+// and the multipliers added, so the amounts add up to the fare.
+type FareBreakdown struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	BaseFare              *money.Money           `protobuf:"bytes,1,opt,name=base_fare,json=baseFare,proto3" json:"base_fare,omitempty"`
+	DistanceFare          *money.Money           `protobuf:"bytes,2,opt,name=distance_fare,json=distanceFare,proto3" json:"distance_fare,omitempty"`
+	MinimumFareAdjustment *money.Money           `protobuf:"bytes,3,opt,name=minimum_fare_adjustment,json=minimumFareAdjustment,proto3" json:"minimum_fare_adjustment,omitempty"`
+	// Multipliers in basis points (10000 = none).
+	TimeOfDayMultiplierBp int32        `protobuf:"varint,4,opt,name=time_of_day_multiplier_bp,json=timeOfDayMultiplierBp,proto3" json:"time_of_day_multiplier_bp,omitempty"`
+	TimeOfDayAdjustment   *money.Money `protobuf:"bytes,5,opt,name=time_of_day_adjustment,json=timeOfDayAdjustment,proto3" json:"time_of_day_adjustment,omitempty"`
+	SurgeMultiplierBp     int32        `protobuf:"varint,6,opt,name=surge_multiplier_bp,json=surgeMultiplierBp,proto3" json:"surge_multiplier_bp,omitempty"`
+	SurgeAdjustment       *money.Money `protobuf:"bytes,7,opt,name=surge_adjustment,json=surgeAdjustment,proto3" json:"surge_adjustment,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *FareBreakdown) Reset() {
+	*x = FareBreakdown{}
+	mi := &file_proto_booking_booking_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Booking) String() string {
+func (x *FareBreakdown) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Booking) ProtoMessage() {}
+func (*FareBreakdown) ProtoMessage() {}
 
-func (x *Booking) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_booking_booking_proto_msgTypes[1]
+func (x *FareBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -124,66 +208,102 @@ func (x *Booking) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Booking.ProtoReflect.Descriptor instead.
-func (*Booking) Descriptor() ([]byte, []int) {
-	return file_proto_booking_booking_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use FareBreakdown.ProtoReflect.Descriptor instead.
+func (*FareBreakdown) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Booking) GetBookingId() int32 {
+func (x *FareBreakdown) GetBaseFare() *money.Money {
 	if x != nil {
-		return x.BookingId
+		return x.BaseFare
 	}
-	return 0
+	return nil
 }
 
-func (x *Booking) GetUserId() int32 {
+func (x *FareBreakdown) GetDistanceFare() *money.Money {
 	if x != nil {
-		return x.UserId
+		return x.DistanceFare
+	}
+	return nil
+}
+
+func (x *FareBreakdown) GetMinimumFareAdjustment() *money.Money {
+	if x != nil {
+		return x.MinimumFareAdjustment
+	}
+	return nil
+}
+
+func (x *FareBreakdown) GetTimeOfDayMultiplierBp() int32 {
+	if x != nil {
+		return x.TimeOfDayMultiplierBp
 	}
 	return 0
 }
 
-func (x *Booking) GetRideId() int32 {
+func (x *FareBreakdown) GetTimeOfDayAdjustment() *money.Money {
 	if x != nil {
-		return x.RideId
+		return x.TimeOfDayAdjustment
+	}
+	return nil
+}
+
+func (x *FareBreakdown) GetSurgeMultiplierBp() int32 {
+	if x != nil {
+		return x.SurgeMultiplierBp
 	}
 	return 0
 }
 
-func (x *Booking) GetTime() string {
+func (x *FareBreakdown) GetSurgeAdjustment() *money.Money {
 	if x != nil {
-		return x.Time
+		return x.SurgeAdjustment
 	}
-	return ""
+	return nil
 }
 
-type BookingDetails struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
-	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
-	Distance      int32                  `protobuf:"varint,4,opt,name=distance,proto3" json:"distance,omitempty"`
-	Cost          int32                  `protobuf:"varint,5,opt,name=cost,proto3" json:"cost,omitempty"`
-	Time          string                 `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// FareQuote is a signed quote obtained from ride.RideService/QuoteFare and
+// passed through unchanged.
+type FareQuote struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Source       string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination  string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Distance     int32                  `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	VehicleClass string                 `protobuf:"bytes,4,opt,name=vehicle_class,json=vehicleClass,proto3" json:"vehicle_class,omitempty"`
+	// Whole units of price's currency, for clients that predate price.
+	//
+	// Deprecated: Marked as deprecated in proto/booking/booking.proto.
+	Fare          int32                  `protobuf:"varint,5,opt,name=fare,proto3" json:"fare,omitempty"`
+	TariffVersion int32                  `protobuf:"varint,6,opt,name=tariff_version,json=tariffVersion,proto3" json:"tariff_version,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Signature     string                 `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Demand multiplier included in price, in basis points (10000 = no surge).
+	SurgeMultiplierBp int32                  `protobuf:"varint,9,opt,name=surge_multiplier_bp,json=surgeMultiplierBp,proto3" json:"surge_multiplier_bp,omitempty"`
+	SurgeExpiresAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=surge_expires_at,json=surgeExpiresAt,proto3" json:"surge_expires_at,omitempty"`
+	Price             *money.Money           `protobuf:"bytes,11,opt,name=price,proto3" json:"price,omitempty"`
+	// Optional itemization of price, signed separately so that quotes without
+	// it still verify.
+	Breakdown          *FareBreakdown `protobuf:"bytes,12,opt,name=breakdown,proto3" json:"breakdown,omitempty"`
+	BreakdownSignature string         `protobuf:"bytes,13,opt,name=breakdown_signature,json=breakdownSignature,proto3" json:"breakdown_signature,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
-func (x *BookingDetails) Reset() {
-	*x = BookingDetails{}
-	mi := &file_proto_booking_booking_proto_msgTypes[2]
+func (x *FareQuote) Reset() {
+	*x = FareQuote{}
+	mi := &file_proto_booking_booking_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BookingDetails) String() string {
+func (x *FareQuote) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BookingDetails) ProtoMessage() {}
+func (*FareQuote) ProtoMessage() {}
 
-func (x *BookingDetails) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_booking_booking_proto_msgTypes[2]
+func (x *FareQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -194,76 +314,138 @@ func (x *BookingDetails) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BookingDetails.ProtoReflect.Descriptor instead.
-func (*BookingDetails) Descriptor() ([]byte, []int) {
-	return file_proto_booking_booking_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use FareQuote.ProtoReflect.Descriptor instead.
+func (*FareQuote) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *BookingDetails) GetName() string {
+func (x *FareQuote) GetSource() string {
 	if x != nil {
-		return x.Name
+		return x.Source
 	}
 	return ""
 }
 
-func (x *BookingDetails) GetSource() string {
+func (x *FareQuote) GetDestination() string {
 	if x != nil {
-		return x.Source
+		return x.Destination
 	}
 	return ""
 }
 
-func (x *BookingDetails) GetDestination() string {
+func (x *FareQuote) GetDistance() int32 {
 	if x != nil {
-		return x.Destination
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *FareQuote) GetVehicleClass() string {
+	if x != nil {
+		return x.VehicleClass
 	}
 	return ""
 }
 
-func (x *BookingDetails) GetDistance() int32 {
+// Deprecated: Marked as deprecated in proto/booking/booking.proto.
+func (x *FareQuote) GetFare() int32 {
 	if x != nil {
-		return x.Distance
+		return x.Fare
 	}
 	return 0
 }
 
-func (x *BookingDetails) GetCost() int32 {
+func (x *FareQuote) GetTariffVersion() int32 {
 	if x != nil {
-		return x.Cost
+		return x.TariffVersion
 	}
 	return 0
 }
 
-func (x *BookingDetails) GetTime() string {
+func (x *FareQuote) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *FareQuote) GetSignature() string {
 	if x != nil {
-		return x.Time
+		return x.Signature
 	}
 	return ""
 }
 
-type CreateBookingRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Ride          *Ride                  `protobuf:"bytes,2,opt,name=ride,proto3" json:"ride,omitempty"`
+func (x *FareQuote) GetSurgeMultiplierBp() int32 {
+	if x != nil {
+		return x.SurgeMultiplierBp
+	}
+	return 0
+}
+
+func (x *FareQuote) GetSurgeExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SurgeExpiresAt
+	}
+	return nil
+}
+
+func (x *FareQuote) GetPrice() *money.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *FareQuote) GetBreakdown() *FareBreakdown {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
+func (x *FareQuote) GetBreakdownSignature() string {
+	if x != nil {
+		return x.BreakdownSignature
+	}
+	return ""
+}
+
+type Booking struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookingId int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	UserId    int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RideId    int32                  `protobuf:"varint,3,opt,name=ride_id,json=rideId,proto3" json:"ride_id,omitempty"`
+	// SCHEDULED, CONFIRMED, CANCELLED, COMPLETED or DISPUTED.
+	Status string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	// Assigned driver, or 0 if none was available.
+	DriverId int32 `protobuf:"varint,6,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	// Set for scheduled bookings.
+	PickupTime *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=pickup_time,json=pickupTime,proto3" json:"pickup_time,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Promo code redeemed with the booking and what it took off the fare.
+	PromoCode     string       `protobuf:"bytes,10,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	Discount      *money.Money `protobuf:"bytes,11,opt,name=discount,proto3" json:"discount,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateBookingRequest) Reset() {
-	*x = CreateBookingRequest{}
-	mi := &file_proto_booking_booking_proto_msgTypes[3]
+func (x *Booking) Reset() {
+	*x = Booking{}
+	mi := &file_proto_booking_booking_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateBookingRequest) String() string {
+func (x *Booking) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateBookingRequest) ProtoMessage() {}
+func (*Booking) ProtoMessage() {}
 
-func (x *CreateBookingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_booking_booking_proto_msgTypes[3]
+func (x *Booking) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -274,47 +456,123 @@ func (x *CreateBookingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateBookingRequest.ProtoReflect.Descriptor instead.
-func (*CreateBookingRequest) Descriptor() ([]byte, []int) {
-	return file_proto_booking_booking_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use Booking.ProtoReflect.Descriptor instead.
+func (*Booking) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CreateBookingRequest) GetUserId() int32 {
+func (x *Booking) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+func (x *Booking) GetUserId() int32 {
 	if x != nil {
 		return x.UserId
 	}
 	return 0
 }
 
-func (x *CreateBookingRequest) GetRide() *Ride {
+func (x *Booking) GetRideId() int32 {
 	if x != nil {
-		return x.Ride
+		return x.RideId
+	}
+	return 0
+}
+
+func (x *Booking) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Booking) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *Booking) GetPickupTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PickupTime
 	}
 	return nil
 }
 
-type GetBookingRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	BookingId     int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+func (x *Booking) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Booking) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Booking) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *Booking) GetDiscount() *money.Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+type BookingDetails struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source      string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	Distance    int32                  `protobuf:"varint,4,opt,name=distance,proto3" json:"distance,omitempty"`
+	// Whole units of price's currency, for clients that predate price.
+	//
+	// Deprecated: Marked as deprecated in proto/booking/booking.proto.
+	Cost       int32                  `protobuf:"varint,5,opt,name=cost,proto3" json:"cost,omitempty"`
+	Status     string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	DriverId   int32                  `protobuf:"varint,8,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	PickupTime *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=pickup_time,json=pickupTime,proto3" json:"pickup_time,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt  *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Fare of the ride before any discount.
+	Price     *money.Money `protobuf:"bytes,12,opt,name=price,proto3" json:"price,omitempty"`
+	PromoCode string       `protobuf:"bytes,13,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	Discount  *money.Money `protobuf:"bytes,14,opt,name=discount,proto3" json:"discount,omitempty"`
+	// What the user pays: price less discount.
+	Total *money.Money `protobuf:"bytes,15,opt,name=total,proto3" json:"total,omitempty"`
+	// How price was computed; unset for rides booked without one.
+	FareBreakdown *FareBreakdown `protobuf:"bytes,16,opt,name=fare_breakdown,json=fareBreakdown,proto3" json:"fare_breakdown,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetBookingRequest) Reset() {
-	*x = GetBookingRequest{}
-	mi := &file_proto_booking_booking_proto_msgTypes[4]
+func (x *BookingDetails) Reset() {
+	*x = BookingDetails{}
+	mi := &file_proto_booking_booking_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetBookingRequest) String() string {
+func (x *BookingDetails) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetBookingRequest) ProtoMessage() {}
+func (*BookingDetails) ProtoMessage() {}
 
-func (x *GetBookingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_booking_booking_proto_msgTypes[4]
+func (x *BookingDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -325,51 +583,1655 @@ func (x *GetBookingRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetBookingRequest.ProtoReflect.Descriptor instead.
-func (*GetBookingRequest) Descriptor() ([]byte, []int) {
-	return file_proto_booking_booking_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use BookingDetails.ProtoReflect.Descriptor instead.
+func (*BookingDetails) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetBookingRequest) GetBookingId() int32 {
+func (x *BookingDetails) GetName() string {
 	if x != nil {
-		return x.BookingId
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BookingDetails) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *BookingDetails) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *BookingDetails) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/booking/booking.proto.
+func (x *BookingDetails) GetCost() int32 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *BookingDetails) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BookingDetails) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *BookingDetails) GetPickupTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PickupTime
+	}
+	return nil
+}
+
+func (x *BookingDetails) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *BookingDetails) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *BookingDetails) GetPrice() *money.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *BookingDetails) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *BookingDetails) GetDiscount() *money.Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *BookingDetails) GetTotal() *money.Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *BookingDetails) GetFareBreakdown() *FareBreakdown {
+	if x != nil {
+		return x.FareBreakdown
+	}
+	return nil
+}
+
+type CreateBookingRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Ride   *Ride                  `protobuf:"bytes,2,opt,name=ride,proto3" json:"ride,omitempty"`
+	Quote  *FareQuote             `protobuf:"bytes,3,opt,name=quote,proto3" json:"quote,omitempty"`
+	// Optional future pickup time. The booking is SCHEDULED until shortly
+	// before pickup; without it the booking is for immediate pickup.
+	PickupTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=pickup_time,json=pickupTime,proto3" json:"pickup_time,omitempty"`
+	// Optional promo code to take off the quoted fare.
+	PromoCode     string `protobuf:"bytes,5,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBookingRequest) Reset() {
+	*x = CreateBookingRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBookingRequest) ProtoMessage() {}
+
+func (x *CreateBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBookingRequest.ProtoReflect.Descriptor instead.
+func (*CreateBookingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateBookingRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *CreateBookingRequest) GetRide() *Ride {
+	if x != nil {
+		return x.Ride
+	}
+	return nil
+}
+
+func (x *CreateBookingRequest) GetQuote() *FareQuote {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+func (x *CreateBookingRequest) GetPickupTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PickupTime
+	}
+	return nil
+}
+
+func (x *CreateBookingRequest) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+type GetBookingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookingId     int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBookingRequest) Reset() {
+	*x = GetBookingRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBookingRequest) ProtoMessage() {}
+
+func (x *GetBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBookingRequest.ProtoReflect.Descriptor instead.
+func (*GetBookingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetBookingRequest) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+type CancelBookingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookingId     int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelBookingRequest) Reset() {
+	*x = CancelBookingRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelBookingRequest) ProtoMessage() {}
+
+func (x *CancelBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelBookingRequest.ProtoReflect.Descriptor instead.
+func (*CancelBookingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CancelBookingRequest) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+type CancelBookingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelBookingResponse) Reset() {
+	*x = CancelBookingResponse{}
+	mi := &file_proto_booking_booking_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelBookingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelBookingResponse) ProtoMessage() {}
+
+func (x *CancelBookingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelBookingResponse.ProtoReflect.Descriptor instead.
+func (*CancelBookingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelBookingResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type CompleteBookingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookingId     int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteBookingRequest) Reset() {
+	*x = CompleteBookingRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteBookingRequest) ProtoMessage() {}
+
+func (x *CompleteBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteBookingRequest.ProtoReflect.Descriptor instead.
+func (*CompleteBookingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CompleteBookingRequest) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+type CompleteBookingResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// Amount captured from the user's card.
+	Charged       *money.Money `protobuf:"bytes,2,opt,name=charged,proto3" json:"charged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteBookingResponse) Reset() {
+	*x = CompleteBookingResponse{}
+	mi := &file_proto_booking_booking_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteBookingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteBookingResponse) ProtoMessage() {}
+
+func (x *CompleteBookingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteBookingResponse.ProtoReflect.Descriptor instead.
+func (*CompleteBookingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CompleteBookingResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CompleteBookingResponse) GetCharged() *money.Money {
+	if x != nil {
+		return x.Charged
+	}
+	return nil
+}
+
+// DisputeBookingRequest refunds a completed booking in full.
+type DisputeBookingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookingId     int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisputeBookingRequest) Reset() {
+	*x = DisputeBookingRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisputeBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisputeBookingRequest) ProtoMessage() {}
+
+func (x *DisputeBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisputeBookingRequest.ProtoReflect.Descriptor instead.
+func (*DisputeBookingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DisputeBookingRequest) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+func (x *DisputeBookingRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DisputeBookingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Refunded      *money.Money           `protobuf:"bytes,2,opt,name=refunded,proto3" json:"refunded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisputeBookingResponse) Reset() {
+	*x = DisputeBookingResponse{}
+	mi := &file_proto_booking_booking_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisputeBookingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisputeBookingResponse) ProtoMessage() {}
+
+func (x *DisputeBookingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisputeBookingResponse.ProtoReflect.Descriptor instead.
+func (*DisputeBookingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DisputeBookingResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DisputeBookingResponse) GetRefunded() *money.Money {
+	if x != nil {
+		return x.Refunded
+	}
+	return nil
+}
+
+type WatchBookingRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookingId int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	// Last version the client has seen; the current state is only sent if it
+	// is newer. Use 0 to always receive the current state first.
+	FromVersion   int64 `protobuf:"varint,2,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchBookingRequest) Reset() {
+	*x = WatchBookingRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBookingRequest) ProtoMessage() {}
+
+func (x *WatchBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBookingRequest.ProtoReflect.Descriptor instead.
+func (*WatchBookingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WatchBookingRequest) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+func (x *WatchBookingRequest) GetFromVersion() int64 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+type BookingUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Booking       *BookingDetails        `protobuf:"bytes,2,opt,name=booking,proto3" json:"booking,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BookingUpdate) Reset() {
+	*x = BookingUpdate{}
+	mi := &file_proto_booking_booking_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BookingUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BookingUpdate) ProtoMessage() {}
+
+func (x *BookingUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BookingUpdate.ProtoReflect.Descriptor instead.
+func (*BookingUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BookingUpdate) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *BookingUpdate) GetBooking() *BookingDetails {
+	if x != nil {
+		return x.Booking
+	}
+	return nil
+}
+
+// Promotion is a promo code and the rules for redeeming it. Zero limits and
+// an empty city mean no restriction.
+type Promotion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Case-insensitive; stored upper case.
+	Code        string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// PERCENT or FIXED.
+	DiscountType string `protobuf:"bytes,3,opt,name=discount_type,json=discountType,proto3" json:"discount_type,omitempty"`
+	// Discount of a PERCENT promotion, in basis points of the fare.
+	PercentBps int64 `protobuf:"varint,4,opt,name=percent_bps,json=percentBps,proto3" json:"percent_bps,omitempty"`
+	// Discount of a FIXED promotion.
+	AmountOff *money.Money `protobuf:"bytes,5,opt,name=amount_off,json=amountOff,proto3" json:"amount_off,omitempty"`
+	// Optional cap on a PERCENT discount.
+	MaxDiscount *money.Money `protobuf:"bytes,6,opt,name=max_discount,json=maxDiscount,proto3" json:"max_discount,omitempty"`
+	// Optional bounds of when the code can be redeemed.
+	StartsAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	MaxRedemptions int32                  `protobuf:"varint,9,opt,name=max_redemptions,json=maxRedemptions,proto3" json:"max_redemptions,omitempty"`
+	MaxPerUser     int32                  `protobuf:"varint,10,opt,name=max_per_user,json=maxPerUser,proto3" json:"max_per_user,omitempty"`
+	FirstRideOnly  bool                   `protobuf:"varint,11,opt,name=first_ride_only,json=firstRideOnly,proto3" json:"first_ride_only,omitempty"`
+	// Gazetteer place pickups must be in, e.g. "pk-khi".
+	CityPlaceId string `protobuf:"bytes,12,opt,name=city_place_id,json=cityPlaceId,proto3" json:"city_place_id,omitempty"`
+	// Redemptions on bookings that were not cancelled. Output only.
+	Redemptions   int32                  `protobuf:"varint,13,opt,name=redemptions,proto3" json:"redemptions,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Promotion) Reset() {
+	*x = Promotion{}
+	mi := &file_proto_booking_booking_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Promotion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Promotion) ProtoMessage() {}
+
+func (x *Promotion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Promotion.ProtoReflect.Descriptor instead.
+func (*Promotion) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Promotion) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Promotion) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Promotion) GetDiscountType() string {
+	if x != nil {
+		return x.DiscountType
+	}
+	return ""
+}
+
+func (x *Promotion) GetPercentBps() int64 {
+	if x != nil {
+		return x.PercentBps
+	}
+	return 0
+}
+
+func (x *Promotion) GetAmountOff() *money.Money {
+	if x != nil {
+		return x.AmountOff
+	}
+	return nil
+}
+
+func (x *Promotion) GetMaxDiscount() *money.Money {
+	if x != nil {
+		return x.MaxDiscount
+	}
+	return nil
+}
+
+func (x *Promotion) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *Promotion) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *Promotion) GetMaxRedemptions() int32 {
+	if x != nil {
+		return x.MaxRedemptions
+	}
+	return 0
+}
+
+func (x *Promotion) GetMaxPerUser() int32 {
+	if x != nil {
+		return x.MaxPerUser
+	}
+	return 0
+}
+
+func (x *Promotion) GetFirstRideOnly() bool {
+	if x != nil {
+		return x.FirstRideOnly
+	}
+	return false
+}
+
+func (x *Promotion) GetCityPlaceId() string {
+	if x != nil {
+		return x.CityPlaceId
+	}
+	return ""
+}
+
+func (x *Promotion) GetRedemptions() int32 {
+	if x != nil {
+		return x.Redemptions
+	}
+	return 0
+}
+
+func (x *Promotion) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreatePromotionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Promotion     *Promotion             `protobuf:"bytes,1,opt,name=promotion,proto3" json:"promotion,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePromotionRequest) Reset() {
+	*x = CreatePromotionRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePromotionRequest) ProtoMessage() {}
+
+func (x *CreatePromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePromotionRequest.ProtoReflect.Descriptor instead.
+func (*CreatePromotionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CreatePromotionRequest) GetPromotion() *Promotion {
+	if x != nil {
+		return x.Promotion
+	}
+	return nil
+}
+
+type GetPromotionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPromotionRequest) Reset() {
+	*x = GetPromotionRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPromotionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPromotionRequest) ProtoMessage() {}
+
+func (x *GetPromotionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPromotionRequest.ProtoReflect.Descriptor instead.
+func (*GetPromotionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetPromotionRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+// RateBookingRequest rates a completed booking. Only the booking's rider can
+// rate it, once.
+type RateBookingRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookingId int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	UserId    int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// 1 to 5.
+	Stars int32 `protobuf:"varint,3,opt,name=stars,proto3" json:"stars,omitempty"`
+	// Optional, up to 1000 characters.
+	Comment string `protobuf:"bytes,4,opt,name=comment,proto3" json:"comment,omitempty"`
+	// Optional, from CLEAN_CAR, SAFE_DRIVING, FRIENDLY, ON_TIME, GOOD_ROUTE,
+	// DIRTY_CAR, UNSAFE_DRIVING, RUDE, LATE and WRONG_ROUTE.
+	Tags          []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateBookingRequest) Reset() {
+	*x = RateBookingRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateBookingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateBookingRequest) ProtoMessage() {}
+
+func (x *RateBookingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateBookingRequest.ProtoReflect.Descriptor instead.
+func (*RateBookingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RateBookingRequest) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+func (x *RateBookingRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RateBookingRequest) GetStars() int32 {
+	if x != nil {
+		return x.Stars
+	}
+	return 0
+}
+
+func (x *RateBookingRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *RateBookingRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type Rating struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookingId int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	UserId    int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The booking's driver, or 0 if it had none.
+	DriverId      int32                  `protobuf:"varint,3,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Stars         int32                  `protobuf:"varint,4,opt,name=stars,proto3" json:"stars,omitempty"`
+	Comment       string                 `protobuf:"bytes,5,opt,name=comment,proto3" json:"comment,omitempty"`
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rating) Reset() {
+	*x = Rating{}
+	mi := &file_proto_booking_booking_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rating) ProtoMessage() {}
+
+func (x *Rating) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rating.ProtoReflect.Descriptor instead.
+func (*Rating) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Rating) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+func (x *Rating) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *Rating) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *Rating) GetStars() int32 {
+	if x != nil {
+		return x.Stars
+	}
+	return 0
+}
+
+func (x *Rating) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Rating) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Rating) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// GetRatingStatsRequest selects a driver by driver_id, or a route by the
+// gazetteer place IDs of its source and destination.
+type GetRatingStatsRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	DriverId           int32                  `protobuf:"varint,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	SourcePlaceId      string                 `protobuf:"bytes,2,opt,name=source_place_id,json=sourcePlaceId,proto3" json:"source_place_id,omitempty"`
+	DestinationPlaceId string                 `protobuf:"bytes,3,opt,name=destination_place_id,json=destinationPlaceId,proto3" json:"destination_place_id,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetRatingStatsRequest) Reset() {
+	*x = GetRatingStatsRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatingStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatingStatsRequest) ProtoMessage() {}
+
+func (x *GetRatingStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatingStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetRatingStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetRatingStatsRequest) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *GetRatingStatsRequest) GetSourcePlaceId() string {
+	if x != nil {
+		return x.SourcePlaceId
+	}
+	return ""
+}
+
+func (x *GetRatingStatsRequest) GetDestinationPlaceId() string {
+	if x != nil {
+		return x.DestinationPlaceId
+	}
+	return ""
+}
+
+type RatingStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Count int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// Mean stars, or 0 without ratings.
+	Average float64 `protobuf:"fixed64,2,opt,name=average,proto3" json:"average,omitempty"`
+	// Ratings with 1, 2, 3, 4 and 5 stars, in that order.
+	Stars []int64 `protobuf:"varint,3,rep,packed,name=stars,proto3" json:"stars,omitempty"`
+	// Ratings carrying each tag.
+	Tags          map[string]int64 `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatingStats) Reset() {
+	*x = RatingStats{}
+	mi := &file_proto_booking_booking_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatingStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatingStats) ProtoMessage() {}
+
+func (x *RatingStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatingStats.ProtoReflect.Descriptor instead.
+func (*RatingStats) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RatingStats) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *RatingStats) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *RatingStats) GetStars() []int64 {
+	if x != nil {
+		return x.Stars
+	}
+	return nil
+}
+
+func (x *RatingStats) GetTags() map[string]int64 {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type GetReceiptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookingId     int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReceiptRequest) Reset() {
+	*x = GetReceiptRequest{}
+	mi := &file_proto_booking_booking_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReceiptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReceiptRequest) ProtoMessage() {}
+
+func (x *GetReceiptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReceiptRequest.ProtoReflect.Descriptor instead.
+func (*GetReceiptRequest) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetReceiptRequest) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+type ReceiptItem struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Description string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	// Negative for discounts.
+	Amount        *money.Money `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiptItem) Reset() {
+	*x = ReceiptItem{}
+	mi := &file_proto_booking_booking_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiptItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiptItem) ProtoMessage() {}
+
+func (x *ReceiptItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiptItem.ProtoReflect.Descriptor instead.
+func (*ReceiptItem) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ReceiptItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ReceiptItem) GetAmount() *money.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+// Receipt is the itemized receipt of a completed booking, issued once and
+// returned unchanged afterwards.
+type Receipt struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Numbered from 1 in each month, e.g. "INV-202610-000042".
+	InvoiceNumber string                 `protobuf:"bytes,1,opt,name=invoice_number,json=invoiceNumber,proto3" json:"invoice_number,omitempty"`
+	BookingId     int32                  `protobuf:"varint,2,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	IssuedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	UserName      string                 `protobuf:"bytes,4,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	Destination   string                 `protobuf:"bytes,6,opt,name=destination,proto3" json:"destination,omitempty"`
+	Distance      int32                  `protobuf:"varint,7,opt,name=distance,proto3" json:"distance,omitempty"`
+	VehicleClass  string                 `protobuf:"bytes,8,opt,name=vehicle_class,json=vehicleClass,proto3" json:"vehicle_class,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Items         []*ReceiptItem         `protobuf:"bytes,10,rep,name=items,proto3" json:"items,omitempty"`
+	Total         *money.Money           `protobuf:"bytes,11,opt,name=total,proto3" json:"total,omitempty"`
+	// Tax included in total, at tax_rate_bps basis points. It is the last of
+	// items, which are otherwise net of tax.
+	TaxRateBps int64        `protobuf:"varint,12,opt,name=tax_rate_bps,json=taxRateBps,proto3" json:"tax_rate_bps,omitempty"`
+	Tax        *money.Money `protobuf:"bytes,13,opt,name=tax,proto3" json:"tax,omitempty"`
+	// The receipt rendered as a standalone HTML page and as a PDF document.
+	Html          string `protobuf:"bytes,14,opt,name=html,proto3" json:"html,omitempty"`
+	Pdf           []byte `protobuf:"bytes,15,opt,name=pdf,proto3" json:"pdf,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_proto_booking_booking_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_booking_booking_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_proto_booking_booking_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Receipt) GetInvoiceNumber() string {
+	if x != nil {
+		return x.InvoiceNumber
+	}
+	return ""
+}
+
+func (x *Receipt) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+func (x *Receipt) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *Receipt) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *Receipt) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Receipt) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *Receipt) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
 	}
 	return 0
 }
 
+func (x *Receipt) GetVehicleClass() string {
+	if x != nil {
+		return x.VehicleClass
+	}
+	return ""
+}
+
+func (x *Receipt) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Receipt) GetItems() []*ReceiptItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Receipt) GetTotal() *money.Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *Receipt) GetTaxRateBps() int64 {
+	if x != nil {
+		return x.TaxRateBps
+	}
+	return 0
+}
+
+func (x *Receipt) GetTax() *money.Money {
+	if x != nil {
+		return x.Tax
+	}
+	return nil
+}
+
+func (x *Receipt) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+func (x *Receipt) GetPdf() []byte {
+	if x != nil {
+		return x.Pdf
+	}
+	return nil
+}
+
 var File_proto_booking_booking_proto protoreflect.FileDescriptor
 
 const file_proto_booking_booking_proto_rawDesc = "" +
 	"\n" +
-	"\x1bproto/booking/booking.proto\x12\abooking\"p\n" +
+	"\x1bproto/booking/booking.proto\x12\abooking\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17proto/audit/audit.proto\x1a\x1bproto/logging/logging.proto\x1a\x17proto/money/money.proto\",\n" +
+	"\x06LatLng\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lng\x18\x02 \x01(\x01R\x03lng\"\xf2\x01\n" +
 	"\x04Ride\x12\x16\n" +
 	"\x06source\x18\x01 \x01(\tR\x06source\x12 \n" +
 	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x1a\n" +
-	"\bdistance\x18\x03 \x01(\x05R\bdistance\x12\x12\n" +
-	"\x04cost\x18\x04 \x01(\x05R\x04cost\"n\n" +
+	"\bdistance\x18\x03 \x01(\x05R\bdistance\x12\x16\n" +
+	"\x04cost\x18\x04 \x01(\x05B\x02\x18\x01R\x04cost\x128\n" +
+	"\x0fsource_location\x18\x05 \x01(\v2\x0f.booking.LatLngR\x0esourceLocation\x12B\n" +
+	"\x14destination_location\x18\x06 \x01(\v2\x0f.booking.LatLngR\x13destinationLocation\"\x99\x03\n" +
+	"\rFareBreakdown\x12)\n" +
+	"\tbase_fare\x18\x01 \x01(\v2\f.money.MoneyR\bbaseFare\x121\n" +
+	"\rdistance_fare\x18\x02 \x01(\v2\f.money.MoneyR\fdistanceFare\x12D\n" +
+	"\x17minimum_fare_adjustment\x18\x03 \x01(\v2\f.money.MoneyR\x15minimumFareAdjustment\x128\n" +
+	"\x19time_of_day_multiplier_bp\x18\x04 \x01(\x05R\x15timeOfDayMultiplierBp\x12A\n" +
+	"\x16time_of_day_adjustment\x18\x05 \x01(\v2\f.money.MoneyR\x13timeOfDayAdjustment\x12.\n" +
+	"\x13surge_multiplier_bp\x18\x06 \x01(\x05R\x11surgeMultiplierBp\x127\n" +
+	"\x10surge_adjustment\x18\a \x01(\v2\f.money.MoneyR\x0fsurgeAdjustment\"\x9f\x04\n" +
+	"\tFareQuote\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x1a\n" +
+	"\bdistance\x18\x03 \x01(\x05R\bdistance\x12#\n" +
+	"\rvehicle_class\x18\x04 \x01(\tR\fvehicleClass\x12\x16\n" +
+	"\x04fare\x18\x05 \x01(\x05B\x02\x18\x01R\x04fare\x12%\n" +
+	"\x0etariff_version\x18\x06 \x01(\x05R\rtariffVersion\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1c\n" +
+	"\tsignature\x18\b \x01(\tR\tsignature\x12.\n" +
+	"\x13surge_multiplier_bp\x18\t \x01(\x05R\x11surgeMultiplierBp\x12D\n" +
+	"\x10surge_expires_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x0esurgeExpiresAt\x12\"\n" +
+	"\x05price\x18\v \x01(\v2\f.money.MoneyR\x05price\x124\n" +
+	"\tbreakdown\x18\f \x01(\v2\x16.booking.FareBreakdownR\tbreakdown\x12/\n" +
+	"\x13breakdown_signature\x18\r \x01(\tR\x12breakdownSignature\"\x97\x03\n" +
 	"\aBooking\x12\x1d\n" +
 	"\n" +
 	"booking_id\x18\x01 \x01(\x05R\tbookingId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x17\n" +
-	"\aride_id\x18\x03 \x01(\x05R\x06rideId\x12\x12\n" +
-	"\x04time\x18\x04 \x01(\tR\x04time\"\xa2\x01\n" +
-	"\x0eBookingDetails\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\aride_id\x18\x03 \x01(\x05R\x06rideId\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1b\n" +
+	"\tdriver_id\x18\x06 \x01(\x05R\bdriverId\x12;\n" +
+	"\vpickup_time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"pickupTime\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\n" +
+	" \x01(\tR\tpromoCode\x12(\n" +
+	"\bdiscount\x18\v \x01(\v2\f.money.MoneyR\bdiscountJ\x04\b\x04\x10\x05R\x04time\"\xdc\x04\n" +
+	"\x0eBookingDetails\x12\x18\n" +
+	"\x04name\x18\x01 \x01(\tB\x04\x88\xb5\x18\x01R\x04name\x12\x16\n" +
 	"\x06source\x18\x02 \x01(\tR\x06source\x12 \n" +
 	"\vdestination\x18\x03 \x01(\tR\vdestination\x12\x1a\n" +
-	"\bdistance\x18\x04 \x01(\x05R\bdistance\x12\x12\n" +
-	"\x04cost\x18\x05 \x01(\x05R\x04cost\x12\x12\n" +
-	"\x04time\x18\x06 \x01(\tR\x04time\"R\n" +
+	"\bdistance\x18\x04 \x01(\x05R\bdistance\x12\x16\n" +
+	"\x04cost\x18\x05 \x01(\x05B\x02\x18\x01R\x04cost\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1b\n" +
+	"\tdriver_id\x18\b \x01(\x05R\bdriverId\x12;\n" +
+	"\vpickup_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"pickupTime\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\"\n" +
+	"\x05price\x18\f \x01(\v2\f.money.MoneyR\x05price\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\r \x01(\tR\tpromoCode\x12(\n" +
+	"\bdiscount\x18\x0e \x01(\v2\f.money.MoneyR\bdiscount\x12\"\n" +
+	"\x05total\x18\x0f \x01(\v2\f.money.MoneyR\x05total\x12=\n" +
+	"\x0efare_breakdown\x18\x10 \x01(\v2\x16.booking.FareBreakdownR\rfareBreakdownJ\x04\b\x06\x10\aR\x04time\"\xd8\x01\n" +
 	"\x14CreateBookingRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x05R\x06userId\x12!\n" +
-	"\x04ride\x18\x02 \x01(\v2\r.booking.RideR\x04ride\"2\n" +
+	"\x04ride\x18\x02 \x01(\v2\r.booking.RideR\x04ride\x12(\n" +
+	"\x05quote\x18\x03 \x01(\v2\x12.booking.FareQuoteR\x05quote\x12;\n" +
+	"\vpickup_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"pickupTime\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\x05 \x01(\tR\tpromoCode\"2\n" +
 	"\x11GetBookingRequest\x12\x1d\n" +
 	"\n" +
-	"booking_id\x18\x01 \x01(\x05R\tbookingId2\x95\x01\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\"5\n" +
+	"\x14CancelBookingRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\"1\n" +
+	"\x15CancelBookingResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"7\n" +
+	"\x16CompleteBookingRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\"[\n" +
+	"\x17CompleteBookingResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12&\n" +
+	"\acharged\x18\x02 \x01(\v2\f.money.MoneyR\acharged\"N\n" +
+	"\x15DisputeBookingRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\\\n" +
+	"\x16DisputeBookingResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12(\n" +
+	"\brefunded\x18\x02 \x01(\v2\f.money.MoneyR\brefunded\"W\n" +
+	"\x13WatchBookingRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\x12!\n" +
+	"\ffrom_version\x18\x02 \x01(\x03R\vfromVersion\"\\\n" +
+	"\rBookingUpdate\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x121\n" +
+	"\abooking\x18\x02 \x01(\v2\x17.booking.BookingDetailsR\abooking\"\xc7\x04\n" +
+	"\tPromotion\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12#\n" +
+	"\rdiscount_type\x18\x03 \x01(\tR\fdiscountType\x12\x1f\n" +
+	"\vpercent_bps\x18\x04 \x01(\x03R\n" +
+	"percentBps\x12+\n" +
+	"\n" +
+	"amount_off\x18\x05 \x01(\v2\f.money.MoneyR\tamountOff\x12/\n" +
+	"\fmax_discount\x18\x06 \x01(\v2\f.money.MoneyR\vmaxDiscount\x127\n" +
+	"\tstarts_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12'\n" +
+	"\x0fmax_redemptions\x18\t \x01(\x05R\x0emaxRedemptions\x12 \n" +
+	"\fmax_per_user\x18\n" +
+	" \x01(\x05R\n" +
+	"maxPerUser\x12&\n" +
+	"\x0ffirst_ride_only\x18\v \x01(\bR\rfirstRideOnly\x12\"\n" +
+	"\rcity_place_id\x18\f \x01(\tR\vcityPlaceId\x12 \n" +
+	"\vredemptions\x18\r \x01(\x05R\vredemptions\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"J\n" +
+	"\x16CreatePromotionRequest\x120\n" +
+	"\tpromotion\x18\x01 \x01(\v2\x12.booking.PromotionR\tpromotion\")\n" +
+	"\x13GetPromotionRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\x90\x01\n" +
+	"\x12RateBookingRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x14\n" +
+	"\x05stars\x18\x03 \x01(\x05R\x05stars\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\"\xdc\x01\n" +
+	"\x06Rating\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\x12\x1b\n" +
+	"\tdriver_id\x18\x03 \x01(\x05R\bdriverId\x12\x14\n" +
+	"\x05stars\x18\x04 \x01(\x05R\x05stars\x12\x18\n" +
+	"\acomment\x18\x05 \x01(\tR\acomment\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8e\x01\n" +
+	"\x15GetRatingStatsRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\x05R\bdriverId\x12&\n" +
+	"\x0fsource_place_id\x18\x02 \x01(\tR\rsourcePlaceId\x120\n" +
+	"\x14destination_place_id\x18\x03 \x01(\tR\x12destinationPlaceId\"\xc0\x01\n" +
+	"\vRatingStats\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x18\n" +
+	"\aaverage\x18\x02 \x01(\x01R\aaverage\x12\x14\n" +
+	"\x05stars\x18\x03 \x03(\x03R\x05stars\x122\n" +
+	"\x04tags\x18\x04 \x03(\v2\x1e.booking.RatingStats.TagsEntryR\x04tags\x1a7\n" +
+	"\tTagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"2\n" +
+	"\x11GetReceiptRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\"U\n" +
+	"\vReceiptItem\x12 \n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\x12$\n" +
+	"\x06amount\x18\x02 \x01(\v2\f.money.MoneyR\x06amount\"\xa9\x04\n" +
+	"\aReceipt\x12%\n" +
+	"\x0einvoice_number\x18\x01 \x01(\tR\rinvoiceNumber\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x02 \x01(\x05R\tbookingId\x127\n" +
+	"\tissued_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x12!\n" +
+	"\tuser_name\x18\x04 \x01(\tB\x04\x88\xb5\x18\x01R\buserName\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x06 \x01(\tR\vdestination\x12\x1a\n" +
+	"\bdistance\x18\a \x01(\x05R\bdistance\x12#\n" +
+	"\rvehicle_class\x18\b \x01(\tR\fvehicleClass\x12=\n" +
+	"\fcompleted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12*\n" +
+	"\x05items\x18\n" +
+	" \x03(\v2\x14.booking.ReceiptItemR\x05items\x12\"\n" +
+	"\x05total\x18\v \x01(\v2\f.money.MoneyR\x05total\x12 \n" +
+	"\ftax_rate_bps\x18\f \x01(\x03R\n" +
+	"taxRateBps\x12\x1e\n" +
+	"\x03tax\x18\r \x01(\v2\f.money.MoneyR\x03tax\x12\x18\n" +
+	"\x04html\x18\x0e \x01(\tB\x04\x88\xb5\x18\x01R\x04html\x12\x16\n" +
+	"\x03pdf\x18\x0f \x01(\fB\x04\x88\xb5\x18\x01R\x03pdf2\xed\x06\n" +
 	"\x0eBookingService\x12@\n" +
 	"\rCreateBooking\x12\x1d.booking.CreateBookingRequest\x1a\x10.booking.Booking\x12A\n" +
 	"\n" +
-	"GetBooking\x12\x1a.booking.GetBookingRequest\x1a\x17.booking.BookingDetailsB\x14Z\x12booking-service/pbb\x06proto3"
+	"GetBooking\x12\x1a.booking.GetBookingRequest\x1a\x17.booking.BookingDetails\x12N\n" +
+	"\rCancelBooking\x12\x1d.booking.CancelBookingRequest\x1a\x1e.booking.CancelBookingResponse\x12F\n" +
+	"\fWatchBooking\x12\x1c.booking.WatchBookingRequest\x1a\x16.booking.BookingUpdate0\x01\x12T\n" +
+	"\x0fCompleteBooking\x12\x1f.booking.CompleteBookingRequest\x1a .booking.CompleteBookingResponse\x12Q\n" +
+	"\x0eDisputeBooking\x12\x1e.booking.DisputeBookingRequest\x1a\x1f.booking.DisputeBookingResponse\x12F\n" +
+	"\x0fCreatePromotion\x12\x1f.booking.CreatePromotionRequest\x1a\x12.booking.Promotion\x12@\n" +
+	"\fGetPromotion\x12\x1c.booking.GetPromotionRequest\x1a\x12.booking.Promotion\x12;\n" +
+	"\vRateBooking\x12\x1b.booking.RateBookingRequest\x1a\x0f.booking.Rating\x12F\n" +
+	"\x0eGetRatingStats\x12\x1e.booking.GetRatingStatsRequest\x1a\x14.booking.RatingStats\x12:\n" +
+	"\n" +
+	"GetReceipt\x12\x1a.booking.GetReceiptRequest\x1a\x10.booking.Receipt\x12J\n" +
+	"\rQueryAuditLog\x12\x1b.audit.QueryAuditLogRequest\x1a\x1c.audit.QueryAuditLogResponseB\x14Z\x12booking-service/pbb\x06proto3"
 
 var (
 	file_proto_booking_booking_proto_rawDescOnce sync.Once
@@ -383,25 +2245,112 @@ func file_proto_booking_booking_proto_rawDescGZIP() []byte {
 	return file_proto_booking_booking_proto_rawDescData
 }
 
-var file_proto_booking_booking_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_booking_booking_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_proto_booking_booking_proto_goTypes = []any{
-	(*Ride)(nil),                 // 0: booking.Ride
-	(*Booking)(nil),              // 1: booking.Booking
-	(*BookingDetails)(nil),       // 2: booking.BookingDetails
-	(*CreateBookingRequest)(nil), // 3: booking.CreateBookingRequest
-	(*GetBookingRequest)(nil),    // 4: booking.GetBookingRequest
+	(*LatLng)(nil),                      // 0: booking.LatLng
+	(*Ride)(nil),                        // 1: booking.Ride
+	(*FareBreakdown)(nil),               // 2: booking.FareBreakdown
+	(*FareQuote)(nil),                   // 3: booking.FareQuote
+	(*Booking)(nil),                     // 4: booking.Booking
+	(*BookingDetails)(nil),              // 5: booking.BookingDetails
+	(*CreateBookingRequest)(nil),        // 6: booking.CreateBookingRequest
+	(*GetBookingRequest)(nil),           // 7: booking.GetBookingRequest
+	(*CancelBookingRequest)(nil),        // 8: booking.CancelBookingRequest
+	(*CancelBookingResponse)(nil),       // 9: booking.CancelBookingResponse
+	(*CompleteBookingRequest)(nil),      // 10: booking.CompleteBookingRequest
+	(*CompleteBookingResponse)(nil),     // 11: booking.CompleteBookingResponse
+	(*DisputeBookingRequest)(nil),       // 12: booking.DisputeBookingRequest
+	(*DisputeBookingResponse)(nil),      // 13: booking.DisputeBookingResponse
+	(*WatchBookingRequest)(nil),         // 14: booking.WatchBookingRequest
+	(*BookingUpdate)(nil),               // 15: booking.BookingUpdate
+	(*Promotion)(nil),                   // 16: booking.Promotion
+	(*CreatePromotionRequest)(nil),      // 17: booking.CreatePromotionRequest
+	(*GetPromotionRequest)(nil),         // 18: booking.GetPromotionRequest
+	(*RateBookingRequest)(nil),          // 19: booking.RateBookingRequest
+	(*Rating)(nil),                      // 20: booking.Rating
+	(*GetRatingStatsRequest)(nil),       // 21: booking.GetRatingStatsRequest
+	(*RatingStats)(nil),                 // 22: booking.RatingStats
+	(*GetReceiptRequest)(nil),           // 23: booking.GetReceiptRequest
+	(*ReceiptItem)(nil),                 // 24: booking.ReceiptItem
+	(*Receipt)(nil),                     // 25: booking.Receipt
+	nil,                                 // 26: booking.RatingStats.TagsEntry
+	(*money.Money)(nil),                 // 27: money.Money
+	(*timestamppb.Timestamp)(nil),       // 28: google.protobuf.Timestamp
+	(*audit.QueryAuditLogRequest)(nil),  // 29: audit.QueryAuditLogRequest
+	(*audit.QueryAuditLogResponse)(nil), // 30: audit.QueryAuditLogResponse
 }
 var file_proto_booking_booking_proto_depIdxs = []int32{
-	0, // 0: booking.CreateBookingRequest.ride:type_name -> booking.Ride
-	3, // 1: booking.BookingService.CreateBooking:input_type -> booking.CreateBookingRequest
-	4, // 2: booking.BookingService.GetBooking:input_type -> booking.GetBookingRequest
-	1, // 3: booking.BookingService.CreateBooking:output_type -> booking.Booking
-	2, // 4: booking.BookingService.GetBooking:output_type -> booking.BookingDetails
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: booking.Ride.source_location:type_name -> booking.LatLng
+	0,  // 1: booking.Ride.destination_location:type_name -> booking.LatLng
+	27, // 2: booking.FareBreakdown.base_fare:type_name -> money.Money
+	27, // 3: booking.FareBreakdown.distance_fare:type_name -> money.Money
+	27, // 4: booking.FareBreakdown.minimum_fare_adjustment:type_name -> money.Money
+	27, // 5: booking.FareBreakdown.time_of_day_adjustment:type_name -> money.Money
+	27, // 6: booking.FareBreakdown.surge_adjustment:type_name -> money.Money
+	28, // 7: booking.FareQuote.expires_at:type_name -> google.protobuf.Timestamp
+	28, // 8: booking.FareQuote.surge_expires_at:type_name -> google.protobuf.Timestamp
+	27, // 9: booking.FareQuote.price:type_name -> money.Money
+	2,  // 10: booking.FareQuote.breakdown:type_name -> booking.FareBreakdown
+	28, // 11: booking.Booking.pickup_time:type_name -> google.protobuf.Timestamp
+	28, // 12: booking.Booking.created_at:type_name -> google.protobuf.Timestamp
+	28, // 13: booking.Booking.updated_at:type_name -> google.protobuf.Timestamp
+	27, // 14: booking.Booking.discount:type_name -> money.Money
+	28, // 15: booking.BookingDetails.pickup_time:type_name -> google.protobuf.Timestamp
+	28, // 16: booking.BookingDetails.created_at:type_name -> google.protobuf.Timestamp
+	28, // 17: booking.BookingDetails.updated_at:type_name -> google.protobuf.Timestamp
+	27, // 18: booking.BookingDetails.price:type_name -> money.Money
+	27, // 19: booking.BookingDetails.discount:type_name -> money.Money
+	27, // 20: booking.BookingDetails.total:type_name -> money.Money
+	2,  // 21: booking.BookingDetails.fare_breakdown:type_name -> booking.FareBreakdown
+	1,  // 22: booking.CreateBookingRequest.ride:type_name -> booking.Ride
+	3,  // 23: booking.CreateBookingRequest.quote:type_name -> booking.FareQuote
+	28, // 24: booking.CreateBookingRequest.pickup_time:type_name -> google.protobuf.Timestamp
+	27, // 25: booking.CompleteBookingResponse.charged:type_name -> money.Money
+	27, // 26: booking.DisputeBookingResponse.refunded:type_name -> money.Money
+	5,  // 27: booking.BookingUpdate.booking:type_name -> booking.BookingDetails
+	27, // 28: booking.Promotion.amount_off:type_name -> money.Money
+	27, // 29: booking.Promotion.max_discount:type_name -> money.Money
+	28, // 30: booking.Promotion.starts_at:type_name -> google.protobuf.Timestamp
+	28, // 31: booking.Promotion.ends_at:type_name -> google.protobuf.Timestamp
+	28, // 32: booking.Promotion.created_at:type_name -> google.protobuf.Timestamp
+	16, // 33: booking.CreatePromotionRequest.promotion:type_name -> booking.Promotion
+	28, // 34: booking.Rating.created_at:type_name -> google.protobuf.Timestamp
+	26, // 35: booking.RatingStats.tags:type_name -> booking.RatingStats.TagsEntry
+	27, // 36: booking.ReceiptItem.amount:type_name -> money.Money
+	28, // 37: booking.Receipt.issued_at:type_name -> google.protobuf.Timestamp
+	28, // 38: booking.Receipt.completed_at:type_name -> google.protobuf.Timestamp
+	24, // 39: booking.Receipt.items:type_name -> booking.ReceiptItem
+	27, // 40: booking.Receipt.total:type_name -> money.Money
+	27, // 41: booking.Receipt.tax:type_name -> money.Money
+	6,  // 42: booking.BookingService.CreateBooking:input_type -> booking.CreateBookingRequest
+	7,  // 43: booking.BookingService.GetBooking:input_type -> booking.GetBookingRequest
+	8,  // 44: booking.BookingService.CancelBooking:input_type -> booking.CancelBookingRequest
+	14, // 45: booking.BookingService.WatchBooking:input_type -> booking.WatchBookingRequest
+	10, // 46: booking.BookingService.CompleteBooking:input_type -> booking.CompleteBookingRequest
+	12, // 47: booking.BookingService.DisputeBooking:input_type -> booking.DisputeBookingRequest
+	17, // 48: booking.BookingService.CreatePromotion:input_type -> booking.CreatePromotionRequest
+	18, // 49: booking.BookingService.GetPromotion:input_type -> booking.GetPromotionRequest
+	19, // 50: booking.BookingService.RateBooking:input_type -> booking.RateBookingRequest
+	21, // 51: booking.BookingService.GetRatingStats:input_type -> booking.GetRatingStatsRequest
+	23, // 52: booking.BookingService.GetReceipt:input_type -> booking.GetReceiptRequest
+	29, // 53: booking.BookingService.QueryAuditLog:input_type -> audit.QueryAuditLogRequest
+	4,  // 54: booking.BookingService.CreateBooking:output_type -> booking.Booking
+	5,  // 55: booking.BookingService.GetBooking:output_type -> booking.BookingDetails
+	9,  // 56: booking.BookingService.CancelBooking:output_type -> booking.CancelBookingResponse
+	15, // 57: booking.BookingService.WatchBooking:output_type -> booking.BookingUpdate
+	11, // 58: booking.BookingService.CompleteBooking:output_type -> booking.CompleteBookingResponse
+	13, // 59: booking.BookingService.DisputeBooking:output_type -> booking.DisputeBookingResponse
+	16, // 60: booking.BookingService.CreatePromotion:output_type -> booking.Promotion
+	16, // 61: booking.BookingService.GetPromotion:output_type -> booking.Promotion
+	20, // 62: booking.BookingService.RateBooking:output_type -> booking.Rating
+	22, // 63: booking.BookingService.GetRatingStats:output_type -> booking.RatingStats
+	25, // 64: booking.BookingService.GetReceipt:output_type -> booking.Receipt
+	30, // 65: booking.BookingService.QueryAuditLog:output_type -> audit.QueryAuditLogResponse
+	54, // [54:66] is the sub-list for method output_type
+	42, // [42:54] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
 }
 
 func init() { file_proto_booking_booking_proto_init() }
@@ -415,7 +2364,7 @@ func file_proto_booking_booking_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_booking_booking_proto_rawDesc), len(file_proto_booking_booking_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,188 @@
+// Package promotions holds the rules of promo codes: what a code takes off a
+// fare, and who may redeem it, where and when.
+package promotions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+// Discount types.
+const (
+	TypePercent = "PERCENT"
+	TypeFixed   = "FIXED"
+)
+
+// Reasons a promotion cannot be redeemed.
+var (
+	ErrNotActive     = errors.New("promo code is not active")
+	ErrExhausted     = errors.New("promo code has been fully redeemed")
+	ErrUserLimit     = errors.New("promo code already redeemed by this user")
+	ErrFirstRideOnly = errors.New("promo code is only valid on a first ride")
+	ErrWrongCity     = errors.New("promo code is not valid in this city")
+	ErrWrongCurrency = errors.New("promo code is not valid in this currency")
+)
+
+// IsIneligible reports whether err is one of the reasons a promotion cannot
+// be redeemed.
+func IsIneligible(err error) bool {
+	for _, reason := range []error{ErrNotActive, ErrExhausted, ErrUserLimit, ErrFirstRideOnly, ErrWrongCity, ErrWrongCurrency} {
+		if errors.Is(err, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// Promotion is a promo code and its discount rules. Zero limits and an empty
+// city mean no restriction.
+type Promotion struct {
+	Code        string
+	Description string
+	Type        string
+	// PercentBps is the discount of a PERCENT promotion, in basis points of
+	// the fare.
+	PercentBps int64
+	// AmountOff is the discount of a FIXED promotion.
+	AmountOff money.Money
+	// MaxDiscount caps a PERCENT discount; zero leaves it uncapped.
+	MaxDiscount money.Money
+	// StartsAt and EndsAt bound when the code can be redeemed. Either may be
+	// zero for an open end.
+	StartsAt time.Time
+	EndsAt   time.Time
+	// MaxRedemptions caps redemptions across all users, and MaxPerUser those
+	// of each user.
+	MaxRedemptions int32
+	MaxPerUser     int32
+	FirstRideOnly  bool
+	// CityPlaceID restricts the code to pickups in a gazetteer place, e.g.
+	// "pk-khi".
+	CityPlaceID string
+	// Redemptions counts redemptions of bookings that were not cancelled.
+	Redemptions int32
+	CreatedAt   time.Time
+}
+
+// Usage is what a redemption is checked against.
+type Usage struct {
+	Now           time.Time
+	Fare          money.Money
+	PickupPlaceID string
+	// UserRedemptions counts the user's redemptions of the code on bookings
+	// that were not cancelled.
+	UserRedemptions int32
+	// PriorBookings counts the user's bookings that were not cancelled.
+	PriorBookings int32
+}
+
+// Normalize returns code as it is stored: trimmed and upper case.
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate returns an error if p is not a well-formed promotion.
+func (p *Promotion) Validate() error {
+	if p.Code == "" || p.Code != Normalize(p.Code) {
+		return fmt.Errorf("code must be non-empty, trimmed and upper case")
+	}
+	switch p.Type {
+	case TypePercent:
+		if p.PercentBps <= 0 || p.PercentBps > 10000 {
+			return fmt.Errorf("percent discount must be between 1 and 10000 basis points")
+		}
+		if p.MaxDiscount.IsNegative() {
+			return fmt.Errorf("max discount cannot be negative")
+		}
+		if p.MaxDiscount.IsPositive() {
+			if _, err := money.Exponent(p.MaxDiscount.Currency); err != nil {
+				return err
+			}
+		}
+	case TypeFixed:
+		if _, err := money.Exponent(p.AmountOff.Currency); err != nil {
+			return err
+		}
+		if !p.AmountOff.IsPositive() {
+			return fmt.Errorf("amount off must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown discount type %q", p.Type)
+	}
+	if !p.StartsAt.IsZero() && !p.EndsAt.IsZero() && !p.EndsAt.After(p.StartsAt) {
+		return fmt.Errorf("promotion must end after it starts")
+	}
+	if p.MaxRedemptions < 0 || p.MaxPerUser < 0 {
+		return fmt.Errorf("redemption limits cannot be negative")
+	}
+	return nil
+}
+
+// CheckActive returns an error unless the code can be redeemed by someone at
+// now: it is within its validity window and not fully redeemed.
+func (p *Promotion) CheckActive(now time.Time) error {
+	if (!p.StartsAt.IsZero() && now.Before(p.StartsAt)) || (!p.EndsAt.IsZero() && !now.Before(p.EndsAt)) {
+		return ErrNotActive
+	}
+	if p.MaxRedemptions > 0 && p.Redemptions >= p.MaxRedemptions {
+		return ErrExhausted
+	}
+	return nil
+}
+
+// Discount checks every rule against u and returns what the code takes off
+// u.Fare, which is never more than the fare.
+func (p *Promotion) Discount(u Usage) (money.Money, error) {
+	if err := p.CheckActive(u.Now); err != nil {
+		return money.Money{}, err
+	}
+	if p.MaxPerUser > 0 && u.UserRedemptions >= p.MaxPerUser {
+		return money.Money{}, ErrUserLimit
+	}
+	if p.FirstRideOnly && u.PriorBookings > 0 {
+		return money.Money{}, ErrFirstRideOnly
+	}
+	if p.CityPlaceID != "" && u.PickupPlaceID != p.CityPlaceID {
+		return money.Money{}, ErrWrongCity
+	}
+
+	var discount money.Money
+	switch p.Type {
+	case TypePercent:
+		var err error
+		if discount, err = u.Fare.MulRatio(p.PercentBps, 10000, money.HalfUp); err != nil {
+			return money.Money{}, err
+		}
+		if p.MaxDiscount.IsPositive() {
+			if p.MaxDiscount.Currency != u.Fare.Currency {
+				return money.Money{}, ErrWrongCurrency
+			}
+			if discount, err = smaller(discount, p.MaxDiscount); err != nil {
+				return money.Money{}, err
+			}
+		}
+	case TypeFixed:
+		if p.AmountOff.Currency != u.Fare.Currency {
+			return money.Money{}, ErrWrongCurrency
+		}
+		discount = p.AmountOff
+	default:
+		return money.Money{}, fmt.Errorf("unknown discount type %q", p.Type)
+	}
+	return smaller(discount, u.Fare)
+}
+
+func smaller(a, b money.Money) (money.Money, error) {
+	cmp, err := a.Cmp(b)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if cmp > 0 {
+		return b, nil
+	}
+	return a, nil
+}
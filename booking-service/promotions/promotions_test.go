@@ -0,0 +1,103 @@
+package promotions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+func rupees(minor int64) money.Money {
+	return money.Money{Currency: money.PKR, Minor: minor}
+}
+
+var now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestPromotion_Validate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		promotion Promotion
+		wantErr   bool
+	}{
+		{name: "Percent", promotion: Promotion{Code: "TEN", Type: TypePercent, PercentBps: 1000}},
+		{name: "Fixed", promotion: Promotion{Code: "FLAT", Type: TypeFixed, AmountOff: rupees(5000)}},
+		{name: "Lower Case Code", promotion: Promotion{Code: "ten", Type: TypePercent, PercentBps: 1000}, wantErr: true},
+		{name: "Empty Code", promotion: Promotion{Type: TypePercent, PercentBps: 1000}, wantErr: true},
+		{name: "Over 100 Percent", promotion: Promotion{Code: "TEN", Type: TypePercent, PercentBps: 10001}, wantErr: true},
+		{name: "Cap Without Currency", promotion: Promotion{Code: "TEN", Type: TypePercent, PercentBps: 1000,
+			MaxDiscount: money.Money{Minor: 100}}, wantErr: true},
+		{name: "Zero Amount Off", promotion: Promotion{Code: "FLAT", Type: TypeFixed, AmountOff: rupees(0)}, wantErr: true},
+		{name: "Unknown Type", promotion: Promotion{Code: "BOGO", Type: "BOGO"}, wantErr: true},
+		{name: "Ends Before It Starts", promotion: Promotion{Code: "TEN", Type: TypePercent, PercentBps: 1000,
+			StartsAt: now, EndsAt: now.Add(-time.Hour)}, wantErr: true},
+		{name: "Negative Limit", promotion: Promotion{Code: "TEN", Type: TypePercent, PercentBps: 1000, MaxPerUser: -1}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.promotion.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPromotion_Discount(t *testing.T) {
+	usage := Usage{Now: now, Fare: rupees(53000), PickupPlaceID: "pk-khi"}
+
+	testCases := []struct {
+		name      string
+		promotion Promotion
+		usage     func(u *Usage)
+		expected  money.Money
+		wantErr   error
+	}{
+		{name: "Percent", promotion: Promotion{Type: TypePercent, PercentBps: 1250}, expected: rupees(6625)},
+		{name: "Percent Capped", promotion: Promotion{Type: TypePercent, PercentBps: 5000, MaxDiscount: rupees(10000)}, expected: rupees(10000)},
+		{name: "Fixed", promotion: Promotion{Type: TypeFixed, AmountOff: rupees(5000)}, expected: rupees(5000)},
+		{name: "Fixed Above Fare", promotion: Promotion{Type: TypeFixed, AmountOff: rupees(99900)}, expected: rupees(53000)},
+		{name: "Fixed In Another Currency", promotion: Promotion{Type: TypeFixed, AmountOff: money.Money{Currency: "USD", Minor: 500}},
+			wantErr: ErrWrongCurrency},
+		{name: "Not Started", promotion: Promotion{Type: TypePercent, PercentBps: 1000, StartsAt: now.Add(time.Minute)}, wantErr: ErrNotActive},
+		{name: "Ended", promotion: Promotion{Type: TypePercent, PercentBps: 1000, EndsAt: now}, wantErr: ErrNotActive},
+		{name: "Exhausted", promotion: Promotion{Type: TypePercent, PercentBps: 1000, MaxRedemptions: 100, Redemptions: 100},
+			wantErr: ErrExhausted},
+		{name: "User Limit", promotion: Promotion{Type: TypePercent, PercentBps: 1000, MaxPerUser: 2},
+			usage: func(u *Usage) { u.UserRedemptions = 2 }, wantErr: ErrUserLimit},
+		{name: "First Ride", promotion: Promotion{Type: TypePercent, PercentBps: 1000, FirstRideOnly: true}, expected: rupees(5300)},
+		{name: "Not First Ride", promotion: Promotion{Type: TypePercent, PercentBps: 1000, FirstRideOnly: true},
+			usage: func(u *Usage) { u.PriorBookings = 1 }, wantErr: ErrFirstRideOnly},
+		{name: "City", promotion: Promotion{Type: TypePercent, PercentBps: 1000, CityPlaceID: "pk-khi"}, expected: rupees(5300)},
+		{name: "Wrong City", promotion: Promotion{Type: TypePercent, PercentBps: 1000, CityPlaceID: "pk-lhe"}, wantErr: ErrWrongCity},
+		{name: "Unknown Pickup", promotion: Promotion{Type: TypePercent, PercentBps: 1000, CityPlaceID: "pk-khi"},
+			usage: func(u *Usage) { u.PickupPlaceID = "" }, wantErr: ErrWrongCity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := usage
+			if tc.usage != nil {
+				tc.usage(&u)
+			}
+
+			discount, err := tc.promotion.Discount(u)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.True(t, IsIneligible(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, discount)
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "WELCOME10", Normalize(" welcome10\n"))
+}
@@ -0,0 +1,141 @@
+// Package ratings holds the rules of rider ratings and the statistics kept
+// for each driver and route.
+package ratings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxCommentLength is the longest comment a rating may carry, in characters.
+const MaxCommentLength = 1000
+
+// Tags riders can attach to a rating.
+var Tags = []string{
+	"CLEAN_CAR",
+	"SAFE_DRIVING",
+	"FRIENDLY",
+	"ON_TIME",
+	"GOOD_ROUTE",
+	"DIRTY_CAR",
+	"UNSAFE_DRIVING",
+	"RUDE",
+	"LATE",
+	"WRONG_ROUTE",
+}
+
+// Rating is a rider's rating of a completed booking.
+type Rating struct {
+	BookingID int32
+	UserID    int32
+	// DriverID is the booking's driver, or 0 if it had none.
+	DriverID int32
+	// Route is the booking's route, or zero if its places were not
+	// recognised.
+	Route     Route
+	Stars     int32
+	Comment   string
+	Tags      []string
+	CreatedAt time.Time
+}
+
+// Route is a ride's source and destination gazetteer places.
+type Route struct {
+	SourcePlaceID      string
+	DestinationPlaceID string
+}
+
+// IsZero reports whether the route is unknown.
+func (r Route) IsZero() bool {
+	return r.SourcePlaceID == "" || r.DestinationPlaceID == ""
+}
+
+// DriverSubject returns the subject of a driver's statistics.
+func DriverSubject(driverID int32) string {
+	return fmt.Sprintf("driver:%d", driverID)
+}
+
+// RouteSubject returns the subject of a route's statistics.
+func RouteSubject(route Route) string {
+	return fmt.Sprintf("route:%s:%s", route.SourcePlaceID, route.DestinationPlaceID)
+}
+
+// Subjects returns the subjects whose statistics include r.
+func (r *Rating) Subjects() []string {
+	var subjects []string
+	if r.DriverID != 0 {
+		subjects = append(subjects, DriverSubject(r.DriverID))
+	}
+	if !r.Route.IsZero() {
+		subjects = append(subjects, RouteSubject(r.Route))
+	}
+	return subjects
+}
+
+// Normalize trims the comment and upper-cases and sorts the tags, dropping
+// duplicates.
+func (r *Rating) Normalize() {
+	r.Comment = strings.TrimSpace(r.Comment)
+
+	seen := map[string]bool{}
+	var tags []string
+	for _, tag := range r.Tags {
+		tag = strings.ToUpper(strings.TrimSpace(tag))
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	r.Tags = tags
+}
+
+// Validate returns an error if r's stars, comment or tags are not allowed.
+func (r *Rating) Validate() error {
+	if r.Stars < 1 || r.Stars > 5 {
+		return fmt.Errorf("stars must be between 1 and 5")
+	}
+	if utf8.RuneCountInString(r.Comment) > MaxCommentLength {
+		return fmt.Errorf("comment must be at most %d characters", MaxCommentLength)
+	}
+	for _, tag := range r.Tags {
+		if !isTag(tag) {
+			return fmt.Errorf("unknown tag %q", tag)
+		}
+	}
+	return nil
+}
+
+func isTag(tag string) bool {
+	for _, t := range Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats aggregates the ratings of a subject.
+type Stats struct {
+	Count int64
+	// Stars counts the ratings with each number of stars; Stars[0] counts
+	// one-star ratings.
+	Stars [5]int64
+	// Tags counts the ratings carrying each tag.
+	Tags map[string]int64
+}
+
+// Average returns the mean number of stars, or 0 without ratings.
+func (s Stats) Average() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	var total int64
+	for i, n := range s.Stars {
+		total += int64(i+1) * n
+	}
+	return float64(total) / float64(s.Count)
+}
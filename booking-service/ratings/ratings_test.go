@@ -0,0 +1,58 @@
+package ratings
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRating_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		rating  Rating
+		wantErr bool
+	}{
+		{name: "Stars Only", rating: Rating{Stars: 5}},
+		{name: "Comment And Tags", rating: Rating{Stars: 4, Comment: "Smooth ride", Tags: []string{"ON_TIME", "FRIENDLY"}}},
+		{name: "No Stars", rating: Rating{}, wantErr: true},
+		{name: "Six Stars", rating: Rating{Stars: 6}, wantErr: true},
+		{name: "Unknown Tag", rating: Rating{Stars: 3, Tags: []string{"FAST"}}, wantErr: true},
+		{name: "Long Comment", rating: Rating{Stars: 3, Comment: strings.Repeat("ب", MaxCommentLength+1)}, wantErr: true},
+		{name: "Longest Comment", rating: Rating{Stars: 3, Comment: strings.Repeat("ب", MaxCommentLength)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rating.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRating_Normalize(t *testing.T) {
+	rating := Rating{Stars: 5, Comment: "  Great driver \n", Tags: []string{"on_time", " FRIENDLY", "ON_TIME"}}
+	rating.Normalize()
+
+	assert.Equal(t, "Great driver", rating.Comment)
+	assert.Equal(t, []string{"FRIENDLY", "ON_TIME"}, rating.Tags)
+	assert.NoError(t, rating.Validate())
+}
+
+func TestRating_Subjects(t *testing.T) {
+	rating := Rating{DriverID: 7, Route: Route{SourcePlaceID: "pk-khi", DestinationPlaceID: "pk-lhe"}}
+	assert.Equal(t, []string{"driver:7", "route:pk-khi:pk-lhe"}, rating.Subjects())
+
+	// Without a driver or a recognised route there is nothing to aggregate
+	rating = Rating{Route: Route{SourcePlaceID: "pk-khi"}}
+	assert.Empty(t, rating.Subjects())
+}
+
+func TestStats_Average(t *testing.T) {
+	assert.Equal(t, 0.0, Stats{}.Average())
+	assert.Equal(t, 4.25, Stats{Count: 4, Stars: [5]int64{0, 0, 1, 1, 2}}.Average())
+}
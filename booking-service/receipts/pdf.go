@@ -0,0 +1,102 @@
+package receipts
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Fonts of the standard 14 that every PDF reader provides, so none has to be
+// embedded.
+const (
+	fontRegular  = "F1"
+	fontBold     = "F2"
+	fontMono     = "F3"
+	fontMonoBold = "F4"
+)
+
+var baseFonts = []struct{ name, baseFont string }{
+	{fontRegular, "Helvetica"},
+	{fontBold, "Helvetica-Bold"},
+	{fontMono, "Courier"},
+	{fontMonoBold, "Courier-Bold"},
+}
+
+// courierWidth is the advance of every Courier glyph, in thousandths of the
+// font size.
+const courierWidth = 600
+
+// pdfPage collects the drawing operators of a single A4 page.
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+// text draws s with its baseline starting at (x, y), in points from the
+// bottom left corner.
+func (p *pdfPage) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %g Tf %g %g Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+// rightText draws s in a Courier font so it ends at x.
+func (p *pdfPage) rightText(font string, size, x, y float64, s string) {
+	width := float64(utf8.RuneCountInString(s)) * size * courierWidth / 1000
+	p.text(font, size, x-width, y, s)
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %g %g m %g %g l S\n", x1, y1, x2, y2)
+}
+
+// document returns the page as a complete PDF file.
+func (p *pdfPage) document() []byte {
+	fontRefs := make([]string, len(baseFonts))
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"", // the page, once the font objects are numbered
+	}
+	for i, f := range baseFonts {
+		objects = append(objects, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.baseFont))
+		fontRefs[i] = fmt.Sprintf("/%s %d 0 R", f.name, len(objects))
+	}
+	objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	objects[2] = fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+		strings.Join(fontRefs, " "), len(objects))
+
+	var doc bytes.Buffer
+	doc.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = doc.Len()
+		fmt.Fprintf(&doc, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := doc.Len()
+	fmt.Fprintf(&doc, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&doc, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&doc, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return doc.Bytes()
+}
+
+// pdfString encodes s for a PDF string literal in WinAnsiEncoding. Characters
+// outside Latin-1 are replaced with '?'.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,233 @@
+// Package receipts builds the itemized receipt of a completed booking and
+// renders it as HTML and PDF.
+package receipts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+// Trip is what a receipt is built from, gathered when the booking completes.
+type Trip struct {
+	BookingID    int32
+	UserName     string
+	Source       string
+	Destination  string
+	Distance     int32
+	VehicleClass string
+	CompletedAt  time.Time
+	Fare         money.Money
+	// Components itemize Fare; nil for rides booked without a breakdown.
+	Components *FareComponents
+	// PromoCode and Discount are empty and zero without a promotion.
+	PromoCode string
+	Discount  money.Money
+}
+
+// FareComponents are how a fare was computed. The adjustments are what the
+// minimum fare and the multipliers added, so the amounts add up to the fare.
+type FareComponents struct {
+	BaseFare              money.Money
+	DistanceFare          money.Money
+	MinimumFareAdjustment money.Money
+	// Multipliers are in basis points, where 10000 is 1x.
+	TimeOfDayMultiplierBP int32
+	TimeOfDayAdjustment   money.Money
+	SurgeMultiplierBP     int32
+	SurgeAdjustment       money.Money
+}
+
+// LineItem is one line of a receipt; discounts are negative.
+type LineItem struct {
+	Description string      `json:"description"`
+	Amount      money.Money `json:"amount"`
+}
+
+// Receipt is an issued receipt. It is stored as issued, so later changes to
+// the user or ride do not alter it.
+type Receipt struct {
+	// InvoiceNumber is assigned when the receipt is issued, e.g.
+	// "INV-202610-000042".
+	InvoiceNumber string    `json:"invoice_number"`
+	BookingID     int32     `json:"booking_id"`
+	IssuedAt      time.Time `json:"issued_at"`
+
+	UserName     string    `json:"user_name"`
+	Source       string    `json:"source"`
+	Destination  string    `json:"destination"`
+	Distance     int32     `json:"distance"`
+	VehicleClass string    `json:"vehicle_class"`
+	CompletedAt  time.Time `json:"completed_at"`
+
+	Items []LineItem  `json:"items"`
+	Total money.Money `json:"total"`
+	// Fares include tax; Tax is the part of Total that is tax at TaxRateBps
+	// basis points. It is the last item, and the others are net of it.
+	TaxRateBps int64       `json:"tax_rate_bps"`
+	Tax        money.Money `json:"tax"`
+}
+
+// New itemizes trip: one line per fare component, or a single fare line
+// without them, then the promo code and the tax. taxRateBps is the tax rate
+// included in fares, in basis points.
+func New(trip Trip, taxRateBps int64) (*Receipt, error) {
+	if taxRateBps < 0 {
+		return nil, fmt.Errorf("tax rate cannot be negative")
+	}
+
+	items, err := fareItems(trip)
+	if err != nil {
+		return nil, err
+	}
+	total := trip.Fare
+	if trip.PromoCode != "" {
+		discount, err := money.New(trip.Discount.Currency, -trip.Discount.Minor)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, LineItem{Description: fmt.Sprintf("Promo code %s", trip.PromoCode), Amount: discount})
+		if total, err = total.Add(discount); err != nil {
+			return nil, err
+		}
+	}
+
+	// The tax in a tax-inclusive total t at rate r is t * r / (1 + r)
+	tax, err := total.MulRatio(taxRateBps, 10000+taxRateBps, money.HalfUp)
+	if err != nil {
+		return nil, err
+	}
+	if items, err = netOfTax(items, total, tax, taxRateBps); err != nil {
+		return nil, err
+	}
+	items = append(items, LineItem{Description: fmt.Sprintf("Tax (%s)", TaxRate(taxRateBps)), Amount: tax})
+
+	return &Receipt{
+		BookingID:    trip.BookingID,
+		UserName:     trip.UserName,
+		Source:       trip.Source,
+		Destination:  trip.Destination,
+		Distance:     trip.Distance,
+		VehicleClass: trip.VehicleClass,
+		CompletedAt:  trip.CompletedAt,
+		Items:        items,
+		Total:        total,
+		TaxRateBps:   taxRateBps,
+		Tax:          tax,
+	}, nil
+}
+
+// fareItems itemizes trip's fare, leaving out components that added nothing.
+func fareItems(trip Trip) ([]LineItem, error) {
+	c := trip.Components
+	if c == nil {
+		return []LineItem{{
+			Description: fmt.Sprintf("Ride fare (%s, %d km)", trip.VehicleClass, trip.Distance),
+			Amount:      trip.Fare,
+		}}, nil
+	}
+
+	items := []LineItem{
+		{Description: fmt.Sprintf("Base fare (%s)", trip.VehicleClass), Amount: c.BaseFare},
+		{Description: fmt.Sprintf("Distance (%d km)", trip.Distance), Amount: c.DistanceFare},
+	}
+	if !c.MinimumFareAdjustment.IsZero() {
+		items = append(items, LineItem{Description: "Minimum fare", Amount: c.MinimumFareAdjustment})
+	}
+	if !c.TimeOfDayAdjustment.IsZero() {
+		items = append(items, LineItem{
+			Description: fmt.Sprintf("Time of day (%s)", Multiplier(c.TimeOfDayMultiplierBP)),
+			Amount:      c.TimeOfDayAdjustment,
+		})
+	}
+	if !c.SurgeAdjustment.IsZero() {
+		items = append(items, LineItem{
+			Description: fmt.Sprintf("Surge (%s)", Multiplier(c.SurgeMultiplierBP)),
+			Amount:      c.SurgeAdjustment,
+		})
+	}
+
+	sum, err := sumItems(items)
+	if err != nil {
+		return nil, err
+	}
+	if sum != trip.Fare {
+		return nil, fmt.Errorf("fare components add up to %s, not the fare of %s", sum, trip.Fare)
+	}
+	return items, nil
+}
+
+// netOfTax takes the tax out of each of items, whose amounts include it and
+// add up to total. Rounding is settled on the first item, so that the items
+// and tax still add up to total.
+func netOfTax(items []LineItem, total, tax money.Money, taxRateBps int64) ([]LineItem, error) {
+	net := make([]LineItem, len(items))
+	for i, item := range items {
+		amount, err := item.Amount.MulRatio(10000, 10000+taxRateBps, money.HalfUp)
+		if err != nil {
+			return nil, err
+		}
+		net[i] = LineItem{Description: item.Description, Amount: amount}
+	}
+
+	sum, err := sumItems(net)
+	if err != nil {
+		return nil, err
+	}
+	if sum, err = sum.Add(tax); err != nil {
+		return nil, err
+	}
+	residual, err := total.Sub(sum)
+	if err != nil {
+		return nil, err
+	}
+	if net[0].Amount, err = net[0].Amount.Add(residual); err != nil {
+		return nil, err
+	}
+	return net, nil
+}
+
+func sumItems(items []LineItem) (money.Money, error) {
+	sum := items[0].Amount
+	for _, item := range items[1:] {
+		var err error
+		if sum, err = sum.Add(item.Amount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return sum, nil
+}
+
+// InvoiceNumber formats the seq-th invoice of the month of issuedAt, in UTC.
+func InvoiceNumber(issuedAt time.Time, seq int64) string {
+	return fmt.Sprintf("INV-%s-%06d", Period(issuedAt), seq)
+}
+
+// Period returns the month invoices issued at t are numbered in, e.g.
+// "202610".
+func Period(t time.Time) string {
+	return t.UTC().Format("200601")
+}
+
+// TaxRate formats a rate in basis points as a percentage, e.g. "16%" or
+// "12.5%".
+func TaxRate(bps int64) string {
+	if bps%100 == 0 {
+		return fmt.Sprintf("%d%%", bps/100)
+	}
+	return fmt.Sprintf("%s%%", trimZeros(fmt.Sprintf("%d.%02d", bps/100, bps%100)))
+}
+
+// Multiplier formats a multiplier in basis points, e.g. "1.5x" or "1.105x".
+func Multiplier(bp int32) string {
+	return fmt.Sprintf("%sx", strings.TrimSuffix(trimZeros(fmt.Sprintf("%d.%04d", bp/10000, bp%10000)), "."))
+}
+
+func trimZeros(s string) string {
+	for s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,210 @@
+package receipts
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+func rupees(minor int64) money.Money {
+	return money.Money{Currency: money.PKR, Minor: minor}
+}
+
+func testTrip() Trip {
+	return Trip{
+		BookingID:    7,
+		UserName:     "Fatima",
+		Source:       "Karachi",
+		Destination:  "Lahore",
+		Distance:     1200,
+		VehicleClass: "ECONOMY",
+		CompletedAt:  time.Date(2026, 10, 19, 16, 30, 0, 0, time.UTC),
+		Fare:         rupees(530000),
+	}
+}
+
+func TestNew(t *testing.T) {
+	receipt, err := New(testTrip(), 1600)
+	require.NoError(t, err)
+	// 5300 * 16 / 116 = 731.03, and the fare is shown net of it
+	assert.Equal(t, []LineItem{
+		{Description: "Ride fare (ECONOMY, 1200 km)", Amount: rupees(456897)},
+		{Description: "Tax (16%)", Amount: rupees(73103)},
+	}, receipt.Items)
+	assert.Equal(t, rupees(530000), receipt.Total)
+	assert.Equal(t, rupees(73103), receipt.Tax)
+
+	trip := testTrip()
+	trip.PromoCode = "WELCOME20"
+	trip.Discount = rupees(50000)
+	receipt, err = New(trip, 0)
+	require.NoError(t, err)
+	assert.Equal(t, LineItem{Description: "Promo code WELCOME20", Amount: rupees(-50000)}, receipt.Items[1])
+	assert.Equal(t, LineItem{Description: "Tax (0%)", Amount: rupees(0)}, receipt.Items[2])
+	assert.Equal(t, rupees(480000), receipt.Total)
+	assert.Equal(t, rupees(0), receipt.Tax)
+
+	_, err = New(testTrip(), -1)
+	assert.Error(t, err)
+}
+
+func TestNew_FareComponents(t *testing.T) {
+	trip := testTrip()
+	trip.Fare = rupees(742500)
+	trip.Components = &FareComponents{
+		BaseFare:              rupees(50000),
+		DistanceFare:          rupees(400000),
+		MinimumFareAdjustment: rupees(0),
+		TimeOfDayMultiplierBP: 11000,
+		TimeOfDayAdjustment:   rupees(45000),
+		SurgeMultiplierBP:     15000,
+		SurgeAdjustment:       rupees(247500),
+	}
+
+	receipt, err := New(trip, 1600)
+	require.NoError(t, err)
+	// Each component net of 16% tax; the minimum fare added nothing
+	assert.Equal(t, []LineItem{
+		{Description: "Base fare (ECONOMY)", Amount: rupees(43103)},
+		{Description: "Distance (1200 km)", Amount: rupees(344828)},
+		{Description: "Time of day (1.1x)", Amount: rupees(38793)},
+		{Description: "Surge (1.5x)", Amount: rupees(213362)},
+		{Description: "Tax (16%)", Amount: rupees(102414)},
+	}, receipt.Items)
+	assert.Equal(t, rupees(742500), receipt.Total)
+
+	// Components that do not add up to the fare are rejected
+	trip.Components.SurgeAdjustment = rupees(0)
+	_, err = New(trip, 1600)
+	assert.Error(t, err)
+}
+
+func TestNew_ItemsAddUpToTotal(t *testing.T) {
+	for _, taxRateBps := range []int64{0, 1250, 1600, 1700} {
+		for _, discount := range []int64{0, 3333, 50000} {
+			for fare := int64(70000); fare < 80000; fare += 997 {
+				trip := testTrip()
+				trip.Fare = rupees(fare)
+				trip.Components = &FareComponents{
+					BaseFare:              rupees(50000),
+					DistanceFare:          rupees(fare - 50000 - fare/7 - fare/11),
+					MinimumFareAdjustment: rupees(0),
+					TimeOfDayMultiplierBP: 11050,
+					TimeOfDayAdjustment:   rupees(fare / 7),
+					SurgeMultiplierBP:     11250,
+					SurgeAdjustment:       rupees(fare / 11),
+				}
+				if discount > 0 {
+					trip.PromoCode = "SAVE"
+					trip.Discount = rupees(discount)
+				}
+
+				receipt, err := New(trip, taxRateBps)
+				require.NoError(t, err)
+
+				sum := rupees(0)
+				for _, item := range receipt.Items {
+					sum, err = sum.Add(item.Amount)
+					require.NoError(t, err)
+				}
+				assert.Equal(t, receipt.Total, sum, "fare %d, discount %d, tax %d bps", fare, discount, taxRateBps)
+				assert.Equal(t, receipt.Tax, receipt.Items[len(receipt.Items)-1].Amount)
+			}
+		}
+	}
+}
+
+func TestInvoiceNumber(t *testing.T) {
+	// Numbered in the UTC month
+	issuedAt := time.Date(2026, 11, 1, 2, 0, 0, 0, time.FixedZone("PKT", 5*60*60))
+	assert.Equal(t, "INV-202610-000042", InvoiceNumber(issuedAt, 42))
+}
+
+func TestTaxRate(t *testing.T) {
+	assert.Equal(t, "16%", TaxRate(1600))
+	assert.Equal(t, "12.5%", TaxRate(1250))
+	assert.Equal(t, "0.75%", TaxRate(75))
+	assert.Equal(t, "0%", TaxRate(0))
+}
+
+func TestMultiplier(t *testing.T) {
+	assert.Equal(t, "1x", Multiplier(10000))
+	assert.Equal(t, "1.5x", Multiplier(15000))
+	assert.Equal(t, "1.105x", Multiplier(11050))
+	assert.Equal(t, "2x", Multiplier(20000))
+}
+
+func testReceipt(t *testing.T) *Receipt {
+	trip := testTrip()
+	trip.UserName = "<Fatima & Ali>"
+	trip.PromoCode = "WELCOME20"
+	trip.Discount = rupees(50000)
+	receipt, err := New(trip, 1600)
+	require.NoError(t, err)
+	receipt.InvoiceNumber = "INV-202610-000001"
+	receipt.IssuedAt = time.Date(2026, 10, 19, 16, 31, 0, 0, time.UTC)
+	return receipt
+}
+
+func TestHTML(t *testing.T) {
+	html, err := HTML(testReceipt(t))
+	require.NoError(t, err)
+
+	for _, want := range []string{
+		"Invoice INV-202610-000001",
+		"Issued 19 Oct 2026 16:31 UTC",
+		"Billed to &lt;Fatima &amp; Ali&gt;",
+		"Karachi to Lahore, 1200 km, ECONOMY",
+		`<td>Ride fare (ECONOMY, 1200 km)</td><td class="amount">PKR 4,568.96</td>`,
+		`<td>Promo code WELCOME20</td><td class="amount">PKR -431.03</td>`,
+		`<td>Tax (16%)</td><td class="amount">PKR 662.07</td>`,
+		`<td>Total</td><td class="amount">PKR 4,800.00</td>`,
+	} {
+		assert.Contains(t, string(html), want)
+	}
+}
+
+func TestPDF(t *testing.T) {
+	pdf := PDF(testReceipt(t))
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "(Invoice INV-202610-000001) Tj")
+	assert.Contains(t, string(pdf), "(PKR 4,800.00) Tj")
+	assert.Contains(t, string(pdf), `(Tax \(16%\)) Tj`)
+
+	// The cross-reference table points at each object, and startxref at the
+	// table
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	require.Len(t, offsets, 8)
+	for i, offset := range offsets {
+		at, err := strconv.Atoi(string(offset[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(pdf[at:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+
+	// The content stream's length is exact
+	length := regexp.MustCompile(`/Length (\d+) >>\nstream\n`).FindSubmatchIndex(pdf)
+	require.NotNil(t, length)
+	n, err := strconv.Atoi(string(pdf[length[2]:length[3]]))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf[length[1]+n:], []byte("endstream")))
+}
+
+func TestPDFString(t *testing.T) {
+	assert.Equal(t, `Caf\351 \(Clifton\) \\ ?`, pdfString(`Café (Clifton) \ →`))
+}
@@ -0,0 +1,87 @@
+package receipts
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+const dateLayout = "2 Jan 2006 15:04 MST"
+
+var htmlTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.UTC().Format(dateLayout) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Receipt {{.InvoiceNumber}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; max-width: 40em; margin: 2em auto; color: #222; }
+table { width: 100%; border-collapse: collapse; }
+td { padding: 0.3em 0; }
+td.amount { text-align: right; white-space: nowrap; }
+tr.total td { border-top: 1px solid #222; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Receipt</h1>
+<p>Invoice {{.InvoiceNumber}}<br>Issued {{date .IssuedAt}}<br>Booking #{{.BookingID}}</p>
+<p>Billed to {{.UserName}}</p>
+<p>{{.Source}} to {{.Destination}}, {{.Distance}} km, {{.VehicleClass}}<br>Completed {{date .CompletedAt}}</p>
+<table>
+{{- range .Items}}
+<tr><td>{{.Description}}</td><td class="amount">{{.Amount}}</td></tr>
+{{- end}}
+<tr class="total"><td>Total</td><td class="amount">{{.Total}}</td></tr>
+</table>
+</body>
+</html>
+`))
+
+// HTML renders r as a standalone HTML page.
+func HTML(r *Receipt) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PDF renders r as a single A4 page.
+func PDF(r *Receipt) []byte {
+	const left, right = 50, 545
+	p := &pdfPage{}
+
+	y := 780.0
+	p.text(fontBold, 20, left, y, "Receipt")
+	y -= 30
+	for _, line := range []string{
+		"Invoice " + r.InvoiceNumber,
+		"Issued " + r.IssuedAt.UTC().Format(dateLayout),
+		fmt.Sprintf("Booking #%d", r.BookingID),
+	} {
+		p.text(fontRegular, 11, left, y, line)
+		y -= 15
+	}
+
+	y -= 15
+	p.text(fontRegular, 11, left, y, "Billed to "+r.UserName)
+	y -= 30
+	p.text(fontRegular, 11, left, y, fmt.Sprintf("%s to %s, %d km, %s", r.Source, r.Destination, r.Distance, r.VehicleClass))
+	y -= 15
+	p.text(fontRegular, 11, left, y, "Completed "+r.CompletedAt.UTC().Format(dateLayout))
+
+	y -= 35
+	for _, item := range r.Items {
+		p.text(fontRegular, 11, left, y, item.Description)
+		p.rightText(fontMono, 11, right, y, item.Amount.String())
+		y -= 18
+	}
+	p.line(left, y+12, right, y+12)
+	y -= 4
+	p.text(fontBold, 12, left, y, "Total")
+	p.rightText(fontMonoBold, 12, right, y, r.Total.String())
+
+	return p.document()
+}
@@ -5,19 +5,100 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
+
+	"booking-service/promotions"
+
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+const (
+	StatusScheduled = "SCHEDULED"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+	StatusCompleted = "COMPLETED"
+	StatusDisputed  = "DISPUTED"
 )
 
+// Domain events written to the outbox alongside booking state changes.
+const (
+	AggregateBooking           = "booking"
+	EventBookingCreated        = "BookingCreated"
+	EventBookingCancelled      = "BookingCancelled"
+	EventBookingRideUpdated    = "BookingRideUpdated"
+	EventBookingDriverAssigned = "BookingDriverAssigned"
+	EventBookingScheduled      = "BookingScheduled"
+	EventBookingActivated      = "BookingActivated"
+	EventBookingCompleted      = "BookingCompleted"
+	EventBookingDisputed       = "BookingDisputed"
+)
+
+// MethodCreateBooking is recorded in the audit log for immediate and
+// scheduled bookings alike.
+const MethodCreateBooking = "CreateBooking"
+
 type Booking struct {
 	ID     int32
 	UserID int32
 	RideID int32
-	Time   string
+	Status string
+	// DriverID is the assigned driver, or 0 if none is assigned.
+	DriverID int32
+	// PickupAt is the pickup time of a scheduled booking, or zero for an
+	// immediate one.
+	PickupAt time.Time
+	// Version starts at 1 and is incremented on every change to the booking
+	// or its ride.
+	Version int64
+	// PromoCode is the promotion redeemed with the booking and Discount what it
+	// took off the fare, or empty and zero without one.
+	PromoCode string
+	Discount  money.Money
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Redemption redeems a promo code with a new booking. The discount is worked
+// out as the booking is inserted, against the promotion's usage at that
+// moment.
+type Redemption struct {
+	Code          string
+	Fare          money.Money
+	PickupPlaceID string
+	Now           time.Time
+}
+
+// BookingEvent is the payload of booking domain events.
+type BookingEvent struct {
+	BookingID int32      `json:"booking_id"`
+	UserID    int32      `json:"user_id"`
+	RideID    int32      `json:"ride_id"`
+	Status    string     `json:"status"`
+	DriverID  int32      `json:"driver_id,omitempty"`
+	PickupAt  *time.Time `json:"pickup_at,omitempty"`
+	Version   int64      `json:"version"`
+	PromoCode string     `json:"promo_code,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 }
 
 type BookingRepository interface {
-	Create(ctx context.Context, userID, rideID int32) (*Booking, error)
+	// Create and Schedule redeem redemption with the booking when it is not
+	// nil, or fail without creating the booking if the code cannot be
+	// redeemed.
+	Create(ctx context.Context, userID, rideID int32, redemption *Redemption) (*Booking, error)
 	GetByID(ctx context.Context, id int32) (*Booking, error)
+	Cancel(ctx context.Context, id int32) (*Booking, error)
+	MarkRideUpdated(ctx context.Context, rideID int32) (int, error)
+	AssignDriver(ctx context.Context, id, driverID int32) (*Booking, error)
+	Schedule(ctx context.Context, userID, rideID int32, pickupAt time.Time, redemption *Redemption) (*Booking, error)
+	ActivateDue(ctx context.Context, dueBy time.Time, limit int) ([]*Booking, error)
+	Complete(ctx context.Context, id int32) (*Booking, error)
+	Dispute(ctx context.Context, id int32) (*Booking, error)
 }
 
 type PostgresBookingRepository struct {
@@ -28,30 +109,67 @@ func NewPostgresBookingRepository(db *sql.DB) BookingRepository {
 	return &PostgresBookingRepository{db: db}
 }
 
-func (r *PostgresBookingRepository) Create(ctx context.Context, userID, rideID int32) (*Booking, error) {
-	var bookingID int32
-	timestamp := time.Now().Format(time.RFC3339)
-	query := `INSERT INTO bookings (user_id, ride_id, time) VALUES ($1, $2, $3) RETURNING booking_id`
-	err := r.db.QueryRowContext(ctx, query, userID, rideID, timestamp).Scan(&bookingID)
+const bookingColumns = `booking_id, user_id, ride_id, status, COALESCE(driver_id, 0), pickup_at, version,
+	COALESCE(promo_code, ''), COALESCE(discount_currency, ''), COALESCE(discount_minor, 0), created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBooking(row rowScanner) (*Booking, error) {
+	b := &Booking{}
+	var pickupAt sql.NullTime
+	if err := row.Scan(&b.ID, &b.UserID, &b.RideID, &b.Status, &b.DriverID, &pickupAt, &b.Version,
+		&b.PromoCode, &b.Discount.Currency, &b.Discount.Minor, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		return nil, err
+	}
+	b.PickupAt = pickupAt.Time
+	return b, nil
+}
+
+func (r *PostgresBookingRepository) Create(ctx context.Context, userID, rideID int32, redemption *Redemption) (*Booking, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		log.Printf("Create booking failed: %v", err)
 		return nil, err
 	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO bookings (user_id, ride_id, status, version) VALUES ($1, $2, $3, 1) RETURNING ` + bookingColumns
+	booking, err := scanBooking(tx.QueryRowContext(ctx, query, userID, rideID, StatusConfirmed))
+	if err != nil {
+		log.Printf("Create booking failed: %v", err)
+		return nil, err
+	}
+
+	if redemption != nil {
+		if booking, err = redeem(ctx, tx, booking, redemption); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := recordBookingEvent(ctx, tx, EventBookingCreated, booking); err != nil {
+		log.Printf("Create booking failed: %v", err)
+		return nil, err
+	}
 
-	return &Booking{
-		ID:     bookingID,
-		UserID: userID,
-		RideID: rideID,
-		Time:   timestamp,
-	}, nil
+	// Riders book for themselves
+	if err := audit.Record(audit.WithUser(ctx, userID), tx, MethodCreateBooking, nil, bookingEvent(booking)); err != nil {
+		log.Printf("Create booking failed: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Create booking failed: %v", err)
+		return nil, err
+	}
+
+	return booking, nil
 }
 
 func (r *PostgresBookingRepository) GetByID(ctx context.Context, id int32) (*Booking, error) {
-	query := `SELECT user_id, ride_id, time FROM bookings WHERE booking_id = $1`
-	var userID, rideID int32
-	var timeStr string
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&userID, &rideID, &timeStr)
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE booking_id = $1`
+	booking, err := scanBooking(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("booking not found")
@@ -59,11 +177,379 @@ func (r *PostgresBookingRepository) GetByID(ctx context.Context, id int32) (*Boo
 		log.Printf("Get booking failed: %v", err)
 		return nil, err
 	}
+	return booking, nil
+}
+
+func (r *PostgresBookingRepository) Cancel(ctx context.Context, id int32) (*Booking, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Cancel booking failed: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT status FROM bookings WHERE booking_id = $1 FOR UPDATE`
+	var current string
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&current); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("booking not found")
+		}
+		log.Printf("Cancel booking failed: %v", err)
+		return nil, err
+	}
+
+	switch current {
+	case StatusCancelled:
+		return nil, fmt.Errorf("booking already cancelled")
+	case StatusCompleted, StatusDisputed:
+		return nil, fmt.Errorf("booking already completed")
+	}
+
+	query = `UPDATE bookings SET status = $1, version = version + 1, updated_at = now() WHERE booking_id = $2 RETURNING ` + bookingColumns
+	booking, err := scanBooking(tx.QueryRowContext(ctx, query, StatusCancelled, id))
+	if err != nil {
+		log.Printf("Cancel booking failed: %v", err)
+		return nil, err
+	}
+
+	// Give the promo code back, so a cancelled booking does not use it up
+	query = `WITH released AS (DELETE FROM promo_redemptions WHERE booking_id = $1 RETURNING code)
+		UPDATE promotions SET redemptions = redemptions - 1 WHERE code IN (SELECT code FROM released)`
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		log.Printf("Cancel booking failed: %v", err)
+		return nil, err
+	}
+
+	if err := recordBookingEvent(ctx, tx, EventBookingCancelled, booking); err != nil {
+		log.Printf("Cancel booking failed: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Cancel booking failed: %v", err)
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// redeem applies a promo code to a booking being inserted in tx. The
+// promotion's row is locked while its usage is counted and updated, so
+// concurrent bookings cannot redeem it past its limits.
+func redeem(ctx context.Context, tx *sql.Tx, booking *Booking, redemption *Redemption) (*Booking, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE code = $1 FOR UPDATE`
+	promotion, err := scanPromotion(tx.QueryRowContext(ctx, query, promotions.Normalize(redemption.Code)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("promotion not found")
+		}
+		log.Printf("Redeem promotion failed: %v", err)
+		return nil, err
+	}
+
+	usage := promotions.Usage{
+		Now:           redemption.Now,
+		Fare:          redemption.Fare,
+		PickupPlaceID: redemption.PickupPlaceID,
+	}
+	query = `SELECT COUNT(*) FROM promo_redemptions WHERE code = $1 AND user_id = $2`
+	if err := tx.QueryRowContext(ctx, query, promotion.Code, booking.UserID).Scan(&usage.UserRedemptions); err != nil {
+		log.Printf("Redeem promotion failed: %v", err)
+		return nil, err
+	}
+	query = `SELECT COUNT(*) FROM bookings WHERE user_id = $1 AND booking_id <> $2 AND status <> $3`
+	if err := tx.QueryRowContext(ctx, query, booking.UserID, booking.ID, StatusCancelled).Scan(&usage.PriorBookings); err != nil {
+		log.Printf("Redeem promotion failed: %v", err)
+		return nil, err
+	}
+
+	discount, err := promotion.Discount(usage)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `INSERT INTO promo_redemptions (booking_id, code, user_id, currency, discount_minor) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := tx.ExecContext(ctx, query, booking.ID, promotion.Code, booking.UserID, discount.Currency, discount.Minor); err != nil {
+		log.Printf("Redeem promotion failed: %v", err)
+		return nil, err
+	}
+	query = `UPDATE promotions SET redemptions = redemptions + 1 WHERE code = $1`
+	if _, err := tx.ExecContext(ctx, query, promotion.Code); err != nil {
+		log.Printf("Redeem promotion failed: %v", err)
+		return nil, err
+	}
+
+	query = `UPDATE bookings SET promo_code = $1, discount_currency = $2, discount_minor = $3 WHERE booking_id = $4
+		RETURNING ` + bookingColumns
+	booking, err = scanBooking(tx.QueryRowContext(ctx, query, promotion.Code, discount.Currency, discount.Minor, booking.ID))
+	if err != nil {
+		log.Printf("Redeem promotion failed: %v", err)
+		return nil, err
+	}
+	return booking, nil
+}
+
+// MarkRideUpdated bumps the version of every booking for rideID and records a
+// BookingRideUpdated event for each, so watchers re-read the ride details.
+// It returns the number of bookings affected.
+func (r *PostgresBookingRepository) MarkRideUpdated(ctx context.Context, rideID int32) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Mark ride updated failed: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE bookings SET version = version + 1, updated_at = now() WHERE ride_id = $1 RETURNING ` + bookingColumns
+	rows, err := tx.QueryContext(ctx, query, rideID)
+	if err != nil {
+		log.Printf("Mark ride updated failed: %v", err)
+		return 0, err
+	}
+
+	var bookings []*Booking
+	for rows.Next() {
+		b, err := scanBooking(rows)
+		if err != nil {
+			rows.Close()
+			log.Printf("Mark ride updated failed: %v", err)
+			return 0, err
+		}
+		bookings = append(bookings, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("Mark ride updated failed: %v", err)
+		return 0, err
+	}
 
-	return &Booking{
-		ID:     id,
-		UserID: userID,
-		RideID: rideID,
-		Time:   timeStr,
-	}, nil
+	for _, b := range bookings {
+		if err := recordBookingEvent(ctx, tx, EventBookingRideUpdated, b); err != nil {
+			log.Printf("Mark ride updated failed: %v", err)
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Mark ride updated failed: %v", err)
+		return 0, err
+	}
+
+	return len(bookings), nil
+}
+
+// AssignDriver records the driver assigned to a booking, bumps its version
+// and records a BookingDriverAssigned event.
+func (r *PostgresBookingRepository) AssignDriver(ctx context.Context, id, driverID int32) (*Booking, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Assign booking driver failed: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE bookings SET driver_id = $1, version = version + 1, updated_at = now() WHERE booking_id = $2 RETURNING ` + bookingColumns
+	booking, err := scanBooking(tx.QueryRowContext(ctx, query, driverID, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("booking not found")
+		}
+		log.Printf("Assign booking driver failed: %v", err)
+		return nil, err
+	}
+
+	if err := recordBookingEvent(ctx, tx, EventBookingDriverAssigned, booking); err != nil {
+		log.Printf("Assign booking driver failed: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Assign booking driver failed: %v", err)
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// Schedule creates a SCHEDULED booking for pickup at pickupAt. ActivateDue
+// confirms it shortly before pickup.
+func (r *PostgresBookingRepository) Schedule(ctx context.Context, userID, rideID int32, pickupAt time.Time, redemption *Redemption) (*Booking, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Schedule booking failed: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO bookings (user_id, ride_id, status, pickup_at, version) VALUES ($1, $2, $3, $4, 1)
+		RETURNING ` + bookingColumns
+	booking, err := scanBooking(tx.QueryRowContext(ctx, query, userID, rideID, StatusScheduled, pickupAt))
+	if err != nil {
+		log.Printf("Schedule booking failed: %v", err)
+		return nil, err
+	}
+
+	if redemption != nil {
+		if booking, err = redeem(ctx, tx, booking, redemption); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := recordBookingEvent(ctx, tx, EventBookingScheduled, booking); err != nil {
+		log.Printf("Schedule booking failed: %v", err)
+		return nil, err
+	}
+
+	// Riders book for themselves
+	if err := audit.Record(audit.WithUser(ctx, userID), tx, MethodCreateBooking, nil, bookingEvent(booking)); err != nil {
+		log.Printf("Schedule booking failed: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Schedule booking failed: %v", err)
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+// ActivateDue confirms up to limit SCHEDULED bookings with pickup at or before
+// dueBy, earliest first, and records a BookingActivated event for each. Each
+// booking is activated exactly once: concurrent callers skip rows another
+// caller has locked, and activated bookings are no longer SCHEDULED.
+func (r *PostgresBookingRepository) ActivateDue(ctx context.Context, dueBy time.Time, limit int) ([]*Booking, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Activate scheduled bookings failed: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE bookings SET status = $1, version = version + 1, updated_at = now()
+		WHERE booking_id IN (
+			SELECT booking_id FROM bookings
+			WHERE status = $2 AND pickup_at <= $3
+			ORDER BY pickup_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED)
+		RETURNING ` + bookingColumns
+	rows, err := tx.QueryContext(ctx, query, StatusConfirmed, StatusScheduled, dueBy, limit)
+	if err != nil {
+		log.Printf("Activate scheduled bookings failed: %v", err)
+		return nil, err
+	}
+
+	var bookings []*Booking
+	for rows.Next() {
+		b, err := scanBooking(rows)
+		if err != nil {
+			rows.Close()
+			log.Printf("Activate scheduled bookings failed: %v", err)
+			return nil, err
+		}
+		bookings = append(bookings, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("Activate scheduled bookings failed: %v", err)
+		return nil, err
+	}
+
+	for _, b := range bookings {
+		if err := recordBookingEvent(ctx, tx, EventBookingActivated, b); err != nil {
+			log.Printf("Activate scheduled bookings failed: %v", err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Activate scheduled bookings failed: %v", err)
+		return nil, err
+	}
+
+	return bookings, nil
+}
+
+// Complete marks a CONFIRMED booking COMPLETED and records a BookingCompleted
+// event. A booking that is already COMPLETED is returned unchanged.
+func (r *PostgresBookingRepository) Complete(ctx context.Context, id int32) (*Booking, error) {
+	return r.transition(ctx, id, StatusConfirmed, StatusCompleted, EventBookingCompleted, "Complete booking")
+}
+
+// Dispute marks a COMPLETED booking DISPUTED and records a BookingDisputed
+// event. A booking that is already DISPUTED is returned unchanged.
+func (r *PostgresBookingRepository) Dispute(ctx context.Context, id int32) (*Booking, error) {
+	return r.transition(ctx, id, StatusCompleted, StatusDisputed, EventBookingDisputed, "Dispute booking")
+}
+
+// transition moves a booking from one status to another. op names the
+// operation in logs.
+func (r *PostgresBookingRepository) transition(ctx context.Context, id int32, from, to, eventType, op string) (*Booking, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("%s failed: %v", op, err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE booking_id = $1 FOR UPDATE`
+	booking, err := scanBooking(tx.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("booking not found")
+		}
+		log.Printf("%s failed: %v", op, err)
+		return nil, err
+	}
+
+	switch booking.Status {
+	case to:
+		return booking, nil
+	case from:
+	default:
+		return nil, fmt.Errorf("booking is not %s", strings.ToLower(from))
+	}
+
+	query = `UPDATE bookings SET status = $1, version = version + 1, updated_at = now() WHERE booking_id = $2 RETURNING ` + bookingColumns
+	booking, err = scanBooking(tx.QueryRowContext(ctx, query, to, id))
+	if err != nil {
+		log.Printf("%s failed: %v", op, err)
+		return nil, err
+	}
+
+	if err := recordBookingEvent(ctx, tx, eventType, booking); err != nil {
+		log.Printf("%s failed: %v", op, err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("%s failed: %v", op, err)
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+func recordBookingEvent(ctx context.Context, tx *sql.Tx, eventType string, b *Booking) error {
+	return outbox.Record(ctx, tx, AggregateBooking, strconv.Itoa(int(b.ID)), eventType, bookingEvent(b))
+}
+
+// bookingEvent is b as published in events and recorded in the audit log.
+func bookingEvent(b *Booking) BookingEvent {
+	event := BookingEvent{
+		BookingID: b.ID,
+		UserID:    b.UserID,
+		RideID:    b.RideID,
+		Status:    b.Status,
+		DriverID:  b.DriverID,
+		Version:   b.Version,
+		PromoCode: b.PromoCode,
+		CreatedAt: b.CreatedAt,
+		UpdatedAt: b.UpdatedAt,
+	}
+	if !b.PickupAt.IsZero() {
+		event.PickupAt = &b.PickupAt
+	}
+	return event
 }
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 	repository "booking-service/repository"
@@ -15,13 +16,36 @@ type BookingRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: ctx, userID, rideID
-func (_m *BookingRepository) Create(ctx context.Context, userID int32, rideID int32) (*repository.Booking, error) {
-	ret := _m.Called(ctx, userID, rideID)
+// ActivateDue provides a mock function with given fields: ctx, dueBy, limit
+func (_m *BookingRepository) ActivateDue(ctx context.Context, dueBy time.Time, limit int) ([]*repository.Booking, error) {
+	ret := _m.Called(ctx, dueBy, limit)
+
+	var r0 []*repository.Booking
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*repository.Booking); ok {
+		r0 = rf(ctx, dueBy, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository.Booking)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, dueBy, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AssignDriver provides a mock function with given fields: ctx, id, driverID
+func (_m *BookingRepository) AssignDriver(ctx context.Context, id int32, driverID int32) (*repository.Booking, error) {
+	ret := _m.Called(ctx, id, driverID)
 
 	var r0 *repository.Booking
 	if rf, ok := ret.Get(0).(func(context.Context, int32, int32) *repository.Booking); ok {
-		r0 = rf(ctx, userID, rideID)
+		r0 = rf(ctx, id, driverID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*repository.Booking)
@@ -30,7 +54,99 @@ func (_m *BookingRepository) Create(ctx context.Context, userID int32, rideID in
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, int32, int32) error); ok {
-		r1 = rf(ctx, userID, rideID)
+		r1 = rf(ctx, id, driverID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Cancel provides a mock function with given fields: ctx, id
+func (_m *BookingRepository) Cancel(ctx context.Context, id int32) (*repository.Booking, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *repository.Booking
+	if rf, ok := ret.Get(0).(func(context.Context, int32) *repository.Booking); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.Booking)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Complete provides a mock function with given fields: ctx, id
+func (_m *BookingRepository) Complete(ctx context.Context, id int32) (*repository.Booking, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *repository.Booking
+	if rf, ok := ret.Get(0).(func(context.Context, int32) *repository.Booking); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.Booking)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, userID, rideID, redemption
+func (_m *BookingRepository) Create(ctx context.Context, userID int32, rideID int32, redemption *repository.Redemption) (*repository.Booking, error) {
+	ret := _m.Called(ctx, userID, rideID, redemption)
+
+	var r0 *repository.Booking
+	if rf, ok := ret.Get(0).(func(context.Context, int32, int32, *repository.Redemption) *repository.Booking); ok {
+		r0 = rf(ctx, userID, rideID, redemption)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.Booking)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32, int32, *repository.Redemption) error); ok {
+		r1 = rf(ctx, userID, rideID, redemption)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Dispute provides a mock function with given fields: ctx, id
+func (_m *BookingRepository) Dispute(ctx context.Context, id int32) (*repository.Booking, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *repository.Booking
+	if rf, ok := ret.Get(0).(func(context.Context, int32) *repository.Booking); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.Booking)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,6 +177,50 @@ func (_m *BookingRepository) GetByID(ctx context.Context, id int32) (*repository
 	return r0, r1
 }
 
+// MarkRideUpdated provides a mock function with given fields: ctx, rideID
+func (_m *BookingRepository) MarkRideUpdated(ctx context.Context, rideID int32) (int, error) {
+	ret := _m.Called(ctx, rideID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, int32) int); ok {
+		r0 = rf(ctx, rideID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, rideID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Schedule provides a mock function with given fields: ctx, userID, rideID, pickupAt, redemption
+func (_m *BookingRepository) Schedule(ctx context.Context, userID int32, rideID int32, pickupAt time.Time, redemption *repository.Redemption) (*repository.Booking, error) {
+	ret := _m.Called(ctx, userID, rideID, pickupAt, redemption)
+
+	var r0 *repository.Booking
+	if rf, ok := ret.Get(0).(func(context.Context, int32, int32, time.Time, *repository.Redemption) *repository.Booking); ok {
+		r0 = rf(ctx, userID, rideID, pickupAt, redemption)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.Booking)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int32, int32, time.Time, *repository.Redemption) error); ok {
+		r1 = rf(ctx, userID, rideID, pickupAt, redemption)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewBookingRepository creates a new instance of BookingRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 func NewBookingRepository(t mock.TestingT) *BookingRepository {
 	mock := &BookingRepository{}
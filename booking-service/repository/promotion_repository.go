@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"booking-service/promotions"
+)
+
+type PromotionRepository interface {
+	Create(ctx context.Context, promotion *promotions.Promotion) (*promotions.Promotion, error)
+	GetByCode(ctx context.Context, code string) (*promotions.Promotion, error)
+}
+
+type PostgresPromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPromotionRepository(db *sql.DB) PromotionRepository {
+	return &PostgresPromotionRepository{db: db}
+}
+
+const promotionColumns = `code, description, discount_type, percent_bps, currency, amount_off_minor, max_discount_minor,
+	starts_at, ends_at, max_redemptions, max_per_user, first_ride_only, city_place_id, redemptions, created_at`
+
+func scanPromotion(row rowScanner) (*promotions.Promotion, error) {
+	p := &promotions.Promotion{}
+	var currency string
+	var amountOff, maxDiscount int64
+	var startsAt, endsAt sql.NullTime
+	if err := row.Scan(&p.Code, &p.Description, &p.Type, &p.PercentBps, &currency, &amountOff, &maxDiscount,
+		&startsAt, &endsAt, &p.MaxRedemptions, &p.MaxPerUser, &p.FirstRideOnly, &p.CityPlaceID, &p.Redemptions,
+		&p.CreatedAt); err != nil {
+		return nil, err
+	}
+	currency = strings.TrimSpace(currency)
+	if amountOff != 0 {
+		p.AmountOff.Currency, p.AmountOff.Minor = currency, amountOff
+	}
+	if maxDiscount != 0 {
+		p.MaxDiscount.Currency, p.MaxDiscount.Minor = currency, maxDiscount
+	}
+	p.StartsAt = startsAt.Time
+	p.EndsAt = endsAt.Time
+	return p, nil
+}
+
+// nullTime stores a zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func (r *PostgresPromotionRepository) Create(ctx context.Context, promotion *promotions.Promotion) (*promotions.Promotion, error) {
+	if err := promotion.Validate(); err != nil {
+		return nil, err
+	}
+
+	// A promotion's amounts share one currency
+	currency := promotion.AmountOff.Currency
+	if currency == "" {
+		currency = promotion.MaxDiscount.Currency
+	}
+
+	query := `INSERT INTO promotions (code, description, discount_type, percent_bps, currency, amount_off_minor,
+			max_discount_minor, starts_at, ends_at, max_redemptions, max_per_user, first_ride_only, city_place_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (code) DO NOTHING
+		RETURNING ` + promotionColumns
+	created, err := scanPromotion(r.db.QueryRowContext(ctx, query, promotion.Code, promotion.Description, promotion.Type,
+		promotion.PercentBps, currency, promotion.AmountOff.Minor, promotion.MaxDiscount.Minor,
+		nullTime(promotion.StartsAt), nullTime(promotion.EndsAt), promotion.MaxRedemptions, promotion.MaxPerUser,
+		promotion.FirstRideOnly, promotion.CityPlaceID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("promotion already exists")
+		}
+		log.Printf("Create promotion failed: %v", err)
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *PostgresPromotionRepository) GetByCode(ctx context.Context, code string) (*promotions.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE code = $1`
+	promotion, err := scanPromotion(r.db.QueryRowContext(ctx, query, promotions.Normalize(code)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("promotion not found")
+		}
+		log.Printf("Get promotion failed: %v", err)
+		return nil, err
+	}
+	return promotion, nil
+}
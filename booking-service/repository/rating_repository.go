@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"booking-service/ratings"
+)
+
+type RatingRepository interface {
+	// Rate records a rating of a completed booking by its rider and adds it to
+	// the statistics of the booking's driver and route. The driver is taken
+	// from the booking.
+	Rate(ctx context.Context, rating *ratings.Rating) (*ratings.Rating, error)
+	// GetStats returns a subject's statistics, which are empty if it has no
+	// ratings.
+	GetStats(ctx context.Context, subject string) (*ratings.Stats, error)
+}
+
+type PostgresRatingRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRatingRepository(db *sql.DB) RatingRepository {
+	return &PostgresRatingRepository{db: db}
+}
+
+func (r *PostgresRatingRepository) Rate(ctx context.Context, rating *ratings.Rating) (*ratings.Rating, error) {
+	if err := rating.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Rate booking failed: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the booking so it cannot change status while it is rated
+	var userID, driverID int32
+	var status string
+	query := `SELECT user_id, status, COALESCE(driver_id, 0) FROM bookings WHERE booking_id = $1 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, rating.BookingID).Scan(&userID, &status, &driverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("booking not found")
+		}
+		log.Printf("Rate booking failed: %v", err)
+		return nil, err
+	}
+	if userID != rating.UserID {
+		return nil, fmt.Errorf("booking belongs to another user")
+	}
+	if status != StatusCompleted {
+		return nil, fmt.Errorf("booking is not completed")
+	}
+
+	rated := *rating
+	rated.DriverID = driverID
+	query = `INSERT INTO ratings (booking_id, user_id, driver_id, source_place_id, destination_place_id, stars, comment)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, $7)
+		ON CONFLICT (booking_id) DO NOTHING
+		RETURNING created_at`
+	err = tx.QueryRowContext(ctx, query, rated.BookingID, rated.UserID, rated.DriverID, rated.Route.SourcePlaceID,
+		rated.Route.DestinationPlaceID, rated.Stars, rated.Comment).Scan(&rated.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("booking already rated")
+		}
+		log.Printf("Rate booking failed: %v", err)
+		return nil, err
+	}
+
+	for _, tag := range rated.Tags {
+		query = `INSERT INTO rating_tags (booking_id, tag) VALUES ($1, $2)`
+		if _, err := tx.ExecContext(ctx, query, rated.BookingID, tag); err != nil {
+			log.Printf("Rate booking failed: %v", err)
+			return nil, err
+		}
+	}
+
+	// stars_<n> is picked from the validated star count
+	starsColumn := fmt.Sprintf("stars_%d", rated.Stars)
+	for _, subject := range rated.Subjects() {
+		query = `INSERT INTO rating_stats (subject, ratings_count, ` + starsColumn + `) VALUES ($1, 1, 1)
+			ON CONFLICT (subject) DO UPDATE SET
+				ratings_count = rating_stats.ratings_count + 1,
+				` + starsColumn + ` = rating_stats.` + starsColumn + ` + 1,
+				updated_at = now()`
+		if _, err := tx.ExecContext(ctx, query, subject); err != nil {
+			log.Printf("Rate booking failed: %v", err)
+			return nil, err
+		}
+
+		for _, tag := range rated.Tags {
+			query = `INSERT INTO rating_tag_stats (subject, tag, ratings_count) VALUES ($1, $2, 1)
+				ON CONFLICT (subject, tag) DO UPDATE SET ratings_count = rating_tag_stats.ratings_count + 1`
+			if _, err := tx.ExecContext(ctx, query, subject, tag); err != nil {
+				log.Printf("Rate booking failed: %v", err)
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Rate booking failed: %v", err)
+		return nil, err
+	}
+
+	return &rated, nil
+}
+
+func (r *PostgresRatingRepository) GetStats(ctx context.Context, subject string) (*ratings.Stats, error) {
+	stats := &ratings.Stats{Tags: map[string]int64{}}
+	query := `SELECT ratings_count, stars_1, stars_2, stars_3, stars_4, stars_5 FROM rating_stats WHERE subject = $1`
+	err := r.db.QueryRowContext(ctx, query, subject).Scan(&stats.Count,
+		&stats.Stars[0], &stats.Stars[1], &stats.Stars[2], &stats.Stars[3], &stats.Stars[4])
+	if err == sql.ErrNoRows {
+		return stats, nil
+	}
+	if err != nil {
+		log.Printf("Get rating stats failed: %v", err)
+		return nil, err
+	}
+
+	query = `SELECT tag, ratings_count FROM rating_tag_stats WHERE subject = $1`
+	rows, err := r.db.QueryContext(ctx, query, subject)
+	if err != nil {
+		log.Printf("Get rating stats failed: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag string
+		var count int64
+		if err := rows.Scan(&tag, &count); err != nil {
+			log.Printf("Get rating stats failed: %v", err)
+			return nil, err
+		}
+		stats.Tags[tag] = count
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Get rating stats failed: %v", err)
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"booking-service/receipts"
+)
+
+type ReceiptRepository interface {
+	// Issue assigns the receipt the next invoice number of the month of its
+	// IssuedAt and stores it. If the booking already has a receipt, that
+	// receipt is returned instead.
+	Issue(ctx context.Context, receipt *receipts.Receipt) (*receipts.Receipt, error)
+	GetByBookingID(ctx context.Context, bookingID int32) (*receipts.Receipt, error)
+}
+
+type PostgresReceiptRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresReceiptRepository(db *sql.DB) ReceiptRepository {
+	return &PostgresReceiptRepository{db: db}
+}
+
+func scanReceipt(row rowScanner) (*receipts.Receipt, error) {
+	var data []byte
+	if err := row.Scan(&data); err != nil {
+		return nil, err
+	}
+	receipt := &receipts.Receipt{}
+	if err := json.Unmarshal(data, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+func (r *PostgresReceiptRepository) Issue(ctx context.Context, receipt *receipts.Receipt) (*receipts.Receipt, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Issue receipt failed: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the booking so concurrent calls issue one receipt between them
+	query := `SELECT booking_id FROM bookings WHERE booking_id = $1 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, receipt.BookingID).Scan(new(int32)); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("booking not found")
+		}
+		log.Printf("Issue receipt failed: %v", err)
+		return nil, err
+	}
+
+	query = `SELECT data FROM receipts WHERE booking_id = $1`
+	existing, err := scanReceipt(tx.QueryRowContext(ctx, query, receipt.BookingID))
+	if err == nil {
+		return existing, nil
+	}
+	if err != sql.ErrNoRows {
+		log.Printf("Issue receipt failed: %v", err)
+		return nil, err
+	}
+
+	var seq int64
+	period := receipts.Period(receipt.IssuedAt)
+	query = `INSERT INTO invoice_sequences (period, last_number) VALUES ($1, 1)
+		ON CONFLICT (period) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number`
+	if err := tx.QueryRowContext(ctx, query, period).Scan(&seq); err != nil {
+		log.Printf("Issue receipt failed: %v", err)
+		return nil, err
+	}
+
+	issued := *receipt
+	issued.InvoiceNumber = receipts.InvoiceNumber(issued.IssuedAt, seq)
+	data, err := json.Marshal(issued)
+	if err != nil {
+		return nil, err
+	}
+	query = `INSERT INTO receipts (booking_id, invoice_number, issued_at, data) VALUES ($1, $2, $3, $4)`
+	if _, err := tx.ExecContext(ctx, query, issued.BookingID, issued.InvoiceNumber, issued.IssuedAt, data); err != nil {
+		log.Printf("Issue receipt failed: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Issue receipt failed: %v", err)
+		return nil, err
+	}
+
+	return &issued, nil
+}
+
+func (r *PostgresReceiptRepository) GetByBookingID(ctx context.Context, bookingID int32) (*receipts.Receipt, error) {
+	query := `SELECT data FROM receipts WHERE booking_id = $1`
+	receipt, err := scanReceipt(r.db.QueryRowContext(ctx, query, bookingID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("receipt not found")
+		}
+		log.Printf("Get receipt failed: %v", err)
+		return nil, err
+	}
+	return receipt, nil
+}
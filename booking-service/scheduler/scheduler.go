@@ -0,0 +1,81 @@
+// Package scheduler activates scheduled bookings shortly before pickup.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"booking-service/repository"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+)
+
+// batchSize caps how many bookings one repository call activates.
+const batchSize = 100
+
+// Activator prepares a booking for pickup once it has been activated, e.g. by
+// assigning a driver.
+type Activator interface {
+	ActivateBooking(ctx context.Context, booking *repository.Booking)
+}
+
+// Scheduler confirms SCHEDULED bookings once their pickup is within lead and
+// hands them to an Activator. Activation is a single status change in the
+// repository, so a booking is never activated twice, even across restarts or
+// with several booking-service instances polling.
+type Scheduler struct {
+	repo      repository.BookingRepository
+	activator Activator
+	lead      time.Duration
+	logger    *logger.Logger
+}
+
+func New(repo repository.BookingRepository, activator Activator, lead time.Duration, log *logger.Logger) *Scheduler {
+	return &Scheduler{
+		repo:      repo,
+		activator: activator,
+		lead:      lead,
+		logger:    log,
+	}
+}
+
+// ActivateDue activates every booking with pickup within lead of now and
+// returns how many were activated.
+func (s *Scheduler) ActivateDue(ctx context.Context, now time.Time) (int, error) {
+	activated := 0
+	for {
+		bookings, err := s.repo.ActivateDue(ctx, now.Add(s.lead), batchSize)
+		if err != nil {
+			return activated, err
+		}
+
+		for _, booking := range bookings {
+			s.logger.Info("activated scheduled booking", "booking_id", booking.ID, "pickup_at", booking.PickupAt)
+			s.activator.ActivateBooking(ctx, booking)
+		}
+		activated += len(bookings)
+
+		if len(bookings) < batchSize {
+			return activated, nil
+		}
+	}
+}
+
+// Run activates due bookings every interval until ctx is done. Bookings that
+// fell due while the service was down are activated on the first tick.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ActivateDue(ctx, time.Now()); err != nil {
+			s.logger.Error("failed to activate scheduled bookings", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
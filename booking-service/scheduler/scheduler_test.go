@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"booking-service/repository"
+	"booking-service/repository/mocks"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+)
+
+type recordingActivator struct {
+	activated []int32
+}
+
+func (a *recordingActivator) ActivateBooking(ctx context.Context, booking *repository.Booking) {
+	a.activated = append(a.activated, booking.ID)
+}
+
+func bookings(from, n int32) []*repository.Booking {
+	var res []*repository.Booking
+	for id := from; id < from+n; id++ {
+		res = append(res, &repository.Booking{ID: id, Status: repository.StatusConfirmed})
+	}
+	return res
+}
+
+func TestScheduler_ActivateDue(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	activator := &recordingActivator{}
+	s := New(mockRepo, activator, 10*time.Minute, logger.NewLogger("booking-service"))
+
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Expectations: bookings with pickup up to the lead from now are due, and
+	// full batches are followed by another
+	dueBy := now.Add(10 * time.Minute)
+	mockRepo.On("ActivateDue", ctx, dueBy, batchSize).Return(bookings(1, batchSize), nil).Once()
+	mockRepo.On("ActivateDue", ctx, dueBy, batchSize).Return(bookings(101, 2), nil).Once()
+
+	// Action
+	activated, err := s.ActivateDue(ctx, now)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 102, activated)
+	assert.Len(t, activator.activated, 102)
+	assert.Equal(t, int32(102), activator.activated[101])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestScheduler_ActivateDue_RepositoryError(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	activator := &recordingActivator{}
+	s := New(mockRepo, activator, 10*time.Minute, logger.NewLogger("booking-service"))
+
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// Expectations
+	mockRepo.On("ActivateDue", ctx, now.Add(10*time.Minute), batchSize).Return(nil, errors.New("database error"))
+
+	// Action
+	activated, err := s.ActivateDue(ctx, now)
+
+	// Assertions
+	assert.Error(t, err)
+	assert.Zero(t, activated)
+	assert.Empty(t, activator.activated)
+}
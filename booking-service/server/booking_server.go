@@ -1,139 +1,957 @@
 package server
 
 import (
+	"booking-service/events"
 	pb "booking-service/pb/proto/booking"
+	"booking-service/promotions"
+	"booking-service/ratings"
+	"booking-service/receipts"
 	"booking-service/repository"
 	"context"
 	"fmt"
+	"time"
 
+	driverpb "driver-service/pb/proto/driver"
+	paymentpb "payment-service/pb/proto/payment"
 	ridepb "ride-service/pb/proto/ride"
 	userpb "user-service/pb/proto/user"
 
+	"github.com/hasnain-zafar/go-microservices/common/audit"
 	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	auditpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
 
 	"github.com/hasnain-zafar/go-microservices/common/errors"
 	"github.com/hasnain-zafar/go-microservices/common/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Scheduled bookings must be made at least defaultMinLead and at most
+// defaultMaxLead before pickup unless configured with WithScheduleWindow.
+const (
+	defaultMinLead = 15 * time.Minute
+	defaultMaxLead = 7 * 24 * time.Hour
 )
 
 type BookingServer struct {
 	pb.UnimplementedBookingServiceServer
-	repo         repository.BookingRepository
-	userClient   userpb.UserServiceClient
-	rideClient   ridepb.RideServiceClient
-	logger       *logger.Logger
-	errorHandler *errors.ErrorHandler
-	serviceName  string
+	repo          repository.BookingRepository
+	userClient    userpb.UserServiceClient
+	rideClient    ridepb.RideServiceClient
+	driverClient  driverpb.DriverServiceClient
+	paymentClient paymentpb.PaymentServiceClient
+	logger        *logger.Logger
+	errorHandler  *errors.ErrorHandler
+	serviceName   string
+	feed          *events.Feed
+	promotions    repository.PromotionRepository
+	ratings       repository.RatingRepository
+	receipts      repository.ReceiptRepository
+	taxRateBps    int64
+	minLead       time.Duration
+	maxLead       time.Duration
+	auditLog      audit.Log
+}
+
+// Option configures optional BookingServer dependencies.
+type Option func(*BookingServer)
+
+// WithMetrics counts the server's errors in reg rather than in a registry of
+// its own.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(s *BookingServer) {
+		s.logger = s.logger.WithMetrics(reg)
+		s.errorHandler = errors.NewErrorHandler(s.logger)
+	}
+}
+
+// WithScheduleWindow sets how far ahead of pickup bookings may be scheduled.
+func WithScheduleWindow(minLead, maxLead time.Duration) Option {
+	return func(s *BookingServer) {
+		s.minLead = minLead
+		s.maxLead = maxLead
+	}
+}
+
+// WithFeed sets the booking event feed that drives WatchBooking streams.
+// Without it, WatchBooking only sends the current state.
+func WithFeed(feed *events.Feed) Option {
+	return func(s *BookingServer) {
+		s.feed = feed
+	}
+}
+
+// WithPromotions sets the promotion repository, enabling promo codes.
+// Without it, bookings with a promo code are rejected.
+func WithPromotions(repo repository.PromotionRepository) Option {
+	return func(s *BookingServer) {
+		s.promotions = repo
+	}
+}
+
+// WithRatings sets the rating repository, enabling RateBooking and
+// GetRatingStats.
+func WithRatings(repo repository.RatingRepository) Option {
+	return func(s *BookingServer) {
+		s.ratings = repo
+	}
+}
+
+// WithReceipts sets the receipt repository and the tax rate included in fares,
+// in basis points. Receipts are issued when bookings complete.
+func WithReceipts(repo repository.ReceiptRepository, taxRateBps int64) Option {
+	return func(s *BookingServer) {
+		s.receipts = repo
+		s.taxRateBps = taxRateBps
+	}
+}
+
+// WithAuditLog enables QueryAuditLog for callers authenticated as
+// audit.Admin.
+func WithAuditLog(log audit.Log) Option {
+	return func(s *BookingServer) {
+		s.auditLog = log
+	}
 }
 
 func NewBookingServer(
 	repo repository.BookingRepository,
 	userClient userpb.UserServiceClient,
 	rideClient ridepb.RideServiceClient,
+	driverClient driverpb.DriverServiceClient,
+	paymentClient paymentpb.PaymentServiceClient,
+	opts ...Option,
 ) *BookingServer {
 	serviceName := "booking-service"
 	log := logger.NewLogger(serviceName)
-	return &BookingServer{
-		repo:         repo,
-		userClient:   userClient,
-		rideClient:   rideClient,
-		logger:       log,
-		errorHandler: errors.NewErrorHandler(log),
-		serviceName:  serviceName,
+	s := &BookingServer{
+		repo:          repo,
+		userClient:    userClient,
+		rideClient:    rideClient,
+		driverClient:  driverClient,
+		paymentClient: paymentClient,
+		logger:        log,
+		errorHandler:  errors.NewErrorHandler(log),
+		serviceName:   serviceName,
+		minLead:       defaultMinLead,
+		maxLead:       defaultMaxLead,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.feed == nil {
+		s.feed = events.NewFeed(s.logger)
 	}
+	return s
 }
 
 func (s *BookingServer) CreateBooking(ctx context.Context, req *pb.CreateBookingRequest) (*pb.Booking, error) {
 	method := "CreateBooking"
 	metrics.IncrementRequestCounter(s.serviceName, method)
-	s.logger.LogRequest(method, req)
+	s.logger.LogRequest(ctx, method, req)
 
 	if err := validateCreateBookingRequest(req); err != nil {
 		return nil, s.errorHandler.HandleInvalidArgument("invalid booking request", err)
 	}
+	now := time.Now()
+	if err := s.validatePickupTime(req.PickupTime, now); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid pickup time", err)
+	}
+	fare, err := money.FromProto(quotePrice(req.Quote))
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid fare quote", err)
+	}
 
-	_, err := s.userClient.GetUser(ctx, &userpb.GetUserRequest{UserId: req.UserId})
+	// Reject a code that cannot be redeemed before creating the ride; the
+	// rules that depend on the user and ride are checked with the booking
+	if req.PromoCode != "" {
+		if err := s.checkPromotion(ctx, req.PromoCode, now); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = s.userClient.GetUser(ctx, &userpb.GetUserRequest{UserId: req.UserId})
 	if err != nil {
-		s.logger.Error("failed to get user", "error", err, "user_id", req.UserId)
-		logger.IncrementNetworkErrorCount()
+		s.logger.ErrorContext(ctx, "failed to get user", "error", err, "user_id", req.UserId)
 		return nil, s.errorHandler.HandleNetworkError("failed to verify user", err)
 	}
 
+	// ride-service verifies the quote's signature and takes the fare from it
 	rideReq := &ridepb.CreateRideRequest{
 		Source:      req.Ride.Source,
 		Destination: req.Ride.Destination,
 		Distance:    req.Ride.Distance,
-		Cost:        req.Ride.Cost,
+		Quote:       toRideQuote(req.Quote),
+
+		SourceLocation:      toRideLatLng(req.Ride.SourceLocation),
+		DestinationLocation: toRideLatLng(req.Ride.DestinationLocation),
 	}
 
 	rideRes, err := s.rideClient.CreateRide(ctx, rideReq)
 	if err != nil {
-		s.logger.Error("failed to create ride", "error", err)
-		logger.IncrementNetworkErrorCount()
+		s.logger.ErrorContext(ctx, "failed to create ride", "error", err)
 		return nil, s.errorHandler.HandleNetworkError("failed to create ride", err)
 	}
 
-	booking, err := s.repo.Create(ctx, req.UserId, rideRes.RideId)
+	var redemption *repository.Redemption
+	if req.PromoCode != "" {
+		redemption = &repository.Redemption{
+			Code:          req.PromoCode,
+			Fare:          fare,
+			PickupPlaceID: rideRes.SourcePlaceId,
+			Now:           now,
+		}
+	}
+
+	var booking *repository.Booking
+	if req.PickupTime != nil {
+		// A driver is assigned when the scheduler activates the booking
+		booking, err = s.repo.Schedule(ctx, req.UserId, rideRes.RideId, req.PickupTime.AsTime(), redemption)
+	} else {
+		booking, err = s.repo.Create(ctx, req.UserId, rideRes.RideId, redemption)
+	}
 	if err != nil {
+		switch {
+		case err.Error() == "promotion not found":
+			return nil, s.errorHandler.HandleNotFound("promo code not found", err)
+		case promotions.IsIneligible(err):
+			return nil, s.errorHandler.HandleFailedPrecondition("promo code cannot be redeemed", err)
+		}
 		return nil, s.errorHandler.HandleDatabaseError("failed to create booking", err)
 	}
 
+	// Hold the discounted fare on the user's card; the booking is cancelled
+	// if it cannot be paid for
+	if err := s.authorizePayment(ctx, booking, fare); err != nil {
+		return nil, err
+	}
+
+	// The booking is confirmed even if no driver can be assigned yet
+	if booking.Status == repository.StatusConfirmed && rideRes.SourceLocation != nil {
+		booking = s.assignDriver(ctx, booking, rideRes.SourceLocation, req.Quote.VehicleClass)
+	}
+
 	res := &pb.Booking{
+		BookingId:  booking.ID,
+		UserId:     booking.UserID,
+		RideId:     booking.RideID,
+		Status:     booking.Status,
+		DriverId:   booking.DriverID,
+		PickupTime: pickupTimestamp(booking),
+		CreatedAt:  timestamppb.New(booking.CreatedAt),
+		UpdatedAt:  timestamppb.New(booking.UpdatedAt),
+		PromoCode:  booking.PromoCode,
+		Discount:   bookingDiscount(booking),
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// ActivateBooking assigns a driver to a scheduled booking the scheduler has
+// just confirmed. Like an immediate booking, it stays confirmed without a
+// driver if none can be assigned.
+func (s *BookingServer) ActivateBooking(ctx context.Context, booking *repository.Booking) {
+	ride, err := s.rideClient.GetRide(ctx, &ridepb.GetRideRequest{RideId: booking.RideID})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get ride for activated booking", "error", err, "booking_id", booking.ID)
+		return
+	}
+	if ride.SourceLocation != nil {
+		s.assignDriver(ctx, booking, ride.SourceLocation, ride.VehicleClass)
+	}
+}
+
+// assignDriver asks driver-service for the nearest available driver to pickup
+// and records the assignment on the booking. Failures are logged and the
+// booking is returned without a driver.
+func (s *BookingServer) assignDriver(ctx context.Context, booking *repository.Booking, pickup *ridepb.LatLng, vehicleClass string) *repository.Booking {
+	assignment, err := s.driverClient.AssignDriver(ctx, &driverpb.AssignDriverRequest{
+		BookingId:    booking.ID,
+		Pickup:       &driverpb.LatLng{Lat: pickup.Lat, Lng: pickup.Lng},
+		VehicleClass: vehicleClass,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to assign driver", "error", err, "booking_id", booking.ID)
+		return booking
+	}
+
+	assigned, err := s.repo.AssignDriver(ctx, booking.ID, assignment.Driver.GetDriverId())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to record driver assignment", "error", err, "booking_id", booking.ID)
+		s.releaseDriver(ctx, booking.ID)
+		return booking
+	}
+	return assigned
+}
+
+// checkPromotion returns the error to reject a booking with if code is not a
+// promotion anyone could redeem at now.
+func (s *BookingServer) checkPromotion(ctx context.Context, code string, now time.Time) error {
+	if s.promotions == nil {
+		return s.errorHandler.HandleFailedPrecondition("promo codes are not enabled", fmt.Errorf("no promotion repository"))
+	}
+	promotion, err := s.promotions.GetByCode(ctx, code)
+	if err != nil {
+		if err.Error() == "promotion not found" {
+			return s.errorHandler.HandleNotFound("promo code not found", err)
+		}
+		return s.errorHandler.HandleDatabaseError("failed to get promotion", err)
+	}
+	if err := promotion.CheckActive(now); err != nil {
+		return s.errorHandler.HandleFailedPrecondition("promo code cannot be redeemed", err)
+	}
+	return nil
+}
+
+// authorizePayment asks payment-service to authorize the fare of a new
+// booking less its discount. If that fails the booking is cancelled and any
+// authorization that may have been made is voided. A booking discounted to
+// nothing still gets a payment, which holds nothing on the card, so that
+// completing it credits the driver with their share of the discount.
+func (s *BookingServer) authorizePayment(ctx context.Context, booking *repository.Booking, fare money.Money) error {
+	total, err := bookingTotal(booking, fare)
+	if err != nil {
+		return s.errorHandler.HandleInternalError("failed to apply discount", err)
+	}
+
+	_, err = s.paymentClient.AuthorizePayment(ctx, &paymentpb.AuthorizePaymentRequest{
 		BookingId: booking.ID,
 		UserId:    booking.UserID,
-		RideId:    booking.RideID,
-		Time:      booking.Time,
+		Amount:    money.ToProto(total),
+		Discount:  bookingDiscount(booking),
+	})
+	if err == nil {
+		return nil
 	}
 
-	s.logger.LogResponse(method, res)
+	s.logger.ErrorContext(ctx, "failed to authorize payment", "error", err, "booking_id", booking.ID)
+	if _, cancelErr := s.repo.Cancel(ctx, booking.ID); cancelErr != nil {
+		s.logger.ErrorContext(ctx, "failed to cancel unpaid booking", "error", cancelErr, "booking_id", booking.ID)
+	}
+	s.voidPayment(ctx, booking.ID)
 
-	return res, nil
+	if status.Code(err) == codes.FailedPrecondition {
+		return s.errorHandler.HandleFailedPrecondition("payment declined", err)
+	}
+	return s.errorHandler.HandleNetworkError("failed to authorize payment", err)
+}
+
+// voidPayment releases a booking's payment authorization. It is best effort:
+// failures are logged only.
+func (s *BookingServer) voidPayment(ctx context.Context, bookingID int32) {
+	_, err := s.paymentClient.VoidPayment(ctx, &paymentpb.VoidPaymentRequest{BookingId: bookingID})
+	if err != nil && status.Code(err) != codes.NotFound {
+		s.logger.ErrorContext(ctx, "failed to void payment", "error", err, "booking_id", bookingID)
+	}
+}
+
+// releaseDriver frees the driver assigned to a booking. It is best effort:
+// failures are logged only.
+func (s *BookingServer) releaseDriver(ctx context.Context, bookingID int32) {
+	_, err := s.driverClient.ReleaseDriver(ctx, &driverpb.ReleaseDriverRequest{BookingId: bookingID})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to release driver", "error", err, "booking_id", bookingID)
+	}
 }
 
 func (s *BookingServer) GetBooking(ctx context.Context, req *pb.GetBookingRequest) (*pb.BookingDetails, error) {
 	method := "GetBooking"
 	metrics.IncrementRequestCounter(s.serviceName, method)
-	s.logger.LogRequest(method, req)
+	s.logger.LogRequest(ctx, method, req)
 
 	if req.GetBookingId() <= 0 {
 		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
 	}
 
-	booking, err := s.repo.GetByID(ctx, req.BookingId)
+	booking, err := s.getBooking(ctx, req.BookingId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.bookingDetails(ctx, booking)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// WatchBooking streams the booking's details whenever its status or ride
+// changes. The current state is sent first if it is newer than
+// req.FromVersion, so a reconnecting client resumes without missing changes;
+// intermediate versions are coalesced into the latest state.
+func (s *BookingServer) WatchBooking(req *pb.WatchBookingRequest, stream pb.BookingService_WatchBookingServer) error {
+	method := "WatchBooking"
+	ctx := stream.Context()
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetBookingId() <= 0 {
+		return s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+	if req.GetFromVersion() < 0 {
+		return s.errorHandler.HandleInvalidArgument("invalid version", fmt.Errorf("from version cannot be negative"))
+	}
+
+	// Listen before reading the current state so no change is missed in between
+	changes, stop := s.feed.Listen(req.BookingId)
+	defer stop()
+
+	lastVersion := req.FromVersion
+	for {
+		booking, err := s.getBooking(ctx, req.BookingId)
+		if err != nil {
+			return err
+		}
+
+		if booking.Version > lastVersion {
+			details, err := s.bookingDetails(ctx, booking)
+			if err != nil {
+				return err
+			}
+
+			update := &pb.BookingUpdate{Version: booking.Version, Booking: details}
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+			s.logger.LogResponse(ctx, method, update)
+			lastVersion = booking.Version
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changes:
+		}
+	}
+}
+
+func (s *BookingServer) getBooking(ctx context.Context, id int32) (*repository.Booking, error) {
+	booking, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		if err.Error() == "booking not found" {
 			return nil, s.errorHandler.HandleNotFound("booking not found", err)
 		}
 		return nil, s.errorHandler.HandleDatabaseError("failed to get booking", err)
 	}
+	return booking, nil
+}
 
+// bookingDetails joins a booking with its user and ride.
+func (s *BookingServer) bookingDetails(ctx context.Context, booking *repository.Booking) (*pb.BookingDetails, error) {
 	userRes, err := s.userClient.GetUser(ctx, &userpb.GetUserRequest{UserId: booking.UserID})
 	if err != nil {
-		s.logger.Error("failed to get user details", "error", err, "user_id", booking.UserID)
-		logger.IncrementNetworkErrorCount()
+		s.logger.ErrorContext(ctx, "failed to get user details", "error", err, "user_id", booking.UserID)
 		return nil, s.errorHandler.HandleNetworkError("failed to get user details", err)
 	}
 
 	rideRes, err := s.rideClient.GetRide(ctx, &ridepb.GetRideRequest{RideId: booking.RideID})
 	if err != nil {
-		s.logger.Error("failed to get ride details", "error", err, "ride_id", booking.RideID)
-		logger.IncrementNetworkErrorCount()
+		s.logger.ErrorContext(ctx, "failed to get ride details", "error", err, "ride_id", booking.RideID)
 		return nil, s.errorHandler.HandleNetworkError("failed to get ride details", err)
 	}
 
-	res := &pb.BookingDetails{
+	price := ridePrice(rideRes)
+	fare, err := money.FromProto(price)
+	if err != nil {
+		return nil, s.errorHandler.HandleInternalError("invalid ride price", err)
+	}
+	total, err := bookingTotal(booking, fare)
+	if err != nil {
+		return nil, s.errorHandler.HandleInternalError("failed to apply discount", err)
+	}
+
+	return &pb.BookingDetails{
 		Name:        userRes.Name,
 		Source:      rideRes.Source,
 		Destination: rideRes.Destination,
 		Distance:    rideRes.Distance,
 		Cost:        rideRes.Cost,
-		Time:        booking.Time,
+		Price:       price,
+		Status:      booking.Status,
+		DriverId:    booking.DriverID,
+		PickupTime:  pickupTimestamp(booking),
+		CreatedAt:   timestamppb.New(booking.CreatedAt),
+		UpdatedAt:   timestamppb.New(booking.UpdatedAt),
+		PromoCode:   booking.PromoCode,
+		Discount:    bookingDiscount(booking),
+		Total:       money.ToProto(total),
+
+		FareBreakdown: fromRideBreakdown(rideRes.FareBreakdown),
+	}, nil
+}
+
+func (s *BookingServer) CancelBooking(ctx context.Context, req *pb.CancelBookingRequest) (*pb.CancelBookingResponse, error) {
+	method := "CancelBooking"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+
+	booking, err := s.repo.Cancel(ctx, req.BookingId)
+	if err != nil {
+		switch err.Error() {
+		case "booking not found":
+			return nil, s.errorHandler.HandleNotFound("booking not found", err)
+		case "booking already cancelled", "booking already completed":
+			return nil, s.errorHandler.HandleFailedPrecondition("booking cannot be cancelled", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to cancel booking", err)
+	}
+
+	if booking.DriverID != 0 {
+		s.releaseDriver(ctx, booking.ID)
+	}
+	s.voidPayment(ctx, booking.ID)
+
+	res := &pb.CancelBookingResponse{
+		Message: fmt.Sprintf("Booking %d cancelled successfully", req.BookingId),
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// CompleteBooking marks a confirmed booking completed once the ride is over
+// and captures its payment. Completing a completed booking retries the
+// capture, so a failed capture can be retried.
+func (s *BookingServer) CompleteBooking(ctx context.Context, req *pb.CompleteBookingRequest) (*pb.CompleteBookingResponse, error) {
+	method := "CompleteBooking"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+
+	booking, err := s.repo.Complete(ctx, req.BookingId)
+	if err != nil {
+		switch err.Error() {
+		case "booking not found":
+			return nil, s.errorHandler.HandleNotFound("booking not found", err)
+		case "booking is not confirmed":
+			return nil, s.errorHandler.HandleFailedPrecondition("booking cannot be completed", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to complete booking", err)
+	}
+
+	res := &pb.CompleteBookingResponse{
+		Message: fmt.Sprintf("Booking %d completed successfully", req.BookingId),
+	}
+
+	payment, err := s.paymentClient.CapturePayment(ctx, &paymentpb.CapturePaymentRequest{
+		BookingId: booking.ID,
+		DriverId:  booking.DriverID,
+	})
+	// The driver is released only once paid, since payment-service credits
+	// only the driver assigned to the booking
+	if booking.DriverID != 0 {
+		s.releaseDriver(ctx, booking.ID)
+	}
+	switch {
+	case status.Code(err) == codes.NotFound:
+		// Bookings made before payments were introduced have nothing to capture
+		s.logger.InfoContext(ctx, "booking has no payment to capture", "booking_id", booking.ID)
+	case err != nil:
+		s.logger.ErrorContext(ctx, "failed to capture payment", "error", err, "booking_id", booking.ID)
+		return nil, s.paymentError("failed to capture payment", err)
+	default:
+		res.Charged = payment.Captured
+	}
+
+	// A receipt that cannot be issued now is issued by GetReceipt
+	if s.receipts != nil {
+		if _, err := s.issueReceipt(ctx, booking); err != nil {
+			s.logger.ErrorContext(ctx, "failed to issue receipt", "error", err, "booking_id", booking.ID)
+		}
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// DisputeBooking refunds a completed booking in full after a dispute.
+// Disputing a disputed booking retries the refund.
+func (s *BookingServer) DisputeBooking(ctx context.Context, req *pb.DisputeBookingRequest) (*pb.DisputeBookingResponse, error) {
+	method := "DisputeBooking"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+	if req.GetReason() == "" {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid dispute", fmt.Errorf("reason cannot be empty"))
+	}
+
+	booking, err := s.repo.Dispute(ctx, req.BookingId)
+	if err != nil {
+		switch err.Error() {
+		case "booking not found":
+			return nil, s.errorHandler.HandleNotFound("booking not found", err)
+		case "booking is not completed":
+			return nil, s.errorHandler.HandleFailedPrecondition("booking cannot be disputed", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to dispute booking", err)
+	}
+
+	payment, err := s.paymentClient.RefundPayment(ctx, &paymentpb.RefundPaymentRequest{
+		BookingId: booking.ID,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to refund payment", "error", err, "booking_id", booking.ID)
+		return nil, s.paymentError("failed to refund payment", err)
+	}
+
+	res := &pb.DisputeBookingResponse{
+		Message:  fmt.Sprintf("Booking %d refunded", req.BookingId),
+		Refunded: payment.Refunded,
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// CreatePromotion adds a promo code that bookings can be made with.
+func (s *BookingServer) CreatePromotion(ctx context.Context, req *pb.CreatePromotionRequest) (*pb.Promotion, error) {
+	method := "CreatePromotion"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.promotions == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("promo codes are not enabled", fmt.Errorf("no promotion repository"))
+	}
+	if req.Promotion == nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid promotion", fmt.Errorf("promotion is required"))
+	}
+	promotion, err := promotionFromProto(req.Promotion)
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid promotion", err)
+	}
+	if err := promotion.Validate(); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid promotion", err)
 	}
 
-	s.logger.LogResponse(method, res)
+	created, err := s.promotions.Create(ctx, promotion)
+	if err != nil {
+		if err.Error() == "promotion already exists" {
+			return nil, s.errorHandler.HandleFailedPrecondition("promotion already exists", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to create promotion", err)
+	}
+
+	res := promotionToProto(created)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *BookingServer) GetPromotion(ctx context.Context, req *pb.GetPromotionRequest) (*pb.Promotion, error) {
+	method := "GetPromotion"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.promotions == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("promo codes are not enabled", fmt.Errorf("no promotion repository"))
+	}
+	if promotions.Normalize(req.GetCode()) == "" {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid promo code", fmt.Errorf("code cannot be empty"))
+	}
+
+	promotion, err := s.promotions.GetByCode(ctx, req.Code)
+	if err != nil {
+		if err.Error() == "promotion not found" {
+			return nil, s.errorHandler.HandleNotFound("promotion not found", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to get promotion", err)
+	}
+
+	res := promotionToProto(promotion)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// RateBooking records the rider's rating of a completed booking and adds it
+// to the statistics of its driver and route.
+func (s *BookingServer) RateBooking(ctx context.Context, req *pb.RateBookingRequest) (*pb.Rating, error) {
+	method := "RateBooking"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.ratings == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("ratings are not enabled", fmt.Errorf("no rating repository"))
+	}
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+	if req.GetUserId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid user ID", fmt.Errorf("user ID must be positive"))
+	}
+	rating := &ratings.Rating{
+		BookingID: req.BookingId,
+		UserID:    req.UserId,
+		Stars:     req.Stars,
+		Comment:   req.Comment,
+		Tags:      req.Tags,
+	}
+	rating.Normalize()
+	if err := rating.Validate(); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid rating", err)
+	}
+
+	// Reject what can be rejected before asking ride-service for the route;
+	// the repository checks again as it records the rating
+	booking, err := s.getBooking(ctx, req.BookingId)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != req.UserId {
+		return nil, s.errorHandler.HandlePermissionDenied("booking cannot be rated", fmt.Errorf("booking belongs to another user"))
+	}
+	if booking.Status != repository.StatusCompleted {
+		return nil, s.errorHandler.HandleFailedPrecondition("booking cannot be rated", fmt.Errorf("booking is not completed"))
+	}
+
+	ride, err := s.rideClient.GetRide(ctx, &ridepb.GetRideRequest{RideId: booking.RideID})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get ride for rating", "error", err, "ride_id", booking.RideID)
+		return nil, s.errorHandler.HandleNetworkError("failed to get ride details", err)
+	}
+	rating.Route = ratings.Route{SourcePlaceID: ride.SourcePlaceId, DestinationPlaceID: ride.DestinationPlaceId}
+
+	rated, err := s.ratings.Rate(ctx, rating)
+	if err != nil {
+		switch err.Error() {
+		case "booking not found":
+			return nil, s.errorHandler.HandleNotFound("booking not found", err)
+		case "booking belongs to another user":
+			return nil, s.errorHandler.HandlePermissionDenied("booking cannot be rated", err)
+		case "booking is not completed", "booking already rated":
+			return nil, s.errorHandler.HandleFailedPrecondition("booking cannot be rated", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to rate booking", err)
+	}
+
+	res := &pb.Rating{
+		BookingId: rated.BookingID,
+		UserId:    rated.UserID,
+		DriverId:  rated.DriverID,
+		Stars:     rated.Stars,
+		Comment:   rated.Comment,
+		Tags:      rated.Tags,
+		CreatedAt: timestamppb.New(rated.CreatedAt),
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// GetRatingStats returns the rating statistics of a driver or a route.
+func (s *BookingServer) GetRatingStats(ctx context.Context, req *pb.GetRatingStatsRequest) (*pb.RatingStats, error) {
+	method := "GetRatingStats"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.ratings == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("ratings are not enabled", fmt.Errorf("no rating repository"))
+	}
+
+	route := ratings.Route{SourcePlaceID: req.GetSourcePlaceId(), DestinationPlaceID: req.GetDestinationPlaceId()}
+	var subject string
+	switch {
+	case req.GetDriverId() < 0:
+		return nil, s.errorHandler.HandleInvalidArgument("invalid driver ID", fmt.Errorf("driver ID must be positive"))
+	case req.GetDriverId() > 0 && (route.SourcePlaceID != "" || route.DestinationPlaceID != ""):
+		return nil, s.errorHandler.HandleInvalidArgument("invalid subject", fmt.Errorf("give a driver or a route, not both"))
+	case req.GetDriverId() > 0:
+		subject = ratings.DriverSubject(req.DriverId)
+	case route.IsZero():
+		return nil, s.errorHandler.HandleInvalidArgument("invalid subject", fmt.Errorf("driver ID or source and destination place IDs are required"))
+	default:
+		subject = ratings.RouteSubject(route)
+	}
+
+	stats, err := s.ratings.GetStats(ctx, subject)
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to get rating stats", err)
+	}
+
+	res := &pb.RatingStats{
+		Count:   stats.Count,
+		Average: stats.Average(),
+		Stars:   stats.Stars[:],
+		Tags:    stats.Tags,
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// GetReceipt returns a completed booking's receipt, issuing it if it was not
+// issued when the booking completed.
+func (s *BookingServer) GetReceipt(ctx context.Context, req *pb.GetReceiptRequest) (*pb.Receipt, error) {
+	method := "GetReceipt"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.receipts == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("receipts are not enabled", fmt.Errorf("no receipt repository"))
+	}
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+
+	receipt, err := s.receipts.GetByBookingID(ctx, req.BookingId)
+	if err != nil && err.Error() != "receipt not found" {
+		return nil, s.errorHandler.HandleDatabaseError("failed to get receipt", err)
+	}
+	if err != nil {
+		booking, err := s.getBooking(ctx, req.BookingId)
+		if err != nil {
+			return nil, err
+		}
+		if booking.Status != repository.StatusCompleted && booking.Status != repository.StatusDisputed {
+			return nil, s.errorHandler.HandleFailedPrecondition("booking has no receipt", fmt.Errorf("booking is not completed"))
+		}
+		if receipt, err = s.issueReceipt(ctx, booking); err != nil {
+			return nil, err
+		}
+	}
+
+	html, err := receipts.HTML(receipt)
+	if err != nil {
+		return nil, s.errorHandler.HandleInternalError("failed to render receipt", err)
+	}
+
+	res := &pb.Receipt{
+		InvoiceNumber: receipt.InvoiceNumber,
+		BookingId:     receipt.BookingID,
+		IssuedAt:      timestamppb.New(receipt.IssuedAt),
+		UserName:      receipt.UserName,
+		Source:        receipt.Source,
+		Destination:   receipt.Destination,
+		Distance:      receipt.Distance,
+		VehicleClass:  receipt.VehicleClass,
+		CompletedAt:   timestamppb.New(receipt.CompletedAt),
+		Total:         money.ToProto(receipt.Total),
+		TaxRateBps:    receipt.TaxRateBps,
+		Tax:           money.ToProto(receipt.Tax),
+		Html:          string(html),
+		Pdf:           receipts.PDF(receipt),
+	}
+	for _, item := range receipt.Items {
+		res.Items = append(res.Items, &pb.ReceiptItem{Description: item.Description, Amount: money.ToProto(item.Amount)})
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *BookingServer) QueryAuditLog(ctx context.Context, req *auditpb.QueryAuditLogRequest) (*auditpb.QueryAuditLogResponse, error) {
+	method := "QueryAuditLog"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.auditLog == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("audit log is not enabled", fmt.Errorf("no audit log configured"))
+	}
+	if !audit.IsAdmin(ctx) {
+		return nil, s.errorHandler.HandlePermissionDenied("audit log is for admins only", fmt.Errorf("caller is %s, not %s", audit.Actor(ctx), audit.Admin))
+	}
+
+	filter, err := audit.FilterFromProto(req)
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid audit log query", err)
+	}
+
+	res, err := audit.Query(ctx, s.auditLog, filter, req.GetVerify())
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to query audit log", err)
+	}
+
+	s.logger.LogResponse(ctx, method, res)
 
 	return res, nil
 }
 
+// issueReceipt itemizes a completed booking with its user and ride and
+// issues its receipt.
+func (s *BookingServer) issueReceipt(ctx context.Context, booking *repository.Booking) (*receipts.Receipt, error) {
+	userRes, err := s.userClient.GetUser(ctx, &userpb.GetUserRequest{UserId: booking.UserID})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get user for receipt", "error", err, "user_id", booking.UserID)
+		return nil, s.errorHandler.HandleNetworkError("failed to get user details", err)
+	}
+	ride, err := s.rideClient.GetRide(ctx, &ridepb.GetRideRequest{RideId: booking.RideID})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get ride for receipt", "error", err, "ride_id", booking.RideID)
+		return nil, s.errorHandler.HandleNetworkError("failed to get ride details", err)
+	}
+	fare, err := money.FromProto(ridePrice(ride))
+	if err != nil {
+		return nil, s.errorHandler.HandleInternalError("invalid ride price", err)
+	}
+	components, err := fareComponents(ride.FareBreakdown)
+	if err != nil {
+		return nil, s.errorHandler.HandleInternalError("invalid ride fare breakdown", err)
+	}
+
+	receipt, err := receipts.New(receipts.Trip{
+		BookingID:    booking.ID,
+		UserName:     userRes.Name,
+		Source:       ride.Source,
+		Destination:  ride.Destination,
+		Distance:     ride.Distance,
+		VehicleClass: ride.VehicleClass,
+		CompletedAt:  booking.UpdatedAt,
+		Fare:         fare,
+		Components:   components,
+		PromoCode:    booking.PromoCode,
+		Discount:     booking.Discount,
+	}, s.taxRateBps)
+	if err != nil {
+		return nil, s.errorHandler.HandleInternalError("failed to itemize receipt", err)
+	}
+	receipt.IssuedAt = time.Now()
+
+	issued, err := s.receipts.Issue(ctx, receipt)
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to issue receipt", err)
+	}
+	return issued, nil
+}
+
+// paymentError maps a payment-service error to the booking's response.
+func (s *BookingServer) paymentError(message string, err error) error {
+	switch status.Code(err) {
+	case codes.FailedPrecondition:
+		return s.errorHandler.HandleFailedPrecondition(message, err)
+	case codes.NotFound:
+		return s.errorHandler.HandleNotFound(message, err)
+	}
+	return s.errorHandler.HandleNetworkError(message, err)
+}
+
 func validateCreateBookingRequest(req *pb.CreateBookingRequest) error {
 	if req.UserId <= 0 {
 		return fmt.Errorf("user ID must be positive")
@@ -153,9 +971,222 @@ func validateCreateBookingRequest(req *pb.CreateBookingRequest) error {
 	if ride.Distance <= 0 {
 		return fmt.Errorf("distance must be positive")
 	}
-	if ride.Cost <= 0 {
-		return fmt.Errorf("cost must be positive")
+
+	quote := req.Quote
+	if quote == nil {
+		return fmt.Errorf("fare quote is required")
+	}
+	if quote.Source != ride.Source || quote.Destination != ride.Destination || quote.Distance != ride.Distance {
+		return fmt.Errorf("fare quote does not match the requested ride")
 	}
 
 	return nil
 }
+
+// validatePickupTime checks that an optional pickup time falls within the
+// schedule window after now.
+func (s *BookingServer) validatePickupTime(pickupTime *timestamppb.Timestamp, now time.Time) error {
+	if pickupTime == nil {
+		return nil
+	}
+	if err := pickupTime.CheckValid(); err != nil {
+		return err
+	}
+
+	lead := pickupTime.AsTime().Sub(now)
+	if lead < s.minLead {
+		return fmt.Errorf("pickup must be at least %v from now", s.minLead)
+	}
+	if lead > s.maxLead {
+		return fmt.Errorf("pickup must be at most %v from now", s.maxLead)
+	}
+	return nil
+}
+
+// bookingDiscount returns what a booking's promo code took off its fare, or
+// nil without one.
+func bookingDiscount(b *repository.Booking) *moneypb.Money {
+	if b.PromoCode == "" {
+		return nil
+	}
+	return money.ToProto(b.Discount)
+}
+
+// bookingTotal returns what the user pays for a booking with fare.
+func bookingTotal(b *repository.Booking, fare money.Money) (money.Money, error) {
+	if b.PromoCode == "" {
+		return fare, nil
+	}
+	return fare.Sub(b.Discount)
+}
+
+func pickupTimestamp(b *repository.Booking) *timestamppb.Timestamp {
+	if b.PickupAt.IsZero() {
+		return nil
+	}
+	return timestamppb.New(b.PickupAt)
+}
+
+// ridePrice returns a ride's price, reading the whole-rupee cost from
+// ride-service instances that predate price.
+func ridePrice(ride *ridepb.Ride) *moneypb.Money {
+	if ride.Price != nil {
+		return ride.Price
+	}
+	price, _ := money.FromMajor(money.PKR, int64(ride.Cost))
+	return money.ToProto(price)
+}
+
+// quotePrice returns a quote's price, reading the whole-rupee fare from
+// quotes that predate price.
+func quotePrice(q *pb.FareQuote) *moneypb.Money {
+	if q.Price != nil {
+		return q.Price
+	}
+	price, _ := money.FromMajor(money.PKR, int64(q.Fare))
+	return money.ToProto(price)
+}
+
+func promotionFromProto(p *pb.Promotion) (*promotions.Promotion, error) {
+	promotion := &promotions.Promotion{
+		Code:           promotions.Normalize(p.Code),
+		Description:    p.Description,
+		Type:           p.DiscountType,
+		PercentBps:     p.PercentBps,
+		MaxRedemptions: p.MaxRedemptions,
+		MaxPerUser:     p.MaxPerUser,
+		FirstRideOnly:  p.FirstRideOnly,
+		CityPlaceID:    p.CityPlaceId,
+	}
+	var err error
+	if p.AmountOff != nil {
+		if promotion.AmountOff, err = money.FromProto(p.AmountOff); err != nil {
+			return nil, fmt.Errorf("amount off: %w", err)
+		}
+	}
+	if p.MaxDiscount != nil {
+		if promotion.MaxDiscount, err = money.FromProto(p.MaxDiscount); err != nil {
+			return nil, fmt.Errorf("max discount: %w", err)
+		}
+	}
+	if p.StartsAt != nil {
+		promotion.StartsAt = p.StartsAt.AsTime()
+	}
+	if p.EndsAt != nil {
+		promotion.EndsAt = p.EndsAt.AsTime()
+	}
+	return promotion, nil
+}
+
+func promotionToProto(p *promotions.Promotion) *pb.Promotion {
+	res := &pb.Promotion{
+		Code:           p.Code,
+		Description:    p.Description,
+		DiscountType:   p.Type,
+		PercentBps:     p.PercentBps,
+		MaxRedemptions: p.MaxRedemptions,
+		MaxPerUser:     p.MaxPerUser,
+		FirstRideOnly:  p.FirstRideOnly,
+		CityPlaceId:    p.CityPlaceID,
+		Redemptions:    p.Redemptions,
+		CreatedAt:      timestamppb.New(p.CreatedAt),
+	}
+	if p.AmountOff.Currency != "" {
+		res.AmountOff = money.ToProto(p.AmountOff)
+	}
+	if p.MaxDiscount.Currency != "" {
+		res.MaxDiscount = money.ToProto(p.MaxDiscount)
+	}
+	if !p.StartsAt.IsZero() {
+		res.StartsAt = timestamppb.New(p.StartsAt)
+	}
+	if !p.EndsAt.IsZero() {
+		res.EndsAt = timestamppb.New(p.EndsAt)
+	}
+	return res
+}
+
+func toRideLatLng(p *pb.LatLng) *ridepb.LatLng {
+	if p == nil {
+		return nil
+	}
+	return &ridepb.LatLng{Lat: p.Lat, Lng: p.Lng}
+}
+
+func toRideQuote(q *pb.FareQuote) *ridepb.FareQuote {
+	return &ridepb.FareQuote{
+		Source:        q.Source,
+		Destination:   q.Destination,
+		Distance:      q.Distance,
+		VehicleClass:  q.VehicleClass,
+		Fare:          q.Fare,
+		Price:         q.Price,
+		TariffVersion: q.TariffVersion,
+		ExpiresAt:     q.ExpiresAt,
+		Signature:     q.Signature,
+
+		SurgeMultiplierBp: q.SurgeMultiplierBp,
+		SurgeExpiresAt:    q.SurgeExpiresAt,
+
+		Breakdown:          toRideBreakdown(q.Breakdown),
+		BreakdownSignature: q.BreakdownSignature,
+	}
+}
+
+func toRideBreakdown(b *pb.FareBreakdown) *ridepb.FareBreakdown {
+	if b == nil {
+		return nil
+	}
+	return &ridepb.FareBreakdown{
+		BaseFare:              b.BaseFare,
+		DistanceFare:          b.DistanceFare,
+		MinimumFareAdjustment: b.MinimumFareAdjustment,
+		TimeOfDayMultiplierBp: b.TimeOfDayMultiplierBp,
+		TimeOfDayAdjustment:   b.TimeOfDayAdjustment,
+		SurgeMultiplierBp:     b.SurgeMultiplierBp,
+		SurgeAdjustment:       b.SurgeAdjustment,
+	}
+}
+
+func fromRideBreakdown(b *ridepb.FareBreakdown) *pb.FareBreakdown {
+	if b == nil {
+		return nil
+	}
+	return &pb.FareBreakdown{
+		BaseFare:              b.BaseFare,
+		DistanceFare:          b.DistanceFare,
+		MinimumFareAdjustment: b.MinimumFareAdjustment,
+		TimeOfDayMultiplierBp: b.TimeOfDayMultiplierBp,
+		TimeOfDayAdjustment:   b.TimeOfDayAdjustment,
+		SurgeMultiplierBp:     b.SurgeMultiplierBp,
+		SurgeAdjustment:       b.SurgeAdjustment,
+	}
+}
+
+// fareComponents reads a ride's fare breakdown for its receipt, or nil if it
+// has none.
+func fareComponents(b *ridepb.FareBreakdown) (*receipts.FareComponents, error) {
+	if b == nil {
+		return nil, nil
+	}
+	c := &receipts.FareComponents{
+		TimeOfDayMultiplierBP: b.TimeOfDayMultiplierBp,
+		SurgeMultiplierBP:     b.SurgeMultiplierBp,
+	}
+	for _, amount := range []struct {
+		dst *money.Money
+		src *moneypb.Money
+	}{
+		{&c.BaseFare, b.BaseFare},
+		{&c.DistanceFare, b.DistanceFare},
+		{&c.MinimumFareAdjustment, b.MinimumFareAdjustment},
+		{&c.TimeOfDayAdjustment, b.TimeOfDayAdjustment},
+		{&c.SurgeAdjustment, b.SurgeAdjustment},
+	} {
+		var err error
+		if *amount.dst, err = money.FromProto(amount.src); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
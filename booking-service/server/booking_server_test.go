@@ -4,56 +4,124 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "booking-service/pb/proto/booking"
+	"booking-service/promotions"
+	"booking-service/ratings"
+	"booking-service/receipts"
 	"booking-service/repository"
 	"booking-service/repository/mocks"
+	driverpb "driver-service/pb/proto/driver"
+	drivermocks "driver-service/pb/proto/driver/mocks"
+	paymentpb "payment-service/pb/proto/payment"
+	paymentmocks "payment-service/pb/proto/payment/mocks"
 	ridepb "ride-service/pb/proto/ride"
 	ridemocks "ride-service/pb/proto/ride/mocks"
 	userpb "user-service/pb/proto/user"
 	usermocks "user-service/pb/proto/user/mocks"
+
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	auditmocks "github.com/hasnain-zafar/go-microservices/common/audit/mocks"
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	auditpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
 )
 
+var testCreatedAt = time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// testQuote is a fare quote for the New York to Boston test ride. Its
+// signature is only checked by ride-service, which is mocked here.
+func testQuote() *pb.FareQuote {
+	return &pb.FareQuote{
+		Source:        "New York",
+		Destination:   "Boston",
+		Distance:      200,
+		VehicleClass:  "ECONOMY",
+		Fare:          150,
+		Price:         &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15000},
+		TariffVersion: 1,
+		Signature:     "signature",
+	}
+}
+
+func testRideQuote() *ridepb.FareQuote {
+	return toRideQuote(testQuote())
+}
+
+// authorizingPaymentClient authorizes every payment.
+func authorizingPaymentClient() *paymentmocks.PaymentServiceClient {
+	client := new(paymentmocks.PaymentServiceClient)
+	client.On("AuthorizePayment", mock.Anything, mock.Anything).
+		Return(&paymentpb.Payment{Status: "AUTHORIZED"}, nil)
+	return client
+}
+
+// voidingPaymentClient voids every payment.
+func voidingPaymentClient() *paymentmocks.PaymentServiceClient {
+	client := new(paymentmocks.PaymentServiceClient)
+	client.On("VoidPayment", mock.Anything, mock.Anything).
+		Return(&paymentpb.Payment{Status: "VOIDED"}, nil)
+	return client
+}
+
 func TestCreateBooking_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.BookingRepository)
 	mockUserClient := new(usermocks.UserServiceClient)
 	mockRideClient := new(ridemocks.RideServiceClient)
+	mockPaymentClient := new(paymentmocks.PaymentServiceClient)
 
-	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), mockPaymentClient)
 
 	ctx := context.Background()
 	req := &pb.CreateBookingRequest{
 		UserId: 1,
 		Ride: &pb.Ride{
-			Source:      "New York",
-			Destination: "Boston",
-			Distance:    200,
-			Cost:        150,
+			Source:              "New York",
+			Destination:         "Boston",
+			Distance:            200,
+			SourceLocation:      &pb.LatLng{Lat: 40.7128, Lng: -74.0060},
+			DestinationLocation: &pb.LatLng{Lat: 42.3601, Lng: -71.0589},
 		},
+		Quote: testQuote(),
 	}
 
-	// Expectations
+	// Expectations: coordinates are forwarded to ride-service
 	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 1}).
 		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
 
 	mockRideClient.On("CreateRide", ctx, &ridepb.CreateRideRequest{
-		Source:      "New York",
-		Destination: "Boston",
-		Distance:    200,
-		Cost:        150,
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Quote:               testRideQuote(),
+		SourceLocation:      &ridepb.LatLng{Lat: 40.7128, Lng: -74.0060},
+		DestinationLocation: &ridepb.LatLng{Lat: 42.3601, Lng: -71.0589},
 	}).Return(&ridepb.CreateRideResponse{RideId: 5}, nil)
 
 	// Mock the booking creation
 	mockBooking := &repository.Booking{
-		ID:     10,
-		UserID: 1,
-		RideID: 5,
-		Time:   "2023-01-01T12:00:00Z",
+		ID:        10,
+		UserID:    1,
+		RideID:    5,
+		CreatedAt: testCreatedAt,
 	}
-	mockRepo.On("Create", ctx, int32(1), int32(5)).Return(mockBooking, nil)
+	mockRepo.On("Create", ctx, int32(1), int32(5), (*repository.Redemption)(nil)).Return(mockBooking, nil)
+
+	// The quoted price is authorized for the new booking
+	mockPaymentClient.On("AuthorizePayment", ctx, &paymentpb.AuthorizePaymentRequest{
+		BookingId: 10,
+		UserId:    1,
+		Amount:    &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15000},
+	}).Return(&paymentpb.Payment{Status: "AUTHORIZED"}, nil)
 
 	// Action
 	resp, err := bookingServer.CreateBooking(ctx, req)
@@ -64,12 +132,13 @@ func TestCreateBooking_Success(t *testing.T) {
 	assert.Equal(t, int32(10), resp.BookingId)
 	assert.Equal(t, int32(1), resp.UserId)
 	assert.Equal(t, int32(5), resp.RideId)
-	assert.Equal(t, "2023-01-01T12:00:00Z", resp.Time)
+	assert.Equal(t, testCreatedAt, resp.CreatedAt.AsTime())
 
 	// Verify expectations
 	mockUserClient.AssertExpectations(t)
 	mockRideClient.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
+	mockPaymentClient.AssertExpectations(t)
 }
 
 func TestCreateBooking_InvalidRequest(t *testing.T) {
@@ -86,8 +155,8 @@ func TestCreateBooking_InvalidRequest(t *testing.T) {
 					Source:      "New York",
 					Destination: "Boston",
 					Distance:    200,
-					Cost:        150,
 				},
+				Quote: testQuote(),
 			},
 		},
 		{
@@ -105,8 +174,8 @@ func TestCreateBooking_InvalidRequest(t *testing.T) {
 					Source:      "",
 					Destination: "Boston",
 					Distance:    200,
-					Cost:        150,
 				},
+				Quote: testQuote(),
 			},
 		},
 		{
@@ -117,8 +186,8 @@ func TestCreateBooking_InvalidRequest(t *testing.T) {
 					Source:      "New York",
 					Destination: "",
 					Distance:    200,
-					Cost:        150,
 				},
+				Quote: testQuote(),
 			},
 		},
 		{
@@ -129,22 +198,33 @@ func TestCreateBooking_InvalidRequest(t *testing.T) {
 					Source:      "New York",
 					Destination: "Boston",
 					Distance:    0,
-					Cost:        150,
 				},
+				Quote: testQuote(),
 			},
 		},
 		{
-			name: "Zero Cost",
+			name: "Missing Quote",
 			req: &pb.CreateBookingRequest{
 				UserId: 1,
 				Ride: &pb.Ride{
 					Source:      "New York",
 					Destination: "Boston",
 					Distance:    200,
-					Cost:        0,
 				},
 			},
 		},
+		{
+			name: "Quote For Different Ride",
+			req: &pb.CreateBookingRequest{
+				UserId: 1,
+				Ride: &pb.Ride{
+					Source:      "New York",
+					Destination: "Washington DC",
+					Distance:    200,
+				},
+				Quote: testQuote(),
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -154,7 +234,7 @@ func TestCreateBooking_InvalidRequest(t *testing.T) {
 			mockUserClient := new(usermocks.UserServiceClient)
 			mockRideClient := new(ridemocks.RideServiceClient)
 
-			bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+			bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
 
 			// Action
 			resp, err := bookingServer.CreateBooking(context.Background(), tc.req)
@@ -177,7 +257,7 @@ func TestCreateBooking_UserServiceError(t *testing.T) {
 	mockUserClient := new(usermocks.UserServiceClient)
 	mockRideClient := new(ridemocks.RideServiceClient)
 
-	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
 
 	ctx := context.Background()
 	req := &pb.CreateBookingRequest{
@@ -186,8 +266,8 @@ func TestCreateBooking_UserServiceError(t *testing.T) {
 			Source:      "New York",
 			Destination: "Boston",
 			Distance:    200,
-			Cost:        150,
 		},
+		Quote: testQuote(),
 	}
 
 	// Expectations
@@ -213,7 +293,7 @@ func TestCreateBooking_RideServiceError(t *testing.T) {
 	mockUserClient := new(usermocks.UserServiceClient)
 	mockRideClient := new(ridemocks.RideServiceClient)
 
-	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
 
 	ctx := context.Background()
 	req := &pb.CreateBookingRequest{
@@ -222,8 +302,8 @@ func TestCreateBooking_RideServiceError(t *testing.T) {
 			Source:      "New York",
 			Destination: "Boston",
 			Distance:    200,
-			Cost:        150,
 		},
+		Quote: testQuote(),
 	}
 
 	// Expectations
@@ -234,7 +314,7 @@ func TestCreateBooking_RideServiceError(t *testing.T) {
 		Source:      "New York",
 		Destination: "Boston",
 		Distance:    200,
-		Cost:        150,
+		Quote:       testRideQuote(),
 	}).Return(nil, errors.New("failed to create ride"))
 
 	// Action
@@ -256,7 +336,7 @@ func TestCreateBooking_RepositoryError(t *testing.T) {
 	mockUserClient := new(usermocks.UserServiceClient)
 	mockRideClient := new(ridemocks.RideServiceClient)
 
-	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
 
 	ctx := context.Background()
 	req := &pb.CreateBookingRequest{
@@ -265,8 +345,8 @@ func TestCreateBooking_RepositoryError(t *testing.T) {
 			Source:      "New York",
 			Destination: "Boston",
 			Distance:    200,
-			Cost:        150,
 		},
+		Quote: testQuote(),
 	}
 
 	// Expectations
@@ -277,10 +357,10 @@ func TestCreateBooking_RepositoryError(t *testing.T) {
 		Source:      "New York",
 		Destination: "Boston",
 		Distance:    200,
-		Cost:        150,
+		Quote:       testRideQuote(),
 	}).Return(&ridepb.CreateRideResponse{RideId: 5}, nil)
 
-	mockRepo.On("Create", ctx, int32(1), int32(5)).Return(nil, errors.New("database error"))
+	mockRepo.On("Create", ctx, int32(1), int32(5), (*repository.Redemption)(nil)).Return(nil, errors.New("database error"))
 
 	// Action
 	resp, err := bookingServer.CreateBooking(ctx, req)
@@ -295,13 +375,266 @@ func TestCreateBooking_RepositoryError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// setupDriverAssignment expects a booking for a ride whose pickup is known, so
+// CreateBooking asks driver-service for a driver.
+func setupDriverAssignment(ctx context.Context, mockRepo *mocks.BookingRepository, mockUserClient *usermocks.UserServiceClient, mockRideClient *ridemocks.RideServiceClient) {
+	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 1}).
+		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+	mockRideClient.On("CreateRide", ctx, &ridepb.CreateRideRequest{
+		Source:      "New York",
+		Destination: "Boston",
+		Distance:    200,
+		Quote:       testRideQuote(),
+	}).Return(&ridepb.CreateRideResponse{RideId: 5, SourceLocation: &ridepb.LatLng{Lat: 40.7128, Lng: -74.0060}}, nil)
+	mockRepo.On("Create", ctx, int32(1), int32(5), (*repository.Redemption)(nil)).Return(&repository.Booking{
+		ID:        10,
+		UserID:    1,
+		RideID:    5,
+		CreatedAt: testCreatedAt,
+		Status:    repository.StatusConfirmed,
+		Version:   1,
+	}, nil)
+}
+
+func testBookingRequest() *pb.CreateBookingRequest {
+	return &pb.CreateBookingRequest{
+		UserId: 1,
+		Ride:   &pb.Ride{Source: "New York", Destination: "Boston", Distance: 200},
+		Quote:  testQuote(),
+	}
+}
+
+func testAssignDriverRequest() *driverpb.AssignDriverRequest {
+	return &driverpb.AssignDriverRequest{
+		BookingId:    10,
+		Pickup:       &driverpb.LatLng{Lat: 40.7128, Lng: -74.0060},
+		VehicleClass: "ECONOMY",
+	}
+}
+
+func TestCreateBooking_AssignsDriver(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	mockDriverClient := new(drivermocks.DriverServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, mockDriverClient, authorizingPaymentClient())
+
+	ctx := context.Background()
+
+	// Expectations: the nearest driver to the ride's pickup is assigned
+	setupDriverAssignment(ctx, mockRepo, mockUserClient, mockRideClient)
+	mockDriverClient.On("AssignDriver", ctx, testAssignDriverRequest()).
+		Return(&driverpb.Assignment{BookingId: 10, Driver: &driverpb.Driver{DriverId: 7}, DistanceKm: 1.5}, nil)
+	mockRepo.On("AssignDriver", ctx, int32(10), int32(7)).Return(&repository.Booking{
+		ID:        10,
+		UserID:    1,
+		RideID:    5,
+		CreatedAt: testCreatedAt,
+		Status:    repository.StatusConfirmed,
+		DriverID:  7,
+		Version:   2,
+	}, nil)
+
+	// Action
+	resp, err := bookingServer.CreateBooking(ctx, testBookingRequest())
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), resp.BookingId)
+	assert.Equal(t, int32(7), resp.DriverId)
+	mockDriverClient.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateBooking_NoDriverAvailable(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	mockDriverClient := new(drivermocks.DriverServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, mockDriverClient, authorizingPaymentClient())
+
+	ctx := context.Background()
+
+	// Expectations
+	setupDriverAssignment(ctx, mockRepo, mockUserClient, mockRideClient)
+	mockDriverClient.On("AssignDriver", ctx, testAssignDriverRequest()).
+		Return(nil, status.Error(codes.NotFound, "no driver available"))
+
+	// Action
+	resp, err := bookingServer.CreateBooking(ctx, testBookingRequest())
+
+	// Assertions: the booking is still confirmed, without a driver
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), resp.BookingId)
+	assert.Equal(t, repository.StatusConfirmed, resp.Status)
+	assert.Zero(t, resp.DriverId)
+	mockDriverClient.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "AssignDriver")
+}
+
+func TestCreateBooking_AssignmentNotRecorded(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	mockDriverClient := new(drivermocks.DriverServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, mockDriverClient, authorizingPaymentClient())
+
+	ctx := context.Background()
+
+	// Expectations: the driver is released if the booking cannot record them
+	setupDriverAssignment(ctx, mockRepo, mockUserClient, mockRideClient)
+	mockDriverClient.On("AssignDriver", ctx, testAssignDriverRequest()).
+		Return(&driverpb.Assignment{BookingId: 10, Driver: &driverpb.Driver{DriverId: 7}}, nil)
+	mockRepo.On("AssignDriver", ctx, int32(10), int32(7)).Return(nil, errors.New("database error"))
+	mockDriverClient.On("ReleaseDriver", ctx, &driverpb.ReleaseDriverRequest{BookingId: 10}).
+		Return(&driverpb.ReleaseDriverResponse{}, nil)
+
+	// Action
+	resp, err := bookingServer.CreateBooking(ctx, testBookingRequest())
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Zero(t, resp.DriverId)
+	mockDriverClient.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateBooking_Scheduled(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	mockDriverClient := new(drivermocks.DriverServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, mockDriverClient, authorizingPaymentClient())
+
+	ctx := context.Background()
+	pickupAt := time.Now().Add(2 * time.Hour).Truncate(time.Second).UTC()
+	req := testBookingRequest()
+	req.PickupTime = timestamppb.New(pickupAt)
+
+	// Expectations: the booking is scheduled and no driver is assigned yet
+	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 1}).
+		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+	mockRideClient.On("CreateRide", ctx, &ridepb.CreateRideRequest{
+		Source:      "New York",
+		Destination: "Boston",
+		Distance:    200,
+		Quote:       testRideQuote(),
+	}).Return(&ridepb.CreateRideResponse{RideId: 5, SourceLocation: &ridepb.LatLng{Lat: 40.7128, Lng: -74.0060}}, nil)
+	mockRepo.On("Schedule", ctx, int32(1), int32(5), pickupAt, (*repository.Redemption)(nil)).Return(&repository.Booking{
+		ID:        10,
+		UserID:    1,
+		RideID:    5,
+		CreatedAt: testCreatedAt,
+		Status:    repository.StatusScheduled,
+		PickupAt:  pickupAt,
+		Version:   1,
+	}, nil)
+
+	// Action
+	resp, err := bookingServer.CreateBooking(ctx, req)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusScheduled, resp.Status)
+	assert.Equal(t, pickupAt, resp.PickupTime.AsTime())
+	assert.Zero(t, resp.DriverId)
+	mockRepo.AssertExpectations(t)
+	mockDriverClient.AssertNotCalled(t, "AssignDriver")
+}
+
+func TestCreateBooking_InvalidPickupTime(t *testing.T) {
+	testCases := []struct {
+		name       string
+		pickupTime *timestamppb.Timestamp
+	}{
+		{name: "In The Past", pickupTime: timestamppb.New(time.Now().Add(-time.Hour))},
+		{name: "Too Soon", pickupTime: timestamppb.New(time.Now().Add(5 * time.Minute))},
+		{name: "Too Far Ahead", pickupTime: timestamppb.New(time.Now().Add(8 * 24 * time.Hour))},
+		{name: "Invalid Timestamp", pickupTime: &timestamppb.Timestamp{Seconds: 1, Nanos: -1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(mocks.BookingRepository)
+			mockRideClient := new(ridemocks.RideServiceClient)
+			bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
+
+			req := testBookingRequest()
+			req.PickupTime = tc.pickupTime
+
+			// Action
+			resp, err := bookingServer.CreateBooking(context.Background(), req)
+
+			// Assertions
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			mockRideClient.AssertNotCalled(t, "CreateRide")
+			mockRepo.AssertNotCalled(t, "Schedule")
+		})
+	}
+}
+
+func TestCreateBooking_ScheduleWindowOption(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient),
+		new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient), WithScheduleWindow(time.Hour, 2*time.Hour))
+
+	now := time.Now()
+
+	// Assertions
+	assert.Error(t, bookingServer.validatePickupTime(timestamppb.New(now.Add(30*time.Minute)), now))
+	assert.NoError(t, bookingServer.validatePickupTime(timestamppb.New(now.Add(90*time.Minute)), now))
+	assert.Error(t, bookingServer.validatePickupTime(timestamppb.New(now.Add(3*time.Hour)), now))
+	assert.NoError(t, bookingServer.validatePickupTime(nil, now))
+}
+
+func TestActivateBooking_AssignsDriver(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	mockDriverClient := new(drivermocks.DriverServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), mockRideClient, mockDriverClient, new(paymentmocks.PaymentServiceClient))
+
+	ctx := context.Background()
+	booking := &repository.Booking{ID: 10, UserID: 1, RideID: 5, Status: repository.StatusConfirmed, Version: 2}
+
+	// Expectations: the pickup and vehicle class come from the ride
+	mockRideClient.On("GetRide", ctx, &ridepb.GetRideRequest{RideId: 5}).Return(&ridepb.Ride{
+		Source:         "New York",
+		VehicleClass:   "ECONOMY",
+		SourceLocation: &ridepb.LatLng{Lat: 40.7128, Lng: -74.0060},
+	}, nil)
+	mockDriverClient.On("AssignDriver", ctx, testAssignDriverRequest()).
+		Return(&driverpb.Assignment{BookingId: 10, Driver: &driverpb.Driver{DriverId: 7}}, nil)
+	mockRepo.On("AssignDriver", ctx, int32(10), int32(7)).Return(&repository.Booking{ID: 10, DriverID: 7}, nil)
+
+	// Action
+	bookingServer.ActivateBooking(ctx, booking)
+
+	// Assertions
+	mockRideClient.AssertExpectations(t)
+	mockDriverClient.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestGetBooking_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.BookingRepository)
 	mockUserClient := new(usermocks.UserServiceClient)
 	mockRideClient := new(ridemocks.RideServiceClient)
 
-	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
 
 	ctx := context.Background()
 	req := &pb.GetBookingRequest{
@@ -310,10 +643,10 @@ func TestGetBooking_Success(t *testing.T) {
 
 	// Mock the booking
 	mockBooking := &repository.Booking{
-		ID:     1,
-		UserID: 2,
-		RideID: 3,
-		Time:   "2023-01-01T12:00:00Z",
+		ID:        1,
+		UserID:    2,
+		RideID:    3,
+		CreatedAt: testCreatedAt,
 	}
 
 	// Expectations
@@ -328,7 +661,8 @@ func TestGetBooking_Success(t *testing.T) {
 			Source:      "New York",
 			Destination: "Boston",
 			Distance:    200,
-			Cost:        150,
+			Cost:        151,
+			Price:       &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15050},
 		}, nil)
 
 	// Action
@@ -341,8 +675,9 @@ func TestGetBooking_Success(t *testing.T) {
 	assert.Equal(t, "New York", resp.Source)
 	assert.Equal(t, "Boston", resp.Destination)
 	assert.Equal(t, int32(200), resp.Distance)
-	assert.Equal(t, int32(150), resp.Cost)
-	assert.Equal(t, "2023-01-01T12:00:00Z", resp.Time)
+	assert.Equal(t, int32(151), resp.Cost)
+	assert.Equal(t, int64(15050), resp.Price.MinorUnits)
+	assert.Equal(t, testCreatedAt, resp.CreatedAt.AsTime())
 
 	// Verify expectations
 	mockRepo.AssertExpectations(t)
@@ -350,13 +685,39 @@ func TestGetBooking_Success(t *testing.T) {
 	mockRideClient.AssertExpectations(t)
 }
 
+func TestGetBooking_LegacyRideCost(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
+
+	ctx := context.Background()
+
+	// Expectations: ride-service predates price and only sends cost
+	mockRepo.On("GetByID", ctx, int32(1)).Return(&repository.Booking{ID: 1, UserID: 2, RideID: 3}, nil)
+	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 2}).
+		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+	mockRideClient.On("GetRide", ctx, &ridepb.GetRideRequest{RideId: 3}).
+		Return(&ridepb.Ride{RideId: 3, Source: "New York", Destination: "Boston", Distance: 200, Cost: 150}, nil)
+
+	// Action
+	resp, err := bookingServer.GetBooking(ctx, &pb.GetBookingRequest{BookingId: 1})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int32(150), resp.Cost)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15000}, resp.Price)
+}
+
 func TestGetBooking_InvalidId(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.BookingRepository)
 	mockUserClient := new(usermocks.UserServiceClient)
 	mockRideClient := new(ridemocks.RideServiceClient)
 
-	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
 
 	ctx := context.Background()
 	req := &pb.GetBookingRequest{
@@ -382,7 +743,7 @@ func TestGetBooking_NotFound(t *testing.T) {
 	mockUserClient := new(usermocks.UserServiceClient)
 	mockRideClient := new(ridemocks.RideServiceClient)
 
-	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
 
 	ctx := context.Background()
 	req := &pb.GetBookingRequest{
@@ -411,7 +772,7 @@ func TestGetBooking_UserServiceError(t *testing.T) {
 	mockUserClient := new(usermocks.UserServiceClient)
 	mockRideClient := new(ridemocks.RideServiceClient)
 
-	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
 
 	ctx := context.Background()
 	req := &pb.GetBookingRequest{
@@ -420,10 +781,10 @@ func TestGetBooking_UserServiceError(t *testing.T) {
 
 	// Mock the booking
 	mockBooking := &repository.Booking{
-		ID:     1,
-		UserID: 2,
-		RideID: 3,
-		Time:   "2023-01-01T12:00:00Z",
+		ID:        1,
+		UserID:    2,
+		RideID:    3,
+		CreatedAt: testCreatedAt,
 	}
 
 	// Expectations
@@ -451,7 +812,7 @@ func TestGetBooking_RideServiceError(t *testing.T) {
 	mockUserClient := new(usermocks.UserServiceClient)
 	mockRideClient := new(ridemocks.RideServiceClient)
 
-	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
 
 	ctx := context.Background()
 	req := &pb.GetBookingRequest{
@@ -460,10 +821,10 @@ func TestGetBooking_RideServiceError(t *testing.T) {
 
 	// Mock the booking
 	mockBooking := &repository.Booking{
-		ID:     1,
-		UserID: 2,
-		RideID: 3,
-		Time:   "2023-01-01T12:00:00Z",
+		ID:        1,
+		UserID:    2,
+		RideID:    3,
+		CreatedAt: testCreatedAt,
 	}
 
 	// Expectations
@@ -487,3 +848,1048 @@ func TestGetBooking_RideServiceError(t *testing.T) {
 	mockUserClient.AssertExpectations(t)
 	mockRideClient.AssertExpectations(t)
 }
+
+func TestCancelBooking_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), voidingPaymentClient())
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("Cancel", ctx, int32(1)).Return(&repository.Booking{
+		ID:        1,
+		UserID:    2,
+		RideID:    3,
+		CreatedAt: testCreatedAt,
+		Status:    repository.StatusCancelled,
+	}, nil)
+
+	// Action
+	resp, err := bookingServer.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: 1})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "Booking 1 cancelled successfully", resp.Message)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelBooking_ReleasesDriver(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockDriverClient := new(drivermocks.DriverServiceClient)
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), mockDriverClient, voidingPaymentClient())
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("Cancel", ctx, int32(1)).Return(&repository.Booking{
+		ID:       1,
+		Status:   repository.StatusCancelled,
+		DriverID: 7,
+	}, nil)
+	mockDriverClient.On("ReleaseDriver", ctx, &driverpb.ReleaseDriverRequest{BookingId: 1}).
+		Return(nil, status.Error(codes.Unavailable, "driver-service unavailable"))
+
+	// Action
+	resp, err := bookingServer.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: 1})
+
+	// Assertions: a failed release does not fail the cancellation
+	assert.NoError(t, err)
+	assert.Equal(t, "Booking 1 cancelled successfully", resp.Message)
+	mockRepo.AssertExpectations(t)
+	mockDriverClient.AssertExpectations(t)
+}
+
+func TestCancelBooking_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		repoErr  error
+		expected codes.Code
+	}{
+		{name: "Not Found", repoErr: errors.New("booking not found"), expected: codes.NotFound},
+		{name: "Already Cancelled", repoErr: errors.New("booking already cancelled"), expected: codes.FailedPrecondition},
+		{name: "Already Completed", repoErr: errors.New("booking already completed"), expected: codes.FailedPrecondition},
+		{name: "Database Error", repoErr: errors.New("database error"), expected: codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(mocks.BookingRepository)
+			bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
+
+			ctx := context.Background()
+			mockRepo.On("Cancel", ctx, int32(1)).Return(nil, tc.repoErr)
+
+			// Action
+			resp, err := bookingServer.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: 1})
+
+			// Assertions
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.expected, status.Code(err))
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCancelBooking_InvalidId(t *testing.T) {
+	mockRepo := new(mocks.BookingRepository)
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
+
+	resp, err := bookingServer.CancelBooking(context.Background(), &pb.CancelBookingRequest{BookingId: 0})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockRepo.AssertNotCalled(t, "Cancel")
+}
+
+func TestCancelBooking_VoidsPayment(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), new(drivermocks.DriverServiceClient), mockPaymentClient)
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("Cancel", ctx, int32(1)).Return(&repository.Booking{ID: 1, Status: repository.StatusCancelled}, nil)
+	mockPaymentClient.On("VoidPayment", ctx, &paymentpb.VoidPaymentRequest{BookingId: 1}).
+		Return(nil, status.Error(codes.Unavailable, "payment-service unavailable"))
+
+	// Action
+	resp, err := bookingServer.CancelBooking(ctx, &pb.CancelBookingRequest{BookingId: 1})
+
+	// Assertions: a failed void does not fail the cancellation
+	assert.NoError(t, err)
+	assert.Equal(t, "Booking 1 cancelled successfully", resp.Message)
+	mockPaymentClient.AssertExpectations(t)
+}
+
+// setupPaymentFailure expects a booking request for the New York to Boston
+// ride to get as far as authorizing its payment.
+func setupPaymentFailure(ctx context.Context, mockRepo *mocks.BookingRepository, mockUserClient *usermocks.UserServiceClient, mockRideClient *ridemocks.RideServiceClient) *pb.CreateBookingRequest {
+	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 1}).
+		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+	mockRideClient.On("CreateRide", ctx, mock.Anything).
+		Return(&ridepb.CreateRideResponse{RideId: 5, SourceLocation: &ridepb.LatLng{Lat: 40.7128, Lng: -74.0060}}, nil)
+	mockRepo.On("Create", ctx, int32(1), int32(5), (*repository.Redemption)(nil)).
+		Return(&repository.Booking{ID: 10, UserID: 1, RideID: 5, Status: repository.StatusConfirmed}, nil)
+	mockRepo.On("Cancel", ctx, int32(10)).
+		Return(&repository.Booking{ID: 10, UserID: 1, RideID: 5, Status: repository.StatusCancelled}, nil)
+
+	return &pb.CreateBookingRequest{
+		UserId: 1,
+		Ride:   &pb.Ride{Source: "New York", Destination: "Boston", Distance: 200},
+		Quote:  testQuote(),
+	}
+}
+
+func TestCreateBooking_PaymentFailure(t *testing.T) {
+	testCases := []struct {
+		name       string
+		paymentErr error
+		expected   codes.Code
+	}{
+		{name: "Declined", paymentErr: status.Error(codes.FailedPrecondition, "payment declined: insufficient funds"), expected: codes.FailedPrecondition},
+		{name: "Unavailable", paymentErr: status.Error(codes.Unavailable, "payment provider timed out"), expected: codes.Unavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(mocks.BookingRepository)
+			mockUserClient := new(usermocks.UserServiceClient)
+			mockRideClient := new(ridemocks.RideServiceClient)
+			mockDriverClient := new(drivermocks.DriverServiceClient)
+			mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+			bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, mockDriverClient, mockPaymentClient)
+
+			ctx := context.Background()
+			req := setupPaymentFailure(ctx, mockRepo, mockUserClient, mockRideClient)
+
+			// Expectations: the booking is cancelled and any authorization voided
+			mockPaymentClient.On("AuthorizePayment", ctx, mock.Anything).Return(nil, tc.paymentErr)
+			mockPaymentClient.On("VoidPayment", ctx, &paymentpb.VoidPaymentRequest{BookingId: 10}).
+				Return(&paymentpb.Payment{Status: "DECLINED"}, nil)
+
+			// Action
+			resp, err := bookingServer.CreateBooking(ctx, req)
+
+			// Assertions: no driver is assigned to an unpaid booking
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.expected, status.Code(err))
+			mockRepo.AssertExpectations(t)
+			mockPaymentClient.AssertExpectations(t)
+			mockDriverClient.AssertNotCalled(t, "AssignDriver", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCompleteBooking_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockDriverClient := new(drivermocks.DriverServiceClient)
+	mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), mockDriverClient, mockPaymentClient)
+
+	ctx := context.Background()
+	charged := &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15000}
+
+	// Expectations
+	mockRepo.On("Complete", ctx, int32(1)).Return(&repository.Booking{
+		ID:       1,
+		Status:   repository.StatusCompleted,
+		DriverID: 7,
+	}, nil)
+	mockDriverClient.On("ReleaseDriver", ctx, &driverpb.ReleaseDriverRequest{BookingId: 1}).
+		Return(&driverpb.ReleaseDriverResponse{}, nil)
+	// The driver is credited with their share of the fare
+	mockPaymentClient.On("CapturePayment", ctx, &paymentpb.CapturePaymentRequest{BookingId: 1, DriverId: 7}).
+		Return(&paymentpb.Payment{Status: "CAPTURED", Captured: charged}, nil)
+
+	// Action
+	resp, err := bookingServer.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: 1})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "Booking 1 completed successfully", resp.Message)
+	assert.Equal(t, charged, resp.Charged)
+	mockRepo.AssertExpectations(t)
+	mockDriverClient.AssertExpectations(t)
+	mockPaymentClient.AssertExpectations(t)
+}
+
+func TestCompleteBooking_WithoutPayment(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), new(drivermocks.DriverServiceClient), mockPaymentClient)
+
+	ctx := context.Background()
+
+	// Expectations: bookings made before payments have none to capture
+	mockRepo.On("Complete", ctx, int32(1)).Return(&repository.Booking{ID: 1, Status: repository.StatusCompleted}, nil)
+	mockPaymentClient.On("CapturePayment", ctx, &paymentpb.CapturePaymentRequest{BookingId: 1}).
+		Return(nil, status.Error(codes.NotFound, "payment not found"))
+
+	// Action
+	resp, err := bookingServer.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: 1})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Nil(t, resp.Charged)
+}
+
+func TestCompleteBooking_Errors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		repoErr    error
+		captureErr error
+		expected   codes.Code
+	}{
+		{name: "Not Found", repoErr: errors.New("booking not found"), expected: codes.NotFound},
+		{name: "Not Confirmed", repoErr: errors.New("booking is not confirmed"), expected: codes.FailedPrecondition},
+		{name: "Database Error", repoErr: errors.New("database error"), expected: codes.Internal},
+		{name: "Capture Unavailable", captureErr: status.Error(codes.Unavailable, "payment provider timed out"), expected: codes.Unavailable},
+		{name: "Capture Rejected", captureErr: status.Error(codes.FailedPrecondition, "payment is VOIDED"), expected: codes.FailedPrecondition},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(mocks.BookingRepository)
+			mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+			bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), new(drivermocks.DriverServiceClient), mockPaymentClient)
+
+			ctx := context.Background()
+			if tc.repoErr != nil {
+				mockRepo.On("Complete", ctx, int32(1)).Return(nil, tc.repoErr)
+			} else {
+				mockRepo.On("Complete", ctx, int32(1)).Return(&repository.Booking{ID: 1, Status: repository.StatusCompleted}, nil)
+				mockPaymentClient.On("CapturePayment", ctx, mock.Anything).Return(nil, tc.captureErr)
+			}
+
+			// Action
+			resp, err := bookingServer.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: 1})
+
+			// Assertions
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.expected, status.Code(err))
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDisputeBooking_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), new(drivermocks.DriverServiceClient), mockPaymentClient)
+
+	ctx := context.Background()
+	refunded := &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15000}
+
+	// Expectations: the whole payment is refunded
+	mockRepo.On("Dispute", ctx, int32(1)).Return(&repository.Booking{ID: 1, Status: repository.StatusDisputed}, nil)
+	mockPaymentClient.On("RefundPayment", ctx, &paymentpb.RefundPaymentRequest{BookingId: 1, Reason: "driver never arrived"}).
+		Return(&paymentpb.Payment{Status: "REFUNDED", Refunded: refunded}, nil)
+
+	// Action
+	resp, err := bookingServer.DisputeBooking(ctx, &pb.DisputeBookingRequest{BookingId: 1, Reason: "driver never arrived"})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "Booking 1 refunded", resp.Message)
+	assert.Equal(t, refunded, resp.Refunded)
+	mockRepo.AssertExpectations(t)
+	mockPaymentClient.AssertExpectations(t)
+}
+
+func TestDisputeBooking_Errors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		req       *pb.DisputeBookingRequest
+		repoErr   error
+		refundErr error
+		expected  codes.Code
+	}{
+		{name: "Invalid ID", req: &pb.DisputeBookingRequest{Reason: "overcharged"}, expected: codes.InvalidArgument},
+		{name: "Missing Reason", req: &pb.DisputeBookingRequest{BookingId: 1}, expected: codes.InvalidArgument},
+		{name: "Not Completed", repoErr: errors.New("booking is not completed"), expected: codes.FailedPrecondition},
+		{name: "Not Found", repoErr: errors.New("booking not found"), expected: codes.NotFound},
+		{name: "Refund Unavailable", refundErr: status.Error(codes.Unavailable, "payment provider timed out"), expected: codes.Unavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(mocks.BookingRepository)
+			mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+			bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), new(drivermocks.DriverServiceClient), mockPaymentClient)
+
+			ctx := context.Background()
+			req := tc.req
+			if req == nil {
+				req = &pb.DisputeBookingRequest{BookingId: 1, Reason: "overcharged"}
+				if tc.repoErr != nil {
+					mockRepo.On("Dispute", ctx, int32(1)).Return(nil, tc.repoErr)
+				} else {
+					mockRepo.On("Dispute", ctx, int32(1)).Return(&repository.Booking{ID: 1, Status: repository.StatusDisputed}, nil)
+					mockPaymentClient.On("RefundPayment", ctx, mock.Anything).Return(nil, tc.refundErr)
+				}
+			}
+
+			// Action
+			resp, err := bookingServer.DisputeBooking(ctx, req)
+
+			// Assertions
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.expected, status.Code(err))
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// testPromotion takes 10% off, up to Rs 20.
+func testPromotion() *promotions.Promotion {
+	return &promotions.Promotion{
+		Code:        "WELCOME10",
+		Type:        promotions.TypePercent,
+		PercentBps:  1000,
+		MaxDiscount: money.Money{Currency: money.PKR, Minor: 2000},
+	}
+}
+
+func TestCreateBooking_PromoCode(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockPromotions := new(mocks.PromotionRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), mockPaymentClient,
+		WithPromotions(mockPromotions))
+
+	ctx := context.Background()
+	req := testBookingRequest()
+	req.PromoCode = "welcome10"
+
+	// Expectations: the code is redeemed against the quoted fare and the
+	// pickup's city, and the discounted fare is authorized
+	mockPromotions.On("GetByCode", ctx, "welcome10").Return(testPromotion(), nil)
+	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 1}).
+		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+	mockRideClient.On("CreateRide", ctx, mock.Anything).
+		Return(&ridepb.CreateRideResponse{RideId: 5, SourcePlaceId: "us-nyc"}, nil)
+	mockRepo.On("Create", ctx, int32(1), int32(5), mock.MatchedBy(func(r *repository.Redemption) bool {
+		return r.Code == "welcome10" && r.Fare == money.Money{Currency: money.PKR, Minor: 15000} &&
+			r.PickupPlaceID == "us-nyc" && !r.Now.IsZero()
+	})).Return(&repository.Booking{
+		ID:        10,
+		UserID:    1,
+		RideID:    5,
+		Status:    repository.StatusConfirmed,
+		PromoCode: "WELCOME10",
+		Discount:  money.Money{Currency: money.PKR, Minor: 1500},
+	}, nil)
+	mockPaymentClient.On("AuthorizePayment", ctx, &paymentpb.AuthorizePaymentRequest{
+		BookingId: 10,
+		UserId:    1,
+		Amount:    &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 13500},
+		Discount:  &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 1500},
+	}).Return(&paymentpb.Payment{Status: "AUTHORIZED"}, nil)
+
+	// Action
+	resp, err := bookingServer.CreateBooking(ctx, req)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "WELCOME10", resp.PromoCode)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 1500}, resp.Discount)
+
+	// Verify expectations
+	mockPromotions.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+	mockPaymentClient.AssertExpectations(t)
+}
+
+func TestCreateBooking_FreeRide(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockPromotions := new(mocks.PromotionRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), mockPaymentClient,
+		WithPromotions(mockPromotions))
+
+	ctx := context.Background()
+	req := testBookingRequest()
+	req.PromoCode = "FREERIDE"
+
+	// Expectations: a fare discounted to nothing holds nothing on the card, but
+	// still has a payment to credit the driver from
+	mockPromotions.On("GetByCode", ctx, "FREERIDE").Return(&promotions.Promotion{
+		Code:      "FREERIDE",
+		Type:      promotions.TypeFixed,
+		AmountOff: money.Money{Currency: money.PKR, Minor: 50000},
+	}, nil)
+	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 1}).
+		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+	mockRideClient.On("CreateRide", ctx, mock.Anything).Return(&ridepb.CreateRideResponse{RideId: 5}, nil)
+	mockRepo.On("Create", ctx, int32(1), int32(5), mock.Anything).Return(&repository.Booking{
+		ID:        10,
+		UserID:    1,
+		RideID:    5,
+		Status:    repository.StatusConfirmed,
+		PromoCode: "FREERIDE",
+		Discount:  money.Money{Currency: money.PKR, Minor: 15000},
+	}, nil)
+	mockPaymentClient.On("AuthorizePayment", ctx, &paymentpb.AuthorizePaymentRequest{
+		BookingId: 10,
+		UserId:    1,
+		Amount:    &moneypb.Money{CurrencyCode: "PKR"},
+		Discount:  &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15000},
+	}).Return(&paymentpb.Payment{Status: "AUTHORIZED"}, nil)
+
+	// Action
+	resp, err := bookingServer.CreateBooking(ctx, req)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15000), resp.Discount.MinorUnits)
+	mockPaymentClient.AssertExpectations(t)
+}
+
+func TestCreateBooking_PromoCodeErrors(t *testing.T) {
+	expired := testPromotion()
+	expired.EndsAt = time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name       string
+		noPromos   bool
+		promotion  *promotions.Promotion
+		getErr     error
+		redeemErr  error
+		expected   codes.Code
+		rideCalled bool
+	}{
+		{name: "Not Enabled", noPromos: true, expected: codes.FailedPrecondition},
+		{name: "Unknown Code", getErr: errors.New("promotion not found"), expected: codes.NotFound},
+		{name: "Lookup Failed", getErr: errors.New("database error"), expected: codes.Internal},
+		{name: "Expired", promotion: expired, expected: codes.FailedPrecondition},
+		{name: "Ineligible", promotion: testPromotion(), redeemErr: promotions.ErrFirstRideOnly, expected: codes.FailedPrecondition, rideCalled: true},
+		{name: "Deleted Since Lookup", promotion: testPromotion(), redeemErr: errors.New("promotion not found"), expected: codes.NotFound, rideCalled: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(mocks.BookingRepository)
+			mockPromotions := new(mocks.PromotionRepository)
+			mockUserClient := new(usermocks.UserServiceClient)
+			mockRideClient := new(ridemocks.RideServiceClient)
+			mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+
+			var opts []Option
+			if !tc.noPromos {
+				opts = append(opts, WithPromotions(mockPromotions))
+			}
+			bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), mockPaymentClient, opts...)
+
+			ctx := context.Background()
+			req := testBookingRequest()
+			req.PromoCode = "WELCOME10"
+
+			if !tc.noPromos {
+				mockPromotions.On("GetByCode", ctx, "WELCOME10").Return(tc.promotion, tc.getErr)
+			}
+			if tc.rideCalled {
+				mockUserClient.On("GetUser", ctx, mock.Anything).Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+				mockRideClient.On("CreateRide", ctx, mock.Anything).Return(&ridepb.CreateRideResponse{RideId: 5}, nil)
+				mockRepo.On("Create", ctx, int32(1), int32(5), mock.Anything).Return(nil, tc.redeemErr)
+			}
+
+			// Action
+			resp, err := bookingServer.CreateBooking(ctx, req)
+
+			// Assertions
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.expected, status.Code(err))
+			if !tc.rideCalled {
+				mockRideClient.AssertNotCalled(t, "CreateRide", mock.Anything, mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+			mockPaymentClient.AssertNotCalled(t, "AuthorizePayment", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetBooking_Discount(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("GetByID", ctx, int32(1)).Return(&repository.Booking{
+		ID:        1,
+		UserID:    2,
+		RideID:    3,
+		PromoCode: "WELCOME10",
+		Discount:  money.Money{Currency: money.PKR, Minor: 1505},
+	}, nil)
+	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 2}).
+		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+	mockRideClient.On("GetRide", ctx, &ridepb.GetRideRequest{RideId: 3}).
+		Return(&ridepb.Ride{RideId: 3, Price: &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15050}}, nil)
+
+	// Action
+	resp, err := bookingServer.GetBooking(ctx, &pb.GetBookingRequest{BookingId: 1})
+
+	// Assertions: the discount is itemized between price and total
+	assert.NoError(t, err)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15050}, resp.Price)
+	assert.Equal(t, "WELCOME10", resp.PromoCode)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 1505}, resp.Discount)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 13545}, resp.Total)
+}
+
+func TestCreatePromotion(t *testing.T) {
+	// Setup
+	mockPromotions := new(mocks.PromotionRepository)
+	bookingServer := NewBookingServer(new(mocks.BookingRepository), new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient),
+		new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient), WithPromotions(mockPromotions))
+
+	ctx := context.Background()
+	endsAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := &pb.CreatePromotionRequest{Promotion: &pb.Promotion{
+		Code:         " welcome10 ",
+		DiscountType: "PERCENT",
+		PercentBps:   1000,
+		MaxDiscount:  &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 2000},
+		EndsAt:       timestamppb.New(endsAt),
+		MaxPerUser:   1,
+	}}
+
+	// Expectations: the code is stored upper case
+	expected := testPromotion()
+	expected.EndsAt = endsAt
+	expected.MaxPerUser = 1
+	mockPromotions.On("Create", ctx, expected).Return(expected, nil)
+
+	// Action
+	resp, err := bookingServer.CreatePromotion(ctx, req)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "WELCOME10", resp.Code)
+	assert.Equal(t, int64(2000), resp.MaxDiscount.MinorUnits)
+	assert.Nil(t, resp.AmountOff)
+	assert.Nil(t, resp.StartsAt)
+	assert.Equal(t, endsAt, resp.EndsAt.AsTime())
+	mockPromotions.AssertExpectations(t)
+}
+
+func TestCreatePromotion_Errors(t *testing.T) {
+	testCases := []struct {
+		name      string
+		promotion *pb.Promotion
+		repoErr   error
+		expected  codes.Code
+	}{
+		{name: "Missing Promotion", expected: codes.InvalidArgument},
+		{name: "Unknown Type", promotion: &pb.Promotion{Code: "X", DiscountType: "BOGO"}, expected: codes.InvalidArgument},
+		{name: "Invalid Amount", promotion: &pb.Promotion{Code: "X", DiscountType: "FIXED",
+			AmountOff: &moneypb.Money{CurrencyCode: "XXX", MinorUnits: 100}}, expected: codes.InvalidArgument},
+		{name: "Already Exists", promotion: &pb.Promotion{Code: "X", DiscountType: "PERCENT", PercentBps: 500},
+			repoErr: errors.New("promotion already exists"), expected: codes.FailedPrecondition},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockPromotions := new(mocks.PromotionRepository)
+			bookingServer := NewBookingServer(new(mocks.BookingRepository), new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient),
+				new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient), WithPromotions(mockPromotions))
+
+			ctx := context.Background()
+			if tc.repoErr != nil {
+				mockPromotions.On("Create", ctx, mock.Anything).Return(nil, tc.repoErr)
+			}
+
+			// Action
+			resp, err := bookingServer.CreatePromotion(ctx, &pb.CreatePromotionRequest{Promotion: tc.promotion})
+
+			// Assertions
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.expected, status.Code(err))
+			mockPromotions.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPromotion_NotFound(t *testing.T) {
+	// Setup
+	mockPromotions := new(mocks.PromotionRepository)
+	bookingServer := NewBookingServer(new(mocks.BookingRepository), new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient),
+		new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient), WithPromotions(mockPromotions))
+
+	ctx := context.Background()
+	mockPromotions.On("GetByCode", ctx, "NOPE").Return(nil, errors.New("promotion not found"))
+
+	// Action
+	resp, err := bookingServer.GetPromotion(ctx, &pb.GetPromotionRequest{Code: "NOPE"})
+
+	// Assertions
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = bookingServer.GetPromotion(ctx, &pb.GetPromotionRequest{Code: " "})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// newRatingTestServer returns a server whose repository holds a completed
+// booking 1 of user 2 on ride 3 with driver 4.
+func newRatingTestServer(ctx context.Context, status string) (*BookingServer, *mocks.BookingRepository, *mocks.RatingRepository, *ridemocks.RideServiceClient) {
+	mockRepo := new(mocks.BookingRepository)
+	mockRatings := new(mocks.RatingRepository)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), mockRideClient, new(drivermocks.DriverServiceClient),
+		new(paymentmocks.PaymentServiceClient), WithRatings(mockRatings))
+
+	mockRepo.On("GetByID", ctx, int32(1)).
+		Return(&repository.Booking{ID: 1, UserID: 2, RideID: 3, DriverID: 4, Status: status}, nil)
+	return bookingServer, mockRepo, mockRatings, mockRideClient
+}
+
+func TestRateBooking_Success(t *testing.T) {
+	// Setup
+	ctx := context.Background()
+	bookingServer, mockRepo, mockRatings, mockRideClient := newRatingTestServer(ctx, repository.StatusCompleted)
+
+	// Expectations: the rating is normalized and carries the ride's route
+	mockRideClient.On("GetRide", ctx, &ridepb.GetRideRequest{RideId: 3}).
+		Return(&ridepb.Ride{RideId: 3, SourcePlaceId: "pk-khi", DestinationPlaceId: "pk-lhe"}, nil)
+	mockRatings.On("Rate", ctx, &ratings.Rating{
+		BookingID: 1,
+		UserID:    2,
+		Route:     ratings.Route{SourcePlaceID: "pk-khi", DestinationPlaceID: "pk-lhe"},
+		Stars:     5,
+		Comment:   "Great driver",
+		Tags:      []string{"FRIENDLY", "ON_TIME"},
+	}).Return(&ratings.Rating{
+		BookingID: 1,
+		UserID:    2,
+		DriverID:  4,
+		Stars:     5,
+		Comment:   "Great driver",
+		Tags:      []string{"FRIENDLY", "ON_TIME"},
+		CreatedAt: testCreatedAt,
+	}, nil)
+
+	// Action
+	resp, err := bookingServer.RateBooking(ctx, &pb.RateBookingRequest{
+		BookingId: 1,
+		UserId:    2,
+		Stars:     5,
+		Comment:   " Great driver ",
+		Tags:      []string{"on_time", "friendly"},
+	})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), resp.DriverId)
+	assert.Equal(t, []string{"FRIENDLY", "ON_TIME"}, resp.Tags)
+	assert.Equal(t, testCreatedAt, resp.CreatedAt.AsTime())
+
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+	mockRatings.AssertExpectations(t)
+	mockRideClient.AssertExpectations(t)
+}
+
+func TestRateBooking_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		req      *pb.RateBookingRequest
+		status   string
+		rateErr  error
+		expected codes.Code
+	}{
+		{name: "Invalid Booking ID", req: &pb.RateBookingRequest{UserId: 2, Stars: 5}, expected: codes.InvalidArgument},
+		{name: "Invalid User ID", req: &pb.RateBookingRequest{BookingId: 1, Stars: 5}, expected: codes.InvalidArgument},
+		{name: "No Stars", req: &pb.RateBookingRequest{BookingId: 1, UserId: 2}, expected: codes.InvalidArgument},
+		{name: "Unknown Tag", req: &pb.RateBookingRequest{BookingId: 1, UserId: 2, Stars: 5, Tags: []string{"FAST"}}, expected: codes.InvalidArgument},
+		{name: "Another User's Booking", req: &pb.RateBookingRequest{BookingId: 1, UserId: 9, Stars: 5}, status: repository.StatusCompleted,
+			expected: codes.PermissionDenied},
+		{name: "Not Completed", status: repository.StatusConfirmed, expected: codes.FailedPrecondition},
+		{name: "Disputed", status: repository.StatusDisputed, expected: codes.FailedPrecondition},
+		{name: "Already Rated", status: repository.StatusCompleted, rateErr: errors.New("booking already rated"), expected: codes.FailedPrecondition},
+		{name: "Database Error", status: repository.StatusCompleted, rateErr: errors.New("database error"), expected: codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			ctx := context.Background()
+			bookingServer, _, mockRatings, mockRideClient := newRatingTestServer(ctx, tc.status)
+
+			req := tc.req
+			if req == nil {
+				req = &pb.RateBookingRequest{BookingId: 1, UserId: 2, Stars: 5}
+			}
+			if tc.rateErr != nil {
+				mockRideClient.On("GetRide", ctx, mock.Anything).Return(&ridepb.Ride{RideId: 3}, nil)
+				mockRatings.On("Rate", ctx, mock.Anything).Return(nil, tc.rateErr)
+			}
+
+			// Action
+			resp, err := bookingServer.RateBooking(ctx, req)
+
+			// Assertions
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.expected, status.Code(err))
+			mockRatings.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetRatingStats(t *testing.T) {
+	testCases := []struct {
+		name     string
+		req      *pb.GetRatingStatsRequest
+		subject  string
+		expected codes.Code
+	}{
+		{name: "Driver", req: &pb.GetRatingStatsRequest{DriverId: 4}, subject: "driver:4"},
+		{name: "Route", req: &pb.GetRatingStatsRequest{SourcePlaceId: "pk-khi", DestinationPlaceId: "pk-lhe"}, subject: "route:pk-khi:pk-lhe"},
+		{name: "No Subject", req: &pb.GetRatingStatsRequest{}, expected: codes.InvalidArgument},
+		{name: "Half A Route", req: &pb.GetRatingStatsRequest{SourcePlaceId: "pk-khi"}, expected: codes.InvalidArgument},
+		{name: "Driver And Route", req: &pb.GetRatingStatsRequest{DriverId: 4, SourcePlaceId: "pk-khi", DestinationPlaceId: "pk-lhe"},
+			expected: codes.InvalidArgument},
+		{name: "Negative Driver", req: &pb.GetRatingStatsRequest{DriverId: -1}, expected: codes.InvalidArgument},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRatings := new(mocks.RatingRepository)
+			bookingServer := NewBookingServer(new(mocks.BookingRepository), new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient),
+				new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient), WithRatings(mockRatings))
+
+			ctx := context.Background()
+			if tc.subject != "" {
+				mockRatings.On("GetStats", ctx, tc.subject).Return(&ratings.Stats{
+					Count: 3,
+					Stars: [5]int64{0, 0, 0, 1, 2},
+					Tags:  map[string]int64{"ON_TIME": 2},
+				}, nil)
+			}
+
+			// Action
+			resp, err := bookingServer.GetRatingStats(ctx, tc.req)
+
+			// Assertions
+			assert.Equal(t, tc.expected, status.Code(err))
+			if tc.expected == codes.OK {
+				assert.Equal(t, int64(3), resp.Count)
+				assert.InDelta(t, 4.67, resp.Average, 0.01)
+				assert.Equal(t, []int64{0, 0, 0, 1, 2}, resp.Stars)
+				assert.Equal(t, map[string]int64{"ON_TIME": 2}, resp.Tags)
+			}
+			mockRatings.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCompleteBooking_IssuesReceipt(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockReceipts := new(mocks.ReceiptRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient), mockPaymentClient,
+		WithReceipts(mockReceipts, 1600))
+
+	ctx := context.Background()
+
+	// Expectations: the receipt is itemized from the user, ride and discount
+	mockRepo.On("Complete", ctx, int32(1)).Return(&repository.Booking{
+		ID:        1,
+		UserID:    2,
+		RideID:    3,
+		Status:    repository.StatusCompleted,
+		PromoCode: "WELCOME10",
+		Discount:  money.Money{Currency: money.PKR, Minor: 1500},
+		UpdatedAt: testCreatedAt,
+	}, nil)
+	mockPaymentClient.On("CapturePayment", ctx, mock.Anything).
+		Return(&paymentpb.Payment{Status: "CAPTURED"}, nil)
+	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 2}).
+		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+	mockRideClient.On("GetRide", ctx, &ridepb.GetRideRequest{RideId: 3}).
+		Return(&ridepb.Ride{RideId: 3, Source: "New York", Destination: "Boston", Distance: 200, VehicleClass: "ECONOMY",
+			Price: &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15000},
+			FareBreakdown: &ridepb.FareBreakdown{
+				BaseFare:              &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 5000},
+				DistanceFare:          &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 10000},
+				MinimumFareAdjustment: &moneypb.Money{CurrencyCode: "PKR"},
+				TimeOfDayAdjustment:   &moneypb.Money{CurrencyCode: "PKR"},
+				SurgeAdjustment:       &moneypb.Money{CurrencyCode: "PKR"},
+			}}, nil)
+	mockReceipts.On("Issue", ctx, mock.MatchedBy(func(r *receipts.Receipt) bool {
+		return r.BookingID == 1 && r.UserName == "John Doe" && r.Source == "New York" && r.CompletedAt.Equal(testCreatedAt) &&
+			assert.ObjectsAreEqual([]receipts.LineItem{
+				{Description: "Base fare (ECONOMY)", Amount: money.Money{Currency: money.PKR, Minor: 4310}},
+				{Description: "Distance (200 km)", Amount: money.Money{Currency: money.PKR, Minor: 8621}},
+				{Description: "Promo code WELCOME10", Amount: money.Money{Currency: money.PKR, Minor: -1293}},
+				{Description: "Tax (16%)", Amount: money.Money{Currency: money.PKR, Minor: 1862}},
+			}, r.Items) &&
+			r.Total == money.Money{Currency: money.PKR, Minor: 13500} && r.TaxRateBps == 1600 &&
+			!r.IssuedAt.IsZero()
+	})).Return(&receipts.Receipt{InvoiceNumber: "INV-202301-000001"}, nil)
+
+	// Action
+	_, err := bookingServer.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: 1})
+
+	// Assertions
+	assert.NoError(t, err)
+	mockReceipts.AssertExpectations(t)
+}
+
+func TestCompleteBooking_ReceiptFailure(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockPaymentClient := new(paymentmocks.PaymentServiceClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, new(ridemocks.RideServiceClient), new(drivermocks.DriverServiceClient), mockPaymentClient,
+		WithReceipts(new(mocks.ReceiptRepository), 0))
+
+	ctx := context.Background()
+
+	// Expectations: the booking completes even though user-service is down
+	mockRepo.On("Complete", ctx, int32(1)).Return(&repository.Booking{ID: 1, UserID: 2, Status: repository.StatusCompleted}, nil)
+	mockPaymentClient.On("CapturePayment", ctx, mock.Anything).Return(&paymentpb.Payment{Status: "CAPTURED"}, nil)
+	mockUserClient.On("GetUser", ctx, mock.Anything).Return(nil, status.Error(codes.Unavailable, "user-service is down"))
+
+	// Action
+	resp, err := bookingServer.CompleteBooking(ctx, &pb.CompleteBookingRequest{BookingId: 1})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "Booking 1 completed successfully", resp.Message)
+}
+
+func TestGetReceipt_Issued(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockReceipts := new(mocks.ReceiptRepository)
+	bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient), new(drivermocks.DriverServiceClient),
+		new(paymentmocks.PaymentServiceClient), WithReceipts(mockReceipts, 1600))
+
+	ctx := context.Background()
+	receipt, err := receipts.New(receipts.Trip{
+		BookingID:    1,
+		UserName:     "John Doe",
+		Source:       "New York",
+		Destination:  "Boston",
+		Distance:     200,
+		VehicleClass: "ECONOMY",
+		Fare:         money.Money{Currency: money.PKR, Minor: 15000},
+	}, 1600)
+	assert.NoError(t, err)
+	receipt.InvoiceNumber = "INV-202301-000001"
+	receipt.IssuedAt = testCreatedAt
+
+	// Expectations: an issued receipt is returned as it was issued
+	mockReceipts.On("GetByBookingID", ctx, int32(1)).Return(receipt, nil)
+
+	// Action
+	resp, err := bookingServer.GetReceipt(ctx, &pb.GetReceiptRequest{BookingId: 1})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "INV-202301-000001", resp.InvoiceNumber)
+	assert.Equal(t, testCreatedAt, resp.IssuedAt.AsTime())
+	assert.Equal(t, "John Doe", resp.UserName)
+	assert.Equal(t, []*pb.ReceiptItem{{
+		Description: "Ride fare (ECONOMY, 200 km)",
+		Amount:      &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 12931},
+	}, {
+		Description: "Tax (16%)",
+		Amount:      &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 2069},
+	}}, resp.Items)
+	assert.Equal(t, int64(15000), resp.Total.MinorUnits)
+	assert.Equal(t, int64(2069), resp.Tax.MinorUnits)
+	assert.Contains(t, resp.Html, "INV-202301-000001")
+	assert.Equal(t, "%PDF-", string(resp.Pdf[:5]))
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestGetReceipt_IssuesCompletedBooking(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.BookingRepository)
+	mockReceipts := new(mocks.ReceiptRepository)
+	mockUserClient := new(usermocks.UserServiceClient)
+	mockRideClient := new(ridemocks.RideServiceClient)
+	bookingServer := NewBookingServer(mockRepo, mockUserClient, mockRideClient, new(drivermocks.DriverServiceClient),
+		new(paymentmocks.PaymentServiceClient), WithReceipts(mockReceipts, 0))
+
+	ctx := context.Background()
+
+	// Expectations: a receipt not issued on completion is issued now
+	mockReceipts.On("GetByBookingID", ctx, int32(1)).Return(nil, errors.New("receipt not found"))
+	mockRepo.On("GetByID", ctx, int32(1)).Return(&repository.Booking{ID: 1, UserID: 2, RideID: 3, Status: repository.StatusCompleted}, nil)
+	mockUserClient.On("GetUser", ctx, &userpb.GetUserRequest{UserId: 2}).
+		Return(&userpb.GetUserResponse{Name: "John Doe"}, nil)
+	mockRideClient.On("GetRide", ctx, &ridepb.GetRideRequest{RideId: 3}).
+		Return(&ridepb.Ride{RideId: 3, Price: &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15000}}, nil)
+	mockReceipts.On("Issue", ctx, mock.Anything).Return(func(ctx context.Context, r *receipts.Receipt) *receipts.Receipt {
+		issued := *r
+		issued.InvoiceNumber = "INV-202301-000002"
+		return &issued
+	}, nil)
+
+	// Action
+	resp, err := bookingServer.GetReceipt(ctx, &pb.GetReceiptRequest{BookingId: 1})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "INV-202301-000002", resp.InvoiceNumber)
+	assert.Equal(t, "John Doe", resp.UserName)
+	mockReceipts.AssertExpectations(t)
+}
+
+func TestGetReceipt_Errors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		noReceipts bool
+		bookingID  int32
+		status     string
+		getErr     error
+		expected   codes.Code
+	}{
+		{name: "Not Enabled", noReceipts: true, bookingID: 1, expected: codes.FailedPrecondition},
+		{name: "Invalid ID", expected: codes.InvalidArgument},
+		{name: "Not Completed", bookingID: 1, status: repository.StatusConfirmed, expected: codes.FailedPrecondition},
+		{name: "Booking Not Found", bookingID: 1, getErr: errors.New("booking not found"), expected: codes.NotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(mocks.BookingRepository)
+			mockReceipts := new(mocks.ReceiptRepository)
+			var opts []Option
+			if !tc.noReceipts {
+				opts = append(opts, WithReceipts(mockReceipts, 0))
+			}
+			bookingServer := NewBookingServer(mockRepo, new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient),
+				new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient), opts...)
+
+			ctx := context.Background()
+			mockReceipts.On("GetByBookingID", ctx, int32(1)).Return(nil, errors.New("receipt not found"))
+			if tc.getErr != nil {
+				mockRepo.On("GetByID", ctx, int32(1)).Return(nil, tc.getErr)
+			} else {
+				mockRepo.On("GetByID", ctx, int32(1)).Return(&repository.Booking{ID: 1, Status: tc.status}, nil)
+			}
+
+			// Action
+			resp, err := bookingServer.GetReceipt(ctx, &pb.GetReceiptRequest{BookingId: tc.bookingID})
+
+			// Assertions
+			assert.Nil(t, resp)
+			assert.Equal(t, tc.expected, status.Code(err))
+			mockReceipts.AssertNotCalled(t, "Issue", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestQueryAuditLog(t *testing.T) {
+	// Setup
+	mockLog := new(auditmocks.Log)
+	bookingServer := NewBookingServer(new(mocks.BookingRepository), new(usermocks.UserServiceClient), new(ridemocks.RideServiceClient),
+		new(drivermocks.DriverServiceClient), new(paymentmocks.PaymentServiceClient),
+		WithAuditLog(mockLog))
+
+	ctx := audit.WithActor(context.Background(), audit.Admin)
+	createdAt := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	mockLog.On("Query", ctx, audit.Filter{Method: "CreateBooking", Limit: 50}).Return([]*audit.Entry{{
+		ID:        9,
+		Method:    "CreateBooking",
+		Actor:     audit.Anonymous,
+		RequestID: "req-7",
+		After:     []byte(`{"booking_id":4,"status":"CONFIRMED"}`),
+		CreatedAt: createdAt,
+		PrevHash:  "dd",
+		Hash:      "ee",
+	}}, nil)
+	mockLog.On("Verify", ctx).Return(int64(9), nil)
+
+	// Execute
+	res, err := bookingServer.QueryAuditLog(ctx, &auditpb.QueryAuditLogRequest{Method: "CreateBooking", Verify: true})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, res.Entries, 1)
+	assert.Equal(t, "req-7", res.Entries[0].RequestId)
+	assert.False(t, res.ChainIntact)
+	assert.Equal(t, int64(9), res.FirstBrokenEntryId)
+
+	_, err = bookingServer.QueryAuditLog(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-actor-id", audit.Admin)),
+		&auditpb.QueryAuditLogRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
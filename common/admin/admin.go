@@ -0,0 +1,180 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+)
+
+// Version and Commit identify the build. The Dockerfiles set them with
+// -ldflags "-X github.com/hasnain-zafar/go-microservices/common/admin.Version=...".
+var (
+	Version = "dev"
+	Commit  = ""
+)
+
+// BuildInfo describes the running binary.
+type BuildInfo struct {
+	Service   string `json:"service"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"go_version"`
+}
+
+// Build returns the build info of service. Without a Commit set at build
+// time, the commit go build recorded from the repository is used, if any.
+func Build(service string) BuildInfo {
+	info := BuildInfo{Service: service, Version: Version, Commit: Commit, GoVersion: runtime.Version()}
+	if info.Commit == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range bi.Settings {
+				if setting.Key == "vcs.revision" {
+					info.Commit = setting.Value
+				}
+			}
+		}
+	}
+	return info
+}
+
+// Server is a service's admin HTTP server. /metrics is open for Prometheus;
+// the debug endpoints under /admin/ and /debug/pprof/ require the admin
+// token as a bearer token and are disabled when it is empty.
+type Server struct {
+	service  string
+	token    string
+	registry *metrics.Registry
+	config   any
+	db       *sql.DB
+	conns    *ConnTracker
+}
+
+// Option configures the optional endpoints of a Server.
+type Option func(*Server)
+
+// WithConfig serves cfg, with its secrets masked, on /admin/config.
+func WithConfig(cfg any) Option {
+	return func(s *Server) {
+		s.config = cfg
+	}
+}
+
+// WithDB serves the connection pool stats of db on /admin/db.
+func WithDB(db *sql.DB) Option {
+	return func(s *Server) {
+		s.db = db
+	}
+}
+
+// WithConnections serves the gRPC connections conns tracks on
+// /admin/connections.
+func WithConnections(conns *ConnTracker) Option {
+	return func(s *Server) {
+		s.conns = conns
+	}
+}
+
+func NewServer(service, token string, registry *metrics.Registry, opts ...Option) *Server {
+	s := &Server{service: service, token: token, registry: registry}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.registry.Handler())
+
+	admin := http.NewServeMux()
+	admin.HandleFunc("/debug/pprof/", pprof.Index)
+	admin.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	admin.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	admin.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	admin.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	admin.HandleFunc("GET /admin/build", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, Build(s.service))
+	})
+	admin.Handle("/admin/log-level", logger.LevelHandler())
+	if s.config != nil {
+		admin.HandleFunc("GET /admin/config", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, MaskSecrets(s.config))
+		})
+	}
+	if s.db != nil {
+		admin.HandleFunc("GET /admin/db", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, poolStats(s.db.Stats()))
+		})
+	}
+	if s.conns != nil {
+		admin.HandleFunc("GET /admin/connections", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, s.conns.Conns())
+		})
+	}
+	mux.Handle("/debug/pprof/", s.requireToken(admin))
+	mux.Handle("/admin/", s.requireToken(admin))
+	return mux
+}
+
+func (s *Server) ListenAndServe(port int) error {
+	return http.ListenAndServe(fmt.Sprintf(":%d", port), s.Handler())
+}
+
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token == "" {
+			http.Error(w, "admin endpoints are disabled", http.StatusForbidden)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid admin token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// PoolStats are the sql.DBStats of a connection pool.
+type PoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+func poolStats(stats sql.DBStats) PoolStats {
+	return PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,107 @@
+package admin
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Masked replaces the value of secret config fields.
+const Masked = "****"
+
+// secretNames are the words that mark a config field as secret.
+var secretNames = []string{"password", "secret", "token", "key"}
+
+// MaskSecrets returns cfg as a map, for a config dump: non-empty fields
+// whose names contain a secret word are replaced with Masked, and so are
+// the passwords in URLs such as the database URL.
+func MaskSecrets(cfg any) any {
+	return mask(reflect.ValueOf(cfg), false)
+}
+
+func mask(v reflect.Value, secret bool) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	if t, ok := v.Interface().(fmt.Stringer); ok && v.Kind() != reflect.Struct && v.Kind() != reflect.Pointer {
+		if secret {
+			return Masked
+		}
+		return t.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return mask(v.Elem(), secret)
+	case reflect.Struct:
+		out := map[string]any{}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			out[field.Name] = mask(v.Field(i), secret || isSecretName(field.Name))
+		}
+		return out
+	case reflect.Map:
+		out := map[string]any{}
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			out[key] = mask(iter.Value(), secret || isSecretName(key))
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if secret {
+			return Masked
+		}
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = mask(v.Index(i), false)
+		}
+		return out
+	case reflect.String:
+		if secret && v.String() != "" {
+			return Masked
+		}
+		return maskURL(v.String())
+	default:
+		if secret && !v.IsZero() {
+			return Masked
+		}
+		return v.Interface()
+	}
+}
+
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range secretNames {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskURL masks the password of s if it is a URL with one.
+func maskURL(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/stats"
+)
+
+// Conn is a gRPC connection to the service.
+type Conn struct {
+	RemoteAddr  string    `json:"remote_addr"`
+	LocalAddr   string    `json:"local_addr"`
+	ConnectedAt time.Time `json:"connected_at"`
+	// ActiveRPCs are the calls in flight on the connection and TotalRPCs
+	// every call made on it.
+	ActiveRPCs int64 `json:"active_rpcs"`
+	TotalRPCs  int64 `json:"total_rpcs"`
+}
+
+type connIDKey struct{}
+
+// ConnTracker is a gRPC stats.Handler that keeps the server's open
+// connections. Install it with grpc.StatsHandler.
+type ConnTracker struct {
+	mu     sync.Mutex
+	nextID int64
+	conns  map[int64]*Conn
+	now    func() time.Time
+}
+
+func NewConnTracker() *ConnTracker {
+	return &ConnTracker{conns: map[int64]*Conn{}, now: time.Now}
+}
+
+// Conns returns the open connections, oldest first.
+func (t *ConnTracker) Conns() []Conn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	conns := make([]Conn, 0, len(t.conns))
+	for _, c := range t.conns {
+		conns = append(conns, *c)
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].ConnectedAt.Before(conns[j].ConnectedAt) })
+	return conns
+}
+
+func (t *ConnTracker) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	c := &Conn{ConnectedAt: t.now()}
+	if info.RemoteAddr != nil {
+		c.RemoteAddr = info.RemoteAddr.String()
+	}
+	if info.LocalAddr != nil {
+		c.LocalAddr = info.LocalAddr.String()
+	}
+	t.conns[t.nextID] = c
+	return context.WithValue(ctx, connIDKey{}, t.nextID)
+}
+
+func (t *ConnTracker) HandleConn(ctx context.Context, s stats.ConnStats) {
+	if _, ok := s.(*stats.ConnEnd); !ok {
+		return
+	}
+	if id, ok := ctx.Value(connIDKey{}).(int64); ok {
+		t.mu.Lock()
+		delete(t.conns, id)
+		t.mu.Unlock()
+	}
+}
+
+func (t *ConnTracker) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+// HandleRPC counts calls on the connection whose context the call's derives
+// from.
+func (t *ConnTracker) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	id, ok := ctx.Value(connIDKey{}).(int64)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.conns[id]
+	if !ok {
+		return
+	}
+	switch s.(type) {
+	case *stats.Begin:
+		c.ActiveRPCs++
+		c.TotalRPCs++
+	case *stats.End:
+		c.ActiveRPCs--
+	}
+}
@@ -0,0 +1,158 @@
+// Package audit keeps an append-only log of the mutating calls a service
+// handles: who made the call, from where, and the entity before and after it.
+// Entries are chained by hash, so an entry that is edited or removed after the
+// fact no longer matches the entries that follow it.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// RequestIDHeader is the metadata key a caller identifies its request with.
+const RequestIDHeader = logger.RequestIDHeader
+
+// Entry is one audited call. Before and After are JSON snapshots of the
+// entity, nil for the side that did not exist.
+type Entry struct {
+	ID        int64
+	Method    string
+	Actor     string
+	RequestID string
+	Peer      string
+	Before    json.RawMessage
+	After     json.RawMessage
+	CreatedAt time.Time
+	PrevHash  string
+	Hash      string
+}
+
+// hashed is what an entry's hash covers, in a fixed field order.
+type hashed struct {
+	PrevHash  string          `json:"prev_hash"`
+	Method    string          `json:"method"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id"`
+	Peer      string          `json:"peer"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// ComputeHash returns the hex SHA-256 of e's fields and PrevHash.
+func (e *Entry) ComputeHash() (string, error) {
+	data, err := json.Marshal(hashed{
+		PrevHash:  e.PrevHash,
+		Method:    e.Method,
+		Actor:     e.Actor,
+		RequestID: e.RequestID,
+		Peer:      e.Peer,
+		Before:    e.Before,
+		After:     e.After,
+		CreatedAt: e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// FirstBroken returns the ID of the first of entries, in ascending ID order,
+// that does not follow prevHash or whose hash does not match its fields, or 0
+// if the chain holds.
+func FirstBroken(prevHash string, entries []*Entry) int64 {
+	for _, e := range entries {
+		if e.PrevHash != prevHash {
+			return e.ID
+		}
+		hash, err := e.ComputeHash()
+		if err != nil || hash != e.Hash {
+			return e.ID
+		}
+		prevHash = e.Hash
+	}
+	return 0
+}
+
+// NewEntry builds an entry for method from the authenticated caller in ctx and
+// the snapshots before and after the call. Nil snapshots are left empty.
+func NewEntry(ctx context.Context, method string, before, after any, now time.Time) (*Entry, error) {
+	e := &Entry{
+		Method:    method,
+		Actor:     Actor(ctx),
+		CreatedAt: now.UTC().Truncate(time.Microsecond),
+	}
+	// The request ID is taken from the caller's metadata unless the call
+	// was tagged with one by logger.UnaryServerInterceptor
+	if e.RequestID = logger.RequestID(ctx); e.RequestID == "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(RequestIDHeader); len(v) > 0 {
+				e.RequestID = v[0]
+			}
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		e.Peer = p.Addr.String()
+	}
+
+	var err error
+	if e.Before, err = snapshot(before); err != nil {
+		return nil, fmt.Errorf("marshal %s before snapshot: %w", method, err)
+	}
+	if e.After, err = snapshot(after); err != nil {
+		return nil, fmt.Errorf("marshal %s after snapshot: %w", method, err)
+	}
+	return e, nil
+}
+
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// Record appends an entry for method to the audit_log table as part of tx, so
+// the entry is persisted if and only if the call's state change commits.
+// Appends lock the one-row audit_log_head table, which holds the latest
+// entry's hash, until tx ends, so that each entry chains onto the one
+// committed before it while reads of the log carry on.
+func Record(ctx context.Context, tx *sql.Tx, method string, before, after any) error {
+	e, err := NewEntry(ctx, method, before, after, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := tx.QueryRowContext(ctx, `SELECT hash FROM audit_log_head FOR UPDATE`).Scan(&e.PrevHash); err != nil {
+		return fmt.Errorf("lock audit log head: %w", err)
+	}
+	if e.Hash, err = e.ComputeHash(); err != nil {
+		return fmt.Errorf("hash %s audit entry: %w", method, err)
+	}
+
+	query := `INSERT INTO audit_log (method, actor, request_id, peer, before, after, created_at, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	if _, err := tx.ExecContext(ctx, query, e.Method, e.Actor, e.RequestID, e.Peer,
+		nullJSON(e.Before), nullJSON(e.After), e.CreatedAt, e.PrevHash, e.Hash); err != nil {
+		return fmt.Errorf("record %s audit entry: %w", method, err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE audit_log_head SET hash = $1`, e.Hash); err != nil {
+		return fmt.Errorf("advance audit log head: %w", err)
+	}
+	return nil
+}
+
+func nullJSON(v json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(v), Valid: v != nil}
+}
@@ -0,0 +1,292 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	auditpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var testNow = time.Date(2026, 10, 19, 9, 30, 0, 123456789, time.UTC)
+
+func callerContext(actor, requestID string) context.Context {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, requestID))
+	return peer.NewContext(WithActor(ctx, actor), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 41234}})
+}
+
+type user struct {
+	UserID int32  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func TestNewEntry(t *testing.T) {
+	e, err := NewEntry(callerContext("admin-1", "req-42"), "DeleteUser", user{UserID: 42, Name: "Ali"}, nil, testNow)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DeleteUser", e.Method)
+	assert.Equal(t, "admin-1", e.Actor)
+	assert.Equal(t, "req-42", e.RequestID)
+	assert.Equal(t, "10.0.0.7:41234", e.Peer)
+	assert.JSONEq(t, `{"user_id": 42, "name": "Ali"}`, string(e.Before))
+	assert.Nil(t, e.After)
+	// Stored timestamps keep microseconds, so the hash must not cover more
+	assert.Equal(t, testNow.Truncate(time.Microsecond), e.CreatedAt)
+}
+
+func TestNewEntry_Anonymous(t *testing.T) {
+	// An actor claimed in the metadata is not an authenticated identity
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-actor-id", "admin-1"))
+	e, err := NewEntry(ctx, "CreateUser", nil, user{UserID: 4, Name: "Sara"}, testNow)
+	require.NoError(t, err)
+
+	assert.Equal(t, Anonymous, e.Actor)
+	assert.Empty(t, e.RequestID)
+	assert.Empty(t, e.Peer)
+	assert.Nil(t, e.Before)
+}
+
+// chain builds n linked entries with IDs 1..n.
+func chain(t *testing.T, n int) []*Entry {
+	t.Helper()
+	var entries []*Entry
+	prevHash := ""
+	for i := 1; i <= n; i++ {
+		e, err := NewEntry(callerContext("admin-1", ""), "CreateUser", nil, user{UserID: int32(i)}, testNow.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+		e.ID = int64(i)
+		e.PrevHash = prevHash
+		e.Hash, err = e.ComputeHash()
+		require.NoError(t, err)
+		prevHash = e.Hash
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestComputeHash(t *testing.T) {
+	e := chain(t, 1)[0]
+
+	again, err := e.ComputeHash()
+	require.NoError(t, err)
+	assert.Equal(t, e.Hash, again)
+	assert.Len(t, e.Hash, 64)
+
+	// The hash covers every field and the previous hash
+	for name, tamper := range map[string]func(e *Entry){
+		"actor":      func(e *Entry) { e.Actor = "someone-else" },
+		"after":      func(e *Entry) { e.After = json.RawMessage(`{"user_id":99}`) },
+		"created at": func(e *Entry) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) },
+		"prev hash":  func(e *Entry) { e.PrevHash = "00" },
+	} {
+		tampered := *e
+		tamper(&tampered)
+		hash, err := tampered.ComputeHash()
+		require.NoError(t, err)
+		assert.NotEqual(t, e.Hash, hash, name)
+	}
+}
+
+func TestFirstBroken(t *testing.T) {
+	assert.Zero(t, FirstBroken("", chain(t, 3)))
+	assert.Zero(t, FirstBroken("", nil))
+
+	// Continuing a verified prefix
+	entries := chain(t, 3)
+	assert.Zero(t, FirstBroken(entries[0].Hash, entries[1:]))
+
+	// An edited entry
+	entries = chain(t, 3)
+	entries[1].Actor = "someone-else"
+	assert.Equal(t, int64(2), FirstBroken("", entries))
+
+	// An edited entry whose hash was recomputed breaks the next link
+	entries = chain(t, 3)
+	entries[1].Actor = "someone-else"
+	entries[1].Hash, _ = entries[1].ComputeHash()
+	assert.Equal(t, int64(3), FirstBroken("", entries))
+
+	// A removed entry
+	entries = chain(t, 3)
+	assert.Equal(t, int64(3), FirstBroken("", []*Entry{entries[0], entries[2]}))
+}
+
+func TestAuthenticator(t *testing.T) {
+	withToken := func(values ...string) context.Context {
+		md := metadata.MD{}
+		for _, v := range values {
+			md.Append(AuthorizationHeader, v)
+		}
+		return metadata.NewIncomingContext(context.Background(), md)
+	}
+
+	auth := NewAuthenticator("s3cret")
+	assert.Equal(t, Admin, auth.Authenticate(withToken("Bearer s3cret")))
+	assert.Equal(t, Admin, auth.Authenticate(withToken("Bearer wrong", "Bearer s3cret")))
+	assert.Equal(t, Anonymous, auth.Authenticate(withToken("Bearer wrong")))
+	assert.Equal(t, Anonymous, auth.Authenticate(withToken("s3cret")))
+	assert.Equal(t, Anonymous, auth.Authenticate(context.Background()))
+	assert.Equal(t, Anonymous, auth.Authenticate(metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("x-actor-id", Admin))))
+
+	// Without a token nobody is an admin
+	assert.Equal(t, Anonymous, NewAuthenticator("").Authenticate(withToken("Bearer ")))
+
+	// Other services are recorded by the name they send, which can never pass
+	// for an admin
+	fromService := func(name string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(CallerHeader, name))
+	}
+	assert.Equal(t, "service:booking-service", auth.Authenticate(fromService("booking-service")))
+	assert.Equal(t, Anonymous, auth.Authenticate(fromService("Booking Service")))
+	assert.Equal(t, Anonymous, auth.Authenticate(fromService("")))
+	assert.NotEqual(t, Admin, auth.Authenticate(fromService(Admin)))
+	assert.Equal(t, Admin, auth.Authenticate(metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs(AuthorizationHeader, "Bearer s3cret", CallerHeader, "booking-service"))))
+
+	// The interceptor tags the call with the authenticated actor
+	var actor string
+	var admin bool
+	handler := func(ctx context.Context, req any) (any, error) {
+		actor, admin = Actor(ctx), IsAdmin(ctx)
+		return nil, nil
+	}
+	_, err := auth.UnaryServerInterceptor()(withToken("Bearer s3cret"), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, Admin, actor)
+	assert.True(t, admin)
+
+	_, err = auth.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, Anonymous, actor)
+	assert.False(t, admin)
+}
+
+func TestWithUser(t *testing.T) {
+	ctx := WithActor(context.Background(), ServiceActor("booking-service"))
+	assert.Equal(t, "user:42", Actor(WithUser(ctx, 42)))
+	assert.False(t, IsAdmin(WithUser(ctx, 42)))
+
+	// An admin acting for a user stays the actor
+	admin := WithActor(context.Background(), Admin)
+	assert.Equal(t, Admin, Actor(WithUser(admin, 42)))
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	var sent metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		sent, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	err := UnaryClientInterceptor("booking-service")(context.Background(), "/ride.RideService/CreateRide", nil, nil, nil, invoker)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"booking-service"}, sent.Get(CallerHeader))
+
+	// The service that receives the call records it as the actor
+	ctx := metadata.NewIncomingContext(context.Background(), sent)
+	assert.Equal(t, ServiceActor("booking-service"), NewAuthenticator("s3cret").Authenticate(ctx))
+}
+
+func TestFilterFromProto(t *testing.T) {
+	since := testNow.Add(-time.Hour)
+
+	filter, err := FilterFromProto(&auditpb.QueryAuditLogRequest{Actor: "admin-1", Method: "DeleteUser", Since: timestamppb.New(since)})
+	require.NoError(t, err)
+	assert.Equal(t, Filter{Actor: "admin-1", Method: "DeleteUser", Since: since, Limit: 50}, filter)
+
+	filter, err = FilterFromProto(&auditpb.QueryAuditLogRequest{Limit: 500, Until: timestamppb.New(testNow)})
+	require.NoError(t, err)
+	assert.Equal(t, Filter{Until: testNow, Limit: 500}, filter)
+
+	for _, req := range []*auditpb.QueryAuditLogRequest{
+		{Limit: -1},
+		{Limit: 501},
+		{Since: timestamppb.New(testNow), Until: timestamppb.New(since)},
+		{Since: &timestamppb.Timestamp{Nanos: -1}},
+	} {
+		_, err := FilterFromProto(req)
+		assert.Error(t, err, req.String())
+	}
+}
+
+func TestEntryToProto(t *testing.T) {
+	e := chain(t, 1)[0]
+
+	assert.Equal(t, &auditpb.AuditEntry{
+		EntryId:   1,
+		Method:    "CreateUser",
+		Actor:     "admin-1",
+		Peer:      "10.0.0.7:41234",
+		After:     `{"user_id":1,"name":""}`,
+		CreatedAt: timestamppb.New(e.CreatedAt),
+		Hash:      e.Hash,
+	}, EntryToProto(e))
+}
+
+func TestEntryToProto_Redacted(t *testing.T) {
+	e := chain(t, 1)[0]
+	e.Before = json.RawMessage(`{"user_id":1,"name":"Ali"}`)
+
+	// Snapshots hold personal data, so logged responses carry neither
+	res := &auditpb.QueryAuditLogResponse{Entries: []*auditpb.AuditEntry{EntryToProto(e)}}
+	redacted := logger.Redact(res).(*auditpb.QueryAuditLogResponse)
+	require.Len(t, redacted.Entries, 1)
+	assert.Equal(t, logger.Redacted, redacted.Entries[0].Before)
+	assert.Equal(t, logger.Redacted, redacted.Entries[0].After)
+	assert.Equal(t, "CreateUser", redacted.Entries[0].Method)
+	assert.Equal(t, e.Hash, redacted.Entries[0].Hash)
+
+	// The response itself is left alone
+	assert.Equal(t, `{"user_id":1,"name":"Ali"}`, res.Entries[0].Before)
+}
+
+// fakeLog serves fixed entries and a fixed Verify result.
+type fakeLog struct {
+	entries  []*Entry
+	broken   int64
+	verified bool
+}
+
+func (l *fakeLog) Query(ctx context.Context, filter Filter) ([]*Entry, error) {
+	return l.entries, nil
+}
+
+func (l *fakeLog) Verify(ctx context.Context) (int64, error) {
+	l.verified = true
+	return l.broken, nil
+}
+
+func TestQuery(t *testing.T) {
+	entries := chain(t, 2)
+	ctx := context.Background()
+	filter := Filter{Method: "CreateUser", Limit: 50}
+	l := &fakeLog{entries: []*Entry{entries[1], entries[0]}, broken: 2}
+
+	res, err := Query(ctx, l, filter, false)
+	require.NoError(t, err)
+	assert.Equal(t, []*auditpb.AuditEntry{EntryToProto(entries[1]), EntryToProto(entries[0])}, res.Entries)
+	assert.False(t, res.ChainIntact)
+	assert.False(t, l.verified)
+
+	res, err = Query(ctx, l, filter, true)
+	require.NoError(t, err)
+	assert.False(t, res.ChainIntact)
+	assert.Equal(t, int64(2), res.FirstBrokenEntryId)
+
+	l.broken = 0
+	res, err = Query(ctx, l, filter, true)
+	require.NoError(t, err)
+	assert.True(t, res.ChainIntact)
+	assert.Zero(t, res.FirstBrokenEntryId)
+}
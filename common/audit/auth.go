@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AuthorizationHeader is the metadata key a caller sends its bearer token
+// under.
+const AuthorizationHeader = "authorization"
+
+// CallerHeader is the metadata key another service sends its name under; see
+// UnaryClientInterceptor.
+const CallerHeader = "x-caller-service"
+
+// Actors recorded for calls, by how their caller identified itself.
+const (
+	// Admin is the actor of calls bearing the service's admin token.
+	Admin = "admin"
+	// Anonymous is the actor of calls that did not identify their caller.
+	Anonymous = "anonymous"
+)
+
+// servicePattern matches service names such as "booking-service".
+var servicePattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,63}$`)
+
+// ServiceActor is the actor of calls from another service. Like UserActor it
+// is the caller's claim rather than a credential, so it never grants a
+// privilege; the prefix keeps it from ever reading as Admin.
+func ServiceActor(service string) string {
+	return "service:" + service
+}
+
+// UserActor is the actor of calls a user makes for themselves.
+func UserActor(userID int32) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx whose calls are recorded as actor. Only
+// Authenticator may tag a call as Admin.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// WithUser returns a copy of ctx whose calls are recorded as made by the user,
+// for RPCs a user makes for themselves, such as booking a ride. An admin's
+// call stays recorded as the admin's.
+func WithUser(ctx context.Context, userID int32) context.Context {
+	if IsAdmin(ctx) {
+		return ctx
+	}
+	return WithActor(ctx, UserActor(userID))
+}
+
+// Actor returns the actor of the call ctx belongs to, or Anonymous. Only
+// Admin is authenticated; see Authenticator.
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return Anonymous
+}
+
+// IsAdmin reports whether the call ctx belongs to authenticated as Admin.
+func IsAdmin(ctx context.Context) bool {
+	return Actor(ctx) == Admin
+}
+
+// Authenticator identifies callers by the admin token. With an empty token
+// no caller is an admin.
+type Authenticator struct {
+	token string
+}
+
+func NewAuthenticator(token string) *Authenticator {
+	return &Authenticator{token: token}
+}
+
+// Authenticate returns Admin if the caller in ctx sent the admin token as
+// "authorization: Bearer <token>". Otherwise it returns the ServiceActor of a
+// service that sent its name under CallerHeader, and Anonymous for any other
+// caller.
+func (a *Authenticator) Authenticate(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Anonymous
+	}
+	if a.token != "" {
+		for _, v := range md.Get(AuthorizationHeader) {
+			token, ok := strings.CutPrefix(v, "Bearer ")
+			if ok && subtle.ConstantTimeCompare([]byte(token), []byte(a.token)) == 1 {
+				return Admin
+			}
+		}
+	}
+	if v := md.Get(CallerHeader); len(v) > 0 && servicePattern.MatchString(v[0]) {
+		return ServiceActor(v[0])
+	}
+	return Anonymous
+}
+
+// UnaryServerInterceptor tags each call's context with the actor its caller
+// authenticated as, for Actor and IsAdmin.
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(WithActor(ctx, a.Authenticate(ctx)), req)
+	}
+}
+
+// UnaryClientInterceptor sends service's name on outgoing calls, so that the
+// services it calls record it as their actor.
+func UnaryClientInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, CallerHeader, service)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
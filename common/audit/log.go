@@ -0,0 +1,191 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	auditpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultQueryLimit = 50
+	maxQueryLimit     = 500
+	// verifyBatchSize caps how many entries Verify reads at a time.
+	verifyBatchSize = 1000
+)
+
+// Filter selects audit entries. Zero fields match every entry.
+type Filter struct {
+	Actor  string
+	Method string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+// Log reads a service's audit log. Entries are only ever written by Record.
+type Log interface {
+	// Query returns up to filter.Limit matching entries, newest first.
+	Query(ctx context.Context, filter Filter) ([]*Entry, error)
+	// Verify walks the whole chain and returns the ID of the first entry
+	// that does not match it, or 0 if it is intact.
+	Verify(ctx context.Context) (int64, error)
+}
+
+type PostgresLog struct {
+	db *sql.DB
+}
+
+func NewPostgresLog(db *sql.DB) Log {
+	return &PostgresLog{db: db}
+}
+
+const entryColumns = `entry_id, method, actor, request_id, peer, before, after, created_at, prev_hash, hash`
+
+func scanEntries(rows *sql.Rows) ([]*Entry, error) {
+	defer rows.Close()
+	var entries []*Entry
+	for rows.Next() {
+		e := &Entry{}
+		var before, after sql.NullString
+		if err := rows.Scan(&e.ID, &e.Method, &e.Actor, &e.RequestID, &e.Peer, &before, &after,
+			&e.CreatedAt, &e.PrevHash, &e.Hash); err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (l *PostgresLog) Query(ctx context.Context, filter Filter) ([]*Entry, error) {
+	var since, until sql.NullTime
+	if !filter.Since.IsZero() {
+		since = sql.NullTime{Time: filter.Since, Valid: true}
+	}
+	if !filter.Until.IsZero() {
+		until = sql.NullTime{Time: filter.Until, Valid: true}
+	}
+
+	query := `SELECT ` + entryColumns + ` FROM audit_log
+		WHERE ($1 = '' OR actor = $1) AND ($2 = '' OR method = $2)
+			AND ($3::TIMESTAMPTZ IS NULL OR created_at >= $3) AND ($4::TIMESTAMPTZ IS NULL OR created_at < $4)
+		ORDER BY entry_id DESC LIMIT $5`
+	rows, err := l.db.QueryContext(ctx, query, filter.Actor, filter.Method, since, until, filter.Limit)
+	if err != nil {
+		log.Printf("Query audit log failed: %v", err)
+		return nil, err
+	}
+	entries, err := scanEntries(rows)
+	if err != nil {
+		log.Printf("Query audit log failed: %v", err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (l *PostgresLog) Verify(ctx context.Context) (int64, error) {
+	var afterID int64
+	prevHash := ""
+	for {
+		query := `SELECT ` + entryColumns + ` FROM audit_log WHERE entry_id > $1 ORDER BY entry_id LIMIT $2`
+		rows, err := l.db.QueryContext(ctx, query, afterID, verifyBatchSize)
+		if err != nil {
+			log.Printf("Verify audit log failed: %v", err)
+			return 0, err
+		}
+		entries, err := scanEntries(rows)
+		if err != nil {
+			log.Printf("Verify audit log failed: %v", err)
+			return 0, err
+		}
+		if len(entries) == 0 {
+			return 0, nil
+		}
+		if broken := FirstBroken(prevHash, entries); broken != 0 {
+			return broken, nil
+		}
+		last := entries[len(entries)-1]
+		afterID, prevHash = last.ID, last.Hash
+	}
+}
+
+// FilterFromProto validates req and returns its filter.
+func FilterFromProto(req *auditpb.QueryAuditLogRequest) (Filter, error) {
+	filter := Filter{
+		Actor:  req.GetActor(),
+		Method: req.GetMethod(),
+		Limit:  int(req.GetLimit()),
+	}
+	if req.GetLimit() < 0 || req.GetLimit() > maxQueryLimit {
+		return Filter{}, fmt.Errorf("limit must be between 0 and %d", maxQueryLimit)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultQueryLimit
+	}
+	if req.GetSince() != nil {
+		if err := req.GetSince().CheckValid(); err != nil {
+			return Filter{}, fmt.Errorf("invalid since: %v", err)
+		}
+		filter.Since = req.GetSince().AsTime()
+	}
+	if req.GetUntil() != nil {
+		if err := req.GetUntil().CheckValid(); err != nil {
+			return Filter{}, fmt.Errorf("invalid until: %v", err)
+		}
+		filter.Until = req.GetUntil().AsTime()
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Until.After(filter.Since) {
+		return Filter{}, fmt.Errorf("until must be after since")
+	}
+	return filter, nil
+}
+
+// EntryToProto converts e for the QueryAuditLog RPC.
+func EntryToProto(e *Entry) *auditpb.AuditEntry {
+	return &auditpb.AuditEntry{
+		EntryId:   e.ID,
+		Method:    e.Method,
+		Actor:     e.Actor,
+		RequestId: e.RequestID,
+		Peer:      e.Peer,
+		Before:    string(e.Before),
+		After:     string(e.After),
+		CreatedAt: timestamppb.New(e.CreatedAt),
+		PrevHash:  e.PrevHash,
+		Hash:      e.Hash,
+	}
+}
+
+// Query answers a QueryAuditLog call for filter, checking the whole chain as
+// well when verify is set.
+func Query(ctx context.Context, l Log, filter Filter, verify bool) (*auditpb.QueryAuditLogResponse, error) {
+	entries, err := l.Query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	res := &auditpb.QueryAuditLogResponse{}
+	for _, e := range entries {
+		res.Entries = append(res.Entries, EntryToProto(e))
+	}
+	if verify {
+		broken, err := l.Verify(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res.ChainIntact = broken == 0
+		res.FirstBrokenEntryId = broken
+	}
+	return res, nil
+}
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// PoolConfig sizes a service's connection pool and sets how long Open waits
+// for the database to come up.
+type PoolConfig struct {
+	MaxOpenConns int
+	MaxIdleConns int
+	// Connections are closed once they are ConnMaxLifetime old or have been
+	// idle for ConnMaxIdleTime; 0 keeps them.
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// The database is pinged up to ConnectAttempts times, first retried
+	// after ConnectBackoff and then after twice the previous wait.
+	ConnectAttempts int
+	ConnectBackoff  time.Duration
+}
+
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    20,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+		ConnectAttempts: 8,
+		ConnectBackoff:  500 * time.Millisecond,
+	}
+}
+
+// PoolConfigFromEnv reads DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS,
+// DB_CONN_MAX_LIFETIME, DB_CONN_MAX_IDLE_TIME, DB_CONNECT_ATTEMPTS and
+// DB_CONNECT_BACKOFF, falling back to the defaults for unset variables.
+func PoolConfigFromEnv() (PoolConfig, error) {
+	cfg := DefaultPoolConfig()
+	for key, n := range map[string]*int{
+		"DB_MAX_OPEN_CONNS":   &cfg.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS":   &cfg.MaxIdleConns,
+		"DB_CONNECT_ATTEMPTS": &cfg.ConnectAttempts,
+	} {
+		if value := os.Getenv(key); value != "" {
+			v, err := strconv.Atoi(value)
+			if err != nil || v < 0 {
+				return PoolConfig{}, fmt.Errorf("invalid %s %q: want a count", key, value)
+			}
+			*n = v
+		}
+	}
+	for key, d := range map[string]*time.Duration{
+		"DB_CONN_MAX_LIFETIME":  &cfg.ConnMaxLifetime,
+		"DB_CONN_MAX_IDLE_TIME": &cfg.ConnMaxIdleTime,
+		"DB_CONNECT_BACKOFF":    &cfg.ConnectBackoff,
+	} {
+		if value := os.Getenv(key); value != "" {
+			v, err := time.ParseDuration(value)
+			if err != nil || v < 0 {
+				return PoolConfig{}, fmt.Errorf("invalid %s %q: want a duration", key, value)
+			}
+			*d = v
+		}
+	}
+	if cfg.ConnectAttempts < 1 {
+		return PoolConfig{}, fmt.Errorf("invalid DB_CONNECT_ATTEMPTS %d: want at least 1", cfg.ConnectAttempts)
+	}
+	if cfg.MaxOpenConns > 0 && cfg.MaxIdleConns > cfg.MaxOpenConns {
+		return PoolConfig{}, fmt.Errorf("DB_MAX_IDLE_CONNS %d is more than DB_MAX_OPEN_CONNS %d", cfg.MaxIdleConns, cfg.MaxOpenConns)
+	}
+	return cfg, nil
+}
+
+// Open opens a pool sized by cfg and pings the database until it answers,
+// so that a service started alongside Postgres waits for it rather than
+// failing.
+func Open(ctx context.Context, driverName, url string, cfg PoolConfig) (*sql.DB, error) {
+	db, err := sql.Open(driverName, url)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	if err := ping(ctx, db, cfg.ConnectAttempts, cfg.ConnectBackoff); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func ping(ctx context.Context, db *sql.DB, attempts int, backoff time.Duration) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = db.PingContext(ctx); err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("after %d attempts: %w", attempt, err)
+		}
+		log.Printf("Database not reachable (attempt %d of %d), retrying in %v: %v", attempt, attempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startingDriver fails to connect until its database has been asked
+// upFrom times.
+type startingDriver struct {
+	upFrom int64
+	tries  atomic.Int64
+}
+
+func (d *startingDriver) Open(name string) (driver.Conn, error) {
+	if d.tries.Add(1) < d.upFrom {
+		return nil, errors.New("connection refused")
+	}
+	return conn{}, nil
+}
+
+type conn struct{}
+
+func (conn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (conn) Close() error                              { return nil }
+func (conn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func register(t *testing.T, d driver.Driver) string {
+	name := "database-test-" + t.Name()
+	sql.Register(name, d)
+	return name
+}
+
+func testPoolConfig() PoolConfig {
+	cfg := DefaultPoolConfig()
+	cfg.ConnectAttempts = 3
+	cfg.ConnectBackoff = time.Millisecond
+	return cfg
+}
+
+func TestOpen(t *testing.T) {
+	d := &startingDriver{upFrom: 3}
+	cfg := testPoolConfig()
+	cfg.MaxOpenConns = 4
+
+	db, err := Open(context.Background(), register(t, d), "", cfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	assert.Equal(t, int64(3), d.tries.Load())
+	assert.Equal(t, 4, db.Stats().MaxOpenConnections)
+}
+
+func TestOpen_GivesUp(t *testing.T) {
+	d := &startingDriver{upFrom: 10}
+
+	_, err := Open(context.Background(), register(t, d), "", testPoolConfig())
+	assert.ErrorContains(t, err, "after 3 attempts: connection refused")
+	assert.Equal(t, int64(3), d.tries.Load())
+}
+
+func TestOpen_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cfg := testPoolConfig()
+	cfg.ConnectBackoff = time.Hour
+
+	_, err := Open(ctx, register(t, &startingDriver{upFrom: 10}), "", cfg)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPoolConfigFromEnv(t *testing.T) {
+	t.Setenv("DB_MAX_OPEN_CONNS", "50")
+	t.Setenv("DB_MAX_IDLE_CONNS", "25")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "1h")
+	t.Setenv("DB_CONNECT_BACKOFF", "2s")
+
+	cfg, err := PoolConfigFromEnv()
+	require.NoError(t, err)
+	want := DefaultPoolConfig()
+	want.MaxOpenConns = 50
+	want.MaxIdleConns = 25
+	want.ConnMaxLifetime = time.Hour
+	want.ConnectBackoff = 2 * time.Second
+	assert.Equal(t, want, cfg)
+
+	for key, value := range map[string]string{
+		"DB_MAX_OPEN_CONNS":     "many",
+		"DB_MAX_IDLE_CONNS":     "60",
+		"DB_CONN_MAX_IDLE_TIME": "-1m",
+		"DB_CONNECT_ATTEMPTS":   "0",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			_, err := PoolConfigFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -9,59 +9,62 @@ import (
 
 type ErrorHandler struct {
 	logger  *logger.Logger
-	service string
+	metrics *metrics.Registry
 }
 
+// NewErrorHandler counts errors in the registry of log.
 func NewErrorHandler(log *logger.Logger) *ErrorHandler {
-	serviceName := log.GetServiceName()
 	return &ErrorHandler{
 		logger:  log,
-		service: serviceName,
+		metrics: log.Metrics(),
 	}
 }
 
 func (e *ErrorHandler) HandleNotFound(msg string, err error) error {
 	e.logger.Error(msg, "error", err)
-	metrics.IncrementErrorCounter(e.service, "not_found")
+	e.metrics.IncrementError("not_found")
 	return status.Errorf(codes.NotFound, "%s: %v", msg, err)
 }
 
 func (e *ErrorHandler) HandleInvalidArgument(msg string, err error) error {
 	e.logger.Error(msg, "error", err)
-	metrics.IncrementErrorCounter(e.service, "invalid_argument")
-	logger.IncrementValidationErrorCount()
+	e.metrics.IncrementError("invalid_argument")
 	return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
 }
 
 func (e *ErrorHandler) HandleDatabaseError(msg string, err error) error {
 	e.logger.Error(msg, "error", err)
-	metrics.IncrementErrorCounter(e.service, "database")
-	logger.IncrementDBErrorCount()
+	e.metrics.IncrementError("database")
 	return status.Errorf(codes.Internal, "%s: %v", msg, err)
 }
 
 func (e *ErrorHandler) HandleInternalError(msg string, err error) error {
 	e.logger.Error(msg, "error", err)
-	metrics.IncrementErrorCounter(e.service, "internal")
+	e.metrics.IncrementError("internal")
 	return status.Errorf(codes.Internal, "%s: %v", msg, err)
 }
 
 func (e *ErrorHandler) HandleUnauthenticated(msg string, err error) error {
 	e.logger.Error(msg, "error", err)
-	metrics.IncrementErrorCounter(e.service, "unauthenticated")
+	e.metrics.IncrementError("unauthenticated")
 	return status.Errorf(codes.Unauthenticated, "%s: %v", msg, err)
 }
 
 func (e *ErrorHandler) HandlePermissionDenied(msg string, err error) error {
 	e.logger.Error(msg, "error", err)
-	metrics.IncrementErrorCounter(e.service, "permission_denied")
+	e.metrics.IncrementError("permission_denied")
 	return status.Errorf(codes.PermissionDenied, "%s: %v", msg, err)
 }
 
+func (e *ErrorHandler) HandleFailedPrecondition(msg string, err error) error {
+	e.logger.Error(msg, "error", err)
+	e.metrics.IncrementError("failed_precondition")
+	return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+}
+
 func (e *ErrorHandler) HandleNetworkError(msg string, err error) error {
 	e.logger.Error(msg, "error", err)
-	metrics.IncrementErrorCounter(e.service, "network")
-	logger.IncrementNetworkErrorCount()
+	e.metrics.IncrementError("network")
 	return status.Errorf(codes.Unavailable, "%s: %v", msg, err)
 }
 
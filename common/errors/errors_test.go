@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	log := logger.NewLoggerWithWriter("payment-service", &buf)
+	h := NewErrorHandler(log)
+	cause := errors.New("boom")
+
+	for _, tt := range []struct {
+		handle    func(string, error) error
+		code      codes.Code
+		errorType string
+	}{
+		{h.HandleNotFound, codes.NotFound, "not_found"},
+		{h.HandleInvalidArgument, codes.InvalidArgument, "invalid_argument"},
+		{h.HandleDatabaseError, codes.Internal, "database"},
+		{h.HandleInternalError, codes.Internal, "internal"},
+		{h.HandleUnauthenticated, codes.Unauthenticated, "unauthenticated"},
+		{h.HandlePermissionDenied, codes.PermissionDenied, "permission_denied"},
+		{h.HandleFailedPrecondition, codes.FailedPrecondition, "failed_precondition"},
+		{h.HandleNetworkError, codes.Unavailable, "network"},
+	} {
+		err := tt.handle("failed to capture payment", cause)
+		assert.Equal(t, tt.code, status.Code(err), tt.errorType)
+		assert.Equal(t, "failed to capture payment: boom", status.Convert(err).Message())
+		assert.Equal(t, int64(1), log.Metrics().ErrorCount(tt.errorType), tt.errorType)
+	}
+	assert.Len(t, log.Metrics().ErrorCounts(), 8)
+	assert.Contains(t, buf.String(), "failed to capture payment")
+}
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader is the metadata key a request ID travels under, both on
+// incoming and outgoing calls and in response headers.
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLength bounds request IDs accepted from callers; longer ones
+// are replaced with a generated ID.
+const maxRequestIDLength = 128
+
+// callFields are the fields a call's context adds to every log line written
+// with it.
+type callFields struct {
+	requestID string
+	method    string
+	userID    int32
+}
+
+type callFieldsKey struct{}
+
+// WithRequestID returns a copy of ctx carrying requestID, for log lines and
+// outgoing calls.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	f := fieldsFrom(ctx)
+	f.requestID = requestID
+	return context.WithValue(ctx, callFieldsKey{}, &f)
+}
+
+// WithUserID returns a copy of ctx whose log lines carry userID.
+func WithUserID(ctx context.Context, userID int32) context.Context {
+	f := fieldsFrom(ctx)
+	f.userID = userID
+	return context.WithValue(ctx, callFieldsKey{}, &f)
+}
+
+// RequestID returns the request ID of the call ctx belongs to, or "" if it
+// has none.
+func RequestID(ctx context.Context) string {
+	return fieldsFrom(ctx).requestID
+}
+
+func fieldsFrom(ctx context.Context) callFields {
+	if f, ok := ctx.Value(callFieldsKey{}).(*callFields); ok {
+		return *f
+	}
+	return callFields{}
+}
+
+// NewRequestID returns a random request ID.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// contextHandler adds the fields of a call's context to each record, unless
+// the record already has a field of the same name.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if f, ok := ctx.Value(callFieldsKey{}).(*callFields); ok {
+		present := map[string]bool{}
+		r.Attrs(func(a slog.Attr) bool {
+			present[a.Key] = true
+			return true
+		})
+		add := func(key string, value any) {
+			if !present[key] {
+				r.AddAttrs(slog.Any(key, value))
+			}
+		}
+		if f.requestID != "" {
+			add("request_id", f.requestID)
+		}
+		if f.method != "" {
+			add("method", f.method)
+		}
+		if f.userID != 0 {
+			add("user_id", f.userID)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// callContext tags ctx with the call's request ID, taken from the incoming
+// metadata or generated, and its method. The request ID is sent back in the
+// response headers.
+func callContext(ctx context.Context, fullMethod string) context.Context {
+	requestID := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(RequestIDHeader); len(v) > 0 && validRequestID(v[0]) {
+			requestID = v[0]
+		}
+	}
+	if requestID == "" {
+		requestID = NewRequestID()
+	}
+	// Fails only outside a real transport, e.g. when handlers are called
+	// directly in tests
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, requestID))
+
+	f := fieldsFrom(ctx)
+	f.requestID = requestID
+	f.method = path.Base(fullMethod)
+	return context.WithValue(ctx, callFieldsKey{}, &f)
+}
+
+// validRequestID accepts printable ASCII IDs of a sensible length, so that
+// callers cannot inject arbitrary text into logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// UnaryServerInterceptor tags each call's context with its request ID, method
+// and, for requests with a user_id, the user ID, so that log lines written
+// with the context carry them. It logs each call's outcome.
+func UnaryServerInterceptor(log *Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = callContext(ctx, info.FullMethod)
+		if r, ok := req.(interface{ GetUserId() int32 }); ok && r.GetUserId() != 0 {
+			ctx = WithUserID(ctx, r.GetUserId())
+		}
+
+		start := time.Now()
+		res, err := handler(ctx, req)
+		log.InfoContext(ctx, "request completed", "code", status.Code(err).String(), "duration_ms", time.Since(start).Milliseconds())
+		return res, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls,
+// whose contexts carry the request ID and method.
+func StreamServerInterceptor(log *Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := callContext(ss.Context(), info.FullMethod)
+
+		start := time.Now()
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		log.InfoContext(ctx, "request completed", "code", status.Code(err).String(), "duration_ms", time.Since(start).Milliseconds())
+		return err
+	}
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryClientInterceptor sends the request ID of the call ctx belongs to on
+// outgoing calls, so downstream services log under the same ID.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if requestID := RequestID(ctx); requestID != "" {
+			if md, ok := metadata.FromOutgoingContext(ctx); !ok || len(md.Get(RequestIDHeader)) == 0 {
+				ctx = metadata.AppendToOutgoingContext(ctx, RequestIDHeader, requestID)
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
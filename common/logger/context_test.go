@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type getUserRequest struct{ userID int32 }
+
+func (r *getUserRequest) GetUserId() int32 { return r.userID }
+
+// lines decodes the JSON lines written to buf.
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var m map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &m))
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestLoggerContext(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLoggerWithWriter("booking-service", &buf)
+
+	ctx := WithUserID(WithRequestID(context.Background(), "req-1"), 7)
+	log.InfoContext(ctx, "booking created", "booking_id", 3)
+	log.Info("no context")
+	// A field already on the line wins over the context's
+	log.ErrorContext(ctx, "failed", "user_id", 9)
+
+	got := lines(t, &buf)
+	require.Len(t, got, 3)
+	assert.Equal(t, "req-1", got[0]["request_id"])
+	assert.Equal(t, float64(7), got[0]["user_id"])
+	assert.Equal(t, float64(3), got[0]["booking_id"])
+	assert.Equal(t, "booking-service", got[0]["service"])
+	assert.NotContains(t, got[1], "request_id")
+	assert.Equal(t, float64(9), got[2]["user_id"])
+	assert.Equal(t, "req-1", got[2]["request_id"])
+	raw := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 1, strings.Count(raw[2], `"user_id"`))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLoggerWithWriter("user-service", &buf)
+	interceptor := UnaryServerInterceptor(log)
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.UserService/GetUser"}
+
+	var handled context.Context
+	handler := func(ctx context.Context, req any) (any, error) {
+		handled = ctx
+		log.LogRequest(ctx, "GetUser", req)
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	// The caller's request ID is kept
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "req-42"))
+	_, err := interceptor(ctx, &getUserRequest{userID: 5}, info, handler)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "req-42", RequestID(handled))
+
+	got := lines(t, &buf)
+	require.Len(t, got, 2)
+	assert.Equal(t, "received request", got[0]["msg"])
+	assert.Equal(t, "req-42", got[0]["request_id"])
+	assert.Equal(t, "GetUser", got[0]["method"])
+	assert.Equal(t, float64(5), got[0]["user_id"])
+	assert.Equal(t, "request completed", got[1]["msg"])
+	assert.Equal(t, "NotFound", got[1]["code"])
+	assert.Equal(t, "req-42", got[1]["request_id"])
+
+	// Missing and unusable IDs are replaced
+	for _, md := range []metadata.MD{
+		nil,
+		metadata.Pairs(RequestIDHeader, ""),
+		metadata.Pairs(RequestIDHeader, "bad id\nwith newline"),
+		metadata.Pairs(RequestIDHeader, strings.Repeat("a", 129)),
+	} {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		_, _ = interceptor(ctx, &getUserRequest{}, info, handler)
+		assert.Len(t, RequestID(handled), 32)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+
+	var sent metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		sent, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	ctx := WithRequestID(context.Background(), "req-42")
+	require.NoError(t, interceptor(ctx, "/ride.RideService/GetRide", nil, nil, nil, invoker))
+	assert.Equal(t, []string{"req-42"}, sent.Get(RequestIDHeader))
+
+	// An ID the caller set explicitly is left alone
+	ctx = metadata.AppendToOutgoingContext(ctx, RequestIDHeader, "explicit")
+	require.NoError(t, interceptor(ctx, "/ride.RideService/GetRide", nil, nil, nil, invoker))
+	assert.Equal(t, []string{"explicit"}, sent.Get(RequestIDHeader))
+
+	// Calls outside a request carry no ID
+	require.NoError(t, interceptor(context.Background(), "/ride.RideService/GetRide", nil, nil, nil, invoker))
+	assert.Empty(t, sent.Get(RequestIDHeader))
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// level is the minimum level of every Logger of the process.
+var level slog.LevelVar
+
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+func GetLevel() slog.Level {
+	return level.Level()
+}
+
+type levelBody struct {
+	Level string `json:"level"`
+}
+
+// LevelHandler serves the log level: GET returns it and PUT, with a body
+// such as {"level": "DEBUG"}, changes it until the process restarts.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body levelBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			var l slog.Level
+			if err := l.UnmarshalText([]byte(body.Level)); err != nil {
+				http.Error(w, "invalid level: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			SetLevel(l)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(levelBody{Level: GetLevel().String()})
+	})
+}
@@ -1,16 +1,12 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"log/slog"
 	"os"
-	"sync/atomic"
-)
 
-var (
-	DBErrorCount         atomic.Int64
-	NetworkErrorCount    atomic.Int64
-	ValidationErrorCount atomic.Int64
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
 )
 
 const (
@@ -23,35 +19,39 @@ const (
 type Logger struct {
 	logger      *slog.Logger
 	serviceName string
+	// metrics counts the service's errors; see WithMetrics.
+	metrics *metrics.Registry
 }
 
 func NewLogger(serviceName string) *Logger {
 	jsonHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
+		Level: &level,
 	})
 
-	logger := slog.New(jsonHandler).With(
+	logger := slog.New(contextHandler{jsonHandler}).With(
 		"service", serviceName,
 	)
 
 	return &Logger{
 		logger:      logger,
 		serviceName: serviceName,
+		metrics:     metrics.NewRegistry(serviceName),
 	}
 }
 
 func NewLoggerWithWriter(serviceName string, w io.Writer) *Logger {
 	jsonHandler := slog.NewJSONHandler(w, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
+		Level: &level,
 	})
 
-	logger := slog.New(jsonHandler).With(
+	logger := slog.New(contextHandler{jsonHandler}).With(
 		"service", serviceName,
 	)
 
 	return &Logger{
 		logger:      logger,
 		serviceName: serviceName,
+		metrics:     metrics.NewRegistry(serviceName),
 	}
 }
 
@@ -59,6 +59,22 @@ func (l *Logger) GetServiceName() string {
 	return l.serviceName
 }
 
+// Metrics returns the registry the service's errors are counted in. A new
+// Logger has a registry of its own.
+func (l *Logger) Metrics() *metrics.Registry {
+	return l.metrics
+}
+
+// WithMetrics returns a copy of l that shares reg, e.g. the registry main
+// serves on the metrics port.
+func (l *Logger) WithMetrics(reg *metrics.Registry) *Logger {
+	return &Logger{
+		logger:      l.logger,
+		serviceName: l.serviceName,
+		metrics:     reg,
+	}
+}
+
 func (l *Logger) Debug(msg string, args ...any) {
 	l.logger.Debug(msg, args...)
 }
@@ -75,37 +91,47 @@ func (l *Logger) Error(msg string, args ...any) {
 	l.logger.Error(msg, args...)
 }
 
-func (l *Logger) WithValues(keyValues ...any) *Logger {
-	return &Logger{
-		logger:      l.logger.With(keyValues...),
-		serviceName: l.serviceName,
-	}
+// DebugContext, InfoContext, WarnContext and ErrorContext add the request ID,
+// method and user ID of the call ctx belongs to.
+func (l *Logger) DebugContext(ctx context.Context, msg string, args ...any) {
+	l.logger.DebugContext(ctx, msg, args...)
+}
+
+func (l *Logger) InfoContext(ctx context.Context, msg string, args ...any) {
+	l.logger.InfoContext(ctx, msg, args...)
 }
 
-func (l *Logger) LogRequest(method string, req any) {
-	l.Info("received request", "method", method, "payload", req)
+func (l *Logger) WarnContext(ctx context.Context, msg string, args ...any) {
+	l.logger.WarnContext(ctx, msg, args...)
 }
 
-func (l *Logger) LogResponse(method string, res any) {
-	l.Info("sending response", "method", method, "payload", res)
+func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	l.logger.ErrorContext(ctx, msg, args...)
 }
 
-func IncrementDBErrorCount() {
-	DBErrorCount.Add(1)
+func (l *Logger) WithValues(keyValues ...any) *Logger {
+	return &Logger{
+		logger:      l.logger.With(keyValues...),
+		serviceName: l.serviceName,
+		metrics:     l.metrics,
+	}
 }
 
-func IncrementNetworkErrorCount() {
-	NetworkErrorCount.Add(1)
+// LogRequest and LogResponse log a sample of calls' payloads, redacted and
+// truncated as configured.
+func (l *Logger) LogRequest(ctx context.Context, method string, req any) {
+	l.logPayload(ctx, "received request", method, req)
 }
 
-func IncrementValidationErrorCount() {
-	ValidationErrorCount.Add(1)
+func (l *Logger) LogResponse(ctx context.Context, method string, res any) {
+	l.logPayload(ctx, "sending response", method, res)
 }
 
-func GetErrorCounts() map[string]int64 {
-	return map[string]int64{
-		"db_errors":         DBErrorCount.Load(),
-		"network_errors":    NetworkErrorCount.Load(),
-		"validation_errors": ValidationErrorCount.Load(),
+func (l *Logger) logPayload(ctx context.Context, msg, method string, payload any) {
+	s := currentSettings()
+	if !l.logger.Enabled(ctx, LevelInfo) || !s.sampled(ctx, method) {
+		return
 	}
+	args := append([]any{"method", method}, payloadAttrs(payload, s.PayloadMaxBytes)...)
+	l.InfoContext(ctx, msg, args...)
 }
@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	loggingpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/logging"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// Redacted replaces the value of string fields marked sensitive.
+	Redacted = "[REDACTED]"
+
+	DefaultPayloadMaxBytes = 2048
+)
+
+// Settings are the process-wide logging settings, shared by every Logger.
+type Settings struct {
+	Level slog.Level
+	// Payloads longer than PayloadMaxBytes are truncated; 0 logs them whole.
+	PayloadMaxBytes int
+	// SampleRate is the fraction of calls whose payloads are logged, unless
+	// SampleRates has a rate for the method.
+	SampleRate  float64
+	SampleRates map[string]float64
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		Level:           LevelInfo,
+		PayloadMaxBytes: DefaultPayloadMaxBytes,
+		SampleRate:      1,
+	}
+}
+
+var settings atomic.Pointer[Settings]
+
+func init() {
+	s := DefaultSettings()
+	settings.Store(&s)
+}
+
+// Configure applies s to every Logger of the process.
+func Configure(s Settings) {
+	settings.Store(&s)
+	SetLevel(s.Level)
+}
+
+func currentSettings() *Settings {
+	return settings.Load()
+}
+
+// SettingsFromEnv reads LOG_LEVEL, LOG_PAYLOAD_MAX_BYTES, LOG_SAMPLE_RATE and
+// LOG_SAMPLE_RATES, e.g. "GetUser=0.1,UpdateLocation=0", falling back to the
+// defaults for unset variables.
+func SettingsFromEnv() (Settings, error) {
+	s := DefaultSettings()
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		if err := s.Level.UnmarshalText([]byte(v)); err != nil {
+			return Settings{}, fmt.Errorf("invalid LOG_LEVEL %q: %v", v, err)
+		}
+	}
+	if v := os.Getenv("LOG_PAYLOAD_MAX_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Settings{}, fmt.Errorf("invalid LOG_PAYLOAD_MAX_BYTES %q: want a byte count", v)
+		}
+		s.PayloadMaxBytes = n
+	}
+	if v := os.Getenv("LOG_SAMPLE_RATE"); v != "" {
+		rate, err := parseRate(v)
+		if err != nil {
+			return Settings{}, fmt.Errorf("invalid LOG_SAMPLE_RATE %q: %v", v, err)
+		}
+		s.SampleRate = rate
+	}
+	rates, err := ParseSampleRates(os.Getenv("LOG_SAMPLE_RATES"))
+	if err != nil {
+		return Settings{}, fmt.Errorf("invalid LOG_SAMPLE_RATES: %v", err)
+	}
+	s.SampleRates = rates
+	return s, nil
+}
+
+// ParseSampleRates reads a comma-separated list of method=rate pairs.
+func ParseSampleRates(list string) (map[string]float64, error) {
+	rates := map[string]float64{}
+	for _, pair := range strings.Split(list, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		method, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(method) == "" {
+			return nil, fmt.Errorf("%q is not method=rate", pair)
+		}
+		rate, err := parseRate(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %v", pair, err)
+		}
+		rates[strings.TrimSpace(method)] = rate
+	}
+	return rates, nil
+}
+
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("want a rate between 0 and 1")
+	}
+	return rate, nil
+}
+
+// sampled reports whether the payloads of the call ctx belongs to are logged.
+// Calls with a request ID are sampled by it, so that a call's request and
+// response are logged together.
+func (s *Settings) sampled(ctx context.Context, method string) bool {
+	rate, ok := s.SampleRates[method]
+	if !ok {
+		rate = s.SampleRate
+	}
+	if rate >= 1 {
+		return true
+	}
+	if rate <= 0 {
+		return false
+	}
+	if requestID := RequestID(ctx); requestID != "" {
+		h := fnv.New64a()
+		h.Write([]byte(requestID))
+		return float64(h.Sum64()%10000)/10000 < rate
+	}
+	return rand.Float64() < rate
+}
+
+// payloadAttrs returns the log fields for payload: protobuf messages with
+// their sensitive fields redacted, cut to maxBytes.
+func payloadAttrs(payload any, maxBytes int) []any {
+	m, ok := payload.(proto.Message)
+	if !ok {
+		return []any{"payload", payload}
+	}
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(Redact(m))
+	if err != nil {
+		return []any{"payload", payload, "payload_error", err.Error()}
+	}
+	// protojson randomizes its whitespace
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, b); err == nil {
+		b = compact.Bytes()
+	}
+	if maxBytes > 0 && len(b) > maxBytes {
+		cut := maxBytes
+		for cut > 0 && !utf8.RuneStart(b[cut]) {
+			cut--
+		}
+		return []any{"payload", string(b[:cut]), "payload_truncated", true, "payload_bytes", len(b)}
+	}
+	return []any{"payload", json.RawMessage(b)}
+}
+
+// Redact returns a copy of m whose fields marked (logging.sensitive), in m
+// and the messages it holds, are replaced with Redacted if they are strings
+// and cleared otherwise.
+func Redact(m proto.Message) proto.Message {
+	if m == nil {
+		return m
+	}
+	clone := proto.Clone(m)
+	redact(clone.ProtoReflect())
+	return clone
+}
+
+func redact(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case sensitive(fd):
+			if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+				m.Set(fd, protoreflect.ValueOfString(Redacted))
+			} else {
+				m.Clear(fd)
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redact(mv.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i := 0; i < v.List().Len(); i++ {
+					redact(v.List().Get(i).Message())
+				}
+			}
+		case fd.Message() != nil:
+			redact(v.Message())
+		}
+		return true
+	})
+}
+
+func sensitive(fd protoreflect.FieldDescriptor) bool {
+	opts := fd.Options()
+	if opts == nil {
+		return false
+	}
+	marked, _ := proto.GetExtension(opts, loggingpb.E_Sensitive).(bool)
+	return marked
+}
@@ -0,0 +1,214 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	loggingpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/logging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// personDescriptor describes
+//
+//	message Person {
+//	  string name = 1 [(logging.sensitive) = true];
+//	  int32 age = 2;
+//	  repeated string phones = 3 [(logging.sensitive) = true];
+//	  repeated Person friends = 4;
+//	}
+func personDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	sensitive := &descriptorpb.FieldOptions{}
+	proto.SetExtension(sensitive, loggingpb.E_Sensitive, true)
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, label descriptorpb.FieldDescriptorProto_Label, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Type:     typ.Enum(),
+			Label:    label.Enum(),
+			Options:  opts,
+		}
+		if typ == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+			f.TypeName = proto.String(".test.Person")
+		}
+		return f
+	}
+	optional, repeated := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL, descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test/person.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Person"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, optional, sensitive),
+				field("age", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, optional, nil),
+				field("phones", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated, sensitive),
+				field("friends", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, repeated, nil),
+			},
+		}},
+	}, nil)
+	require.NoError(t, err)
+	return file.Messages().Get(0)
+}
+
+func newPerson(md protoreflect.MessageDescriptor, name string, age int32, phones ...string) *dynamicpb.Message {
+	p := dynamicpb.NewMessage(md)
+	p.Set(md.Fields().ByName("name"), protoreflect.ValueOfString(name))
+	p.Set(md.Fields().ByName("age"), protoreflect.ValueOfInt32(age))
+	list := p.Mutable(md.Fields().ByName("phones")).List()
+	for _, phone := range phones {
+		list.Append(protoreflect.ValueOfString(phone))
+	}
+	return p
+}
+
+func TestRedact(t *testing.T) {
+	md := personDescriptor(t)
+	p := newPerson(md, "Ali", 30, "+923001234567")
+	friends := p.Mutable(md.Fields().ByName("friends")).List()
+	friends.Append(protoreflect.ValueOfMessage(newPerson(md, "Sara", 28)))
+
+	redacted := Redact(p).ProtoReflect()
+	assert.Equal(t, Redacted, redacted.Get(md.Fields().ByName("name")).String())
+	assert.Equal(t, int64(30), redacted.Get(md.Fields().ByName("age")).Int())
+	assert.False(t, redacted.Has(md.Fields().ByName("phones")))
+	friend := redacted.Get(md.Fields().ByName("friends")).List().Get(0).Message()
+	assert.Equal(t, Redacted, friend.Get(md.Fields().ByName("name")).String())
+
+	// The logged message is left alone
+	assert.Equal(t, "Ali", p.Get(md.Fields().ByName("name")).String())
+	assert.Equal(t, 1, p.Get(md.Fields().ByName("phones")).List().Len())
+	assert.Nil(t, Redact(nil))
+}
+
+func TestLogRequest_Payload(t *testing.T) {
+	defer Configure(DefaultSettings())
+	md := personDescriptor(t)
+	var buf bytes.Buffer
+	log := NewLoggerWithWriter("user-service", &buf)
+	ctx := context.Background()
+
+	log.LogRequest(ctx, "CreateUser", newPerson(md, "Ali", 30))
+	// Payloads other than protobuf messages are logged as they are
+	log.LogResponse(ctx, "CreateUser", map[string]int{"user_id": 4})
+
+	s := DefaultSettings()
+	s.PayloadMaxBytes = 10
+	Configure(s)
+	log.LogRequest(ctx, "CreateUser", wrapperspb.String(strings.Repeat("é", 10)))
+
+	got := lines(t, &buf)
+	require.Len(t, got, 3)
+	assert.Equal(t, "received request", got[0]["msg"])
+	assert.Equal(t, map[string]any{"name": Redacted, "age": float64(30)}, got[0]["payload"])
+	assert.Equal(t, map[string]any{"user_id": float64(4)}, got[1]["payload"])
+	// Truncated on a character boundary
+	assert.Equal(t, `"éééé`, got[2]["payload"])
+	assert.Equal(t, true, got[2]["payload_truncated"])
+	assert.Equal(t, float64(22), got[2]["payload_bytes"])
+}
+
+func TestLogRequest_Sampling(t *testing.T) {
+	defer Configure(DefaultSettings())
+	var buf bytes.Buffer
+	log := NewLoggerWithWriter("driver-service", &buf)
+
+	s := DefaultSettings()
+	s.SampleRate = 0.5
+	s.SampleRates = map[string]float64{"UpdateLocation": 0, "GetDriver": 1}
+	Configure(s)
+
+	log.LogRequest(context.Background(), "UpdateLocation", wrapperspb.Int32(1))
+	assert.Empty(t, buf.String())
+	log.LogRequest(context.Background(), "GetDriver", wrapperspb.Int32(1))
+	assert.Len(t, lines(t, &buf), 1)
+
+	// A call's request and response are sampled together
+	logged := 0
+	for i := 0; i < 200; i++ {
+		buf.Reset()
+		ctx := WithRequestID(context.Background(), NewRequestID())
+		log.LogRequest(ctx, "AssignDriver", wrapperspb.Int32(1))
+		log.LogResponse(ctx, "AssignDriver", wrapperspb.Int32(2))
+		if buf.Len() > 0 {
+			assert.Len(t, lines(t, &buf), 2)
+			logged++
+		}
+	}
+	assert.Greater(t, logged, 50)
+	assert.Less(t, logged, 150)
+}
+
+func TestSettingsFromEnv(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_PAYLOAD_MAX_BYTES", "512")
+	t.Setenv("LOG_SAMPLE_RATE", "0.25")
+	t.Setenv("LOG_SAMPLE_RATES", " GetUser=0.1, UpdateLocation=0 ")
+
+	s, err := SettingsFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Settings{
+		Level:           LevelDebug,
+		PayloadMaxBytes: 512,
+		SampleRate:      0.25,
+		SampleRates:     map[string]float64{"GetUser": 0.1, "UpdateLocation": 0},
+	}, s)
+
+	for key, value := range map[string]string{
+		"LOG_LEVEL":             "loud",
+		"LOG_PAYLOAD_MAX_BYTES": "-1",
+		"LOG_SAMPLE_RATE":       "2",
+		"LOG_SAMPLE_RATES":      "GetUser",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			_, err := SettingsFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer SetLevel(LevelInfo)
+	var buf bytes.Buffer
+	log := NewLoggerWithWriter("ride-service", &buf)
+	handler := LevelHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/log-level", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level": "INFO"}`, rec.Body.String())
+
+	log.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level": "debug"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level": "DEBUG"}`, rec.Body.String())
+	log.Debug("shown")
+	assert.Contains(t, buf.String(), "shown")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(`{"level": "loud"}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, LevelDebug, GetLevel())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/log-level", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -5,15 +5,6 @@ import (
 )
 
 var (
-	// ErrorCounter counts error events with service and type labels
-	ErrorCounter = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "app_errors_total",
-			Help: "Total number of errors by service and type",
-		},
-		[]string{"service", "type"},
-	)
-
 	// RequestCounter counts gRPC method calls with service and method labels
 	RequestCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -22,21 +13,53 @@ var (
 		},
 		[]string{"service", "method"},
 	)
+
+	// SurgeMultiplier reports the current surge pricing multiplier per area
+	SurgeMultiplier = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ride_surge_multiplier",
+			Help: "Current surge pricing multiplier by area (1 means no surge)",
+		},
+		[]string{"area"},
+	)
+
+	// NotificationCounter counts notification delivery attempts by channel
+	// and outcome: sent, retried or failed
+	NotificationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "notification_deliveries_total",
+			Help: "Total number of notification delivery attempts by channel and outcome",
+		},
+		[]string{"channel", "outcome"},
+	)
 )
 
-// Init registers all metrics with Prometheus
+// Init registers the process-wide metrics with Prometheus. Errors are counted
+// per service by a Registry.
 func Init() {
 	// Register metrics with Prometheus
-	prometheus.MustRegister(ErrorCounter)
 	prometheus.MustRegister(RequestCounter)
-}
-
-// IncrementErrorCounter increments the error counter for the specified service and error type
-func IncrementErrorCounter(service, errorType string) {
-	ErrorCounter.WithLabelValues(service, errorType).Inc()
+	prometheus.MustRegister(SurgeMultiplier)
+	prometheus.MustRegister(NotificationCounter)
 }
 
 // IncrementRequestCounter increments the request counter for the specified service and method
 func IncrementRequestCounter(service, method string) {
 	RequestCounter.WithLabelValues(service, method).Inc()
 }
+
+// SetSurgeMultiplier records the surge multiplier for an area
+func SetSurgeMultiplier(area string, multiplier float64) {
+	SurgeMultiplier.WithLabelValues(area).Set(multiplier)
+}
+
+// DeleteSurgeMultiplier removes the surge gauge for an area with no recent demand
+func DeleteSurgeMultiplier(area string) {
+	SurgeMultiplier.DeleteLabelValues(area)
+}
+
+// IncrementNotificationCounter counts a notification delivery attempt on a
+// channel with its outcome
+func IncrementNotificationCounter(channel, outcome string) {
+	NotificationCounter.WithLabelValues(channel, outcome).Inc()
+}
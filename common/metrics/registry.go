@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds one service's own metrics: its error counts and connection
+// pool stats. Each service creates its own, as does each test, so that
+// counts never leak between them.
+type Registry struct {
+	service  string
+	registry *prometheus.Registry
+	errors   *prometheus.CounterVec
+}
+
+func NewRegistry(service string) *Registry {
+	r := &Registry{
+		service:  service,
+		registry: prometheus.NewRegistry(),
+		errors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "app_errors_total",
+				Help:        "Total number of errors by service and type",
+				ConstLabels: prometheus.Labels{"service": service},
+			},
+			[]string{"type"},
+		),
+	}
+	r.registry.MustRegister(r.errors)
+	return r
+}
+
+func (r *Registry) Service() string {
+	return r.service
+}
+
+// IncrementError counts an error of the given type, e.g. "database".
+func (r *Registry) IncrementError(errorType string) {
+	r.errors.WithLabelValues(errorType).Inc()
+}
+
+// ErrorCount returns how many errors of the given type were counted.
+func (r *Registry) ErrorCount(errorType string) int64 {
+	return r.ErrorCounts()[errorType]
+}
+
+// ErrorCounts returns the number of errors counted by type.
+func (r *Registry) ErrorCounts() map[string]int64 {
+	counts := map[string]int64{}
+	families, err := r.registry.Gather()
+	if err != nil {
+		return counts
+	}
+	for _, family := range families {
+		if family.GetName() != "app_errors_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "type" {
+					counts[label.GetValue()] = int64(m.GetCounter().GetValue())
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// RegisterDBStats exports the sql.DBStats of db, e.g. go_sql_in_use_connections
+// and go_sql_wait_duration_seconds_total, labelled with dbName and the
+// service.
+func (r *Registry) RegisterDBStats(db *sql.DB, dbName string) {
+	reg := prometheus.WrapRegistererWith(prometheus.Labels{"service": r.service}, r.registry)
+	reg.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler serves the registry's metrics together with the process-wide ones
+// registered by Init.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(prometheus.Gatherers{prometheus.DefaultGatherer, r.registry}, promhttp.HandlerOpts{})
+}
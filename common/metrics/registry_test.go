@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_ErrorCounts(t *testing.T) {
+	t.Parallel()
+	r := NewRegistry("booking-service")
+	assert.Equal(t, "booking-service", r.Service())
+	assert.Empty(t, r.ErrorCounts())
+
+	r.IncrementError("database")
+	r.IncrementError("database")
+	r.IncrementError("network")
+
+	assert.Equal(t, map[string]int64{"database": 2, "network": 1}, r.ErrorCounts())
+	assert.Equal(t, int64(2), r.ErrorCount("database"))
+	assert.Zero(t, r.ErrorCount("not_found"))
+}
+
+func TestRegistry_Isolated(t *testing.T) {
+	t.Parallel()
+	a, b := NewRegistry("user-service"), NewRegistry("user-service")
+
+	a.IncrementError("internal")
+
+	assert.Equal(t, int64(1), a.ErrorCount("internal"))
+	assert.Empty(t, b.ErrorCounts())
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	t.Parallel()
+	r := NewRegistry("ride-service")
+	r.IncrementError("invalid_argument")
+
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `app_errors_total{service="ride-service",type="invalid_argument"} 1`)
+}
+
+func TestRegistry_DBStats(t *testing.T) {
+	t.Parallel()
+	r := NewRegistry("payment-service")
+	db, err := sql.Open("metrics-test", "")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(12)
+
+	r.RegisterDBStats(db, "payments_db")
+
+	srv := httptest.NewServer(r.Handler())
+	defer srv.Close()
+	res, err := http.Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `go_sql_max_open_connections{db_name="payments_db",service="payment-service"} 12`)
+	assert.Contains(t, string(body), `go_sql_in_use_connections{db_name="payments_db",service="payment-service"} 0`)
+	assert.Contains(t, string(body), `go_sql_wait_duration_seconds_total{db_name="payments_db",service="payment-service"} 0`)
+}
+
+// noDriver lets tests open a sql.DB that never connects.
+type noDriver struct{}
+
+func (noDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("no database")
+}
+
+func init() {
+	sql.Register("metrics-test", noDriver{})
+}
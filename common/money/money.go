@@ -0,0 +1,324 @@
+// Package money represents amounts as integer minor units of a currency, so
+// sums are exact and every rounding step is explicit.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
+)
+
+// PKR is the currency amounts were in before they carried one.
+const PKR = "PKR"
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrOverflow         = errors.New("amount overflows int64 minor units")
+	ErrMissing          = errors.New("amount is missing")
+	ErrInvalidAmount    = errors.New("invalid amount")
+)
+
+// exponents holds the number of minor-unit digits of supported ISO 4217
+// currencies.
+var exponents = map[string]int{
+	"AED": 2,
+	"BHD": 3,
+	"EUR": 2,
+	"GBP": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"PKR": 2,
+	"SAR": 2,
+	"USD": 2,
+}
+
+// Exponent returns how many minor-unit digits currency has, e.g. 2 for PKR.
+func Exponent(currency string) (int, error) {
+	exp, ok := exponents[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+	return exp, nil
+}
+
+// RoundingMode decides where an amount between two units goes.
+type RoundingMode int
+
+const (
+	// HalfUp rounds to the nearest unit, and halves away from zero.
+	HalfUp RoundingMode = iota
+	// HalfEven rounds to the nearest unit, and halves to the even one.
+	HalfEven
+	// Down truncates towards zero.
+	Down
+)
+
+// Money is an amount in minor units of Currency, e.g. 12550 PKR is Rs 125.50.
+// The zero value has no currency and stands for "no amount".
+type Money struct {
+	Currency string `json:"currency_code"`
+	Minor    int64  `json:"minor_units"`
+}
+
+// New returns minor units of currency.
+func New(currency string, minor int64) (Money, error) {
+	if _, err := Exponent(currency); err != nil {
+		return Money{}, err
+	}
+	return Money{Currency: currency, Minor: minor}, nil
+}
+
+// FromMajor returns whole major units of currency, e.g. FromMajor("PKR", 125)
+// is Rs 125.00.
+func FromMajor(currency string, major int64) (Money, error) {
+	exp, err := Exponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	minor, ok := mul(major, pow10(exp))
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Currency: currency, Minor: minor}, nil
+}
+
+func (m Money) IsZero() bool     { return m.Minor == 0 }
+func (m Money) IsNegative() bool { return m.Minor < 0 }
+func (m Money) IsPositive() bool { return m.Minor > 0 }
+
+// Add returns m + o. Both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.Minor + o.Minor
+	// Overflow flips the sign away from that of both operands
+	if (m.Minor >= 0) == (o.Minor >= 0) && (sum >= 0) != (m.Minor >= 0) {
+		return Money{}, ErrOverflow
+	}
+	return Money{Currency: m.Currency, Minor: sum}, nil
+}
+
+// Sub returns m - o. Both must be in the same currency.
+func (m Money) Sub(o Money) (Money, error) {
+	if o.Minor == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(Money{Currency: o.Currency, Minor: -o.Minor})
+}
+
+// Mul returns m times n.
+func (m Money) Mul(n int64) (Money, error) {
+	minor, ok := mul(m.Minor, n)
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Currency: m.Currency, Minor: minor}, nil
+}
+
+// MulRatio returns m times num/den, rounded with mode to a whole minor unit.
+// The intermediate product cannot overflow, e.g. MulRatio(12500, 10000,
+// HalfUp) applies a 1.25x multiplier given in basis points.
+func (m Money) MulRatio(num, den int64, mode RoundingMode) (Money, error) {
+	return m.mulRatio(num, den, 1, mode)
+}
+
+// MulRatioMajor is MulRatio rounded once to a whole major unit instead, so an
+// amount kept in whole units stays whole without being rounded twice.
+func (m Money) MulRatioMajor(num, den int64, mode RoundingMode) (Money, error) {
+	exp, err := Exponent(m.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return m.mulRatio(num, den, pow10(exp), mode)
+}
+
+func (m Money) mulRatio(num, den, unit int64, mode RoundingMode) (Money, error) {
+	if den == 0 {
+		return Money{}, fmt.Errorf("%w: zero denominator", ErrInvalidAmount)
+	}
+	product := new(big.Int).Mul(big.NewInt(m.Minor), big.NewInt(num))
+	divisor := new(big.Int).Mul(big.NewInt(den), big.NewInt(unit))
+	units, ok := divRound(product, divisor, mode)
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Currency: m.Currency, Minor: units}.Mul(unit)
+}
+
+// RoundMajor rounds m to whole major units with mode.
+func (m Money) RoundMajor(mode RoundingMode) (Money, error) {
+	return m.MulRatioMajor(1, 1, mode)
+}
+
+// Major returns m in whole major units, rounded with mode.
+func (m Money) Major(mode RoundingMode) (int64, error) {
+	exp, err := Exponent(m.Currency)
+	if err != nil {
+		return 0, err
+	}
+	major, ok := divRound(big.NewInt(m.Minor), big.NewInt(pow10(exp)), mode)
+	if !ok {
+		return 0, ErrOverflow
+	}
+	return major, nil
+}
+
+// Cmp returns -1, 0 or 1 as m is less than, equal to or greater than o.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Minor < o.Minor:
+		return -1, nil
+	case m.Minor > o.Minor:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// String formats m with its currency code, thousands separators and every
+// minor digit, e.g. "PKR 1,234.50" or "PKR -5.00".
+func (m Money) String() string {
+	exp, err := Exponent(m.Currency)
+	if err != nil {
+		return fmt.Sprintf("%s %d", m.Currency, m.Minor)
+	}
+
+	digits := strconv.FormatUint(absUint(m.Minor), 10)
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-exp], digits[len(digits)-exp:]
+
+	var b strings.Builder
+	b.WriteString(m.Currency)
+	b.WriteByte(' ')
+	if m.Minor < 0 {
+		b.WriteByte('-')
+	}
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	if exp > 0 {
+		b.WriteByte('.')
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// Parse reads an amount formatted by String. Thousands separators are
+// optional, but the fraction must not have more digits than the currency.
+func Parse(s string) (Money, error) {
+	currency, amount, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return Money{}, fmt.Errorf("%w %q: want \"<currency> <amount>\"", ErrInvalidAmount, s)
+	}
+	exp, err := Exponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	amount = strings.ReplaceAll(amount, ",", "")
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+	whole, frac, _ := strings.Cut(amount, ".")
+	if whole == "" || len(frac) > exp || strings.ContainsAny(whole+frac, "+-") {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	digits := whole + frac
+	if negative {
+		digits = "-" + digits
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return Money{}, ErrOverflow
+		}
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	return Money{Currency: currency, Minor: minor}, nil
+}
+
+// ToProto converts m to its wire form.
+func ToProto(m Money) *moneypb.Money {
+	return &moneypb.Money{CurrencyCode: m.Currency, MinorUnits: m.Minor}
+}
+
+// FromProto converts a wire amount, checking its currency.
+func FromProto(p *moneypb.Money) (Money, error) {
+	if p == nil {
+		return Money{}, ErrMissing
+	}
+	return New(p.CurrencyCode, p.MinorUnits)
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency != o.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return nil
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+func mul(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return c, true
+}
+
+// divRound divides n by d, rounding with mode, and reports whether the
+// quotient fits in an int64.
+func divRound(n, d *big.Int, mode RoundingMode) (int64, bool) {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() != 0 && mode != Down {
+		// Compare twice the remainder with the divisor to find the nearer unit
+		twice := new(big.Int).Abs(r)
+		twice.Lsh(twice, 1)
+		cmp := twice.Cmp(new(big.Int).Abs(d))
+		if cmp > 0 || (cmp == 0 && (mode == HalfUp || q.Bit(0) == 1)) {
+			if n.Sign()*d.Sign() < 0 {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+	if !q.IsInt64() {
+		return 0, false
+	}
+	return q.Int64(), true
+}
+
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
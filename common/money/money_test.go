@@ -0,0 +1,190 @@
+package money
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pkr(minor int64) Money {
+	return Money{Currency: PKR, Minor: minor}
+}
+
+func TestFromMajor(t *testing.T) {
+	m, err := FromMajor(PKR, 125)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(12500), m)
+
+	m, err = FromMajor("JPY", 125)
+	require.NoError(t, err)
+	assert.Equal(t, int64(125), m.Minor)
+
+	_, err = FromMajor("XXX", 1)
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	_, err = FromMajor(PKR, math.MaxInt64/10)
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestAddSub(t *testing.T) {
+	sum, err := pkr(12550).Add(pkr(450))
+	require.NoError(t, err)
+	assert.Equal(t, pkr(13000), sum)
+
+	diff, err := pkr(100).Sub(pkr(250))
+	require.NoError(t, err)
+	assert.Equal(t, pkr(-150), diff)
+
+	_, err = pkr(1).Add(Money{Currency: "USD", Minor: 1})
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = pkr(math.MaxInt64).Add(pkr(1))
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = pkr(math.MinInt64).Sub(pkr(1))
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = pkr(0).Sub(pkr(math.MinInt64))
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestMul(t *testing.T) {
+	m, err := pkr(250).Mul(4)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(1000), m)
+
+	_, err = pkr(math.MaxInt64 / 2).Mul(3)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = pkr(math.MinInt64).Mul(-1)
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestMulRatio(t *testing.T) {
+	testCases := []struct {
+		name  string
+		minor int64
+		num   int64
+		mode  RoundingMode
+		want  int64
+	}{
+		{name: "Exact", minor: 1000, num: 12500, mode: HalfUp, want: 1250},
+		{name: "Half Up", minor: 3, num: 5000, mode: HalfUp, want: 2},
+		{name: "Half Up Negative", minor: -3, num: 5000, mode: HalfUp, want: -2},
+		{name: "Half Even Down", minor: 5, num: 5000, mode: HalfEven, want: 2},
+		{name: "Half Even Up", minor: 7, num: 5000, mode: HalfEven, want: 4},
+		{name: "Below Half", minor: 1, num: 4999, mode: HalfUp, want: 0},
+		{name: "Down", minor: 19, num: 5000, mode: Down, want: 9},
+		{name: "Down Negative", minor: -19, num: 5000, mode: Down, want: -9},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := pkr(tc.minor).MulRatio(tc.num, 10000, tc.mode)
+			require.NoError(t, err)
+			assert.Equal(t, pkr(tc.want), m)
+		})
+	}
+
+	// The intermediate product may exceed int64 as long as the result fits
+	m, err := pkr(math.MaxInt64/2).MulRatio(10000, 10000, HalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(math.MaxInt64/2), m)
+
+	_, err = pkr(math.MaxInt64/2).MulRatio(30000, 10000, HalfUp)
+	assert.ErrorIs(t, err, ErrOverflow)
+	_, err = pkr(1).MulRatio(1, 0, HalfUp)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
+
+func TestMulRatioMajor(t *testing.T) {
+	// Rs 2.00 x 1.2475 is Rs 2.495: rounding to paisa first would give
+	// Rs 2.50 and then Rs 3.00
+	m, err := pkr(200).MulRatioMajor(12475, 10000, HalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(200), m)
+
+	m, err = pkr(50000).MulRatioMajor(12500, 10000, HalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(62500), m)
+
+	m, err = Money{Currency: "JPY", Minor: 333}.MulRatioMajor(12500, 10000, HalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(416), m.Minor)
+}
+
+func TestRoundMajor(t *testing.T) {
+	m, err := pkr(12550).RoundMajor(HalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(12600), m)
+
+	m, err = pkr(12550).RoundMajor(HalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(12600), m)
+
+	m, err = pkr(12450).RoundMajor(HalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(12400), m)
+
+	m, err = pkr(-12599).RoundMajor(Down)
+	require.NoError(t, err)
+	assert.Equal(t, pkr(-12500), m)
+
+	major, err := pkr(12550).Major(HalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(126), major)
+}
+
+func TestCmp(t *testing.T) {
+	cmp, err := pkr(1).Cmp(pkr(2))
+	require.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	cmp, err = pkr(2).Cmp(pkr(2))
+	require.NoError(t, err)
+	assert.Zero(t, cmp)
+
+	_, err = pkr(1).Cmp(Money{Currency: "USD", Minor: 1})
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestStringAndParse(t *testing.T) {
+	testCases := []struct {
+		money Money
+		want  string
+	}{
+		{money: pkr(123456789), want: "PKR 1,234,567.89"},
+		{money: pkr(12550), want: "PKR 125.50"},
+		{money: pkr(5), want: "PKR 0.05"},
+		{money: pkr(0), want: "PKR 0.00"},
+		{money: pkr(-500), want: "PKR -5.00"},
+		{money: pkr(math.MinInt64), want: "PKR -92,233,720,368,547,758.08"},
+		{money: Money{Currency: "JPY", Minor: 1200}, want: "JPY 1,200"},
+		{money: Money{Currency: "KWD", Minor: 1005}, want: "KWD 1.005"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.money.String())
+
+			parsed, err := Parse(tc.want)
+			require.NoError(t, err)
+			assert.Equal(t, tc.money, parsed)
+		})
+	}
+
+	m, err := Parse("PKR 125.5")
+	require.NoError(t, err)
+	assert.Equal(t, pkr(12550), m)
+
+	for _, s := range []string{"125.50", "PKR 1.234", "PKR abc", "PKR -", "PKR --1", "XXX 1"} {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestProto(t *testing.T) {
+	m, err := FromProto(ToProto(pkr(12550)))
+	require.NoError(t, err)
+	assert.Equal(t, pkr(12550), m)
+
+	_, err = FromProto(nil)
+	assert.ErrorIs(t, err, ErrMissing)
+}
@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+)
+
+// Message is a single payload delivered on a subject.
+type Message struct {
+	Subject string
+	Data    []byte
+}
+
+// Handler processes a message received from a subscription.
+type Handler func(ctx context.Context, msg Message)
+
+// Subscription is an active subscription on a broker.
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Broker publishes messages to and subscribes to messages from subjects.
+// Subjects are dot separated tokens; subscriptions may use the NATS wildcards
+// "*" (exactly one token) and ">" (one or more trailing tokens).
+//
+// Every subscriber of Subscribe receives each message. QueueSubscribe shares
+// the messages among the subscribers that name the same queue group, so that
+// the replicas of a service handle each event once between them.
+type Broker interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+	Subscribe(subject string, handler Handler) (Subscription, error)
+	QueueSubscribe(subject, queue string, handler Handler) (Subscription, error)
+	Close() error
+}
+
+// NewBroker returns a NATS broker for nats:// URLs and an in-process broker when
+// url is empty.
+func NewBroker(ctx context.Context, url string) (Broker, error) {
+	switch {
+	case url == "":
+		return NewInProcessBroker(), nil
+	case strings.HasPrefix(url, "nats://"):
+		return DialNATS(ctx, url)
+	default:
+		return nil, fmt.Errorf("unsupported broker URL %q", url)
+	}
+}
+
+// InProcessBroker delivers messages synchronously to subscribers in the same
+// process. It is used for local development and tests.
+type InProcessBroker struct {
+	mu     sync.RWMutex
+	nextID int64
+	subs   map[int64]*inProcessSubscription
+}
+
+type inProcessSubscription struct {
+	broker  *InProcessBroker
+	id      int64
+	subject string
+	queue   string
+	handler Handler
+}
+
+func NewInProcessBroker() *InProcessBroker {
+	return &InProcessBroker{subs: make(map[int64]*inProcessSubscription)}
+}
+
+func (b *InProcessBroker) Publish(ctx context.Context, subject string, data []byte) error {
+	b.mu.RLock()
+	var handlers []Handler
+	queues := map[string][]Handler{}
+	for _, sub := range b.subs {
+		if !MatchSubject(sub.subject, subject) {
+			continue
+		}
+		if sub.queue != "" {
+			queues[sub.queue] = append(queues[sub.queue], sub.handler)
+		} else {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.mu.RUnlock()
+
+	// One member of each queue group gets the message
+	for _, members := range queues {
+		handlers = append(handlers, members[rand.IntN(len(members))])
+	}
+
+	for _, handler := range handlers {
+		handler(ctx, Message{Subject: subject, Data: data})
+	}
+	return nil
+}
+
+func (b *InProcessBroker) Subscribe(subject string, handler Handler) (Subscription, error) {
+	return b.subscribe(subject, "", handler), nil
+}
+
+func (b *InProcessBroker) QueueSubscribe(subject, queue string, handler Handler) (Subscription, error) {
+	if queue == "" {
+		return nil, fmt.Errorf("queue group is required")
+	}
+	return b.subscribe(subject, queue, handler), nil
+}
+
+func (b *InProcessBroker) subscribe(subject, queue string, handler Handler) *inProcessSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	sub := &inProcessSubscription{broker: b, id: b.nextID, subject: subject, queue: queue, handler: handler}
+	b.subs[sub.id] = sub
+	return sub
+}
+
+func (b *InProcessBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = make(map[int64]*inProcessSubscription)
+	return nil
+}
+
+func (s *inProcessSubscription) Unsubscribe() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	delete(s.broker.subs, s.id)
+	return nil
+}
+
+// MatchSubject reports whether subject matches pattern using NATS wildcard
+// semantics.
+func MatchSubject(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) {
+			return false
+		}
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
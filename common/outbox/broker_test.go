@@ -0,0 +1,92 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchSubject(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		subject string
+		match   bool
+	}{
+		{"booking.BookingCreated", "booking.BookingCreated", true},
+		{"booking.BookingCreated", "booking.BookingCancelled", false},
+		{"booking.*", "booking.BookingCreated", true},
+		{"booking.*", "booking", false},
+		{"*.BookingCreated", "booking.BookingCreated", true},
+		{"booking.>", "booking.BookingCreated", true},
+		{"booking.>", "booking", false},
+		{">", "ride.RideUpdated", true},
+		{"booking", "booking.BookingCreated", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.subject, func(t *testing.T) {
+			assert.Equal(t, tc.match, MatchSubject(tc.pattern, tc.subject))
+		})
+	}
+}
+
+func TestInProcessBroker_PublishSubscribe(t *testing.T) {
+	broker := NewInProcessBroker()
+	ctx := context.Background()
+
+	var received []Message
+	sub, err := broker.Subscribe("booking.*", func(ctx context.Context, msg Message) {
+		received = append(received, msg)
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, broker.Publish(ctx, "booking.BookingCreated", []byte(`{"id":1}`)))
+	require.NoError(t, broker.Publish(ctx, "ride.RideUpdated", []byte(`{"id":2}`)))
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "booking.BookingCreated", received[0].Subject)
+	assert.Equal(t, `{"id":1}`, string(received[0].Data))
+
+	require.NoError(t, sub.Unsubscribe())
+	require.NoError(t, broker.Publish(ctx, "booking.BookingCancelled", nil))
+	assert.Len(t, received, 1)
+}
+
+func TestInProcessBroker_QueueSubscribe(t *testing.T) {
+	broker := NewInProcessBroker()
+	ctx := context.Background()
+
+	// Each queue group gets one copy, and plain subscribers get theirs
+	counts := map[string]int{}
+	for _, name := range []string{"replica-1", "replica-2"} {
+		_, err := broker.QueueSubscribe("ride.>", "booking-service", func(ctx context.Context, msg Message) {
+			counts["booking-service"]++
+		})
+		require.NoError(t, err, name)
+	}
+	_, err := broker.QueueSubscribe("ride.>", "notification-service", func(ctx context.Context, msg Message) {
+		counts["notification-service"]++
+	})
+	require.NoError(t, err)
+	_, err = broker.Subscribe("ride.>", func(ctx context.Context, msg Message) {
+		counts["feed"]++
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, broker.Publish(ctx, "ride.RideUpdated", nil))
+	assert.Equal(t, map[string]int{"booking-service": 1, "notification-service": 1, "feed": 1}, counts)
+
+	_, err = broker.QueueSubscribe("ride.>", "", func(ctx context.Context, msg Message) {})
+	assert.Error(t, err)
+}
+
+func TestNewBroker(t *testing.T) {
+	broker, err := NewBroker(context.Background(), "")
+	require.NoError(t, err)
+	assert.IsType(t, &InProcessBroker{}, broker)
+
+	_, err = NewBroker(context.Background(), "kafka://localhost:9092")
+	assert.Error(t, err)
+}
@@ -0,0 +1,421 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	natsDialTimeout      = 5 * time.Second
+	natsReconnectWait    = 500 * time.Millisecond
+	natsMaxReconnectWait = 10 * time.Second
+)
+
+// NATSBroker is a minimal client for the NATS text protocol. It supports
+// PUB/SUB/UNSUB, queue groups and keep-alive PINGs, which is all the outbox
+// relay and event consumers need. When the connection drops it reconnects
+// with backoff and resubscribes; publishes fail until it is back.
+//
+// Each subscription's handler runs on its own goroutine, in message order, so
+// a slow handler does not hold up the connection or its keep-alives.
+type NATSBroker struct {
+	url     *url.URL
+	address string
+	// reconnectWait is the first wait before reconnecting, doubled on each
+	// failed attempt up to natsMaxReconnectWait.
+	reconnectWait time.Duration
+
+	// writeMu guards conn and writer, and orders subscribing against
+	// resubscribing on reconnect. It is taken before mu.
+	writeMu sync.Mutex
+	conn    net.Conn
+	writer  *bufio.Writer
+
+	mu     sync.Mutex
+	nextID int64
+	subs   map[int64]*natsSubscription
+	err    error
+	closed bool
+	done   chan struct{}
+}
+
+type natsSubscription struct {
+	broker  *NATSBroker
+	sid     int64
+	subject string
+	queue   string
+	handler Handler
+
+	mu      sync.Mutex
+	pending []Message
+	wake    chan struct{}
+	done    chan struct{}
+	stop    sync.Once
+}
+
+// DialNATS connects to a NATS server at a nats://host:port URL.
+func DialNATS(ctx context.Context, rawURL string) (*NATSBroker, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS URL: %w", err)
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	b := &NATSBroker{
+		url:           u,
+		address:       address,
+		reconnectWait: natsReconnectWait,
+		subs:          make(map[int64]*natsSubscription),
+		done:          make(chan struct{}),
+	}
+	reader, err := b.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	go b.run(reader)
+	return b, nil
+}
+
+// connect dials the server, resubscribes every subscription and makes the new
+// connection current.
+func (b *NATSBroker) connect(ctx context.Context) (*bufio.Reader, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", b.address)
+	if err != nil {
+		return nil, fmt.Errorf("connect to NATS: %w", err)
+	}
+	reader, writer := bufio.NewReader(conn), bufio.NewWriter(conn)
+	if err := handshake(reader, writer, b.url); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+
+	b.mu.Lock()
+	for _, sub := range b.subs {
+		writer.WriteString(sub.command())
+	}
+	b.mu.Unlock()
+	if err := writer.Flush(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("resubscribe to NATS: %w", err)
+	}
+
+	b.conn, b.writer = conn, writer
+	b.mu.Lock()
+	b.err = nil
+	b.mu.Unlock()
+	return reader, nil
+}
+
+func handshake(reader *bufio.Reader, writer *bufio.Writer, u *url.URL) error {
+	line, err := readLine(reader)
+	if err != nil {
+		return fmt.Errorf("read NATS INFO: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO") {
+		return fmt.Errorf("unexpected NATS greeting: %q", line)
+	}
+
+	options := map[string]any{"verbose": false, "pedantic": false, "lang": "go", "protocol": 1}
+	if u.User != nil {
+		options["user"] = u.User.Username()
+		if pass, ok := u.User.Password(); ok {
+			options["pass"] = pass
+		}
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(writer, "CONNECT %s\r\nPING\r\n", connect)
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("send NATS CONNECT: %w", err)
+	}
+
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return fmt.Errorf("read NATS handshake: %w", err)
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS rejected connection: %s", line)
+		}
+	}
+}
+
+// run reads from the connection, reconnecting whenever it fails, until the
+// broker is closed.
+func (b *NATSBroker) run(reader *bufio.Reader) {
+	for reader != nil {
+		err := b.readLoop(reader)
+		b.fail(err)
+		reader = b.reconnect()
+	}
+}
+
+// reconnect retries connecting with backoff. It returns nil once the broker
+// is closed.
+func (b *NATSBroker) reconnect() *bufio.Reader {
+	wait := b.reconnectWait
+	for {
+		select {
+		case <-b.done:
+			return nil
+		case <-time.After(wait):
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), natsDialTimeout)
+		reader, err := b.connect(ctx)
+		cancel()
+		if err == nil {
+			return reader
+		}
+		b.fail(err)
+		wait = min(2*wait, natsMaxReconnectWait)
+	}
+}
+
+func (b *NATSBroker) Publish(ctx context.Context, subject string, data []byte) error {
+	if err := b.connErr(); err != nil {
+		return err
+	}
+
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	fmt.Fprintf(b.writer, "PUB %s %d\r\n", subject, len(data))
+	b.writer.Write(data)
+	b.writer.WriteString("\r\n")
+	return b.writer.Flush()
+}
+
+func (b *NATSBroker) Subscribe(subject string, handler Handler) (Subscription, error) {
+	return b.subscribe(subject, "", handler)
+}
+
+func (b *NATSBroker) QueueSubscribe(subject, queue string, handler Handler) (Subscription, error) {
+	if queue == "" {
+		return nil, fmt.Errorf("queue group is required")
+	}
+	return b.subscribe(subject, queue, handler)
+}
+
+func (b *NATSBroker) subscribe(subject, queue string, handler Handler) (Subscription, error) {
+	if err := b.connErr(); err != nil {
+		return nil, err
+	}
+
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+
+	b.mu.Lock()
+	b.nextID++
+	sub := &natsSubscription{
+		broker:  b,
+		sid:     b.nextID,
+		subject: subject,
+		queue:   queue,
+		handler: handler,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	b.subs[sub.sid] = sub
+	b.mu.Unlock()
+
+	b.writer.WriteString(sub.command())
+	if err := b.writer.Flush(); err != nil {
+		b.mu.Lock()
+		delete(b.subs, sub.sid)
+		b.mu.Unlock()
+		return nil, err
+	}
+	go sub.run()
+	return sub, nil
+}
+
+func (b *NATSBroker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.done)
+	for _, sub := range b.subs {
+		sub.close()
+	}
+	b.mu.Unlock()
+
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	return b.conn.Close()
+}
+
+func (s *natsSubscription) Unsubscribe() error {
+	s.broker.mu.Lock()
+	delete(s.broker.subs, s.sid)
+	s.broker.mu.Unlock()
+	s.close()
+	// A subscription dropped while disconnected is simply not resubscribed
+	if s.broker.connErr() != nil {
+		return nil
+	}
+	return s.broker.write(fmt.Sprintf("UNSUB %d\r\n", s.sid))
+}
+
+// command is the SUB line that registers s with the server.
+func (s *natsSubscription) command() string {
+	if s.queue != "" {
+		return fmt.Sprintf("SUB %s %s %d\r\n", s.subject, s.queue, s.sid)
+	}
+	return fmt.Sprintf("SUB %s %d\r\n", s.subject, s.sid)
+}
+
+// deliver queues msg for the subscription's handler without blocking.
+func (s *natsSubscription) deliver(msg Message) {
+	s.mu.Lock()
+	s.pending = append(s.pending, msg)
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run hands queued messages to the handler one at a time until the
+// subscription is closed.
+func (s *natsSubscription) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+		}
+		for {
+			s.mu.Lock()
+			if len(s.pending) == 0 {
+				s.mu.Unlock()
+				break
+			}
+			msg := s.pending[0]
+			s.pending[0] = Message{}
+			s.pending = s.pending[1:]
+			s.mu.Unlock()
+
+			s.handler(context.Background(), msg)
+		}
+	}
+}
+
+func (s *natsSubscription) close() {
+	s.stop.Do(func() { close(s.done) })
+}
+
+func (b *NATSBroker) write(s string) error {
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	b.writer.WriteString(s)
+	return b.writer.Flush()
+}
+
+func (b *NATSBroker) connErr() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return fmt.Errorf("NATS broker is closed")
+	}
+	return b.err
+}
+
+// readLoop handles server messages until the connection fails.
+func (b *NATSBroker) readLoop(reader *bufio.Reader) error {
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case strings.HasPrefix(line, "MSG "):
+			if err := b.dispatch(reader, line); err != nil {
+				return err
+			}
+		case line == "PING":
+			if err := b.write("PONG\r\n"); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS error: %s", line)
+		}
+	}
+}
+
+// dispatch handles "MSG <subject> <sid> [reply-to] <#bytes>" and its payload.
+func (b *NATSBroker) dispatch(reader *bufio.Reader, line string) error {
+	fields := strings.Fields(line)
+	if len(fields) != 4 && len(fields) != 5 {
+		return fmt.Errorf("malformed NATS MSG: %q", line)
+	}
+
+	sid, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed NATS sid: %q", line)
+	}
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return fmt.Errorf("malformed NATS size: %q", line)
+	}
+
+	payload := make([]byte, size+2)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	sub, ok := b.subs[sid]
+	b.mu.Unlock()
+	if ok {
+		sub.deliver(Message{Subject: fields[1], Data: payload[:size]})
+	}
+	return nil
+}
+
+// fail records that the connection is down and closes it, so that publishes
+// fail fast until reconnect succeeds.
+func (b *NATSBroker) fail(err error) {
+	b.mu.Lock()
+	b.err = fmt.Errorf("NATS connection lost: %w", err)
+	b.mu.Unlock()
+
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+	if b.conn != nil {
+		b.conn.Close()
+	}
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,226 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNATSServer accepts connections one at a time and echoes every PUB back
+// as a MSG to the subscriptions whose subject matches it. It records the
+// SUB lines it receives, and its current connection can be dropped or pinged.
+type fakeNATSServer struct {
+	url  string
+	subs chan string
+	pong chan struct{}
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newFakeNATSServer(t *testing.T) *fakeNATSServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	s := &fakeNATSServer{
+		url:  "nats://" + listener.Addr().String(),
+		subs: make(chan string, 10),
+		pong: make(chan struct{}, 1),
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conn = conn
+			s.mu.Unlock()
+			s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeNATSServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	fmt.Fprint(conn, "INFO {\"server_id\":\"fake\"}\r\n")
+
+	sids := map[string]string{}
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		switch {
+		case line == "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case line == "PONG":
+			s.pong <- struct{}{}
+		case fields[0] == "SUB":
+			sids[fields[1]] = fields[len(fields)-1]
+			s.subs <- line
+		case fields[0] == "PUB":
+			var size int
+			fmt.Sscanf(fields[2], "%d", &size)
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			for pattern, sid := range sids {
+				if MatchSubject(pattern, fields[1]) {
+					fmt.Fprintf(conn, "MSG %s %s %d\r\n%s\r\n", fields[1], sid, size, payload[:size])
+				}
+			}
+		}
+	}
+}
+
+// drop closes the current connection, as a server restart would.
+func (s *fakeNATSServer) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conn.Close()
+}
+
+// ping sends the client a keep-alive PING.
+func (s *fakeNATSServer) ping() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprint(s.conn, "PING\r\n")
+}
+
+func receive(t *testing.T, messages <-chan Message) Message {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+		return Message{}
+	}
+}
+
+func TestNATSBroker_PublishSubscribe(t *testing.T) {
+	server := newFakeNATSServer(t)
+	ctx := context.Background()
+
+	broker, err := DialNATS(ctx, server.url)
+	require.NoError(t, err)
+	defer broker.Close()
+
+	received := make(chan Message, 1)
+	_, err = broker.Subscribe("booking.BookingCreated", func(ctx context.Context, msg Message) {
+		received <- msg
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "SUB booking.BookingCreated 1", <-server.subs)
+
+	require.NoError(t, broker.Publish(ctx, "booking.BookingCreated", []byte(`{"booking_id":7}`)))
+
+	msg := receive(t, received)
+	assert.Equal(t, "booking.BookingCreated", msg.Subject)
+	assert.Equal(t, `{"booking_id":7}`, string(msg.Data))
+}
+
+func TestNATSBroker_QueueSubscribe(t *testing.T) {
+	server := newFakeNATSServer(t)
+
+	broker, err := DialNATS(context.Background(), server.url)
+	require.NoError(t, err)
+	defer broker.Close()
+
+	_, err = broker.QueueSubscribe("ride.RideUpdated", "booking-service", func(ctx context.Context, msg Message) {})
+	require.NoError(t, err)
+	assert.Equal(t, "SUB ride.RideUpdated booking-service 1", <-server.subs)
+
+	_, err = broker.QueueSubscribe("ride.RideUpdated", "", func(ctx context.Context, msg Message) {})
+	assert.Error(t, err)
+}
+
+func TestNATSBroker_Reconnect(t *testing.T) {
+	server := newFakeNATSServer(t)
+	ctx := context.Background()
+
+	broker, err := DialNATS(ctx, server.url)
+	require.NoError(t, err)
+	broker.reconnectWait = 10 * time.Millisecond
+	defer broker.Close()
+
+	received := make(chan Message, 1)
+	_, err = broker.QueueSubscribe("booking.>", "notifier", func(ctx context.Context, msg Message) {
+		received <- msg
+	})
+	require.NoError(t, err)
+	<-server.subs
+
+	// The subscription is renewed on the new connection, and publishing
+	// works again once it is up
+	server.drop()
+	assert.Equal(t, "SUB booking.> notifier 1", <-server.subs)
+	require.Eventually(t, func() bool {
+		return broker.Publish(ctx, "booking.BookingCreated", []byte(`{}`)) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "booking.BookingCreated", receive(t, received).Subject)
+
+	require.NoError(t, broker.Close())
+	assert.Error(t, broker.Publish(ctx, "booking.BookingCreated", nil))
+}
+
+func TestNATSBroker_SlowHandler(t *testing.T) {
+	server := newFakeNATSServer(t)
+	ctx := context.Background()
+
+	broker, err := DialNATS(ctx, server.url)
+	require.NoError(t, err)
+	defer broker.Close()
+
+	release := make(chan struct{})
+	defer close(release)
+	received := make(chan Message, 2)
+	_, err = broker.Subscribe("booking.BookingCreated", func(ctx context.Context, msg Message) {
+		received <- msg
+		<-release
+	})
+	require.NoError(t, err)
+	<-server.subs
+
+	require.NoError(t, broker.Publish(ctx, "booking.BookingCreated", []byte(`1`)))
+	require.NoError(t, broker.Publish(ctx, "booking.BookingCreated", []byte(`2`)))
+	assert.Equal(t, "1", string(receive(t, received).Data))
+
+	// The handler is still busy, but keep-alives are answered
+	server.ping()
+	select {
+	case <-server.pong:
+	case <-time.After(5 * time.Second):
+		t.Fatal("PING was not answered while a handler was busy")
+	}
+
+	// Queued messages follow in order
+	release <- struct{}{}
+	assert.Equal(t, "2", string(receive(t, received).Data))
+}
+
+func TestDialNATS_Unreachable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := DialNATS(ctx, "nats://127.0.0.1:1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event is a domain event stored in a service's outbox table. It is also the
+// JSON envelope published to the broker.
+type Event struct {
+	ID            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// Subject returns the broker subject an event is published on, e.g.
+// "booking.BookingCreated".
+func (e Event) Subject() string {
+	return Subject(e.AggregateType, e.EventType)
+}
+
+// Subject builds the broker subject for an aggregate type and event type.
+func Subject(aggregateType, eventType string) string {
+	return fmt.Sprintf("%s.%s", aggregateType, eventType)
+}
+
+// Record writes an event to the outbox table as part of tx, so the event is
+// persisted if and only if the surrounding state change commits.
+func Record(ctx context.Context, tx *sql.Tx, aggregateType, aggregateID, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s payload: %w", eventType, err)
+	}
+
+	query := `INSERT INTO outbox (aggregate_type, aggregate_id, event_type, payload) VALUES ($1, $2, $3, $4)`
+	if _, err := tx.ExecContext(ctx, query, aggregateType, aggregateID, eventType, data); err != nil {
+		return fmt.Errorf("record %s event: %w", eventType, err)
+	}
+	return nil
+}
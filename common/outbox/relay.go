@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+)
+
+const (
+	defaultRelayInterval  = time.Second
+	defaultRelayBatchSize = 100
+)
+
+// Relay polls the outbox table and publishes undelivered events to a broker in
+// insertion order, marking each one delivered once the broker accepted it.
+// Delivery is at-least-once: an event may be published again if the relay
+// stops between publishing and committing.
+type Relay struct {
+	db        *sql.DB
+	broker    Broker
+	logger    *logger.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+func NewRelay(db *sql.DB, broker Broker, log *logger.Logger) *Relay {
+	return &Relay{
+		db:        db,
+		broker:    broker,
+		logger:    log,
+		interval:  defaultRelayInterval,
+		batchSize: defaultRelayBatchSize,
+	}
+}
+
+// Run relays events until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := r.RelayOnce(ctx)
+			if err != nil {
+				r.logger.Error("outbox relay failed", "error", err)
+				break
+			}
+			if n < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes a single batch of undelivered events and returns how many
+// were delivered. Rows are locked with SKIP LOCKED so several replicas can run
+// relays against the same table.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin outbox transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT event_id, aggregate_type, aggregate_id, event_type, payload, created_at
+		FROM outbox
+		WHERE delivered_at IS NULL
+		ORDER BY event_id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
+	rows, err := tx.QueryContext(ctx, query, r.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("query outbox: %w", err)
+	}
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.AggregateType, &e.AggregateID, &e.EventType, &payload, &e.OccurredAt); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan outbox event: %w", err)
+		}
+		e.Payload = payload
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate outbox: %w", err)
+	}
+
+	delivered := 0
+	var publishErr error
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			publishErr = fmt.Errorf("marshal event %d: %w", e.ID, err)
+			break
+		}
+		// Stop at the first failure so later events are not published ahead of
+		// an earlier one for the same aggregate.
+		if err := r.broker.Publish(ctx, e.Subject(), data); err != nil {
+			publishErr = fmt.Errorf("publish event %d: %w", e.ID, err)
+			break
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE outbox SET delivered_at = CURRENT_TIMESTAMP WHERE event_id = $1`, e.ID); err != nil {
+			publishErr = fmt.Errorf("mark event %d delivered: %w", e.ID, err)
+			break
+		}
+		delivered++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit outbox transaction: %w", err)
+	}
+	return delivered, publishErr
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"log"
+	"github.com/joho/godotenv"
+
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+)
+
+type Config struct {
+	DBUrl string
+
+	// DBPool holds the DB_* connection pool settings.
+	DBPool database.PoolConfig
+	// Log holds the LOG_* logging settings.
+	Log logger.Settings
+	// AdminToken is the bearer token of the admin server's debug endpoints,
+	// which are disabled while it is empty.
+	AdminToken string
+}
+
+func Load() Config {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	dbUser := os.Getenv("DB_USER")
+	dbPass := os.Getenv("DB_PASSWORD")
+	dbName := os.Getenv("DB_NAME")
+	dbHost := os.Getenv("DB_HOST")
+	dbPort := os.Getenv("DB_PORT")
+
+	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		dbUser, dbPass, dbHost, dbPort, dbName)
+
+	dbPool, err := database.PoolConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logSettings, err := logger.SettingsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return Config{
+		DBUrl: dbUrl,
+
+		DBPool:     dbPool,
+		Log:        logSettings,
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	}
+}
@@ -0,0 +1,33 @@
+CREATE TABLE drivers (
+  driver_id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL,
+  phone TEXT NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'OFFLINE',
+  lat DOUBLE PRECISION,
+  lng DOUBLE PRECISION,
+  location_updated_at TIMESTAMPTZ,
+  -- Booking the driver is currently assigned to
+  booking_id INTEGER UNIQUE
+);
+
+CREATE TABLE vehicles (
+  vehicle_id SERIAL PRIMARY KEY,
+  driver_id INTEGER NOT NULL UNIQUE REFERENCES drivers (driver_id) ON DELETE CASCADE,
+  vehicle_class VARCHAR(16) NOT NULL,
+  make TEXT NOT NULL,
+  model TEXT NOT NULL,
+  plate_number TEXT NOT NULL UNIQUE,
+  color TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX drivers_available_idx ON drivers (driver_id) WHERE status = 'AVAILABLE';
+
+INSERT INTO drivers (name, phone) VALUES
+('Imran', '+923001234567'),
+('Sana', '+923011234567'),
+('Bilal', '+923021234567');
+
+INSERT INTO vehicles (driver_id, vehicle_class, make, model, plate_number, color) VALUES
+(1, 'ECONOMY', 'Suzuki', 'Alto', 'KHI-1234', 'White'),
+(2, 'COMFORT', 'Toyota', 'Corolla', 'LHE-5678', 'Silver'),
+(3, 'PREMIUM', 'Honda', 'Civic', 'ISB-9012', 'Black');
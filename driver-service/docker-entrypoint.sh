@@ -0,0 +1,8 @@
+#!/bin/sh
+set -e
+
+echo "Starting Driver Service..."
+echo "Connecting to database at $DB_HOST:$DB_PORT"
+
+# Execute the binary
+./driver-service
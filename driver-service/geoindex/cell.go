@@ -0,0 +1,161 @@
+package geoindex
+
+import "math"
+
+const (
+	earthRadiusKm = 6371.0088
+	kmPerDegree   = earthRadiusKm * math.Pi / 180
+
+	// boundSlack shrinks ring distance bounds, since the parallel arc used to
+	// bound a column's distance slightly overestimates the great-circle one.
+	boundSlack = 0.99
+)
+
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// cell is a square of the lat/lng grid: row counts up from the south pole and
+// col eastwards from the antimeridian.
+type cell struct {
+	row int32
+	col int32
+}
+
+type grid struct {
+	sizeDeg float64
+	rows    int32
+	cols    int32
+}
+
+func newGrid(sizeDeg float64) grid {
+	return grid{
+		sizeDeg: sizeDeg,
+		rows:    int32(math.Ceil(180 / sizeDeg)),
+		cols:    int32(math.Ceil(360 / sizeDeg)),
+	}
+}
+
+func (g grid) cellOf(p Point) cell {
+	row := int32(math.Floor((p.Lat + 90) / g.sizeDeg))
+	if row < 0 {
+		row = 0
+	}
+	if row >= g.rows {
+		row = g.rows - 1
+	}
+	col := int32(math.Floor((p.Lng + 180) / g.sizeDeg))
+	return cell{row: row, col: g.wrapCol(col)}
+}
+
+func (g grid) wrapCol(col int32) int32 {
+	col %= g.cols
+	if col < 0 {
+		col += g.cols
+	}
+	return col
+}
+
+// span bounds a search of radiusKm around center: how many rows and columns
+// away a matching cell can be, and the smallest width in km of a cell and of a
+// degree of longitude, which bound the distance to cells farther out.
+type span struct {
+	rows    int32
+	cols    int32
+	cellKm  float64
+	lngKm   float64
+	maxRing int32
+}
+
+func (g grid) spanOf(center Point, radiusKm float64) span {
+	cellHeightKm := g.sizeDeg * kmPerDegree
+	maxLat := math.Min(90, math.Abs(center.Lat)+radiusKm/kmPerDegree)
+	cellWidthKm := cellHeightKm * math.Cos(maxLat*math.Pi/180)
+
+	// Cells n rows or columns away are at least n-1 whole cells away
+	s := span{
+		rows:   int32(radiusKm/cellHeightKm) + 1,
+		cols:   (g.cols - 1) / 2,
+		cellKm: cellWidthKm * boundSlack,
+		lngKm:  cellWidthKm / g.sizeDeg * boundSlack,
+	}
+	if cols := radiusKm / cellWidthKm; cellWidthKm > 0 && cols < float64(s.cols) {
+		s.cols = int32(cols) + 1
+	}
+	if s.cellKm <= 0 {
+		// Near a pole every column is close; only rows bound the search
+		s.cellKm = cellHeightKm * boundSlack
+	}
+	s.maxRing = s.rows
+	if s.cols > s.maxRing {
+		s.maxRing = s.cols
+	}
+	return s
+}
+
+// ringBoundKm is a lower bound on the distance from anywhere in the center
+// cell to any cell in ring r.
+func (s span) ringBoundKm(r int32) float64 {
+	if r <= 1 {
+		return 0
+	}
+	return float64(r-1) * s.cellKm
+}
+
+// cellBoundKm is a lower bound on the distance from p to any point in c.
+func (g grid) cellBoundKm(p Point, c cell, s span) float64 {
+	south := float64(c.row)*g.sizeDeg - 90
+	dLat := math.Max(0, math.Max(south-p.Lat, p.Lat-(south+g.sizeDeg)))
+
+	west := float64(c.col)*g.sizeDeg - 180
+	dLng := math.Mod(p.Lng-west+360, 360)
+	if dLng <= g.sizeDeg {
+		dLng = 0
+	} else {
+		dLng = math.Min(dLng-g.sizeDeg, 360-dLng)
+	}
+
+	return math.Max(dLat*kmPerDegree, dLng*s.lngKm)
+}
+
+// ring calls fn for each cell of the square ring r cells around center that
+// lies within the span.
+func (g grid) ring(center cell, r int32, s span, fn func(cell)) {
+	visit := func(dr, dc int32) {
+		row := center.row + dr
+		if row < 0 || row >= g.rows {
+			return
+		}
+		fn(cell{row: row, col: g.wrapCol(center.col + dc)})
+	}
+
+	if r == 0 {
+		visit(0, 0)
+		return
+	}
+
+	maxCol := min(r, s.cols)
+	for dr := -min(r, s.rows); dr <= min(r, s.rows); dr++ {
+		if dr == -r || dr == r {
+			for dc := -maxCol; dc <= maxCol; dc++ {
+				visit(dr, dc)
+			}
+		} else if r <= s.cols {
+			visit(dr, -r)
+			visit(dr, r)
+		}
+	}
+}
+
+// haversineKm is the great-circle distance between a and b.
+func haversineKm(a, b Point) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
@@ -0,0 +1,54 @@
+package geoindex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrid_CellOf(t *testing.T) {
+	g := newGrid(1)
+
+	assert.Equal(t, cell{row: 114, col: 247}, g.cellOf(Point{Lat: 24.86, Lng: 67.0}))
+	assert.Equal(t, cell{row: 0, col: 0}, g.cellOf(Point{Lat: -90, Lng: -180}))
+	// The north pole and antimeridian fold into the last row and first column
+	assert.Equal(t, cell{row: 179, col: 0}, g.cellOf(Point{Lat: 90, Lng: 180}))
+}
+
+func TestGrid_RingWrapsAntimeridian(t *testing.T) {
+	g := newGrid(1)
+	center := g.cellOf(Point{Lat: 0.5, Lng: 179.5})
+	s := g.spanOf(Point{Lat: 0.5, Lng: 179.5}, 150)
+
+	var cells []cell
+	g.ring(center, 1, s, func(c cell) { cells = append(cells, c) })
+
+	assert.Len(t, cells, 8)
+	assert.Contains(t, cells, cell{row: 90, col: 0})
+	assert.Contains(t, cells, cell{row: 91, col: 358})
+}
+
+func TestGrid_RingsCoverSpan(t *testing.T) {
+	g := newGrid(DefaultCellSizeDeg)
+	center := Point{Lat: 24.86, Lng: 67.0}
+	s := g.spanOf(center, 5)
+
+	seen := make(map[cell]int)
+	for r := int32(0); r <= s.maxRing; r++ {
+		g.ring(g.cellOf(center), r, s, func(c cell) { seen[c]++ })
+	}
+
+	// Every cell in the span is visited exactly once
+	assert.Len(t, seen, int((2*s.rows+1)*(2*s.cols+1)))
+	for c, n := range seen {
+		assert.Equal(t, 1, n, "cell %v", c)
+	}
+}
+
+func TestHaversineKm(t *testing.T) {
+	karachi := Point{Lat: 24.8607, Lng: 67.0011}
+	lahore := Point{Lat: 31.5204, Lng: 74.3587}
+
+	assert.InDelta(t, 1033, haversineKm(karachi, lahore), 1)
+	assert.Zero(t, haversineKm(karachi, karachi))
+}
@@ -0,0 +1,310 @@
+// Package geoindex is an in-memory spatial index of driver locations. It
+// buckets drivers into fixed-size lat/lng cells so nearest-driver queries only
+// look at the cells around a pickup instead of every driver.
+package geoindex
+
+import (
+	"context"
+	"hash/maphash"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultCellSizeDeg gives cells about 1.1 km tall; at Pakistan's latitudes
+// they are about 1 km wide.
+const DefaultCellSizeDeg = 0.01
+
+// shardCount spreads cells and drivers over independently locked shards so
+// location updates in different places do not contend.
+const shardCount = 64
+
+type Entry struct {
+	ID           int32
+	Location     Point
+	VehicleClass string
+	UpdatedAt    time.Time
+}
+
+type Match struct {
+	Entry
+	DistanceKm float64
+}
+
+type Query struct {
+	Center   Point
+	RadiusKm float64
+	// K is the most matches returned; 0 returns every match within RadiusKm.
+	K int
+	// Filter, if set, skips entries it returns false for. It is called with a
+	// shard lock held and must not call back into the Index.
+	Filter func(Entry) bool
+}
+
+type cellShard struct {
+	mu    sync.RWMutex
+	cells map[cell]map[int32]Entry
+}
+
+type driverShard struct {
+	mu    sync.Mutex
+	cells map[int32]cell
+}
+
+// Index is safe for concurrent use. Updates to one driver are serialised;
+// queries never block each other.
+type Index struct {
+	grid    grid
+	seed    maphash.Seed
+	cells   [shardCount]cellShard
+	drivers [shardCount]driverShard
+}
+
+func New(cellSizeDeg float64) *Index {
+	ix := &Index{grid: newGrid(cellSizeDeg), seed: maphash.MakeSeed()}
+	for i := range ix.cells {
+		ix.cells[i].cells = make(map[cell]map[int32]Entry)
+		ix.drivers[i].cells = make(map[int32]cell)
+	}
+	return ix
+}
+
+func (ix *Index) cellShard(c cell) *cellShard {
+	return &ix.cells[maphash.Comparable(ix.seed, c)%shardCount]
+}
+
+func (ix *Index) driverShard(id int32) *driverShard {
+	return &ix.drivers[uint32(id)%shardCount]
+}
+
+// Upsert adds the entry or moves an existing entry with the same ID.
+func (ix *Index) Upsert(e Entry) {
+	ds := ix.driverShard(e.ID)
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	to := ix.grid.cellOf(e.Location)
+	ix.put(to, e)
+
+	// Add before removing so queries never miss a moving driver; they drop
+	// the duplicate instead
+	if from, ok := ds.cells[e.ID]; ok && from != to {
+		ix.delete(from, e.ID)
+	}
+	ds.cells[e.ID] = to
+}
+
+// Remove drops the entry for id, if any.
+func (ix *Index) Remove(id int32) {
+	ds := ix.driverShard(id)
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if c, ok := ds.cells[id]; ok {
+		ix.delete(c, id)
+		delete(ds.cells, id)
+	}
+}
+
+func (ix *Index) Get(id int32) (Entry, bool) {
+	ds := ix.driverShard(id)
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	c, ok := ds.cells[id]
+	if !ok {
+		return Entry{}, false
+	}
+	cs := ix.cellShard(c)
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	e, ok := cs.cells[c][id]
+	return e, ok
+}
+
+func (ix *Index) Len() int {
+	n := 0
+	for i := range ix.drivers {
+		ds := &ix.drivers[i]
+		ds.mu.Lock()
+		n += len(ds.cells)
+		ds.mu.Unlock()
+	}
+	return n
+}
+
+// Prune removes entries last updated before cutoff and returns how many.
+func (ix *Index) Prune(cutoff time.Time) int {
+	removed := 0
+	for i := range ix.drivers {
+		ds := &ix.drivers[i]
+		ds.mu.Lock()
+		for id, c := range ds.cells {
+			cs := ix.cellShard(c)
+			cs.mu.RLock()
+			stale := cs.cells[c][id].UpdatedAt.Before(cutoff)
+			cs.mu.RUnlock()
+			if stale {
+				ix.delete(c, id)
+				delete(ds.cells, id)
+				removed++
+			}
+		}
+		ds.mu.Unlock()
+	}
+	return removed
+}
+
+// RunPrune removes entries older than maxAge every interval until ctx is done.
+func (ix *Index) RunPrune(ctx context.Context, maxAge, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			ix.Prune(now.Add(-maxAge))
+		}
+	}
+}
+
+func (ix *Index) put(c cell, e Entry) {
+	cs := ix.cellShard(c)
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	entries, ok := cs.cells[c]
+	if !ok {
+		entries = make(map[int32]Entry)
+		cs.cells[c] = entries
+	}
+	entries[e.ID] = e
+}
+
+func (ix *Index) delete(c cell, id int32) {
+	cs := ix.cellShard(c)
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	entries := cs.cells[c]
+	delete(entries, id)
+	if len(entries) == 0 {
+		delete(cs.cells, c)
+	}
+}
+
+// Nearest returns the entries within q.RadiusKm of q.Center, closest first,
+// up to q.K of them. Cells are searched in rings outwards from the center
+// until no unsearched cell can hold a closer entry.
+func (ix *Index) Nearest(q Query) []Match {
+	center := ix.grid.cellOf(q.Center)
+	s := ix.grid.spanOf(q.Center, q.RadiusKm)
+
+	var matches []Match
+	for r := int32(0); r <= s.maxRing; r++ {
+		bound := s.ringBoundKm(r)
+		if bound > q.RadiusKm {
+			break
+		}
+		if q.K > 0 && len(matches) == q.K && bound > matches[q.K-1].DistanceKm {
+			break
+		}
+
+		ix.grid.ring(center, r, s, func(c cell) {
+			// Skip cells, and then entries, that cannot beat the current
+			// farthest match; the latitude difference alone bounds distance
+			limit := q.RadiusKm
+			if q.K > 0 && len(matches) == q.K {
+				limit = matches[q.K-1].DistanceKm
+			}
+			if ix.grid.cellBoundKm(q.Center, c, s) > limit {
+				return
+			}
+
+			cs := ix.cellShard(c)
+			cs.mu.RLock()
+			defer cs.mu.RUnlock()
+
+			for _, e := range cs.cells[c] {
+				if math.Abs(e.Location.Lat-q.Center.Lat)*kmPerDegree > limit {
+					continue
+				}
+				if q.Filter != nil && !q.Filter(e) {
+					continue
+				}
+				d := haversineKm(q.Center, e.Location)
+				if d > limit {
+					continue
+				}
+				m := Match{Entry: e, DistanceKm: d}
+				if q.K > 0 {
+					matches = insertMatch(matches, m, q.K)
+					if len(matches) == q.K {
+						limit = matches[q.K-1].DistanceKm
+					}
+				} else {
+					matches = append(matches, m)
+				}
+			}
+		})
+	}
+
+	if q.K == 0 {
+		matches = sortMatches(matches)
+	}
+	return matches
+}
+
+// sortMatches orders unbounded matches by distance then ID, dropping the
+// farther match of an entry seen twice.
+func sortMatches(matches []Match) []Match {
+	sort.Slice(matches, func(i, j int) bool { return less(matches[i], matches[j]) })
+
+	seen := make(map[int32]bool, len(matches))
+	n := 0
+	for _, m := range matches {
+		if !seen[m.ID] {
+			seen[m.ID] = true
+			matches[n] = m
+			n++
+		}
+	}
+	return matches[:n]
+}
+
+// insertMatch adds m to matches, which are kept sorted by distance then ID
+// and capped at k. An entry seen twice while it moved between cells keeps its
+// closer match.
+func insertMatch(matches []Match, m Match, k int) []Match {
+	for i := range matches {
+		if matches[i].ID == m.ID {
+			if !less(m, matches[i]) {
+				return matches
+			}
+			matches = append(matches[:i], matches[i+1:]...)
+			break
+		}
+	}
+
+	if len(matches) == k && !less(m, matches[k-1]) {
+		return matches
+	}
+
+	i := sort.Search(len(matches), func(i int) bool { return less(m, matches[i]) })
+	if len(matches) < k {
+		matches = append(matches, Match{})
+	}
+	copy(matches[i+1:], matches[i:])
+	matches[i] = m
+	return matches
+}
+
+func less(a, b Match) bool {
+	if a.DistanceKm != b.DistanceKm {
+		return a.DistanceKm < b.DistanceKm
+	}
+	return a.ID < b.ID
+}
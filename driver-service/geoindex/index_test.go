@@ -0,0 +1,270 @@
+package geoindex
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var karachi = Point{Lat: 24.8607, Lng: 67.0011}
+
+func entry(id int32, lat, lng float64) Entry {
+	return Entry{ID: id, Location: Point{Lat: lat, Lng: lng}, VehicleClass: "ECONOMY", UpdatedAt: time.Now()}
+}
+
+func ids(matches []Match) []int32 {
+	res := make([]int32, len(matches))
+	for i, m := range matches {
+		res[i] = m.ID
+	}
+	return res
+}
+
+func TestIndex_Nearest(t *testing.T) {
+	ix := New(DefaultCellSizeDeg)
+	ix.Upsert(entry(1, 24.8610, 67.0015)) // ~50 m
+	ix.Upsert(entry(2, 24.8700, 67.0100)) // ~1.4 km
+	ix.Upsert(entry(3, 24.9000, 67.0500)) // ~6.6 km
+	ix.Upsert(entry(4, 25.3960, 68.3578)) // Hyderabad, ~150 km
+
+	matches := ix.Nearest(Query{Center: karachi, RadiusKm: 10, K: 2})
+	assert.Equal(t, []int32{1, 2}, ids(matches))
+	assert.InDelta(t, 0.05, matches[0].DistanceKm, 0.01)
+
+	// K = 0 returns everything within the radius
+	assert.Equal(t, []int32{1, 2, 3}, ids(ix.Nearest(Query{Center: karachi, RadiusKm: 10})))
+	assert.Equal(t, []int32{1, 2}, ids(ix.Nearest(Query{Center: karachi, RadiusKm: 5})))
+	assert.Empty(t, ix.Nearest(Query{Center: Point{Lat: 31.5204, Lng: 74.3587}, RadiusKm: 50, K: 5}))
+}
+
+func TestIndex_NearestFilter(t *testing.T) {
+	ix := New(DefaultCellSizeDeg)
+	ix.Upsert(entry(1, 24.8610, 67.0015))
+	premium := entry(2, 24.8700, 67.0100)
+	premium.VehicleClass = "PREMIUM"
+	ix.Upsert(premium)
+
+	matches := ix.Nearest(Query{
+		Center:   karachi,
+		RadiusKm: 10,
+		K:        1,
+		Filter:   func(e Entry) bool { return e.VehicleClass == "PREMIUM" },
+	})
+	assert.Equal(t, []int32{2}, ids(matches))
+}
+
+func TestIndex_UpsertMovesAndRemove(t *testing.T) {
+	ix := New(DefaultCellSizeDeg)
+	ix.Upsert(entry(1, 24.8610, 67.0015))
+	ix.Upsert(entry(1, 31.5204, 74.3587))
+
+	assert.Equal(t, 1, ix.Len())
+	assert.Empty(t, ix.Nearest(Query{Center: karachi, RadiusKm: 10}))
+	e, ok := ix.Get(1)
+	require.True(t, ok)
+	assert.Equal(t, 31.5204, e.Location.Lat)
+
+	ix.Remove(1)
+	ix.Remove(1)
+	assert.Zero(t, ix.Len())
+	_, ok = ix.Get(1)
+	assert.False(t, ok)
+}
+
+func TestIndex_Prune(t *testing.T) {
+	ix := New(DefaultCellSizeDeg)
+	now := time.Now()
+	stale := entry(1, 24.8610, 67.0015)
+	stale.UpdatedAt = now.Add(-10 * time.Minute)
+	ix.Upsert(stale)
+	ix.Upsert(entry(2, 24.8700, 67.0100))
+
+	assert.Equal(t, 1, ix.Prune(now.Add(-5*time.Minute)))
+	assert.Equal(t, []int32{2}, ids(ix.Nearest(Query{Center: karachi, RadiusKm: 10})))
+}
+
+// TestIndex_NearestMatchesBruteForce compares ring search with a full scan
+// over random drivers and pickups, including around the antimeridian.
+func TestIndex_NearestMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, area := range []struct {
+		name     string
+		lat, lng float64
+	}{
+		{name: "Karachi", lat: 24.86, lng: 67.0},
+		{name: "Antimeridian", lat: -17.7, lng: 179.99},
+		{name: "Far North", lat: 78.2, lng: 15.6},
+	} {
+		t.Run(area.name, func(t *testing.T) {
+			ix := New(DefaultCellSizeDeg)
+			var all []Entry
+			for i := int32(1); i <= 2000; i++ {
+				e := entry(i, area.lat+rng.Float64()-0.5, wrapLng(area.lng+rng.Float64()-0.5))
+				ix.Upsert(e)
+				all = append(all, e)
+			}
+
+			for i := 0; i < 200; i++ {
+				center := Point{Lat: area.lat + rng.Float64()*0.6 - 0.3, Lng: wrapLng(area.lng + rng.Float64()*0.6 - 0.3)}
+				radius := 1 + rng.Float64()*20
+				k := rng.Intn(10)
+
+				want := bruteForce(all, center, radius, k)
+				got := ix.Nearest(Query{Center: center, RadiusKm: radius, K: k})
+				assert.Equal(t, ids(want), ids(got), "center %v radius %.1f k %d", center, radius, k)
+			}
+		})
+	}
+}
+
+func TestIndex_ConcurrentUpdatesAndQueries(t *testing.T) {
+	ix := New(DefaultCellSizeDeg)
+	var wg sync.WaitGroup
+
+	for w := 0; w < 8; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(w)))
+			for i := 0; i < 2000; i++ {
+				id := int32(rng.Intn(500))
+				if rng.Intn(10) == 0 {
+					ix.Remove(id)
+				} else {
+					ix.Upsert(entry(id, 24.86+rng.Float64()*0.1, 67.0+rng.Float64()*0.1))
+				}
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				matches := ix.Nearest(Query{Center: karachi, RadiusKm: 15, K: 10})
+				seen := make(map[int32]bool)
+				for _, m := range matches {
+					assert.False(t, seen[m.ID], "driver %d returned twice", m.ID)
+					seen[m.ID] = true
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, ix.Len(), 500)
+}
+
+func bruteForce(all []Entry, center Point, radiusKm float64, k int) []Match {
+	var matches []Match
+	for _, e := range all {
+		if d := haversineKm(center, e.Location); d <= radiusKm {
+			matches = append(matches, Match{Entry: e, DistanceKm: d})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return less(matches[i], matches[j]) })
+	if k > 0 && len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+func wrapLng(lng float64) float64 {
+	if lng >= 180 {
+		return lng - 360
+	}
+	if lng < -180 {
+		return lng + 360
+	}
+	return lng
+}
+
+// benchmarkIndex spreads n drivers uniformly over a box of the given size in
+// degrees centred on Karachi.
+func benchmarkIndex(n int, sizeDeg float64) *Index {
+	rng := rand.New(rand.NewSource(1))
+	ix := New(DefaultCellSizeDeg)
+	for i := 0; i < n; i++ {
+		ix.Upsert(entry(int32(i+1),
+			karachi.Lat+(rng.Float64()-0.5)*sizeDeg,
+			karachi.Lng+(rng.Float64()-0.5)*sizeDeg))
+	}
+	return ix
+}
+
+func randomPickup(rng *rand.Rand, sizeDeg float64) Point {
+	return Point{
+		Lat: karachi.Lat + (rng.Float64()-0.5)*sizeDeg,
+		Lng: karachi.Lng + (rng.Float64()-0.5)*sizeDeg,
+	}
+}
+
+// 100k drivers over a ~35 km city or a ~1100 km region.
+var benchmarkAreas = []struct {
+	name    string
+	sizeDeg float64
+}{
+	{name: "City", sizeDeg: 0.3},
+	{name: "Region", sizeDeg: 10},
+}
+
+func BenchmarkNearest(b *testing.B) {
+	for _, area := range benchmarkAreas {
+		ix := benchmarkIndex(100_000, area.sizeDeg)
+		for _, q := range []struct {
+			k        int
+			radiusKm float64
+		}{
+			{k: 5, radiusKm: 10},
+			{k: 5, radiusKm: 50},
+			{k: 20, radiusKm: 50},
+		} {
+			b.Run(fmt.Sprintf("%s/k=%d/radius=%.0fkm", area.name, q.k, q.radiusKm), func(b *testing.B) {
+				rng := rand.New(rand.NewSource(2))
+				for i := 0; i < b.N; i++ {
+					ix.Nearest(Query{Center: randomPickup(rng, area.sizeDeg), RadiusKm: q.radiusKm, K: q.k})
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkUpsert(b *testing.B) {
+	ix := benchmarkIndex(100_000, 0.3)
+	rng := rand.New(rand.NewSource(2))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		p := randomPickup(rng, 0.3)
+		ix.Upsert(entry(int32(rng.Intn(100_000)+1), p.Lat, p.Lng))
+	}
+}
+
+// BenchmarkParallel mixes location updates and nearest queries from many
+// goroutines, as the service sees them.
+func BenchmarkParallel(b *testing.B) {
+	ix := benchmarkIndex(100_000, 0.3)
+	var seed int64
+	var mu sync.Mutex
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		mu.Lock()
+		seed++
+		rng := rand.New(rand.NewSource(seed))
+		mu.Unlock()
+
+		for pb.Next() {
+			p := randomPickup(rng, 0.3)
+			if rng.Intn(2) == 0 {
+				ix.Upsert(entry(int32(rng.Intn(100_000)+1), p.Lat, p.Lng))
+			} else {
+				ix.Nearest(Query{Center: p, RadiusKm: 10, K: 5})
+			}
+		}
+	})
+}
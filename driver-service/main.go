@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"driver-service/config"
+	"driver-service/geoindex"
+	pb "driver-service/pb/proto/driver"
+	"driver-service/repository"
+	"driver-service/server"
+
+	"github.com/hasnain-zafar/go-microservices/common/admin"
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+)
+
+func main() {
+	// Initialize Prometheus metrics
+	metrics.Init()
+	registry := metrics.NewRegistry("driver-service")
+
+	cfg := config.Load()
+	logger.Configure(cfg.Log)
+
+	// Wait for Postgres, which may still be starting
+	db, err := database.Open(context.Background(), "postgres", cfg.DBUrl, cfg.DBPool)
+	if err != nil {
+		log.Fatalf("❌ DB not reachable: %v", err)
+	}
+	defer db.Close()
+	registry.RegisterDBStats(db, "drivers_db")
+
+	fmt.Println("✅ Connected to drivers_db successfully")
+
+	// Serve metrics and, with ADMIN_TOKEN set, the debug endpoints
+	conns := admin.NewConnTracker()
+	adminServer := admin.NewServer("driver-service", cfg.AdminToken, registry,
+		admin.WithConfig(cfg),
+		admin.WithDB(db),
+		admin.WithConnections(conns),
+	)
+	go startAdminServer(adminServer, "driver-service", 2115)
+
+	driverRepo := repository.NewPostgresDriverRepository(db)
+
+	// Match drivers from an in-memory index of available drivers' locations,
+	// loaded from the database and pruned of stale locations
+	index := geoindex.New(geoindex.DefaultCellSizeDeg)
+	driverServer := server.NewDriverServer(driverRepo, index, server.WithMetrics(registry))
+	if err := driverServer.LoadIndex(context.Background()); err != nil {
+		log.Fatalf("❌ Failed to load driver index: %v", err)
+	}
+	go index.RunPrune(context.Background(), repository.StaleLocationAfter, time.Minute)
+	fmt.Printf("📍 Indexed %d available drivers\n", index.Len())
+
+	listener, err := net.Listen("tcp", ":50054")
+	if err != nil {
+		log.Fatalf("❌ Failed to listen on port 50054: %v", err)
+	}
+
+	// Tag every call with a request ID for its log lines
+	requestLogger := logger.NewLogger("driver-service")
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(conns),
+		grpc.ChainUnaryInterceptor(logger.UnaryServerInterceptor(requestLogger)),
+		grpc.ChainStreamInterceptor(logger.StreamServerInterceptor(requestLogger)),
+	)
+	pb.RegisterDriverServiceServer(grpcServer, driverServer)
+
+	reflection.Register(grpcServer)
+
+	fmt.Println("🚀 DriverService gRPC server listening on :50054")
+	if err := grpcServer.Serve(listener); err != nil {
+		log.Fatalf("❌ Failed to serve: %v", err)
+	}
+}
+
+func startAdminServer(adminServer *admin.Server, serviceName string, port int) {
+	fmt.Printf("📊 Admin server for %s starting on :%d\n", serviceName, port)
+	if err := adminServer.ListenAndServe(port); err != nil {
+		log.Fatalf("❌ Failed to start admin server: %v", err)
+	}
+}
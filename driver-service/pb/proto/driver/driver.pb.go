@@ -0,0 +1,967 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: proto/driver/driver.proto
+
+package pb
+
+import (
+	_ "github.com/hasnain-zafar/go-microservices/common/pb/proto/logging"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// LatLng is a WGS84 coordinate in decimal degrees.
+type LatLng struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lng           float64                `protobuf:"fixed64,2,opt,name=lng,proto3" json:"lng,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatLng) Reset() {
+	*x = LatLng{}
+	mi := &file_proto_driver_driver_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatLng) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatLng) ProtoMessage() {}
+
+func (x *LatLng) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatLng.ProtoReflect.Descriptor instead.
+func (*LatLng) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LatLng) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *LatLng) GetLng() float64 {
+	if x != nil {
+		return x.Lng
+	}
+	return 0
+}
+
+type Vehicle struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ECONOMY, COMFORT or PREMIUM.
+	VehicleClass  string `protobuf:"bytes,1,opt,name=vehicle_class,json=vehicleClass,proto3" json:"vehicle_class,omitempty"`
+	Make          string `protobuf:"bytes,2,opt,name=make,proto3" json:"make,omitempty"`
+	Model         string `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	PlateNumber   string `protobuf:"bytes,4,opt,name=plate_number,json=plateNumber,proto3" json:"plate_number,omitempty"`
+	Color         string `protobuf:"bytes,5,opt,name=color,proto3" json:"color,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vehicle) Reset() {
+	*x = Vehicle{}
+	mi := &file_proto_driver_driver_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vehicle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vehicle) ProtoMessage() {}
+
+func (x *Vehicle) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vehicle.ProtoReflect.Descriptor instead.
+func (*Vehicle) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Vehicle) GetVehicleClass() string {
+	if x != nil {
+		return x.VehicleClass
+	}
+	return ""
+}
+
+func (x *Vehicle) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *Vehicle) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Vehicle) GetPlateNumber() string {
+	if x != nil {
+		return x.PlateNumber
+	}
+	return ""
+}
+
+func (x *Vehicle) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+type Driver struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverId int32                  `protobuf:"varint,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Name     string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Phone    string                 `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"`
+	Vehicle  *Vehicle               `protobuf:"bytes,4,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	// OFFLINE, AVAILABLE or ASSIGNED.
+	Status string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	// Last reported location; unset until the driver reports one.
+	Location          *LatLng                `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
+	LocationUpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=location_updated_at,json=locationUpdatedAt,proto3" json:"location_updated_at,omitempty"`
+	// Booking the driver is assigned to while ASSIGNED.
+	BookingId     int32 `protobuf:"varint,8,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Driver) Reset() {
+	*x = Driver{}
+	mi := &file_proto_driver_driver_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Driver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Driver) ProtoMessage() {}
+
+func (x *Driver) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Driver.ProtoReflect.Descriptor instead.
+func (*Driver) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Driver) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *Driver) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Driver) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Driver) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+func (x *Driver) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Driver) GetLocation() *LatLng {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Driver) GetLocationUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LocationUpdatedAt
+	}
+	return nil
+}
+
+func (x *Driver) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+type CreateDriverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Phone         string                 `protobuf:"bytes,2,opt,name=phone,proto3" json:"phone,omitempty"`
+	Vehicle       *Vehicle               `protobuf:"bytes,3,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDriverRequest) Reset() {
+	*x = CreateDriverRequest{}
+	mi := &file_proto_driver_driver_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDriverRequest) ProtoMessage() {}
+
+func (x *CreateDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDriverRequest.ProtoReflect.Descriptor instead.
+func (*CreateDriverRequest) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateDriverRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateDriverRequest) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *CreateDriverRequest) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+type CreateDriverResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      int32                  `protobuf:"varint,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDriverResponse) Reset() {
+	*x = CreateDriverResponse{}
+	mi := &file_proto_driver_driver_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDriverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDriverResponse) ProtoMessage() {}
+
+func (x *CreateDriverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDriverResponse.ProtoReflect.Descriptor instead.
+func (*CreateDriverResponse) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateDriverResponse) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+type GetDriverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      int32                  `protobuf:"varint,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverRequest) Reset() {
+	*x = GetDriverRequest{}
+	mi := &file_proto_driver_driver_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverRequest) ProtoMessage() {}
+
+func (x *GetDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverRequest) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetDriverRequest) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+type SetAvailabilityRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverId int32                  `protobuf:"varint,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	// true to go online (AVAILABLE), false to go OFFLINE.
+	Online        bool `protobuf:"varint,2,opt,name=online,proto3" json:"online,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAvailabilityRequest) Reset() {
+	*x = SetAvailabilityRequest{}
+	mi := &file_proto_driver_driver_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAvailabilityRequest) ProtoMessage() {}
+
+func (x *SetAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*SetAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetAvailabilityRequest) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *SetAvailabilityRequest) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+type SetAvailabilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAvailabilityResponse) Reset() {
+	*x = SetAvailabilityResponse{}
+	mi := &file_proto_driver_driver_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAvailabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAvailabilityResponse) ProtoMessage() {}
+
+func (x *SetAvailabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAvailabilityResponse.ProtoReflect.Descriptor instead.
+func (*SetAvailabilityResponse) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SetAvailabilityResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type UpdateLocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      int32                  `protobuf:"varint,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Location      *LatLng                `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLocationRequest) Reset() {
+	*x = UpdateLocationRequest{}
+	mi := &file_proto_driver_driver_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLocationRequest) ProtoMessage() {}
+
+func (x *UpdateLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLocationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLocationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateLocationRequest) GetDriverId() int32 {
+	if x != nil {
+		return x.DriverId
+	}
+	return 0
+}
+
+func (x *UpdateLocationRequest) GetLocation() *LatLng {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type UpdateLocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLocationResponse) Reset() {
+	*x = UpdateLocationResponse{}
+	mi := &file_proto_driver_driver_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLocationResponse) ProtoMessage() {}
+
+func (x *UpdateLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLocationResponse.ProtoReflect.Descriptor instead.
+func (*UpdateLocationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateLocationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type AssignDriverRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	BookingId int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	Pickup    *LatLng                `protobuf:"bytes,2,opt,name=pickup,proto3" json:"pickup,omitempty"`
+	// Only drivers with this vehicle class are considered; any class when empty.
+	VehicleClass string `protobuf:"bytes,3,opt,name=vehicle_class,json=vehicleClass,proto3" json:"vehicle_class,omitempty"`
+	// Defaults to 10 km; at most 50 km.
+	MaxDistanceKm float64 `protobuf:"fixed64,4,opt,name=max_distance_km,json=maxDistanceKm,proto3" json:"max_distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignDriverRequest) Reset() {
+	*x = AssignDriverRequest{}
+	mi := &file_proto_driver_driver_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignDriverRequest) ProtoMessage() {}
+
+func (x *AssignDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignDriverRequest.ProtoReflect.Descriptor instead.
+func (*AssignDriverRequest) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AssignDriverRequest) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+func (x *AssignDriverRequest) GetPickup() *LatLng {
+	if x != nil {
+		return x.Pickup
+	}
+	return nil
+}
+
+func (x *AssignDriverRequest) GetVehicleClass() string {
+	if x != nil {
+		return x.VehicleClass
+	}
+	return ""
+}
+
+func (x *AssignDriverRequest) GetMaxDistanceKm() float64 {
+	if x != nil {
+		return x.MaxDistanceKm
+	}
+	return 0
+}
+
+// Assignment is the driver reserved for a booking. Repeating AssignDriver for
+// the same booking returns the same assignment.
+type Assignment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookingId     int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	Driver        *Driver                `protobuf:"bytes,2,opt,name=driver,proto3" json:"driver,omitempty"`
+	DistanceKm    float64                `protobuf:"fixed64,3,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Assignment) Reset() {
+	*x = Assignment{}
+	mi := &file_proto_driver_driver_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Assignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Assignment) ProtoMessage() {}
+
+func (x *Assignment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Assignment.ProtoReflect.Descriptor instead.
+func (*Assignment) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Assignment) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+func (x *Assignment) GetDriver() *Driver {
+	if x != nil {
+		return x.Driver
+	}
+	return nil
+}
+
+func (x *Assignment) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+type ReleaseDriverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BookingId     int32                  `protobuf:"varint,1,opt,name=booking_id,json=bookingId,proto3" json:"booking_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseDriverRequest) Reset() {
+	*x = ReleaseDriverRequest{}
+	mi := &file_proto_driver_driver_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseDriverRequest) ProtoMessage() {}
+
+func (x *ReleaseDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseDriverRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseDriverRequest) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReleaseDriverRequest) GetBookingId() int32 {
+	if x != nil {
+		return x.BookingId
+	}
+	return 0
+}
+
+type ReleaseDriverResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseDriverResponse) Reset() {
+	*x = ReleaseDriverResponse{}
+	mi := &file_proto_driver_driver_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseDriverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseDriverResponse) ProtoMessage() {}
+
+func (x *ReleaseDriverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_driver_driver_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseDriverResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseDriverResponse) Descriptor() ([]byte, []int) {
+	return file_proto_driver_driver_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReleaseDriverResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_proto_driver_driver_proto protoreflect.FileDescriptor
+
+const file_proto_driver_driver_proto_rawDesc = "" +
+	"\n" +
+	"\x19proto/driver/driver.proto\x12\x06driver\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bproto/logging/logging.proto\",\n" +
+	"\x06LatLng\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lng\x18\x02 \x01(\x01R\x03lng\"\x97\x01\n" +
+	"\aVehicle\x12#\n" +
+	"\rvehicle_class\x18\x01 \x01(\tR\fvehicleClass\x12\x12\n" +
+	"\x04make\x18\x02 \x01(\tR\x04make\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12'\n" +
+	"\fplate_number\x18\x04 \x01(\tB\x04\x88\xb5\x18\x01R\vplateNumber\x12\x14\n" +
+	"\x05color\x18\x05 \x01(\tR\x05color\"\xb5\x02\n" +
+	"\x06Driver\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\x05R\bdriverId\x12\x18\n" +
+	"\x04name\x18\x02 \x01(\tB\x04\x88\xb5\x18\x01R\x04name\x12\x1a\n" +
+	"\x05phone\x18\x03 \x01(\tB\x04\x88\xb5\x18\x01R\x05phone\x12)\n" +
+	"\avehicle\x18\x04 \x01(\v2\x0f.driver.VehicleR\avehicle\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12*\n" +
+	"\blocation\x18\x06 \x01(\v2\x0e.driver.LatLngR\blocation\x12J\n" +
+	"\x13location_updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x11locationUpdatedAt\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\b \x01(\x05R\tbookingId\"v\n" +
+	"\x13CreateDriverRequest\x12\x18\n" +
+	"\x04name\x18\x01 \x01(\tB\x04\x88\xb5\x18\x01R\x04name\x12\x1a\n" +
+	"\x05phone\x18\x02 \x01(\tB\x04\x88\xb5\x18\x01R\x05phone\x12)\n" +
+	"\avehicle\x18\x03 \x01(\v2\x0f.driver.VehicleR\avehicle\"3\n" +
+	"\x14CreateDriverResponse\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\x05R\bdriverId\"/\n" +
+	"\x10GetDriverRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\x05R\bdriverId\"M\n" +
+	"\x16SetAvailabilityRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\x05R\bdriverId\x12\x16\n" +
+	"\x06online\x18\x02 \x01(\bR\x06online\"1\n" +
+	"\x17SetAvailabilityResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"`\n" +
+	"\x15UpdateLocationRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\x05R\bdriverId\x12*\n" +
+	"\blocation\x18\x02 \x01(\v2\x0e.driver.LatLngR\blocation\"2\n" +
+	"\x16UpdateLocationResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xa9\x01\n" +
+	"\x13AssignDriverRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\x12&\n" +
+	"\x06pickup\x18\x02 \x01(\v2\x0e.driver.LatLngR\x06pickup\x12#\n" +
+	"\rvehicle_class\x18\x03 \x01(\tR\fvehicleClass\x12&\n" +
+	"\x0fmax_distance_km\x18\x04 \x01(\x01R\rmaxDistanceKm\"t\n" +
+	"\n" +
+	"Assignment\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\x12&\n" +
+	"\x06driver\x18\x02 \x01(\v2\x0e.driver.DriverR\x06driver\x12\x1f\n" +
+	"\vdistance_km\x18\x03 \x01(\x01R\n" +
+	"distanceKm\"5\n" +
+	"\x14ReleaseDriverRequest\x12\x1d\n" +
+	"\n" +
+	"booking_id\x18\x01 \x01(\x05R\tbookingId\"1\n" +
+	"\x15ReleaseDriverResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage2\xc5\x03\n" +
+	"\rDriverService\x12I\n" +
+	"\fCreateDriver\x12\x1b.driver.CreateDriverRequest\x1a\x1c.driver.CreateDriverResponse\x125\n" +
+	"\tGetDriver\x12\x18.driver.GetDriverRequest\x1a\x0e.driver.Driver\x12R\n" +
+	"\x0fSetAvailability\x12\x1e.driver.SetAvailabilityRequest\x1a\x1f.driver.SetAvailabilityResponse\x12O\n" +
+	"\x0eUpdateLocation\x12\x1d.driver.UpdateLocationRequest\x1a\x1e.driver.UpdateLocationResponse\x12?\n" +
+	"\fAssignDriver\x12\x1b.driver.AssignDriverRequest\x1a\x12.driver.Assignment\x12L\n" +
+	"\rReleaseDriver\x12\x1c.driver.ReleaseDriverRequest\x1a\x1d.driver.ReleaseDriverResponseB\x13Z\x11driver-service/pbb\x06proto3"
+
+var (
+	file_proto_driver_driver_proto_rawDescOnce sync.Once
+	file_proto_driver_driver_proto_rawDescData []byte
+)
+
+func file_proto_driver_driver_proto_rawDescGZIP() []byte {
+	file_proto_driver_driver_proto_rawDescOnce.Do(func() {
+		file_proto_driver_driver_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_driver_driver_proto_rawDesc), len(file_proto_driver_driver_proto_rawDesc)))
+	})
+	return file_proto_driver_driver_proto_rawDescData
+}
+
+var file_proto_driver_driver_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_proto_driver_driver_proto_goTypes = []any{
+	(*LatLng)(nil),                  // 0: driver.LatLng
+	(*Vehicle)(nil),                 // 1: driver.Vehicle
+	(*Driver)(nil),                  // 2: driver.Driver
+	(*CreateDriverRequest)(nil),     // 3: driver.CreateDriverRequest
+	(*CreateDriverResponse)(nil),    // 4: driver.CreateDriverResponse
+	(*GetDriverRequest)(nil),        // 5: driver.GetDriverRequest
+	(*SetAvailabilityRequest)(nil),  // 6: driver.SetAvailabilityRequest
+	(*SetAvailabilityResponse)(nil), // 7: driver.SetAvailabilityResponse
+	(*UpdateLocationRequest)(nil),   // 8: driver.UpdateLocationRequest
+	(*UpdateLocationResponse)(nil),  // 9: driver.UpdateLocationResponse
+	(*AssignDriverRequest)(nil),     // 10: driver.AssignDriverRequest
+	(*Assignment)(nil),              // 11: driver.Assignment
+	(*ReleaseDriverRequest)(nil),    // 12: driver.ReleaseDriverRequest
+	(*ReleaseDriverResponse)(nil),   // 13: driver.ReleaseDriverResponse
+	(*timestamppb.Timestamp)(nil),   // 14: google.protobuf.Timestamp
+}
+var file_proto_driver_driver_proto_depIdxs = []int32{
+	1,  // 0: driver.Driver.vehicle:type_name -> driver.Vehicle
+	0,  // 1: driver.Driver.location:type_name -> driver.LatLng
+	14, // 2: driver.Driver.location_updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: driver.CreateDriverRequest.vehicle:type_name -> driver.Vehicle
+	0,  // 4: driver.UpdateLocationRequest.location:type_name -> driver.LatLng
+	0,  // 5: driver.AssignDriverRequest.pickup:type_name -> driver.LatLng
+	2,  // 6: driver.Assignment.driver:type_name -> driver.Driver
+	3,  // 7: driver.DriverService.CreateDriver:input_type -> driver.CreateDriverRequest
+	5,  // 8: driver.DriverService.GetDriver:input_type -> driver.GetDriverRequest
+	6,  // 9: driver.DriverService.SetAvailability:input_type -> driver.SetAvailabilityRequest
+	8,  // 10: driver.DriverService.UpdateLocation:input_type -> driver.UpdateLocationRequest
+	10, // 11: driver.DriverService.AssignDriver:input_type -> driver.AssignDriverRequest
+	12, // 12: driver.DriverService.ReleaseDriver:input_type -> driver.ReleaseDriverRequest
+	4,  // 13: driver.DriverService.CreateDriver:output_type -> driver.CreateDriverResponse
+	2,  // 14: driver.DriverService.GetDriver:output_type -> driver.Driver
+	7,  // 15: driver.DriverService.SetAvailability:output_type -> driver.SetAvailabilityResponse
+	9,  // 16: driver.DriverService.UpdateLocation:output_type -> driver.UpdateLocationResponse
+	11, // 17: driver.DriverService.AssignDriver:output_type -> driver.Assignment
+	13, // 18: driver.DriverService.ReleaseDriver:output_type -> driver.ReleaseDriverResponse
+	13, // [13:19] is the sub-list for method output_type
+	7,  // [7:13] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_proto_driver_driver_proto_init() }
+func file_proto_driver_driver_proto_init() {
+	if File_proto_driver_driver_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_driver_driver_proto_rawDesc), len(file_proto_driver_driver_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_driver_driver_proto_goTypes,
+		DependencyIndexes: file_proto_driver_driver_proto_depIdxs,
+		MessageInfos:      file_proto_driver_driver_proto_msgTypes,
+	}.Build()
+	File_proto_driver_driver_proto = out.File
+	file_proto_driver_driver_proto_goTypes = nil
+	file_proto_driver_driver_proto_depIdxs = nil
+}
@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	StatusOffline   = "OFFLINE"
+	StatusAvailable = "AVAILABLE"
+	StatusAssigned  = "ASSIGNED"
+)
+
+// StaleLocationAfter is how long a reported location is trusted for matching.
+const StaleLocationAfter = 5 * time.Minute
+
+type Location struct {
+	Lat float64
+	Lng float64
+}
+
+type Vehicle struct {
+	Class       string
+	Make        string
+	Model       string
+	PlateNumber string
+	Color       string
+}
+
+type Driver struct {
+	ID      int32
+	Name    string
+	Phone   string
+	Vehicle Vehicle
+	Status  string
+	// Location is nil until the driver reports one.
+	Location          *Location
+	LocationUpdatedAt time.Time
+	// BookingID is the booking the driver is assigned to, or 0.
+	BookingID int32
+}
+
+// Assignment is a driver reserved for a booking.
+type Assignment struct {
+	BookingID  int32
+	Driver     *Driver
+	DistanceKm float64
+}
+
+type DriverRepository interface {
+	Create(ctx context.Context, driver *Driver) (int32, error)
+	GetByID(ctx context.Context, id int32) (*Driver, error)
+	ListAvailable(ctx context.Context) ([]*Driver, error)
+	SetAvailability(ctx context.Context, id int32, online bool) (*Driver, error)
+	UpdateLocation(ctx context.Context, id int32, location Location) (*Driver, error)
+	Assign(ctx context.Context, bookingID int32, pickup Location, candidates []int32) (*Assignment, error)
+	Release(ctx context.Context, bookingID int32) (*Driver, error)
+}
+
+type PostgresDriverRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresDriverRepository(db *sql.DB) DriverRepository {
+	return &PostgresDriverRepository{db: db}
+}
+
+const (
+	driverColumns = `d.driver_id, d.name, d.phone, d.status, d.lat, d.lng, d.location_updated_at,
+		COALESCE(d.booking_id, 0), v.vehicle_class, v.make, v.model, v.plate_number, v.color`
+	driverTables = `drivers d JOIN vehicles v ON v.driver_id = d.driver_id`
+)
+
+// distanceKm is the haversine distance in km from a driver's location to the
+// point given by the $1 (lat) and $2 (lng) parameters.
+const distanceKm = `(2 * 6371.0088 * asin(sqrt(
+		power(sin(radians(d.lat - $1) / 2), 2) +
+		cos(radians($1)) * cos(radians(d.lat)) * power(sin(radians(d.lng - $2) / 2), 2))))`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDriver(row rowScanner, extra ...any) (*Driver, error) {
+	d := &Driver{}
+	var lat, lng sql.NullFloat64
+	var locationUpdatedAt sql.NullTime
+	dest := []any{&d.ID, &d.Name, &d.Phone, &d.Status, &lat, &lng, &locationUpdatedAt, &d.BookingID,
+		&d.Vehicle.Class, &d.Vehicle.Make, &d.Vehicle.Model, &d.Vehicle.PlateNumber, &d.Vehicle.Color}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if lat.Valid && lng.Valid {
+		d.Location = &Location{Lat: lat.Float64, Lng: lng.Float64}
+		d.LocationUpdatedAt = locationUpdatedAt.Time
+	}
+	return d, nil
+}
+
+func (r *PostgresDriverRepository) Create(ctx context.Context, driver *Driver) (int32, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Create driver failed: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var driverID int32
+	err = tx.QueryRowContext(ctx, `INSERT INTO drivers (name, phone, status) VALUES ($1, $2, $3) RETURNING driver_id`,
+		driver.Name, driver.Phone, StatusOffline).Scan(&driverID)
+	if err != nil {
+		log.Printf("Create driver failed: %v", err)
+		return 0, err
+	}
+
+	vehicle := driver.Vehicle
+	_, err = tx.ExecContext(ctx, `INSERT INTO vehicles (driver_id, vehicle_class, make, model, plate_number, color)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		driverID, vehicle.Class, vehicle.Make, vehicle.Model, vehicle.PlateNumber, vehicle.Color)
+	if err != nil {
+		log.Printf("Create driver failed: %v", err)
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Create driver failed: %v", err)
+		return 0, err
+	}
+	return driverID, nil
+}
+
+func (r *PostgresDriverRepository) GetByID(ctx context.Context, id int32) (*Driver, error) {
+	driver, err := scanDriver(r.db.QueryRowContext(ctx, `SELECT `+driverColumns+` FROM `+driverTables+` WHERE d.driver_id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("driver not found")
+		}
+		log.Printf("Get driver failed: %v", err)
+		return nil, err
+	}
+	return driver, nil
+}
+
+// ListAvailable returns the AVAILABLE drivers whose location is fresh.
+func (r *PostgresDriverRepository) ListAvailable(ctx context.Context) ([]*Driver, error) {
+	query := `SELECT ` + driverColumns + ` FROM ` + driverTables + `
+		WHERE d.status = $1 AND d.lat IS NOT NULL AND d.location_updated_at >= $2`
+	rows, err := r.db.QueryContext(ctx, query, StatusAvailable, time.Now().Add(-StaleLocationAfter))
+	if err != nil {
+		log.Printf("List available drivers failed: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drivers []*Driver
+	for rows.Next() {
+		driver, err := scanDriver(rows)
+		if err != nil {
+			log.Printf("List available drivers failed: %v", err)
+			return nil, err
+		}
+		drivers = append(drivers, driver)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("List available drivers failed: %v", err)
+		return nil, err
+	}
+	return drivers, nil
+}
+
+// SetAvailability takes a driver online (AVAILABLE) or OFFLINE and returns the
+// updated driver. Drivers cannot change availability while ASSIGNED.
+func (r *PostgresDriverRepository) SetAvailability(ctx context.Context, id int32, online bool) (*Driver, error) {
+	status := StatusOffline
+	if online {
+		status = StatusAvailable
+	}
+
+	query := `WITH d AS (UPDATE drivers SET status = $1 WHERE driver_id = $2 AND status <> $3 RETURNING *)
+		SELECT ` + driverColumns + ` FROM d JOIN vehicles v ON v.driver_id = d.driver_id`
+	driver, err := scanDriver(r.db.QueryRowContext(ctx, query, status, id, StatusAssigned))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Set driver availability failed: %v", err)
+			return nil, err
+		}
+		// Either the driver does not exist or is assigned
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("driver is on a trip")
+	}
+	return driver, nil
+}
+
+func (r *PostgresDriverRepository) UpdateLocation(ctx context.Context, id int32, location Location) (*Driver, error) {
+	query := `WITH d AS (UPDATE drivers SET lat = $1, lng = $2, location_updated_at = $3 WHERE driver_id = $4 RETURNING *)
+		SELECT ` + driverColumns + ` FROM d JOIN vehicles v ON v.driver_id = d.driver_id`
+	driver, err := scanDriver(r.db.QueryRowContext(ctx, query, location.Lat, location.Lng, time.Now(), id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("driver not found")
+		}
+		log.Printf("Update driver location failed: %v", err)
+		return nil, err
+	}
+	return driver, nil
+}
+
+// Assign reserves the first of candidates, in order, that is still AVAILABLE
+// with a fresh location. If the booking already has a driver, that assignment
+// is returned instead.
+func (r *PostgresDriverRepository) Assign(ctx context.Context, bookingID int32, pickup Location, candidates []int32) (*Assignment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Assign driver failed: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var distance float64
+	existing := `SELECT ` + driverColumns + `, ` + distanceKm + ` FROM ` + driverTables + ` WHERE d.booking_id = $3`
+	driver, err := scanDriver(tx.QueryRowContext(ctx, existing, pickup.Lat, pickup.Lng, bookingID), &distance)
+	if err == nil {
+		return &Assignment{BookingID: bookingID, Driver: driver, DistanceKm: distance}, nil
+	}
+	if err != sql.ErrNoRows {
+		log.Printf("Assign driver failed: %v", err)
+		return nil, err
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no driver available")
+	}
+
+	first := `SELECT ` + driverColumns + `, ` + distanceKm + ` FROM ` + driverTables + `
+		WHERE d.driver_id = ANY($3::int[]) AND d.status = $4 AND d.location_updated_at >= $5
+		ORDER BY array_position($3::int[], d.driver_id)
+		LIMIT 1
+		FOR UPDATE OF d SKIP LOCKED`
+	driver, err = scanDriver(tx.QueryRowContext(ctx, first,
+		pickup.Lat, pickup.Lng, pq.Array(candidates), StatusAvailable, time.Now().Add(-StaleLocationAfter)), &distance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no driver available")
+		}
+		log.Printf("Assign driver failed: %v", err)
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE drivers SET status = $1, booking_id = $2 WHERE driver_id = $3`,
+		StatusAssigned, bookingID, driver.ID)
+	if err != nil {
+		log.Printf("Assign driver failed: %v", err)
+		return nil, err
+	}
+	driver.Status = StatusAssigned
+	driver.BookingID = bookingID
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Assign driver failed: %v", err)
+		return nil, err
+	}
+	return &Assignment{BookingID: bookingID, Driver: driver, DistanceKm: distance}, nil
+}
+
+// Release frees the driver assigned to bookingID and returns them. The driver
+// becomes AVAILABLE again.
+func (r *PostgresDriverRepository) Release(ctx context.Context, bookingID int32) (*Driver, error) {
+	query := `WITH d AS (UPDATE drivers SET status = $1, booking_id = NULL WHERE booking_id = $2 RETURNING *)
+		SELECT ` + driverColumns + ` FROM d JOIN vehicles v ON v.driver_id = d.driver_id`
+	driver, err := scanDriver(r.db.QueryRowContext(ctx, query, StatusAvailable, bookingID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("assignment not found")
+		}
+		log.Printf("Release driver failed: %v", err)
+		return nil, err
+	}
+	return driver, nil
+}
@@ -0,0 +1,370 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"driver-service/geoindex"
+	pb "driver-service/pb/proto/driver"
+	"driver-service/repository"
+
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+
+	"github.com/hasnain-zafar/go-microservices/common/errors"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+)
+
+// AssignDriver searches defaultMaxDistanceKm around the pickup unless asked
+// for more, up to maxMaxDistanceKm.
+const (
+	defaultMaxDistanceKm = 10
+	maxMaxDistanceKm     = 50
+)
+
+// assignCandidates is how many of the nearest drivers AssignDriver offers the
+// repository, in case the closest ones are taken concurrently.
+const assignCandidates = 5
+
+var vehicleClasses = map[string]bool{
+	"ECONOMY": true,
+	"COMFORT": true,
+	"PREMIUM": true,
+}
+
+type DriverServer struct {
+	pb.UnimplementedDriverServiceServer
+	repo         repository.DriverRepository
+	index        *geoindex.Index
+	logger       *logger.Logger
+	errorHandler *errors.ErrorHandler
+	serviceName  string
+}
+
+// Option configures optional DriverServer dependencies.
+type Option func(*DriverServer)
+
+// WithMetrics counts the server's errors in reg rather than in a registry of
+// its own.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(s *DriverServer) {
+		s.logger = s.logger.WithMetrics(reg)
+		s.errorHandler = errors.NewErrorHandler(s.logger)
+	}
+}
+
+// NewDriverServer keeps index in step with the drivers' availability and
+// locations and matches drivers through it. Call LoadIndex before serving.
+func NewDriverServer(repo repository.DriverRepository, index *geoindex.Index, opts ...Option) *DriverServer {
+	serviceName := "driver-service"
+	log := logger.NewLogger(serviceName)
+	s := &DriverServer{
+		repo:         repo,
+		index:        index,
+		logger:       log,
+		errorHandler: errors.NewErrorHandler(log),
+		serviceName:  serviceName,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// LoadIndex adds every available driver with a fresh location to the index.
+func (s *DriverServer) LoadIndex(ctx context.Context) error {
+	drivers, err := s.repo.ListAvailable(ctx)
+	if err != nil {
+		return err
+	}
+	for _, driver := range drivers {
+		s.track(driver)
+	}
+	return nil
+}
+
+// track updates the index for a driver: only AVAILABLE drivers with a known
+// location are candidates for assignment. A racing update can briefly leave a
+// just-assigned driver indexed; Assign re-checks availability.
+func (s *DriverServer) track(d *repository.Driver) {
+	if d.Status != repository.StatusAvailable || d.Location == nil {
+		s.index.Remove(d.ID)
+		return
+	}
+	s.index.Upsert(geoindex.Entry{
+		ID:           d.ID,
+		Location:     geoindex.Point{Lat: d.Location.Lat, Lng: d.Location.Lng},
+		VehicleClass: d.Vehicle.Class,
+		UpdatedAt:    d.LocationUpdatedAt,
+	})
+}
+
+func (s *DriverServer) CreateDriver(ctx context.Context, req *pb.CreateDriverRequest) (*pb.CreateDriverResponse, error) {
+	method := "CreateDriver"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if err := validateCreateDriverRequest(req); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid driver request", err)
+	}
+
+	driverID, err := s.repo.Create(ctx, &repository.Driver{
+		Name:  req.Name,
+		Phone: req.Phone,
+		Vehicle: repository.Vehicle{
+			Class:       req.Vehicle.VehicleClass,
+			Make:        req.Vehicle.Make,
+			Model:       req.Vehicle.Model,
+			PlateNumber: req.Vehicle.PlateNumber,
+			Color:       req.Vehicle.Color,
+		},
+	})
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to create driver", err)
+	}
+
+	res := &pb.CreateDriverResponse{DriverId: driverID}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *DriverServer) GetDriver(ctx context.Context, req *pb.GetDriverRequest) (*pb.Driver, error) {
+	method := "GetDriver"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetDriverId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid driver ID", fmt.Errorf("driver ID must be positive"))
+	}
+
+	driver, err := s.repo.GetByID(ctx, req.DriverId)
+	if err != nil {
+		if err.Error() == "driver not found" {
+			return nil, s.errorHandler.HandleNotFound("driver not found", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to get driver", err)
+	}
+
+	res := driverToProto(driver)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *DriverServer) SetAvailability(ctx context.Context, req *pb.SetAvailabilityRequest) (*pb.SetAvailabilityResponse, error) {
+	method := "SetAvailability"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetDriverId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid driver ID", fmt.Errorf("driver ID must be positive"))
+	}
+
+	driver, err := s.repo.SetAvailability(ctx, req.DriverId, req.Online)
+	if err != nil {
+		switch err.Error() {
+		case "driver not found":
+			return nil, s.errorHandler.HandleNotFound("driver not found", err)
+		case "driver is on a trip":
+			return nil, s.errorHandler.HandleFailedPrecondition("driver availability cannot change", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to set driver availability", err)
+	}
+
+	s.track(driver)
+
+	res := &pb.SetAvailabilityResponse{Status: driver.Status}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *DriverServer) UpdateLocation(ctx context.Context, req *pb.UpdateLocationRequest) (*pb.UpdateLocationResponse, error) {
+	method := "UpdateLocation"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetDriverId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid driver ID", fmt.Errorf("driver ID must be positive"))
+	}
+	if err := validateLatLng(req.Location); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid location", err)
+	}
+
+	driver, err := s.repo.UpdateLocation(ctx, req.DriverId, repository.Location{Lat: req.Location.Lat, Lng: req.Location.Lng})
+	if err != nil {
+		if err.Error() == "driver not found" {
+			return nil, s.errorHandler.HandleNotFound("driver not found", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to update driver location", err)
+	}
+	s.track(driver)
+
+	res := &pb.UpdateLocationResponse{
+		Message: fmt.Sprintf("Driver %d location updated", req.DriverId),
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// AssignDriver reserves the nearest available driver for a booking. Candidates
+// come from the index; the repository reserves the first still available.
+func (s *DriverServer) AssignDriver(ctx context.Context, req *pb.AssignDriverRequest) (*pb.Assignment, error) {
+	method := "AssignDriver"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if err := validateAssignDriverRequest(req); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid assignment request", err)
+	}
+
+	maxDistance := req.MaxDistanceKm
+	if maxDistance == 0 {
+		maxDistance = defaultMaxDistanceKm
+	}
+
+	freshSince := time.Now().Add(-repository.StaleLocationAfter)
+	matches := s.index.Nearest(geoindex.Query{
+		Center:   geoindex.Point{Lat: req.Pickup.Lat, Lng: req.Pickup.Lng},
+		RadiusKm: maxDistance,
+		K:        assignCandidates,
+		Filter: func(e geoindex.Entry) bool {
+			return (req.VehicleClass == "" || e.VehicleClass == req.VehicleClass) && !e.UpdatedAt.Before(freshSince)
+		},
+	})
+	candidates := make([]int32, len(matches))
+	for i, m := range matches {
+		candidates[i] = m.ID
+	}
+
+	// The repository is asked even without candidates, since the booking may
+	// already have a driver
+	pickup := repository.Location{Lat: req.Pickup.Lat, Lng: req.Pickup.Lng}
+	assignment, err := s.repo.Assign(ctx, req.BookingId, pickup, candidates)
+	if err != nil {
+		if err.Error() == "no driver available" {
+			return nil, s.errorHandler.HandleNotFound("no driver available", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to assign driver", err)
+	}
+	s.track(assignment.Driver)
+
+	res := &pb.Assignment{
+		BookingId:  assignment.BookingID,
+		Driver:     driverToProto(assignment.Driver),
+		DistanceKm: assignment.DistanceKm,
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *DriverServer) ReleaseDriver(ctx context.Context, req *pb.ReleaseDriverRequest) (*pb.ReleaseDriverResponse, error) {
+	method := "ReleaseDriver"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+
+	driver, err := s.repo.Release(ctx, req.BookingId)
+	if err != nil {
+		if err.Error() == "assignment not found" {
+			return nil, s.errorHandler.HandleNotFound("assignment not found", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to release driver", err)
+	}
+	s.track(driver)
+
+	res := &pb.ReleaseDriverResponse{
+		Message: fmt.Sprintf("Driver %d released from booking %d", driver.ID, req.BookingId),
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func validateCreateDriverRequest(req *pb.CreateDriverRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if req.Phone == "" {
+		return fmt.Errorf("phone cannot be empty")
+	}
+	if req.Vehicle == nil {
+		return fmt.Errorf("vehicle is required")
+	}
+	if !vehicleClasses[req.Vehicle.VehicleClass] {
+		return fmt.Errorf("unsupported vehicle class %q", req.Vehicle.VehicleClass)
+	}
+	if req.Vehicle.Make == "" || req.Vehicle.Model == "" {
+		return fmt.Errorf("vehicle make and model cannot be empty")
+	}
+	if req.Vehicle.PlateNumber == "" {
+		return fmt.Errorf("plate number cannot be empty")
+	}
+	return nil
+}
+
+func validateAssignDriverRequest(req *pb.AssignDriverRequest) error {
+	if req.BookingId <= 0 {
+		return fmt.Errorf("booking ID must be positive")
+	}
+	if err := validateLatLng(req.Pickup); err != nil {
+		return fmt.Errorf("invalid pickup: %w", err)
+	}
+	if req.VehicleClass != "" && !vehicleClasses[req.VehicleClass] {
+		return fmt.Errorf("unsupported vehicle class %q", req.VehicleClass)
+	}
+	if req.MaxDistanceKm < 0 || req.MaxDistanceKm > maxMaxDistanceKm || math.IsNaN(req.MaxDistanceKm) {
+		return fmt.Errorf("max distance must be between 0 and %d km", maxMaxDistanceKm)
+	}
+	return nil
+}
+
+func validateLatLng(p *pb.LatLng) error {
+	if p == nil {
+		return fmt.Errorf("location is required")
+	}
+	if math.IsNaN(p.Lat) || p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if math.IsNaN(p.Lng) || p.Lng < -180 || p.Lng > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return nil
+}
+
+func driverToProto(d *repository.Driver) *pb.Driver {
+	res := &pb.Driver{
+		DriverId: d.ID,
+		Name:     d.Name,
+		Phone:    d.Phone,
+		Vehicle: &pb.Vehicle{
+			VehicleClass: d.Vehicle.Class,
+			Make:         d.Vehicle.Make,
+			Model:        d.Vehicle.Model,
+			PlateNumber:  d.Vehicle.PlateNumber,
+			Color:        d.Vehicle.Color,
+		},
+		Status:    d.Status,
+		BookingId: d.BookingID,
+	}
+	if d.Location != nil {
+		res.Location = &pb.LatLng{Lat: d.Location.Lat, Lng: d.Location.Lng}
+		res.LocationUpdatedAt = timestamppb.New(d.LocationUpdatedAt)
+	}
+	return res
+}
@@ -0,0 +1,395 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"driver-service/geoindex"
+	pb "driver-service/pb/proto/driver"
+	"driver-service/repository"
+	"driver-service/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var karachi = &pb.LatLng{Lat: 24.8607, Lng: 67.0011}
+
+func testVehicle() *pb.Vehicle {
+	return &pb.Vehicle{
+		VehicleClass: "ECONOMY",
+		Make:         "Suzuki",
+		Model:        "Alto",
+		PlateNumber:  "KHI-1234",
+		Color:        "White",
+	}
+}
+
+func testDriver() *repository.Driver {
+	return &repository.Driver{
+		ID:    1,
+		Name:  "Imran",
+		Phone: "+923001234567",
+		Vehicle: repository.Vehicle{
+			Class:       "ECONOMY",
+			Make:        "Suzuki",
+			Model:       "Alto",
+			PlateNumber: "KHI-1234",
+			Color:       "White",
+		},
+		Status:            repository.StatusAvailable,
+		Location:          &repository.Location{Lat: 24.86, Lng: 67.0},
+		LocationUpdatedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// indexedDriver is an available driver at location who reported just now.
+func indexedDriver(id int32, class string, lat, lng float64) geoindex.Entry {
+	return geoindex.Entry{
+		ID:           id,
+		Location:     geoindex.Point{Lat: lat, Lng: lng},
+		VehicleClass: class,
+		UpdatedAt:    time.Now(),
+	}
+}
+
+func TestCreateDriver_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.DriverRepository)
+	driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+
+	ctx := context.Background()
+	req := &pb.CreateDriverRequest{Name: "Imran", Phone: "+923001234567", Vehicle: testVehicle()}
+
+	// Expectations
+	mockRepo.On("Create", ctx, &repository.Driver{
+		Name:  "Imran",
+		Phone: "+923001234567",
+		Vehicle: repository.Vehicle{
+			Class:       "ECONOMY",
+			Make:        "Suzuki",
+			Model:       "Alto",
+			PlateNumber: "KHI-1234",
+			Color:       "White",
+		},
+	}).Return(int32(1), nil)
+
+	// Action
+	resp, err := driverServer.CreateDriver(ctx, req)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), resp.DriverId)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateDriver_InvalidRequest(t *testing.T) {
+	unknownClass := testVehicle()
+	unknownClass.VehicleClass = "BUS"
+	noPlate := testVehicle()
+	noPlate.PlateNumber = ""
+
+	testCases := []struct {
+		name string
+		req  *pb.CreateDriverRequest
+	}{
+		{name: "Empty Name", req: &pb.CreateDriverRequest{Phone: "+923001234567", Vehicle: testVehicle()}},
+		{name: "Empty Phone", req: &pb.CreateDriverRequest{Name: "Imran", Vehicle: testVehicle()}},
+		{name: "Missing Vehicle", req: &pb.CreateDriverRequest{Name: "Imran", Phone: "+923001234567"}},
+		{name: "Unknown Vehicle Class", req: &pb.CreateDriverRequest{Name: "Imran", Phone: "+923001234567", Vehicle: unknownClass}},
+		{name: "Missing Plate", req: &pb.CreateDriverRequest{Name: "Imran", Phone: "+923001234567", Vehicle: noPlate}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.DriverRepository)
+			driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+
+			resp, err := driverServer.CreateDriver(context.Background(), tc.req)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Nil(t, resp)
+			mockRepo.AssertNotCalled(t, "Create")
+		})
+	}
+}
+
+func TestGetDriver_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.DriverRepository)
+	driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("GetByID", ctx, int32(1)).Return(testDriver(), nil)
+
+	// Action
+	resp, err := driverServer.GetDriver(ctx, &pb.GetDriverRequest{DriverId: 1})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, "Imran", resp.Name)
+	assert.Equal(t, "KHI-1234", resp.Vehicle.PlateNumber)
+	assert.Equal(t, repository.StatusAvailable, resp.Status)
+	assert.Equal(t, 24.86, resp.Location.Lat)
+	assert.Equal(t, int64(1735732800), resp.LocationUpdatedAt.Seconds)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDriver_NotFound(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.DriverRepository)
+	driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("GetByID", ctx, int32(99)).Return(nil, errors.New("driver not found"))
+
+	// Action
+	resp, err := driverServer.GetDriver(ctx, &pb.GetDriverRequest{DriverId: 99})
+
+	// Assertions
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Nil(t, resp)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetAvailability(t *testing.T) {
+	testCases := []struct {
+		name     string
+		repoErr  error
+		expected codes.Code
+	}{
+		{name: "Success", expected: codes.OK},
+		{name: "Driver Not Found", repoErr: errors.New("driver not found"), expected: codes.NotFound},
+		{name: "Driver On Trip", repoErr: errors.New("driver is on a trip"), expected: codes.FailedPrecondition},
+		{name: "Database Error", repoErr: errors.New("database error"), expected: codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.DriverRepository)
+			driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+			ctx := context.Background()
+
+			var returned *repository.Driver
+			if tc.repoErr == nil {
+				returned = testDriver()
+			}
+			mockRepo.On("SetAvailability", ctx, int32(1), true).Return(returned, tc.repoErr)
+
+			resp, err := driverServer.SetAvailability(ctx, &pb.SetAvailabilityRequest{DriverId: 1, Online: true})
+
+			assert.Equal(t, tc.expected, status.Code(err))
+			if tc.repoErr == nil {
+				assert.Equal(t, repository.StatusAvailable, resp.Status)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetAvailability_UpdatesIndex(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.DriverRepository)
+	index := geoindex.New(geoindex.DefaultCellSizeDeg)
+	driverServer := NewDriverServer(mockRepo, index)
+
+	ctx := context.Background()
+	offline := testDriver()
+	offline.Status = repository.StatusOffline
+
+	// Expectations
+	mockRepo.On("SetAvailability", ctx, int32(1), true).Return(testDriver(), nil)
+	mockRepo.On("SetAvailability", ctx, int32(1), false).Return(offline, nil)
+
+	// Action and assertions: going online indexes the driver, offline removes them
+	_, err := driverServer.SetAvailability(ctx, &pb.SetAvailabilityRequest{DriverId: 1, Online: true})
+	assert.NoError(t, err)
+	entry, ok := index.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "ECONOMY", entry.VehicleClass)
+
+	_, err = driverServer.SetAvailability(ctx, &pb.SetAvailabilityRequest{DriverId: 1, Online: false})
+	assert.NoError(t, err)
+	assert.Zero(t, index.Len())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateLocation_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.DriverRepository)
+	driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+
+	ctx := context.Background()
+
+	moved := testDriver()
+	moved.Location = &repository.Location{Lat: 24.8607, Lng: 67.0011}
+
+	// Expectations
+	mockRepo.On("UpdateLocation", ctx, int32(1), repository.Location{Lat: 24.8607, Lng: 67.0011}).Return(moved, nil)
+
+	// Action
+	resp, err := driverServer.UpdateLocation(ctx, &pb.UpdateLocationRequest{DriverId: 1, Location: karachi})
+
+	// Assertions: the available driver is indexed at the new location
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	entry, ok := driverServer.index.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, geoindex.Point{Lat: 24.8607, Lng: 67.0011}, entry.Location)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateLocation_InvalidRequest(t *testing.T) {
+	for _, req := range []*pb.UpdateLocationRequest{
+		{DriverId: 0, Location: karachi},
+		{DriverId: 1},
+		{DriverId: 1, Location: &pb.LatLng{Lat: 91, Lng: 0}},
+		{DriverId: 1, Location: &pb.LatLng{Lat: 0, Lng: 181}},
+	} {
+		mockRepo := new(mocks.DriverRepository)
+		driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+
+		resp, err := driverServer.UpdateLocation(context.Background(), req)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Nil(t, resp)
+		mockRepo.AssertNotCalled(t, "UpdateLocation")
+	}
+}
+
+func TestAssignDriver_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.DriverRepository)
+	index := geoindex.New(geoindex.DefaultCellSizeDeg)
+	driverServer := NewDriverServer(mockRepo, index)
+
+	index.Upsert(indexedDriver(1, "ECONOMY", 24.8610, 67.0015))
+	index.Upsert(indexedDriver(2, "ECONOMY", 24.8700, 67.0100))
+	index.Upsert(indexedDriver(3, "PREMIUM", 24.8608, 67.0012))
+	index.Upsert(indexedDriver(4, "ECONOMY", 25.3960, 68.3578)) // beyond the default radius
+	stale := indexedDriver(5, "ECONOMY", 24.8607, 67.0011)
+	stale.UpdatedAt = time.Now().Add(-time.Hour)
+	index.Upsert(stale)
+
+	ctx := context.Background()
+	assigned := testDriver()
+	assigned.Status = repository.StatusAssigned
+	assigned.BookingID = 10
+
+	// Expectations: fresh drivers of the class within the radius, nearest first
+	mockRepo.On("Assign", ctx, int32(10), repository.Location{Lat: 24.8607, Lng: 67.0011}, []int32{1, 2}).
+		Return(&repository.Assignment{BookingID: 10, Driver: assigned, DistanceKm: 0.05}, nil)
+
+	// Action
+	resp, err := driverServer.AssignDriver(ctx, &pb.AssignDriverRequest{BookingId: 10, Pickup: karachi, VehicleClass: "ECONOMY"})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), resp.BookingId)
+	assert.Equal(t, int32(1), resp.Driver.DriverId)
+	assert.Equal(t, repository.StatusAssigned, resp.Driver.Status)
+	assert.Equal(t, 0.05, resp.DistanceKm)
+	_, indexed := index.Get(1)
+	assert.False(t, indexed, "assigned driver should leave the index")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAssignDriver_NoDriverAvailable(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.DriverRepository)
+	driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+
+	ctx := context.Background()
+
+	// Expectations: the repository is still asked in case the booking already has a driver
+	mockRepo.On("Assign", ctx, int32(10), mock.Anything, []int32{}).
+		Return(nil, errors.New("no driver available"))
+
+	// Action
+	resp, err := driverServer.AssignDriver(ctx, &pb.AssignDriverRequest{BookingId: 10, Pickup: karachi, MaxDistanceKm: 25})
+
+	// Assertions
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Nil(t, resp)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAssignDriver_InvalidRequest(t *testing.T) {
+	testCases := []struct {
+		name string
+		req  *pb.AssignDriverRequest
+	}{
+		{name: "Invalid Booking ID", req: &pb.AssignDriverRequest{Pickup: karachi}},
+		{name: "Missing Pickup", req: &pb.AssignDriverRequest{BookingId: 10}},
+		{name: "Unknown Vehicle Class", req: &pb.AssignDriverRequest{BookingId: 10, Pickup: karachi, VehicleClass: "BUS"}},
+		{name: "Radius Too Large", req: &pb.AssignDriverRequest{BookingId: 10, Pickup: karachi, MaxDistanceKm: 500}},
+		{name: "Negative Radius", req: &pb.AssignDriverRequest{BookingId: 10, Pickup: karachi, MaxDistanceKm: -1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.DriverRepository)
+			driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+
+			resp, err := driverServer.AssignDriver(context.Background(), tc.req)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Nil(t, resp)
+			mockRepo.AssertNotCalled(t, "Assign")
+		})
+	}
+}
+
+func TestReleaseDriver(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.DriverRepository)
+	driverServer := NewDriverServer(mockRepo, geoindex.New(geoindex.DefaultCellSizeDeg))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("Release", ctx, int32(10)).Return(testDriver(), nil)
+	mockRepo.On("Release", ctx, int32(11)).Return(nil, errors.New("assignment not found"))
+
+	// Action
+	resp, err := driverServer.ReleaseDriver(ctx, &pb.ReleaseDriverRequest{BookingId: 10})
+	_, notFoundErr := driverServer.ReleaseDriver(ctx, &pb.ReleaseDriverRequest{BookingId: 11})
+
+	// Assertions: the released driver is a candidate again
+	assert.NoError(t, err)
+	assert.Equal(t, "Driver 1 released from booking 10", resp.Message)
+	_, indexed := driverServer.index.Get(1)
+	assert.True(t, indexed)
+	assert.Equal(t, codes.NotFound, status.Code(notFoundErr))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLoadIndex(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.DriverRepository)
+	index := geoindex.New(geoindex.DefaultCellSizeDeg)
+	driverServer := NewDriverServer(mockRepo, index)
+
+	ctx := context.Background()
+	other := testDriver()
+	other.ID = 2
+
+	// Expectations
+	mockRepo.On("ListAvailable", ctx).Return([]*repository.Driver{testDriver(), other}, nil)
+
+	// Action
+	err := driverServer.LoadIndex(ctx)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 2, index.Len())
+	mockRepo.AssertExpectations(t)
+}
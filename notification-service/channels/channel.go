@@ -0,0 +1,59 @@
+// Package channels sends notifications to users by email, SMS, push and, for
+// local development, to a log file.
+package channels
+
+import (
+	"context"
+	"errors"
+)
+
+// Channel names.
+const (
+	Email = "email"
+	SMS   = "sms"
+	Push  = "push"
+	Log   = "log"
+)
+
+// Names lists every channel a user can receive messages on.
+var Names = []string{Email, SMS, Push, Log}
+
+// Message is a rendered notification addressed to a user on one channel.
+type Message struct {
+	// To is the channel's address of the user: an email address, a phone
+	// number, a device token, or the user ID for the log.
+	To      string
+	Subject string
+	Body    string
+}
+
+// Channel delivers messages. Send returns a PermanentError when retrying the
+// message cannot succeed, e.g. for an address the provider rejects.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// PermanentError is a delivery failure that retrying will not fix.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether err, or an error it wraps, is a PermanentError.
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
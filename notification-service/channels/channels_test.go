@@ -0,0 +1,124 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sentMail struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	msg  string
+}
+
+func newTestEmail(t *testing.T, config SMTPConfig, sent *[]sentMail) *EmailChannel {
+	t.Helper()
+	email, err := NewEmail(config)
+	require.NoError(t, err)
+	email.now = func() time.Time { return time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC) }
+	email.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		*sent = append(*sent, sentMail{addr: addr, auth: a, from: from, to: to, msg: string(msg)})
+		return nil
+	}
+	return email
+}
+
+func TestEmail_Send(t *testing.T) {
+	var sent []sentMail
+	email := newTestEmail(t, SMTPConfig{Host: "smtp.example.com", Port: 587, Username: "rides", Password: "secret",
+		From: "Rides <no-reply@example.com>"}, &sent)
+
+	err := email.Send(context.Background(), Message{
+		To:      "fatima@example.com",
+		Subject: "آپ کی بکنگ",
+		Body:    "Booking #7 is confirmed.",
+	})
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+
+	assert.Equal(t, "smtp.example.com:587", sent[0].addr)
+	assert.NotNil(t, sent[0].auth)
+	assert.Equal(t, "no-reply@example.com", sent[0].from)
+	assert.Equal(t, []string{"fatima@example.com"}, sent[0].to)
+
+	assert.Contains(t, sent[0].msg, "From: \"Rides\" <no-reply@example.com>\r\n")
+	assert.Contains(t, sent[0].msg, "To: <fatima@example.com>\r\n")
+	assert.Contains(t, sent[0].msg, "Subject: =?utf-8?q?")
+	assert.Contains(t, sent[0].msg, "Date: Mon, 19 Oct 2026 09:30:00 +0000\r\n")
+	assert.Contains(t, sent[0].msg, "Content-Type: text/plain; charset=utf-8\r\n")
+	assert.True(t, strings.HasSuffix(sent[0].msg, "\r\n\r\nBooking #7 is confirmed."))
+}
+
+func TestEmail_Errors(t *testing.T) {
+	_, err := NewEmail(SMTPConfig{Host: "smtp.example.com", From: "not an address"})
+	assert.Error(t, err)
+
+	var sent []sentMail
+	email := newTestEmail(t, SMTPConfig{Host: "smtp.example.com", Port: 25, From: "no-reply@example.com"}, &sent)
+
+	err = email.Send(context.Background(), Message{To: "fatima"})
+	assert.True(t, IsPermanent(err))
+	assert.Empty(t, sent)
+
+	// Server failures are worth retrying
+	email.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Nil(t, a)
+		return errors.New("connection refused")
+	}
+	err = email.Send(context.Background(), Message{To: "fatima@example.com"})
+	assert.EqualError(t, err, "send email: connection refused")
+	assert.False(t, IsPermanent(err))
+}
+
+func TestSMS_Send(t *testing.T) {
+	var out bytes.Buffer
+	sms := NewSMS(NewLog(&out))
+
+	err := sms.Send(context.Background(), Message{To: "0300-1234567", Body: "hi"})
+	assert.True(t, IsPermanent(err))
+	assert.Empty(t, out.String())
+
+	require.NoError(t, sms.Send(context.Background(), Message{To: "+923001234567", Subject: "Hello", Body: "hi"}))
+	assert.Contains(t, out.String(), `"channel":"sms","to":"+923001234567","body":"hi"`)
+}
+
+func TestPush_Send(t *testing.T) {
+	var out bytes.Buffer
+	push := NewPush(NewLog(&out))
+
+	assert.True(t, IsPermanent(push.Send(context.Background(), Message{Body: "hi"})))
+
+	require.NoError(t, push.Send(context.Background(), Message{To: "device-1", Subject: "Hello", Body: "hi"}))
+	assert.Contains(t, out.String(), `"channel":"push","to":"device-1","subject":"Hello","body":"hi"`)
+}
+
+func TestLog_Send(t *testing.T) {
+	var out bytes.Buffer
+	logChannel := NewLog(&out)
+	logChannel.now = func() time.Time { return time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC) }
+
+	require.NoError(t, logChannel.Send(context.Background(), Message{To: "3", Subject: "Hello", Body: "first"}))
+	require.NoError(t, logChannel.Send(context.Background(), Message{To: "3", Body: "second"}))
+
+	assert.Equal(t,
+		`{"time":"2026-10-19T09:30:00Z","channel":"log","to":"3","subject":"Hello","body":"first"}`+"\n"+
+			`{"time":"2026-10-19T09:30:00Z","channel":"log","to":"3","body":"second"}`+"\n",
+		out.String())
+}
+
+func TestPermanent(t *testing.T) {
+	err := Permanent(errors.New("rejected"))
+	assert.EqualError(t, err, "rejected")
+	assert.True(t, IsPermanent(err))
+	assert.False(t, IsPermanent(errors.New("timeout")))
+}
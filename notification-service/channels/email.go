@@ -0,0 +1,99 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig is the mail server email is sent through.
+type SMTPConfig struct {
+	Host string
+	Port int
+	// Username and Password authenticate with PLAIN auth; an empty username
+	// sends without authenticating.
+	Username string
+	Password string
+	// From is the sender address, e.g. "Rides <no-reply@example.com>".
+	From string
+}
+
+// EmailChannel sends plain text email through an SMTP server.
+type EmailChannel struct {
+	config SMTPConfig
+	from   *mail.Address
+	// sendMail is smtp.SendMail, replaced in tests.
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	now      func() time.Time
+}
+
+// NewEmail returns an email channel sending through the server in config.
+func NewEmail(config SMTPConfig) (*EmailChannel, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", config.From, err)
+	}
+	return &EmailChannel{
+		config:   config,
+		from:     from,
+		sendMail: smtp.SendMail,
+		now:      time.Now,
+	}, nil
+}
+
+func (c *EmailChannel) Name() string {
+	return Email
+}
+
+// Send delivers msg to the address msg.To. The SMTP exchange does not observe
+// ctx; it is bounded by the server's own timeouts.
+func (c *EmailChannel) Send(ctx context.Context, msg Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid email address %q: %w", msg.To, err))
+	}
+	data, err := c.compose(to, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if c.config.Username != "" {
+		auth = smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host)
+	}
+	addr := net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port))
+	if err := c.sendMail(addr, auth, c.from.Address, []string{to.Address}, data); err != nil {
+		return fmt.Errorf("send email: %w", err)
+	}
+	return nil
+}
+
+// compose builds the message with UTF-8 headers and a quoted-printable body,
+// so templates in any language survive servers without 8BITMIME.
+func (c *EmailChannel) compose(to *mail.Address, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", c.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", c.now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	if _, err := body.Write([]byte(msg.Body)); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,64 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// LogChannel writes messages to a file as JSON lines instead of sending
+// them, for local development. It also implements SMSProvider and
+// PushProvider, so SMS and push can be tried out without a gateway.
+type LogChannel struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+type logEntry struct {
+	Time    time.Time `json:"time"`
+	Channel string    `json:"channel"`
+	To      string    `json:"to"`
+	Subject string    `json:"subject,omitempty"`
+	Body    string    `json:"body"`
+}
+
+func NewLog(w io.Writer) *LogChannel {
+	return &LogChannel{w: w, now: time.Now}
+}
+
+func (c *LogChannel) Name() string {
+	return Log
+}
+
+func (c *LogChannel) Send(ctx context.Context, msg Message) error {
+	return c.write(Log, msg.To, msg.Subject, msg.Body)
+}
+
+func (c *LogChannel) SendSMS(ctx context.Context, to, text string) error {
+	return c.write(SMS, to, "", text)
+}
+
+func (c *LogChannel) Push(ctx context.Context, token, title, body string) error {
+	return c.write(Push, token, title, body)
+}
+
+func (c *LogChannel) write(channel, to, subject, body string) error {
+	line, err := json.Marshal(logEntry{
+		Time:    c.now().UTC(),
+		Channel: channel,
+		To:      to,
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,35 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+)
+
+// PushProvider delivers push notifications to devices, e.g. through APNs or
+// FCM.
+type PushProvider interface {
+	Push(ctx context.Context, token, title, body string) error
+}
+
+// PushChannel sends a message to a device with its subject as the title.
+type PushChannel struct {
+	provider PushProvider
+}
+
+func NewPush(provider PushProvider) *PushChannel {
+	return &PushChannel{provider: provider}
+}
+
+func (c *PushChannel) Name() string {
+	return Push
+}
+
+func (c *PushChannel) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return Permanent(fmt.Errorf("missing device token"))
+	}
+	if err := c.provider.Push(ctx, msg.To, msg.Subject, msg.Body); err != nil {
+		return fmt.Errorf("send push notification: %w", err)
+	}
+	return nil
+}
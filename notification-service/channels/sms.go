@@ -0,0 +1,44 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// e164 matches phone numbers in E.164 format, e.g. "+923001234567".
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// ValidPhone reports whether phone is in E.164 format.
+func ValidPhone(phone string) bool {
+	return e164.MatchString(phone)
+}
+
+// SMSProvider sends text messages through an SMS gateway.
+type SMSProvider interface {
+	SendSMS(ctx context.Context, to, text string) error
+}
+
+// SMSChannel sends a message's body as a text message. The subject is not
+// sent.
+type SMSChannel struct {
+	provider SMSProvider
+}
+
+func NewSMS(provider SMSProvider) *SMSChannel {
+	return &SMSChannel{provider: provider}
+}
+
+func (c *SMSChannel) Name() string {
+	return SMS
+}
+
+func (c *SMSChannel) Send(ctx context.Context, msg Message) error {
+	if !ValidPhone(msg.To) {
+		return Permanent(fmt.Errorf("invalid phone number %q", msg.To))
+	}
+	if err := c.provider.SendSMS(ctx, msg.To, msg.Body); err != nil {
+		return fmt.Errorf("send SMS: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+
+	"notification-service/channels"
+)
+
+type Config struct {
+	DBUrl     string
+	BrokerURL string
+
+	// Channels are the channels messages are sent on.
+	Channels []string
+	// LogPath is the file the log channel, and the development SMS and push
+	// providers, append to; empty writes to stdout.
+	LogPath string
+	SMTP    channels.SMTPConfig
+
+	// A failed message is attempted up to MaxAttempts times, first retried
+	// after RetryBackoff and then after twice the previous wait. Due
+	// messages are sent every DispatchInterval.
+	MaxAttempts      int32
+	RetryBackoff     time.Duration
+	DispatchInterval time.Duration
+
+	// A failed webhook delivery is attempted up to WebhookMaxAttempts times
+	// with the same doubling backoff from WebhookRetryBackoff, then
+	// dead-lettered.
+	WebhookMaxAttempts  int32
+	WebhookRetryBackoff time.Duration
+
+	// DBPool holds the DB_* connection pool settings.
+	DBPool database.PoolConfig
+	// Log holds the LOG_* logging settings.
+	Log logger.Settings
+	// AdminToken is the bearer token of the admin server's debug endpoints,
+	// which are disabled while it is empty.
+	AdminToken string
+}
+
+func Load() Config {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	dbUser := os.Getenv("DB_USER")
+	dbPass := os.Getenv("DB_PASSWORD")
+	dbName := os.Getenv("DB_NAME")
+	dbHost := os.Getenv("DB_HOST")
+	dbPort := os.Getenv("DB_PORT")
+
+	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		dbUser, dbPass, dbHost, dbPort, dbName)
+
+	dbPool, err := database.PoolConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logSettings, err := logger.SettingsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := Config{
+		DBUrl:     dbUrl,
+		BrokerURL: os.Getenv("BROKER_URL"),
+		Channels:  getChannels(),
+		LogPath:   os.Getenv("NOTIFICATION_LOG_PATH"),
+		SMTP: channels.SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     getInt("SMTP_PORT", 587),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		},
+		MaxAttempts:      int32(getInt("NOTIFICATION_MAX_ATTEMPTS", 5)),
+		RetryBackoff:     getDuration("NOTIFICATION_RETRY_BACKOFF", 30*time.Second),
+		DispatchInterval: getDuration("DISPATCH_INTERVAL", 5*time.Second),
+
+		WebhookMaxAttempts:  int32(getInt("WEBHOOK_MAX_ATTEMPTS", 8)),
+		WebhookRetryBackoff: getDuration("WEBHOOK_RETRY_BACKOFF", 30*time.Second),
+
+		DBPool:     dbPool,
+		Log:        logSettings,
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	}
+	for _, name := range cfg.Channels {
+		if name == channels.Email && cfg.SMTP.Host == "" {
+			log.Fatal("NOTIFICATION_CHANNELS includes email but SMTP_HOST is not set")
+		}
+	}
+	if cfg.MaxAttempts < 1 {
+		log.Fatalf("Invalid NOTIFICATION_MAX_ATTEMPTS %d: want at least 1", cfg.MaxAttempts)
+	}
+	if cfg.WebhookMaxAttempts < 1 {
+		log.Fatalf("Invalid WEBHOOK_MAX_ATTEMPTS %d: want at least 1", cfg.WebhookMaxAttempts)
+	}
+	return cfg
+}
+
+// getChannels reads NOTIFICATION_CHANNELS, a comma separated list of channel
+// names that defaults to the log channel alone.
+func getChannels() []string {
+	value := os.Getenv("NOTIFICATION_CHANNELS")
+	if value == "" {
+		return []string{channels.Log}
+	}
+
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		known := false
+		for _, channel := range channels.Names {
+			known = known || name == channel
+		}
+		if !known {
+			log.Fatalf("Invalid NOTIFICATION_CHANNELS %q: unknown channel %q", value, name)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, value, err)
+	}
+	return n
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, value, err)
+	}
+	return d
+}
@@ -0,0 +1,36 @@
+-- Where and how each user is notified. Users without a row get the defaults:
+-- the default locale and every channel they have an address for.
+CREATE TABLE preferences (
+  user_id INTEGER PRIMARY KEY,
+  locale VARCHAR(16) NOT NULL DEFAULT 'en',
+  email TEXT NOT NULL DEFAULT '',
+  phone TEXT NOT NULL DEFAULT '',
+  push_token TEXT NOT NULL DEFAULT '',
+  channels TEXT[] NOT NULL DEFAULT '{}',
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Every message sent, or waiting to be sent, with its attempts. A message is
+-- queued once per event and channel, so an event the broker delivers twice
+-- is only sent once.
+CREATE TABLE deliveries (
+  delivery_id BIGSERIAL PRIMARY KEY,
+  event_id BIGINT NOT NULL,
+  event_type VARCHAR(64) NOT NULL,
+  user_id INTEGER NOT NULL,
+  channel VARCHAR(16) NOT NULL,
+  address TEXT NOT NULL,
+  locale VARCHAR(16) NOT NULL,
+  subject TEXT NOT NULL,
+  body TEXT NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  sent_at TIMESTAMPTZ,
+  UNIQUE (event_id, channel)
+);
+
+CREATE INDEX deliveries_due_idx ON deliveries (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX deliveries_user_idx ON deliveries (user_id, delivery_id DESC);
@@ -0,0 +1,56 @@
+-- Partner webhook endpoints. A webhook receives the events in event_types,
+-- or every booking event if it is empty, for bookings of the partner's
+-- employees.
+CREATE TABLE webhooks (
+  webhook_id SERIAL PRIMARY KEY,
+  partner_id VARCHAR(64) NOT NULL,
+  url TEXT NOT NULL,
+  -- Key payloads are signed with
+  secret TEXT NOT NULL,
+  event_types TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhooks_partner_idx ON webhooks (partner_id);
+
+CREATE TABLE partner_employees (
+  partner_id VARCHAR(64) NOT NULL,
+  user_id INTEGER NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (partner_id, user_id)
+);
+
+CREATE INDEX partner_employees_user_idx ON partner_employees (user_id);
+
+-- Every event queued for a webhook, once per webhook and event. The payload
+-- is fixed when the delivery is queued; only its signature changes between
+-- attempts.
+CREATE TABLE webhook_deliveries (
+  delivery_id BIGSERIAL PRIMARY KEY,
+  webhook_id INTEGER NOT NULL REFERENCES webhooks (webhook_id),
+  event_id BIGINT NOT NULL,
+  event_type VARCHAR(64) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  last_status_code INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  delivered_at TIMESTAMPTZ,
+  UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, delivery_id DESC);
+
+-- Deliveries that ran out of attempts. A replayed delivery keeps its row,
+-- stamped with when it was replayed, until it is delivered or dies again.
+CREATE TABLE webhook_dead_letters (
+  delivery_id BIGINT PRIMARY KEY REFERENCES webhook_deliveries (delivery_id),
+  webhook_id INTEGER NOT NULL REFERENCES webhooks (webhook_id),
+  attempts INTEGER NOT NULL,
+  last_error TEXT NOT NULL,
+  dead_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  replayed_at TIMESTAMPTZ
+);
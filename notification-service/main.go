@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+
+	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"notification-service/channels"
+	"notification-service/config"
+	"notification-service/notifier"
+	pb "notification-service/pb/proto/notification"
+	"notification-service/repository"
+	"notification-service/server"
+	"notification-service/templates"
+	"notification-service/webhooks"
+
+	"github.com/hasnain-zafar/go-microservices/common/admin"
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+func main() {
+	// Initialize Prometheus metrics
+	metrics.Init()
+	registry := metrics.NewRegistry("notification-service")
+
+	cfg := config.Load()
+	logger.Configure(cfg.Log)
+
+	// Wait for Postgres, which may still be starting
+	db, err := database.Open(context.Background(), "postgres", cfg.DBUrl, cfg.DBPool)
+	if err != nil {
+		log.Fatalf("❌ DB not reachable: %v", err)
+	}
+	defer db.Close()
+	registry.RegisterDBStats(db, "notifications_db")
+
+	fmt.Println("✅ Connected to notifications_db successfully")
+
+	// Serve metrics and, with ADMIN_TOKEN set, the debug endpoints
+	conns := admin.NewConnTracker()
+	adminServer := admin.NewServer("notification-service", cfg.AdminToken, registry,
+		admin.WithConfig(cfg),
+		admin.WithDB(db),
+		admin.WithConnections(conns),
+	)
+	go startAdminServer(adminServer, "notification-service", 2117)
+
+	broker, err := outbox.NewBroker(context.Background(), cfg.BrokerURL)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to message broker: %v", err)
+	}
+	defer broker.Close()
+
+	catalog, err := templates.Default()
+	if err != nil {
+		log.Fatalf("❌ Failed to load templates: %v", err)
+	}
+
+	var logFile io.Writer = os.Stdout
+	if cfg.LogPath != "" {
+		f, err := os.OpenFile(cfg.LogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatalf("❌ Failed to open notification log: %v", err)
+		}
+		defer f.Close()
+		logFile = f
+	}
+	devLog := channels.NewLog(logFile)
+
+	// SMS and push go to the development log until real providers are
+	// configured
+	var chans []channels.Channel
+	for _, name := range cfg.Channels {
+		switch name {
+		case channels.Email:
+			email, err := channels.NewEmail(cfg.SMTP)
+			if err != nil {
+				log.Fatalf("❌ Failed to configure email: %v", err)
+			}
+			chans = append(chans, email)
+		case channels.SMS:
+			chans = append(chans, channels.NewSMS(devLog))
+		case channels.Push:
+			chans = append(chans, channels.NewPush(devLog))
+		case channels.Log:
+			chans = append(chans, devLog)
+		}
+	}
+
+	preferenceRepo := repository.NewPostgresPreferenceRepository(db)
+	deliveryRepo := repository.NewPostgresDeliveryRepository(db)
+
+	// Queue messages for booking events and send them as they fall due
+	bookingNotifier := notifier.New(deliveryRepo, preferenceRepo, catalog, chans, logger.NewLogger("notification-service"),
+		notifier.WithRetries(cfg.MaxAttempts, cfg.RetryBackoff),
+	)
+	if _, err := bookingNotifier.Start(broker); err != nil {
+		log.Fatalf("❌ Failed to subscribe to booking events: %v", err)
+	}
+	go bookingNotifier.Run(context.Background(), cfg.DispatchInterval)
+
+	// Post booking events to partners' webhooks
+	webhookRepo := repository.NewPostgresWebhookRepository(db)
+	webhookDeliveryRepo := repository.NewPostgresWebhookDeliveryRepository(db)
+	webhookDispatcher := webhooks.NewDispatcher(webhookRepo, webhookDeliveryRepo, logger.NewLogger("notification-service"),
+		webhooks.WithRetries(cfg.WebhookMaxAttempts, cfg.WebhookRetryBackoff),
+	)
+	if _, err := webhookDispatcher.Start(broker); err != nil {
+		log.Fatalf("❌ Failed to subscribe to booking events: %v", err)
+	}
+	go webhookDispatcher.Run(context.Background(), cfg.DispatchInterval)
+
+	notificationServer := server.NewNotificationServer(preferenceRepo, deliveryRepo, catalog,
+		server.WithWebhooks(webhookRepo, webhookDeliveryRepo),
+		server.WithMetrics(registry),
+	)
+
+	listener, err := net.Listen("tcp", ":50056")
+	if err != nil {
+		log.Fatalf("❌ Failed to listen on port 50056: %v", err)
+	}
+
+	// Tag every call with a request ID for its log lines, and with the actor
+	// its caller authenticated as, since only admins may manage webhooks
+	requestLogger := logger.NewLogger("notification-service")
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(conns),
+		grpc.ChainUnaryInterceptor(
+			logger.UnaryServerInterceptor(requestLogger),
+			audit.NewAuthenticator(cfg.AdminToken).UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(logger.StreamServerInterceptor(requestLogger)),
+	)
+	pb.RegisterNotificationServiceServer(grpcServer, notificationServer)
+
+	reflection.Register(grpcServer)
+
+	fmt.Println("🚀 NotificationService gRPC server listening on :50056")
+	if err := grpcServer.Serve(listener); err != nil {
+		log.Fatalf("❌ Failed to serve: %v", err)
+	}
+}
+
+func startAdminServer(adminServer *admin.Server, serviceName string, port int) {
+	fmt.Printf("📊 Admin server for %s starting on :%d\n", serviceName, port)
+	if err := adminServer.ListenAndServe(port); err != nil {
+		log.Fatalf("❌ Failed to start admin server: %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+// Package notifier turns booking events into messages for the booking's user
+// and sends them on the user's channels, retrying failed deliveries.
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	bookingrepo "booking-service/repository"
+	"notification-service/channels"
+	"notification-service/repository"
+	"notification-service/templates"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+// queueGroup shares booking events among notification-service replicas, so
+// each event is queued for sending once.
+const queueGroup = "notification-service.notifier"
+
+// batchSize caps how many deliveries one repository call claims.
+const batchSize = 100
+
+// claimLease is how long a claimed delivery is left to its sender before it
+// is due again, in case the sender stopped mid-send.
+const claimLease = 5 * time.Minute
+
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = 30 * time.Second
+)
+
+// DefaultPreferences are the preferences of a user who never set any: the
+// default locale and every channel.
+func DefaultPreferences(userID int32) *repository.Preferences {
+	return &repository.Preferences{
+		UserID:   userID,
+		Locale:   templates.DefaultLocale,
+		Channels: channels.Names,
+	}
+}
+
+// Notifier queues a message on each of the user's channels for every booking
+// event that has a template, and sends queued messages when they fall due. A
+// message that fails is retried with exponential backoff until it has been
+// attempted maxAttempts times, or at once given up if the channel reports a
+// permanent failure.
+type Notifier struct {
+	deliveries  repository.DeliveryRepository
+	preferences repository.PreferenceRepository
+	catalog     *templates.Catalog
+	channels    map[string]channels.Channel
+	logger      *logger.Logger
+	maxAttempts int32
+	backoff     time.Duration
+}
+
+// Option configures optional Notifier settings.
+type Option func(*Notifier)
+
+// WithRetries sets how many times a message is attempted, and the wait
+// before its first retry. Each later retry waits twice as long as the one
+// before.
+func WithRetries(maxAttempts int32, backoff time.Duration) Option {
+	return func(n *Notifier) {
+		n.maxAttempts = maxAttempts
+		n.backoff = backoff
+	}
+}
+
+// New returns a Notifier sending on chans. Users are only sent messages on
+// channels in chans.
+func New(
+	deliveries repository.DeliveryRepository,
+	preferences repository.PreferenceRepository,
+	catalog *templates.Catalog,
+	chans []channels.Channel,
+	log *logger.Logger,
+	opts ...Option,
+) *Notifier {
+	n := &Notifier{
+		deliveries:  deliveries,
+		preferences: preferences,
+		catalog:     catalog,
+		channels:    make(map[string]channels.Channel),
+		logger:      log,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+	}
+	for _, ch := range chans {
+		n.channels[ch.Name()] = ch
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Start subscribes the notifier to all booking events on broker.
+func (n *Notifier) Start(broker outbox.Broker) (outbox.Subscription, error) {
+	return broker.QueueSubscribe(bookingrepo.AggregateBooking+".>", queueGroup, n.handle)
+}
+
+func (n *Notifier) handle(ctx context.Context, msg outbox.Message) {
+	var event outbox.Event
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		n.logger.Error("failed to decode booking event", "error", err, "subject", msg.Subject)
+		return
+	}
+
+	queued, err := n.Notify(ctx, event)
+	if err != nil {
+		n.logger.Error("failed to queue notifications", "error", err, "event_id", event.ID, "event_type", event.EventType)
+		return
+	}
+	if queued > 0 {
+		n.logger.Info("notifications queued", "event_id", event.ID, "event_type", event.EventType, "deliveries", queued)
+	}
+}
+
+// Notify queues the messages for a booking event and returns how many were
+// queued. Events without a template, and events already queued, queue
+// nothing.
+func (n *Notifier) Notify(ctx context.Context, event outbox.Event) (int, error) {
+	var payload bookingrepo.BookingEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return 0, fmt.Errorf("decode booking event payload: %w", err)
+	}
+
+	prefs, err := n.preferences.Get(ctx, payload.UserID)
+	if err != nil {
+		if err.Error() != "preferences not found" {
+			return 0, err
+		}
+		prefs = DefaultPreferences(payload.UserID)
+	}
+
+	rendered, err := n.catalog.Render(prefs.Locale, event.EventType, templates.Data{
+		BookingID: payload.BookingID,
+		Status:    payload.Status,
+		DriverID:  payload.DriverID,
+		PickupAt:  payload.PickupAt,
+		PromoCode: payload.PromoCode,
+	})
+	if errors.Is(err, templates.ErrNoTemplate) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var deliveries []*repository.Delivery
+	for _, name := range prefs.Channels {
+		address := Address(prefs, name)
+		if n.channels[name] == nil || address == "" {
+			continue
+		}
+		deliveries = append(deliveries, &repository.Delivery{
+			EventID:   event.ID,
+			EventType: event.EventType,
+			UserID:    payload.UserID,
+			Channel:   name,
+			Address:   address,
+			Locale:    rendered.Locale,
+			Subject:   rendered.Subject,
+			Body:      rendered.Body,
+		})
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	return n.deliveries.Enqueue(ctx, deliveries)
+}
+
+// Address returns the user's address on a channel, or "" if they have none.
+func Address(prefs *repository.Preferences, channel string) string {
+	switch channel {
+	case channels.Email:
+		return prefs.Email
+	case channels.SMS:
+		return prefs.Phone
+	case channels.Push:
+		return prefs.PushToken
+	case channels.Log:
+		return strconv.Itoa(int(prefs.UserID))
+	default:
+		return ""
+	}
+}
+
+// DispatchDue sends every message due by now and returns how many were sent.
+func (n *Notifier) DispatchDue(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+	for {
+		deliveries, err := n.deliveries.ClaimDue(ctx, now, claimLease, batchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, delivery := range deliveries {
+			ok, err := n.dispatch(ctx, delivery, now)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+
+		if len(deliveries) < batchSize {
+			return sent, nil
+		}
+	}
+}
+
+// dispatch sends a claimed delivery and records the outcome, reporting
+// whether it was sent.
+func (n *Notifier) dispatch(ctx context.Context, delivery *repository.Delivery, now time.Time) (bool, error) {
+	var err error
+	if ch := n.channels[delivery.Channel]; ch != nil {
+		err = ch.Send(ctx, channels.Message{To: delivery.Address, Subject: delivery.Subject, Body: delivery.Body})
+	} else {
+		err = channels.Permanent(fmt.Errorf("channel %q is not configured", delivery.Channel))
+	}
+
+	log := n.logger.WithValues("delivery_id", delivery.ID, "channel", delivery.Channel, "attempts", delivery.Attempts)
+	switch {
+	case err == nil:
+		metrics.IncrementNotificationCounter(delivery.Channel, "sent")
+		log.Info("notification sent", "event_type", delivery.EventType)
+		return true, n.deliveries.MarkSent(ctx, delivery.ID, now)
+	case channels.IsPermanent(err) || delivery.Attempts >= n.maxAttempts:
+		metrics.IncrementNotificationCounter(delivery.Channel, "failed")
+		log.Error("notification failed", "error", err)
+		return false, n.deliveries.Fail(ctx, delivery.ID, err.Error())
+	default:
+		retryAt := now.Add(n.backoff << (delivery.Attempts - 1))
+		metrics.IncrementNotificationCounter(delivery.Channel, "retried")
+		log.Warn("notification failed, retrying", "error", err, "retry_at", retryAt)
+		return false, n.deliveries.Retry(ctx, delivery.ID, err.Error(), retryAt)
+	}
+}
+
+// Run sends due messages every interval until ctx is done.
+func (n *Notifier) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := n.DispatchDue(ctx, time.Now()); err != nil {
+			n.logger.Error("failed to send notifications", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	bookingrepo "booking-service/repository"
+	"notification-service/channels"
+	"notification-service/repository"
+	"notification-service/repository/mocks"
+	"notification-service/templates"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+var testNow = time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+
+// stubChannel records the messages it is sent and fails with errs in turn.
+type stubChannel struct {
+	name string
+	errs []error
+	sent []channels.Message
+}
+
+func (c *stubChannel) Name() string {
+	return c.name
+}
+
+func (c *stubChannel) Send(ctx context.Context, msg channels.Message) error {
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return err
+	}
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+func newTestNotifier(t *testing.T, deliveries *mocks.DeliveryRepository, prefs *mocks.PreferenceRepository, chans ...channels.Channel) *Notifier {
+	t.Helper()
+	catalog, err := templates.Default()
+	require.NoError(t, err)
+	return New(deliveries, prefs, catalog, chans, logger.NewLogger("notification-service"),
+		WithRetries(3, time.Minute))
+}
+
+func bookingEvent(t *testing.T, id int64, eventType string, payload bookingrepo.BookingEvent) outbox.Event {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return outbox.Event{
+		ID:            id,
+		AggregateType: bookingrepo.AggregateBooking,
+		AggregateID:   "7",
+		EventType:     eventType,
+		Payload:       data,
+		OccurredAt:    testNow,
+	}
+}
+
+func TestNotify(t *testing.T) {
+	// Setup
+	mockDeliveries := new(mocks.DeliveryRepository)
+	mockPrefs := new(mocks.PreferenceRepository)
+	n := newTestNotifier(t, mockDeliveries, mockPrefs,
+		&stubChannel{name: channels.Email}, &stubChannel{name: channels.SMS}, &stubChannel{name: channels.Log})
+
+	ctx := context.Background()
+	mockPrefs.On("Get", ctx, int32(3)).Return(&repository.Preferences{
+		UserID: 3,
+		Locale: "ur",
+		Email:  "fatima@example.com",
+		// SMS has no number and push is not configured
+		Channels: []string{channels.Email, channels.SMS, channels.Push},
+	}, nil)
+	mockDeliveries.On("Enqueue", ctx, []*repository.Delivery{{
+		EventID:   42,
+		EventType: bookingrepo.EventBookingCancelled,
+		UserID:    3,
+		Channel:   channels.Email,
+		Address:   "fatima@example.com",
+		Locale:    "ur",
+		Subject:   "آپ کی بکنگ منسوخ ہو گئی",
+		Body:      "بکنگ #7 منسوخ کر دی گئی ہے۔ اس کے لیے روکی گئی رقم جاری کر دی گئی ہے۔",
+	}}).Return(1, nil)
+
+	// Execute
+	queued, err := n.Notify(ctx, bookingEvent(t, 42, bookingrepo.EventBookingCancelled,
+		bookingrepo.BookingEvent{BookingID: 7, UserID: 3, Status: bookingrepo.StatusCancelled}))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, queued)
+	mockDeliveries.AssertExpectations(t)
+}
+
+func TestNotify_DefaultPreferences(t *testing.T) {
+	// Setup
+	mockDeliveries := new(mocks.DeliveryRepository)
+	mockPrefs := new(mocks.PreferenceRepository)
+	n := newTestNotifier(t, mockDeliveries, mockPrefs, &stubChannel{name: channels.Email}, &stubChannel{name: channels.Log})
+
+	ctx := context.Background()
+	mockPrefs.On("Get", ctx, int32(3)).Return(nil, errors.New("preferences not found"))
+	mockDeliveries.On("Enqueue", ctx, []*repository.Delivery{{
+		EventID:   42,
+		EventType: bookingrepo.EventBookingDriverAssigned,
+		UserID:    3,
+		Channel:   channels.Log,
+		Address:   "3",
+		Locale:    "en",
+		Subject:   "Your driver is on the way",
+		Body:      "Driver #5 has been assigned to booking #7.",
+	}}).Return(1, nil)
+
+	// Execute
+	queued, err := n.Notify(ctx, bookingEvent(t, 42, bookingrepo.EventBookingDriverAssigned,
+		bookingrepo.BookingEvent{BookingID: 7, UserID: 3, DriverID: 5}))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, queued)
+}
+
+func TestNotify_Skipped(t *testing.T) {
+	// Setup
+	mockDeliveries := new(mocks.DeliveryRepository)
+	mockPrefs := new(mocks.PreferenceRepository)
+	n := newTestNotifier(t, mockDeliveries, mockPrefs, &stubChannel{name: channels.Log})
+
+	ctx := context.Background()
+	mockPrefs.On("Get", ctx, int32(3)).Return(&repository.Preferences{UserID: 3, Locale: "en"}, nil)
+
+	// Users are not told about ride updates
+	queued, err := n.Notify(ctx, bookingEvent(t, 41, bookingrepo.EventBookingRideUpdated,
+		bookingrepo.BookingEvent{BookingID: 7, UserID: 3}))
+	require.NoError(t, err)
+	assert.Zero(t, queued)
+
+	// A user who opted out of every channel gets nothing
+	queued, err = n.Notify(ctx, bookingEvent(t, 42, bookingrepo.EventBookingCreated,
+		bookingrepo.BookingEvent{BookingID: 7, UserID: 3}))
+	require.NoError(t, err)
+	assert.Zero(t, queued)
+	mockDeliveries.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+}
+
+func TestNotify_PreferencesError(t *testing.T) {
+	mockDeliveries := new(mocks.DeliveryRepository)
+	mockPrefs := new(mocks.PreferenceRepository)
+	n := newTestNotifier(t, mockDeliveries, mockPrefs, &stubChannel{name: channels.Log})
+
+	ctx := context.Background()
+	mockPrefs.On("Get", ctx, int32(3)).Return(nil, errors.New("connection refused"))
+
+	_, err := n.Notify(ctx, bookingEvent(t, 42, bookingrepo.EventBookingCreated,
+		bookingrepo.BookingEvent{BookingID: 7, UserID: 3}))
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestStart_QueuesBrokerEvents(t *testing.T) {
+	// Setup
+	mockDeliveries := new(mocks.DeliveryRepository)
+	mockPrefs := new(mocks.PreferenceRepository)
+	n := newTestNotifier(t, mockDeliveries, mockPrefs, &stubChannel{name: channels.Log})
+
+	broker := outbox.NewInProcessBroker()
+	_, err := n.Start(broker)
+	require.NoError(t, err)
+
+	mockPrefs.On("Get", mock.Anything, int32(3)).Return(nil, errors.New("preferences not found"))
+	mockDeliveries.On("Enqueue", mock.Anything, mock.MatchedBy(func(d []*repository.Delivery) bool {
+		return len(d) == 1 && d[0].EventID == 42 && d[0].Subject == "Thanks for riding with us"
+	})).Return(1, nil).Once()
+
+	// Execute
+	event := bookingEvent(t, 42, bookingrepo.EventBookingCompleted, bookingrepo.BookingEvent{BookingID: 7, UserID: 3})
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	require.NoError(t, broker.Publish(context.Background(), event.Subject(), data))
+
+	// Assert
+	mockDeliveries.AssertExpectations(t)
+}
+
+func pending(id int64, channel string, attempts int32) *repository.Delivery {
+	return &repository.Delivery{
+		ID:        id,
+		EventType: bookingrepo.EventBookingCreated,
+		UserID:    3,
+		Channel:   channel,
+		Address:   "fatima@example.com",
+		Subject:   "Your booking is confirmed",
+		Body:      "Booking #7 is confirmed.",
+		Status:    repository.StatusPending,
+		Attempts:  attempts,
+	}
+}
+
+func TestDispatchDue(t *testing.T) {
+	// Setup
+	mockDeliveries := new(mocks.DeliveryRepository)
+	mockPrefs := new(mocks.PreferenceRepository)
+	email := &stubChannel{name: channels.Email, errs: []error{
+		errors.New("connection refused"),
+		errors.New("connection refused"),
+		channels.Permanent(errors.New("mailbox does not exist")),
+	}}
+	n := newTestNotifier(t, mockDeliveries, mockPrefs, email)
+
+	ctx := context.Background()
+	mockDeliveries.On("ClaimDue", ctx, testNow, claimLease, batchSize).Return([]*repository.Delivery{
+		pending(1, channels.Email, 2),
+		pending(2, channels.Email, 3),
+		pending(3, channels.Email, 1),
+		pending(4, channels.Email, 1),
+		pending(5, channels.SMS, 1),
+	}, nil).Once()
+	// Second attempt fails: retried after twice the backoff
+	mockDeliveries.On("Retry", ctx, int64(1), "connection refused", testNow.Add(2*time.Minute)).Return(nil).Once()
+	// Last attempt fails: given up
+	mockDeliveries.On("Fail", ctx, int64(2), "connection refused").Return(nil).Once()
+	mockDeliveries.On("Fail", ctx, int64(3), "mailbox does not exist").Return(nil).Once()
+	mockDeliveries.On("MarkSent", ctx, int64(4), testNow).Return(nil).Once()
+	mockDeliveries.On("Fail", ctx, int64(5), `channel "sms" is not configured`).Return(nil).Once()
+
+	// Execute
+	sent, err := n.DispatchDue(ctx, testNow)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []channels.Message{{To: "fatima@example.com", Subject: "Your booking is confirmed", Body: "Booking #7 is confirmed."}}, email.sent)
+	mockDeliveries.AssertExpectations(t)
+}
+
+func TestDispatchDue_Batches(t *testing.T) {
+	// Setup
+	mockDeliveries := new(mocks.DeliveryRepository)
+	mockPrefs := new(mocks.PreferenceRepository)
+	var out bytes.Buffer
+	n := newTestNotifier(t, mockDeliveries, mockPrefs, channels.NewLog(&out))
+
+	ctx := context.Background()
+	var full []*repository.Delivery
+	for id := int64(1); id <= batchSize; id++ {
+		full = append(full, pending(id, channels.Log, 1))
+	}
+	mockDeliveries.On("ClaimDue", ctx, testNow, claimLease, batchSize).Return(full, nil).Once()
+	mockDeliveries.On("ClaimDue", ctx, testNow, claimLease, batchSize).Return([]*repository.Delivery{pending(101, channels.Log, 1)}, nil).Once()
+	mockDeliveries.On("MarkSent", ctx, mock.Anything, testNow).Return(nil)
+
+	// Execute
+	sent, err := n.DispatchDue(ctx, testNow)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, batchSize+1, sent)
+	assert.Equal(t, batchSize+1, bytes.Count(out.Bytes(), []byte("\n")))
+}
+
+func TestDispatchDue_RepositoryError(t *testing.T) {
+	mockDeliveries := new(mocks.DeliveryRepository)
+	mockPrefs := new(mocks.PreferenceRepository)
+	n := newTestNotifier(t, mockDeliveries, mockPrefs, &stubChannel{name: channels.Email})
+
+	ctx := context.Background()
+	mockDeliveries.On("ClaimDue", ctx, testNow, claimLease, batchSize).Return([]*repository.Delivery{pending(1, channels.Email, 1)}, nil).Once()
+	mockDeliveries.On("MarkSent", ctx, int64(1), testNow).Return(errors.New("connection refused"))
+
+	sent, err := n.DispatchDue(ctx, testNow)
+	assert.EqualError(t, err, "connection refused")
+	assert.Zero(t, sent)
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	StatusPending = "PENDING"
+	StatusSent    = "SENT"
+	StatusFailed  = "FAILED"
+)
+
+// Delivery is a message to a user on one channel about one event.
+type Delivery struct {
+	ID        int64
+	EventID   int64
+	EventType string
+	UserID    int32
+	Channel   string
+	Address   string
+	Locale    string
+	Subject   string
+	Body      string
+	Status    string
+	// Attempts counts the times the message was claimed for sending.
+	Attempts  int32
+	LastError string
+	// NextAttemptAt is when a PENDING message is next due.
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+type DeliveryRepository interface {
+	// Enqueue queues deliveries as PENDING and due now, skipping any whose
+	// event and channel are already queued. It returns how many were queued.
+	Enqueue(ctx context.Context, deliveries []*Delivery) (int, error)
+	// ClaimDue returns up to limit PENDING deliveries due by now, counting an
+	// attempt on each and putting them off until now plus lease, so a
+	// delivery that is never marked is retried after the lease.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error)
+	MarkSent(ctx context.Context, id int64, sentAt time.Time) error
+	// Retry records a failed attempt and makes the delivery due again at at.
+	Retry(ctx context.Context, id int64, lastError string, at time.Time) error
+	// Fail records a failed attempt and gives up on the delivery.
+	Fail(ctx context.Context, id int64, lastError string) error
+	// ListByUser returns the user's latest deliveries, newest first.
+	ListByUser(ctx context.Context, userID int32, limit int) ([]*Delivery, error)
+}
+
+type PostgresDeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresDeliveryRepository(db *sql.DB) *PostgresDeliveryRepository {
+	return &PostgresDeliveryRepository{db: db}
+}
+
+const deliveryColumns = `delivery_id, event_id, event_type, user_id, channel, address, locale, subject, body,
+	status, attempts, last_error, next_attempt_at, created_at, sent_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDelivery(row rowScanner) (*Delivery, error) {
+	d := &Delivery{}
+	var sentAt sql.NullTime
+	if err := row.Scan(&d.ID, &d.EventID, &d.EventType, &d.UserID, &d.Channel, &d.Address, &d.Locale, &d.Subject,
+		&d.Body, &d.Status, &d.Attempts, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &sentAt); err != nil {
+		return nil, err
+	}
+	if sentAt.Valid {
+		d.SentAt = &sentAt.Time
+	}
+	return d, nil
+}
+
+func scanDeliveries(rows *sql.Rows) ([]*Delivery, error) {
+	defer rows.Close()
+	var deliveries []*Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *PostgresDeliveryRepository) Enqueue(ctx context.Context, deliveries []*Delivery) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Enqueue deliveries failed: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO deliveries (event_id, event_type, user_id, channel, address, locale, subject, body, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (event_id, channel) DO NOTHING`
+	queued := 0
+	for _, d := range deliveries {
+		res, err := tx.ExecContext(ctx, query, d.EventID, d.EventType, d.UserID, d.Channel, d.Address, d.Locale,
+			d.Subject, d.Body, StatusPending)
+		if err != nil {
+			log.Printf("Enqueue deliveries failed: %v", err)
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			log.Printf("Enqueue deliveries failed: %v", err)
+			return 0, err
+		}
+		queued += int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Enqueue deliveries failed: %v", err)
+		return 0, err
+	}
+	return queued, nil
+}
+
+func (r *PostgresDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Delivery, error) {
+	// SKIP LOCKED lets several instances claim disjoint batches
+	query := `UPDATE deliveries SET attempts = attempts + 1, next_attempt_at = $2
+		WHERE delivery_id IN (
+			SELECT delivery_id FROM deliveries
+			WHERE status = $3 AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, delivery_id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), StatusPending, limit)
+	if err != nil {
+		log.Printf("Claim due deliveries failed: %v", err)
+		return nil, err
+	}
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		log.Printf("Claim due deliveries failed: %v", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *PostgresDeliveryRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	query := `UPDATE deliveries SET status = $1, last_error = '', sent_at = $2 WHERE delivery_id = $3`
+	return r.update(ctx, "Mark delivery sent", query, StatusSent, sentAt, id)
+}
+
+func (r *PostgresDeliveryRepository) Retry(ctx context.Context, id int64, lastError string, at time.Time) error {
+	query := `UPDATE deliveries SET last_error = $1, next_attempt_at = $2 WHERE delivery_id = $3`
+	return r.update(ctx, "Retry delivery", query, lastError, at, id)
+}
+
+func (r *PostgresDeliveryRepository) Fail(ctx context.Context, id int64, lastError string) error {
+	query := `UPDATE deliveries SET status = $1, last_error = $2 WHERE delivery_id = $3`
+	return r.update(ctx, "Fail delivery", query, StatusFailed, lastError, id)
+}
+
+func (r *PostgresDeliveryRepository) update(ctx context.Context, op, query string, args ...any) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("%s failed: %v", op, err)
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("%s failed: %v", op, err)
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("delivery not found")
+	}
+	return nil
+}
+
+func (r *PostgresDeliveryRepository) ListByUser(ctx context.Context, userID int32, limit int) ([]*Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM deliveries WHERE user_id = $1 ORDER BY delivery_id DESC LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		log.Printf("List deliveries failed: %v", err)
+		return nil, err
+	}
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		log.Printf("List deliveries failed: %v", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Preferences are where and how a user is notified.
+type Preferences struct {
+	UserID    int32
+	Locale    string
+	Email     string
+	Phone     string
+	PushToken string
+	// Channels are the channels the user receives messages on.
+	Channels  []string
+	UpdatedAt time.Time
+}
+
+type PreferenceRepository interface {
+	// Get returns the user's preferences, or "preferences not found" if they
+	// never set any.
+	Get(ctx context.Context, userID int32) (*Preferences, error)
+	// Set replaces the user's preferences.
+	Set(ctx context.Context, prefs *Preferences) (*Preferences, error)
+}
+
+type PostgresPreferenceRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPreferenceRepository(db *sql.DB) *PostgresPreferenceRepository {
+	return &PostgresPreferenceRepository{db: db}
+}
+
+const preferenceColumns = `user_id, locale, email, phone, push_token, channels, updated_at`
+
+func (r *PostgresPreferenceRepository) Get(ctx context.Context, userID int32) (*Preferences, error) {
+	query := `SELECT ` + preferenceColumns + ` FROM preferences WHERE user_id = $1`
+	prefs, err := scanPreferences(r.db.QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("preferences not found")
+		}
+		log.Printf("Get preferences failed: %v", err)
+		return nil, err
+	}
+	return prefs, nil
+}
+
+func (r *PostgresPreferenceRepository) Set(ctx context.Context, prefs *Preferences) (*Preferences, error) {
+	query := `INSERT INTO preferences (user_id, locale, email, phone, push_token, channels)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			locale = EXCLUDED.locale,
+			email = EXCLUDED.email,
+			phone = EXCLUDED.phone,
+			push_token = EXCLUDED.push_token,
+			channels = EXCLUDED.channels,
+			updated_at = now()
+		RETURNING ` + preferenceColumns
+	channels := prefs.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+	saved, err := scanPreferences(r.db.QueryRowContext(ctx, query,
+		prefs.UserID, prefs.Locale, prefs.Email, prefs.Phone, prefs.PushToken, pq.Array(channels)))
+	if err != nil {
+		log.Printf("Set preferences failed: %v", err)
+		return nil, err
+	}
+	return saved, nil
+}
+
+func scanPreferences(row rowScanner) (*Preferences, error) {
+	var prefs Preferences
+	err := row.Scan(&prefs.UserID, &prefs.Locale, &prefs.Email, &prefs.Phone, &prefs.PushToken,
+		pq.Array(&prefs.Channels), &prefs.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
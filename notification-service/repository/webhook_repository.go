@@ -0,0 +1,404 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Webhook deliveries are PENDING until delivered, or DEAD once they ran out
+// of attempts.
+const (
+	StatusDelivered = "DELIVERED"
+	StatusDead      = "DEAD"
+)
+
+// Webhook is a partner's endpoint for events about its employees' bookings.
+type Webhook struct {
+	ID        int32
+	PartnerID string
+	URL       string
+	Secret    string
+	// EventTypes filters the events sent; empty sends every event.
+	EventTypes []string
+	CreatedAt  time.Time
+}
+
+// WebhookDelivery is an event queued for a webhook.
+type WebhookDelivery struct {
+	ID        int64
+	WebhookID int32
+	EventID   int64
+	EventType string
+	Payload   []byte
+	Status    string
+	// Attempts counts the times the delivery was claimed for sending.
+	Attempts  int32
+	LastError string
+	// LastStatusCode is the HTTP status of the last response, or 0 if the
+	// endpoint could not be reached.
+	LastStatusCode int32
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) (*Webhook, error)
+	GetByID(ctx context.Context, id int32) (*Webhook, error)
+	ListByPartner(ctx context.Context, partnerID string) ([]*Webhook, error)
+	// ListForEvent returns the webhooks of every partner employing the user
+	// whose filters pass eventType.
+	ListForEvent(ctx context.Context, userID int32, eventType string) ([]*Webhook, error)
+	// AddEmployee is a no-op if the user is already the partner's employee.
+	AddEmployee(ctx context.Context, partnerID string, userID int32) error
+	RemoveEmployee(ctx context.Context, partnerID string, userID int32) error
+}
+
+type WebhookDeliveryRepository interface {
+	// Enqueue queues deliveries as PENDING and due now, skipping any whose
+	// webhook and event are already queued. It returns how many were queued.
+	Enqueue(ctx context.Context, deliveries []*WebhookDelivery) (int, error)
+	// ClaimDue returns up to limit PENDING deliveries due by now, counting an
+	// attempt on each and putting them off until now plus lease.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, statusCode int32, at time.Time) error
+	// Retry records a failed attempt and makes the delivery due again at at.
+	Retry(ctx context.Context, id int64, statusCode int32, lastError string, at time.Time) error
+	// Kill records a failed attempt, marks the delivery DEAD and adds it to
+	// the dead letters.
+	Kill(ctx context.Context, id int64, statusCode int32, lastError string) error
+	// ListByWebhook returns the webhook's latest deliveries, newest first,
+	// optionally only those with status.
+	ListByWebhook(ctx context.Context, webhookID int32, status string, limit int) ([]*WebhookDelivery, error)
+	// Replay queues the webhook's DEAD deliveries in ids, or all of them if
+	// ids is empty, to be attempted afresh. It returns how many were queued.
+	Replay(ctx context.Context, webhookID int32, ids []int64) (int, error)
+}
+
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookRepository(db *sql.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+const webhookColumns = `webhook_id, partner_id, url, secret, event_types, created_at`
+
+func scanWebhook(row rowScanner) (*Webhook, error) {
+	w := &Webhook{}
+	if err := row.Scan(&w.ID, &w.PartnerID, &w.URL, &w.Secret, pq.Array(&w.EventTypes), &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func scanWebhooks(rows *sql.Rows) ([]*Webhook, error) {
+	defer rows.Close()
+	var webhooks []*Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *PostgresWebhookRepository) Create(ctx context.Context, webhook *Webhook) (*Webhook, error) {
+	eventTypes := webhook.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	query := `INSERT INTO webhooks (partner_id, url, secret, event_types) VALUES ($1, $2, $3, $4)
+		RETURNING ` + webhookColumns
+	created, err := scanWebhook(r.db.QueryRowContext(ctx, query, webhook.PartnerID, webhook.URL, webhook.Secret,
+		pq.Array(eventTypes)))
+	if err != nil {
+		log.Printf("Create webhook failed: %v", err)
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *PostgresWebhookRepository) GetByID(ctx context.Context, id int32) (*Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE webhook_id = $1`
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		log.Printf("Get webhook failed: %v", err)
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *PostgresWebhookRepository) ListByPartner(ctx context.Context, partnerID string) ([]*Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE partner_id = $1 ORDER BY webhook_id`
+	rows, err := r.db.QueryContext(ctx, query, partnerID)
+	if err != nil {
+		log.Printf("List webhooks failed: %v", err)
+		return nil, err
+	}
+	webhooks, err := scanWebhooks(rows)
+	if err != nil {
+		log.Printf("List webhooks failed: %v", err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *PostgresWebhookRepository) ListForEvent(ctx context.Context, userID int32, eventType string) ([]*Webhook, error) {
+	query := `SELECT w.webhook_id, w.partner_id, w.url, w.secret, w.event_types, w.created_at
+		FROM webhooks w
+		JOIN partner_employees e ON e.partner_id = w.partner_id
+		WHERE e.user_id = $1 AND (cardinality(w.event_types) = 0 OR $2 = ANY (w.event_types))
+		ORDER BY w.webhook_id`
+	rows, err := r.db.QueryContext(ctx, query, userID, eventType)
+	if err != nil {
+		log.Printf("List webhooks for event failed: %v", err)
+		return nil, err
+	}
+	webhooks, err := scanWebhooks(rows)
+	if err != nil {
+		log.Printf("List webhooks for event failed: %v", err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *PostgresWebhookRepository) AddEmployee(ctx context.Context, partnerID string, userID int32) error {
+	query := `INSERT INTO partner_employees (partner_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, partnerID, userID); err != nil {
+		log.Printf("Add partner employee failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (r *PostgresWebhookRepository) RemoveEmployee(ctx context.Context, partnerID string, userID int32) error {
+	query := `DELETE FROM partner_employees WHERE partner_id = $1 AND user_id = $2`
+	res, err := r.db.ExecContext(ctx, query, partnerID, userID)
+	if err != nil {
+		log.Printf("Remove partner employee failed: %v", err)
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("Remove partner employee failed: %v", err)
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("employee not found")
+	}
+	return nil
+}
+
+type PostgresWebhookDeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookDeliveryRepository(db *sql.DB) *PostgresWebhookDeliveryRepository {
+	return &PostgresWebhookDeliveryRepository{db: db}
+}
+
+const webhookDeliveryColumns = `delivery_id, webhook_id, event_id, event_type, payload, status, attempts, last_error,
+	last_status_code, next_attempt_at, created_at, delivered_at`
+
+func scanWebhookDelivery(row rowScanner) (*WebhookDelivery, error) {
+	d := &WebhookDelivery{}
+	var deliveredAt sql.NullTime
+	if err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.LastError,
+		&d.LastStatusCode, &d.NextAttemptAt, &d.CreatedAt, &deliveredAt); err != nil {
+		return nil, err
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return d, nil
+}
+
+func scanWebhookDeliveries(rows *sql.Rows) ([]*WebhookDelivery, error) {
+	defer rows.Close()
+	var deliveries []*WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *PostgresWebhookDeliveryRepository) Enqueue(ctx context.Context, deliveries []*WebhookDelivery) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Enqueue webhook deliveries failed: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`
+	queued := 0
+	for _, d := range deliveries {
+		res, err := tx.ExecContext(ctx, query, d.WebhookID, d.EventID, d.EventType, d.Payload, StatusPending)
+		if err != nil {
+			log.Printf("Enqueue webhook deliveries failed: %v", err)
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			log.Printf("Enqueue webhook deliveries failed: %v", err)
+			return 0, err
+		}
+		queued += int(n)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Enqueue webhook deliveries failed: %v", err)
+		return 0, err
+	}
+	return queued, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error) {
+	// SKIP LOCKED lets several instances claim disjoint batches
+	query := `UPDATE webhook_deliveries SET attempts = attempts + 1, next_attempt_at = $2
+		WHERE delivery_id IN (
+			SELECT delivery_id FROM webhook_deliveries
+			WHERE status = $3 AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, delivery_id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), StatusPending, limit)
+	if err != nil {
+		log.Printf("Claim due webhook deliveries failed: %v", err)
+		return nil, err
+	}
+	deliveries, err := scanWebhookDeliveries(rows)
+	if err != nil {
+		log.Printf("Claim due webhook deliveries failed: %v", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) MarkDelivered(ctx context.Context, id int64, statusCode int32, at time.Time) error {
+	query := `UPDATE webhook_deliveries SET status = $1, last_status_code = $2, last_error = '', delivered_at = $3
+		WHERE delivery_id = $4`
+	return r.update(ctx, r.db, "Mark webhook delivered", query, StatusDelivered, statusCode, at, id)
+}
+
+func (r *PostgresWebhookDeliveryRepository) Retry(ctx context.Context, id int64, statusCode int32, lastError string, at time.Time) error {
+	query := `UPDATE webhook_deliveries SET last_status_code = $1, last_error = $2, next_attempt_at = $3
+		WHERE delivery_id = $4`
+	return r.update(ctx, r.db, "Retry webhook delivery", query, statusCode, lastError, at, id)
+}
+
+func (r *PostgresWebhookDeliveryRepository) Kill(ctx context.Context, id int64, statusCode int32, lastError string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Kill webhook delivery failed: %v", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE webhook_deliveries SET status = $1, last_status_code = $2, last_error = $3 WHERE delivery_id = $4`
+	if err := r.update(ctx, tx, "Kill webhook delivery", query, StatusDead, statusCode, lastError, id); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO webhook_dead_letters (delivery_id, webhook_id, attempts, last_error)
+		SELECT delivery_id, webhook_id, attempts, last_error FROM webhook_deliveries WHERE delivery_id = $1
+		ON CONFLICT (delivery_id) DO UPDATE SET
+			attempts = EXCLUDED.attempts,
+			last_error = EXCLUDED.last_error,
+			dead_at = now(),
+			replayed_at = NULL`
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		log.Printf("Kill webhook delivery failed: %v", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Kill webhook delivery failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func (r *PostgresWebhookDeliveryRepository) update(ctx context.Context, db execer, op, query string, args ...any) error {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		log.Printf("%s failed: %v", op, err)
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("%s failed: %v", op, err)
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("delivery not found")
+	}
+	return nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID int32, status string, limit int) ([]*WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY delivery_id DESC
+		LIMIT $3`
+	rows, err := r.db.QueryContext(ctx, query, webhookID, status, limit)
+	if err != nil {
+		log.Printf("List webhook deliveries failed: %v", err)
+		return nil, err
+	}
+	deliveries, err := scanWebhookDeliveries(rows)
+	if err != nil {
+		log.Printf("List webhook deliveries failed: %v", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) Replay(ctx context.Context, webhookID int32, ids []int64) (int, error) {
+	if ids == nil {
+		ids = []int64{}
+	}
+	query := `WITH replayed AS (
+			UPDATE webhook_deliveries SET status = $1, attempts = 0, next_attempt_at = now()
+			WHERE webhook_id = $2 AND status = $3 AND (cardinality($4::BIGINT[]) = 0 OR delivery_id = ANY ($4))
+			RETURNING delivery_id
+		)
+		UPDATE webhook_dead_letters SET replayed_at = now()
+		WHERE delivery_id IN (SELECT delivery_id FROM replayed)`
+	res, err := r.db.ExecContext(ctx, query, StatusPending, webhookID, StatusDead, pq.Array(ids))
+	if err != nil {
+		log.Printf("Replay webhook deliveries failed: %v", err)
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("Replay webhook deliveries failed: %v", err)
+		return 0, err
+	}
+	return int(n), nil
+}
@@ -0,0 +1,578 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"regexp"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"notification-service/channels"
+	"notification-service/notifier"
+	pb "notification-service/pb/proto/notification"
+	"notification-service/repository"
+	"notification-service/templates"
+	"notification-service/webhooks"
+
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	"github.com/hasnain-zafar/go-microservices/common/errors"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+)
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 500
+)
+
+// partnerIDPattern matches partner IDs such as "acme" or "globex-pk".
+var partnerIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+type NotificationServer struct {
+	pb.UnimplementedNotificationServiceServer
+	preferences  repository.PreferenceRepository
+	deliveries   repository.DeliveryRepository
+	catalog      *templates.Catalog
+	logger       *logger.Logger
+	errorHandler *errors.ErrorHandler
+	serviceName  string
+
+	webhooks          repository.WebhookRepository
+	webhookDeliveries repository.WebhookDeliveryRepository
+	resolver          webhooks.Resolver
+}
+
+// Option configures optional NotificationServer dependencies.
+type Option func(*NotificationServer)
+
+// WithMetrics counts the server's errors in reg rather than in a registry of
+// its own.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(s *NotificationServer) {
+		s.logger = s.logger.WithMetrics(reg)
+		s.errorHandler = errors.NewErrorHandler(s.logger)
+	}
+}
+
+// WithWebhooks enables the partner webhook RPCs, which only admins may call.
+func WithWebhooks(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository) Option {
+	return func(s *NotificationServer) {
+		s.webhooks = webhooks
+		s.webhookDeliveries = deliveries
+	}
+}
+
+// WithResolver sets how webhook hosts are resolved to check that they are
+// public.
+func WithResolver(resolver webhooks.Resolver) Option {
+	return func(s *NotificationServer) {
+		s.resolver = resolver
+	}
+}
+
+func NewNotificationServer(
+	preferences repository.PreferenceRepository,
+	deliveries repository.DeliveryRepository,
+	catalog *templates.Catalog,
+	opts ...Option,
+) *NotificationServer {
+	serviceName := "notification-service"
+	log := logger.NewLogger(serviceName)
+	s := &NotificationServer{
+		preferences:  preferences,
+		deliveries:   deliveries,
+		catalog:      catalog,
+		logger:       log,
+		errorHandler: errors.NewErrorHandler(log),
+		serviceName:  serviceName,
+		resolver:     net.DefaultResolver,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// SetPreferences replaces where and how the user is notified. Messages
+// already queued are sent as they were addressed.
+func (s *NotificationServer) SetPreferences(ctx context.Context, req *pb.SetPreferencesRequest) (*pb.Preferences, error) {
+	method := "SetPreferences"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	prefs, err := s.validatePreferences(req.GetPreferences())
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid preferences", err)
+	}
+
+	prefs, err = s.preferences.Set(ctx, prefs)
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to set preferences", err)
+	}
+
+	res := preferencesToProto(prefs)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// GetPreferences returns the user's preferences, or the defaults if they
+// never set any.
+func (s *NotificationServer) GetPreferences(ctx context.Context, req *pb.GetPreferencesRequest) (*pb.Preferences, error) {
+	method := "GetPreferences"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetUserId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid user ID", fmt.Errorf("user ID must be positive"))
+	}
+
+	prefs, err := s.preferences.Get(ctx, req.UserId)
+	if err != nil {
+		if err.Error() != "preferences not found" {
+			return nil, s.errorHandler.HandleDatabaseError("failed to get preferences", err)
+		}
+		prefs = notifier.DefaultPreferences(req.UserId)
+	}
+
+	res := preferencesToProto(prefs)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// ListDeliveries returns the user's latest messages and whether they were
+// sent.
+func (s *NotificationServer) ListDeliveries(ctx context.Context, req *pb.ListDeliveriesRequest) (*pb.ListDeliveriesResponse, error) {
+	method := "ListDeliveries"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetUserId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid user ID", fmt.Errorf("user ID must be positive"))
+	}
+	limit := int(req.Limit)
+	switch {
+	case limit < 0 || limit > maxDeliveryLimit:
+		return nil, s.errorHandler.HandleInvalidArgument("invalid limit",
+			fmt.Errorf("limit must be between 0 and %d", maxDeliveryLimit))
+	case limit == 0:
+		limit = defaultDeliveryLimit
+	}
+
+	deliveries, err := s.deliveries.ListByUser(ctx, req.UserId, limit)
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to list deliveries", err)
+	}
+
+	res := &pb.ListDeliveriesResponse{}
+	for _, d := range deliveries {
+		res.Deliveries = append(res.Deliveries, deliveryToProto(d))
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// CreateWebhook registers a partner endpoint and returns it with the secret
+// its requests are signed with. The secret is not returned again. The
+// endpoint's host must resolve only to public addresses.
+func (s *NotificationServer) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.Webhook, error) {
+	method := "CreateWebhook"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.webhooks == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("webhooks unavailable", fmt.Errorf("webhooks are not enabled"))
+	}
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.validateCreateWebhookRequest(ctx, req)
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid webhook", err)
+	}
+	if webhook.Secret, err = webhooks.NewSecret(); err != nil {
+		return nil, s.errorHandler.HandleInternalError("failed to generate webhook secret", err)
+	}
+
+	webhook, err = s.webhooks.Create(ctx, webhook)
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to create webhook", err)
+	}
+
+	res := webhookToProto(webhook)
+	res.Secret = webhook.Secret
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// ListWebhooks returns a partner's webhooks, without their secrets.
+func (s *NotificationServer) ListWebhooks(ctx context.Context, req *pb.ListWebhooksRequest) (*pb.ListWebhooksResponse, error) {
+	method := "ListWebhooks"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.webhooks == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("webhooks unavailable", fmt.Errorf("webhooks are not enabled"))
+	}
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := validatePartnerID(req.GetPartnerId()); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid partner ID", err)
+	}
+
+	list, err := s.webhooks.ListByPartner(ctx, req.PartnerId)
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to list webhooks", err)
+	}
+
+	res := &pb.ListWebhooksResponse{}
+	for _, webhook := range list {
+		res.Webhooks = append(res.Webhooks, webhookToProto(webhook))
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// AddPartnerEmployee sends events about the user's bookings to the partner's
+// webhooks. Adding an employee twice has no effect.
+func (s *NotificationServer) AddPartnerEmployee(ctx context.Context, req *pb.AddPartnerEmployeeRequest) (*pb.AddPartnerEmployeeResponse, error) {
+	method := "AddPartnerEmployee"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.webhooks == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("webhooks unavailable", fmt.Errorf("webhooks are not enabled"))
+	}
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := validatePartnerEmployee(req.GetPartnerId(), req.GetUserId()); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid partner employee", err)
+	}
+
+	if err := s.webhooks.AddEmployee(ctx, req.PartnerId, req.UserId); err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to add partner employee", err)
+	}
+
+	res := &pb.AddPartnerEmployeeResponse{}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// RemovePartnerEmployee stops sending events about the user's bookings to
+// the partner's webhooks. Deliveries already queued are still sent.
+func (s *NotificationServer) RemovePartnerEmployee(ctx context.Context, req *pb.RemovePartnerEmployeeRequest) (*pb.RemovePartnerEmployeeResponse, error) {
+	method := "RemovePartnerEmployee"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.webhooks == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("webhooks unavailable", fmt.Errorf("webhooks are not enabled"))
+	}
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := validatePartnerEmployee(req.GetPartnerId(), req.GetUserId()); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid partner employee", err)
+	}
+
+	if err := s.webhooks.RemoveEmployee(ctx, req.PartnerId, req.UserId); err != nil {
+		if err.Error() == "employee not found" {
+			return nil, s.errorHandler.HandleNotFound("partner employee not found", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to remove partner employee", err)
+	}
+
+	res := &pb.RemovePartnerEmployeeResponse{}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// ListWebhookDeliveries returns a webhook's latest deliveries, optionally
+// only those with a status.
+func (s *NotificationServer) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	method := "ListWebhookDeliveries"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.webhooks == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("webhooks unavailable", fmt.Errorf("webhooks are not enabled"))
+	}
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	switch req.GetStatus() {
+	case "", repository.StatusPending, repository.StatusDelivered, repository.StatusDead:
+	default:
+		return nil, s.errorHandler.HandleInvalidArgument("invalid status",
+			fmt.Errorf("status %q must be PENDING, DELIVERED or DEAD", req.GetStatus()))
+	}
+	limit := int(req.GetLimit())
+	switch {
+	case limit < 0 || limit > maxDeliveryLimit:
+		return nil, s.errorHandler.HandleInvalidArgument("invalid limit",
+			fmt.Errorf("limit must be between 0 and %d", maxDeliveryLimit))
+	case limit == 0:
+		limit = defaultDeliveryLimit
+	}
+	if err := s.checkWebhook(ctx, req.GetWebhookId()); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookDeliveries.ListByWebhook(ctx, req.WebhookId, req.Status, limit)
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to list webhook deliveries", err)
+	}
+
+	res := &pb.ListWebhookDeliveriesResponse{}
+	for _, d := range deliveries {
+		res.Deliveries = append(res.Deliveries, webhookDeliveryToProto(d))
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// ReplayWebhookDeliveries queues dead deliveries of a webhook to be attempted
+// afresh, with a full set of retries. Deliveries that are not dead are left
+// alone.
+func (s *NotificationServer) ReplayWebhookDeliveries(ctx context.Context, req *pb.ReplayWebhookDeliveriesRequest) (*pb.ReplayWebhookDeliveriesResponse, error) {
+	method := "ReplayWebhookDeliveries"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.webhooks == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("webhooks unavailable", fmt.Errorf("webhooks are not enabled"))
+	}
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkWebhook(ctx, req.GetWebhookId()); err != nil {
+		return nil, err
+	}
+
+	replayed, err := s.webhookDeliveries.Replay(ctx, req.WebhookId, req.DeliveryIds)
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to replay webhook deliveries", err)
+	}
+
+	res := &pb.ReplayWebhookDeliveriesResponse{Replayed: int32(replayed)}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// requireAdmin returns a gRPC error unless the caller sent the admin token.
+// Webhooks are registered for partners by the platform's staff, and are sent
+// booking events of the partner's employees.
+func (s *NotificationServer) requireAdmin(ctx context.Context) error {
+	if !audit.IsAdmin(ctx) {
+		return s.errorHandler.HandlePermissionDenied("webhooks are managed by admins only",
+			fmt.Errorf("caller is %s, not %s", audit.Actor(ctx), audit.Admin))
+	}
+	return nil
+}
+
+// checkWebhook returns a gRPC error unless webhookID is an existing webhook.
+func (s *NotificationServer) checkWebhook(ctx context.Context, webhookID int32) error {
+	if webhookID <= 0 {
+		return s.errorHandler.HandleInvalidArgument("invalid webhook ID", fmt.Errorf("webhook ID must be positive"))
+	}
+	if _, err := s.webhooks.GetByID(ctx, webhookID); err != nil {
+		if err.Error() == "webhook not found" {
+			return s.errorHandler.HandleNotFound("webhook not found", err)
+		}
+		return s.errorHandler.HandleDatabaseError("failed to get webhook", err)
+	}
+	return nil
+}
+
+func (s *NotificationServer) validateCreateWebhookRequest(ctx context.Context, req *pb.CreateWebhookRequest) (*repository.Webhook, error) {
+	if err := validatePartnerID(req.PartnerId); err != nil {
+		return nil, err
+	}
+	if err := webhooks.CheckURL(ctx, s.resolver, req.Url); err != nil {
+		return nil, err
+	}
+
+	webhook := &repository.Webhook{PartnerID: req.PartnerId, URL: req.Url}
+	seen := make(map[string]bool)
+	for _, eventType := range req.EventTypes {
+		if !knownEventType(eventType) {
+			return nil, fmt.Errorf("unknown event type %q, want one of %v", eventType, webhooks.EventTypes)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			webhook.EventTypes = append(webhook.EventTypes, eventType)
+		}
+	}
+	return webhook, nil
+}
+
+func validatePartnerID(partnerID string) error {
+	if !partnerIDPattern.MatchString(partnerID) {
+		return fmt.Errorf("partner ID %q must be lower case letters, digits, '-' and '_'", partnerID)
+	}
+	return nil
+}
+
+func validatePartnerEmployee(partnerID string, userID int32) error {
+	if err := validatePartnerID(partnerID); err != nil {
+		return err
+	}
+	if userID <= 0 {
+		return fmt.Errorf("user ID must be positive")
+	}
+	return nil
+}
+
+func knownEventType(eventType string) bool {
+	for _, known := range webhooks.EventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *NotificationServer) validatePreferences(p *pb.Preferences) (*repository.Preferences, error) {
+	if p == nil {
+		return nil, fmt.Errorf("preferences are required")
+	}
+	if p.UserId <= 0 {
+		return nil, fmt.Errorf("user ID must be positive")
+	}
+
+	prefs := &repository.Preferences{
+		UserID:    p.UserId,
+		Locale:    p.Locale,
+		Email:     p.Email,
+		Phone:     p.Phone,
+		PushToken: p.PushToken,
+	}
+	if prefs.Locale == "" {
+		prefs.Locale = templates.DefaultLocale
+	}
+	if !s.catalog.HasLocale(prefs.Locale) {
+		return nil, fmt.Errorf("unsupported locale %q, want one of %v", prefs.Locale, s.catalog.Locales())
+	}
+	if prefs.Email != "" {
+		addr, err := mail.ParseAddress(prefs.Email)
+		if err != nil || addr.Address != prefs.Email {
+			return nil, fmt.Errorf("invalid email address %q", prefs.Email)
+		}
+	}
+	if prefs.Phone != "" && !channels.ValidPhone(prefs.Phone) {
+		return nil, fmt.Errorf("phone number %q must be in E.164 format", prefs.Phone)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range p.Channels {
+		if !knownChannel(name) {
+			return nil, fmt.Errorf("unknown channel %q, want one of %v", name, channels.Names)
+		}
+		if !seen[name] {
+			seen[name] = true
+			prefs.Channels = append(prefs.Channels, name)
+		}
+	}
+	return prefs, nil
+}
+
+func knownChannel(name string) bool {
+	for _, known := range channels.Names {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+func preferencesToProto(p *repository.Preferences) *pb.Preferences {
+	res := &pb.Preferences{
+		UserId:    p.UserID,
+		Locale:    p.Locale,
+		Email:     p.Email,
+		Phone:     p.Phone,
+		PushToken: p.PushToken,
+		Channels:  p.Channels,
+	}
+	if !p.UpdatedAt.IsZero() {
+		res.UpdatedAt = timestamppb.New(p.UpdatedAt)
+	}
+	return res
+}
+
+func deliveryToProto(d *repository.Delivery) *pb.Delivery {
+	res := &pb.Delivery{
+		DeliveryId: d.ID,
+		EventId:    d.EventID,
+		EventType:  d.EventType,
+		UserId:     d.UserID,
+		Channel:    d.Channel,
+		Address:    d.Address,
+		Locale:     d.Locale,
+		Subject:    d.Subject,
+		Body:       d.Body,
+		Status:     d.Status,
+		Attempts:   d.Attempts,
+		LastError:  d.LastError,
+		CreatedAt:  timestamppb.New(d.CreatedAt),
+	}
+	if d.SentAt != nil {
+		res.SentAt = timestamppb.New(*d.SentAt)
+	}
+	return res
+}
+
+// webhookToProto leaves out the secret, which is only returned on creation.
+func webhookToProto(w *repository.Webhook) *pb.Webhook {
+	return &pb.Webhook{
+		WebhookId:  w.ID,
+		PartnerId:  w.PartnerID,
+		Url:        w.URL,
+		EventTypes: w.EventTypes,
+		CreatedAt:  timestamppb.New(w.CreatedAt),
+	}
+}
+
+func webhookDeliveryToProto(d *repository.WebhookDelivery) *pb.WebhookDelivery {
+	res := &pb.WebhookDelivery{
+		DeliveryId:     d.ID,
+		WebhookId:      d.WebhookID,
+		EventId:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        string(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastError:      d.LastError,
+		LastStatusCode: d.LastStatusCode,
+		CreatedAt:      timestamppb.New(d.CreatedAt),
+	}
+	if d.Status == repository.StatusPending {
+		res.NextAttemptAt = timestamppb.New(d.NextAttemptAt)
+	}
+	if d.DeliveredAt != nil {
+		res.DeliveredAt = timestamppb.New(*d.DeliveredAt)
+	}
+	return res
+}
@@ -0,0 +1,455 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "notification-service/pb/proto/notification"
+	"notification-service/repository"
+	"notification-service/repository/mocks"
+	"notification-service/templates"
+
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+)
+
+var testUpdatedAt = time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+
+// assertProtoEqual compares messages by content. Logging a response caches
+// reflection state in it, which assert.Equal would compare as well.
+func assertProtoEqual(t *testing.T, want, got proto.Message) {
+	t.Helper()
+	assert.True(t, proto.Equal(want, got), "want %v\ngot  %v", want, got)
+}
+
+func newTestServer(t *testing.T, prefs *mocks.PreferenceRepository, deliveries *mocks.DeliveryRepository) *NotificationServer {
+	t.Helper()
+	catalog, err := templates.Default()
+	require.NoError(t, err)
+	return NewNotificationServer(prefs, deliveries, catalog)
+}
+
+func TestSetPreferences(t *testing.T) {
+	// Setup
+	mockPrefs := new(mocks.PreferenceRepository)
+	s := newTestServer(t, mockPrefs, new(mocks.DeliveryRepository))
+
+	ctx := context.Background()
+	want := &repository.Preferences{
+		UserID:   3,
+		Locale:   "ur",
+		Email:    "fatima@example.com",
+		Phone:    "+923001234567",
+		Channels: []string{"email", "sms"},
+	}
+	saved := *want
+	saved.UpdatedAt = testUpdatedAt
+	mockPrefs.On("Set", ctx, want).Return(&saved, nil)
+
+	// Execute
+	res, err := s.SetPreferences(ctx, &pb.SetPreferencesRequest{Preferences: &pb.Preferences{
+		UserId:   3,
+		Locale:   "ur",
+		Email:    "fatima@example.com",
+		Phone:    "+923001234567",
+		Channels: []string{"email", "sms", "email"},
+	}})
+
+	// Assert
+	require.NoError(t, err)
+	assertProtoEqual(t, &pb.Preferences{
+		UserId:    3,
+		Locale:    "ur",
+		Email:     "fatima@example.com",
+		Phone:     "+923001234567",
+		Channels:  []string{"email", "sms"},
+		UpdatedAt: timestamppb.New(testUpdatedAt),
+	}, res)
+}
+
+func TestSetPreferences_DefaultLocale(t *testing.T) {
+	mockPrefs := new(mocks.PreferenceRepository)
+	s := newTestServer(t, mockPrefs, new(mocks.DeliveryRepository))
+
+	ctx := context.Background()
+	mockPrefs.On("Set", ctx, mock.MatchedBy(func(p *repository.Preferences) bool {
+		return p.Locale == "en" && p.Channels == nil
+	})).Return(&repository.Preferences{UserID: 3, Locale: "en", UpdatedAt: testUpdatedAt}, nil)
+
+	res, err := s.SetPreferences(ctx, &pb.SetPreferencesRequest{Preferences: &pb.Preferences{UserId: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, "en", res.Locale)
+	assert.Empty(t, res.Channels)
+}
+
+func TestSetPreferences_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		prefs *pb.Preferences
+	}{
+		{"missing", nil},
+		{"no user", &pb.Preferences{}},
+		{"unknown locale", &pb.Preferences{UserId: 3, Locale: "fr"}},
+		{"invalid email", &pb.Preferences{UserId: 3, Email: "fatima"}},
+		{"named email", &pb.Preferences{UserId: 3, Email: "Fatima <fatima@example.com>"}},
+		{"local phone", &pb.Preferences{UserId: 3, Phone: "03001234567"}},
+		{"unknown channel", &pb.Preferences{UserId: 3, Channels: []string{"fax"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPrefs := new(mocks.PreferenceRepository)
+			s := newTestServer(t, mockPrefs, new(mocks.DeliveryRepository))
+
+			_, err := s.SetPreferences(context.Background(), &pb.SetPreferencesRequest{Preferences: tt.prefs})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			mockPrefs.AssertNotCalled(t, "Set", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetPreferences(t *testing.T) {
+	// Setup
+	mockPrefs := new(mocks.PreferenceRepository)
+	s := newTestServer(t, mockPrefs, new(mocks.DeliveryRepository))
+
+	ctx := context.Background()
+	mockPrefs.On("Get", ctx, int32(3)).Return(&repository.Preferences{
+		UserID: 3, Locale: "ur", PushToken: "device-1", Channels: []string{"push"}, UpdatedAt: testUpdatedAt,
+	}, nil)
+	mockPrefs.On("Get", ctx, int32(4)).Return(nil, errors.New("preferences not found"))
+	mockPrefs.On("Get", ctx, int32(5)).Return(nil, errors.New("connection refused"))
+
+	// Execute & Assert
+	res, err := s.GetPreferences(ctx, &pb.GetPreferencesRequest{UserId: 3})
+	require.NoError(t, err)
+	assertProtoEqual(t, &pb.Preferences{
+		UserId: 3, Locale: "ur", PushToken: "device-1", Channels: []string{"push"}, UpdatedAt: timestamppb.New(testUpdatedAt),
+	}, res)
+
+	// Users who never set preferences get the defaults
+	res, err = s.GetPreferences(ctx, &pb.GetPreferencesRequest{UserId: 4})
+	require.NoError(t, err)
+	assertProtoEqual(t, &pb.Preferences{UserId: 4, Locale: "en", Channels: []string{"email", "sms", "push", "log"}}, res)
+
+	_, err = s.GetPreferences(ctx, &pb.GetPreferencesRequest{UserId: 5})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	_, err = s.GetPreferences(ctx, &pb.GetPreferencesRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListDeliveries(t *testing.T) {
+	// Setup
+	mockDeliveries := new(mocks.DeliveryRepository)
+	s := newTestServer(t, new(mocks.PreferenceRepository), mockDeliveries)
+
+	ctx := context.Background()
+	sentAt := testUpdatedAt.Add(time.Second)
+	mockDeliveries.On("ListByUser", ctx, int32(3), 50).Return([]*repository.Delivery{
+		{
+			ID: 2, EventID: 42, EventType: "BookingCancelled", UserID: 3, Channel: "sms", Address: "+923001234567",
+			Locale: "en", Subject: "Your booking was cancelled", Body: "Booking #7 has been cancelled.",
+			Status: repository.StatusPending, Attempts: 1, LastError: "gateway timeout", CreatedAt: testUpdatedAt,
+		},
+		{
+			ID: 1, EventID: 41, EventType: "BookingCreated", UserID: 3, Channel: "log", Address: "3",
+			Locale: "en", Subject: "Your booking is confirmed", Body: "Booking #7 is confirmed.",
+			Status: repository.StatusSent, Attempts: 1, CreatedAt: testUpdatedAt, SentAt: &sentAt,
+		},
+	}, nil)
+	mockDeliveries.On("ListByUser", ctx, int32(3), 10).Return(nil, errors.New("connection refused"))
+
+	// Execute & Assert
+	res, err := s.ListDeliveries(ctx, &pb.ListDeliveriesRequest{UserId: 3})
+	require.NoError(t, err)
+	require.Len(t, res.Deliveries, 2)
+	assert.Equal(t, &pb.Delivery{
+		DeliveryId: 2, EventId: 42, EventType: "BookingCancelled", UserId: 3, Channel: "sms", Address: "+923001234567",
+		Locale: "en", Subject: "Your booking was cancelled", Body: "Booking #7 has been cancelled.",
+		Status: "PENDING", Attempts: 1, LastError: "gateway timeout", CreatedAt: timestamppb.New(testUpdatedAt),
+	}, res.Deliveries[0])
+	assert.Equal(t, timestamppb.New(sentAt), res.Deliveries[1].SentAt)
+
+	_, err = s.ListDeliveries(ctx, &pb.ListDeliveriesRequest{UserId: 3, Limit: 10})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	_, err = s.ListDeliveries(ctx, &pb.ListDeliveriesRequest{UserId: 3, Limit: 501})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.ListDeliveries(ctx, &pb.ListDeliveriesRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func newWebhookTestServer(t *testing.T, webhooks *mocks.WebhookRepository, deliveries *mocks.WebhookDeliveryRepository) *NotificationServer {
+	t.Helper()
+	catalog, err := templates.Default()
+	require.NoError(t, err)
+	return NewNotificationServer(new(mocks.PreferenceRepository), new(mocks.DeliveryRepository), catalog,
+		WithWebhooks(webhooks, deliveries),
+		WithResolver(fakeResolver{
+			"acme.example.com":     {"93.184.215.14"},
+			"internal.example.com": {"10.0.0.5"},
+		}))
+}
+
+// fakeResolver resolves the hosts it maps, and no others.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	var addrs []net.IPAddr
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+// adminContext is the context of a call bearing the admin token.
+func adminContext() context.Context {
+	return audit.WithActor(context.Background(), audit.Admin)
+}
+
+func TestCreateWebhook(t *testing.T) {
+	// Setup
+	mockWebhooks := new(mocks.WebhookRepository)
+	s := newWebhookTestServer(t, mockWebhooks, new(mocks.WebhookDeliveryRepository))
+
+	ctx := adminContext()
+	var secret string
+	mockWebhooks.On("Create", ctx, mock.MatchedBy(func(w *repository.Webhook) bool {
+		secret = w.Secret
+		return w.PartnerID == "acme" && w.URL == "https://acme.example.com/hooks" &&
+			assert.ObjectsAreEqual([]string{"BookingCreated", "BookingCancelled"}, w.EventTypes)
+	})).Return(func(ctx context.Context, w *repository.Webhook) *repository.Webhook {
+		created := *w
+		created.ID = 1
+		created.CreatedAt = testUpdatedAt
+		return &created
+	}, nil)
+
+	// Execute
+	res, err := s.CreateWebhook(ctx, &pb.CreateWebhookRequest{
+		PartnerId:  "acme",
+		Url:        "https://acme.example.com/hooks",
+		EventTypes: []string{"BookingCreated", "BookingCancelled", "BookingCreated"},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assertProtoEqual(t, &pb.Webhook{
+		WebhookId:  1,
+		PartnerId:  "acme",
+		Url:        "https://acme.example.com/hooks",
+		Secret:     secret,
+		EventTypes: []string{"BookingCreated", "BookingCancelled"},
+		CreatedAt:  timestamppb.New(testUpdatedAt),
+	}, res)
+	assert.NotEmpty(t, secret)
+}
+
+func TestCreateWebhook_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.CreateWebhookRequest
+	}{
+		{"no partner", &pb.CreateWebhookRequest{Url: "https://acme.example.com/hooks"}},
+		{"upper case partner", &pb.CreateWebhookRequest{PartnerId: "Acme", Url: "https://acme.example.com/hooks"}},
+		{"relative URL", &pb.CreateWebhookRequest{PartnerId: "acme", Url: "/hooks"}},
+		{"ftp URL", &pb.CreateWebhookRequest{PartnerId: "acme", Url: "ftp://acme.example.com/hooks"}},
+		{"unknown event", &pb.CreateWebhookRequest{PartnerId: "acme", Url: "https://acme.example.com/hooks", EventTypes: []string{"UserDeleted"}}},
+		{"loopback URL", &pb.CreateWebhookRequest{PartnerId: "acme", Url: "http://127.0.0.1:2117/admin/build"}},
+		{"metadata URL", &pb.CreateWebhookRequest{PartnerId: "acme", Url: "http://169.254.169.254/latest/meta-data"}},
+		{"private host", &pb.CreateWebhookRequest{PartnerId: "acme", Url: "https://internal.example.com/hooks"}},
+		{"unresolvable host", &pb.CreateWebhookRequest{PartnerId: "acme", Url: "https://nowhere.example.com/hooks"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockWebhooks := new(mocks.WebhookRepository)
+			s := newWebhookTestServer(t, mockWebhooks, new(mocks.WebhookDeliveryRepository))
+
+			_, err := s.CreateWebhook(adminContext(), tt.req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			mockWebhooks.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWebhooks_NotEnabled(t *testing.T) {
+	s := newTestServer(t, new(mocks.PreferenceRepository), new(mocks.DeliveryRepository))
+	ctx := adminContext()
+
+	_, err := s.CreateWebhook(ctx, &pb.CreateWebhookRequest{PartnerId: "acme", Url: "https://acme.example.com/hooks"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, err = s.ReplayWebhookDeliveries(ctx, &pb.ReplayWebhookDeliveriesRequest{WebhookId: 1})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestWebhooks_AdminOnly(t *testing.T) {
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	s := newWebhookTestServer(t, mockWebhooks, mockDeliveries)
+
+	calls := map[string]func(ctx context.Context) error{
+		"CreateWebhook": func(ctx context.Context) error {
+			_, err := s.CreateWebhook(ctx, &pb.CreateWebhookRequest{PartnerId: "acme", Url: "https://acme.example.com/hooks"})
+			return err
+		},
+		"ListWebhooks": func(ctx context.Context) error {
+			_, err := s.ListWebhooks(ctx, &pb.ListWebhooksRequest{PartnerId: "acme"})
+			return err
+		},
+		"AddPartnerEmployee": func(ctx context.Context) error {
+			_, err := s.AddPartnerEmployee(ctx, &pb.AddPartnerEmployeeRequest{PartnerId: "acme", UserId: 3})
+			return err
+		},
+		"RemovePartnerEmployee": func(ctx context.Context) error {
+			_, err := s.RemovePartnerEmployee(ctx, &pb.RemovePartnerEmployeeRequest{PartnerId: "acme", UserId: 3})
+			return err
+		},
+		"ListWebhookDeliveries": func(ctx context.Context) error {
+			_, err := s.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{WebhookId: 1})
+			return err
+		},
+		"ReplayWebhookDeliveries": func(ctx context.Context) error {
+			_, err := s.ReplayWebhookDeliveries(ctx, &pb.ReplayWebhookDeliveriesRequest{WebhookId: 1})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, codes.PermissionDenied, status.Code(call(context.Background())))
+			assert.Equal(t, codes.PermissionDenied, status.Code(call(audit.WithActor(context.Background(), audit.Anonymous))))
+		})
+	}
+	// Nothing is read or changed
+	assert.Empty(t, mockWebhooks.Calls)
+	assert.Empty(t, mockDeliveries.Calls)
+}
+
+func TestListWebhooks(t *testing.T) {
+	mockWebhooks := new(mocks.WebhookRepository)
+	s := newWebhookTestServer(t, mockWebhooks, new(mocks.WebhookDeliveryRepository))
+
+	ctx := adminContext()
+	mockWebhooks.On("ListByPartner", ctx, "acme").Return([]*repository.Webhook{
+		{ID: 1, PartnerID: "acme", URL: "https://acme.example.com/hooks", Secret: "whsec_1", CreatedAt: testUpdatedAt},
+	}, nil)
+
+	res, err := s.ListWebhooks(ctx, &pb.ListWebhooksRequest{PartnerId: "acme"})
+	require.NoError(t, err)
+	// Secrets are never listed
+	assert.Equal(t, []*pb.Webhook{
+		{WebhookId: 1, PartnerId: "acme", Url: "https://acme.example.com/hooks", CreatedAt: timestamppb.New(testUpdatedAt)},
+	}, res.Webhooks)
+
+	_, err = s.ListWebhooks(ctx, &pb.ListWebhooksRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestPartnerEmployees(t *testing.T) {
+	mockWebhooks := new(mocks.WebhookRepository)
+	s := newWebhookTestServer(t, mockWebhooks, new(mocks.WebhookDeliveryRepository))
+
+	ctx := adminContext()
+	mockWebhooks.On("AddEmployee", ctx, "acme", int32(3)).Return(nil)
+	mockWebhooks.On("RemoveEmployee", ctx, "acme", int32(3)).Return(nil)
+	mockWebhooks.On("RemoveEmployee", ctx, "acme", int32(4)).Return(errors.New("employee not found"))
+
+	_, err := s.AddPartnerEmployee(ctx, &pb.AddPartnerEmployeeRequest{PartnerId: "acme", UserId: 3})
+	require.NoError(t, err)
+	_, err = s.RemovePartnerEmployee(ctx, &pb.RemovePartnerEmployeeRequest{PartnerId: "acme", UserId: 3})
+	require.NoError(t, err)
+
+	_, err = s.RemovePartnerEmployee(ctx, &pb.RemovePartnerEmployeeRequest{PartnerId: "acme", UserId: 4})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = s.AddPartnerEmployee(ctx, &pb.AddPartnerEmployeeRequest{PartnerId: "acme"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	// Setup
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	s := newWebhookTestServer(t, mockWebhooks, mockDeliveries)
+
+	ctx := adminContext()
+	mockWebhooks.On("GetByID", ctx, int32(1)).Return(&repository.Webhook{ID: 1, PartnerID: "acme"}, nil)
+	mockWebhooks.On("GetByID", ctx, int32(2)).Return(nil, errors.New("webhook not found"))
+	deliveredAt := testUpdatedAt.Add(time.Second)
+	mockDeliveries.On("ListByWebhook", ctx, int32(1), "", 50).Return([]*repository.WebhookDelivery{
+		{
+			ID: 11, WebhookID: 1, EventID: 42, EventType: "BookingCancelled", Payload: []byte(`{"event_id":42}`),
+			Status: repository.StatusPending, Attempts: 2, LastError: "endpoint returned 503 Service Unavailable",
+			LastStatusCode: 503, NextAttemptAt: testUpdatedAt.Add(time.Minute), CreatedAt: testUpdatedAt,
+		},
+		{
+			ID: 10, WebhookID: 1, EventID: 41, EventType: "BookingCreated", Payload: []byte(`{"event_id":41}`),
+			Status: repository.StatusDelivered, Attempts: 1, LastStatusCode: 204, NextAttemptAt: testUpdatedAt,
+			CreatedAt: testUpdatedAt, DeliveredAt: &deliveredAt,
+		},
+	}, nil)
+	mockDeliveries.On("ListByWebhook", ctx, int32(1), "DEAD", 10).Return(nil, nil)
+
+	// Execute & Assert
+	res, err := s.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{WebhookId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []*pb.WebhookDelivery{
+		{
+			DeliveryId: 11, WebhookId: 1, EventId: 42, EventType: "BookingCancelled", Payload: `{"event_id":42}`,
+			Status: "PENDING", Attempts: 2, LastError: "endpoint returned 503 Service Unavailable", LastStatusCode: 503,
+			NextAttemptAt: timestamppb.New(testUpdatedAt.Add(time.Minute)), CreatedAt: timestamppb.New(testUpdatedAt),
+		},
+		{
+			DeliveryId: 10, WebhookId: 1, EventId: 41, EventType: "BookingCreated", Payload: `{"event_id":41}`,
+			Status: "DELIVERED", Attempts: 1, LastStatusCode: 204, CreatedAt: timestamppb.New(testUpdatedAt),
+			DeliveredAt: timestamppb.New(deliveredAt),
+		},
+	}, res.Deliveries)
+
+	res, err = s.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{WebhookId: 1, Status: "DEAD", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, res.Deliveries)
+
+	_, err = s.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{WebhookId: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = s.ListWebhookDeliveries(ctx, &pb.ListWebhookDeliveriesRequest{WebhookId: 1, Status: "SENT"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestReplayWebhookDeliveries(t *testing.T) {
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	s := newWebhookTestServer(t, mockWebhooks, mockDeliveries)
+
+	ctx := adminContext()
+	mockWebhooks.On("GetByID", ctx, int32(1)).Return(&repository.Webhook{ID: 1, PartnerID: "acme"}, nil)
+	mockDeliveries.On("Replay", ctx, int32(1), []int64{12, 13}).Return(1, nil)
+	mockDeliveries.On("Replay", ctx, int32(1), []int64(nil)).Return(3, nil)
+
+	res, err := s.ReplayWebhookDeliveries(ctx, &pb.ReplayWebhookDeliveriesRequest{WebhookId: 1, DeliveryIds: []int64{12, 13}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), res.Replayed)
+
+	res, err = s.ReplayWebhookDeliveries(ctx, &pb.ReplayWebhookDeliveriesRequest{WebhookId: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), res.Replayed)
+
+	_, err = s.ReplayWebhookDeliveries(ctx, &pb.ReplayWebhookDeliveriesRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,150 @@
+// Package templates renders notification messages from per-locale templates.
+// Each locale is a directory of <EventType>.tmpl files defining a "subject"
+// and a "body" template.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed locales
+var bundled embed.FS
+
+// DefaultLocale is used for users without a locale, and for events a
+// locale has no template for.
+const DefaultLocale = "en"
+
+// ErrNoTemplate means users are not notified of the event type.
+var ErrNoTemplate = errors.New("no template for event")
+
+// Data is what templates can refer to.
+type Data struct {
+	BookingID int32
+	Status    string
+	DriverID  int32
+	PickupAt  *time.Time
+	PromoCode string
+}
+
+// Rendered is a message rendered in a locale.
+type Rendered struct {
+	Locale  string
+	Subject string
+	Body    string
+}
+
+// Catalog holds every locale's templates.
+type Catalog struct {
+	// templates maps locale and event type to a template
+	templates map[string]map[string]*template.Template
+}
+
+var funcs = template.FuncMap{
+	// time formats a time in UTC, or nothing if it is unset
+	"time": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format("2 Jan 2006 15:04 UTC")
+	},
+}
+
+// Default returns the catalog bundled with the service.
+func Default() (*Catalog, error) {
+	locales, err := fs.Sub(bundled, "locales")
+	if err != nil {
+		return nil, err
+	}
+	return Load(locales)
+}
+
+// Load reads a catalog with a directory per locale from fsys. The default
+// locale must be among them.
+func Load(fsys fs.FS) (*Catalog, error) {
+	c := &Catalog{templates: make(map[string]map[string]*template.Template)}
+
+	files, err := fs.Glob(fsys, "*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		locale := path.Dir(file)
+		eventType := strings.TrimSuffix(path.Base(file), ".tmpl")
+
+		tmpl, err := template.New(eventType).Funcs(funcs).Option("missingkey=error").ParseFS(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		for _, name := range []string{"subject", "body"} {
+			if tmpl.Lookup(name) == nil {
+				return nil, fmt.Errorf("%s does not define %q", file, name)
+			}
+		}
+
+		if c.templates[locale] == nil {
+			c.templates[locale] = make(map[string]*template.Template)
+		}
+		c.templates[locale][eventType] = tmpl
+	}
+
+	if c.templates[DefaultLocale] == nil {
+		return nil, fmt.Errorf("no templates for default locale %q", DefaultLocale)
+	}
+	return c, nil
+}
+
+// Locales returns the catalog's locales in order.
+func (c *Catalog) Locales() []string {
+	locales := make([]string, 0, len(c.templates))
+	for locale := range c.templates {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// HasLocale reports whether the catalog has templates in locale.
+func (c *Catalog) HasLocale(locale string) bool {
+	return c.templates[locale] != nil
+}
+
+// Render renders the message for eventType in locale, falling back to the
+// default locale when locale has no template for it. It returns
+// ErrNoTemplate if no locale has one.
+func (c *Catalog) Render(locale, eventType string, data Data) (*Rendered, error) {
+	tmpl := c.templates[locale][eventType]
+	if tmpl == nil {
+		locale = DefaultLocale
+		tmpl = c.templates[locale][eventType]
+	}
+	if tmpl == nil {
+		return nil, ErrNoTemplate
+	}
+
+	subject, err := execute(tmpl, "subject", data)
+	if err != nil {
+		return nil, err
+	}
+	body, err := execute(tmpl, "body", data)
+	if err != nil {
+		return nil, err
+	}
+	return &Rendered{Locale: locale, Subject: subject, Body: body}, nil
+}
+
+func execute(tmpl *template.Template, name string, data Data) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("render %s %s: %w", tmpl.Name(), name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,98 @@
+package templates
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	catalog, err := Default()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"en", "ur"}, catalog.Locales())
+
+	// Every locale has the same events
+	for _, locale := range catalog.Locales() {
+		assert.Len(t, catalog.templates[locale], len(catalog.templates[DefaultLocale]), locale)
+	}
+}
+
+func TestRender(t *testing.T) {
+	catalog, err := Default()
+	require.NoError(t, err)
+
+	pickup := time.Date(2026, 10, 20, 13, 30, 0, 0, time.FixedZone("PKT", 5*3600))
+	rendered, err := catalog.Render("en", "BookingScheduled", Data{BookingID: 7, PickupAt: &pickup, PromoCode: "WELCOME20"})
+	require.NoError(t, err)
+	assert.Equal(t, &Rendered{
+		Locale:  "en",
+		Subject: "Your ride is scheduled",
+		Body:    "Booking #7 is scheduled for pickup at 20 Oct 2026 08:30 UTC. Promo code WELCOME20 was applied.",
+	}, rendered)
+
+	rendered, err = catalog.Render("ur", "BookingCreated", Data{BookingID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "ur", rendered.Locale)
+	assert.Equal(t, "بکنگ #7 کی تصدیق ہو گئی ہے۔", rendered.Body)
+
+	// Unknown locales fall back to the default
+	rendered, err = catalog.Render("fr", "BookingCompleted", Data{BookingID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "en", rendered.Locale)
+	assert.Equal(t, "Booking #7 is complete. Your receipt is ready.", rendered.Body)
+
+	_, err = catalog.Render("en", "BookingRideUpdated", Data{BookingID: 7})
+	assert.ErrorIs(t, err, ErrNoTemplate)
+}
+
+func TestRender_FallsBackPerEvent(t *testing.T) {
+	catalog, err := Load(fstest.MapFS{
+		"en/BookingCreated.tmpl":   {Data: []byte(`{{define "subject"}}Booked{{end}}{{define "body"}}#{{.BookingID}}{{end}}`)},
+		"en/BookingCancelled.tmpl": {Data: []byte(`{{define "subject"}}Cancelled{{end}}{{define "body"}}#{{.BookingID}}{{end}}`)},
+		"de/BookingCreated.tmpl":   {Data: []byte(`{{define "subject"}}Gebucht{{end}}{{define "body"}}Nr. {{.BookingID}}{{end}}`)},
+	})
+	require.NoError(t, err)
+
+	rendered, err := catalog.Render("de", "BookingCreated", Data{BookingID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, &Rendered{Locale: "de", Subject: "Gebucht", Body: "Nr. 7"}, rendered)
+
+	rendered, err = catalog.Render("de", "BookingCancelled", Data{BookingID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, &Rendered{Locale: "en", Subject: "Cancelled", Body: "#7"}, rendered)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  string
+	}{
+		{
+			name:  "no default locale",
+			files: fstest.MapFS{"ur/BookingCreated.tmpl": {Data: []byte(`{{define "subject"}}{{end}}{{define "body"}}{{end}}`)}},
+			want:  `no templates for default locale "en"`,
+		},
+		{
+			name:  "missing body",
+			files: fstest.MapFS{"en/BookingCreated.tmpl": {Data: []byte(`{{define "subject"}}Booked{{end}}`)}},
+			want:  `en/BookingCreated.tmpl does not define "body"`,
+		},
+		{
+			name:  "syntax error",
+			files: fstest.MapFS{"en/BookingCreated.tmpl": {Data: []byte(`{{define "subject"}}{{.BookingID{{end}}`)}},
+			want:  "parse en/BookingCreated.tmpl",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.files)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// Resolver looks up a host's addresses. net.DefaultResolver is one.
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// CheckURL returns an error unless rawURL is an absolute http or https URL
+// whose host resolves only to public addresses, so that a webhook cannot be
+// pointed at the services and metadata endpoints on our own network.
+func CheckURL(ctx context.Context, resolver Resolver, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL %q must be an absolute http or https URL", rawURL)
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		return checkIP(ip)
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("%s has no addresses", host)
+	}
+	for _, addr := range addrs {
+		if err := checkIP(addr.IP); err != nil {
+			return fmt.Errorf("%s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// checkIP returns an error if ip is loopback, private, link-local,
+// unspecified or multicast.
+func checkIP(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("%s is not a public address", ip)
+	}
+	return nil
+}
+
+// checkDial refuses connections to addresses CheckURL would reject. The
+// dialer calls it with the address it resolved, so a host that has since
+// been pointed at our network, or a redirect to one, is refused too.
+func checkDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("dial %s: not an IP address", address)
+	}
+	if err := checkIP(ip); err != nil {
+		return fmt.Errorf("dial %s: %w", address, err)
+	}
+	return nil
+}
+
+// newClient returns the client requests are sent with by default. It dials
+// only public addresses, and never through a proxy, whose address would be
+// the one checked.
+func newClient() *http.Client {
+	dialer := &net.Dialer{Timeout: requestTimeout, Control: checkDial}
+	return &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: requestTimeout,
+			IdleConnTimeout:     90 * time.Second,
+			MaxIdleConns:        100,
+		},
+	}
+}
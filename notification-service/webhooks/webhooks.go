@@ -0,0 +1,328 @@
+// Package webhooks posts booking events to partners' HTTP endpoints. Each
+// request is signed with the webhook's secret, so partners can check it came
+// from us and was not replayed.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	bookingrepo "booking-service/repository"
+	"notification-service/repository"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+// Request headers.
+const (
+	// SignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>", the
+	// HMAC being of "<unix seconds>.<body>" keyed with the webhook's secret.
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// EventTypes are the booking events a webhook can subscribe to.
+var EventTypes = []string{
+	bookingrepo.EventBookingCreated,
+	bookingrepo.EventBookingScheduled,
+	bookingrepo.EventBookingActivated,
+	bookingrepo.EventBookingDriverAssigned,
+	bookingrepo.EventBookingRideUpdated,
+	bookingrepo.EventBookingCancelled,
+	bookingrepo.EventBookingCompleted,
+	bookingrepo.EventBookingDisputed,
+}
+
+// queueGroup shares booking events among notification-service replicas, so
+// each event is queued for delivery once.
+const queueGroup = "notification-service.webhooks"
+
+const (
+	defaultMaxAttempts = 8
+	defaultBackoff     = 30 * time.Second
+	// maxBackoff caps the wait before a retry, however many attempts there
+	// have been.
+	maxBackoff     = 24 * time.Hour
+	requestTimeout = 10 * time.Second
+)
+
+// batchSize caps how many deliveries one repository call claims.
+const batchSize = 20
+
+// claimLease is how long a claimed delivery is left to its sender before it
+// is due again, in case the sender stopped mid-request. Deliveries are sent
+// one at a time, so it leaves room for every request of a batch to time out.
+const claimLease = 2 * batchSize * requestTimeout
+
+// Payload is the JSON body posted for an event.
+type Payload struct {
+	EventID    int64                    `json:"event_id"`
+	EventType  string                   `json:"event_type"`
+	OccurredAt time.Time                `json:"occurred_at"`
+	PartnerID  string                   `json:"partner_id"`
+	Booking    bookingrepo.BookingEvent `json:"booking"`
+}
+
+// NewSecret returns a random signing secret for a new webhook.
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+// Sign returns the SignatureHeader value for body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Dispatcher queues a delivery to each matching webhook for every booking
+// event, and posts queued deliveries when they fall due. A delivery that
+// fails is retried with exponential backoff; once it has been attempted
+// maxAttempts times it is dead-lettered until it is replayed.
+type Dispatcher struct {
+	webhooks    repository.WebhookRepository
+	deliveries  repository.WebhookDeliveryRepository
+	client      *http.Client
+	logger      *logger.Logger
+	maxAttempts int32
+	backoff     time.Duration
+}
+
+// Option configures optional Dispatcher settings.
+type Option func(*Dispatcher)
+
+// WithRetries sets how many times a delivery is attempted, and the wait
+// before its first retry. Each later retry waits twice as long as the one
+// before.
+func WithRetries(maxAttempts int32, backoff time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = maxAttempts
+		d.backoff = backoff
+	}
+}
+
+// WithHTTPClient sets the client requests are sent with, in place of one that
+// only dials public addresses.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+func NewDispatcher(
+	webhooks repository.WebhookRepository,
+	deliveries repository.WebhookDeliveryRepository,
+	log *logger.Logger,
+	opts ...Option,
+) *Dispatcher {
+	d := &Dispatcher{
+		webhooks:    webhooks,
+		deliveries:  deliveries,
+		client:      newClient(),
+		logger:      log,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Start subscribes the dispatcher to all booking events on broker.
+func (d *Dispatcher) Start(broker outbox.Broker) (outbox.Subscription, error) {
+	return broker.QueueSubscribe(bookingrepo.AggregateBooking+".>", queueGroup, d.handle)
+}
+
+func (d *Dispatcher) handle(ctx context.Context, msg outbox.Message) {
+	var event outbox.Event
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		d.logger.Error("failed to decode booking event", "error", err, "subject", msg.Subject)
+		return
+	}
+
+	queued, err := d.Enqueue(ctx, event)
+	if err != nil {
+		d.logger.Error("failed to queue webhook deliveries", "error", err, "event_id", event.ID, "event_type", event.EventType)
+		return
+	}
+	if queued > 0 {
+		d.logger.Info("webhook deliveries queued", "event_id", event.ID, "event_type", event.EventType, "deliveries", queued)
+	}
+}
+
+// Enqueue queues a delivery of a booking event to each webhook of a partner
+// employing the booking's user whose filter passes the event, and returns
+// how many were queued.
+func (d *Dispatcher) Enqueue(ctx context.Context, event outbox.Event) (int, error) {
+	var booking bookingrepo.BookingEvent
+	if err := json.Unmarshal(event.Payload, &booking); err != nil {
+		return 0, fmt.Errorf("decode booking event payload: %w", err)
+	}
+
+	webhooks, err := d.webhooks.ListForEvent(ctx, booking.UserID, event.EventType)
+	if err != nil {
+		return 0, err
+	}
+	if len(webhooks) == 0 {
+		return 0, nil
+	}
+
+	var deliveries []*repository.WebhookDelivery
+	for _, webhook := range webhooks {
+		body, err := json.Marshal(Payload{
+			EventID:    event.ID,
+			EventType:  event.EventType,
+			OccurredAt: event.OccurredAt,
+			PartnerID:  webhook.PartnerID,
+			Booking:    booking,
+		})
+		if err != nil {
+			return 0, err
+		}
+		deliveries = append(deliveries, &repository.WebhookDelivery{
+			WebhookID: webhook.ID,
+			EventID:   event.ID,
+			EventType: event.EventType,
+			Payload:   body,
+		})
+	}
+	return d.deliveries.Enqueue(ctx, deliveries)
+}
+
+// DispatchDue posts every delivery due by now and returns how many were
+// delivered.
+func (d *Dispatcher) DispatchDue(ctx context.Context, now time.Time) (int, error) {
+	webhooks := make(map[int32]*repository.Webhook)
+	delivered := 0
+	for {
+		deliveries, err := d.deliveries.ClaimDue(ctx, now, claimLease, batchSize)
+		if err != nil {
+			return delivered, err
+		}
+
+		for _, delivery := range deliveries {
+			webhook, ok := webhooks[delivery.WebhookID]
+			if !ok {
+				if webhook, err = d.webhooks.GetByID(ctx, delivery.WebhookID); err != nil {
+					return delivered, err
+				}
+				webhooks[delivery.WebhookID] = webhook
+			}
+
+			ok, err := d.dispatch(ctx, webhook, delivery, now)
+			if err != nil {
+				return delivered, err
+			}
+			if ok {
+				delivered++
+			}
+		}
+
+		if len(deliveries) < batchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// dispatch posts a claimed delivery and records the outcome, reporting
+// whether it was delivered.
+func (d *Dispatcher) dispatch(ctx context.Context, webhook *repository.Webhook, delivery *repository.WebhookDelivery, now time.Time) (bool, error) {
+	statusCode, err := d.post(ctx, webhook, delivery)
+
+	log := d.logger.WithValues("delivery_id", delivery.ID, "webhook_id", webhook.ID, "attempts", delivery.Attempts)
+	switch {
+	case err == nil:
+		metrics.IncrementNotificationCounter("webhook", "sent")
+		log.Info("webhook delivered", "event_type", delivery.EventType, "status_code", statusCode)
+		return true, d.deliveries.MarkDelivered(ctx, delivery.ID, statusCode, now)
+	case delivery.Attempts >= d.maxAttempts:
+		metrics.IncrementNotificationCounter("webhook", "failed")
+		log.Error("webhook delivery dead-lettered", "error", err, "status_code", statusCode)
+		return false, d.deliveries.Kill(ctx, delivery.ID, statusCode, err.Error())
+	default:
+		retryAt := now.Add(d.retryDelay(delivery.Attempts))
+		metrics.IncrementNotificationCounter("webhook", "retried")
+		log.Warn("webhook delivery failed, retrying", "error", err, "status_code", statusCode, "retry_at", retryAt)
+		return false, d.deliveries.Retry(ctx, delivery.ID, statusCode, err.Error(), retryAt)
+	}
+}
+
+// retryDelay returns the wait before retrying a delivery that has been
+// attempted attempts times: the backoff, doubled for each attempt after the
+// first, up to maxBackoff.
+func (d *Dispatcher) retryDelay(attempts int32) time.Duration {
+	delay := d.backoff
+	for i := int32(1); i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// post sends a delivery and returns the response's status code, or 0 if
+// there was no response. Any status other than 2xx is an error. The request
+// is signed as it is sent rather than when its batch was claimed, so its
+// timestamp is fresh however long the batch's earlier requests took.
+func (d *Dispatcher) post(ctx context.Context, webhook *repository.Webhook, delivery *repository.WebhookDelivery) (int32, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, time.Now(), delivery.Payload))
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return int32(res.StatusCode), fmt.Errorf("endpoint returned %s", res.Status)
+	}
+	return int32(res.StatusCode), nil
+}
+
+// Run posts due deliveries every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchDue(ctx, time.Now()); err != nil {
+			d.logger.Error("failed to post webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	bookingrepo "booking-service/repository"
+	"notification-service/repository"
+	"notification-service/repository/mocks"
+
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+var testNow = time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+
+const testSecret = "whsec_test"
+
+// newTestDispatcher returns a dispatcher whose client may dial the loopback
+// endpoints tests listen on.
+func newTestDispatcher(webhooks *mocks.WebhookRepository, deliveries *mocks.WebhookDeliveryRepository) *Dispatcher {
+	return NewDispatcher(webhooks, deliveries, logger.NewLogger("notification-service"), WithRetries(3, time.Minute),
+		WithHTTPClient(&http.Client{Timeout: requestTimeout}))
+}
+
+// fakeResolver resolves the hosts it maps, and no others.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	var addrs []net.IPAddr
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func bookingEvent(t *testing.T, id int64, eventType string, payload bookingrepo.BookingEvent) outbox.Event {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return outbox.Event{
+		ID:            id,
+		AggregateType: bookingrepo.AggregateBooking,
+		AggregateID:   "7",
+		EventType:     eventType,
+		Payload:       data,
+		OccurredAt:    testNow,
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event_id":42}`)
+	signature := Sign(testSecret, testNow, body)
+
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte("1792402200." + string(body)))
+	assert.Equal(t, "t=1792402200,v1="+hex.EncodeToString(mac.Sum(nil)), signature)
+
+	assert.NotEqual(t, signature, Sign("whsec_other", testNow, body))
+	assert.NotEqual(t, signature, Sign(testSecret, testNow.Add(time.Second), body))
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := NewSecret()
+	require.NoError(t, err)
+	second, err := NewSecret()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "whsec_"))
+	assert.Len(t, first, len("whsec_")+64)
+	assert.NotEqual(t, first, second)
+}
+
+func TestEnqueue(t *testing.T) {
+	// Setup
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	d := newTestDispatcher(mockWebhooks, mockDeliveries)
+
+	ctx := context.Background()
+	mockWebhooks.On("ListForEvent", ctx, int32(3), bookingrepo.EventBookingCompleted).Return([]*repository.Webhook{
+		{ID: 1, PartnerID: "acme"},
+		{ID: 2, PartnerID: "globex"},
+	}, nil)
+	var queued []*repository.WebhookDelivery
+	mockDeliveries.On("Enqueue", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(1).([]*repository.WebhookDelivery)
+	}).Return(2, nil)
+
+	// Execute
+	n, err := d.Enqueue(ctx, bookingEvent(t, 42, bookingrepo.EventBookingCompleted,
+		bookingrepo.BookingEvent{BookingID: 7, UserID: 3, Status: bookingrepo.StatusCompleted}))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	require.Len(t, queued, 2)
+	assert.Equal(t, int32(1), queued[0].WebhookID)
+	assert.Equal(t, int64(42), queued[0].EventID)
+	assert.Equal(t, bookingrepo.EventBookingCompleted, queued[0].EventType)
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(queued[1].Payload, &payload))
+	assert.Equal(t, Payload{
+		EventID:    42,
+		EventType:  bookingrepo.EventBookingCompleted,
+		OccurredAt: testNow,
+		PartnerID:  "globex",
+		Booking:    bookingrepo.BookingEvent{BookingID: 7, UserID: 3, Status: bookingrepo.StatusCompleted},
+	}, payload)
+}
+
+func TestEnqueue_NoWebhooks(t *testing.T) {
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	d := newTestDispatcher(mockWebhooks, mockDeliveries)
+
+	ctx := context.Background()
+	mockWebhooks.On("ListForEvent", ctx, int32(3), bookingrepo.EventBookingCreated).Return(nil, nil)
+
+	n, err := d.Enqueue(ctx, bookingEvent(t, 42, bookingrepo.EventBookingCreated, bookingrepo.BookingEvent{BookingID: 7, UserID: 3}))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	mockDeliveries.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+}
+
+func TestStart_QueuesBrokerEvents(t *testing.T) {
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	d := newTestDispatcher(mockWebhooks, mockDeliveries)
+
+	broker := outbox.NewInProcessBroker()
+	_, err := d.Start(broker)
+	require.NoError(t, err)
+
+	mockWebhooks.On("ListForEvent", mock.Anything, int32(3), bookingrepo.EventBookingCancelled).
+		Return([]*repository.Webhook{{ID: 1, PartnerID: "acme"}}, nil)
+	mockDeliveries.On("Enqueue", mock.Anything, mock.MatchedBy(func(d []*repository.WebhookDelivery) bool {
+		return len(d) == 1 && d[0].EventID == 42
+	})).Return(1, nil).Once()
+
+	event := bookingEvent(t, 42, bookingrepo.EventBookingCancelled, bookingrepo.BookingEvent{BookingID: 7, UserID: 3})
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	require.NoError(t, broker.Publish(context.Background(), event.Subject(), data))
+
+	mockDeliveries.AssertExpectations(t)
+}
+
+func TestDispatchDue(t *testing.T) {
+	// Setup
+	type request struct {
+		header http.Header
+		body   string
+	}
+	var requests []request
+	failures := map[string]int{"/flaky": http.StatusServiceUnavailable, "/gone": http.StatusGone}
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{header: r.Header, body: string(body)})
+		if code, ok := failures[r.URL.Path]; ok {
+			w.WriteHeader(code)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer endpoint.Close()
+
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	d := newTestDispatcher(mockWebhooks, mockDeliveries)
+
+	ctx := context.Background()
+	mockWebhooks.On("GetByID", ctx, int32(1)).Return(&repository.Webhook{ID: 1, URL: endpoint.URL + "/ok", Secret: testSecret}, nil).Once()
+	mockWebhooks.On("GetByID", ctx, int32(2)).Return(&repository.Webhook{ID: 2, URL: endpoint.URL + "/flaky", Secret: testSecret}, nil).Once()
+	mockWebhooks.On("GetByID", ctx, int32(3)).Return(&repository.Webhook{ID: 3, URL: endpoint.URL + "/gone", Secret: testSecret}, nil).Once()
+	mockDeliveries.On("ClaimDue", ctx, testNow, claimLease, batchSize).Return([]*repository.WebhookDelivery{
+		{ID: 10, WebhookID: 1, EventType: "BookingCreated", Payload: []byte(`{"event_id":41}`), Attempts: 1},
+		{ID: 11, WebhookID: 1, EventType: "BookingCompleted", Payload: []byte(`{"event_id":42}`), Attempts: 1},
+		{ID: 12, WebhookID: 2, EventType: "BookingCreated", Payload: []byte(`{"event_id":41}`), Attempts: 2},
+		{ID: 13, WebhookID: 3, EventType: "BookingCreated", Payload: []byte(`{"event_id":41}`), Attempts: 3},
+	}, nil).Once()
+	mockDeliveries.On("MarkDelivered", ctx, int64(10), int32(204), testNow).Return(nil).Once()
+	mockDeliveries.On("MarkDelivered", ctx, int64(11), int32(204), testNow).Return(nil).Once()
+	// Second attempt fails: retried after twice the backoff
+	mockDeliveries.On("Retry", ctx, int64(12), int32(503), "endpoint returned 503 Service Unavailable", testNow.Add(2*time.Minute)).Return(nil).Once()
+	// Last attempt fails: dead-lettered
+	mockDeliveries.On("Kill", ctx, int64(13), int32(410), "endpoint returned 410 Gone").Return(nil).Once()
+
+	// Execute
+	delivered, err := d.DispatchDue(ctx, testNow)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, delivered)
+	mockDeliveries.AssertExpectations(t)
+
+	require.Len(t, requests, 4)
+	first := requests[0]
+	assert.Equal(t, `{"event_id":41}`, first.body)
+	assert.Equal(t, "application/json", first.header.Get("Content-Type"))
+	assert.Equal(t, "BookingCreated", first.header.Get(EventHeader))
+	assert.Equal(t, "10", first.header.Get(DeliveryHeader))
+	// Signed as it was sent, not when its batch was claimed
+	signature := first.header.Get(SignatureHeader)
+	timestamp, _, ok := strings.Cut(strings.TrimPrefix(signature, "t="), ",")
+	require.True(t, ok)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(unix, 0), time.Minute)
+	assert.Equal(t, Sign(testSecret, time.Unix(unix, 0), []byte(first.body)), signature)
+}
+
+func TestDispatchDue_PrivateAddress(t *testing.T) {
+	// Setup: the endpoint listens on loopback, which the default client
+	// refuses to dial
+	requested := false
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer endpoint.Close()
+
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	d := NewDispatcher(mockWebhooks, mockDeliveries, logger.NewLogger("notification-service"), WithRetries(3, time.Minute))
+
+	ctx := context.Background()
+	mockWebhooks.On("GetByID", ctx, int32(1)).Return(&repository.Webhook{ID: 1, URL: endpoint.URL, Secret: testSecret}, nil)
+	mockDeliveries.On("ClaimDue", ctx, testNow, claimLease, batchSize).Return([]*repository.WebhookDelivery{
+		{ID: 10, WebhookID: 1, Payload: []byte(`{}`), Attempts: 1},
+	}, nil).Once()
+	mockDeliveries.On("Retry", ctx, int64(10), int32(0), mock.MatchedBy(func(reason string) bool {
+		return strings.Contains(reason, "127.0.0.1 is not a public address")
+	}), testNow.Add(time.Minute)).Return(nil).Once()
+
+	// Execute
+	delivered, err := d.DispatchDue(ctx, testNow)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Zero(t, delivered)
+	assert.False(t, requested)
+	mockDeliveries.AssertExpectations(t)
+}
+
+func TestRetryDelay(t *testing.T) {
+	d := NewDispatcher(nil, nil, logger.NewLogger("notification-service"), WithRetries(100, 30*time.Second))
+
+	assert.Equal(t, 30*time.Second, d.retryDelay(1))
+	assert.Equal(t, time.Minute, d.retryDelay(2))
+	assert.Equal(t, 4*time.Minute, d.retryDelay(4))
+	// Capped rather than overflowing
+	assert.Equal(t, maxBackoff, d.retryDelay(40))
+	assert.Equal(t, maxBackoff, d.retryDelay(100))
+}
+
+func TestClaimLease(t *testing.T) {
+	// A claimed batch must not fall due again while its requests are sent
+	assert.Greater(t, claimLease, batchSize*requestTimeout)
+}
+
+func TestCheckURL(t *testing.T) {
+	resolver := fakeResolver{
+		"acme.example.com":     {"93.184.215.14"},
+		"internal.example.com": {"93.184.215.14", "10.0.0.5"},
+	}
+
+	tests := []struct {
+		name  string
+		url   string
+		valid bool
+	}{
+		{"public host", "https://acme.example.com/hooks", true},
+		{"public IP", "http://93.184.215.14:8080/hooks", true},
+		{"relative URL", "/hooks", false},
+		{"ftp URL", "ftp://acme.example.com/hooks", false},
+		{"unresolvable host", "https://nowhere.example.com/hooks", false},
+		{"host with a private address", "https://internal.example.com/hooks", false},
+		{"loopback", "http://127.0.0.1:2117/admin/build", false},
+		{"IPv6 loopback", "http://[::1]/hooks", false},
+		{"private", "http://192.168.1.10/hooks", false},
+		{"link-local metadata", "http://169.254.169.254/latest/meta-data", false},
+		{"unspecified", "http://0.0.0.0/hooks", false},
+		{"IPv4-mapped loopback", "http://[::ffff:127.0.0.1]/hooks", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckURL(context.Background(), resolver, tt.url)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestDispatchDue_Unreachable(t *testing.T) {
+	endpoint := httptest.NewServer(http.NotFoundHandler())
+	url := endpoint.URL
+	endpoint.Close()
+
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	d := newTestDispatcher(mockWebhooks, mockDeliveries)
+
+	ctx := context.Background()
+	mockWebhooks.On("GetByID", ctx, int32(1)).Return(&repository.Webhook{ID: 1, URL: url, Secret: testSecret}, nil)
+	mockDeliveries.On("ClaimDue", ctx, testNow, claimLease, batchSize).Return([]*repository.WebhookDelivery{
+		{ID: 10, WebhookID: 1, Payload: []byte(`{}`), Attempts: 1},
+	}, nil).Once()
+	mockDeliveries.On("Retry", ctx, int64(10), int32(0), mock.AnythingOfType("string"), testNow.Add(time.Minute)).Return(nil).Once()
+
+	delivered, err := d.DispatchDue(ctx, testNow)
+	require.NoError(t, err)
+	assert.Zero(t, delivered)
+	mockDeliveries.AssertExpectations(t)
+}
+
+func TestDispatchDue_RepositoryError(t *testing.T) {
+	mockWebhooks := new(mocks.WebhookRepository)
+	mockDeliveries := new(mocks.WebhookDeliveryRepository)
+	d := newTestDispatcher(mockWebhooks, mockDeliveries)
+
+	ctx := context.Background()
+	mockDeliveries.On("ClaimDue", ctx, testNow, claimLease, batchSize).Return(nil, errors.New("connection refused"))
+
+	_, err := d.DispatchDue(ctx, testNow)
+	assert.EqualError(t, err, "connection refused")
+}
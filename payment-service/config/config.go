@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"log"
+	"strconv"
+	"github.com/joho/godotenv"
+
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+)
+
+type Config struct {
+	DBUrl string
+
+	// Failure rates of the fake payment provider, from 0 to 1.
+	FakeDeclineRate      float64
+	FakeTimeoutRate      float64
+	FakeLostResponseRate float64
+
+	// CommissionBps is the platform's cut of each fare, in basis points.
+	CommissionBps int64
+
+	// DBPool holds the DB_* connection pool settings.
+	DBPool database.PoolConfig
+	// Log holds the LOG_* logging settings.
+	Log logger.Settings
+	// AdminToken is the bearer token of the admin server's debug endpoints,
+	// which are disabled while it is empty.
+	AdminToken string
+}
+
+func Load() Config {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	dbUser := os.Getenv("DB_USER")
+	dbPass := os.Getenv("DB_PASSWORD")
+	dbName := os.Getenv("DB_NAME")
+	dbHost := os.Getenv("DB_HOST")
+	dbPort := os.Getenv("DB_PORT")
+
+	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		dbUser, dbPass, dbHost, dbPort, dbName)
+
+	dbPool, err := database.PoolConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logSettings, err := logger.SettingsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return Config{
+		DBUrl:                dbUrl,
+		FakeDeclineRate:      getRate("FAKE_PROVIDER_DECLINE_RATE"),
+		FakeTimeoutRate:      getRate("FAKE_PROVIDER_TIMEOUT_RATE"),
+		FakeLostResponseRate: getRate("FAKE_PROVIDER_LOST_RESPONSE_RATE"),
+		CommissionBps:        getCommissionBps(),
+
+		DBPool:     dbPool,
+		Log:        logSettings,
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	}
+}
+
+func getRate(key string) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		log.Fatalf("Invalid %s %q: want a rate between 0 and 1", key, value)
+	}
+	return rate
+}
+
+func getCommissionBps() int64 {
+	value := os.Getenv("COMMISSION_BPS")
+	if value == "" {
+		return 2000
+	}
+	bps, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || bps < 0 || bps > 10000 {
+		log.Fatalf("Invalid COMMISSION_BPS %q: want basis points between 0 and 10000", value)
+	}
+	return bps
+}
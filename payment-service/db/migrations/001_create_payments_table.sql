@@ -0,0 +1,34 @@
+CREATE TABLE payments (
+  payment_id SERIAL PRIMARY KEY,
+  -- One payment per booking; provider calls are keyed by the booking ID
+  booking_id INTEGER NOT NULL UNIQUE,
+  user_id INTEGER NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+  currency CHAR(3) NOT NULL,
+  amount_minor BIGINT NOT NULL,
+  captured_minor BIGINT NOT NULL DEFAULT 0,
+  refunded_minor BIGINT NOT NULL DEFAULT 0,
+  refund_count INTEGER NOT NULL DEFAULT 0,
+  -- Provider's reference for the authorization
+  provider_ref TEXT NOT NULL DEFAULT '',
+  decline_reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Every provider operation applied to a payment, in order. The idempotency
+-- key is the one sent to the provider, so an operation is recorded once even
+-- when the call was retried.
+CREATE TABLE payment_ledger (
+  entry_id BIGSERIAL PRIMARY KEY,
+  payment_id INTEGER NOT NULL REFERENCES payments (payment_id),
+  entry_type VARCHAR(16) NOT NULL,
+  currency CHAR(3) NOT NULL,
+  amount_minor BIGINT NOT NULL,
+  provider_ref TEXT NOT NULL DEFAULT '',
+  idempotency_key TEXT NOT NULL UNIQUE,
+  reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX payment_ledger_payment_idx ON payment_ledger (payment_id, entry_id);
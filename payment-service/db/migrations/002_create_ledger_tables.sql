@@ -0,0 +1,46 @@
+-- Double-entry ledger of rider wallets, driver earnings and platform accounts.
+-- An account's balance is only changed together with the postings that
+-- explain it, in the same transaction.
+CREATE TABLE ledger_accounts (
+  code TEXT PRIMARY KEY,
+  account_type VARCHAR(16) NOT NULL,
+  currency CHAR(3) NOT NULL,
+  -- Balance on the account's normal side: debit for assets, credit otherwise
+  balance_minor BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE journal_entries (
+  entry_id BIGSERIAL PRIMARY KEY,
+  idempotency_key TEXT NOT NULL UNIQUE,
+  kind VARCHAR(16) NOT NULL,
+  memo TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Debits are positive and credits negative, so an entry's postings sum to zero
+CREATE TABLE journal_postings (
+  posting_id BIGSERIAL PRIMARY KEY,
+  entry_id BIGINT NOT NULL REFERENCES journal_entries (entry_id),
+  account_code TEXT NOT NULL REFERENCES ledger_accounts (code),
+  currency CHAR(3) NOT NULL,
+  amount_minor BIGINT NOT NULL CHECK (amount_minor <> 0)
+);
+
+CREATE INDEX journal_postings_entry_idx ON journal_postings (entry_id);
+CREATE INDEX journal_postings_account_idx ON journal_postings (account_code);
+
+-- Journal entries are immutable: mistakes are corrected by posting another
+-- entry
+CREATE FUNCTION reject_journal_change() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'journal entries are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER journal_entries_immutable BEFORE UPDATE OR DELETE ON journal_entries
+  FOR EACH ROW EXECUTE FUNCTION reject_journal_change();
+
+CREATE TRIGGER journal_postings_immutable BEFORE UPDATE OR DELETE ON journal_postings
+  FOR EACH ROW EXECUTE FUNCTION reject_journal_change();
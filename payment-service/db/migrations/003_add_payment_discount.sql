@@ -0,0 +1,3 @@
+-- Part of the fare the platform pays for, such as a promotion. The driver's
+-- earnings are a share of the amount captured plus the discount.
+ALTER TABLE payments ADD COLUMN discount_minor BIGINT NOT NULL DEFAULT 0;
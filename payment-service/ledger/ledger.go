@@ -0,0 +1,277 @@
+// Package ledger keeps double-entry books of the money held for riders and
+// drivers: rider wallets, driver earnings and the platform's own accounts.
+// Every journal entry debits and credits accounts by the same total, so the
+// books always balance and no balance changes without an entry explaining it.
+package ledger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+// Account types. Assets have a debit normal balance, the others a credit one.
+const (
+	TypeAsset     = "ASSET"
+	TypeLiability = "LIABILITY"
+	TypeRevenue   = "REVENUE"
+)
+
+// Platform accounts.
+const (
+	// ProviderClearing is money held for the platform by the card provider.
+	ProviderClearing = "platform:provider_clearing"
+	// PlatformRevenue is the platform's commission and wallet spending, less
+	// the discounts it pays for.
+	PlatformRevenue = "platform:revenue"
+)
+
+// Journal entry kinds.
+const (
+	KindTopUp   = "TOP_UP"
+	KindDebit   = "DEBIT"
+	KindEarning = "EARNING"
+	KindRefund  = "REFUND"
+)
+
+// UserWallet is the code of a rider's wallet, which the platform owes them.
+func UserWallet(userID int32) string {
+	return fmt.Sprintf("user:%d:wallet", userID)
+}
+
+// DriverEarnings is the code of a driver's earnings awaiting payout.
+func DriverEarnings(driverID int32) string {
+	return fmt.Sprintf("driver:%d:earnings", driverID)
+}
+
+// EarningKey is the idempotency key of a booking's earning entry.
+func EarningKey(bookingID int32) string {
+	return fmt.Sprintf("booking-%d-earning", bookingID)
+}
+
+// RefundKey is the idempotency key of a booking's nth refund entry, matching
+// the nth refund sent to the provider.
+func RefundKey(bookingID, n int32) string {
+	return fmt.Sprintf("booking-%d-refund-%d", bookingID, n)
+}
+
+// TopUpKey and DebitKey scope a client's idempotency key to the wallet.
+func TopUpKey(userID int32, key string) string {
+	return fmt.Sprintf("wallet-%d-topup-%s", userID, key)
+}
+
+func DebitKey(userID int32, key string) string {
+	return fmt.Sprintf("wallet-%d-debit-%s", userID, key)
+}
+
+// AccountType returns the type of the account with code, or an error if code
+// is not a known kind of account.
+func AccountType(code string) (string, error) {
+	switch {
+	case code == ProviderClearing:
+		return TypeAsset, nil
+	case code == PlatformRevenue:
+		return TypeRevenue, nil
+	case strings.HasPrefix(code, "user:") && strings.HasSuffix(code, ":wallet"),
+		strings.HasPrefix(code, "driver:") && strings.HasSuffix(code, ":earnings"):
+		return TypeLiability, nil
+	}
+	return "", fmt.Errorf("unknown account %q", code)
+}
+
+// Account is a ledger account and its balance on its normal side, e.g. a
+// wallet's balance is what the platform owes the rider.
+type Account struct {
+	Code    string
+	Type    string
+	Balance money.Money
+}
+
+// NewAccount returns an empty account with code in currency.
+func NewAccount(code, currency string) (*Account, error) {
+	accountType, err := AccountType(code)
+	if err != nil {
+		return nil, err
+	}
+	return &Account{Code: code, Type: accountType, Balance: money.Money{Currency: currency}}, nil
+}
+
+// Apply adds a posting to the account's balance, or returns an error if the
+// posting is in another currency or would overdraw a rider's wallet. Driver
+// earnings may go negative when a refund claws back an earning already paid
+// out.
+func (a *Account) Apply(p Posting) error {
+	change := p.Amount
+	if a.Type != TypeAsset {
+		change.Minor = -change.Minor
+	}
+	balance, err := a.Balance.Add(change)
+	if err != nil {
+		return err
+	}
+	if balance.IsNegative() && strings.HasSuffix(a.Code, ":wallet") {
+		return fmt.Errorf("insufficient funds")
+	}
+	a.Balance = balance
+	return nil
+}
+
+// Posting debits an account by a positive amount, or credits it by a negative
+// one.
+type Posting struct {
+	Account string
+	Amount  money.Money
+}
+
+func Debit(account string, amount money.Money) Posting {
+	return Posting{Account: account, Amount: amount}
+}
+
+func Credit(account string, amount money.Money) Posting {
+	amount.Minor = -amount.Minor
+	return Posting{Account: account, Amount: amount}
+}
+
+// Entry is an immutable journal entry. Posting an entry whose idempotency key
+// is already posted has no effect.
+type Entry struct {
+	ID             int64
+	IdempotencyKey string
+	Kind           string
+	Memo           string
+	Postings       []Posting
+	CreatedAt      time.Time
+}
+
+// Validate returns an error unless the entry balances: it has at least two
+// postings, all in one currency and none zero, and they sum to zero.
+func (e Entry) Validate() error {
+	if e.IdempotencyKey == "" {
+		return fmt.Errorf("entry has no idempotency key")
+	}
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("entry needs at least two postings")
+	}
+	sum := money.Money{Currency: e.Postings[0].Amount.Currency}
+	for _, p := range e.Postings {
+		if _, err := AccountType(p.Account); err != nil {
+			return err
+		}
+		if p.Amount.IsZero() {
+			return fmt.Errorf("entry has a zero posting to %s", p.Account)
+		}
+		var err error
+		if sum, err = sum.Add(p.Amount); err != nil {
+			return err
+		}
+	}
+	if !sum.IsZero() {
+		return fmt.Errorf("entry does not balance: off by %s", sum)
+	}
+	return nil
+}
+
+// TopUp moves money charged to a rider's card into their wallet.
+func TopUp(userID int32, amount money.Money, key string) Entry {
+	return Entry{
+		IdempotencyKey: TopUpKey(userID, key),
+		Kind:           KindTopUp,
+		Postings: []Posting{
+			Debit(ProviderClearing, amount),
+			Credit(UserWallet(userID), amount),
+		},
+	}
+}
+
+// WalletDebit spends money from a rider's wallet.
+func WalletDebit(userID int32, amount money.Money, key, memo string) Entry {
+	return Entry{
+		IdempotencyKey: DebitKey(userID, key),
+		Kind:           KindDebit,
+		Memo:           memo,
+		Postings: []Posting{
+			Debit(UserWallet(userID), amount),
+			Credit(PlatformRevenue, amount),
+		},
+	}
+}
+
+// Earning splits a booking's fare between the driver and the platform's
+// commission, given in basis points of the fare. The fare is what was
+// captured from the rider plus the discount the platform paid towards it, so
+// a promotion comes out of the platform's revenue rather than the driver's
+// share. A booking without a driver, driverID 0, is all revenue. The entry has
+// no postings if there is nothing to split.
+func Earning(bookingID, driverID int32, captured, discount money.Money, commissionBps int64) (Entry, error) {
+	entry := Entry{
+		IdempotencyKey: EarningKey(bookingID),
+		Kind:           KindEarning,
+		Memo:           fmt.Sprintf("booking %d", bookingID),
+	}
+	if !captured.IsZero() {
+		entry.Postings = append(entry.Postings, Debit(ProviderClearing, captured))
+	}
+
+	fare, err := captured.Add(discount)
+	if err != nil {
+		return Entry{}, err
+	}
+	commission := fare
+	if driverID != 0 {
+		if commission, err = fare.MulRatio(commissionBps, 10000, money.HalfUp); err != nil {
+			return Entry{}, err
+		}
+		share, err := fare.Sub(commission)
+		if err != nil {
+			return Entry{}, err
+		}
+		if !share.IsZero() {
+			entry.Postings = append(entry.Postings, Credit(DriverEarnings(driverID), share))
+		}
+	}
+	// A discount larger than the commission debits revenue
+	revenue, err := commission.Sub(discount)
+	if err != nil {
+		return Entry{}, err
+	}
+	if !revenue.IsZero() {
+		entry.Postings = append(entry.Postings, Credit(PlatformRevenue, revenue))
+	}
+	return entry, nil
+}
+
+// Refund reverses amount of a booking's earning entry as its nth refund,
+// taking it back from each account the captured amount was split between in
+// proportion to its share. Rounding leftovers come out of the last account,
+// which is the platform's commission when it has one.
+func Refund(bookingID, n int32, earning Entry, amount money.Money, reason string) (Entry, error) {
+	captured := earning.Postings[0].Amount
+	entry := Entry{
+		IdempotencyKey: RefundKey(bookingID, n),
+		Kind:           KindRefund,
+		Memo:           reason,
+		Postings:       []Posting{Credit(earning.Postings[0].Account, amount)},
+	}
+
+	remaining := amount
+	credits := earning.Postings[1:]
+	for i, p := range credits {
+		share := remaining
+		if i < len(credits)-1 {
+			var err error
+			if share, err = amount.MulRatio(-p.Amount.Minor, captured.Minor, money.Down); err != nil {
+				return Entry{}, err
+			}
+		}
+		var err error
+		if remaining, err = remaining.Sub(share); err != nil {
+			return Entry{}, err
+		}
+		if !share.IsZero() {
+			entry.Postings = append(entry.Postings, Debit(p.Account, share))
+		}
+	}
+	return entry, nil
+}
@@ -0,0 +1,180 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+func rupees(minor int64) money.Money {
+	return money.Money{Currency: money.PKR, Minor: minor}
+}
+
+func TestEntry_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		postings []Posting
+		wantErr  bool
+	}{
+		{name: "Balanced", postings: []Posting{Debit(ProviderClearing, rupees(100)), Credit(UserWallet(1), rupees(100))}},
+		{name: "Unbalanced", postings: []Posting{Debit(ProviderClearing, rupees(100)), Credit(UserWallet(1), rupees(90))}, wantErr: true},
+		{name: "Single Posting", postings: []Posting{Debit(ProviderClearing, rupees(0))}, wantErr: true},
+		{name: "Zero Posting", postings: []Posting{
+			Debit(ProviderClearing, rupees(100)), Credit(UserWallet(1), rupees(100)), Credit(PlatformRevenue, rupees(0)),
+		}, wantErr: true},
+		{name: "Mixed Currencies", postings: []Posting{
+			Debit(ProviderClearing, rupees(100)), Credit(UserWallet(1), money.Money{Currency: "USD", Minor: 100}),
+		}, wantErr: true},
+		{name: "Unknown Account", postings: []Posting{Debit("bank", rupees(100)), Credit(UserWallet(1), rupees(100))}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Entry{IdempotencyKey: "key", Postings: tc.postings}.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	entry := TopUp(1, rupees(100), "abc")
+	entry.IdempotencyKey = ""
+	assert.Error(t, entry.Validate(), "entry without an idempotency key")
+}
+
+func TestAccount_Apply(t *testing.T) {
+	wallet, err := NewAccount(UserWallet(1), money.PKR)
+	require.NoError(t, err)
+
+	// Crediting a liability raises its balance, debiting lowers it
+	require.NoError(t, wallet.Apply(Credit(wallet.Code, rupees(500))))
+	require.NoError(t, wallet.Apply(Debit(wallet.Code, rupees(200))))
+	assert.Equal(t, rupees(300), wallet.Balance)
+
+	assert.EqualError(t, wallet.Apply(Debit(wallet.Code, rupees(301))), "insufficient funds")
+	assert.Equal(t, rupees(300), wallet.Balance)
+	assert.ErrorIs(t, wallet.Apply(Debit(wallet.Code, money.Money{Currency: "USD", Minor: 1})), money.ErrCurrencyMismatch)
+
+	// Earnings can be clawed back below zero
+	earnings, err := NewAccount(DriverEarnings(5), money.PKR)
+	require.NoError(t, err)
+	require.NoError(t, earnings.Apply(Debit(earnings.Code, rupees(100))))
+	assert.Equal(t, rupees(-100), earnings.Balance)
+
+	// Debiting an asset raises its balance
+	clearing, err := NewAccount(ProviderClearing, money.PKR)
+	require.NoError(t, err)
+	require.NoError(t, clearing.Apply(Debit(clearing.Code, rupees(100))))
+	assert.Equal(t, rupees(100), clearing.Balance)
+
+	_, err = NewAccount("bank", money.PKR)
+	assert.Error(t, err)
+}
+
+func TestEarning(t *testing.T) {
+	entry, err := Earning(7, 5, rupees(50050), rupees(0), 2000)
+	require.NoError(t, err)
+	require.NoError(t, entry.Validate())
+	assert.Equal(t, "booking-7-earning", entry.IdempotencyKey)
+	assert.Equal(t, []Posting{
+		Debit(ProviderClearing, rupees(50050)),
+		Credit(DriverEarnings(5), rupees(40040)),
+		Credit(PlatformRevenue, rupees(10010)),
+	}, entry.Postings)
+
+	// Without a driver the fare is all revenue
+	entry, err = Earning(7, 0, rupees(50050), rupees(0), 2000)
+	require.NoError(t, err)
+	assert.Equal(t, []Posting{
+		Debit(ProviderClearing, rupees(50050)),
+		Credit(PlatformRevenue, rupees(50050)),
+	}, entry.Postings)
+
+	// Without commission the fare is all the driver's
+	entry, err = Earning(7, 5, rupees(50050), rupees(0), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []Posting{
+		Debit(ProviderClearing, rupees(50050)),
+		Credit(DriverEarnings(5), rupees(50050)),
+	}, entry.Postings)
+}
+
+func TestEarning_Discount(t *testing.T) {
+	// The driver's share is of the whole fare; the platform pays the discount
+	// out of its commission
+	entry, err := Earning(7, 5, rupees(900), rupees(100), 2000)
+	require.NoError(t, err)
+	require.NoError(t, entry.Validate())
+	assert.Equal(t, []Posting{
+		Debit(ProviderClearing, rupees(900)),
+		Credit(DriverEarnings(5), rupees(800)),
+		Credit(PlatformRevenue, rupees(100)),
+	}, entry.Postings)
+
+	// A discount beyond the commission is paid out of revenue
+	entry, err = Earning(7, 5, rupees(700), rupees(300), 2000)
+	require.NoError(t, err)
+	require.NoError(t, entry.Validate())
+	assert.Equal(t, []Posting{
+		Debit(ProviderClearing, rupees(700)),
+		Credit(DriverEarnings(5), rupees(800)),
+		Debit(PlatformRevenue, rupees(100)),
+	}, entry.Postings)
+
+	// A free ride still earns the driver their share
+	entry, err = Earning(7, 5, rupees(0), rupees(1000), 2000)
+	require.NoError(t, err)
+	require.NoError(t, entry.Validate())
+	assert.Equal(t, []Posting{
+		Credit(DriverEarnings(5), rupees(800)),
+		Debit(PlatformRevenue, rupees(800)),
+	}, entry.Postings)
+
+	// Without a driver nor a charge there is nothing to split
+	entry, err = Earning(7, 0, rupees(0), rupees(1000), 2000)
+	require.NoError(t, err)
+	assert.Empty(t, entry.Postings)
+
+	// Refunding what was paid takes back the driver's whole share
+	entry, err = Earning(7, 5, rupees(700), rupees(300), 2000)
+	require.NoError(t, err)
+	refund, err := Refund(7, 1, entry, rupees(700), "dispute")
+	require.NoError(t, err)
+	require.NoError(t, refund.Validate())
+	assert.Equal(t, []Posting{
+		Credit(ProviderClearing, rupees(700)),
+		Debit(DriverEarnings(5), rupees(800)),
+		Credit(PlatformRevenue, rupees(100)),
+	}, refund.Postings)
+}
+
+func TestRefund(t *testing.T) {
+	earning, err := Earning(7, 5, rupees(1000), rupees(0), 2000)
+	require.NoError(t, err)
+
+	// A third of the fare, which does not split evenly
+	refund, err := Refund(7, 1, earning, rupees(333), "detour")
+	require.NoError(t, err)
+	require.NoError(t, refund.Validate())
+	assert.Equal(t, "booking-7-refund-1", refund.IdempotencyKey)
+	assert.Equal(t, "detour", refund.Memo)
+	assert.Equal(t, []Posting{
+		Credit(ProviderClearing, rupees(333)),
+		Debit(DriverEarnings(5), rupees(266)),
+		Debit(PlatformRevenue, rupees(67)),
+	}, refund.Postings)
+
+	// A full refund reverses the earning exactly
+	refund, err = Refund(7, 1, earning, rupees(1000), "dispute")
+	require.NoError(t, err)
+	assert.Equal(t, []Posting{
+		Credit(ProviderClearing, rupees(1000)),
+		Debit(DriverEarnings(5), rupees(800)),
+		Debit(PlatformRevenue, rupees(200)),
+	}, refund.Postings)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	driverpb "driver-service/pb/proto/driver"
+	"payment-service/config"
+	pb "payment-service/pb/proto/payment"
+	"payment-service/provider"
+	"payment-service/repository"
+	"payment-service/server"
+
+	"github.com/hasnain-zafar/go-microservices/common/admin"
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+)
+
+func main() {
+	// Initialize Prometheus metrics
+	metrics.Init()
+	registry := metrics.NewRegistry("payment-service")
+
+	cfg := config.Load()
+	logger.Configure(cfg.Log)
+
+	// Wait for Postgres, which may still be starting
+	db, err := database.Open(context.Background(), "postgres", cfg.DBUrl, cfg.DBPool)
+	if err != nil {
+		log.Fatalf("❌ DB not reachable: %v", err)
+	}
+	defer db.Close()
+	registry.RegisterDBStats(db, "payments_db")
+
+	fmt.Println("✅ Connected to payments_db successfully")
+
+	paymentRepo := repository.NewPostgresPaymentRepository(db)
+	ledgerRepo := repository.NewPostgresLedgerRepository(db)
+
+	// "payment-service check-ledger" checks the ledger's invariants and exits,
+	// so it can run as a scheduled job alongside the service without starting
+	// servers of its own
+	if len(os.Args) > 1 && os.Args[1] == "check-ledger" {
+		code := checkLedger(ledgerRepo)
+		db.Close()
+		os.Exit(code)
+	}
+
+	// Serve metrics and, with ADMIN_TOKEN set, the debug endpoints
+	conns := admin.NewConnTracker()
+	adminServer := admin.NewServer("payment-service", cfg.AdminToken, registry,
+		admin.WithConfig(cfg),
+		admin.WithDB(db),
+		admin.WithConnections(conns),
+	)
+	go startAdminServer(adminServer, "payment-service", 2116)
+
+	// Charge cards through the local fake provider until a real one is
+	// configured
+	paymentProvider := provider.NewFake(provider.FakeRates{
+		Decline:      cfg.FakeDeclineRate,
+		Timeout:      cfg.FakeTimeoutRate,
+		LostResponse: cfg.FakeLostResponseRate,
+	}, time.Now().UnixNano())
+
+	// Earnings are only credited to the driver driver-service has assigned
+	driverConn, err := grpc.Dial("driver-service:50054", grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(logger.UnaryClientInterceptor(), audit.UnaryClientInterceptor("payment-service")))
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to driver-service: %v", err)
+	}
+	defer driverConn.Close()
+	driverClient := driverpb.NewDriverServiceClient(driverConn)
+
+	paymentServer := server.NewPaymentServer(paymentRepo, ledgerRepo, paymentProvider, driverClient,
+		server.WithCommission(cfg.CommissionBps),
+		server.WithMetrics(registry),
+	)
+
+	listener, err := net.Listen("tcp", ":50055")
+	if err != nil {
+		log.Fatalf("❌ Failed to listen on port 50055: %v", err)
+	}
+
+	// Tag every call with a request ID for its log lines
+	requestLogger := logger.NewLogger("payment-service")
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(conns),
+		grpc.ChainUnaryInterceptor(logger.UnaryServerInterceptor(requestLogger)),
+		grpc.ChainStreamInterceptor(logger.StreamServerInterceptor(requestLogger)),
+	)
+	pb.RegisterPaymentServiceServer(grpcServer, paymentServer)
+
+	reflection.Register(grpcServer)
+
+	fmt.Println("🚀 PaymentService gRPC server listening on :50055")
+	if err := grpcServer.Serve(listener); err != nil {
+		log.Fatalf("❌ Failed to serve: %v", err)
+	}
+}
+
+// checkLedger prints every ledger invariant violation and returns the exit
+// status: 0 if the books are sound, 1 if not and 2 if they could not be read.
+func checkLedger(ledgerRepo repository.LedgerRepository) int {
+	violations, err := ledgerRepo.CheckInvariants(context.Background())
+	if err != nil {
+		fmt.Printf("❌ Failed to check ledger: %v\n", err)
+		return 2
+	}
+	for _, violation := range violations {
+		fmt.Printf("❌ %s\n", violation)
+	}
+	if len(violations) > 0 {
+		fmt.Printf("❌ Ledger has %d invariant violations\n", len(violations))
+		return 1
+	}
+	fmt.Println("✅ Ledger invariants hold")
+	return 0
+}
+
+func startAdminServer(adminServer *admin.Server, serviceName string, port int) {
+	fmt.Printf("📊 Admin server for %s starting on :%d\n", serviceName, port)
+	if err := adminServer.ListenAndServe(port); err != nil {
+		log.Fatalf("❌ Failed to start admin server: %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+// FakeRates are the fractions of calls a Fake provider fails, from 0 to 1.
+type FakeRates struct {
+	// Decline is the fraction of authorizations declined.
+	Decline float64
+	// Timeout is the fraction of calls that time out before reaching the
+	// provider, and so have no effect.
+	Timeout float64
+	// LostResponse is the fraction of calls that take effect but time out
+	// before the response arrives.
+	LostResponse float64
+}
+
+// Fake is an in-memory provider for local development and tests that
+// simulates declines and timeouts at the configured rates.
+type Fake struct {
+	mu      sync.Mutex
+	rates   FakeRates
+	rand    *rand.Rand
+	results map[string]fakeResult
+	auths   map[string]*fakeAuth
+	nextRef int
+}
+
+// fakeResult is the outcome of a key's first call that took effect.
+type fakeResult struct {
+	result Result
+	err    error
+}
+
+type fakeAuth struct {
+	amount   money.Money
+	captured money.Money
+	refunded money.Money
+	voided   bool
+}
+
+// NewFake returns a Fake failing calls at rates, drawn from a source seeded
+// with seed.
+func NewFake(rates FakeRates, seed int64) *Fake {
+	return &Fake{
+		rates:   rates,
+		rand:    rand.New(rand.NewSource(seed)),
+		results: make(map[string]fakeResult),
+		auths:   make(map[string]*fakeAuth),
+	}
+}
+
+// SetRates changes the failure rates of later calls.
+func (f *Fake) SetRates(rates FakeRates) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rates = rates
+}
+
+func (f *Fake) Authorize(ctx context.Context, key string, userID int32, amount money.Money) (Result, error) {
+	return f.call(ctx, key, func() (Result, error) {
+		if f.hit(f.rates.Decline) {
+			return Result{}, &DeclinedError{Reason: "insufficient funds"}
+		}
+		ref := f.reference("auth")
+		f.auths[ref] = &fakeAuth{amount: amount, captured: money.Money{Currency: amount.Currency}}
+		return Result{Reference: ref}, nil
+	})
+}
+
+func (f *Fake) Capture(ctx context.Context, key, authRef string, amount money.Money) (Result, error) {
+	return f.call(ctx, key, func() (Result, error) {
+		auth, ok := f.auths[authRef]
+		if !ok {
+			return Result{}, fmt.Errorf("unknown authorization %q", authRef)
+		}
+		if auth.voided || auth.captured.IsPositive() {
+			return Result{}, fmt.Errorf("authorization %q cannot be captured", authRef)
+		}
+		if cmp, err := amount.Cmp(auth.amount); err != nil || cmp > 0 {
+			return Result{}, fmt.Errorf("capture of %s exceeds authorization of %s", amount, auth.amount)
+		}
+		auth.captured = amount
+		auth.refunded = money.Money{Currency: amount.Currency}
+		return Result{Reference: f.reference("capture")}, nil
+	})
+}
+
+func (f *Fake) Void(ctx context.Context, key, authRef string) (Result, error) {
+	return f.call(ctx, key, func() (Result, error) {
+		auth, ok := f.auths[authRef]
+		if !ok {
+			return Result{}, fmt.Errorf("unknown authorization %q", authRef)
+		}
+		if auth.captured.IsPositive() {
+			return Result{}, fmt.Errorf("authorization %q is already captured", authRef)
+		}
+		auth.voided = true
+		return Result{Reference: f.reference("void")}, nil
+	})
+}
+
+func (f *Fake) Refund(ctx context.Context, key, authRef string, amount money.Money) (Result, error) {
+	return f.call(ctx, key, func() (Result, error) {
+		auth, ok := f.auths[authRef]
+		if !ok {
+			return Result{}, fmt.Errorf("unknown authorization %q", authRef)
+		}
+		refunded, err := auth.refunded.Add(amount)
+		if err != nil {
+			return Result{}, err
+		}
+		if cmp, err := refunded.Cmp(auth.captured); err != nil || cmp > 0 {
+			return Result{}, fmt.Errorf("refunds of %s exceed capture of %s", refunded, auth.captured)
+		}
+		auth.refunded = refunded
+		return Result{Reference: f.reference("refund")}, nil
+	})
+}
+
+// call runs op once per key. Timeouts are drawn before op runs and lost
+// responses after it, so only the latter are remembered for retries.
+func (f *Fake) call(ctx context.Context, key string, op func() (Result, error)) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.hit(f.rates.Timeout) {
+		return Result{}, ErrTimeout
+	}
+
+	res, ok := f.results[key]
+	if !ok {
+		res.result, res.err = op()
+		f.results[key] = res
+	}
+
+	if f.hit(f.rates.LostResponse) {
+		return Result{}, ErrTimeout
+	}
+	return res.result, res.err
+}
+
+func (f *Fake) hit(rate float64) bool {
+	return rate > 0 && f.rand.Float64() < rate
+}
+
+func (f *Fake) reference(kind string) string {
+	f.nextRef++
+	return fmt.Sprintf("fake_%s_%d", kind, f.nextRef)
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+func rupees(n int64) money.Money {
+	m, _ := money.FromMajor(money.PKR, n)
+	return m
+}
+
+func TestFake_IdempotentByKey(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake(FakeRates{}, 1)
+
+	first, err := fake.Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	require.NoError(t, err)
+	again, err := fake.Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	other, err := fake.Authorize(ctx, AuthorizeKey(2), 1, rupees(500))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Reference, other.Reference)
+
+	capture, err := fake.Capture(ctx, CaptureKey(1), first.Reference, rupees(500))
+	require.NoError(t, err)
+	captureAgain, err := fake.Capture(ctx, CaptureKey(1), first.Reference, rupees(500))
+	require.NoError(t, err)
+	assert.Equal(t, capture, captureAgain)
+}
+
+func TestFake_Declines(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake(FakeRates{Decline: 1}, 1)
+
+	_, err := fake.Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	var declined *DeclinedError
+	require.ErrorAs(t, err, &declined)
+	assert.Equal(t, "insufficient funds", declined.Reason)
+
+	// The key stays declined once declines stop
+	fake.SetRates(FakeRates{})
+	_, err = fake.Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	assert.ErrorAs(t, err, &declined)
+}
+
+func TestFake_Timeouts(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake(FakeRates{Timeout: 1}, 1)
+
+	_, err := fake.Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	// A timed-out call had no effect, so a decline on retry is still possible
+	fake.SetRates(FakeRates{Decline: 1})
+	_, err = fake.Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	var declined *DeclinedError
+	assert.ErrorAs(t, err, &declined)
+}
+
+func TestFake_LostResponses(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake(FakeRates{LostResponse: 1}, 1)
+
+	_, err := fake.Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	// The lost call took effect, so a retry is not declined
+	fake.SetRates(FakeRates{Decline: 1})
+	res, err := fake.Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	require.NoError(t, err)
+	assert.NotEmpty(t, res.Reference)
+}
+
+func TestFake_RejectsInvalidOperations(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake(FakeRates{}, 1)
+
+	auth, err := fake.Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	require.NoError(t, err)
+
+	_, err = fake.Capture(ctx, CaptureKey(1), auth.Reference, rupees(600))
+	assert.Error(t, err, "capture over the authorization")
+
+	_, err = fake.Capture(ctx, CaptureKey(2), "unknown", rupees(100))
+	assert.Error(t, err, "unknown authorization")
+
+	_, err = fake.Capture(ctx, "capture-500", auth.Reference, rupees(500))
+	require.NoError(t, err)
+	_, err = fake.Void(ctx, VoidKey(1), auth.Reference)
+	assert.Error(t, err, "void after capture")
+
+	_, err = fake.Refund(ctx, RefundKey(1, 1), auth.Reference, rupees(300))
+	require.NoError(t, err)
+	_, err = fake.Refund(ctx, RefundKey(1, 2), auth.Reference, rupees(300))
+	assert.Error(t, err, "refunds over the capture")
+	_, err = fake.Refund(ctx, RefundKey(1, 3), auth.Reference, rupees(200))
+	assert.NoError(t, err)
+}
+
+func TestFake_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewFake(FakeRates{}, 1).Authorize(ctx, AuthorizeKey(1), 1, rupees(500))
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,77 @@
+// Package provider moves money through the card payment provider. Every call
+// carries an idempotency key: repeating a call with the same key returns the
+// first call's result instead of moving money again, so a call that timed out
+// can be retried safely.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+// ErrTimeout means the provider did not answer in time. The operation may or
+// may not have taken effect; retry it with the same idempotency key to find
+// out.
+var ErrTimeout = errors.New("payment provider timed out")
+
+// DeclinedError is the provider refusing an operation, e.g. for insufficient
+// funds. Retrying with the same key is declined again.
+type DeclinedError struct {
+	Reason string
+}
+
+func (e *DeclinedError) Error() string {
+	return "payment declined: " + e.Reason
+}
+
+// Result is an operation the provider carried out.
+type Result struct {
+	// Reference identifies the operation at the provider. Captures, voids and
+	// refunds refer to an authorization by its reference.
+	Reference string
+}
+
+type PaymentProvider interface {
+	// Authorize holds amount on the user's card.
+	Authorize(ctx context.Context, key string, userID int32, amount money.Money) (Result, error)
+	// Capture collects amount of the authorization authRef.
+	Capture(ctx context.Context, key, authRef string, amount money.Money) (Result, error)
+	// Void releases the authorization authRef without collecting it.
+	Void(ctx context.Context, key, authRef string) (Result, error)
+	// Refund returns amount of the captured authorization authRef.
+	Refund(ctx context.Context, key, authRef string, amount money.Money) (Result, error)
+}
+
+// Idempotency keys are derived from the booking, so a booking is authorized,
+// captured and voided at most once whichever replica retries the call.
+
+func AuthorizeKey(bookingID int32) string {
+	return fmt.Sprintf("booking-%d-authorize", bookingID)
+}
+
+func CaptureKey(bookingID int32) string {
+	return fmt.Sprintf("booking-%d-capture", bookingID)
+}
+
+func VoidKey(bookingID int32) string {
+	return fmt.Sprintf("booking-%d-void", bookingID)
+}
+
+// RefundKey numbers a booking's refunds from 1, so a retried refund reuses its
+// key until it is recorded and the next refund gets a new one.
+func RefundKey(bookingID, n int32) string {
+	return fmt.Sprintf("booking-%d-refund-%d", bookingID, n)
+}
+
+// TopUpAuthorizeKey and TopUpCaptureKey derive a wallet top-up's provider keys
+// from the client's idempotency key, scoped to the user.
+func TopUpAuthorizeKey(userID int32, key string) string {
+	return fmt.Sprintf("wallet-%d-topup-%s-authorize", userID, key)
+}
+
+func TopUpCaptureKey(userID int32, key string) string {
+	return fmt.Sprintf("wallet-%d-topup-%s-capture", userID, key)
+}
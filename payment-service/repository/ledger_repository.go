@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+
+	"payment-service/ledger"
+)
+
+type LedgerRepository interface {
+	// Post validates a journal entry, records it and applies it to the
+	// balances of its accounts, opening any account it is the first to post
+	// to. An entry whose idempotency key is already posted is returned as it
+	// was posted instead.
+	Post(ctx context.Context, entry ledger.Entry) (*ledger.Entry, error)
+	GetEntry(ctx context.Context, idempotencyKey string) (*ledger.Entry, error)
+	GetAccount(ctx context.Context, code string) (*ledger.Account, error)
+	// CheckInvariants describes every way the books are inconsistent, and
+	// returns none when they are sound.
+	CheckInvariants(ctx context.Context) ([]string, error)
+}
+
+type PostgresLedgerRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresLedgerRepository(db *sql.DB) LedgerRepository {
+	return &PostgresLedgerRepository{db: db}
+}
+
+func scanAccount(row rowScanner) (*ledger.Account, error) {
+	a := &ledger.Account{}
+	if err := row.Scan(&a.Code, &a.Type, &a.Balance.Currency, &a.Balance.Minor); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *PostgresLedgerRepository) Post(ctx context.Context, entry ledger.Entry) (*ledger.Entry, error) {
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Post journal entry failed: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO journal_entries (idempotency_key, kind, memo) VALUES ($1, $2, $3)
+		ON CONFLICT (idempotency_key) DO NOTHING
+		RETURNING entry_id, created_at`
+	err = tx.QueryRowContext(ctx, query, entry.IdempotencyKey, entry.Kind, entry.Memo).Scan(&entry.ID, &entry.CreatedAt)
+	if err == sql.ErrNoRows {
+		// Already posted by an earlier attempt
+		tx.Rollback()
+		return r.GetEntry(ctx, entry.IdempotencyKey)
+	}
+	if err != nil {
+		log.Printf("Post journal entry failed: %v", err)
+		return nil, err
+	}
+
+	// Lock the entry's accounts in code order, so concurrent entries touching
+	// the same accounts cannot deadlock and each sees the other's balance
+	var codes []string
+	accounts := map[string]*ledger.Account{}
+	for _, p := range entry.Postings {
+		if _, ok := accounts[p.Account]; !ok {
+			codes = append(codes, p.Account)
+		}
+		accounts[p.Account] = nil
+	}
+	sort.Strings(codes)
+
+	currency := entry.Postings[0].Amount.Currency
+	for _, code := range codes {
+		accountType, err := ledger.AccountType(code)
+		if err != nil {
+			return nil, err
+		}
+		query = `INSERT INTO ledger_accounts (code, account_type, currency) VALUES ($1, $2, $3)
+			ON CONFLICT (code) DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, code, accountType, currency); err != nil {
+			log.Printf("Post journal entry failed: %v", err)
+			return nil, err
+		}
+
+		query = `SELECT code, account_type, currency, balance_minor FROM ledger_accounts WHERE code = $1 FOR UPDATE`
+		if accounts[code], err = scanAccount(tx.QueryRowContext(ctx, query, code)); err != nil {
+			log.Printf("Post journal entry failed: %v", err)
+			return nil, err
+		}
+	}
+
+	for _, p := range entry.Postings {
+		if err := accounts[p.Account].Apply(p); err != nil {
+			return nil, err
+		}
+
+		query = `INSERT INTO journal_postings (entry_id, account_code, currency, amount_minor) VALUES ($1, $2, $3, $4)`
+		if _, err := tx.ExecContext(ctx, query, entry.ID, p.Account, p.Amount.Currency, p.Amount.Minor); err != nil {
+			log.Printf("Post journal entry failed: %v", err)
+			return nil, err
+		}
+	}
+
+	for _, code := range codes {
+		query = `UPDATE ledger_accounts SET balance_minor = $1, updated_at = now() WHERE code = $2`
+		if _, err := tx.ExecContext(ctx, query, accounts[code].Balance.Minor, code); err != nil {
+			log.Printf("Post journal entry failed: %v", err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Post journal entry failed: %v", err)
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *PostgresLedgerRepository) GetEntry(ctx context.Context, idempotencyKey string) (*ledger.Entry, error) {
+	entry := &ledger.Entry{}
+	query := `SELECT entry_id, idempotency_key, kind, memo, created_at FROM journal_entries WHERE idempotency_key = $1`
+	err := r.db.QueryRowContext(ctx, query, idempotencyKey).Scan(&entry.ID, &entry.IdempotencyKey, &entry.Kind,
+		&entry.Memo, &entry.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("entry not found")
+		}
+		log.Printf("Get journal entry failed: %v", err)
+		return nil, err
+	}
+
+	query = `SELECT account_code, currency, amount_minor FROM journal_postings WHERE entry_id = $1 ORDER BY posting_id`
+	rows, err := r.db.QueryContext(ctx, query, entry.ID)
+	if err != nil {
+		log.Printf("Get journal entry failed: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p ledger.Posting
+		if err := rows.Scan(&p.Account, &p.Amount.Currency, &p.Amount.Minor); err != nil {
+			log.Printf("Get journal entry failed: %v", err)
+			return nil, err
+		}
+		entry.Postings = append(entry.Postings, p)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Get journal entry failed: %v", err)
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (r *PostgresLedgerRepository) GetAccount(ctx context.Context, code string) (*ledger.Account, error) {
+	query := `SELECT code, account_type, currency, balance_minor FROM ledger_accounts WHERE code = $1`
+	account, err := scanAccount(r.db.QueryRowContext(ctx, query, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("account not found")
+		}
+		log.Printf("Get ledger account failed: %v", err)
+		return nil, err
+	}
+	return account, nil
+}
+
+// invariantChecks are queries returning one description per violation.
+var invariantChecks = []string{
+	// Every entry balances in each currency
+	`SELECT 'entry ' || e.idempotency_key || ' is off by ' || SUM(p.amount_minor) || ' ' || p.currency
+		FROM journal_entries e JOIN journal_postings p ON p.entry_id = e.entry_id
+		GROUP BY e.entry_id, e.idempotency_key, p.currency
+		HAVING SUM(p.amount_minor) <> 0`,
+	// Every entry has at least two postings
+	`SELECT 'entry ' || e.idempotency_key || ' has ' || COUNT(p.posting_id) || ' postings'
+		FROM journal_entries e LEFT JOIN journal_postings p ON p.entry_id = e.entry_id
+		GROUP BY e.entry_id, e.idempotency_key
+		HAVING COUNT(p.posting_id) < 2`,
+	// Every balance is the sum of the account's postings
+	`SELECT 'account ' || a.code || ' has balance ' || a.balance_minor || ' but postings sum to ' ||
+			COALESCE(SUM(CASE WHEN a.account_type = 'ASSET' THEN p.amount_minor ELSE -p.amount_minor END), 0)
+		FROM ledger_accounts a LEFT JOIN journal_postings p ON p.account_code = a.code
+		GROUP BY a.code, a.account_type, a.balance_minor
+		HAVING a.balance_minor <> COALESCE(SUM(CASE WHEN a.account_type = 'ASSET' THEN p.amount_minor ELSE -p.amount_minor END), 0)`,
+	// Postings are in their account's currency
+	`SELECT 'account ' || a.code || ' in ' || a.currency || ' has a posting in ' || p.currency
+		FROM ledger_accounts a JOIN journal_postings p ON p.account_code = a.code
+		WHERE p.currency <> a.currency`,
+	// No wallet is overdrawn
+	`SELECT 'wallet ' || code || ' is overdrawn by ' || -balance_minor || ' ' || currency
+		FROM ledger_accounts
+		WHERE code LIKE 'user:%:wallet' AND balance_minor < 0`,
+	// Every captured payment has been split into earnings. A free ride without
+	// a driver has nothing to split.
+	`SELECT 'booking ' || p.booking_id || ' was captured without an earning entry'
+		FROM payments p
+		WHERE p.status IN ('CAPTURED', 'REFUNDED') AND p.captured_minor > 0
+			AND NOT EXISTS (SELECT 1 FROM journal_entries e WHERE e.idempotency_key = 'booking-' || p.booking_id || '-earning')`,
+	// Every refund of a payment has been taken back from its earnings
+	`SELECT 'booking ' || p.booking_id || ' has ' || p.refund_count || ' refunds but ' || COUNT(e.entry_id) || ' refund entries'
+		FROM payments p LEFT JOIN journal_entries e ON e.idempotency_key LIKE 'booking-' || p.booking_id || '-refund-%'
+		GROUP BY p.booking_id, p.refund_count
+		HAVING COUNT(e.entry_id) <> p.refund_count`,
+}
+
+func (r *PostgresLedgerRepository) CheckInvariants(ctx context.Context) ([]string, error) {
+	var violations []string
+	for _, query := range invariantChecks {
+		rows, err := r.db.QueryContext(ctx, query)
+		if err != nil {
+			log.Printf("Check ledger invariants failed: %v", err)
+			return nil, err
+		}
+		for rows.Next() {
+			var violation string
+			if err := rows.Scan(&violation); err != nil {
+				rows.Close()
+				log.Printf("Check ledger invariants failed: %v", err)
+				return nil, err
+			}
+			violations = append(violations, violation)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			log.Printf("Check ledger invariants failed: %v", err)
+			return nil, err
+		}
+	}
+	return violations, nil
+}
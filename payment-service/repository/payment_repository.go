@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+const (
+	StatusPending    = "PENDING"
+	StatusAuthorized = "AUTHORIZED"
+	StatusDeclined   = "DECLINED"
+	StatusCaptured   = "CAPTURED"
+	StatusVoided     = "VOIDED"
+	StatusRefunded   = "REFUNDED"
+)
+
+// Ledger entry types, one for each provider operation.
+const (
+	EntryAuthorize = "AUTHORIZE"
+	EntryDecline   = "DECLINE"
+	EntryCapture   = "CAPTURE"
+	EntryVoid      = "VOID"
+	EntryRefund    = "REFUND"
+)
+
+type Payment struct {
+	ID        int32
+	BookingID int32
+	UserID    int32
+	Status    string
+	// Amount is authorized, or being authorized while PENDING.
+	Amount money.Money
+	// Discount is the part of the fare the platform pays for, such as a
+	// promotion. It is never charged, but counts towards the driver's share.
+	Discount money.Money
+	Captured money.Money
+	Refunded money.Money
+	// Refunds counts recorded refunds, which number their idempotency keys.
+	Refunds int32
+	// ProviderRef is the provider's reference for the authorization.
+	ProviderRef   string
+	DeclineReason string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// LedgerEntry is a provider operation on a payment.
+type LedgerEntry struct {
+	Type           string
+	Amount         money.Money
+	ProviderRef    string
+	IdempotencyKey string
+	Reason         string
+}
+
+// Apply updates p with the effect of entry, or returns an error if entry is
+// not allowed in p's current state.
+func (p *Payment) Apply(entry LedgerEntry) error {
+	switch entry.Type {
+	case EntryAuthorize:
+		if p.Status != StatusPending {
+			return fmt.Errorf("payment is not pending")
+		}
+		p.Status = StatusAuthorized
+		p.ProviderRef = entry.ProviderRef
+	case EntryDecline:
+		if p.Status != StatusPending {
+			return fmt.Errorf("payment is not pending")
+		}
+		p.Status = StatusDeclined
+		p.DeclineReason = entry.Reason
+	case EntryCapture:
+		if p.Status != StatusAuthorized {
+			return fmt.Errorf("payment is not authorized")
+		}
+		p.Status = StatusCaptured
+		p.Captured = entry.Amount
+	case EntryVoid:
+		if p.Status != StatusAuthorized {
+			return fmt.Errorf("payment is not authorized")
+		}
+		p.Status = StatusVoided
+	case EntryRefund:
+		if p.Status != StatusCaptured {
+			return fmt.Errorf("payment is not captured")
+		}
+		refunded, err := p.Refunded.Add(entry.Amount)
+		if err != nil {
+			return err
+		}
+		cmp, err := refunded.Cmp(p.Captured)
+		if err != nil {
+			return err
+		}
+		if cmp > 0 {
+			return fmt.Errorf("refund exceeds captured amount")
+		}
+		p.Refunded = refunded
+		p.Refunds++
+		if cmp == 0 {
+			p.Status = StatusRefunded
+		}
+	default:
+		return fmt.Errorf("unknown ledger entry type %q", entry.Type)
+	}
+	return nil
+}
+
+type PaymentRepository interface {
+	// Create records a PENDING payment of amount for a booking, or returns the
+	// booking's payment if it already has one.
+	Create(ctx context.Context, bookingID, userID int32, amount, discount money.Money) (*Payment, error)
+	GetByBookingID(ctx context.Context, bookingID int32) (*Payment, error)
+	// Record applies a provider operation to the booking's payment and adds it
+	// to the ledger. An entry whose idempotency key is already recorded leaves
+	// the payment unchanged.
+	Record(ctx context.Context, bookingID int32, entry LedgerEntry) (*Payment, error)
+}
+
+type PostgresPaymentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPaymentRepository(db *sql.DB) PaymentRepository {
+	return &PostgresPaymentRepository{db: db}
+}
+
+const paymentColumns = `payment_id, booking_id, user_id, status, currency, amount_minor, discount_minor,
+	captured_minor, refunded_minor, refund_count, provider_ref, decline_reason, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPayment(row rowScanner) (*Payment, error) {
+	p := &Payment{}
+	var currency string
+	if err := row.Scan(&p.ID, &p.BookingID, &p.UserID, &p.Status, &currency, &p.Amount.Minor, &p.Discount.Minor,
+		&p.Captured.Minor, &p.Refunded.Minor, &p.Refunds, &p.ProviderRef, &p.DeclineReason, &p.CreatedAt,
+		&p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	p.Amount.Currency = currency
+	p.Discount.Currency = currency
+	p.Captured.Currency = currency
+	p.Refunded.Currency = currency
+	return p, nil
+}
+
+func (r *PostgresPaymentRepository) Create(ctx context.Context, bookingID, userID int32, amount, discount money.Money) (*Payment, error) {
+	query := `INSERT INTO payments (booking_id, user_id, status, currency, amount_minor, discount_minor)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (booking_id) DO NOTHING
+		RETURNING ` + paymentColumns
+	payment, err := scanPayment(r.db.QueryRowContext(ctx, query, bookingID, userID, StatusPending, amount.Currency,
+		amount.Minor, discount.Minor))
+	if err == sql.ErrNoRows {
+		// The booking already has a payment
+		return r.GetByBookingID(ctx, bookingID)
+	}
+	if err != nil {
+		log.Printf("Create payment failed: %v", err)
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PostgresPaymentRepository) GetByBookingID(ctx context.Context, bookingID int32) (*Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE booking_id = $1`
+	payment, err := scanPayment(r.db.QueryRowContext(ctx, query, bookingID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment not found")
+		}
+		log.Printf("Get payment failed: %v", err)
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PostgresPaymentRepository) Record(ctx context.Context, bookingID int32, entry LedgerEntry) (*Payment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("Record payment operation failed: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE booking_id = $1 FOR UPDATE`
+	payment, err := scanPayment(tx.QueryRowContext(ctx, query, bookingID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment not found")
+		}
+		log.Printf("Record payment operation failed: %v", err)
+		return nil, err
+	}
+
+	query = `INSERT INTO payment_ledger (payment_id, entry_type, currency, amount_minor, provider_ref, idempotency_key, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (idempotency_key) DO NOTHING
+		RETURNING entry_id`
+	var entryID int64
+	err = tx.QueryRowContext(ctx, query, payment.ID, entry.Type, payment.Amount.Currency, entry.Amount.Minor,
+		entry.ProviderRef, entry.IdempotencyKey, entry.Reason).Scan(&entryID)
+	if err == sql.ErrNoRows {
+		// Already recorded by an earlier attempt
+		return payment, nil
+	}
+	if err != nil {
+		log.Printf("Record payment operation failed: %v", err)
+		return nil, err
+	}
+
+	if err := payment.Apply(entry); err != nil {
+		return nil, err
+	}
+
+	query = `UPDATE payments SET status = $1, captured_minor = $2, refunded_minor = $3, refund_count = $4,
+		provider_ref = $5, decline_reason = $6, updated_at = now()
+		WHERE payment_id = $7
+		RETURNING ` + paymentColumns
+	payment, err = scanPayment(tx.QueryRowContext(ctx, query, payment.Status, payment.Captured.Minor, payment.Refunded.Minor,
+		payment.Refunds, payment.ProviderRef, payment.DeclineReason, payment.ID))
+	if err != nil {
+		log.Printf("Record payment operation failed: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Record payment operation failed: %v", err)
+		return nil, err
+	}
+
+	return payment, nil
+}
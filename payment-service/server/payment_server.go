@@ -0,0 +1,683 @@
+package server
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	driverpb "driver-service/pb/proto/driver"
+	"payment-service/ledger"
+	pb "payment-service/pb/proto/payment"
+	"payment-service/provider"
+	"payment-service/repository"
+
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
+
+	"github.com/hasnain-zafar/go-microservices/common/errors"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
+)
+
+// providerAttempts is how many times a provider call that timed out is tried
+// with the same idempotency key before giving up.
+const providerAttempts = 3
+
+const defaultRetryBackoff = 200 * time.Millisecond
+
+// defaultCommissionBps is the platform's cut of a fare, in basis points.
+const defaultCommissionBps = 2000
+
+type PaymentServer struct {
+	pb.UnimplementedPaymentServiceServer
+	repo          repository.PaymentRepository
+	ledgerRepo    repository.LedgerRepository
+	provider      provider.PaymentProvider
+	driverClient  driverpb.DriverServiceClient
+	logger        *logger.Logger
+	errorHandler  *errors.ErrorHandler
+	serviceName   string
+	retryBackoff  time.Duration
+	commissionBps int64
+}
+
+// Option configures optional PaymentServer settings.
+type Option func(*PaymentServer)
+
+// WithMetrics counts the server's errors in reg rather than in a registry of
+// its own.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(s *PaymentServer) {
+		s.logger = s.logger.WithMetrics(reg)
+		s.errorHandler = errors.NewErrorHandler(s.logger)
+	}
+}
+
+// WithRetryBackoff sets the wait before the first retry of a provider call
+// that timed out. Each later retry waits as long again.
+func WithRetryBackoff(backoff time.Duration) Option {
+	return func(s *PaymentServer) {
+		s.retryBackoff = backoff
+	}
+}
+
+// WithCommission sets the platform's cut of each captured fare, in basis
+// points. The rest is credited to the booking's driver.
+func WithCommission(bps int64) Option {
+	return func(s *PaymentServer) {
+		s.commissionBps = bps
+	}
+}
+
+func NewPaymentServer(
+	repo repository.PaymentRepository,
+	ledgerRepo repository.LedgerRepository,
+	provider provider.PaymentProvider,
+	driverClient driverpb.DriverServiceClient,
+	opts ...Option,
+) *PaymentServer {
+	serviceName := "payment-service"
+	log := logger.NewLogger(serviceName)
+	s := &PaymentServer{
+		repo:          repo,
+		ledgerRepo:    ledgerRepo,
+		provider:      provider,
+		driverClient:  driverClient,
+		logger:        log,
+		errorHandler:  errors.NewErrorHandler(log),
+		serviceName:   serviceName,
+		retryBackoff:  defaultRetryBackoff,
+		commissionBps: defaultCommissionBps,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// AuthorizePayment holds the booking's amount on the user's card. Calling it
+// again for the booking returns its payment as it stands. A booking whose
+// discount covers its whole fare holds nothing, but still has a payment so
+// that capturing it credits the driver.
+func (s *PaymentServer) AuthorizePayment(ctx context.Context, req *pb.AuthorizePaymentRequest) (*pb.Payment, error) {
+	method := "AuthorizePayment"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	amount, discount, err := validateAuthorizePaymentRequest(req)
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid payment request", err)
+	}
+
+	payment, err := s.repo.Create(ctx, req.BookingId, req.UserId, amount, discount)
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to create payment", err)
+	}
+
+	payment, err = s.authorize(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status == repository.StatusDeclined {
+		return nil, s.errorHandler.HandleFailedPrecondition("payment declined",
+			&provider.DeclinedError{Reason: payment.DeclineReason})
+	}
+
+	res := paymentToProto(payment)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// authorize asks the provider to authorize a PENDING payment and records the
+// outcome. Payments past PENDING are returned unchanged.
+func (s *PaymentServer) authorize(ctx context.Context, payment *repository.Payment) (*repository.Payment, error) {
+	if payment.Status != repository.StatusPending {
+		return payment, nil
+	}
+
+	key := provider.AuthorizeKey(payment.BookingID)
+	// Nor is anything captured or voided on the card later
+	if payment.Amount.IsZero() {
+		return s.record(ctx, payment, repository.LedgerEntry{Type: repository.EntryAuthorize}, key)
+	}
+	result, err := s.callProvider(ctx, func() (provider.Result, error) {
+		return s.provider.Authorize(ctx, key, payment.UserID, payment.Amount)
+	})
+
+	var declined *provider.DeclinedError
+	switch {
+	case goerrors.As(err, &declined):
+		return s.record(ctx, payment, repository.LedgerEntry{Type: repository.EntryDecline, Reason: declined.Reason}, key)
+	case err != nil:
+		return nil, s.providerError("failed to authorize payment", err)
+	}
+	return s.record(ctx, payment, repository.LedgerEntry{Type: repository.EntryAuthorize, ProviderRef: result.Reference}, key)
+}
+
+// CapturePayment collects the authorized amount once the ride is completed and
+// credits the driver's share of it to their earnings. The driver must be
+// assigned to the booking in driver-service. Capturing a captured
+// payment returns it unchanged, after posting the earnings if an earlier
+// attempt failed to.
+func (s *PaymentServer) CapturePayment(ctx context.Context, req *pb.CapturePaymentRequest) (*pb.Payment, error) {
+	method := "CapturePayment"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+
+	payment, err := s.getPayment(ctx, req.BookingId)
+	if err != nil {
+		return nil, err
+	}
+	if req.DriverId != 0 {
+		if err := s.checkAssignment(ctx, req.BookingId, req.DriverId); err != nil {
+			return nil, err
+		}
+	}
+
+	switch payment.Status {
+	case repository.StatusCaptured, repository.StatusRefunded:
+	case repository.StatusAuthorized:
+		key := provider.CaptureKey(payment.BookingID)
+		var result provider.Result
+		if !payment.Amount.IsZero() {
+			result, err = s.callProvider(ctx, func() (provider.Result, error) {
+				return s.provider.Capture(ctx, key, payment.ProviderRef, payment.Amount)
+			})
+			if err != nil {
+				return nil, s.providerError("failed to capture payment", err)
+			}
+		}
+		entry := repository.LedgerEntry{Type: repository.EntryCapture, Amount: payment.Amount, ProviderRef: result.Reference}
+		if payment, err = s.record(ctx, payment, entry, key); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, s.errorHandler.HandleFailedPrecondition("payment cannot be captured",
+			fmt.Errorf("payment is %s", payment.Status))
+	}
+
+	earning, err := ledger.Earning(payment.BookingID, req.DriverId, payment.Captured, payment.Discount, s.commissionBps)
+	if err != nil {
+		return nil, s.errorHandler.HandleInternalError("failed to split fare", err)
+	}
+	// A free ride without a driver has nothing to split
+	if len(earning.Postings) > 0 {
+		if _, err := s.post(ctx, earning); err != nil {
+			return nil, err
+		}
+	}
+
+	res := paymentToProto(payment)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// VoidPayment releases an authorization that will not be captured. A PENDING
+// payment is authorized first with the same key, since an authorization whose
+// response was lost may still hold the amount.
+func (s *PaymentServer) VoidPayment(ctx context.Context, req *pb.VoidPaymentRequest) (*pb.Payment, error) {
+	method := "VoidPayment"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+
+	payment, err := s.getPayment(ctx, req.BookingId)
+	if err != nil {
+		return nil, err
+	}
+	if payment, err = s.authorize(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	switch payment.Status {
+	case repository.StatusVoided, repository.StatusDeclined:
+	case repository.StatusAuthorized:
+		key := provider.VoidKey(payment.BookingID)
+		var result provider.Result
+		if !payment.Amount.IsZero() {
+			result, err = s.callProvider(ctx, func() (provider.Result, error) {
+				return s.provider.Void(ctx, key, payment.ProviderRef)
+			})
+			if err != nil {
+				return nil, s.providerError("failed to void payment", err)
+			}
+		}
+		entry := repository.LedgerEntry{Type: repository.EntryVoid, ProviderRef: result.Reference}
+		if payment, err = s.record(ctx, payment, entry, key); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, s.errorHandler.HandleFailedPrecondition("payment cannot be voided",
+			fmt.Errorf("payment is %s", payment.Status))
+	}
+
+	res := paymentToProto(payment)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// RefundPayment returns part or all of a captured payment, taking it back from
+// the driver's earnings and the platform's commission in proportion. Without
+// an amount it refunds whatever is left, and returns a fully refunded payment
+// unchanged.
+func (s *PaymentServer) RefundPayment(ctx context.Context, req *pb.RefundPaymentRequest) (*pb.Payment, error) {
+	method := "RefundPayment"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+
+	payment, err := s.getPayment(ctx, req.BookingId)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status == repository.StatusRefunded && req.Amount == nil {
+		res := paymentToProto(payment)
+		s.logger.LogResponse(ctx, method, res)
+		return res, nil
+	}
+	if payment.Status != repository.StatusCaptured {
+		return nil, s.errorHandler.HandleFailedPrecondition("payment cannot be refunded",
+			fmt.Errorf("payment is %s", payment.Status))
+	}
+	if payment.Captured.IsZero() {
+		return nil, s.errorHandler.HandleFailedPrecondition("payment cannot be refunded",
+			fmt.Errorf("nothing was captured"))
+	}
+
+	amount, err := refundAmount(payment, req)
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid refund amount", err)
+	}
+
+	earning, err := s.ledgerRepo.GetEntry(ctx, ledger.EarningKey(payment.BookingID))
+	if err != nil {
+		if err.Error() == "entry not found" {
+			// CapturePayment posts it when retried
+			return nil, s.errorHandler.HandleFailedPrecondition("payment cannot be refunded",
+				fmt.Errorf("earnings of the payment are not posted yet"))
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to get earnings", err)
+	}
+
+	key := provider.RefundKey(payment.BookingID, payment.Refunds+1)
+	result, err := s.callProvider(ctx, func() (provider.Result, error) {
+		return s.provider.Refund(ctx, key, payment.ProviderRef, amount)
+	})
+	if err != nil {
+		return nil, s.providerError("failed to refund payment", err)
+	}
+
+	// Post the refund before recording it, so a retry after a failure posts it
+	// again under the same key
+	reversal, err := ledger.Refund(payment.BookingID, payment.Refunds+1, *earning, amount, req.Reason)
+	if err != nil {
+		return nil, s.errorHandler.HandleInternalError("failed to split refund", err)
+	}
+	if _, err := s.post(ctx, reversal); err != nil {
+		return nil, err
+	}
+	entry := repository.LedgerEntry{Type: repository.EntryRefund, Amount: amount, ProviderRef: result.Reference, Reason: req.Reason}
+	if payment, err = s.record(ctx, payment, entry, key); err != nil {
+		return nil, err
+	}
+
+	res := paymentToProto(payment)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *PaymentServer) GetPayment(ctx context.Context, req *pb.GetPaymentRequest) (*pb.Payment, error) {
+	method := "GetPayment"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetBookingId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid booking ID", fmt.Errorf("booking ID must be positive"))
+	}
+
+	payment, err := s.getPayment(ctx, req.BookingId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := paymentToProto(payment)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// TopUpWallet charges the user's card and adds the amount to their wallet.
+func (s *PaymentServer) TopUpWallet(ctx context.Context, req *pb.TopUpWalletRequest) (*pb.Wallet, error) {
+	method := "TopUpWallet"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	amount, err := validateWalletChange(req.UserId, req.Amount, req.IdempotencyKey)
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid top-up", err)
+	}
+
+	authKey := provider.TopUpAuthorizeKey(req.UserId, req.IdempotencyKey)
+	auth, err := s.callProvider(ctx, func() (provider.Result, error) {
+		return s.provider.Authorize(ctx, authKey, req.UserId, amount)
+	})
+	if err != nil {
+		return nil, s.providerError("failed to charge card", err)
+	}
+
+	captureKey := provider.TopUpCaptureKey(req.UserId, req.IdempotencyKey)
+	_, err = s.callProvider(ctx, func() (provider.Result, error) {
+		return s.provider.Capture(ctx, captureKey, auth.Reference, amount)
+	})
+	if err != nil {
+		return nil, s.providerError("failed to charge card", err)
+	}
+
+	if _, err := s.post(ctx, ledger.TopUp(req.UserId, amount, req.IdempotencyKey)); err != nil {
+		return nil, err
+	}
+
+	res, err := s.getWallet(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// DebitWallet spends from the user's wallet, failing if the wallet holds less
+// than the amount.
+func (s *PaymentServer) DebitWallet(ctx context.Context, req *pb.DebitWalletRequest) (*pb.Wallet, error) {
+	method := "DebitWallet"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	amount, err := validateWalletChange(req.UserId, req.Amount, req.IdempotencyKey)
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid debit", err)
+	}
+
+	if _, err := s.post(ctx, ledger.WalletDebit(req.UserId, amount, req.IdempotencyKey, req.Reason)); err != nil {
+		return nil, err
+	}
+
+	res, err := s.getWallet(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *PaymentServer) GetWallet(ctx context.Context, req *pb.GetWalletRequest) (*pb.Wallet, error) {
+	method := "GetWallet"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetUserId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid user ID", fmt.Errorf("user ID must be positive"))
+	}
+
+	res, err := s.getWallet(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *PaymentServer) GetDriverEarnings(ctx context.Context, req *pb.GetDriverEarningsRequest) (*pb.DriverEarnings, error) {
+	method := "GetDriverEarnings"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if req.GetDriverId() <= 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid driver ID", fmt.Errorf("driver ID must be positive"))
+	}
+
+	account, err := s.ledgerRepo.GetAccount(ctx, ledger.DriverEarnings(req.DriverId))
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, s.errorHandler.HandleNotFound("driver has no earnings", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to get earnings", err)
+	}
+
+	res := &pb.DriverEarnings{
+		DriverId: req.DriverId,
+		Balance:  money.ToProto(account.Balance),
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *PaymentServer) getWallet(ctx context.Context, userID int32) (*pb.Wallet, error) {
+	account, err := s.ledgerRepo.GetAccount(ctx, ledger.UserWallet(userID))
+	if err != nil {
+		if err.Error() == "account not found" {
+			return nil, s.errorHandler.HandleNotFound("wallet not found", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to get wallet", err)
+	}
+	return &pb.Wallet{UserId: userID, Balance: money.ToProto(account.Balance)}, nil
+}
+
+// post adds a journal entry to the ledger.
+func (s *PaymentServer) post(ctx context.Context, entry ledger.Entry) (*ledger.Entry, error) {
+	posted, err := s.ledgerRepo.Post(ctx, entry)
+	if err != nil {
+		switch {
+		case err.Error() == "insufficient funds":
+			return nil, s.errorHandler.HandleFailedPrecondition("insufficient wallet balance", err)
+		case goerrors.Is(err, money.ErrCurrencyMismatch):
+			return nil, s.errorHandler.HandleFailedPrecondition("account is in another currency", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to post journal entry", err)
+	}
+	return posted, nil
+}
+
+// checkAssignment rejects crediting a booking's fare to a driver who is not
+// assigned to it. Once the earnings are posted, posting them again changes
+// nothing, so the driver may since have been released.
+func (s *PaymentServer) checkAssignment(ctx context.Context, bookingID, driverID int32) error {
+	_, err := s.ledgerRepo.GetEntry(ctx, ledger.EarningKey(bookingID))
+	switch {
+	case err == nil:
+		return nil
+	case err.Error() != "entry not found":
+		return s.errorHandler.HandleDatabaseError("failed to get earnings", err)
+	}
+
+	driver, err := s.driverClient.GetDriver(ctx, &driverpb.GetDriverRequest{DriverId: driverID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return s.errorHandler.HandleInvalidArgument("invalid driver", err)
+		}
+		return s.errorHandler.HandleNetworkError("failed to get driver", err)
+	}
+	if driver.BookingId != bookingID {
+		return s.errorHandler.HandleFailedPrecondition("driver cannot be credited",
+			fmt.Errorf("driver %d is not assigned to booking %d", driverID, bookingID))
+	}
+	return nil
+}
+
+func (s *PaymentServer) getPayment(ctx context.Context, bookingID int32) (*repository.Payment, error) {
+	payment, err := s.repo.GetByBookingID(ctx, bookingID)
+	if err != nil {
+		if err.Error() == "payment not found" {
+			return nil, s.errorHandler.HandleNotFound("payment not found", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to get payment", err)
+	}
+	return payment, nil
+}
+
+// record adds a provider operation made with key to the payment's ledger.
+func (s *PaymentServer) record(ctx context.Context, payment *repository.Payment, entry repository.LedgerEntry, key string) (*repository.Payment, error) {
+	if entry.Amount == (money.Money{}) {
+		entry.Amount = payment.Amount
+	}
+	entry.IdempotencyKey = key
+
+	recorded, err := s.repo.Record(ctx, payment.BookingID, entry)
+	if err != nil {
+		// A concurrent call moved the payment on first
+		switch err.Error() {
+		case "payment is not pending", "payment is not authorized", "payment is not captured", "refund exceeds captured amount":
+			return nil, s.errorHandler.HandleFailedPrecondition("payment changed concurrently", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to record payment", err)
+	}
+	return recorded, nil
+}
+
+// callProvider makes a provider call, retrying it with the same idempotency
+// key while it times out.
+func (s *PaymentServer) callProvider(ctx context.Context, call func() (provider.Result, error)) (provider.Result, error) {
+	var result provider.Result
+	var err error
+	for attempt := 1; attempt <= providerAttempts; attempt++ {
+		result, err = call()
+		if !goerrors.Is(err, provider.ErrTimeout) || attempt == providerAttempts {
+			break
+		}
+		s.logger.ErrorContext(ctx, "payment provider timed out, retrying", "error", err, "attempt", attempt)
+		select {
+		case <-ctx.Done():
+			return provider.Result{}, ctx.Err()
+		case <-time.After(time.Duration(attempt) * s.retryBackoff):
+		}
+	}
+	return result, err
+}
+
+func (s *PaymentServer) providerError(message string, err error) error {
+	var declined *provider.DeclinedError
+	if goerrors.As(err, &declined) {
+		return s.errorHandler.HandleFailedPrecondition(message, err)
+	}
+	return s.errorHandler.HandleNetworkError(message, err)
+}
+
+func validateAuthorizePaymentRequest(req *pb.AuthorizePaymentRequest) (money.Money, money.Money, error) {
+	if req.BookingId <= 0 {
+		return money.Money{}, money.Money{}, fmt.Errorf("booking ID must be positive")
+	}
+	if req.UserId <= 0 {
+		return money.Money{}, money.Money{}, fmt.Errorf("user ID must be positive")
+	}
+	amount, err := money.FromProto(req.Amount)
+	if err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	discount := money.Money{Currency: amount.Currency}
+	if req.Discount != nil {
+		if discount, err = money.FromProto(req.Discount); err != nil {
+			return money.Money{}, money.Money{}, fmt.Errorf("discount: %w", err)
+		}
+		if discount.Currency != amount.Currency {
+			return money.Money{}, money.Money{}, fmt.Errorf("discount must be in %s", amount.Currency)
+		}
+	}
+	if amount.IsNegative() || discount.IsNegative() {
+		return money.Money{}, money.Money{}, fmt.Errorf("amount and discount cannot be negative")
+	}
+	if amount.IsZero() && discount.IsZero() {
+		return money.Money{}, money.Money{}, fmt.Errorf("amount must be positive")
+	}
+	return amount, discount, nil
+}
+
+func validateWalletChange(userID int32, amount *moneypb.Money, idempotencyKey string) (money.Money, error) {
+	if userID <= 0 {
+		return money.Money{}, fmt.Errorf("user ID must be positive")
+	}
+	if idempotencyKey == "" {
+		return money.Money{}, fmt.Errorf("idempotency key cannot be empty")
+	}
+	m, err := money.FromProto(amount)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if !m.IsPositive() {
+		return money.Money{}, fmt.Errorf("amount must be positive")
+	}
+	return m, nil
+}
+
+// refundAmount is the requested refund, or everything left to refund.
+func refundAmount(payment *repository.Payment, req *pb.RefundPaymentRequest) (money.Money, error) {
+	remaining, err := payment.Captured.Sub(payment.Refunded)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if req.Amount == nil {
+		return remaining, nil
+	}
+
+	amount, err := money.FromProto(req.Amount)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if !amount.IsPositive() {
+		return money.Money{}, fmt.Errorf("amount must be positive")
+	}
+	cmp, err := amount.Cmp(remaining)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if cmp > 0 {
+		return money.Money{}, fmt.Errorf("refund exceeds captured amount")
+	}
+	return amount, nil
+}
+
+func paymentToProto(p *repository.Payment) *pb.Payment {
+	return &pb.Payment{
+		PaymentId:     p.ID,
+		BookingId:     p.BookingID,
+		UserId:        p.UserID,
+		Status:        p.Status,
+		Amount:        money.ToProto(p.Amount),
+		Discount:      money.ToProto(p.Discount),
+		Captured:      money.ToProto(p.Captured),
+		Refunded:      money.ToProto(p.Refunded),
+		DeclineReason: p.DeclineReason,
+		CreatedAt:     timestamppb.New(p.CreatedAt),
+		UpdatedAt:     timestamppb.New(p.UpdatedAt),
+	}
+}
@@ -0,0 +1,956 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	driverpb "driver-service/pb/proto/driver"
+	drivermocks "driver-service/pb/proto/driver/mocks"
+	"payment-service/ledger"
+	pb "payment-service/pb/proto/payment"
+	"payment-service/provider"
+	"payment-service/repository"
+	"payment-service/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
+)
+
+var testCreatedAt = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func rupees(n int64) money.Money {
+	m, _ := money.FromMajor(money.PKR, n)
+	return m
+}
+
+func testPayment(status string) *repository.Payment {
+	return &repository.Payment{
+		ID:        1,
+		BookingID: 7,
+		UserID:    3,
+		Status:    status,
+		Amount:    rupees(500),
+		Discount:  rupees(0),
+		Captured:  rupees(0),
+		Refunded:  rupees(0),
+		CreatedAt: testCreatedAt,
+		UpdatedAt: testCreatedAt,
+	}
+}
+
+// entry matches a ledger entry of the given type and idempotency key.
+func entry(entryType, key string) any {
+	return mock.MatchedBy(func(e repository.LedgerEntry) bool {
+		return e.Type == entryType && e.IdempotencyKey == key
+	})
+}
+
+// lossyProvider loses the responses of the first lost calls: they take effect
+// at the provider but return ErrTimeout.
+type lossyProvider struct {
+	*provider.Fake
+	lost  int
+	calls int
+}
+
+func (p *lossyProvider) Authorize(ctx context.Context, key string, userID int32, amount money.Money) (provider.Result, error) {
+	p.calls++
+	res, err := p.Fake.Authorize(ctx, key, userID, amount)
+	if p.calls <= p.lost {
+		return provider.Result{}, provider.ErrTimeout
+	}
+	return res, err
+}
+
+func newTestServer(repo *mocks.PaymentRepository, ledgerRepo *mocks.LedgerRepository, p provider.PaymentProvider) *PaymentServer {
+	return NewPaymentServer(repo, ledgerRepo, p, assignedDriver(7, 5), WithRetryBackoff(time.Millisecond))
+}
+
+// assignedDriver returns a driver-service client on which driverID is
+// assigned to bookingID.
+func assignedDriver(bookingID, driverID int32) *drivermocks.DriverServiceClient {
+	drivers := new(drivermocks.DriverServiceClient)
+	drivers.On("GetDriver", mock.Anything, &driverpb.GetDriverRequest{DriverId: driverID}).
+		Return(&driverpb.Driver{DriverId: driverID, Status: "ASSIGNED", BookingId: bookingID}, nil).Maybe()
+	return drivers
+}
+
+// journal matches a journal entry with the given idempotency key.
+func journal(key string) any {
+	return mock.MatchedBy(func(e ledger.Entry) bool {
+		return e.IdempotencyKey == key
+	})
+}
+
+// testEarning is booking 7's earning entry with driver 5 and 20% commission.
+func testEarning() *ledger.Entry {
+	return &ledger.Entry{
+		IdempotencyKey: "booking-7-earning",
+		Kind:           ledger.KindEarning,
+		Postings: []ledger.Posting{
+			ledger.Debit(ledger.ProviderClearing, rupees(500)),
+			ledger.Credit(ledger.DriverEarnings(5), rupees(400)),
+			ledger.Credit(ledger.PlatformRevenue, rupees(100)),
+		},
+	}
+}
+
+// authorizedPayment authorizes a payment at the fake provider and returns it as
+// the repository would record it.
+func authorizedPayment(t *testing.T, fake *provider.Fake) *repository.Payment {
+	t.Helper()
+	res, err := fake.Authorize(context.Background(), provider.AuthorizeKey(7), 3, rupees(500))
+	require.NoError(t, err)
+
+	payment := testPayment(repository.StatusAuthorized)
+	payment.ProviderRef = res.Reference
+	return payment
+}
+
+func TestAuthorizePayment_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+	ctx := context.Background()
+	req := &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3, Amount: money.ToProto(rupees(500))}
+
+	authorized := testPayment(repository.StatusAuthorized)
+	authorized.ProviderRef = "fake_auth_1"
+
+	// Expectations
+	mockRepo.On("Create", ctx, int32(7), int32(3), rupees(500), rupees(0)).Return(testPayment(repository.StatusPending), nil)
+	mockRepo.On("Record", ctx, int32(7), repository.LedgerEntry{
+		Type:           repository.EntryAuthorize,
+		Amount:         rupees(500),
+		ProviderRef:    "fake_auth_1",
+		IdempotencyKey: "booking-7-authorize",
+	}).Return(authorized, nil)
+
+	// Execute
+	res, err := paymentServer.AuthorizePayment(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusAuthorized, res.Status)
+	assert.Equal(t, int64(50000), res.Amount.MinorUnits)
+	assert.Equal(t, testCreatedAt, res.CreatedAt.AsTime())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthorizePayment_Declined(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{Decline: 1}, 1))
+
+	ctx := context.Background()
+	req := &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3, Amount: money.ToProto(rupees(500))}
+
+	declined := testPayment(repository.StatusDeclined)
+	declined.DeclineReason = "insufficient funds"
+
+	// Expectations
+	mockRepo.On("Create", ctx, int32(7), int32(3), rupees(500), rupees(0)).Return(testPayment(repository.StatusPending), nil)
+	mockRepo.On("Record", ctx, int32(7), mock.MatchedBy(func(e repository.LedgerEntry) bool {
+		return e.Type == repository.EntryDecline && e.Reason == "insufficient funds"
+	})).Return(declined, nil)
+
+	// Execute
+	res, err := paymentServer.AuthorizePayment(ctx, req)
+
+	// Assert
+	assert.Nil(t, res)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	assert.Contains(t, st.Message(), "insufficient funds")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthorizePayment_FullyDiscounted(t *testing.T) {
+	// Setup: any call to the provider would fail
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{Timeout: 1}, 1))
+
+	ctx := context.Background()
+	req := &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3,
+		Amount: money.ToProto(rupees(0)), Discount: money.ToProto(rupees(500))}
+
+	pending := testPayment(repository.StatusPending)
+	pending.Amount, pending.Discount = rupees(0), rupees(500)
+	authorized := testPayment(repository.StatusAuthorized)
+	authorized.Amount, authorized.Discount = rupees(0), rupees(500)
+
+	// Expectations: the payment is authorized without holding anything
+	mockRepo.On("Create", ctx, int32(7), int32(3), rupees(0), rupees(500)).Return(pending, nil)
+	mockRepo.On("Record", ctx, int32(7), mock.MatchedBy(func(e repository.LedgerEntry) bool {
+		return e.Type == repository.EntryAuthorize && e.ProviderRef == ""
+	})).Return(authorized, nil)
+
+	// Execute
+	res, err := paymentServer.AuthorizePayment(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusAuthorized, res.Status)
+	assert.Equal(t, int64(50000), res.Discount.MinorUnits)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthorizePayment_RetriesLostResponses(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	lossy := &lossyProvider{Fake: provider.NewFake(provider.FakeRates{}, 1), lost: 2}
+	paymentServer := newTestServer(mockRepo, mockLedger, lossy)
+
+	ctx := context.Background()
+	req := &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3, Amount: money.ToProto(rupees(500))}
+
+	// Expectations: the third attempt returns the first attempt's authorization
+	mockRepo.On("Create", ctx, int32(7), int32(3), rupees(500), rupees(0)).Return(testPayment(repository.StatusPending), nil)
+	mockRepo.On("Record", ctx, int32(7), mock.MatchedBy(func(e repository.LedgerEntry) bool {
+		return e.Type == repository.EntryAuthorize && e.ProviderRef == "fake_auth_1"
+	})).Return(testPayment(repository.StatusAuthorized), nil)
+
+	// Execute
+	res, err := paymentServer.AuthorizePayment(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusAuthorized, res.Status)
+	assert.Equal(t, 3, lossy.calls)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthorizePayment_ProviderUnavailable(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{Timeout: 1}, 1))
+
+	ctx := context.Background()
+	req := &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3, Amount: money.ToProto(rupees(500))}
+
+	// Expectations: the payment stays PENDING for a retry
+	mockRepo.On("Create", ctx, int32(7), int32(3), rupees(500), rupees(0)).Return(testPayment(repository.StatusPending), nil)
+
+	// Execute
+	res, err := paymentServer.AuthorizePayment(ctx, req)
+
+	// Assert
+	assert.Nil(t, res)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.Unavailable, st.Code())
+	mockRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthorizePayment_AlreadyAuthorized(t *testing.T) {
+	// Setup: any provider call would time out
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{Timeout: 1}, 1))
+
+	ctx := context.Background()
+	req := &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3, Amount: money.ToProto(rupees(500))}
+
+	// Expectations
+	mockRepo.On("Create", ctx, int32(7), int32(3), rupees(500), rupees(0)).Return(testPayment(repository.StatusAuthorized), nil)
+
+	// Execute
+	res, err := paymentServer.AuthorizePayment(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusAuthorized, res.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthorizePayment_InvalidRequest(t *testing.T) {
+	testCases := []struct {
+		name string
+		req  *pb.AuthorizePaymentRequest
+	}{
+		{name: "Missing Booking", req: &pb.AuthorizePaymentRequest{UserId: 3, Amount: money.ToProto(rupees(500))}},
+		{name: "Missing User", req: &pb.AuthorizePaymentRequest{BookingId: 7, Amount: money.ToProto(rupees(500))}},
+		{name: "Missing Amount", req: &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3}},
+		{name: "Zero Amount", req: &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3, Amount: money.ToProto(rupees(0))}},
+		{name: "Unknown Currency", req: &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3,
+			Amount: &moneypb.Money{CurrencyCode: "XXX", MinorUnits: 100}}},
+		{name: "Negative Discount", req: &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3,
+			Amount: money.ToProto(rupees(500)), Discount: money.ToProto(rupees(-100))}},
+		{name: "Discount In Another Currency", req: &pb.AuthorizePaymentRequest{BookingId: 7, UserId: 3,
+			Amount: money.ToProto(rupees(500)), Discount: &moneypb.Money{CurrencyCode: "USD", MinorUnits: 100}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.PaymentRepository)
+			mockLedger := new(mocks.LedgerRepository)
+			paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+			res, err := paymentServer.AuthorizePayment(context.Background(), tc.req)
+
+			assert.Nil(t, res)
+			st, _ := status.FromError(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCapturePayment_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	fake := provider.NewFake(provider.FakeRates{}, 1)
+	paymentServer := newTestServer(mockRepo, mockLedger, fake)
+
+	ctx := context.Background()
+	captured := testPayment(repository.StatusCaptured)
+	captured.Captured = rupees(500)
+
+	// Expectations: the driver earns the fare less 20% commission
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(authorizedPayment(t, fake), nil)
+	mockLedger.On("GetEntry", ctx, "booking-7-earning").Return(nil, errors.New("entry not found"))
+	mockRepo.On("Record", ctx, int32(7), entry(repository.EntryCapture, "booking-7-capture")).Return(captured, nil)
+	mockLedger.On("Post", ctx, mock.MatchedBy(func(e ledger.Entry) bool {
+		return assert.ObjectsAreEqual(testEarning().Postings, e.Postings) && e.IdempotencyKey == "booking-7-earning"
+	})).Return(testEarning(), nil)
+
+	// Execute
+	res, err := paymentServer.CapturePayment(ctx, &pb.CapturePaymentRequest{BookingId: 7, DriverId: 5})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusCaptured, res.Status)
+	assert.Equal(t, int64(50000), res.Captured.MinorUnits)
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestCapturePayment_FullyDiscounted(t *testing.T) {
+	// Setup: any call to the provider would fail
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{Timeout: 1}, 1))
+
+	ctx := context.Background()
+	authorized := testPayment(repository.StatusAuthorized)
+	authorized.Amount, authorized.Discount = rupees(0), rupees(500)
+	captured := testPayment(repository.StatusCaptured)
+	captured.Amount, captured.Discount = rupees(0), rupees(500)
+
+	// Expectations: the driver still earns their share of the fare, which the
+	// platform pays for out of its revenue
+	earning := []ledger.Posting{
+		ledger.Credit(ledger.DriverEarnings(5), rupees(400)),
+		ledger.Debit(ledger.PlatformRevenue, rupees(400)),
+	}
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(authorized, nil)
+	mockLedger.On("GetEntry", ctx, "booking-7-earning").Return(nil, errors.New("entry not found"))
+	mockRepo.On("Record", ctx, int32(7), entry(repository.EntryCapture, "booking-7-capture")).Return(captured, nil)
+	mockLedger.On("Post", ctx, mock.MatchedBy(func(e ledger.Entry) bool {
+		return assert.ObjectsAreEqual(earning, e.Postings) && e.IdempotencyKey == "booking-7-earning"
+	})).Return(testEarning(), nil)
+
+	// Execute
+	res, err := paymentServer.CapturePayment(ctx, &pb.CapturePaymentRequest{BookingId: 7, DriverId: 5})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusCaptured, res.Status)
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestCapturePayment_AlreadyCaptured(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := NewPaymentServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{Timeout: 1}, 1),
+		new(drivermocks.DriverServiceClient))
+
+	ctx := context.Background()
+	captured := testPayment(repository.StatusCaptured)
+	captured.Captured = rupees(500)
+
+	// Expectations: the earnings are posted again in case an earlier attempt
+	// failed to, which the ledger ignores if it did not. Since they were, the
+	// driver need no longer be assigned.
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(captured, nil)
+	mockLedger.On("GetEntry", ctx, "booking-7-earning").Return(testEarning(), nil)
+	mockLedger.On("Post", ctx, journal("booking-7-earning")).Return(testEarning(), nil)
+
+	// Execute
+	res, err := paymentServer.CapturePayment(ctx, &pb.CapturePaymentRequest{BookingId: 7, DriverId: 5})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusCaptured, res.Status)
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestCapturePayment_LedgerFailure(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	fake := provider.NewFake(provider.FakeRates{}, 1)
+	paymentServer := newTestServer(mockRepo, mockLedger, fake)
+
+	ctx := context.Background()
+	captured := testPayment(repository.StatusCaptured)
+	captured.Captured = rupees(500)
+
+	// Expectations
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(authorizedPayment(t, fake), nil)
+	mockLedger.On("GetEntry", ctx, "booking-7-earning").Return(nil, errors.New("entry not found"))
+	mockRepo.On("Record", ctx, int32(7), entry(repository.EntryCapture, "booking-7-capture")).Return(captured, nil)
+	mockLedger.On("Post", ctx, journal("booking-7-earning")).Return(nil, errors.New("connection reset"))
+
+	// Execute
+	res, err := paymentServer.CapturePayment(ctx, &pb.CapturePaymentRequest{BookingId: 7, DriverId: 5})
+
+	// Assert: the caller retries, which posts the earnings
+	assert.Nil(t, res)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.Internal, st.Code())
+}
+
+func TestCapturePayment_NotAuthorized(t *testing.T) {
+	for _, paymentStatus := range []string{repository.StatusPending, repository.StatusDeclined, repository.StatusVoided} {
+		t.Run(paymentStatus, func(t *testing.T) {
+			mockRepo := new(mocks.PaymentRepository)
+			mockLedger := new(mocks.LedgerRepository)
+			paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+			ctx := context.Background()
+			mockRepo.On("GetByBookingID", ctx, int32(7)).Return(testPayment(paymentStatus), nil)
+
+			res, err := paymentServer.CapturePayment(ctx, &pb.CapturePaymentRequest{BookingId: 7})
+
+			assert.Nil(t, res)
+			st, _ := status.FromError(err)
+			assert.Equal(t, codes.FailedPrecondition, st.Code())
+			mockRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCapturePayment_UnassignedDriver(t *testing.T) {
+	testCases := []struct {
+		name    string
+		drivers func() *drivermocks.DriverServiceClient
+		code    codes.Code
+	}{
+		{
+			name:    "Assigned To Another Booking",
+			drivers: func() *drivermocks.DriverServiceClient { return assignedDriver(8, 5) },
+			code:    codes.FailedPrecondition,
+		},
+		{
+			name: "Unknown Driver",
+			drivers: func() *drivermocks.DriverServiceClient {
+				drivers := new(drivermocks.DriverServiceClient)
+				drivers.On("GetDriver", mock.Anything, &driverpb.GetDriverRequest{DriverId: 5}).
+					Return(nil, status.Error(codes.NotFound, "driver not found"))
+				return drivers
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			mockRepo := new(mocks.PaymentRepository)
+			mockLedger := new(mocks.LedgerRepository)
+			fake := provider.NewFake(provider.FakeRates{}, 1)
+			paymentServer := NewPaymentServer(mockRepo, mockLedger, fake, tc.drivers())
+
+			ctx := context.Background()
+			mockRepo.On("GetByBookingID", ctx, int32(7)).Return(authorizedPayment(t, fake), nil)
+			mockLedger.On("GetEntry", ctx, "booking-7-earning").Return(nil, errors.New("entry not found"))
+
+			// Execute
+			res, err := paymentServer.CapturePayment(ctx, &pb.CapturePaymentRequest{BookingId: 7, DriverId: 5})
+
+			// Assert: nothing is captured or credited
+			assert.Nil(t, res)
+			assert.Equal(t, tc.code, status.Code(err))
+			mockRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+			mockLedger.AssertNotCalled(t, "Post", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestVoidPayment_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	fake := provider.NewFake(provider.FakeRates{}, 1)
+	paymentServer := newTestServer(mockRepo, mockLedger, fake)
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(authorizedPayment(t, fake), nil)
+	mockRepo.On("Record", ctx, int32(7), entry(repository.EntryVoid, "booking-7-void")).
+		Return(testPayment(repository.StatusVoided), nil)
+
+	// Execute
+	res, err := paymentServer.VoidPayment(ctx, &pb.VoidPaymentRequest{BookingId: 7})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusVoided, res.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVoidPayment_PendingAfterLostResponse(t *testing.T) {
+	// Setup: the provider authorized the booking but the response was lost,
+	// leaving the payment PENDING
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	fake := provider.NewFake(provider.FakeRates{}, 1)
+	paymentServer := newTestServer(mockRepo, mockLedger, fake)
+
+	ctx := context.Background()
+	authorized := authorizedPayment(t, fake)
+
+	// Expectations: the authorization is recorded, then voided
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(testPayment(repository.StatusPending), nil)
+	mockRepo.On("Record", ctx, int32(7), mock.MatchedBy(func(e repository.LedgerEntry) bool {
+		return e.Type == repository.EntryAuthorize && e.ProviderRef == authorized.ProviderRef
+	})).Return(authorized, nil)
+	mockRepo.On("Record", ctx, int32(7), entry(repository.EntryVoid, "booking-7-void")).
+		Return(testPayment(repository.StatusVoided), nil)
+
+	// Execute
+	res, err := paymentServer.VoidPayment(ctx, &pb.VoidPaymentRequest{BookingId: 7})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusVoided, res.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVoidPayment_Captured(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(testPayment(repository.StatusCaptured), nil)
+
+	// Execute
+	res, err := paymentServer.VoidPayment(ctx, &pb.VoidPaymentRequest{BookingId: 7})
+
+	// Assert
+	assert.Nil(t, res)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+}
+
+// capturedPayment captures an authorized payment at the fake provider.
+func capturedPayment(t *testing.T, fake *provider.Fake) *repository.Payment {
+	t.Helper()
+	payment := authorizedPayment(t, fake)
+	_, err := fake.Capture(context.Background(), provider.CaptureKey(7), payment.ProviderRef, payment.Amount)
+	require.NoError(t, err)
+
+	payment.Status = repository.StatusCaptured
+	payment.Captured = payment.Amount
+	return payment
+}
+
+func TestRefundPayment_Partial(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	fake := provider.NewFake(provider.FakeRates{}, 1)
+	paymentServer := newTestServer(mockRepo, mockLedger, fake)
+
+	ctx := context.Background()
+	payment := capturedPayment(t, fake)
+	payment.Refunded = rupees(100)
+	payment.Refunds = 1
+
+	refunded := *payment
+	refunded.Refunded = rupees(300)
+	refunded.Refunds = 2
+
+	// Expectations: the second refund gets the second refund key, and takes
+	// it back from the driver and the platform in proportion
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(payment, nil)
+	mockLedger.On("GetEntry", ctx, "booking-7-earning").Return(testEarning(), nil)
+	mockLedger.On("Post", ctx, mock.MatchedBy(func(e ledger.Entry) bool {
+		return e.IdempotencyKey == "booking-7-refund-2" && e.Memo == "detour" && assert.ObjectsAreEqual([]ledger.Posting{
+			ledger.Credit(ledger.ProviderClearing, rupees(200)),
+			ledger.Debit(ledger.DriverEarnings(5), rupees(160)),
+			ledger.Debit(ledger.PlatformRevenue, rupees(40)),
+		}, e.Postings)
+	})).Return(&ledger.Entry{}, nil)
+	mockRepo.On("Record", ctx, int32(7), mock.MatchedBy(func(e repository.LedgerEntry) bool {
+		return e.Type == repository.EntryRefund && e.IdempotencyKey == "booking-7-refund-2" &&
+			e.Amount == rupees(200) && e.Reason == "detour"
+	})).Return(&refunded, nil)
+
+	// Execute
+	res, err := paymentServer.RefundPayment(ctx, &pb.RefundPaymentRequest{
+		BookingId: 7,
+		Amount:    money.ToProto(rupees(200)),
+		Reason:    "detour",
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusCaptured, res.Status)
+	assert.Equal(t, int64(30000), res.Refunded.MinorUnits)
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestRefundPayment_Remaining(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	fake := provider.NewFake(provider.FakeRates{}, 1)
+	paymentServer := newTestServer(mockRepo, mockLedger, fake)
+
+	ctx := context.Background()
+	payment := capturedPayment(t, fake)
+
+	refunded := *payment
+	refunded.Status = repository.StatusRefunded
+	refunded.Refunded = rupees(500)
+
+	// Expectations
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(payment, nil)
+	mockLedger.On("GetEntry", ctx, "booking-7-earning").Return(testEarning(), nil)
+	mockLedger.On("Post", ctx, journal("booking-7-refund-1")).Return(&ledger.Entry{}, nil)
+	mockRepo.On("Record", ctx, int32(7), mock.MatchedBy(func(e repository.LedgerEntry) bool {
+		return e.Type == repository.EntryRefund && e.Amount == rupees(500)
+	})).Return(&refunded, nil)
+
+	// Execute
+	res, err := paymentServer.RefundPayment(ctx, &pb.RefundPaymentRequest{BookingId: 7, Reason: "dispute"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusRefunded, res.Status)
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestRefundPayment_EarningsNotPosted(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	fake := provider.NewFake(provider.FakeRates{}, 1)
+	paymentServer := newTestServer(mockRepo, mockLedger, fake)
+
+	ctx := context.Background()
+
+	// Expectations: nothing is refunded until the capture is retried
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(capturedPayment(t, fake), nil)
+	mockLedger.On("GetEntry", ctx, "booking-7-earning").Return(nil, errors.New("entry not found"))
+
+	// Execute
+	res, err := paymentServer.RefundPayment(ctx, &pb.RefundPaymentRequest{BookingId: 7})
+
+	// Assert
+	assert.Nil(t, res)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	mockLedger.AssertNotCalled(t, "Post", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRefundPayment_AlreadyRefunded(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{Timeout: 1}, 1))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(testPayment(repository.StatusRefunded), nil)
+
+	// Execute
+	res, err := paymentServer.RefundPayment(ctx, &pb.RefundPaymentRequest{BookingId: 7})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, repository.StatusRefunded, res.Status)
+}
+
+func TestRefundPayment_InvalidAmount(t *testing.T) {
+	testCases := []struct {
+		name   string
+		amount *moneypb.Money
+	}{
+		{name: "Exceeds Capture", amount: money.ToProto(rupees(501))},
+		{name: "Negative", amount: money.ToProto(rupees(-1))},
+		{name: "Other Currency", amount: &moneypb.Money{CurrencyCode: "USD", MinorUnits: 100}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.PaymentRepository)
+			mockLedger := new(mocks.LedgerRepository)
+			fake := provider.NewFake(provider.FakeRates{}, 1)
+			paymentServer := newTestServer(mockRepo, mockLedger, fake)
+
+			ctx := context.Background()
+			mockRepo.On("GetByBookingID", ctx, int32(7)).Return(capturedPayment(t, fake), nil)
+
+			res, err := paymentServer.RefundPayment(ctx, &pb.RefundPaymentRequest{BookingId: 7, Amount: tc.amount})
+
+			assert.Nil(t, res)
+			st, _ := status.FromError(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+			mockRepo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestRefundPayment_NotCaptured(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(testPayment(repository.StatusAuthorized), nil)
+
+	// Execute
+	res, err := paymentServer.RefundPayment(ctx, &pb.RefundPaymentRequest{BookingId: 7})
+
+	// Assert
+	assert.Nil(t, res)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+}
+
+func TestGetPayment_NotFound(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockRepo.On("GetByBookingID", ctx, int32(7)).Return(nil, errors.New("payment not found"))
+
+	// Execute
+	res, err := paymentServer.GetPayment(ctx, &pb.GetPaymentRequest{BookingId: 7})
+
+	// Assert
+	assert.Nil(t, res)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTopUpWallet_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+	ctx := context.Background()
+	req := &pb.TopUpWalletRequest{UserId: 3, Amount: money.ToProto(rupees(1000)), IdempotencyKey: "abc"}
+
+	// Expectations
+	mockLedger.On("Post", ctx, ledger.TopUp(3, rupees(1000), "abc")).Return(&ledger.Entry{}, nil)
+	mockLedger.On("GetAccount", ctx, "user:3:wallet").
+		Return(&ledger.Account{Code: "user:3:wallet", Type: ledger.TypeLiability, Balance: rupees(1500)}, nil)
+
+	// Execute
+	res, err := paymentServer.TopUpWallet(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), res.UserId)
+	assert.Equal(t, int64(150000), res.Balance.MinorUnits)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestTopUpWallet_Declined(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{Decline: 1}, 1))
+
+	ctx := context.Background()
+	req := &pb.TopUpWalletRequest{UserId: 3, Amount: money.ToProto(rupees(1000)), IdempotencyKey: "abc"}
+
+	// Execute
+	res, err := paymentServer.TopUpWallet(ctx, req)
+
+	// Assert
+	assert.Nil(t, res)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	mockLedger.AssertNotCalled(t, "Post", mock.Anything, mock.Anything)
+}
+
+func TestTopUpWallet_InvalidRequest(t *testing.T) {
+	testCases := []struct {
+		name string
+		req  *pb.TopUpWalletRequest
+	}{
+		{name: "Missing User", req: &pb.TopUpWalletRequest{Amount: money.ToProto(rupees(10)), IdempotencyKey: "abc"}},
+		{name: "Missing Amount", req: &pb.TopUpWalletRequest{UserId: 3, IdempotencyKey: "abc"}},
+		{name: "Negative Amount", req: &pb.TopUpWalletRequest{UserId: 3, Amount: money.ToProto(rupees(-10)), IdempotencyKey: "abc"}},
+		{name: "Missing Key", req: &pb.TopUpWalletRequest{UserId: 3, Amount: money.ToProto(rupees(10))}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.PaymentRepository)
+			mockLedger := new(mocks.LedgerRepository)
+			paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+			res, err := paymentServer.TopUpWallet(context.Background(), tc.req)
+
+			assert.Nil(t, res)
+			st, _ := status.FromError(err)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+		})
+	}
+}
+
+func TestDebitWallet_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+	ctx := context.Background()
+	req := &pb.DebitWalletRequest{UserId: 3, Amount: money.ToProto(rupees(200)), IdempotencyKey: "abc", Reason: "tip"}
+
+	// Expectations
+	mockLedger.On("Post", ctx, ledger.WalletDebit(3, rupees(200), "abc", "tip")).Return(&ledger.Entry{}, nil)
+	mockLedger.On("GetAccount", ctx, "user:3:wallet").
+		Return(&ledger.Account{Code: "user:3:wallet", Type: ledger.TypeLiability, Balance: rupees(800)}, nil)
+
+	// Execute
+	res, err := paymentServer.DebitWallet(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(80000), res.Balance.MinorUnits)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestDebitWallet_Errors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		postErr      error
+		expectedCode codes.Code
+	}{
+		{name: "Insufficient Funds", postErr: errors.New("insufficient funds"), expectedCode: codes.FailedPrecondition},
+		{name: "Other Currency", postErr: money.ErrCurrencyMismatch, expectedCode: codes.FailedPrecondition},
+		{name: "Database Error", postErr: errors.New("connection reset"), expectedCode: codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(mocks.PaymentRepository)
+			mockLedger := new(mocks.LedgerRepository)
+			paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+			ctx := context.Background()
+			mockLedger.On("Post", ctx, journal("wallet-3-debit-abc")).Return(nil, tc.postErr)
+
+			res, err := paymentServer.DebitWallet(ctx, &pb.DebitWalletRequest{
+				UserId:         3,
+				Amount:         money.ToProto(rupees(200)),
+				IdempotencyKey: "abc",
+			})
+
+			assert.Nil(t, res)
+			st, _ := status.FromError(err)
+			assert.Equal(t, tc.expectedCode, st.Code())
+		})
+	}
+}
+
+func TestGetWallet_NotFound(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockLedger.On("GetAccount", ctx, "user:3:wallet").Return(nil, errors.New("account not found"))
+
+	// Execute
+	res, err := paymentServer.GetWallet(ctx, &pb.GetWalletRequest{UserId: 3})
+
+	// Assert
+	assert.Nil(t, res)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+}
+
+func TestGetDriverEarnings_Success(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.PaymentRepository)
+	mockLedger := new(mocks.LedgerRepository)
+	paymentServer := newTestServer(mockRepo, mockLedger, provider.NewFake(provider.FakeRates{}, 1))
+
+	ctx := context.Background()
+
+	// Expectations
+	mockLedger.On("GetAccount", ctx, "driver:5:earnings").
+		Return(&ledger.Account{Code: "driver:5:earnings", Type: ledger.TypeLiability, Balance: rupees(400)}, nil)
+
+	// Execute
+	res, err := paymentServer.GetDriverEarnings(ctx, &pb.GetDriverEarningsRequest{DriverId: 5})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(5), res.DriverId)
+	assert.Equal(t, int64(40000), res.Balance.MinorUnits)
+}
+
+func TestPaymentApply(t *testing.T) {
+	payment := testPayment(repository.StatusPending)
+
+	require.NoError(t, payment.Apply(repository.LedgerEntry{Type: repository.EntryAuthorize, ProviderRef: "auth"}))
+	assert.Equal(t, repository.StatusAuthorized, payment.Status)
+	assert.Error(t, payment.Apply(repository.LedgerEntry{Type: repository.EntryRefund, Amount: rupees(1)}))
+
+	require.NoError(t, payment.Apply(repository.LedgerEntry{Type: repository.EntryCapture, Amount: rupees(500)}))
+	require.NoError(t, payment.Apply(repository.LedgerEntry{Type: repository.EntryRefund, Amount: rupees(200)}))
+	assert.Equal(t, repository.StatusCaptured, payment.Status)
+	assert.Error(t, payment.Apply(repository.LedgerEntry{Type: repository.EntryRefund, Amount: rupees(301)}))
+
+	require.NoError(t, payment.Apply(repository.LedgerEntry{Type: repository.EntryRefund, Amount: rupees(300)}))
+	assert.Equal(t, repository.StatusRefunded, payment.Status)
+	assert.Equal(t, int32(2), payment.Refunds)
+	assert.Error(t, payment.Apply(repository.LedgerEntry{Type: repository.EntryVoid}))
+}
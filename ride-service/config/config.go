@@ -3,12 +3,43 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"log"
+	"time"
 	"github.com/joho/godotenv"
+
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
 )
 
 type Config struct {
-	DBUrl string
+	DBUrl     string
+	BrokerURL string
+
+	// QuoteSigningKey signs fare quotes; it must be set.
+	QuoteSigningKey string
+	QuoteTTL        time.Duration
+	PricingTimezone string
+
+	// RoadGraphPath points at an offline road graph used to check ride
+	// distances; straight-line distances are used when it is empty.
+	RoadGraphPath string
+
+	// Surge pricing: booking requests per source area within SurgeWindow
+	// select a multiplier from SurgeTiers ("requests:multiplier_bp,..."),
+	// never above SurgeCapBP. Quoted surge levels hold for SurgeValidity.
+	SurgeWindow   time.Duration
+	SurgeValidity time.Duration
+	SurgeTiers    string
+	SurgeCapBP    int32
+
+	// DBPool holds the DB_* connection pool settings.
+	DBPool database.PoolConfig
+	// Log holds the LOG_* logging settings.
+	Log logger.Settings
+	// AdminToken is the bearer token of the admin server's debug endpoints
+	// and of QueryAuditLog, which are disabled while it is empty.
+	AdminToken string
 }
 
 func Load() Config {
@@ -26,5 +57,61 @@ func Load() Config {
 	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		dbUser, dbPass, dbHost, dbPort, dbName)
 
-	return Config{DBUrl: dbUrl}
+	dbPool, err := database.PoolConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logSettings, err := logger.SettingsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return Config{
+		DBUrl:           dbUrl,
+		BrokerURL:       os.Getenv("BROKER_URL"),
+		QuoteSigningKey: os.Getenv("QUOTE_SIGNING_KEY"),
+		QuoteTTL:        getDuration("QUOTE_TTL", 5*time.Minute),
+		PricingTimezone: getEnv("PRICING_TIMEZONE", "Asia/Karachi"),
+		RoadGraphPath:   os.Getenv("ROAD_GRAPH_PATH"),
+		SurgeWindow:     getDuration("SURGE_WINDOW", 10*time.Minute),
+		SurgeValidity:   getDuration("SURGE_VALIDITY", 2*time.Minute),
+		SurgeTiers:      getEnv("SURGE_TIERS", "10:12500,20:15000,40:20000"),
+		SurgeCapBP:      int32(getInt("SURGE_CAP_BP", 20000)),
+
+		DBPool:     dbPool,
+		Log:        logSettings,
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, value, err)
+	}
+	return d
+}
+
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s %q: %v", key, value, err)
+	}
+	return n
 }
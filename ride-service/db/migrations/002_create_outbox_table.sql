@@ -0,0 +1,11 @@
+CREATE TABLE outbox (
+  event_id BIGSERIAL PRIMARY KEY,
+  aggregate_type TEXT NOT NULL,
+  aggregate_id TEXT NOT NULL,
+  event_type TEXT NOT NULL,
+  payload JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  delivered_at TIMESTAMP
+);
+
+CREATE INDEX outbox_undelivered_idx ON outbox (event_id) WHERE delivered_at IS NULL;
@@ -0,0 +1,31 @@
+CREATE TABLE tariffs (
+  tariff_id SERIAL PRIMARY KEY,
+  vehicle_class TEXT NOT NULL,
+  version INT NOT NULL,
+  base_fare INT NOT NULL CHECK (base_fare >= 0),
+  per_km_rate INT NOT NULL CHECK (per_km_rate >= 0),
+  minimum_fare INT NOT NULL CHECK (minimum_fare >= 0),
+  effective_from TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (vehicle_class, version)
+);
+
+-- Time-of-day multipliers in basis points (10000 = 1x). A band whose end_hour
+-- is not after its start_hour wraps past midnight.
+CREATE TABLE tariff_time_bands (
+  tariff_id INT NOT NULL REFERENCES tariffs (tariff_id),
+  start_hour INT NOT NULL CHECK (start_hour BETWEEN 0 AND 23),
+  end_hour INT NOT NULL CHECK (end_hour BETWEEN 0 AND 24),
+  multiplier_bp INT NOT NULL CHECK (multiplier_bp > 0)
+);
+
+ALTER TABLE rides ADD COLUMN vehicle_class TEXT NOT NULL DEFAULT 'ECONOMY';
+ALTER TABLE rides ADD COLUMN tariff_version INT;
+
+INSERT INTO tariffs (vehicle_class, version, base_fare, per_km_rate, minimum_fare, effective_from) VALUES
+('ECONOMY', 1, 500, 4, 300, '2000-01-01'),
+('COMFORT', 1, 700, 6, 500, '2000-01-01'),
+('PREMIUM', 1, 1000, 9, 800, '2000-01-01');
+
+INSERT INTO tariff_time_bands (tariff_id, start_hour, end_hour, multiplier_bp)
+SELECT tariff_id, band.start_hour, band.end_hour, band.multiplier_bp
+FROM tariffs, (VALUES (7, 10, 12000), (17, 20, 12500), (23, 5, 11000)) AS band (start_hour, end_hour, multiplier_bp);
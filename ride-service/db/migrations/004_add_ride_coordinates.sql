@@ -0,0 +1,6 @@
+-- Pickup and drop-off coordinates; NULL for rides created without them
+ALTER TABLE rides
+    ADD COLUMN source_lat DOUBLE PRECISION,
+    ADD COLUMN source_lng DOUBLE PRECISION,
+    ADD COLUMN destination_lat DOUBLE PRECISION,
+    ADD COLUMN destination_lng DOUBLE PRECISION;
@@ -0,0 +1,4 @@
+-- Canonical gazetteer place IDs; NULL when the free-text place was not recognised
+ALTER TABLE rides
+    ADD COLUMN source_place_id VARCHAR(32),
+    ADD COLUMN destination_place_id VARCHAR(32);
@@ -0,0 +1,4 @@
+-- Existing rides predate tracking and get the migration time
+ALTER TABLE rides
+    ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
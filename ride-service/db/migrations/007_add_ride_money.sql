@@ -0,0 +1,11 @@
+-- Ride prices in minor units with their currency. cost keeps whole units for
+-- instances that predate these columns, which leave them NULL; it can be
+-- dropped once every instance reads cost_minor.
+ALTER TABLE rides
+    ADD COLUMN cost_minor BIGINT,
+    ADD COLUMN currency CHAR(3);
+
+UPDATE rides SET cost_minor = cost::BIGINT * 100, currency = 'PKR';
+
+-- Tariff amounts are whole units of the tariff's currency
+ALTER TABLE tariffs ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'PKR';
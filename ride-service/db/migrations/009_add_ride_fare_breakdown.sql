@@ -0,0 +1,10 @@
+-- How each ride's fare was computed, in minor units of the ride's currency and
+-- basis points; NULL for rides booked without a breakdown
+ALTER TABLE rides
+    ADD COLUMN base_fare_minor BIGINT,
+    ADD COLUMN distance_fare_minor BIGINT,
+    ADD COLUMN minimum_fare_adjustment_minor BIGINT,
+    ADD COLUMN time_of_day_multiplier_bp INTEGER,
+    ADD COLUMN time_of_day_adjustment_minor BIGINT,
+    ADD COLUMN surge_multiplier_bp INTEGER,
+    ADD COLUMN surge_adjustment_minor BIGINT;
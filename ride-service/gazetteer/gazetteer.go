@@ -0,0 +1,261 @@
+package gazetteer
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"ride-service/geo"
+)
+
+//go:embed places.csv
+var bundledPlaces []byte
+
+// Place is a canonical named location.
+type Place struct {
+	ID         string
+	Name       string
+	Location   geo.Point
+	Population int64
+	Aliases    []string
+}
+
+// Gazetteer resolves free-text place names, including aliases such as airport
+// codes, to canonical places.
+type Gazetteer struct {
+	places []*Place
+	// keys maps every normalized name and alias to its place
+	keys map[string]*Place
+}
+
+// Default returns the gazetteer bundled with the service.
+func Default() (*Gazetteer, error) {
+	return Load(bytes.NewReader(bundledPlaces))
+}
+
+// Load reads places from CSV with the header
+// id,name,lat,lng,population,aliases where aliases are separated by |.
+func Load(r io.Reader) (*Gazetteer, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("gazetteer has no places")
+	}
+
+	g := &Gazetteer{keys: make(map[string]*Place)}
+	for i, record := range records[1:] {
+		place, err := parsePlace(record)
+		if err != nil {
+			return nil, fmt.Errorf("gazetteer row %d: %w", i+2, err)
+		}
+
+		for _, name := range append([]string{place.Name}, place.Aliases...) {
+			key := Normalize(name)
+			if other, exists := g.keys[key]; exists {
+				return nil, fmt.Errorf("gazetteer row %d: %q already names %s", i+2, name, other.ID)
+			}
+			g.keys[key] = place
+		}
+		g.places = append(g.places, place)
+	}
+
+	return g, nil
+}
+
+func parsePlace(record []string) (*Place, error) {
+	if len(record) != 6 {
+		return nil, fmt.Errorf("expected 6 columns, got %d", len(record))
+	}
+	if record[0] == "" || record[1] == "" {
+		return nil, fmt.Errorf("id and name are required")
+	}
+
+	lat, err := strconv.ParseFloat(record[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude %q", record[2])
+	}
+	lng, err := strconv.ParseFloat(record[3], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude %q", record[3])
+	}
+	location := geo.Point{Lat: lat, Lng: lng}
+	if err := location.Validate(); err != nil {
+		return nil, err
+	}
+	population, err := strconv.ParseInt(record[4], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid population %q", record[4])
+	}
+
+	var aliases []string
+	for _, alias := range strings.Split(record[5], "|") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return &Place{
+		ID:         record[0],
+		Name:       record[1],
+		Location:   location,
+		Population: population,
+		Aliases:    aliases,
+	}, nil
+}
+
+// Normalize folds case, drops punctuation and collapses whitespace, so that
+// "Karachi", " karachi " and "KARACHI." compare equal.
+func Normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// Lookup resolves text to a place by exact name or alias after normalization.
+func (g *Gazetteer) Lookup(text string) (*Place, bool) {
+	place, ok := g.keys[Normalize(text)]
+	return place, ok
+}
+
+// OtherArea is the area of every source the gazetteer does not recognise.
+const OtherArea = "other"
+
+// AreaKey identifies the area a source belongs to: its place ID when known,
+// otherwise OtherArea, so that there are never more areas than places.
+func (g *Gazetteer) AreaKey(source string) string {
+	if place, ok := g.Lookup(source); ok {
+		return place.ID
+	}
+	return OtherArea
+}
+
+// Search returns up to limit places matching query, best first. Names and
+// aliases match exactly, by prefix, by word prefix or substring, and
+// within a small edit distance of a typed prefix to tolerate typos. Ties
+// are broken by population.
+func (g *Gazetteer) Search(query string, limit int) []*Place {
+	q := Normalize(query)
+	if q == "" || limit <= 0 {
+		return nil
+	}
+
+	type match struct {
+		place *Place
+		score int
+	}
+	var matches []match
+	for _, place := range g.places {
+		best := -1
+		for _, name := range append([]string{place.Name}, place.Aliases...) {
+			if score := matchScore(q, Normalize(name)); score >= 0 && (best < 0 || score < best) {
+				best = score
+			}
+		}
+		if best >= 0 {
+			matches = append(matches, match{place: place, score: best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].place.Population > matches[j].place.Population
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	places := make([]*Place, len(matches))
+	for i, m := range matches {
+		places[i] = m.place
+	}
+	return places
+}
+
+// matchScore ranks how well query matches key; lower is better and -1 means
+// no match.
+func matchScore(query, key string) int {
+	switch {
+	case key == query:
+		return 0
+	case strings.HasPrefix(key, query):
+		return 1
+	case strings.Contains(" "+key, " "+query):
+		return 2
+	case strings.Contains(key, query):
+		return 3
+	}
+
+	// Compare against the key's prefix of the same length, so a typo early in
+	// a partially typed name still matches.
+	keyRunes, queryRunes := []rune(key), []rune(query)
+	maxEdits := maxTypos(len(queryRunes))
+	if maxEdits == 0 {
+		return -1
+	}
+	best := -1
+	for _, n := range []int{len(queryRunes) - 1, len(queryRunes), len(queryRunes) + 1} {
+		if n <= 0 || n > len(keyRunes) {
+			continue
+		}
+		if d := levenshtein(queryRunes, keyRunes[:n]); d <= maxEdits && (best < 0 || d < best) {
+			best = d
+		}
+	}
+	if best < 0 {
+		return -1
+	}
+	return 3 + best
+}
+
+func maxTypos(length int) int {
+	switch {
+	case length >= 8:
+		return 2
+	case length >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,82 @@
+package gazetteer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func placeIDs(places []*Place) []string {
+	ids := make([]string, len(places))
+	for i, p := range places {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "karachi", Normalize(" Karachi "))
+	assert.Equal(t, "karachi", Normalize("KARACHI."))
+	assert.Equal(t, "rahim yar khan", Normalize("Rahim-Yar   Khan"))
+	assert.Equal(t, "", Normalize(" ,. "))
+}
+
+func TestLookup(t *testing.T) {
+	g, err := Default()
+	require.NoError(t, err)
+
+	for _, text := range []string{"Karachi", "karachi ", "KHI", "city of lights"} {
+		place, ok := g.Lookup(text)
+		require.True(t, ok, text)
+		assert.Equal(t, "pk-khi", place.ID)
+	}
+
+	_, ok := g.Lookup("Karach")
+	assert.False(t, ok, "lookup does not fuzzy match")
+
+	assert.Equal(t, "pk-rwp", g.AreaKey("Pindi"))
+	assert.Equal(t, OtherArea, g.AreaKey(" New York"))
+	assert.Equal(t, OtherArea, g.AreaKey("Gate 4, Sector F-7"))
+}
+
+func TestSearch(t *testing.T) {
+	g, err := Default()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		query    string
+		limit    int
+		expected []string
+	}{
+		{name: "Exact Alias", query: "lhe", limit: 5, expected: []string{"pk-lhe"}},
+		{name: "Prefix Ranked By Population", query: "mu", limit: 5, expected: []string{"pk-mux", "pk-mzd", "pk-mre"}},
+		{name: "Word Prefix", query: "khan", limit: 5, expected: []string{"pk-ryk"}},
+		{name: "Typo", query: "Karchi", limit: 5, expected: []string{"pk-khi"}},
+		{name: "Typo In Partial Name", query: "Islamab", limit: 5, expected: []string{"pk-isb"}},
+		{name: "Prefix Before Substring", query: "a", limit: 2, expected: []string{"pk-abt", "pk-khi"}},
+		{name: "No Match", query: "zzzz", limit: 5, expected: []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, placeIDs(g.Search(tc.query, tc.limit)))
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	header := "id,name,lat,lng,population,aliases\n"
+	for _, input := range []string{
+		header,
+		header + "x,X,0,0,1\n",
+		header + "x,X,95,0,1,\n",
+		header + "x,X,0,0,many,\n",
+		header + "x,X,0,0,1,Y\ny,Y,0,0,1,\n",
+	} {
+		_, err := Load(strings.NewReader(input))
+		assert.Error(t, err, input)
+	}
+}
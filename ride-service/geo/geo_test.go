@@ -0,0 +1,25 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	karachi = Point{Lat: 24.8607, Lng: 67.0011}
+	lahore  = Point{Lat: 31.5204, Lng: 74.3587}
+)
+
+func TestHaversine(t *testing.T) {
+	assert.InDelta(t, 1033, Haversine(karachi, lahore), 1)
+	assert.InDelta(t, Haversine(karachi, lahore), Haversine(lahore, karachi), 1e-9)
+	assert.Zero(t, Haversine(karachi, karachi))
+}
+
+func TestPoint_Validate(t *testing.T) {
+	assert.NoError(t, karachi.Validate())
+	assert.NoError(t, Point{Lat: -90, Lng: 180}.Validate())
+	assert.Error(t, Point{Lat: 91, Lng: 0}.Validate())
+	assert.Error(t, Point{Lat: 0, Lng: -181}.Validate())
+}
@@ -0,0 +1,205 @@
+package geo
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// RoadGraph is an offline RoutingProvider over a road network of nodes joined
+// by two-way edges. Points are snapped to their nearest node, and the distance
+// is the shortest path between the snapped nodes plus the straight-line legs
+// to reach them.
+type RoadGraph struct {
+	nodes []graphNode
+	index map[string]int
+}
+
+type graphNode struct {
+	id    string
+	point Point
+	edges []graphEdge
+}
+
+type graphEdge struct {
+	to       int
+	lengthKm float64
+}
+
+// LoadRoadGraph reads a road graph file. See ParseRoadGraph for the format.
+func LoadRoadGraph(path string) (*RoadGraph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseRoadGraph(f)
+}
+
+// ParseRoadGraph reads a road graph with one record per line:
+//
+//	node <id> <lat> <lng>
+//	edge <from-id> <to-id> [length-km]
+//
+// Edges without a length use the haversine distance between their nodes.
+// Blank lines and lines starting with # are ignored.
+func ParseRoadGraph(r io.Reader) (*RoadGraph, error) {
+	g := &RoadGraph{index: make(map[string]int)}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := g.parseLine(strings.Fields(line)); err != nil {
+			return nil, fmt.Errorf("road graph line %d: %w", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(g.nodes) == 0 {
+		return nil, fmt.Errorf("road graph has no nodes")
+	}
+
+	return g, nil
+}
+
+func (g *RoadGraph) parseLine(fields []string) error {
+	switch fields[0] {
+	case "node":
+		if len(fields) != 4 {
+			return fmt.Errorf("node needs an id, latitude and longitude")
+		}
+		if _, exists := g.index[fields[1]]; exists {
+			return fmt.Errorf("duplicate node %q", fields[1])
+		}
+		lat, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return fmt.Errorf("invalid latitude %q", fields[2])
+		}
+		lng, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil {
+			return fmt.Errorf("invalid longitude %q", fields[3])
+		}
+		point := Point{Lat: lat, Lng: lng}
+		if err := point.Validate(); err != nil {
+			return err
+		}
+
+		g.index[fields[1]] = len(g.nodes)
+		g.nodes = append(g.nodes, graphNode{id: fields[1], point: point})
+
+	case "edge":
+		if len(fields) != 3 && len(fields) != 4 {
+			return fmt.Errorf("edge needs two node ids and an optional length")
+		}
+		from, ok := g.index[fields[1]]
+		if !ok {
+			return fmt.Errorf("unknown node %q", fields[1])
+		}
+		to, ok := g.index[fields[2]]
+		if !ok {
+			return fmt.Errorf("unknown node %q", fields[2])
+		}
+		length := Haversine(g.nodes[from].point, g.nodes[to].point)
+		if len(fields) == 4 {
+			var err error
+			length, err = strconv.ParseFloat(fields[3], 64)
+			if err != nil || length < 0 {
+				return fmt.Errorf("invalid edge length %q", fields[3])
+			}
+		}
+
+		g.nodes[from].edges = append(g.nodes[from].edges, graphEdge{to: to, lengthKm: length})
+		g.nodes[to].edges = append(g.nodes[to].edges, graphEdge{to: from, lengthKm: length})
+
+	default:
+		return fmt.Errorf("unknown record %q", fields[0])
+	}
+	return nil
+}
+
+// Distance returns the road distance in km from one point to another, or
+// ErrNoRoute if their nearest nodes are not connected.
+func (g *RoadGraph) Distance(ctx context.Context, from, to Point) (float64, error) {
+	start, startLeg := g.nearest(from)
+	end, endLeg := g.nearest(to)
+
+	path, err := g.shortestPath(ctx, start, end)
+	if err != nil {
+		return 0, err
+	}
+	return startLeg + path + endLeg, nil
+}
+
+func (g *RoadGraph) nearest(p Point) (int, float64) {
+	best, bestDistance := 0, math.Inf(1)
+	for i, node := range g.nodes {
+		if d := Haversine(p, node.point); d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return best, bestDistance
+}
+
+// shortestPath runs Dijkstra's algorithm from start to end.
+func (g *RoadGraph) shortestPath(ctx context.Context, start, end int) (float64, error) {
+	dist := make([]float64, len(g.nodes))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[start] = 0
+
+	queue := &distanceQueue{{node: start}}
+	for queue.Len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		current := heap.Pop(queue).(queueItem)
+		if current.node == end {
+			return current.distance, nil
+		}
+		if current.distance > dist[current.node] {
+			continue
+		}
+
+		for _, edge := range g.nodes[current.node].edges {
+			if d := current.distance + edge.lengthKm; d < dist[edge.to] {
+				dist[edge.to] = d
+				heap.Push(queue, queueItem{node: edge.to, distance: d})
+			}
+		}
+	}
+	return 0, ErrNoRoute
+}
+
+type queueItem struct {
+	node     int
+	distance float64
+}
+
+type distanceQueue []queueItem
+
+func (q distanceQueue) Len() int           { return len(q) }
+func (q distanceQueue) Less(i, j int) bool { return q[i].distance < q[j].distance }
+func (q distanceQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *distanceQueue) Push(x any)        { *q = append(*q, x.(queueItem)) }
+func (q *distanceQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
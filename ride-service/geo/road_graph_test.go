@@ -0,0 +1,73 @@
+package geo
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGraph = `
+# a line a-b-c with a long shortcut a-c, and an isolated node d
+node a 0 0
+node b 0 1
+node c 0 2
+node d 10 10
+
+edge a b 100
+edge b c 100
+edge a c 500
+`
+
+func TestRoadGraph_Distance(t *testing.T) {
+	g, err := ParseRoadGraph(strings.NewReader(testGraph))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	d, err := g.Distance(ctx, Point{Lat: 0, Lng: 0}, Point{Lat: 0, Lng: 2})
+	require.NoError(t, err)
+	assert.InDelta(t, 200, d, 1e-9, "takes the shorter path through b")
+
+	// Points off the graph are snapped to the nearest node
+	d, err = g.Distance(ctx, Point{Lat: 0.01, Lng: 0}, Point{Lat: 0, Lng: 1})
+	require.NoError(t, err)
+	assert.InDelta(t, 100+Haversine(Point{Lat: 0.01, Lng: 0}, Point{Lat: 0, Lng: 0}), d, 1e-9)
+
+	_, err = g.Distance(ctx, Point{Lat: 0, Lng: 0}, Point{Lat: 10, Lng: 10})
+	assert.Equal(t, ErrNoRoute, err)
+}
+
+func TestRoadGraph_DefaultEdgeLength(t *testing.T) {
+	g, err := ParseRoadGraph(strings.NewReader("node a 0 0\nnode b 0 1\nedge a b\n"))
+	require.NoError(t, err)
+
+	d, err := g.Distance(context.Background(), Point{Lat: 0, Lng: 0}, Point{Lat: 0, Lng: 1})
+	require.NoError(t, err)
+	assert.InDelta(t, Haversine(Point{Lat: 0, Lng: 0}, Point{Lat: 0, Lng: 1}), d, 1e-9)
+}
+
+func TestParseRoadGraph_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"node a 0",
+		"node a 95 0",
+		"node a 0 0\nnode a 1 1",
+		"node a 0 0\nedge a b",
+		"node a 0 0\nnode b 0 1\nedge a b -1",
+		"road a b",
+	} {
+		_, err := ParseRoadGraph(strings.NewReader(input))
+		assert.Error(t, err, input)
+	}
+}
+
+func TestLoadRoadGraph_Bundled(t *testing.T) {
+	g, err := LoadRoadGraph("../data/roads.txt")
+	require.NoError(t, err)
+
+	d, err := g.Distance(context.Background(), karachi, lahore)
+	require.NoError(t, err)
+	assert.InDelta(t, 1305, d, 1)
+}
@@ -0,0 +1,21 @@
+package geo
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNoRoute = errors.New("no route between points")
+
+// RoutingProvider computes the travel distance in km between two points.
+type RoutingProvider interface {
+	Distance(ctx context.Context, from, to Point) (float64, error)
+}
+
+// HaversineProvider routes in a straight line. It needs no data and is used
+// when no road graph is configured.
+type HaversineProvider struct{}
+
+func (HaversineProvider) Distance(_ context.Context, from, to Point) (float64, error) {
+	return Haversine(from, to), nil
+}
@@ -1,57 +1,132 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
 	"log"
 	"net"
-	"net/http"
+	"time"
+	_ "time/tzdata"
 
 	_ "github.com/lib/pq"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
 	"ride-service/config"
+	"ride-service/gazetteer"
+	"ride-service/geo"
 	pb "ride-service/pb/proto/ride"
+	"ride-service/pricing"
 	"ride-service/repository"
 	"ride-service/server"
 
+	"github.com/hasnain-zafar/go-microservices/common/admin"
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
 	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
 )
 
 func main() {
 	// Initialize Prometheus metrics
 	metrics.Init()
-
-	// Start metrics HTTP server in a goroutine
-	go startMetricsServer("ride-service", 2113)
+	registry := metrics.NewRegistry("ride-service")
 
 	cfg := config.Load()
+	logger.Configure(cfg.Log)
 
-	db, err := sql.Open("postgres", cfg.DBUrl)
+	// Wait for Postgres, which may still be starting
+	db, err := database.Open(context.Background(), "postgres", cfg.DBUrl, cfg.DBPool)
 	if err != nil {
-		log.Fatalf("❌ Could not connect to DB: %v", err)
+		log.Fatalf("❌ DB not reachable: %v", err)
 	}
 	defer db.Close()
+	registry.RegisterDBStats(db, "rides_db")
+
+	fmt.Println("✅ Connected to rides_db successfully")
+
+	// Serve metrics and, with ADMIN_TOKEN set, the debug endpoints
+	conns := admin.NewConnTracker()
+	adminServer := admin.NewServer("ride-service", cfg.AdminToken, registry,
+		admin.WithConfig(cfg),
+		admin.WithDB(db),
+		admin.WithConnections(conns),
+	)
+	go startAdminServer(adminServer, "ride-service", 2113)
 
-	err = db.Ping()
+	broker, err := outbox.NewBroker(context.Background(), cfg.BrokerURL)
 	if err != nil {
-		log.Fatalf("❌ DB not reachable: %v", err)
+		log.Fatalf("❌ Failed to connect to message broker: %v", err)
 	}
+	defer broker.Close()
 
-	fmt.Println("✅ Connected to rides_db successfully")
+	// Publish domain events written to the outbox by the repository
+	relay := outbox.NewRelay(db, broker, logger.NewLogger("ride-service"))
+	go relay.Run(context.Background())
 
 	rideRepo := repository.NewPostgresRideRepository(db)
 
-	rideServer := server.NewRideServer(rideRepo)
+	if cfg.QuoteSigningKey == "" {
+		log.Fatalf("❌ QUOTE_SIGNING_KEY must be set")
+	}
+	location, err := time.LoadLocation(cfg.PricingTimezone)
+	if err != nil {
+		log.Fatalf("❌ Invalid pricing timezone: %v", err)
+	}
+	tariffRepo := repository.NewPostgresTariffRepository(db)
+	places, err := gazetteer.Default()
+	if err != nil {
+		log.Fatalf("❌ Failed to load gazetteer: %v", err)
+	}
+
+	surgeTiers, err := pricing.ParseSurgeTiers(cfg.SurgeTiers)
+	if err != nil {
+		log.Fatalf("❌ Invalid surge tiers: %v", err)
+	}
+	surge := pricing.NewSurge(pricing.SurgeConfig{
+		Window:   cfg.SurgeWindow,
+		Validity: cfg.SurgeValidity,
+		Tiers:    surgeTiers,
+		CapBP:    cfg.SurgeCapBP,
+		AreaOf:   places.AreaKey,
+	})
+	go surge.Run(context.Background(), 15*time.Second)
+
+	pricingEngine := pricing.NewEngine(tariffRepo, pricing.NewSigner([]byte(cfg.QuoteSigningKey)), location, cfg.QuoteTTL, surge)
+
+	var router geo.RoutingProvider = geo.HaversineProvider{}
+	if cfg.RoadGraphPath != "" {
+		roadGraph, err := geo.LoadRoadGraph(cfg.RoadGraphPath)
+		if err != nil {
+			log.Fatalf("❌ Failed to load road graph: %v", err)
+		}
+		router = roadGraph
+	}
+
+	auditLog := audit.NewPostgresLog(db)
+
+	rideServer := server.NewRideServer(rideRepo, pricingEngine, router, places,
+		server.WithAuditLog(auditLog),
+		server.WithMetrics(registry),
+	)
 
 	listener, err := net.Listen("tcp", ":50052")
 	if err != nil {
 		log.Fatalf("❌ Failed to listen on port 50052: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	// Tag every call with a request ID for its log lines, and with the actor
+	// its caller authenticated as for the audit log
+	requestLogger := logger.NewLogger("ride-service")
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(conns),
+		grpc.ChainUnaryInterceptor(
+			logger.UnaryServerInterceptor(requestLogger),
+			audit.NewAuthenticator(cfg.AdminToken).UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(logger.StreamServerInterceptor(requestLogger)),
+	)
 	pb.RegisterRideServiceServer(grpcServer, rideServer)
 
 	reflection.Register(grpcServer)
@@ -62,11 +137,9 @@ func main() {
 	}
 }
 
-func startMetricsServer(serviceName string, port int) {
-	fmt.Printf("📊 Metrics server for %s starting on :%d\n", serviceName, port)
-	http.Handle("/metrics", promhttp.Handler())
-	err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-	if err != nil {
-		log.Fatalf("❌ Failed to start metrics server: %v", err)
+func startAdminServer(adminServer *admin.Server, serviceName string, port int) {
+	fmt.Printf("📊 Admin server for %s starting on :%d\n", serviceName, port)
+	if err := adminServer.ListenAndServe(port); err != nil {
+		log.Fatalf("❌ Failed to start admin server: %v", err)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	grpc "google.golang.org/grpc"
+	audit "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
 	mock "github.com/stretchr/testify/mock"
 	pb "ride-service/pb/proto/ride"
 )
@@ -75,6 +76,96 @@ func (_m *RideServiceClient) GetRide(ctx context.Context, in *pb.GetRideRequest,
 	return r0, r1
 }
 
+// QueryAuditLog provides a mock function with given fields: ctx, in, opts
+func (_m *RideServiceClient) QueryAuditLog(ctx context.Context, in *audit.QueryAuditLogRequest, opts ...grpc.CallOption) (*audit.QueryAuditLogResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *audit.QueryAuditLogResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.QueryAuditLogRequest, ...grpc.CallOption) *audit.QueryAuditLogResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*audit.QueryAuditLogResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *audit.QueryAuditLogRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QuoteFare provides a mock function with given fields: ctx, in, opts
+func (_m *RideServiceClient) QuoteFare(ctx context.Context, in *pb.QuoteFareRequest, opts ...grpc.CallOption) (*pb.FareQuote, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *pb.FareQuote
+	if rf, ok := ret.Get(0).(func(context.Context, *pb.QuoteFareRequest, ...grpc.CallOption) *pb.FareQuote); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pb.FareQuote)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *pb.QuoteFareRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchPlaces provides a mock function with given fields: ctx, in, opts
+func (_m *RideServiceClient) SearchPlaces(ctx context.Context, in *pb.SearchPlacesRequest, opts ...grpc.CallOption) (*pb.SearchPlacesResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *pb.SearchPlacesResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *pb.SearchPlacesRequest, ...grpc.CallOption) *pb.SearchPlacesResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*pb.SearchPlacesResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *pb.SearchPlacesRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateRide provides a mock function with given fields: ctx, in, opts
 func (_m *RideServiceClient) UpdateRide(ctx context.Context, in *pb.UpdateRideRequest, opts ...grpc.CallOption) (*pb.UpdateRideResponse, error) {
 	_va := make([]interface{}, len(opts))
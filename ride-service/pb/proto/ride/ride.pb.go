@@ -7,8 +7,11 @@
 package pb
 
 import (
+	audit "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+	money "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,20 +24,88 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-type Ride struct {
+// LatLng is a WGS84 coordinate in decimal degrees.
+type LatLng struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RideId        int32                  `protobuf:"varint,1,opt,name=ride_id,json=rideId,proto3" json:"ride_id,omitempty"`
-	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
-	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
-	Distance      int32                  `protobuf:"varint,4,opt,name=distance,proto3" json:"distance,omitempty"`
-	Cost          int32                  `protobuf:"varint,5,opt,name=cost,proto3" json:"cost,omitempty"`
+	Lat           float64                `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"`
+	Lng           float64                `protobuf:"fixed64,2,opt,name=lng,proto3" json:"lng,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LatLng) Reset() {
+	*x = LatLng{}
+	mi := &file_proto_ride_ride_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LatLng) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatLng) ProtoMessage() {}
+
+func (x *LatLng) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ride_ride_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatLng.ProtoReflect.Descriptor instead.
+func (*LatLng) Descriptor() ([]byte, []int) {
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LatLng) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *LatLng) GetLng() float64 {
+	if x != nil {
+		return x.Lng
+	}
+	return 0
+}
+
+type Ride struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	RideId      int32                  `protobuf:"varint,1,opt,name=ride_id,json=rideId,proto3" json:"ride_id,omitempty"`
+	Source      string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	Distance    int32                  `protobuf:"varint,4,opt,name=distance,proto3" json:"distance,omitempty"`
+	// Whole units of price's currency, for clients that predate price.
+	//
+	// Deprecated: Marked as deprecated in proto/ride/ride.proto.
+	Cost                int32   `protobuf:"varint,5,opt,name=cost,proto3" json:"cost,omitempty"`
+	VehicleClass        string  `protobuf:"bytes,6,opt,name=vehicle_class,json=vehicleClass,proto3" json:"vehicle_class,omitempty"`
+	TariffVersion       int32   `protobuf:"varint,7,opt,name=tariff_version,json=tariffVersion,proto3" json:"tariff_version,omitempty"`
+	SourceLocation      *LatLng `protobuf:"bytes,8,opt,name=source_location,json=sourceLocation,proto3" json:"source_location,omitempty"`
+	DestinationLocation *LatLng `protobuf:"bytes,9,opt,name=destination_location,json=destinationLocation,proto3" json:"destination_location,omitempty"`
+	// Canonical gazetteer place IDs; empty when the place was not recognised.
+	SourcePlaceId      string                 `protobuf:"bytes,10,opt,name=source_place_id,json=sourcePlaceId,proto3" json:"source_place_id,omitempty"`
+	DestinationPlaceId string                 `protobuf:"bytes,11,opt,name=destination_place_id,json=destinationPlaceId,proto3" json:"destination_place_id,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Price              *money.Money           `protobuf:"bytes,14,opt,name=price,proto3" json:"price,omitempty"`
+	// How price was computed; unset for rides booked without one.
+	FareBreakdown *FareBreakdown `protobuf:"bytes,15,opt,name=fare_breakdown,json=fareBreakdown,proto3" json:"fare_breakdown,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Ride) Reset() {
 	*x = Ride{}
-	mi := &file_proto_ride_ride_proto_msgTypes[0]
+	mi := &file_proto_ride_ride_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -46,7 +117,7 @@ func (x *Ride) String() string {
 func (*Ride) ProtoMessage() {}
 
 func (x *Ride) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ride_ride_proto_msgTypes[0]
+	mi := &file_proto_ride_ride_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -59,7 +130,7 @@ func (x *Ride) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Ride.ProtoReflect.Descriptor instead.
 func (*Ride) Descriptor() ([]byte, []int) {
-	return file_proto_ride_ride_proto_rawDescGZIP(), []int{0}
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *Ride) GetRideId() int32 {
@@ -90,6 +161,7 @@ func (x *Ride) GetDistance() int32 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in proto/ride/ride.proto.
 func (x *Ride) GetCost() int32 {
 	if x != nil {
 		return x.Cost
@@ -97,19 +169,410 @@ func (x *Ride) GetCost() int32 {
 	return 0
 }
 
-type CreateRideRequest struct {
+func (x *Ride) GetVehicleClass() string {
+	if x != nil {
+		return x.VehicleClass
+	}
+	return ""
+}
+
+func (x *Ride) GetTariffVersion() int32 {
+	if x != nil {
+		return x.TariffVersion
+	}
+	return 0
+}
+
+func (x *Ride) GetSourceLocation() *LatLng {
+	if x != nil {
+		return x.SourceLocation
+	}
+	return nil
+}
+
+func (x *Ride) GetDestinationLocation() *LatLng {
+	if x != nil {
+		return x.DestinationLocation
+	}
+	return nil
+}
+
+func (x *Ride) GetSourcePlaceId() string {
+	if x != nil {
+		return x.SourcePlaceId
+	}
+	return ""
+}
+
+func (x *Ride) GetDestinationPlaceId() string {
+	if x != nil {
+		return x.DestinationPlaceId
+	}
+	return ""
+}
+
+func (x *Ride) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Ride) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Ride) GetPrice() *money.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Ride) GetFareBreakdown() *FareBreakdown {
+	if x != nil {
+		return x.FareBreakdown
+	}
+	return nil
+}
+
+// Place is a canonical location from the gazetteer.
+type Place struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
-	Destination   string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
-	Distance      int32                  `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
-	Cost          int32                  `protobuf:"varint,4,opt,name=cost,proto3" json:"cost,omitempty"`
+	PlaceId       string                 `protobuf:"bytes,1,opt,name=place_id,json=placeId,proto3" json:"place_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Location      *LatLng                `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Aliases       []string               `protobuf:"bytes,4,rep,name=aliases,proto3" json:"aliases,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *Place) Reset() {
+	*x = Place{}
+	mi := &file_proto_ride_ride_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Place) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Place) ProtoMessage() {}
+
+func (x *Place) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ride_ride_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Place.ProtoReflect.Descriptor instead.
+func (*Place) Descriptor() ([]byte, []int) {
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Place) GetPlaceId() string {
+	if x != nil {
+		return x.PlaceId
+	}
+	return ""
+}
+
+func (x *Place) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Place) GetLocation() *LatLng {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Place) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+// FareBreakdown itemizes a fare. The adjustments are what the minimum fare
+// and the multipliers added, so the amounts add up to the fare.
+type FareBreakdown struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	BaseFare              *money.Money           `protobuf:"bytes,1,opt,name=base_fare,json=baseFare,proto3" json:"base_fare,omitempty"`
+	DistanceFare          *money.Money           `protobuf:"bytes,2,opt,name=distance_fare,json=distanceFare,proto3" json:"distance_fare,omitempty"`
+	MinimumFareAdjustment *money.Money           `protobuf:"bytes,3,opt,name=minimum_fare_adjustment,json=minimumFareAdjustment,proto3" json:"minimum_fare_adjustment,omitempty"`
+	// Multipliers in basis points (10000 = none).
+	TimeOfDayMultiplierBp int32        `protobuf:"varint,4,opt,name=time_of_day_multiplier_bp,json=timeOfDayMultiplierBp,proto3" json:"time_of_day_multiplier_bp,omitempty"`
+	TimeOfDayAdjustment   *money.Money `protobuf:"bytes,5,opt,name=time_of_day_adjustment,json=timeOfDayAdjustment,proto3" json:"time_of_day_adjustment,omitempty"`
+	SurgeMultiplierBp     int32        `protobuf:"varint,6,opt,name=surge_multiplier_bp,json=surgeMultiplierBp,proto3" json:"surge_multiplier_bp,omitempty"`
+	SurgeAdjustment       *money.Money `protobuf:"bytes,7,opt,name=surge_adjustment,json=surgeAdjustment,proto3" json:"surge_adjustment,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *FareBreakdown) Reset() {
+	*x = FareBreakdown{}
+	mi := &file_proto_ride_ride_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FareBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FareBreakdown) ProtoMessage() {}
+
+func (x *FareBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ride_ride_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FareBreakdown.ProtoReflect.Descriptor instead.
+func (*FareBreakdown) Descriptor() ([]byte, []int) {
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FareBreakdown) GetBaseFare() *money.Money {
+	if x != nil {
+		return x.BaseFare
+	}
+	return nil
+}
+
+func (x *FareBreakdown) GetDistanceFare() *money.Money {
+	if x != nil {
+		return x.DistanceFare
+	}
+	return nil
+}
+
+func (x *FareBreakdown) GetMinimumFareAdjustment() *money.Money {
+	if x != nil {
+		return x.MinimumFareAdjustment
+	}
+	return nil
+}
+
+func (x *FareBreakdown) GetTimeOfDayMultiplierBp() int32 {
+	if x != nil {
+		return x.TimeOfDayMultiplierBp
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetTimeOfDayAdjustment() *money.Money {
+	if x != nil {
+		return x.TimeOfDayAdjustment
+	}
+	return nil
+}
+
+func (x *FareBreakdown) GetSurgeMultiplierBp() int32 {
+	if x != nil {
+		return x.SurgeMultiplierBp
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetSurgeAdjustment() *money.Money {
+	if x != nil {
+		return x.SurgeAdjustment
+	}
+	return nil
+}
+
+// FareQuote is a fare computed by ride-service. The signatures cover every
+// other field, so a quote cannot be altered by the client.
+type FareQuote struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Source       string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination  string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Distance     int32                  `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	VehicleClass string                 `protobuf:"bytes,4,opt,name=vehicle_class,json=vehicleClass,proto3" json:"vehicle_class,omitempty"`
+	// Whole units of price's currency, for clients that predate price.
+	//
+	// Deprecated: Marked as deprecated in proto/ride/ride.proto.
+	Fare          int32                  `protobuf:"varint,5,opt,name=fare,proto3" json:"fare,omitempty"`
+	TariffVersion int32                  `protobuf:"varint,6,opt,name=tariff_version,json=tariffVersion,proto3" json:"tariff_version,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Signature     string                 `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Demand multiplier included in price, in basis points (10000 = no surge).
+	SurgeMultiplierBp int32                  `protobuf:"varint,9,opt,name=surge_multiplier_bp,json=surgeMultiplierBp,proto3" json:"surge_multiplier_bp,omitempty"`
+	SurgeExpiresAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=surge_expires_at,json=surgeExpiresAt,proto3" json:"surge_expires_at,omitempty"`
+	Price             *money.Money           `protobuf:"bytes,11,opt,name=price,proto3" json:"price,omitempty"`
+	// Optional itemization of price, signed separately so that quotes without
+	// it still verify.
+	Breakdown          *FareBreakdown `protobuf:"bytes,12,opt,name=breakdown,proto3" json:"breakdown,omitempty"`
+	BreakdownSignature string         `protobuf:"bytes,13,opt,name=breakdown_signature,json=breakdownSignature,proto3" json:"breakdown_signature,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *FareQuote) Reset() {
+	*x = FareQuote{}
+	mi := &file_proto_ride_ride_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FareQuote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FareQuote) ProtoMessage() {}
+
+func (x *FareQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ride_ride_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FareQuote.ProtoReflect.Descriptor instead.
+func (*FareQuote) Descriptor() ([]byte, []int) {
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FareQuote) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *FareQuote) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *FareQuote) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *FareQuote) GetVehicleClass() string {
+	if x != nil {
+		return x.VehicleClass
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in proto/ride/ride.proto.
+func (x *FareQuote) GetFare() int32 {
+	if x != nil {
+		return x.Fare
+	}
+	return 0
+}
+
+func (x *FareQuote) GetTariffVersion() int32 {
+	if x != nil {
+		return x.TariffVersion
+	}
+	return 0
+}
+
+func (x *FareQuote) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *FareQuote) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *FareQuote) GetSurgeMultiplierBp() int32 {
+	if x != nil {
+		return x.SurgeMultiplierBp
+	}
+	return 0
+}
+
+func (x *FareQuote) GetSurgeExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SurgeExpiresAt
+	}
+	return nil
+}
+
+func (x *FareQuote) GetPrice() *money.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *FareQuote) GetBreakdown() *FareBreakdown {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
+func (x *FareQuote) GetBreakdownSignature() string {
+	if x != nil {
+		return x.BreakdownSignature
+	}
+	return ""
+}
+
+type CreateRideRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Source      string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Distance    int32                  `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	// Ignored: the ride's cost is taken from quote.
+	//
+	// Deprecated: Marked as deprecated in proto/ride/ride.proto.
+	Cost  int32      `protobuf:"varint,4,opt,name=cost,proto3" json:"cost,omitempty"`
+	Quote *FareQuote `protobuf:"bytes,5,opt,name=quote,proto3" json:"quote,omitempty"`
+	// Required for places the gazetteer does not recognise; for recognised
+	// places they must lie near the place.
+	SourceLocation      *LatLng `protobuf:"bytes,6,opt,name=source_location,json=sourceLocation,proto3" json:"source_location,omitempty"`
+	DestinationLocation *LatLng `protobuf:"bytes,7,opt,name=destination_location,json=destinationLocation,proto3" json:"destination_location,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
 func (x *CreateRideRequest) Reset() {
 	*x = CreateRideRequest{}
-	mi := &file_proto_ride_ride_proto_msgTypes[1]
+	mi := &file_proto_ride_ride_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -121,7 +584,7 @@ func (x *CreateRideRequest) String() string {
 func (*CreateRideRequest) ProtoMessage() {}
 
 func (x *CreateRideRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ride_ride_proto_msgTypes[1]
+	mi := &file_proto_ride_ride_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -134,7 +597,7 @@ func (x *CreateRideRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRideRequest.ProtoReflect.Descriptor instead.
 func (*CreateRideRequest) Descriptor() ([]byte, []int) {
-	return file_proto_ride_ride_proto_rawDescGZIP(), []int{1}
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateRideRequest) GetSource() string {
@@ -158,6 +621,7 @@ func (x *CreateRideRequest) GetDistance() int32 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in proto/ride/ride.proto.
 func (x *CreateRideRequest) GetCost() int32 {
 	if x != nil {
 		return x.Cost
@@ -165,16 +629,41 @@ func (x *CreateRideRequest) GetCost() int32 {
 	return 0
 }
 
+func (x *CreateRideRequest) GetQuote() *FareQuote {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+func (x *CreateRideRequest) GetSourceLocation() *LatLng {
+	if x != nil {
+		return x.SourceLocation
+	}
+	return nil
+}
+
+func (x *CreateRideRequest) GetDestinationLocation() *LatLng {
+	if x != nil {
+		return x.DestinationLocation
+	}
+	return nil
+}
+
 type CreateRideResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RideId        int32                  `protobuf:"varint,1,opt,name=ride_id,json=rideId,proto3" json:"ride_id,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	RideId int32                  `protobuf:"varint,1,opt,name=ride_id,json=rideId,proto3" json:"ride_id,omitempty"`
+	// Pickup coordinates from the request or the recognised source place.
+	SourceLocation *LatLng `protobuf:"bytes,2,opt,name=source_location,json=sourceLocation,proto3" json:"source_location,omitempty"`
+	// Gazetteer ID of the source place; empty when it was not recognised.
+	SourcePlaceId string `protobuf:"bytes,3,opt,name=source_place_id,json=sourcePlaceId,proto3" json:"source_place_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateRideResponse) Reset() {
 	*x = CreateRideResponse{}
-	mi := &file_proto_ride_ride_proto_msgTypes[2]
+	mi := &file_proto_ride_ride_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -186,7 +675,7 @@ func (x *CreateRideResponse) String() string {
 func (*CreateRideResponse) ProtoMessage() {}
 
 func (x *CreateRideResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ride_ride_proto_msgTypes[2]
+	mi := &file_proto_ride_ride_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -199,7 +688,7 @@ func (x *CreateRideResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRideResponse.ProtoReflect.Descriptor instead.
 func (*CreateRideResponse) Descriptor() ([]byte, []int) {
-	return file_proto_ride_ride_proto_rawDescGZIP(), []int{2}
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateRideResponse) GetRideId() int32 {
@@ -209,6 +698,20 @@ func (x *CreateRideResponse) GetRideId() int32 {
 	return 0
 }
 
+func (x *CreateRideResponse) GetSourceLocation() *LatLng {
+	if x != nil {
+		return x.SourceLocation
+	}
+	return nil
+}
+
+func (x *CreateRideResponse) GetSourcePlaceId() string {
+	if x != nil {
+		return x.SourcePlaceId
+	}
+	return ""
+}
+
 type GetRideRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RideId        int32                  `protobuf:"varint,1,opt,name=ride_id,json=rideId,proto3" json:"ride_id,omitempty"`
@@ -218,7 +721,7 @@ type GetRideRequest struct {
 
 func (x *GetRideRequest) Reset() {
 	*x = GetRideRequest{}
-	mi := &file_proto_ride_ride_proto_msgTypes[3]
+	mi := &file_proto_ride_ride_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -230,7 +733,7 @@ func (x *GetRideRequest) String() string {
 func (*GetRideRequest) ProtoMessage() {}
 
 func (x *GetRideRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ride_ride_proto_msgTypes[3]
+	mi := &file_proto_ride_ride_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -243,7 +746,7 @@ func (x *GetRideRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRideRequest.ProtoReflect.Descriptor instead.
 func (*GetRideRequest) Descriptor() ([]byte, []int) {
-	return file_proto_ride_ride_proto_rawDescGZIP(), []int{3}
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetRideRequest) GetRideId() int32 {
@@ -254,16 +757,19 @@ func (x *GetRideRequest) GetRideId() int32 {
 }
 
 type UpdateRideRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RideId        int32                  `protobuf:"varint,1,opt,name=ride_id,json=rideId,proto3" json:"ride_id,omitempty"`
-	Ride          *Ride                  `protobuf:"bytes,2,opt,name=ride,proto3" json:"ride,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	RideId int32                  `protobuf:"varint,1,opt,name=ride_id,json=rideId,proto3" json:"ride_id,omitempty"`
+	// Only the route is read: source, destination, distance and the optional
+	// locations. The ride's cost is taken from quote.
+	Ride          *Ride      `protobuf:"bytes,2,opt,name=ride,proto3" json:"ride,omitempty"`
+	Quote         *FareQuote `protobuf:"bytes,3,opt,name=quote,proto3" json:"quote,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateRideRequest) Reset() {
 	*x = UpdateRideRequest{}
-	mi := &file_proto_ride_ride_proto_msgTypes[4]
+	mi := &file_proto_ride_ride_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -275,7 +781,7 @@ func (x *UpdateRideRequest) String() string {
 func (*UpdateRideRequest) ProtoMessage() {}
 
 func (x *UpdateRideRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ride_ride_proto_msgTypes[4]
+	mi := &file_proto_ride_ride_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -288,7 +794,7 @@ func (x *UpdateRideRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateRideRequest.ProtoReflect.Descriptor instead.
 func (*UpdateRideRequest) Descriptor() ([]byte, []int) {
-	return file_proto_ride_ride_proto_rawDescGZIP(), []int{4}
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UpdateRideRequest) GetRideId() int32 {
@@ -305,6 +811,13 @@ func (x *UpdateRideRequest) GetRide() *Ride {
 	return nil
 }
 
+func (x *UpdateRideRequest) GetQuote() *FareQuote {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
 type UpdateRideResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
@@ -314,7 +827,7 @@ type UpdateRideResponse struct {
 
 func (x *UpdateRideResponse) Reset() {
 	*x = UpdateRideResponse{}
-	mi := &file_proto_ride_ride_proto_msgTypes[5]
+	mi := &file_proto_ride_ride_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -326,7 +839,7 @@ func (x *UpdateRideResponse) String() string {
 func (*UpdateRideResponse) ProtoMessage() {}
 
 func (x *UpdateRideResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ride_ride_proto_msgTypes[5]
+	mi := &file_proto_ride_ride_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -339,7 +852,7 @@ func (x *UpdateRideResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateRideResponse.ProtoReflect.Descriptor instead.
 func (*UpdateRideResponse) Descriptor() ([]byte, []int) {
-	return file_proto_ride_ride_proto_rawDescGZIP(), []int{5}
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *UpdateRideResponse) GetMessage() string {
@@ -349,39 +862,289 @@ func (x *UpdateRideResponse) GetMessage() string {
 	return ""
 }
 
+type QuoteFareRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Source      string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Distance    int32                  `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	// Defaults to ECONOMY when empty.
+	VehicleClass string `protobuf:"bytes,4,opt,name=vehicle_class,json=vehicleClass,proto3" json:"vehicle_class,omitempty"`
+	// As on CreateRideRequest; the distance is checked against the route
+	// before the quote is signed.
+	SourceLocation      *LatLng `protobuf:"bytes,5,opt,name=source_location,json=sourceLocation,proto3" json:"source_location,omitempty"`
+	DestinationLocation *LatLng `protobuf:"bytes,6,opt,name=destination_location,json=destinationLocation,proto3" json:"destination_location,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *QuoteFareRequest) Reset() {
+	*x = QuoteFareRequest{}
+	mi := &file_proto_ride_ride_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteFareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteFareRequest) ProtoMessage() {}
+
+func (x *QuoteFareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ride_ride_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteFareRequest.ProtoReflect.Descriptor instead.
+func (*QuoteFareRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *QuoteFareRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *QuoteFareRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *QuoteFareRequest) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *QuoteFareRequest) GetVehicleClass() string {
+	if x != nil {
+		return x.VehicleClass
+	}
+	return ""
+}
+
+func (x *QuoteFareRequest) GetSourceLocation() *LatLng {
+	if x != nil {
+		return x.SourceLocation
+	}
+	return nil
+}
+
+func (x *QuoteFareRequest) GetDestinationLocation() *LatLng {
+	if x != nil {
+		return x.DestinationLocation
+	}
+	return nil
+}
+
+type SearchPlacesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Defaults to 10; at most 50.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPlacesRequest) Reset() {
+	*x = SearchPlacesRequest{}
+	mi := &file_proto_ride_ride_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPlacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPlacesRequest) ProtoMessage() {}
+
+func (x *SearchPlacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ride_ride_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPlacesRequest.ProtoReflect.Descriptor instead.
+func (*SearchPlacesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchPlacesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchPlacesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchPlacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Places        []*Place               `protobuf:"bytes,1,rep,name=places,proto3" json:"places,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPlacesResponse) Reset() {
+	*x = SearchPlacesResponse{}
+	mi := &file_proto_ride_ride_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPlacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPlacesResponse) ProtoMessage() {}
+
+func (x *SearchPlacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ride_ride_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPlacesResponse.ProtoReflect.Descriptor instead.
+func (*SearchPlacesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ride_ride_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchPlacesResponse) GetPlaces() []*Place {
+	if x != nil {
+		return x.Places
+	}
+	return nil
+}
+
 var File_proto_ride_ride_proto protoreflect.FileDescriptor
 
 const file_proto_ride_ride_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/ride/ride.proto\x12\x04ride\"\x89\x01\n" +
+	"\x15proto/ride/ride.proto\x12\x04ride\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17proto/audit/audit.proto\x1a\x17proto/money/money.proto\",\n" +
+	"\x06LatLng\x12\x10\n" +
+	"\x03lat\x18\x01 \x01(\x01R\x03lat\x12\x10\n" +
+	"\x03lng\x18\x02 \x01(\x01R\x03lng\"\x81\x05\n" +
 	"\x04Ride\x12\x17\n" +
 	"\aride_id\x18\x01 \x01(\x05R\x06rideId\x12\x16\n" +
 	"\x06source\x18\x02 \x01(\tR\x06source\x12 \n" +
 	"\vdestination\x18\x03 \x01(\tR\vdestination\x12\x1a\n" +
-	"\bdistance\x18\x04 \x01(\x05R\bdistance\x12\x12\n" +
-	"\x04cost\x18\x05 \x01(\x05R\x04cost\"}\n" +
+	"\bdistance\x18\x04 \x01(\x05R\bdistance\x12\x16\n" +
+	"\x04cost\x18\x05 \x01(\x05B\x02\x18\x01R\x04cost\x12#\n" +
+	"\rvehicle_class\x18\x06 \x01(\tR\fvehicleClass\x12%\n" +
+	"\x0etariff_version\x18\a \x01(\x05R\rtariffVersion\x125\n" +
+	"\x0fsource_location\x18\b \x01(\v2\f.ride.LatLngR\x0esourceLocation\x12?\n" +
+	"\x14destination_location\x18\t \x01(\v2\f.ride.LatLngR\x13destinationLocation\x12&\n" +
+	"\x0fsource_place_id\x18\n" +
+	" \x01(\tR\rsourcePlaceId\x120\n" +
+	"\x14destination_place_id\x18\v \x01(\tR\x12destinationPlaceId\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\"\n" +
+	"\x05price\x18\x0e \x01(\v2\f.money.MoneyR\x05price\x12:\n" +
+	"\x0efare_breakdown\x18\x0f \x01(\v2\x13.ride.FareBreakdownR\rfareBreakdown\"z\n" +
+	"\x05Place\x12\x19\n" +
+	"\bplace_id\x18\x01 \x01(\tR\aplaceId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12(\n" +
+	"\blocation\x18\x03 \x01(\v2\f.ride.LatLngR\blocation\x12\x18\n" +
+	"\aaliases\x18\x04 \x03(\tR\aaliases\"\x99\x03\n" +
+	"\rFareBreakdown\x12)\n" +
+	"\tbase_fare\x18\x01 \x01(\v2\f.money.MoneyR\bbaseFare\x121\n" +
+	"\rdistance_fare\x18\x02 \x01(\v2\f.money.MoneyR\fdistanceFare\x12D\n" +
+	"\x17minimum_fare_adjustment\x18\x03 \x01(\v2\f.money.MoneyR\x15minimumFareAdjustment\x128\n" +
+	"\x19time_of_day_multiplier_bp\x18\x04 \x01(\x05R\x15timeOfDayMultiplierBp\x12A\n" +
+	"\x16time_of_day_adjustment\x18\x05 \x01(\v2\f.money.MoneyR\x13timeOfDayAdjustment\x12.\n" +
+	"\x13surge_multiplier_bp\x18\x06 \x01(\x05R\x11surgeMultiplierBp\x127\n" +
+	"\x10surge_adjustment\x18\a \x01(\v2\f.money.MoneyR\x0fsurgeAdjustment\"\x9c\x04\n" +
+	"\tFareQuote\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x1a\n" +
+	"\bdistance\x18\x03 \x01(\x05R\bdistance\x12#\n" +
+	"\rvehicle_class\x18\x04 \x01(\tR\fvehicleClass\x12\x16\n" +
+	"\x04fare\x18\x05 \x01(\x05B\x02\x18\x01R\x04fare\x12%\n" +
+	"\x0etariff_version\x18\x06 \x01(\x05R\rtariffVersion\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1c\n" +
+	"\tsignature\x18\b \x01(\tR\tsignature\x12.\n" +
+	"\x13surge_multiplier_bp\x18\t \x01(\x05R\x11surgeMultiplierBp\x12D\n" +
+	"\x10surge_expires_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x0esurgeExpiresAt\x12\"\n" +
+	"\x05price\x18\v \x01(\v2\f.money.MoneyR\x05price\x121\n" +
+	"\tbreakdown\x18\f \x01(\v2\x13.ride.FareBreakdownR\tbreakdown\x12/\n" +
+	"\x13breakdown_signature\x18\r \x01(\tR\x12breakdownSignature\"\xa0\x02\n" +
 	"\x11CreateRideRequest\x12\x16\n" +
 	"\x06source\x18\x01 \x01(\tR\x06source\x12 \n" +
 	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x1a\n" +
-	"\bdistance\x18\x03 \x01(\x05R\bdistance\x12\x12\n" +
-	"\x04cost\x18\x04 \x01(\x05R\x04cost\"-\n" +
+	"\bdistance\x18\x03 \x01(\x05R\bdistance\x12\x16\n" +
+	"\x04cost\x18\x04 \x01(\x05B\x02\x18\x01R\x04cost\x12%\n" +
+	"\x05quote\x18\x05 \x01(\v2\x0f.ride.FareQuoteR\x05quote\x125\n" +
+	"\x0fsource_location\x18\x06 \x01(\v2\f.ride.LatLngR\x0esourceLocation\x12?\n" +
+	"\x14destination_location\x18\a \x01(\v2\f.ride.LatLngR\x13destinationLocation\"\x8c\x01\n" +
 	"\x12CreateRideResponse\x12\x17\n" +
-	"\aride_id\x18\x01 \x01(\x05R\x06rideId\")\n" +
+	"\aride_id\x18\x01 \x01(\x05R\x06rideId\x125\n" +
+	"\x0fsource_location\x18\x02 \x01(\v2\f.ride.LatLngR\x0esourceLocation\x12&\n" +
+	"\x0fsource_place_id\x18\x03 \x01(\tR\rsourcePlaceId\")\n" +
 	"\x0eGetRideRequest\x12\x17\n" +
-	"\aride_id\x18\x01 \x01(\x05R\x06rideId\"L\n" +
+	"\aride_id\x18\x01 \x01(\x05R\x06rideId\"s\n" +
 	"\x11UpdateRideRequest\x12\x17\n" +
 	"\aride_id\x18\x01 \x01(\x05R\x06rideId\x12\x1e\n" +
 	"\x04ride\x18\x02 \x01(\v2\n" +
-	".ride.RideR\x04ride\".\n" +
+	".ride.RideR\x04ride\x12%\n" +
+	"\x05quote\x18\x03 \x01(\v2\x0f.ride.FareQuoteR\x05quote\".\n" +
 	"\x12UpdateRideResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2\xbc\x01\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x85\x02\n" +
+	"\x10QuoteFareRequest\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x1a\n" +
+	"\bdistance\x18\x03 \x01(\x05R\bdistance\x12#\n" +
+	"\rvehicle_class\x18\x04 \x01(\tR\fvehicleClass\x125\n" +
+	"\x0fsource_location\x18\x05 \x01(\v2\f.ride.LatLngR\x0esourceLocation\x12?\n" +
+	"\x14destination_location\x18\x06 \x01(\v2\f.ride.LatLngR\x13destinationLocation\"A\n" +
+	"\x13SearchPlacesRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\";\n" +
+	"\x14SearchPlacesResponse\x12#\n" +
+	"\x06places\x18\x01 \x03(\v2\v.ride.PlaceR\x06places2\x85\x03\n" +
 	"\vRideService\x12?\n" +
 	"\n" +
 	"CreateRide\x12\x17.ride.CreateRideRequest\x1a\x18.ride.CreateRideResponse\x12+\n" +
 	"\aGetRide\x12\x14.ride.GetRideRequest\x1a\n" +
 	".ride.Ride\x12?\n" +
 	"\n" +
-	"UpdateRide\x12\x17.ride.UpdateRideRequest\x1a\x18.ride.UpdateRideResponseB\x11Z\x0fride-service/pbb\x06proto3"
+	"UpdateRide\x12\x17.ride.UpdateRideRequest\x1a\x18.ride.UpdateRideResponse\x124\n" +
+	"\tQuoteFare\x12\x16.ride.QuoteFareRequest\x1a\x0f.ride.FareQuote\x12E\n" +
+	"\fSearchPlaces\x12\x19.ride.SearchPlacesRequest\x1a\x1a.ride.SearchPlacesResponse\x12J\n" +
+	"\rQueryAuditLog\x12\x1b.audit.QueryAuditLogRequest\x1a\x1c.audit.QueryAuditLogResponseB\x11Z\x0fride-service/pbb\x06proto3"
 
 var (
 	file_proto_ride_ride_proto_rawDescOnce sync.Once
@@ -395,28 +1158,69 @@ func file_proto_ride_ride_proto_rawDescGZIP() []byte {
 	return file_proto_ride_ride_proto_rawDescData
 }
 
-var file_proto_ride_ride_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_ride_ride_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_proto_ride_ride_proto_goTypes = []any{
-	(*Ride)(nil),               // 0: ride.Ride
-	(*CreateRideRequest)(nil),  // 1: ride.CreateRideRequest
-	(*CreateRideResponse)(nil), // 2: ride.CreateRideResponse
-	(*GetRideRequest)(nil),     // 3: ride.GetRideRequest
-	(*UpdateRideRequest)(nil),  // 4: ride.UpdateRideRequest
-	(*UpdateRideResponse)(nil), // 5: ride.UpdateRideResponse
+	(*LatLng)(nil),                      // 0: ride.LatLng
+	(*Ride)(nil),                        // 1: ride.Ride
+	(*Place)(nil),                       // 2: ride.Place
+	(*FareBreakdown)(nil),               // 3: ride.FareBreakdown
+	(*FareQuote)(nil),                   // 4: ride.FareQuote
+	(*CreateRideRequest)(nil),           // 5: ride.CreateRideRequest
+	(*CreateRideResponse)(nil),          // 6: ride.CreateRideResponse
+	(*GetRideRequest)(nil),              // 7: ride.GetRideRequest
+	(*UpdateRideRequest)(nil),           // 8: ride.UpdateRideRequest
+	(*UpdateRideResponse)(nil),          // 9: ride.UpdateRideResponse
+	(*QuoteFareRequest)(nil),            // 10: ride.QuoteFareRequest
+	(*SearchPlacesRequest)(nil),         // 11: ride.SearchPlacesRequest
+	(*SearchPlacesResponse)(nil),        // 12: ride.SearchPlacesResponse
+	(*timestamppb.Timestamp)(nil),       // 13: google.protobuf.Timestamp
+	(*money.Money)(nil),                 // 14: money.Money
+	(*audit.QueryAuditLogRequest)(nil),  // 15: audit.QueryAuditLogRequest
+	(*audit.QueryAuditLogResponse)(nil), // 16: audit.QueryAuditLogResponse
 }
 var file_proto_ride_ride_proto_depIdxs = []int32{
-	0, // 0: ride.UpdateRideRequest.ride:type_name -> ride.Ride
-	1, // 1: ride.RideService.CreateRide:input_type -> ride.CreateRideRequest
-	3, // 2: ride.RideService.GetRide:input_type -> ride.GetRideRequest
-	4, // 3: ride.RideService.UpdateRide:input_type -> ride.UpdateRideRequest
-	2, // 4: ride.RideService.CreateRide:output_type -> ride.CreateRideResponse
-	0, // 5: ride.RideService.GetRide:output_type -> ride.Ride
-	5, // 6: ride.RideService.UpdateRide:output_type -> ride.UpdateRideResponse
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	0,  // 0: ride.Ride.source_location:type_name -> ride.LatLng
+	0,  // 1: ride.Ride.destination_location:type_name -> ride.LatLng
+	13, // 2: ride.Ride.created_at:type_name -> google.protobuf.Timestamp
+	13, // 3: ride.Ride.updated_at:type_name -> google.protobuf.Timestamp
+	14, // 4: ride.Ride.price:type_name -> money.Money
+	3,  // 5: ride.Ride.fare_breakdown:type_name -> ride.FareBreakdown
+	0,  // 6: ride.Place.location:type_name -> ride.LatLng
+	14, // 7: ride.FareBreakdown.base_fare:type_name -> money.Money
+	14, // 8: ride.FareBreakdown.distance_fare:type_name -> money.Money
+	14, // 9: ride.FareBreakdown.minimum_fare_adjustment:type_name -> money.Money
+	14, // 10: ride.FareBreakdown.time_of_day_adjustment:type_name -> money.Money
+	14, // 11: ride.FareBreakdown.surge_adjustment:type_name -> money.Money
+	13, // 12: ride.FareQuote.expires_at:type_name -> google.protobuf.Timestamp
+	13, // 13: ride.FareQuote.surge_expires_at:type_name -> google.protobuf.Timestamp
+	14, // 14: ride.FareQuote.price:type_name -> money.Money
+	3,  // 15: ride.FareQuote.breakdown:type_name -> ride.FareBreakdown
+	4,  // 16: ride.CreateRideRequest.quote:type_name -> ride.FareQuote
+	0,  // 17: ride.CreateRideRequest.source_location:type_name -> ride.LatLng
+	0,  // 18: ride.CreateRideRequest.destination_location:type_name -> ride.LatLng
+	0,  // 19: ride.CreateRideResponse.source_location:type_name -> ride.LatLng
+	1,  // 20: ride.UpdateRideRequest.ride:type_name -> ride.Ride
+	4,  // 21: ride.UpdateRideRequest.quote:type_name -> ride.FareQuote
+	0,  // 22: ride.QuoteFareRequest.source_location:type_name -> ride.LatLng
+	0,  // 23: ride.QuoteFareRequest.destination_location:type_name -> ride.LatLng
+	2,  // 24: ride.SearchPlacesResponse.places:type_name -> ride.Place
+	5,  // 25: ride.RideService.CreateRide:input_type -> ride.CreateRideRequest
+	7,  // 26: ride.RideService.GetRide:input_type -> ride.GetRideRequest
+	8,  // 27: ride.RideService.UpdateRide:input_type -> ride.UpdateRideRequest
+	10, // 28: ride.RideService.QuoteFare:input_type -> ride.QuoteFareRequest
+	11, // 29: ride.RideService.SearchPlaces:input_type -> ride.SearchPlacesRequest
+	15, // 30: ride.RideService.QueryAuditLog:input_type -> audit.QueryAuditLogRequest
+	6,  // 31: ride.RideService.CreateRide:output_type -> ride.CreateRideResponse
+	1,  // 32: ride.RideService.GetRide:output_type -> ride.Ride
+	9,  // 33: ride.RideService.UpdateRide:output_type -> ride.UpdateRideResponse
+	4,  // 34: ride.RideService.QuoteFare:output_type -> ride.FareQuote
+	12, // 35: ride.RideService.SearchPlaces:output_type -> ride.SearchPlacesResponse
+	16, // 36: ride.RideService.QueryAuditLog:output_type -> audit.QueryAuditLogResponse
+	31, // [31:37] is the sub-list for method output_type
+	25, // [25:31] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_proto_ride_ride_proto_init() }
@@ -430,7 +1234,7 @@ func file_proto_ride_ride_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_ride_ride_proto_rawDesc), len(file_proto_ride_ride_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
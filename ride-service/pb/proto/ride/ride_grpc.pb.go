@@ -8,6 +8,7 @@ package pb
 
 import (
 	context "context"
+	audit "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -19,9 +20,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	RideService_CreateRide_FullMethodName = "/ride.RideService/CreateRide"
-	RideService_GetRide_FullMethodName    = "/ride.RideService/GetRide"
-	RideService_UpdateRide_FullMethodName = "/ride.RideService/UpdateRide"
+	RideService_CreateRide_FullMethodName    = "/ride.RideService/CreateRide"
+	RideService_GetRide_FullMethodName       = "/ride.RideService/GetRide"
+	RideService_UpdateRide_FullMethodName    = "/ride.RideService/UpdateRide"
+	RideService_QuoteFare_FullMethodName     = "/ride.RideService/QuoteFare"
+	RideService_SearchPlaces_FullMethodName  = "/ride.RideService/SearchPlaces"
+	RideService_QueryAuditLog_FullMethodName = "/ride.RideService/QueryAuditLog"
 )
 
 // RideServiceClient is the client API for RideService service.
@@ -31,6 +35,9 @@ type RideServiceClient interface {
 	CreateRide(ctx context.Context, in *CreateRideRequest, opts ...grpc.CallOption) (*CreateRideResponse, error)
 	GetRide(ctx context.Context, in *GetRideRequest, opts ...grpc.CallOption) (*Ride, error)
 	UpdateRide(ctx context.Context, in *UpdateRideRequest, opts ...grpc.CallOption) (*UpdateRideResponse, error)
+	QuoteFare(ctx context.Context, in *QuoteFareRequest, opts ...grpc.CallOption) (*FareQuote, error)
+	SearchPlaces(ctx context.Context, in *SearchPlacesRequest, opts ...grpc.CallOption) (*SearchPlacesResponse, error)
+	QueryAuditLog(ctx context.Context, in *audit.QueryAuditLogRequest, opts ...grpc.CallOption) (*audit.QueryAuditLogResponse, error)
 }
 
 type rideServiceClient struct {
@@ -71,6 +78,36 @@ func (c *rideServiceClient) UpdateRide(ctx context.Context, in *UpdateRideReques
 	return out, nil
 }
 
+func (c *rideServiceClient) QuoteFare(ctx context.Context, in *QuoteFareRequest, opts ...grpc.CallOption) (*FareQuote, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FareQuote)
+	err := c.cc.Invoke(ctx, RideService_QuoteFare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rideServiceClient) SearchPlaces(ctx context.Context, in *SearchPlacesRequest, opts ...grpc.CallOption) (*SearchPlacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchPlacesResponse)
+	err := c.cc.Invoke(ctx, RideService_SearchPlaces_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rideServiceClient) QueryAuditLog(ctx context.Context, in *audit.QueryAuditLogRequest, opts ...grpc.CallOption) (*audit.QueryAuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(audit.QueryAuditLogResponse)
+	err := c.cc.Invoke(ctx, RideService_QueryAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RideServiceServer is the server API for RideService service.
 // All implementations must embed UnimplementedRideServiceServer
 // for forward compatibility.
@@ -78,6 +115,9 @@ type RideServiceServer interface {
 	CreateRide(context.Context, *CreateRideRequest) (*CreateRideResponse, error)
 	GetRide(context.Context, *GetRideRequest) (*Ride, error)
 	UpdateRide(context.Context, *UpdateRideRequest) (*UpdateRideResponse, error)
+	QuoteFare(context.Context, *QuoteFareRequest) (*FareQuote, error)
+	SearchPlaces(context.Context, *SearchPlacesRequest) (*SearchPlacesResponse, error)
+	QueryAuditLog(context.Context, *audit.QueryAuditLogRequest) (*audit.QueryAuditLogResponse, error)
 	mustEmbedUnimplementedRideServiceServer()
 }
 
@@ -97,6 +137,15 @@ func (UnimplementedRideServiceServer) GetRide(context.Context, *GetRideRequest)
 func (UnimplementedRideServiceServer) UpdateRide(context.Context, *UpdateRideRequest) (*UpdateRideResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateRide not implemented")
 }
+func (UnimplementedRideServiceServer) QuoteFare(context.Context, *QuoteFareRequest) (*FareQuote, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuoteFare not implemented")
+}
+func (UnimplementedRideServiceServer) SearchPlaces(context.Context, *SearchPlacesRequest) (*SearchPlacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchPlaces not implemented")
+}
+func (UnimplementedRideServiceServer) QueryAuditLog(context.Context, *audit.QueryAuditLogRequest) (*audit.QueryAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAuditLog not implemented")
+}
 func (UnimplementedRideServiceServer) mustEmbedUnimplementedRideServiceServer() {}
 func (UnimplementedRideServiceServer) testEmbeddedByValue()                     {}
 
@@ -172,6 +221,60 @@ func _RideService_UpdateRide_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RideService_QuoteFare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuoteFareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RideServiceServer).QuoteFare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RideService_QuoteFare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RideServiceServer).QuoteFare(ctx, req.(*QuoteFareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RideService_SearchPlaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchPlacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RideServiceServer).SearchPlaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RideService_SearchPlaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RideServiceServer).SearchPlaces(ctx, req.(*SearchPlacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RideService_QueryAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(audit.QueryAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RideServiceServer).QueryAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RideService_QueryAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RideServiceServer).QueryAuditLog(ctx, req.(*audit.QueryAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RideService_ServiceDesc is the grpc.ServiceDesc for RideService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,6 +294,18 @@ var RideService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateRide",
 			Handler:    _RideService_UpdateRide_Handler,
 		},
+		{
+			MethodName: "QuoteFare",
+			Handler:    _RideService_QuoteFare_Handler,
+		},
+		{
+			MethodName: "SearchPlaces",
+			Handler:    _RideService_SearchPlaces_Handler,
+		},
+		{
+			MethodName: "QueryAuditLog",
+			Handler:    _RideService_QueryAuditLog_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/ride/ride.proto",
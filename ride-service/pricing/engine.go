@@ -0,0 +1,189 @@
+package pricing
+
+import (
+	"context"
+	"time"
+
+	"ride-service/repository"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+// DefaultVehicleClass is used when a quote request does not name one.
+const DefaultVehicleClass = "ECONOMY"
+
+const basisPoints = 10000
+
+// Engine computes fares from the active tariff for a vehicle class and signs
+// them into quotes.
+type Engine struct {
+	tariffs  repository.TariffRepository
+	signer   *Signer
+	location *time.Location
+	quoteTTL time.Duration
+	surge    *Surge
+	now      func() time.Time
+}
+
+// NewEngine creates an Engine. Time-of-day bands are evaluated in location, and
+// quotes are valid for quoteTTL after they are issued. A nil surge disables
+// demand-based pricing.
+func NewEngine(tariffs repository.TariffRepository, signer *Signer, location *time.Location, quoteTTL time.Duration, surge *Surge) *Engine {
+	return &Engine{
+		tariffs:  tariffs,
+		signer:   signer,
+		location: location,
+		quoteTTL: quoteTTL,
+		surge:    surge,
+		now:      time.Now,
+	}
+}
+
+// Quote prices a trip of distance km with the tariff active now.
+func (e *Engine) Quote(ctx context.Context, source, destination string, distance int32, vehicleClass string) (*Quote, error) {
+	if vehicleClass == "" {
+		vehicleClass = DefaultVehicleClass
+	}
+
+	now := e.now()
+	tariff, err := e.tariffs.GetActive(ctx, vehicleClass, now)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown, err := Itemize(tariff, distance, now.In(e.location))
+	if err != nil {
+		return nil, err
+	}
+	fare, err := breakdown.Total()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := now.Add(e.quoteTTL)
+
+	surgeBP := int32(basisPoints)
+	var surgeExpiresAt time.Time
+	if e.surge != nil {
+		surgeBP, surgeExpiresAt = e.surge.Multiplier(e.surge.Area(source), now)
+		surgeExpiresAt = surgeExpiresAt.Truncate(time.Second)
+		surged, err := ApplyMultiplier(fare, surgeBP)
+		if err != nil {
+			return nil, err
+		}
+		if breakdown.SurgeAdjustment, err = surged.Sub(fare); err != nil {
+			return nil, err
+		}
+		fare = surged
+		// A quote cannot outlive the surge level it was priced at.
+		if surgeExpiresAt.Before(expiresAt) {
+			expiresAt = surgeExpiresAt
+		}
+	}
+	breakdown.SurgeMultiplierBP = surgeBP
+
+	quote := &Quote{
+		Source:            source,
+		Destination:       destination,
+		Distance:          distance,
+		VehicleClass:      vehicleClass,
+		Fare:              fare,
+		TariffVersion:     tariff.Version,
+		SurgeMultiplierBP: surgeBP,
+		SurgeExpiresAt:    surgeExpiresAt,
+		ExpiresAt:         expiresAt.Truncate(time.Second),
+		Breakdown:         breakdown,
+	}
+	e.signer.Sign(quote)
+
+	return quote, nil
+}
+
+// Verify checks that q was issued by this engine and is still valid.
+func (e *Engine) Verify(q *Quote) error {
+	return e.signer.Verify(q, e.now())
+}
+
+// RecordBooking counts a booking request from source towards surge demand.
+func (e *Engine) RecordBooking(source string) {
+	if e.surge != nil {
+		e.surge.RecordRequest(e.surge.Area(source), e.now())
+	}
+}
+
+// CalculateFare applies tariff to a trip of distance km starting at the given
+// local time: the base fare plus the per-km rate, raised to the minimum fare,
+// then scaled by the matching time-of-day multiplier and rounded half up to
+// whole units of the tariff's currency.
+func CalculateFare(tariff *repository.Tariff, distance int32, at time.Time) (money.Money, error) {
+	breakdown, err := Itemize(tariff, distance, at)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return breakdown.Total()
+}
+
+// Itemize computes the fare of CalculateFare and returns it as a breakdown,
+// with no surge.
+func Itemize(tariff *repository.Tariff, distance int32, at time.Time) (*repository.FareBreakdown, error) {
+	base, err := money.FromMajor(tariff.Currency, int64(tariff.BaseFare))
+	if err != nil {
+		return nil, err
+	}
+	perKm, err := money.FromMajor(tariff.Currency, int64(tariff.PerKmRate))
+	if err != nil {
+		return nil, err
+	}
+	minimum, err := money.FromMajor(tariff.Currency, int64(tariff.MinimumFare))
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := &repository.FareBreakdown{
+		BaseFare:              base,
+		MinimumFareAdjustment: money.Money{Currency: tariff.Currency},
+		TimeOfDayMultiplierBP: timeOfDayMultiplier(tariff.TimeBands, at.Hour()),
+		SurgeMultiplierBP:     basisPoints,
+		SurgeAdjustment:       money.Money{Currency: tariff.Currency},
+	}
+	if breakdown.DistanceFare, err = perKm.Mul(int64(distance)); err != nil {
+		return nil, err
+	}
+	fare, err := base.Add(breakdown.DistanceFare)
+	if err != nil {
+		return nil, err
+	}
+	if fare.Minor < minimum.Minor {
+		if breakdown.MinimumFareAdjustment, err = minimum.Sub(fare); err != nil {
+			return nil, err
+		}
+		fare = minimum
+	}
+
+	adjusted, err := ApplyMultiplier(fare, breakdown.TimeOfDayMultiplierBP)
+	if err != nil {
+		return nil, err
+	}
+	if breakdown.TimeOfDayAdjustment, err = adjusted.Sub(fare); err != nil {
+		return nil, err
+	}
+	return breakdown, nil
+}
+
+// ApplyMultiplier scales fare by multiplierBP basis points, rounding half up to
+// whole units.
+func ApplyMultiplier(fare money.Money, multiplierBP int32) (money.Money, error) {
+	return fare.MulRatioMajor(int64(multiplierBP), basisPoints, money.HalfUp)
+}
+
+func timeOfDayMultiplier(bands []repository.TimeBand, hour int) int32 {
+	for _, band := range bands {
+		if band.StartHour < band.EndHour {
+			if hour >= band.StartHour && hour < band.EndHour {
+				return band.MultiplierBP
+			}
+		} else if hour >= band.StartHour || hour < band.EndHour {
+			return band.MultiplierBP
+		}
+	}
+	return basisPoints
+}
@@ -0,0 +1,183 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ride-service/gazetteer"
+	"ride-service/repository"
+	"ride-service/repository/mocks"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTariff = &repository.Tariff{
+	VehicleClass: "ECONOMY",
+	Version:      2,
+	Currency:     money.PKR,
+	BaseFare:     500,
+	PerKmRate:    4,
+	MinimumFare:  700,
+	TimeBands: []repository.TimeBand{
+		{StartHour: 7, EndHour: 10, MultiplierBP: 12000},
+		{StartHour: 23, EndHour: 5, MultiplierBP: 11050},
+	},
+}
+
+func pkr(rupees int64) money.Money {
+	return money.Money{Currency: money.PKR, Minor: rupees * 100}
+}
+
+func at(hour int) time.Time {
+	return time.Date(2025, 1, 1, hour, 30, 0, 0, time.UTC)
+}
+
+func TestCalculateFare(t *testing.T) {
+	testCases := []struct {
+		name     string
+		distance int32
+		at       time.Time
+		expected int64
+	}{
+		{name: "Off Peak", distance: 200, at: at(12), expected: 1300},
+		{name: "Minimum Fare", distance: 10, at: at(12), expected: 700},
+		{name: "Morning Peak", distance: 200, at: at(8), expected: 1560},
+		{name: "Peak Band End Is Exclusive", distance: 200, at: at(10), expected: 1300},
+		{name: "Night Band Before Midnight", distance: 200, at: at(23), expected: 1437},
+		{name: "Night Band After Midnight", distance: 200, at: at(2), expected: 1437},
+		{name: "Night Band Rounds Half Up", distance: 50, at: at(2), expected: 774},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fare, err := CalculateFare(testTariff, tc.distance, tc.at)
+			require.NoError(t, err)
+			assert.Equal(t, pkr(tc.expected), fare)
+		})
+	}
+
+	unknown := *testTariff
+	unknown.Currency = "XXX"
+	_, err := CalculateFare(&unknown, 200, at(12))
+	assert.ErrorIs(t, err, money.ErrUnknownCurrency)
+}
+
+func TestItemize(t *testing.T) {
+	// Night band, 1.105x: (500 + 50 * 4) = 700, exactly the minimum fare
+	breakdown, err := Itemize(testTariff, 50, at(2))
+	require.NoError(t, err)
+	assert.Equal(t, &repository.FareBreakdown{
+		BaseFare:              pkr(500),
+		DistanceFare:          pkr(200),
+		MinimumFareAdjustment: pkr(0),
+		TimeOfDayMultiplierBP: 11050,
+		TimeOfDayAdjustment:   pkr(74),
+		SurgeMultiplierBP:     10000,
+		SurgeAdjustment:       pkr(0),
+	}, breakdown)
+
+	// Below the minimum fare
+	breakdown, err = Itemize(testTariff, 10, at(12))
+	require.NoError(t, err)
+	assert.Equal(t, pkr(160), breakdown.MinimumFareAdjustment)
+	assert.Equal(t, pkr(0), breakdown.TimeOfDayAdjustment)
+
+	// The amounts add up to the fare
+	for _, hour := range []int{2, 8, 12, 23} {
+		for _, distance := range []int32{10, 50, 200, 1200} {
+			breakdown, err := Itemize(testTariff, distance, at(hour))
+			require.NoError(t, err)
+			total, err := breakdown.Total()
+			require.NoError(t, err)
+			fare, err := CalculateFare(testTariff, distance, at(hour))
+			require.NoError(t, err)
+			assert.Equal(t, fare, total, "%d km at %d:30", distance, hour)
+		}
+	}
+}
+
+func TestEngine_QuoteAndVerify(t *testing.T) {
+	mockTariffs := new(mocks.TariffRepository)
+	now := at(8)
+	engine := NewEngine(mockTariffs, NewSigner([]byte("key")), time.UTC, 5*time.Minute, nil)
+	engine.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	mockTariffs.On("GetActive", ctx, "ECONOMY", now).Return(testTariff, nil)
+
+	quote, err := engine.Quote(ctx, "Karachi", "Lahore", 200, "")
+	require.NoError(t, err)
+	assert.Equal(t, "ECONOMY", quote.VehicleClass)
+	assert.Equal(t, pkr(1560), quote.Fare)
+	assert.Equal(t, int32(2), quote.TariffVersion)
+	assert.Equal(t, now.Add(5*time.Minute), quote.ExpiresAt)
+	assert.NoError(t, engine.Verify(quote))
+
+	tampered := *quote
+	tampered.Fare = pkr(100)
+	assert.Equal(t, ErrInvalidQuoteSignature, engine.Verify(&tampered))
+
+	tampered = *quote
+	tampered.Fare.Currency = "USD"
+	assert.Equal(t, ErrInvalidQuoteSignature, engine.Verify(&tampered))
+
+	tampered = *quote
+	breakdown := *quote.Breakdown
+	breakdown.TimeOfDayAdjustment = pkr(0)
+	tampered.Breakdown = &breakdown
+	assert.Equal(t, ErrInvalidQuoteSignature, engine.Verify(&tampered))
+
+	// Clients that predate the breakdown drop it
+	tampered = *quote
+	tampered.Breakdown = nil
+	assert.NoError(t, engine.Verify(&tampered))
+
+	otherKey := NewEngine(mockTariffs, NewSigner([]byte("other")), time.UTC, 5*time.Minute, nil)
+	assert.Equal(t, ErrInvalidQuoteSignature, otherKey.Verify(quote))
+
+	now = now.Add(5 * time.Minute)
+	assert.Equal(t, ErrQuoteExpired, engine.Verify(quote))
+}
+
+func TestEngine_QuoteWithSurge(t *testing.T) {
+	mockTariffs := new(mocks.TariffRepository)
+	now := at(12)
+	places, err := gazetteer.Default()
+	require.NoError(t, err)
+	surge := NewSurge(SurgeConfig{
+		Window:   10 * time.Minute,
+		Validity: 2 * time.Minute,
+		Tiers:    []SurgeTier{{MinRequests: 2, MultiplierBP: 15000}},
+		AreaOf:   places.AreaKey,
+	})
+	engine := NewEngine(mockTariffs, NewSigner([]byte("key")), time.UTC, 5*time.Minute, surge)
+	engine.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	mockTariffs.On("GetActive", ctx, "ECONOMY", now).Return(testTariff, nil)
+
+	engine.RecordBooking("Karachi")
+	engine.RecordBooking(" karachi ")
+
+	quote, err := engine.Quote(ctx, "Karachi", "Lahore", 200, "")
+	require.NoError(t, err)
+	assert.Equal(t, pkr(1950), quote.Fare)
+	assert.Equal(t, int32(15000), quote.SurgeMultiplierBP)
+	assert.Equal(t, int32(15000), quote.Breakdown.SurgeMultiplierBP)
+	assert.Equal(t, pkr(650), quote.Breakdown.SurgeAdjustment)
+	assert.Equal(t, now.Add(2*time.Minute), quote.SurgeExpiresAt)
+	assert.Equal(t, now.Add(2*time.Minute), quote.ExpiresAt)
+	assert.NoError(t, engine.Verify(quote))
+
+	tampered := *quote
+	tampered.SurgeMultiplierBP = 10000
+	assert.Equal(t, ErrInvalidQuoteSignature, engine.Verify(&tampered))
+
+	other, err := engine.Quote(ctx, "Lahore", "Karachi", 200, "")
+	require.NoError(t, err)
+	assert.Equal(t, pkr(1300), other.Fare)
+	assert.Equal(t, int32(10000), other.SurgeMultiplierBP)
+}
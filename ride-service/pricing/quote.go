@@ -0,0 +1,102 @@
+package pricing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"ride-service/repository"
+
+	"github.com/hasnain-zafar/go-microservices/common/money"
+)
+
+var (
+	ErrInvalidQuoteSignature = errors.New("fare quote signature is invalid")
+	ErrQuoteExpired          = errors.New("fare quote has expired")
+)
+
+// Quote is a fare computed for a trip, valid until ExpiresAt.
+type Quote struct {
+	Source        string
+	Destination   string
+	Distance      int32
+	VehicleClass  string
+	Fare          money.Money
+	TariffVersion int32
+	// SurgeMultiplierBP is the demand multiplier included in Fare, in basis
+	// points; 10000 means no surge.
+	SurgeMultiplierBP int32
+	SurgeExpiresAt    time.Time
+	ExpiresAt         time.Time
+	Signature         string
+	// Breakdown itemizes Fare. BreakdownSignature covers it and Signature,
+	// so that quotes from clients that drop it still verify.
+	Breakdown          *repository.FareBreakdown
+	BreakdownSignature string
+}
+
+// Signer signs and verifies quotes with HMAC-SHA256 so that a quote handed to
+// a client can be trusted when it comes back on CreateRide.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign sets q.Signature over every other field of q, and
+// q.BreakdownSignature when q has a breakdown.
+func (s *Signer) Sign(q *Quote) {
+	q.Signature = hex.EncodeToString(s.mac(q))
+	if q.Breakdown != nil {
+		q.BreakdownSignature = hex.EncodeToString(s.breakdownMAC(q))
+	}
+}
+
+// Verify checks q's signature and that it has not expired at now.
+func (s *Signer) Verify(q *Quote, now time.Time) error {
+	signature, err := hex.DecodeString(q.Signature)
+	if err != nil || !hmac.Equal(signature, s.mac(q)) {
+		return ErrInvalidQuoteSignature
+	}
+	if q.Breakdown != nil {
+		signature, err := hex.DecodeString(q.BreakdownSignature)
+		if err != nil || !hmac.Equal(signature, s.breakdownMAC(q)) {
+			return ErrInvalidQuoteSignature
+		}
+	}
+	if !now.Before(q.ExpiresAt) {
+		return ErrQuoteExpired
+	}
+	return nil
+}
+
+func (s *Signer) mac(q *Quote) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%q|%q|%d|%q|%q|%d|%d|%d|%d|%d",
+		q.Source, q.Destination, q.Distance, q.VehicleClass, q.Fare.Currency, q.Fare.Minor, q.TariffVersion,
+		q.SurgeMultiplierBP, surgeExpiry(q), q.ExpiresAt.Unix())
+	return mac.Sum(nil)
+}
+
+// breakdownMAC covers q's breakdown and, through q.Signature, the rest of q.
+func (s *Signer) breakdownMAC(q *Quote) []byte {
+	b := q.Breakdown
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "breakdown|%s|%d|%d", q.Signature, b.TimeOfDayMultiplierBP, b.SurgeMultiplierBP)
+	for _, amount := range []money.Money{b.BaseFare, b.DistanceFare, b.MinimumFareAdjustment, b.TimeOfDayAdjustment, b.SurgeAdjustment} {
+		fmt.Fprintf(mac, "|%q|%d", amount.Currency, amount.Minor)
+	}
+	return mac.Sum(nil)
+}
+
+func surgeExpiry(q *Quote) int64 {
+	if q.SurgeExpiresAt.IsZero() {
+		return 0
+	}
+	return q.SurgeExpiresAt.Unix()
+}
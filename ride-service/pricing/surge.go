@@ -0,0 +1,188 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+)
+
+// SurgeTier applies MultiplierBP once an area has at least MinRequests booking
+// requests within the surge window.
+type SurgeTier struct {
+	MinRequests  int
+	MultiplierBP int32
+}
+
+type SurgeConfig struct {
+	// Window is how far back booking requests count towards demand.
+	Window time.Duration
+	// Validity is how long a quoted multiplier is honoured.
+	Validity time.Duration
+	Tiers    []SurgeTier
+	// CapBP is the highest multiplier ever applied.
+	CapBP int32
+	// AreaOf maps a ride source to the area its demand counts towards. Each
+	// area is a metric label, so it must return one of a fixed set; when nil
+	// all demand counts towards DefaultArea.
+	AreaOf func(source string) string
+}
+
+// Surge tracks recent booking requests per source area and turns demand into
+// a fare multiplier.
+type Surge struct {
+	cfg SurgeConfig
+
+	mu       sync.Mutex
+	requests map[string][]time.Time
+}
+
+func NewSurge(cfg SurgeConfig) *Surge {
+	tiers := append([]SurgeTier(nil), cfg.Tiers...)
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinRequests < tiers[j].MinRequests })
+	cfg.Tiers = tiers
+	if cfg.AreaOf == nil {
+		cfg.AreaOf = func(string) string { return DefaultArea }
+	}
+
+	return &Surge{
+		cfg:      cfg,
+		requests: make(map[string][]time.Time),
+	}
+}
+
+// DefaultArea is the single area demand is tracked under when SurgeConfig
+// has no AreaOf.
+const DefaultArea = "all"
+
+// Area returns the area demand from source is tracked under.
+func (s *Surge) Area(source string) string {
+	return s.cfg.AreaOf(source)
+}
+
+// RecordRequest counts a booking request from area at the given time.
+func (s *Surge) RecordRequest(area string, at time.Time) {
+	s.mu.Lock()
+	s.requests[area] = append(s.prune(area, at), at)
+	multiplier := s.multiplier(len(s.requests[area]))
+	s.mu.Unlock()
+
+	metrics.SetSurgeMultiplier(area, float64(multiplier)/basisPoints)
+}
+
+// Multiplier returns the current multiplier for area in basis points and the
+// time until which it is guaranteed.
+func (s *Surge) Multiplier(area string, at time.Time) (int32, time.Time) {
+	s.mu.Lock()
+	multiplier := s.multiplier(len(s.prune(area, at)))
+	s.mu.Unlock()
+
+	return multiplier, at.Add(s.cfg.Validity)
+}
+
+// Refresh drops expired requests for every area and updates the surge gauges,
+// removing areas with no remaining demand.
+func (s *Surge) Refresh(at time.Time) {
+	s.mu.Lock()
+	levels := make(map[string]int32, len(s.requests))
+	for area := range s.requests {
+		if remaining := s.prune(area, at); len(remaining) > 0 {
+			levels[area] = s.multiplier(len(remaining))
+		} else {
+			levels[area] = 0
+		}
+	}
+	s.mu.Unlock()
+
+	for area, multiplier := range levels {
+		if multiplier == 0 {
+			metrics.DeleteSurgeMultiplier(area)
+			continue
+		}
+		metrics.SetSurgeMultiplier(area, float64(multiplier)/basisPoints)
+	}
+}
+
+// Run refreshes surge levels every interval until ctx is cancelled.
+func (s *Surge) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Refresh(now)
+		}
+	}
+}
+
+// prune drops requests older than the window for area. It must be called with
+// s.mu held.
+func (s *Surge) prune(area string, at time.Time) []time.Time {
+	requests := s.requests[area]
+	cutoff := at.Add(-s.cfg.Window)
+
+	i := 0
+	for i < len(requests) && !requests[i].After(cutoff) {
+		i++
+	}
+	requests = requests[i:]
+
+	if len(requests) == 0 {
+		delete(s.requests, area)
+		return nil
+	}
+	s.requests[area] = requests
+	return requests
+}
+
+func (s *Surge) multiplier(requests int) int32 {
+	multiplier := int32(basisPoints)
+	for _, tier := range s.cfg.Tiers {
+		if requests >= tier.MinRequests {
+			multiplier = tier.MultiplierBP
+		}
+	}
+	if s.cfg.CapBP > 0 && multiplier > s.cfg.CapBP {
+		multiplier = s.cfg.CapBP
+	}
+	if multiplier < basisPoints {
+		multiplier = basisPoints
+	}
+	return multiplier
+}
+
+// ParseSurgeTiers parses tiers written as "requests:multiplier_bp" pairs
+// separated by commas, e.g. "10:12500,25:15000".
+func ParseSurgeTiers(value string) ([]SurgeTier, error) {
+	var tiers []SurgeTier
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		requests, multiplier, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid surge tier %q", part)
+		}
+		minRequests, err := strconv.Atoi(requests)
+		if err != nil || minRequests <= 0 {
+			return nil, fmt.Errorf("invalid surge tier request count %q", requests)
+		}
+		multiplierBP, err := strconv.ParseInt(multiplier, 10, 32)
+		if err != nil || multiplierBP < basisPoints {
+			return nil, fmt.Errorf("invalid surge tier multiplier %q", multiplier)
+		}
+
+		tiers = append(tiers, SurgeTier{MinRequests: minRequests, MultiplierBP: int32(multiplierBP)})
+	}
+	return tiers, nil
+}
@@ -0,0 +1,96 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSurge_Multiplier(t *testing.T) {
+	surge := NewSurge(SurgeConfig{
+		Window:   10 * time.Minute,
+		Validity: time.Minute,
+		Tiers: []SurgeTier{
+			{MinRequests: 5, MultiplierBP: 20000},
+			{MinRequests: 2, MultiplierBP: 12500},
+			{MinRequests: 8, MultiplierBP: 30000},
+		},
+		CapBP: 25000,
+	})
+	start := at(12)
+
+	record := func(n int, at time.Time) {
+		for i := 0; i < n; i++ {
+			surge.RecordRequest("karachi", at)
+		}
+	}
+
+	multiplier, expiresAt := surge.Multiplier("karachi", start)
+	assert.Equal(t, int32(10000), multiplier)
+	assert.Equal(t, start.Add(time.Minute), expiresAt)
+
+	record(2, start)
+	multiplier, _ = surge.Multiplier("karachi", start)
+	assert.Equal(t, int32(12500), multiplier)
+
+	record(3, start.Add(5*time.Minute))
+	multiplier, _ = surge.Multiplier("karachi", start.Add(5*time.Minute))
+	assert.Equal(t, int32(20000), multiplier)
+
+	record(3, start.Add(6*time.Minute))
+	multiplier, _ = surge.Multiplier("karachi", start.Add(6*time.Minute))
+	assert.Equal(t, int32(25000), multiplier, "multiplier is capped")
+
+	multiplier, _ = surge.Multiplier("lahore", start.Add(6*time.Minute))
+	assert.Equal(t, int32(10000), multiplier, "demand is tracked per area")
+
+	// The first two requests fall out of the window
+	multiplier, _ = surge.Multiplier("karachi", start.Add(10*time.Minute))
+	assert.Equal(t, int32(20000), multiplier)
+
+	surge.Refresh(start.Add(30 * time.Minute))
+	multiplier, _ = surge.Multiplier("karachi", start.Add(30*time.Minute))
+	assert.Equal(t, int32(10000), multiplier)
+}
+
+func TestSurge_Areas(t *testing.T) {
+	surge := NewSurge(SurgeConfig{
+		Window:   10 * time.Minute,
+		Validity: time.Minute,
+		Tiers:    []SurgeTier{{MinRequests: 2, MultiplierBP: 15000}},
+	})
+	start := at(12)
+
+	// Without AreaOf every source shares one area
+	assert.Equal(t, DefaultArea, surge.Area("Karachi"))
+	assert.Equal(t, DefaultArea, surge.Area("Gate 4, Sector F-7"))
+
+	surge.RecordRequest("pk-khi", start)
+	surge.RecordRequest("other", start)
+	surge.Multiplier("pk-lhe", start)
+	assert.Len(t, surge.requests, 2, "looking up an area does not track it")
+
+	// Areas whose requests have all expired are forgotten
+	surge.Refresh(start.Add(10 * time.Minute))
+	assert.Empty(t, surge.requests)
+}
+
+func TestParseSurgeTiers(t *testing.T) {
+	tiers, err := ParseSurgeTiers("10:12500, 20:15000")
+	require.NoError(t, err)
+	assert.Equal(t, []SurgeTier{
+		{MinRequests: 10, MultiplierBP: 12500},
+		{MinRequests: 20, MultiplierBP: 15000},
+	}, tiers)
+
+	tiers, err = ParseSurgeTiers("")
+	require.NoError(t, err)
+	assert.Empty(t, tiers)
+
+	for _, value := range []string{"10", "x:12500", "10:x", "0:12500", "10:9000"} {
+		_, err := ParseSurgeTiers(value)
+		assert.Error(t, err, value)
+	}
+}
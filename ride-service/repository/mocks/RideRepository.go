@@ -15,20 +15,20 @@ type RideRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: ctx, source, destination, distance, cost
-func (_m *RideRepository) Create(ctx context.Context, source string, destination string, distance int32, cost int32) (int32, error) {
-	ret := _m.Called(ctx, source, destination, distance, cost)
+// Create provides a mock function with given fields: ctx, ride
+func (_m *RideRepository) Create(ctx context.Context, ride *repository.Ride) (int32, error) {
+	ret := _m.Called(ctx, ride)
 
 	var r0 int32
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, int32, int32) int32); ok {
-		r0 = rf(ctx, source, destination, distance, cost)
+	if rf, ok := ret.Get(0).(func(context.Context, *repository.Ride) int32); ok {
+		r0 = rf(ctx, ride)
 	} else {
 		r0 = ret.Get(0).(int32)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, int32, int32) error); ok {
-		r1 = rf(ctx, source, destination, distance, cost)
+	if rf, ok := ret.Get(1).(func(context.Context, *repository.Ride) error); ok {
+		r1 = rf(ctx, ride)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,20 +59,20 @@ func (_m *RideRepository) GetByID(ctx context.Context, id int32) (*repository.Ri
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, id, source, destination, distance, cost
-func (_m *RideRepository) Update(ctx context.Context, id int32, source string, destination string, distance int32, cost int32) (string, error) {
-	ret := _m.Called(ctx, id, source, destination, distance, cost)
+// Update provides a mock function with given fields: ctx, id, ride
+func (_m *RideRepository) Update(ctx context.Context, id int32, ride *repository.Ride) (string, error) {
+	ret := _m.Called(ctx, id, ride)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(context.Context, int32, string, string, int32, int32) string); ok {
-		r0 = rf(ctx, id, source, destination, distance, cost)
+	if rf, ok := ret.Get(0).(func(context.Context, int32, *repository.Ride) string); ok {
+		r0 = rf(ctx, id, ride)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int32, string, string, int32, int32) error); ok {
-		r1 = rf(ctx, id, source, destination, distance, cost)
+	if rf, ok := ret.Get(1).(func(context.Context, int32, *repository.Ride) error); ok {
+		r1 = rf(ctx, id, ride)
 	} else {
 		r1 = ret.Error(1)
 	}
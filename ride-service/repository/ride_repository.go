@@ -5,20 +5,91 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"ride-service/geo"
+
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+// Domain events written to the outbox alongside ride state changes.
+const (
+	AggregateRide    = "ride"
+	EventRideUpdated = "RideUpdated"
+)
+
+// Audited methods, recorded in the audit log alongside ride state changes.
+const (
+	MethodCreateRide = "CreateRide"
+	MethodUpdateRide = "UpdateRide"
 )
 
+// Ride is also the snapshot recorded in the audit log.
 type Ride struct {
-	ID          int32
-	Source      string
-	Destination string
-	Distance    int32
-	Cost        int32
+	ID            int32       `json:"ride_id"`
+	Source        string      `json:"source"`
+	Destination   string      `json:"destination"`
+	Distance      int32       `json:"distance"`
+	Cost          money.Money `json:"price"`
+	VehicleClass  string      `json:"vehicle_class"`
+	TariffVersion int32       `json:"tariff_version"`
+	// SourceLocation and DestinationLocation are nil for rides created
+	// without coordinates.
+	SourceLocation      *geo.Point `json:"source_location,omitempty"`
+	DestinationLocation *geo.Point `json:"destination_location,omitempty"`
+	// SourcePlaceID and DestinationPlaceID are empty for unrecognised places.
+	SourcePlaceID      string    `json:"source_place_id,omitempty"`
+	DestinationPlaceID string    `json:"destination_place_id,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	// FareBreakdown itemizes Cost; nil for rides booked without one.
+	FareBreakdown *FareBreakdown `json:"fare_breakdown,omitempty"`
+}
+
+// FareBreakdown itemizes a fare. The adjustments are what the minimum fare
+// and the multipliers added, so the amounts add up to the fare.
+type FareBreakdown struct {
+	BaseFare              money.Money `json:"base_fare"`
+	DistanceFare          money.Money `json:"distance_fare"`
+	MinimumFareAdjustment money.Money `json:"minimum_fare_adjustment"`
+	// Multipliers are in basis points, where 10000 is 1x.
+	TimeOfDayMultiplierBP int32       `json:"time_of_day_multiplier_bp"`
+	TimeOfDayAdjustment   money.Money `json:"time_of_day_adjustment"`
+	SurgeMultiplierBP     int32       `json:"surge_multiplier_bp"`
+	SurgeAdjustment       money.Money `json:"surge_adjustment"`
+}
+
+// Total adds up the amounts of b.
+func (b *FareBreakdown) Total() (money.Money, error) {
+	total := b.BaseFare
+	for _, amount := range []money.Money{b.DistanceFare, b.MinimumFareAdjustment, b.TimeOfDayAdjustment, b.SurgeAdjustment} {
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// RideEvent is the payload of ride domain events. Cost is Price in whole
+// units, for consumers that predate Price.
+type RideEvent struct {
+	RideID      int32       `json:"ride_id"`
+	Source      string      `json:"source"`
+	Destination string      `json:"destination"`
+	Distance    int32       `json:"distance"`
+	Cost        int32       `json:"cost"`
+	Price       money.Money `json:"price"`
 }
 
 type RideRepository interface {
-	Create(ctx context.Context, source, destination string, distance, cost int32) (int32, error)
+	Create(ctx context.Context, ride *Ride) (int32, error)
 	GetByID(ctx context.Context, id int32) (*Ride, error)
-	Update(ctx context.Context, id int32, source, destination string, distance, cost int32) (string, error)
+	Update(ctx context.Context, id int32, ride *Ride) (string, error)
 }
 
 type PostgresRideRepository struct {
@@ -29,23 +100,107 @@ func NewPostgresRideRepository(db *sql.DB) RideRepository {
 	return &PostgresRideRepository{db: db}
 }
 
-func (r *PostgresRideRepository) Create(ctx context.Context, source, destination string, distance, cost int32) (int32, error) {
-	query := `INSERT INTO rides (source, destination, distance, cost) VALUES ($1, $2, $3, $4) RETURNING ride_id`
-	var rideID int32
-	err := r.db.QueryRowContext(ctx, query, source, destination, distance, cost).Scan(&rideID)
+func (r *PostgresRideRepository) Create(ctx context.Context, ride *Ride) (int32, error) {
+	legacyCost, err := WholeUnits(ride.Cost)
+	if err != nil {
+		log.Printf("Create ride failed: %v", err)
+		return 0, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		log.Printf("Create ride failed: %v", err)
 		return 0, err
 	}
-	return rideID, nil
+	defer tx.Rollback()
+
+	query := `INSERT INTO rides (source, destination, distance, cost, cost_minor, currency, vehicle_class, tariff_version,
+			source_lat, source_lng, destination_lat, destination_lng, source_place_id, destination_place_id,
+			` + breakdownColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, ''), NULLIF($14, ''),
+			$15, $16, $17, $18, $19, $20, $21) RETURNING ` + rideColumns
+	sourceLat, sourceLng := nullPoint(ride.SourceLocation)
+	destinationLat, destinationLng := nullPoint(ride.DestinationLocation)
+
+	args := []any{
+		ride.Source, ride.Destination, ride.Distance, legacyCost, ride.Cost.Minor, ride.Cost.Currency,
+		ride.VehicleClass, ride.TariffVersion,
+		sourceLat, sourceLng, destinationLat, destinationLng, ride.SourcePlaceID, ride.DestinationPlaceID,
+	}
+	created, err := scanRide(tx.QueryRowContext(ctx, query, append(args, breakdownValues(ride.FareBreakdown)...)...))
+	if err != nil {
+		log.Printf("Create ride failed: %v", err)
+		return 0, err
+	}
+
+	if err := audit.Record(ctx, tx, MethodCreateRide, nil, created); err != nil {
+		log.Printf("Create ride failed: %v", err)
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Create ride failed: %v", err)
+		return 0, err
+	}
+	return created.ID, nil
 }
 
-func (r *PostgresRideRepository) GetByID(ctx context.Context, id int32) (*Ride, error) {
-	query := `SELECT source, destination, distance, cost FROM rides WHERE ride_id = $1`
-	var source, destination string
-	var distance, cost int32
+const rideColumns = `ride_id, source, destination, distance, cost, cost_minor, currency, vehicle_class, COALESCE(tariff_version, 0),
+	source_lat, source_lng, destination_lat, destination_lng,
+	COALESCE(source_place_id, ''), COALESCE(destination_place_id, ''), created_at, updated_at, ` + breakdownColumns
+
+// breakdownColumns hold a ride's FareBreakdown, in minor units of the ride's
+// currency, in the order of breakdownValues.
+const breakdownColumns = `base_fare_minor, distance_fare_minor, minimum_fare_adjustment_minor,
+	time_of_day_multiplier_bp, time_of_day_adjustment_minor, surge_multiplier_bp, surge_adjustment_minor`
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&source, &destination, &distance, &cost)
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRide(row rowScanner) (*Ride, error) {
+	ride := &Ride{}
+	var legacyCost int32
+	var costMinor sql.NullInt64
+	var currency sql.NullString
+	var sourceLat, sourceLng, destinationLat, destinationLng sql.NullFloat64
+	var baseFare, distanceFare, minimumFareAdjustment, timeOfDayAdjustment, surgeAdjustment sql.NullInt64
+	var timeOfDayMultiplier, surgeMultiplier sql.NullInt32
+
+	err := row.Scan(&ride.ID, &ride.Source, &ride.Destination, &ride.Distance, &legacyCost, &costMinor, &currency,
+		&ride.VehicleClass, &ride.TariffVersion, &sourceLat, &sourceLng, &destinationLat, &destinationLng,
+		&ride.SourcePlaceID, &ride.DestinationPlaceID, &ride.CreatedAt, &ride.UpdatedAt,
+		&baseFare, &distanceFare, &minimumFareAdjustment, &timeOfDayMultiplier, &timeOfDayAdjustment,
+		&surgeMultiplier, &surgeAdjustment)
+	if err != nil {
+		return nil, err
+	}
+
+	if ride.Cost, err = costFromColumns(legacyCost, costMinor, currency); err != nil {
+		return nil, err
+	}
+	if baseFare.Valid {
+		amount := func(minor sql.NullInt64) money.Money {
+			return money.Money{Currency: ride.Cost.Currency, Minor: minor.Int64}
+		}
+		ride.FareBreakdown = &FareBreakdown{
+			BaseFare:              amount(baseFare),
+			DistanceFare:          amount(distanceFare),
+			MinimumFareAdjustment: amount(minimumFareAdjustment),
+			TimeOfDayMultiplierBP: timeOfDayMultiplier.Int32,
+			TimeOfDayAdjustment:   amount(timeOfDayAdjustment),
+			SurgeMultiplierBP:     surgeMultiplier.Int32,
+			SurgeAdjustment:       amount(surgeAdjustment),
+		}
+	}
+	ride.SourceLocation = pointFromNull(sourceLat, sourceLng)
+	ride.DestinationLocation = pointFromNull(destinationLat, destinationLng)
+	return ride, nil
+}
+
+func (r *PostgresRideRepository) GetByID(ctx context.Context, id int32) (*Ride, error) {
+	query := `SELECT ` + rideColumns + ` FROM rides WHERE ride_id = $1`
+	ride, err := scanRide(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("ride not found")
@@ -53,22 +208,123 @@ func (r *PostgresRideRepository) GetByID(ctx context.Context, id int32) (*Ride,
 		log.Printf("Get ride failed: %v", err)
 		return nil, err
 	}
+	return ride, nil
+}
+
+// costFromColumns reads a ride's price, falling back to the whole-unit cost
+// column for rides written by instances that predate cost_minor.
+func costFromColumns(legacyCost int32, costMinor sql.NullInt64, currency sql.NullString) (money.Money, error) {
+	if !costMinor.Valid || !currency.Valid {
+		return money.FromMajor(money.PKR, int64(legacyCost))
+	}
+	return money.New(currency.String, costMinor.Int64)
+}
 
-	return &Ride{
-		ID:          id,
-		Source:      source,
-		Destination: destination,
-		Distance:    distance,
-		Cost:        cost,
-	}, nil
+// WholeUnits rounds cost half up to whole units for the deprecated int32 cost
+// fields.
+func WholeUnits(cost money.Money) (int32, error) {
+	major, err := cost.Major(money.HalfUp)
+	if err != nil {
+		return 0, err
+	}
+	if major > math.MaxInt32 || major < math.MinInt32 {
+		return 0, money.ErrOverflow
+	}
+	return int32(major), nil
 }
 
-func (r *PostgresRideRepository) Update(ctx context.Context, id int32, source, destination string, distance, cost int32) (string, error) {
-	query := `UPDATE rides SET source = $1, destination = $2, distance = $3, cost = $4 WHERE ride_id = $5`
-	_, err := r.db.ExecContext(ctx, query, source, destination, distance, cost, id)
+// breakdownValues returns the values of breakdownColumns for b, all NULL when
+// b is nil.
+func breakdownValues(b *FareBreakdown) []any {
+	if b == nil {
+		return []any{nil, nil, nil, nil, nil, nil, nil}
+	}
+	return []any{
+		b.BaseFare.Minor, b.DistanceFare.Minor, b.MinimumFareAdjustment.Minor,
+		b.TimeOfDayMultiplierBP, b.TimeOfDayAdjustment.Minor, b.SurgeMultiplierBP, b.SurgeAdjustment.Minor,
+	}
+}
+
+func nullPoint(p *geo.Point) (sql.NullFloat64, sql.NullFloat64) {
+	if p == nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: p.Lat, Valid: true}, sql.NullFloat64{Float64: p.Lng, Valid: true}
+}
+
+func pointFromNull(lat, lng sql.NullFloat64) *geo.Point {
+	if !lat.Valid || !lng.Valid {
+		return nil
+	}
+	return &geo.Point{Lat: lat.Float64, Lng: lng.Float64}
+}
+
+func (r *PostgresRideRepository) Update(ctx context.Context, id int32, ride *Ride) (string, error) {
+	legacyCost, err := WholeUnits(ride.Cost)
+	if err != nil {
+		log.Printf("Update ride failed: %v", err)
+		return "", err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		log.Printf("Update ride failed: %v", err)
 		return "", err
 	}
+	defer tx.Rollback()
+
+	before, err := scanRide(tx.QueryRowContext(ctx, `SELECT `+rideColumns+` FROM rides WHERE ride_id = $1 FOR UPDATE`, id))
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Update ride failed: %v", err)
+		return "", err
+	}
+
+	// Only emit an event and audit entry when a ride was actually changed
+	if before != nil {
+		query := `UPDATE rides SET source = $1, destination = $2, distance = $3, cost = $4, cost_minor = $5, currency = $6,
+				vehicle_class = $7, tariff_version = $8,
+				source_lat = $9, source_lng = $10, destination_lat = $11, destination_lng = $12,
+				source_place_id = NULLIF($13, ''), destination_place_id = NULLIF($14, ''),
+				(` + breakdownColumns + `) = ($15, $16, $17, $18, $19, $20, $21), updated_at = now()
+			WHERE ride_id = $22 RETURNING ` + rideColumns
+		sourceLat, sourceLng := nullPoint(ride.SourceLocation)
+		destinationLat, destinationLng := nullPoint(ride.DestinationLocation)
+
+		args := []any{
+			ride.Source, ride.Destination, ride.Distance, legacyCost, ride.Cost.Minor, ride.Cost.Currency,
+			ride.VehicleClass, ride.TariffVersion,
+			sourceLat, sourceLng, destinationLat, destinationLng, ride.SourcePlaceID, ride.DestinationPlaceID,
+		}
+		args = append(append(args, breakdownValues(ride.FareBreakdown)...), id)
+		after, err := scanRide(tx.QueryRowContext(ctx, query, args...))
+		if err != nil {
+			log.Printf("Update ride failed: %v", err)
+			return "", err
+		}
+
+		event := RideEvent{
+			RideID:      id,
+			Source:      ride.Source,
+			Destination: ride.Destination,
+			Distance:    ride.Distance,
+			Cost:        legacyCost,
+			Price:       ride.Cost,
+		}
+		if err := outbox.Record(ctx, tx, AggregateRide, strconv.Itoa(int(id)), EventRideUpdated, event); err != nil {
+			log.Printf("Update ride failed: %v", err)
+			return "", err
+		}
+
+		if err := audit.Record(ctx, tx, MethodUpdateRide, before, after); err != nil {
+			log.Printf("Update ride failed: %v", err)
+			return "", err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Update ride failed: %v", err)
+		return "", err
+	}
+
 	return fmt.Sprintf("Ride %d updated successfully", id), nil
 }
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// TimeBand applies a fare multiplier between StartHour (inclusive) and EndHour
+// (exclusive) in the pricing time zone. A band whose EndHour is not after its
+// StartHour wraps past midnight.
+type TimeBand struct {
+	StartHour int
+	EndHour   int
+	// MultiplierBP is the multiplier in basis points, where 10000 is 1x.
+	MultiplierBP int32
+}
+
+// Tariff is one version of the fare table for a vehicle class. Amounts are in
+// whole units of Currency.
+type Tariff struct {
+	ID            int32
+	VehicleClass  string
+	Version       int32
+	Currency      string
+	BaseFare      int32
+	PerKmRate     int32
+	MinimumFare   int32
+	EffectiveFrom time.Time
+	TimeBands     []TimeBand
+}
+
+type TariffRepository interface {
+	// GetActive returns the highest tariff version for vehicleClass that is
+	// effective at the given time.
+	GetActive(ctx context.Context, vehicleClass string, at time.Time) (*Tariff, error)
+}
+
+type PostgresTariffRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTariffRepository(db *sql.DB) TariffRepository {
+	return &PostgresTariffRepository{db: db}
+}
+
+func (r *PostgresTariffRepository) GetActive(ctx context.Context, vehicleClass string, at time.Time) (*Tariff, error) {
+	query := `SELECT tariff_id, version, currency, base_fare, per_km_rate, minimum_fare, effective_from
+		FROM tariffs
+		WHERE vehicle_class = $1 AND effective_from <= $2
+		ORDER BY version DESC
+		LIMIT 1`
+	tariff := &Tariff{VehicleClass: vehicleClass}
+	err := r.db.QueryRowContext(ctx, query, vehicleClass, at).Scan(
+		&tariff.ID, &tariff.Version, &tariff.Currency, &tariff.BaseFare, &tariff.PerKmRate, &tariff.MinimumFare, &tariff.EffectiveFrom,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tariff not found")
+		}
+		log.Printf("Get active tariff failed: %v", err)
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT start_hour, end_hour, multiplier_bp FROM tariff_time_bands WHERE tariff_id = $1 ORDER BY start_hour`,
+		tariff.ID)
+	if err != nil {
+		log.Printf("Get tariff time bands failed: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var band TimeBand
+		if err := rows.Scan(&band.StartHour, &band.EndHour, &band.MultiplierBP); err != nil {
+			log.Printf("Get tariff time bands failed: %v", err)
+			return nil, err
+		}
+		tariff.TimeBands = append(tariff.TimeBands, band)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Get tariff time bands failed: %v", err)
+		return nil, err
+	}
+
+	return tariff, nil
+}
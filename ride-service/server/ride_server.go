@@ -3,11 +3,22 @@ package server
 import (
 	"context"
 	"fmt"
+	"math"
+	"time"
 
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"ride-service/gazetteer"
+	"ride-service/geo"
 	pb "ride-service/pb/proto/ride"
+	"ride-service/pricing"
 	"ride-service/repository"
 
+	"github.com/hasnain-zafar/go-microservices/common/audit"
 	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	auditpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
 
 	"github.com/hasnain-zafar/go-microservices/common/errors"
 	"github.com/hasnain-zafar/go-microservices/common/logger"
@@ -16,41 +27,114 @@ import (
 type RideServer struct {
 	pb.UnimplementedRideServiceServer
 	repo         repository.RideRepository
+	pricing      *pricing.Engine
+	router       geo.RoutingProvider
+	places       *gazetteer.Gazetteer
 	logger       *logger.Logger
 	errorHandler *errors.ErrorHandler
 	serviceName  string
+
+	// auditLog is nil when QueryAuditLog is not enabled.
+	auditLog audit.Log
+}
+
+// Option configures optional RideServer features.
+type Option func(*RideServer)
+
+// WithMetrics counts the server's errors in reg rather than in a registry of
+// its own.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(s *RideServer) {
+		s.logger = s.logger.WithMetrics(reg)
+		s.errorHandler = errors.NewErrorHandler(s.logger)
+	}
 }
 
-func NewRideServer(repo repository.RideRepository) *RideServer {
+// WithAuditLog enables QueryAuditLog for callers authenticated as
+// audit.Admin.
+func WithAuditLog(log audit.Log) Option {
+	return func(s *RideServer) {
+		s.auditLog = log
+	}
+}
+
+// Claimed distances may differ from the routed distance by this fraction, or
+// by distanceSlackKm for short trips, before they are rejected.
+const (
+	maxDistanceDeviation = 0.5
+	distanceSlackKm      = 5
+)
+
+// Coordinates sent for a recognised place must lie within placeRadiusKm of
+// it.
+const placeRadiusKm = 50
+
+// SearchPlaces returns defaultPlaceLimit places unless asked for more, up to
+// maxPlaceLimit.
+const (
+	defaultPlaceLimit = 10
+	maxPlaceLimit     = 50
+)
+
+func NewRideServer(repo repository.RideRepository, pricingEngine *pricing.Engine, router geo.RoutingProvider, places *gazetteer.Gazetteer, opts ...Option) *RideServer {
 	serviceName := "ride-service"
 	log := logger.NewLogger(serviceName)
-	return &RideServer{
+	s := &RideServer{
 		repo:         repo,
+		pricing:      pricingEngine,
+		router:       router,
+		places:       places,
 		logger:       log,
 		errorHandler: errors.NewErrorHandler(log),
 		serviceName:  serviceName,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *RideServer) CreateRide(ctx context.Context, req *pb.CreateRideRequest) (*pb.CreateRideResponse, error) {
 	method := "CreateRide"
 	metrics.IncrementRequestCounter(s.serviceName, method)
-	s.logger.LogRequest(method, req)
+	s.logger.LogRequest(ctx, method, req)
 
 	if err := validateCreateRideRequest(req); err != nil {
 		return nil, s.errorHandler.HandleInvalidArgument("invalid ride request", err)
 	}
 
-	rideID, err := s.repo.Create(ctx, req.Source, req.Destination, req.Distance, req.Cost)
+	quote, err := s.verifyQuote(req.Quote, req.Source, req.Destination, req.Distance)
+	if err != nil {
+		return nil, err
+	}
+
+	ride := &repository.Ride{
+		Source:        req.Source,
+		Destination:   req.Destination,
+		Distance:      req.Distance,
+		Cost:          quote.Fare,
+		VehicleClass:  quote.VehicleClass,
+		TariffVersion: quote.TariffVersion,
+		FareBreakdown: quote.Breakdown,
+	}
+	if err := s.locate(ctx, ride, req.SourceLocation, req.DestinationLocation); err != nil {
+		return nil, err
+	}
+
+	rideID, err := s.repo.Create(ctx, ride)
 	if err != nil {
 		return nil, s.errorHandler.HandleDatabaseError("failed to create ride", err)
 	}
+	// Only rides that were made count towards demand
+	s.pricing.RecordBooking(req.Source)
 
 	res := &pb.CreateRideResponse{
-		RideId: rideID,
+		RideId:         rideID,
+		SourceLocation: pointToProto(ride.SourceLocation),
+		SourcePlaceId:  ride.SourcePlaceID,
 	}
 
-	s.logger.LogResponse(method, res)
+	s.logger.LogResponse(ctx, method, res)
 
 	return res, nil
 }
@@ -58,7 +142,7 @@ func (s *RideServer) CreateRide(ctx context.Context, req *pb.CreateRideRequest)
 func (s *RideServer) GetRide(ctx context.Context, req *pb.GetRideRequest) (*pb.Ride, error) {
 	method := "GetRide"
 	metrics.IncrementRequestCounter(s.serviceName, method)
-	s.logger.LogRequest(method, req)
+	s.logger.LogRequest(ctx, method, req)
 
 	if req.GetRideId() <= 0 {
 		return nil, s.errorHandler.HandleInvalidArgument("invalid ride ID", fmt.Errorf("ride ID must be positive"))
@@ -73,14 +157,25 @@ func (s *RideServer) GetRide(ctx context.Context, req *pb.GetRideRequest) (*pb.R
 	}
 
 	res := &pb.Ride{
-		RideId:      ride.ID,
-		Source:      ride.Source,
-		Destination: ride.Destination,
-		Distance:    ride.Distance,
-		Cost:        ride.Cost,
+		RideId:        ride.ID,
+		Source:        ride.Source,
+		Destination:   ride.Destination,
+		Distance:      ride.Distance,
+		Cost:          legacyUnits(ride.Cost),
+		Price:         money.ToProto(ride.Cost),
+		VehicleClass:  ride.VehicleClass,
+		TariffVersion: ride.TariffVersion,
+
+		SourceLocation:      pointToProto(ride.SourceLocation),
+		DestinationLocation: pointToProto(ride.DestinationLocation),
+		SourcePlaceId:       ride.SourcePlaceID,
+		DestinationPlaceId:  ride.DestinationPlaceID,
+		CreatedAt:           optionalTimestamp(ride.CreatedAt),
+		UpdatedAt:           optionalTimestamp(ride.UpdatedAt),
+		FareBreakdown:       breakdownToProto(ride.FareBreakdown),
 	}
 
-	s.logger.LogResponse(method, res)
+	s.logger.LogResponse(ctx, method, res)
 
 	return res, nil
 }
@@ -88,7 +183,7 @@ func (s *RideServer) GetRide(ctx context.Context, req *pb.GetRideRequest) (*pb.R
 func (s *RideServer) UpdateRide(ctx context.Context, req *pb.UpdateRideRequest) (*pb.UpdateRideResponse, error) {
 	method := "UpdateRide"
 	metrics.IncrementRequestCounter(s.serviceName, method)
-	s.logger.LogRequest(method, req)
+	s.logger.LogRequest(ctx, method, req)
 
 	if req.GetRideId() <= 0 {
 		return nil, s.errorHandler.HandleInvalidArgument("invalid ride ID", fmt.Errorf("ride ID must be positive"))
@@ -102,8 +197,26 @@ func (s *RideServer) UpdateRide(ctx context.Context, req *pb.UpdateRideRequest)
 	if err := validateRideDetails(r); err != nil {
 		return nil, s.errorHandler.HandleInvalidArgument("invalid ride details", err)
 	}
+	// As on CreateRide, the fare comes from a signed quote for the new route
+	quote, err := s.verifyQuote(req.Quote, r.Source, r.Destination, r.Distance)
+	if err != nil {
+		return nil, err
+	}
+
+	ride := &repository.Ride{
+		Source:        r.Source,
+		Destination:   r.Destination,
+		Distance:      r.Distance,
+		Cost:          quote.Fare,
+		VehicleClass:  quote.VehicleClass,
+		TariffVersion: quote.TariffVersion,
+		FareBreakdown: quote.Breakdown,
+	}
+	if err := s.locate(ctx, ride, r.SourceLocation, r.DestinationLocation); err != nil {
+		return nil, err
+	}
 
-	message, err := s.repo.Update(ctx, req.RideId, r.Source, r.Destination, r.Distance, r.Cost)
+	message, err := s.repo.Update(ctx, req.RideId, ride)
 	if err != nil {
 		return nil, s.errorHandler.HandleDatabaseError("failed to update ride", err)
 	}
@@ -112,7 +225,99 @@ func (s *RideServer) UpdateRide(ctx context.Context, req *pb.UpdateRideRequest)
 		Message: message,
 	}
 
-	s.logger.LogResponse(method, res)
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+// QuoteFare prices a trip with the active tariff and returns a signed quote
+// that must be presented to CreateRide or UpdateRide.
+func (s *RideServer) QuoteFare(ctx context.Context, req *pb.QuoteFareRequest) (*pb.FareQuote, error) {
+	method := "QuoteFare"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if err := validateQuoteFareRequest(req); err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid quote request", err)
+	}
+	// A signed quote vouches for its distance, so check it before signing
+	if _, _, err := s.route(ctx, req.Source, req.Destination, req.Distance, req.SourceLocation, req.DestinationLocation); err != nil {
+		return nil, err
+	}
+
+	quote, err := s.pricing.Quote(ctx, req.Source, req.Destination, req.Distance, req.VehicleClass)
+	if err != nil {
+		if err.Error() == "tariff not found" {
+			return nil, s.errorHandler.HandleInvalidArgument("unsupported vehicle class", err)
+		}
+		return nil, s.errorHandler.HandleDatabaseError("failed to quote fare", err)
+	}
+
+	res := quoteToProto(quote)
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *RideServer) SearchPlaces(ctx context.Context, req *pb.SearchPlacesRequest) (*pb.SearchPlacesResponse, error) {
+	method := "SearchPlaces"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if gazetteer.Normalize(req.Query) == "" {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid search request", fmt.Errorf("query cannot be empty"))
+	}
+	if req.Limit < 0 {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid search request", fmt.Errorf("limit cannot be negative"))
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultPlaceLimit
+	}
+	if limit > maxPlaceLimit {
+		limit = maxPlaceLimit
+	}
+
+	res := &pb.SearchPlacesResponse{}
+	for _, place := range s.places.Search(req.Query, limit) {
+		res.Places = append(res.Places, &pb.Place{
+			PlaceId:  place.ID,
+			Name:     place.Name,
+			Location: pointToProto(&place.Location),
+			Aliases:  place.Aliases,
+		})
+	}
+
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *RideServer) QueryAuditLog(ctx context.Context, req *auditpb.QueryAuditLogRequest) (*auditpb.QueryAuditLogResponse, error) {
+	method := "QueryAuditLog"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.auditLog == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("audit log is not enabled", fmt.Errorf("no audit log configured"))
+	}
+	if !audit.IsAdmin(ctx) {
+		return nil, s.errorHandler.HandlePermissionDenied("audit log is for admins only", fmt.Errorf("caller is %s, not %s", audit.Actor(ctx), audit.Admin))
+	}
+
+	filter, err := audit.FilterFromProto(req)
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid audit log query", err)
+	}
+
+	res, err := audit.Query(ctx, s.auditLog, filter, req.GetVerify())
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to query audit log", err)
+	}
+
+	s.logger.LogResponse(ctx, method, res)
 
 	return res, nil
 }
@@ -127,12 +332,259 @@ func validateCreateRideRequest(req *pb.CreateRideRequest) error {
 	if req.Distance <= 0 {
 		return fmt.Errorf("distance must be positive")
 	}
-	if req.Cost <= 0 {
-		return fmt.Errorf("cost must be positive")
+	if req.Quote == nil {
+		return fmt.Errorf("fare quote is required")
+	}
+	return validateLocations(req.SourceLocation, req.DestinationLocation)
+}
+
+// validateLocations checks optional ride coordinates, which must be given
+// together.
+func validateLocations(source, destination *pb.LatLng) error {
+	if (source == nil) != (destination == nil) {
+		return fmt.Errorf("source and destination locations must be set together")
+	}
+	if source != nil {
+		if err := pointFromProto(source).Validate(); err != nil {
+			return fmt.Errorf("invalid source location: %w", err)
+		}
+		if err := pointFromProto(destination).Validate(); err != nil {
+			return fmt.Errorf("invalid destination location: %w", err)
+		}
 	}
 	return nil
 }
 
+// locate sets the coordinates and place IDs of ride and checks its distance,
+// as route does.
+func (s *RideServer) locate(ctx context.Context, ride *repository.Ride, sourceLocation, destinationLocation *pb.LatLng) error {
+	source, destination, err := s.route(ctx, ride.Source, ride.Destination, ride.Distance, sourceLocation, destinationLocation)
+	if err != nil {
+		return err
+	}
+	ride.SourceLocation, ride.SourcePlaceID = &source.location, source.placeID
+	ride.DestinationLocation, ride.DestinationPlaceID = &destination.location, destination.placeID
+	return nil
+}
+
+// endpoint is one end of a trip, as resolved by resolveEndpoint.
+type endpoint struct {
+	// location is the coordinates sent, or else those of the place.
+	location geo.Point
+	// routed is what distances are checked from: the place's own
+	// coordinates when it is recognised, so that nearby points cannot
+	// shorten a trip.
+	routed  geo.Point
+	placeID string
+}
+
+// route resolves both ends of a trip and checks the claimed distance against
+// the route between them.
+func (s *RideServer) route(ctx context.Context, source, destination string, distance int32, sourceLocation, destinationLocation *pb.LatLng) (endpoint, endpoint, error) {
+	from, err := s.resolveEndpoint(source, sourceLocation)
+	if err != nil {
+		return endpoint{}, endpoint{}, s.errorHandler.HandleInvalidArgument("invalid source location", err)
+	}
+	to, err := s.resolveEndpoint(destination, destinationLocation)
+	if err != nil {
+		return endpoint{}, endpoint{}, s.errorHandler.HandleInvalidArgument("invalid destination location", err)
+	}
+	if err := s.checkDistance(ctx, distance, from.routed, to.routed); err != nil {
+		return endpoint{}, endpoint{}, err
+	}
+	return from, to, nil
+}
+
+// resolveEndpoint looks name up in the gazetteer. Recognised places keep any
+// coordinates sent as the precise pickup or drop-off, provided they lie near
+// the place; places it does not recognise must come with coordinates.
+func (s *RideServer) resolveEndpoint(name string, location *pb.LatLng) (endpoint, error) {
+	point := pointFromProto(location)
+	place, ok := s.places.Lookup(name)
+	if !ok {
+		if point == nil {
+			return endpoint{}, fmt.Errorf("coordinates are required for unrecognised place %q", name)
+		}
+		return endpoint{location: *point, routed: *point}, nil
+	}
+
+	e := endpoint{location: place.Location, routed: place.Location, placeID: place.ID}
+	if point != nil {
+		if d := geo.Haversine(*point, place.Location); d > placeRadiusKm {
+			return endpoint{}, fmt.Errorf("coordinates are %.0f km from %s", d, place.Name)
+		}
+		e.location = *point
+	}
+	return e, nil
+}
+
+// verifyQuote checks that q was signed by this service, has not expired and
+// prices the given route.
+func (s *RideServer) verifyQuote(q *pb.FareQuote, source, destination string, distance int32) (*pricing.Quote, error) {
+	quote := quoteFromProto(q)
+	if err := s.pricing.Verify(quote); err != nil {
+		if err == pricing.ErrQuoteExpired {
+			return nil, s.errorHandler.HandleFailedPrecondition("fare quote expired", err)
+		}
+		return nil, s.errorHandler.HandleInvalidArgument("invalid fare quote", err)
+	}
+	if quote.Source != source || quote.Destination != destination || quote.Distance != distance {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid fare quote", fmt.Errorf("quote does not match the requested ride"))
+	}
+	return quote, nil
+}
+
+// checkDistance rejects a claimed distance that deviates too far from the
+// routed distance between from and to.
+func (s *RideServer) checkDistance(ctx context.Context, claimed int32, from, to geo.Point) error {
+	computed, err := s.router.Distance(ctx, from, to)
+	if err != nil {
+		if err == geo.ErrNoRoute {
+			return s.errorHandler.HandleInvalidArgument("invalid ride locations", err)
+		}
+		return s.errorHandler.HandleInternalError("failed to compute distance", err)
+	}
+
+	allowed := math.Max(computed*maxDistanceDeviation, distanceSlackKm)
+	if math.Abs(float64(claimed)-computed) > allowed {
+		return s.errorHandler.HandleInvalidArgument("invalid distance",
+			fmt.Errorf("claimed distance %d km does not match computed distance %.0f km", claimed, computed))
+	}
+	return nil
+}
+
+func validateQuoteFareRequest(req *pb.QuoteFareRequest) error {
+	if req.Source == "" {
+		return fmt.Errorf("source cannot be empty")
+	}
+	if req.Destination == "" {
+		return fmt.Errorf("destination cannot be empty")
+	}
+	if req.Distance <= 0 {
+		return fmt.Errorf("distance must be positive")
+	}
+	return validateLocations(req.SourceLocation, req.DestinationLocation)
+}
+
+func quoteToProto(q *pricing.Quote) *pb.FareQuote {
+	return &pb.FareQuote{
+		Source:        q.Source,
+		Destination:   q.Destination,
+		Distance:      q.Distance,
+		VehicleClass:  q.VehicleClass,
+		Fare:          legacyUnits(q.Fare),
+		Price:         money.ToProto(q.Fare),
+		TariffVersion: q.TariffVersion,
+		ExpiresAt:     timestamppb.New(q.ExpiresAt),
+		Signature:     q.Signature,
+
+		SurgeMultiplierBp:  q.SurgeMultiplierBP,
+		SurgeExpiresAt:     optionalTimestamp(q.SurgeExpiresAt),
+		Breakdown:          breakdownToProto(q.Breakdown),
+		BreakdownSignature: q.BreakdownSignature,
+	}
+}
+
+func quoteFromProto(q *pb.FareQuote) *pricing.Quote {
+	return &pricing.Quote{
+		Source:        q.GetSource(),
+		Destination:   q.GetDestination(),
+		Distance:      q.GetDistance(),
+		VehicleClass:  q.GetVehicleClass(),
+		Fare:          quotedPrice(q),
+		TariffVersion: q.GetTariffVersion(),
+		ExpiresAt:     q.GetExpiresAt().AsTime(),
+		Signature:     q.GetSignature(),
+
+		SurgeMultiplierBP:  q.GetSurgeMultiplierBp(),
+		SurgeExpiresAt:     optionalTime(q.GetSurgeExpiresAt()),
+		Breakdown:          breakdownFromProto(q.GetBreakdown()),
+		BreakdownSignature: q.GetBreakdownSignature(),
+	}
+}
+
+func breakdownToProto(b *repository.FareBreakdown) *pb.FareBreakdown {
+	if b == nil {
+		return nil
+	}
+	return &pb.FareBreakdown{
+		BaseFare:              money.ToProto(b.BaseFare),
+		DistanceFare:          money.ToProto(b.DistanceFare),
+		MinimumFareAdjustment: money.ToProto(b.MinimumFareAdjustment),
+		TimeOfDayMultiplierBp: b.TimeOfDayMultiplierBP,
+		TimeOfDayAdjustment:   money.ToProto(b.TimeOfDayAdjustment),
+		SurgeMultiplierBp:     b.SurgeMultiplierBP,
+		SurgeAdjustment:       money.ToProto(b.SurgeAdjustment),
+	}
+}
+
+// breakdownFromProto reads a quote's breakdown as sent, like quotedPrice.
+func breakdownFromProto(b *pb.FareBreakdown) *repository.FareBreakdown {
+	if b == nil {
+		return nil
+	}
+	amount := func(p *moneypb.Money) money.Money {
+		return money.Money{Currency: p.GetCurrencyCode(), Minor: p.GetMinorUnits()}
+	}
+	return &repository.FareBreakdown{
+		BaseFare:              amount(b.GetBaseFare()),
+		DistanceFare:          amount(b.GetDistanceFare()),
+		MinimumFareAdjustment: amount(b.GetMinimumFareAdjustment()),
+		TimeOfDayMultiplierBP: b.GetTimeOfDayMultiplierBp(),
+		TimeOfDayAdjustment:   amount(b.GetTimeOfDayAdjustment()),
+		SurgeMultiplierBP:     b.GetSurgeMultiplierBp(),
+		SurgeAdjustment:       amount(b.GetSurgeAdjustment()),
+	}
+}
+
+// quotedPrice reads a quote's price as sent, leaving the signature to reject
+// anything altered. Quotes from clients that predate price only carry fare.
+func quotedPrice(q *pb.FareQuote) money.Money {
+	if p := q.GetPrice(); p != nil {
+		return money.Money{Currency: p.CurrencyCode, Minor: p.MinorUnits}
+	}
+	fare, _ := money.FromMajor(money.PKR, int64(q.GetFare()))
+	return fare
+}
+
+// legacyUnits fills a deprecated whole-unit amount field, or 0 if m does not
+// fit.
+func legacyUnits(m money.Money) int32 {
+	units, err := repository.WholeUnits(m)
+	if err != nil {
+		return 0
+	}
+	return units
+}
+
+func pointFromProto(p *pb.LatLng) *geo.Point {
+	if p == nil {
+		return nil
+	}
+	return &geo.Point{Lat: p.Lat, Lng: p.Lng}
+}
+
+func pointToProto(p *geo.Point) *pb.LatLng {
+	if p == nil {
+		return nil
+	}
+	return &pb.LatLng{Lat: p.Lat, Lng: p.Lng}
+}
+
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func optionalTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
 func validateRideDetails(ride *pb.Ride) error {
 	if ride.Source == "" {
 		return fmt.Errorf("source cannot be empty")
@@ -143,8 +595,5 @@ func validateRideDetails(ride *pb.Ride) error {
 	if ride.Distance <= 0 {
 		return fmt.Errorf("distance must be positive")
 	}
-	if ride.Cost <= 0 {
-		return fmt.Errorf("cost must be positive")
-	}
-	return nil
+	return validateLocations(ride.SourceLocation, ride.DestinationLocation)
 }
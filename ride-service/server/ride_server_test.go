@@ -4,29 +4,112 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"ride-service/gazetteer"
+	"ride-service/geo"
 	pb "ride-service/pb/proto/ride"
+	"ride-service/pricing"
 	"ride-service/repository"
 	"ride-service/repository/mocks"
 
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	auditmocks "github.com/hasnain-zafar/go-microservices/common/audit/mocks"
+	"github.com/hasnain-zafar/go-microservices/common/money"
+	auditpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+	moneypb "github.com/hasnain-zafar/go-microservices/common/pb/proto/money"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+var testSigner = pricing.NewSigner([]byte("test-signing-key"))
+
+var testPlaces = func() *gazetteer.Gazetteer {
+	places, err := gazetteer.Default()
+	if err != nil {
+		panic(err)
+	}
+	return places
+}()
+
+func newTestPricingEngine(tariffs repository.TariffRepository) *pricing.Engine {
+	return pricing.NewEngine(tariffs, testSigner, time.UTC, 5*time.Minute, nil)
+}
+
+func newTestRideServer(repo repository.RideRepository) *RideServer {
+	return NewRideServer(repo, newTestPricingEngine(new(mocks.TariffRepository)), geo.HaversineProvider{}, testPlaces)
+}
+
+var (
+	newYork = &pb.LatLng{Lat: 40.7128, Lng: -74.0060}
+	boston  = &pb.LatLng{Lat: 42.3601, Lng: -71.0589}
+	lahore  = &pb.LatLng{Lat: 31.5204, Lng: 74.3587}
+)
+
+func pkr(rupees int64) money.Money {
+	return money.Money{Currency: money.PKR, Minor: rupees * 100}
+}
+
+// signedQuote returns a valid quote for the New York to Boston test ride.
+func signedQuote(fare int64) *pb.FareQuote {
+	quote := &pricing.Quote{
+		Source:        "New York",
+		Destination:   "Boston",
+		Distance:      200,
+		VehicleClass:  "ECONOMY",
+		Fare:          pkr(fare),
+		TariffVersion: 1,
+		ExpiresAt:     time.Now().Add(time.Minute).Truncate(time.Second),
+	}
+	testSigner.Sign(quote)
+	return quoteToProto(quote)
+}
+
+// khiToLahore returns a valid quote for a ride between two gazetteer places.
+func khiToLahore(distance int32) *pb.FareQuote {
+	quote := &pricing.Quote{
+		Source:        "KHI",
+		Destination:   "lahore ",
+		Distance:      distance,
+		VehicleClass:  "ECONOMY",
+		Fare:          pkr(5300),
+		TariffVersion: 1,
+		ExpiresAt:     time.Now().Add(time.Minute).Truncate(time.Second),
+	}
+	testSigner.Sign(quote)
+	return quoteToProto(quote)
+}
+
 func TestCreateRide_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.RideRepository)
-	rideServer := NewRideServer(mockRepo)
+	rideServer := newTestRideServer(mockRepo)
 
 	ctx := context.Background()
 	req := &pb.CreateRideRequest{
-		Source:      "New York",
-		Destination: "Boston",
-		Distance:    200,
-		Cost:        150,
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Quote:               signedQuote(150),
+		SourceLocation:      newYork,
+		DestinationLocation: boston,
 	}
 
-	// Expectations
-	mockRepo.On("Create", ctx, "New York", "Boston", int32(200), int32(150)).Return(int32(1), nil)
+	// Expectations: the cost comes from the quote
+	mockRepo.On("Create", ctx, &repository.Ride{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Cost:                pkr(150),
+		VehicleClass:        "ECONOMY",
+		TariffVersion:       1,
+		SourceLocation:      &geo.Point{Lat: 40.7128, Lng: -74.0060},
+		DestinationLocation: &geo.Point{Lat: 42.3601, Lng: -71.0589},
+	}).Return(int32(1), nil)
 
 	// Action
 	resp, err := rideServer.CreateRide(ctx, req)
@@ -38,11 +121,114 @@ func TestCreateRide_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateRide_LegacyQuoteFare(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.RideRepository)
+	rideServer := newTestRideServer(mockRepo)
+
+	ctx := context.Background()
+	quote := signedQuote(150)
+	quote.Price = nil
+
+	// Expectations: a quote from a client that only passes fare on is read
+	// as whole rupees and still verifies
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(ride *repository.Ride) bool {
+		return ride.Cost == pkr(150)
+	})).Return(int32(1), nil)
+
+	// Action
+	_, err := rideServer.CreateRide(ctx, &pb.CreateRideRequest{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Quote:               quote,
+		SourceLocation:      newYork,
+		DestinationLocation: boston,
+	})
+
+	// Assertions
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateRide_FareBreakdown(t *testing.T) {
+	breakdown := &repository.FareBreakdown{
+		BaseFare:              pkr(50),
+		DistanceFare:          pkr(80),
+		MinimumFareAdjustment: pkr(0),
+		TimeOfDayMultiplierBP: 10000,
+		TimeOfDayAdjustment:   pkr(0),
+		SurgeMultiplierBP:     11500,
+		SurgeAdjustment:       pkr(20),
+	}
+	quote := &pricing.Quote{
+		Source:        "New York",
+		Destination:   "Boston",
+		Distance:      200,
+		VehicleClass:  "ECONOMY",
+		Fare:          pkr(150),
+		TariffVersion: 1,
+		ExpiresAt:     time.Now().Add(time.Minute).Truncate(time.Second),
+		Breakdown:     breakdown,
+	}
+	testSigner.Sign(quote)
+
+	mockRepo := new(mocks.RideRepository)
+	rideServer := newTestRideServer(mockRepo)
+	ctx := context.Background()
+
+	// Expectations: the quote's breakdown is stored with the ride
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(ride *repository.Ride) bool {
+		return assert.ObjectsAreEqual(breakdown, ride.FareBreakdown)
+	})).Return(int32(1), nil)
+
+	_, err := rideServer.CreateRide(ctx, &pb.CreateRideRequest{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Quote:               quoteToProto(quote),
+		SourceLocation:      newYork,
+		DestinationLocation: boston,
+	})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// The breakdown cannot be altered, even to move the surge into the base fare
+	tampered := quoteToProto(quote)
+	tampered.Breakdown.SurgeAdjustment.MinorUnits = 0
+	tampered.Breakdown.BaseFare.MinorUnits = 7000
+	_, err = rideServer.CreateRide(ctx, &pb.CreateRideRequest{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Quote:               tampered,
+		SourceLocation:      newYork,
+		DestinationLocation: boston,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
 func TestCreateRide_InvalidRequest(t *testing.T) {
+	tampered := signedQuote(150)
+	tampered.Price.MinorUnits = 100
+
+	expired := &pricing.Quote{
+		Source:        "New York",
+		Destination:   "Boston",
+		Distance:      200,
+		VehicleClass:  "ECONOMY",
+		Fare:          pkr(150),
+		TariffVersion: 1,
+		ExpiresAt:     time.Now().Add(-time.Minute),
+	}
+	testSigner.Sign(expired)
+
 	// Create a set of test cases for different validation failures
 	testCases := []struct {
 		name string
 		req  *pb.CreateRideRequest
+		code codes.Code
 	}{
 		{
 			name: "Empty Source",
@@ -50,44 +236,149 @@ func TestCreateRide_InvalidRequest(t *testing.T) {
 				Source:      "",
 				Destination: "Boston",
 				Distance:    200,
-				Cost:        150,
+				Quote:       signedQuote(150),
 			},
+			code: codes.InvalidArgument,
 		},
 		{
 			name: "Empty Destination",
 			req: &pb.CreateRideRequest{
-				Source:      "New York",
-				Destination: "",
-				Distance:    200,
-				Cost:        150,
+				Source:              "New York",
+				Destination:         "",
+				Distance:            200,
+				Quote:               signedQuote(150),
+				SourceLocation:      newYork,
+				DestinationLocation: boston,
 			},
+			code: codes.InvalidArgument,
 		},
 		{
 			name: "Zero Distance",
 			req: &pb.CreateRideRequest{
-				Source:      "New York",
-				Destination: "Boston",
-				Distance:    0,
-				Cost:        150,
+				Source:              "New York",
+				Destination:         "Boston",
+				Distance:            0,
+				Quote:               signedQuote(150),
+				SourceLocation:      newYork,
+				DestinationLocation: boston,
 			},
+			code: codes.InvalidArgument,
 		},
 		{
 			name: "Negative Distance",
 			req: &pb.CreateRideRequest{
-				Source:      "New York",
-				Destination: "Boston",
-				Distance:    -10,
-				Cost:        150,
+				Source:              "New York",
+				Destination:         "Boston",
+				Distance:            -10,
+				Quote:               signedQuote(150),
+				SourceLocation:      newYork,
+				DestinationLocation: boston,
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Missing Quote",
+			req: &pb.CreateRideRequest{
+				Source:              "New York",
+				Destination:         "Boston",
+				Distance:            200,
+				SourceLocation:      newYork,
+				DestinationLocation: boston,
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Tampered Quote",
+			req: &pb.CreateRideRequest{
+				Source:              "New York",
+				Destination:         "Boston",
+				Distance:            200,
+				Quote:               tampered,
+				SourceLocation:      newYork,
+				DestinationLocation: boston,
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Quote For Different Ride",
+			req: &pb.CreateRideRequest{
+				Source:              "New York",
+				Destination:         "Boston",
+				Distance:            20,
+				Quote:               signedQuote(150),
+				SourceLocation:      newYork,
+				DestinationLocation: boston,
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Expired Quote",
+			req: &pb.CreateRideRequest{
+				Source:              "New York",
+				Destination:         "Boston",
+				Distance:            200,
+				Quote:               quoteToProto(expired),
+				SourceLocation:      newYork,
+				DestinationLocation: boston,
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "Source Location Without Destination",
+			req: &pb.CreateRideRequest{
+				Source:         "New York",
+				Destination:    "Boston",
+				Distance:       200,
+				Quote:          signedQuote(150),
+				SourceLocation: newYork,
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Invalid Latitude",
+			req: &pb.CreateRideRequest{
+				Source:              "New York",
+				Destination:         "Boston",
+				Distance:            200,
+				Quote:               signedQuote(150),
+				SourceLocation:      &pb.LatLng{Lat: 140, Lng: -74.0060},
+				DestinationLocation: boston,
 			},
+			code: codes.InvalidArgument,
 		},
 		{
-			name: "Zero Cost",
+			name: "Distance Far From Route",
+			req: &pb.CreateRideRequest{
+				Source:              "New York",
+				Destination:         "Boston",
+				Distance:            200,
+				Quote:               signedQuote(150),
+				SourceLocation:      newYork,
+				DestinationLocation: lahore,
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Unrecognised Places Without Locations",
 			req: &pb.CreateRideRequest{
 				Source:      "New York",
 				Destination: "Boston",
 				Distance:    200,
-				Cost:        0,
+				Quote:       signedQuote(150),
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Source Location Far From Place",
+			req: &pb.CreateRideRequest{
+				Source:              "KHI",
+				Destination:         "lahore ",
+				Distance:            1200,
+				Quote:               khiToLahore(1200),
+				SourceLocation:      lahore,
+				DestinationLocation: lahore,
 			},
+			code: codes.InvalidArgument,
 		},
 	}
 
@@ -95,13 +386,13 @@ func TestCreateRide_InvalidRequest(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Setup
 			mockRepo := new(mocks.RideRepository)
-			rideServer := NewRideServer(mockRepo)
+			rideServer := newTestRideServer(mockRepo)
 
 			// Action
 			resp, err := rideServer.CreateRide(context.Background(), tc.req)
 
 			// Assertions
-			assert.Error(t, err)
+			assert.Equal(t, tc.code, status.Code(err))
 			assert.Nil(t, resp)
 			// Repository should not be called when validation fails
 			mockRepo.AssertNotCalled(t, "Create")
@@ -109,21 +400,134 @@ func TestCreateRide_InvalidRequest(t *testing.T) {
 	}
 }
 
+func TestCreateRide_WithLocations(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.RideRepository)
+	rideServer := newTestRideServer(mockRepo)
+
+	ctx := context.Background()
+	req := &pb.CreateRideRequest{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Quote:               signedQuote(150),
+		SourceLocation:      newYork,
+		DestinationLocation: boston,
+	}
+
+	// Expectations: a distance within tolerance of the route is accepted and
+	// the coordinates are stored
+	mockRepo.On("Create", ctx, &repository.Ride{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Cost:                pkr(150),
+		VehicleClass:        "ECONOMY",
+		TariffVersion:       1,
+		SourceLocation:      &geo.Point{Lat: 40.7128, Lng: -74.0060},
+		DestinationLocation: &geo.Point{Lat: 42.3601, Lng: -71.0589},
+	}).Return(int32(1), nil)
+
+	// Action
+	resp, err := rideServer.CreateRide(ctx, req)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), resp.RideId)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateRide_KnownPlaces(t *testing.T) {
+	t.Run("Stores Place IDs And Coordinates", func(t *testing.T) {
+		mockRepo := new(mocks.RideRepository)
+		rideServer := newTestRideServer(mockRepo)
+		ctx := context.Background()
+
+		mockRepo.On("Create", ctx, &repository.Ride{
+			Source:              "KHI",
+			Destination:         "lahore ",
+			Distance:            1200,
+			Cost:                pkr(5300),
+			VehicleClass:        "ECONOMY",
+			TariffVersion:       1,
+			SourceLocation:      &geo.Point{Lat: 24.8607, Lng: 67.0011},
+			DestinationLocation: &geo.Point{Lat: 31.5204, Lng: 74.3587},
+			SourcePlaceID:       "pk-khi",
+			DestinationPlaceID:  "pk-lhe",
+		}).Return(int32(1), nil)
+
+		resp, err := rideServer.CreateRide(ctx, &pb.CreateRideRequest{
+			Source:      "KHI",
+			Destination: "lahore ",
+			Distance:    1200,
+			Quote:       khiToLahore(1200),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), resp.RideId)
+		assert.Equal(t, 24.8607, resp.SourceLocation.Lat)
+		assert.Equal(t, "pk-khi", resp.SourcePlaceId)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Keeps Nearby Coordinates", func(t *testing.T) {
+		mockRepo := new(mocks.RideRepository)
+		rideServer := newTestRideServer(mockRepo)
+		ctx := context.Background()
+
+		// The pickup is stored, but the distance is still routed from the
+		// place itself
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(ride *repository.Ride) bool {
+			return *ride.SourceLocation == geo.Point{Lat: 24.9, Lng: 67.1} && ride.SourcePlaceID == "pk-khi"
+		})).Return(int32(1), nil)
+
+		_, err := rideServer.CreateRide(ctx, &pb.CreateRideRequest{
+			Source:              "KHI",
+			Destination:         "lahore ",
+			Distance:            1200,
+			Quote:               khiToLahore(1200),
+			SourceLocation:      &pb.LatLng{Lat: 24.9, Lng: 67.1},
+			DestinationLocation: lahore,
+		})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Distance Checked Against Place Coordinates", func(t *testing.T) {
+		mockRepo := new(mocks.RideRepository)
+		rideServer := newTestRideServer(mockRepo)
+
+		resp, err := rideServer.CreateRide(context.Background(), &pb.CreateRideRequest{
+			Source:      "KHI",
+			Destination: "lahore ",
+			Distance:    50,
+			Quote:       khiToLahore(50),
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Nil(t, resp)
+		mockRepo.AssertNotCalled(t, "Create")
+	})
+}
+
 func TestCreateRide_RepositoryError(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.RideRepository)
-	rideServer := NewRideServer(mockRepo)
+	rideServer := newTestRideServer(mockRepo)
 
 	ctx := context.Background()
 	req := &pb.CreateRideRequest{
-		Source:      "New York",
-		Destination: "Boston",
-		Distance:    200,
-		Cost:        150,
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Quote:               signedQuote(150),
+		SourceLocation:      newYork,
+		DestinationLocation: boston,
 	}
 
 	// Expectations
-	mockRepo.On("Create", ctx, "New York", "Boston", int32(200), int32(150)).Return(int32(0), errors.New("database error"))
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*repository.Ride")).Return(int32(0), errors.New("database error"))
 
 	// Action
 	resp, err := rideServer.CreateRide(ctx, req)
@@ -134,10 +538,192 @@ func TestCreateRide_RepositoryError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateRide_SurgeDemand(t *testing.T) {
+	// Setup: a single request raises the surge tier
+	surge := pricing.NewSurge(pricing.SurgeConfig{
+		Window:   time.Minute,
+		Validity: time.Minute,
+		Tiers:    []pricing.SurgeTier{{MinRequests: 1, MultiplierBP: 15000}},
+		CapBP:    20000,
+	})
+	mockRepo := new(mocks.RideRepository)
+	rideServer := NewRideServer(mockRepo, pricing.NewEngine(new(mocks.TariffRepository), testSigner, time.UTC, 5*time.Minute, surge),
+		geo.HaversineProvider{}, testPlaces)
+
+	ctx := context.Background()
+	req := &pb.CreateRideRequest{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Quote:               signedQuote(150),
+		SourceLocation:      newYork,
+		DestinationLocation: boston,
+	}
+	multiplier := func() int32 {
+		bp, _ := surge.Multiplier(pricing.DefaultArea, time.Now())
+		return bp
+	}
+
+	// Rejected and failed creates do not count towards demand
+	_, err := rideServer.CreateRide(ctx, &pb.CreateRideRequest{Source: "New York", Destination: "Boston", Distance: 200})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*repository.Ride")).Return(int32(0), errors.New("database error")).Once()
+	_, err = rideServer.CreateRide(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, int32(10000), multiplier())
+
+	// A ride that is made does
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*repository.Ride")).Return(int32(1), nil).Once()
+	_, err = rideServer.CreateRide(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, int32(15000), multiplier())
+}
+
+func TestQuoteFare_Success(t *testing.T) {
+	// Setup
+	mockTariffs := new(mocks.TariffRepository)
+	rideServer := NewRideServer(new(mocks.RideRepository), newTestPricingEngine(mockTariffs), geo.HaversineProvider{}, testPlaces)
+
+	ctx := context.Background()
+	req := &pb.QuoteFareRequest{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		SourceLocation:      newYork,
+		DestinationLocation: boston,
+	}
+
+	// Expectations: an empty vehicle class defaults to ECONOMY
+	mockTariffs.On("GetActive", ctx, "ECONOMY", mock.AnythingOfType("time.Time")).Return(&repository.Tariff{
+		VehicleClass: "ECONOMY",
+		Version:      3,
+		Currency:     money.PKR,
+		BaseFare:     500,
+		PerKmRate:    4,
+		MinimumFare:  300,
+	}, nil)
+
+	// Action
+	resp, err := rideServer.QuoteFare(ctx, req)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 130000}, resp.Price)
+	assert.Equal(t, int32(1300), resp.Fare)
+	assert.Equal(t, int32(3), resp.TariffVersion)
+	assert.Equal(t, "ECONOMY", resp.VehicleClass)
+	assert.NotEmpty(t, resp.Signature)
+	assert.True(t, resp.ExpiresAt.AsTime().After(time.Now()))
+	assert.NoError(t, testSigner.Verify(quoteFromProto(resp), time.Now()))
+	mockTariffs.AssertExpectations(t)
+}
+
+func TestQuoteFare_UnsupportedVehicleClass(t *testing.T) {
+	// Setup
+	mockTariffs := new(mocks.TariffRepository)
+	rideServer := NewRideServer(new(mocks.RideRepository), newTestPricingEngine(mockTariffs), geo.HaversineProvider{}, testPlaces)
+
+	ctx := context.Background()
+	req := &pb.QuoteFareRequest{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		VehicleClass:        "SPACESHIP",
+		SourceLocation:      newYork,
+		DestinationLocation: boston,
+	}
+
+	// Expectations
+	mockTariffs.On("GetActive", ctx, "SPACESHIP", mock.AnythingOfType("time.Time")).Return(nil, errors.New("tariff not found"))
+
+	// Action
+	resp, err := rideServer.QuoteFare(ctx, req)
+
+	// Assertions
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockTariffs.AssertExpectations(t)
+}
+
+func TestQuoteFare_InvalidRequest(t *testing.T) {
+	// Setup
+	mockTariffs := new(mocks.TariffRepository)
+	rideServer := NewRideServer(new(mocks.RideRepository), newTestPricingEngine(mockTariffs), geo.HaversineProvider{}, testPlaces)
+
+	testCases := []struct {
+		name string
+		req  *pb.QuoteFareRequest
+	}{
+		{
+			name: "Missing Distance",
+			req:  &pb.QuoteFareRequest{Source: "New York", Destination: "Boston", SourceLocation: newYork, DestinationLocation: boston},
+		},
+		{
+			name: "Unrecognised Places Without Locations",
+			req:  &pb.QuoteFareRequest{Source: "New York", Destination: "Boston", Distance: 200},
+		},
+		{
+			name: "Distance Far From Route",
+			req:  &pb.QuoteFareRequest{Source: "New York", Destination: "Boston", Distance: 20, SourceLocation: newYork, DestinationLocation: boston},
+		},
+		{
+			name: "Distance Far From Place Coordinates",
+			req:  &pb.QuoteFareRequest{Source: "KHI", Destination: "lahore ", Distance: 50},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Action
+			resp, err := rideServer.QuoteFare(context.Background(), tc.req)
+
+			// Assertions: nothing is signed for an unchecked distance
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			mockTariffs.AssertNotCalled(t, "GetActive")
+		})
+	}
+}
+
+func TestSearchPlaces_Success(t *testing.T) {
+	rideServer := newTestRideServer(new(mocks.RideRepository))
+
+	resp, err := rideServer.SearchPlaces(context.Background(), &pb.SearchPlacesRequest{Query: "karchi"})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Places, 1)
+	assert.Equal(t, "pk-khi", resp.Places[0].PlaceId)
+	assert.Equal(t, "Karachi", resp.Places[0].Name)
+	assert.Equal(t, 24.8607, resp.Places[0].Location.Lat)
+	assert.Contains(t, resp.Places[0].Aliases, "KHI")
+}
+
+func TestSearchPlaces_Limit(t *testing.T) {
+	rideServer := newTestRideServer(new(mocks.RideRepository))
+
+	resp, err := rideServer.SearchPlaces(context.Background(), &pb.SearchPlacesRequest{Query: "a", Limit: 3})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Places, 3)
+}
+
+func TestSearchPlaces_InvalidRequest(t *testing.T) {
+	rideServer := newTestRideServer(new(mocks.RideRepository))
+
+	for _, req := range []*pb.SearchPlacesRequest{
+		{Query: "  "},
+		{Query: "lahore", Limit: -1},
+	} {
+		resp, err := rideServer.SearchPlaces(context.Background(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Nil(t, resp)
+	}
+}
+
 func TestGetRide_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.RideRepository)
-	rideServer := NewRideServer(mockRepo)
+	rideServer := newTestRideServer(mockRepo)
 
 	ctx := context.Background()
 	req := &pb.GetRideRequest{RideId: 1}
@@ -148,7 +734,21 @@ func TestGetRide_Success(t *testing.T) {
 		Source:      "New York",
 		Destination: "Boston",
 		Distance:    200,
-		Cost:        150,
+		Cost:        money.Money{Currency: money.PKR, Minor: 15050},
+
+		SourceLocation: &geo.Point{Lat: 40.7128, Lng: -74.0060},
+		SourcePlaceID:  "us-nyc",
+		CreatedAt:      time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2023, 1, 2, 9, 30, 0, 0, time.UTC),
+		FareBreakdown: &repository.FareBreakdown{
+			BaseFare:              pkr(50),
+			DistanceFare:          money.Money{Currency: money.PKR, Minor: 10050},
+			MinimumFareAdjustment: pkr(0),
+			TimeOfDayMultiplierBP: 10000,
+			TimeOfDayAdjustment:   pkr(0),
+			SurgeMultiplierBP:     10000,
+			SurgeAdjustment:       pkr(0),
+		},
 	}
 
 	// Expectations
@@ -164,14 +764,23 @@ func TestGetRide_Success(t *testing.T) {
 	assert.Equal(t, "New York", resp.Source)
 	assert.Equal(t, "Boston", resp.Destination)
 	assert.Equal(t, int32(200), resp.Distance)
-	assert.Equal(t, int32(150), resp.Cost)
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 15050}, resp.Price)
+	// The deprecated cost is rounded half up to whole rupees
+	assert.Equal(t, int32(151), resp.Cost)
+	assert.Equal(t, 40.7128, resp.SourceLocation.Lat)
+	assert.Nil(t, resp.DestinationLocation)
+	assert.Equal(t, "us-nyc", resp.SourcePlaceId)
+	assert.Equal(t, mockRide.CreatedAt, resp.CreatedAt.AsTime())
+	assert.Equal(t, mockRide.UpdatedAt, resp.UpdatedAt.AsTime())
+	assert.Equal(t, &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 10050}, resp.FareBreakdown.DistanceFare)
+	assert.Equal(t, int32(10000), resp.FareBreakdown.SurgeMultiplierBp)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestGetRide_InvalidId(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.RideRepository)
-	rideServer := NewRideServer(mockRepo)
+	rideServer := newTestRideServer(mockRepo)
 
 	ctx := context.Background()
 	req := &pb.GetRideRequest{RideId: 0}
@@ -189,7 +798,7 @@ func TestGetRide_InvalidId(t *testing.T) {
 func TestGetRide_NotFound(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.RideRepository)
-	rideServer := NewRideServer(mockRepo)
+	rideServer := newTestRideServer(mockRepo)
 
 	ctx := context.Background()
 	req := &pb.GetRideRequest{RideId: 999}
@@ -209,22 +818,33 @@ func TestGetRide_NotFound(t *testing.T) {
 func TestUpdateRide_Success(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.RideRepository)
-	rideServer := NewRideServer(mockRepo)
+	rideServer := newTestRideServer(mockRepo)
 
 	ctx := context.Background()
 	req := &pb.UpdateRideRequest{
 		RideId: 1,
 		Ride: &pb.Ride{
-			Source:      "New York",
-			Destination: "Boston",
-			Distance:    200,
-			Cost:        150,
+			Source:              "New York",
+			Destination:         "Boston",
+			Distance:            200,
+			SourceLocation:      newYork,
+			DestinationLocation: boston,
 		},
+		Quote: signedQuote(150),
 	}
 	expectedMsg := "Ride 1 updated successfully"
 
-	// Expectations
-	mockRepo.On("Update", ctx, int32(1), "New York", "Boston", int32(200), int32(150)).Return(expectedMsg, nil)
+	// Expectations: the cost comes from the quote
+	mockRepo.On("Update", ctx, int32(1), &repository.Ride{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Cost:                pkr(150),
+		VehicleClass:        "ECONOMY",
+		TariffVersion:       1,
+		SourceLocation:      &geo.Point{Lat: 40.7128, Lng: -74.0060},
+		DestinationLocation: &geo.Point{Lat: 42.3601, Lng: -71.0589},
+	}).Return(expectedMsg, nil)
 
 	// Action
 	resp, err := rideServer.UpdateRide(ctx, req)
@@ -236,30 +856,156 @@ func TestUpdateRide_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUpdateRide_WithLocations(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.RideRepository)
+	rideServer := newTestRideServer(mockRepo)
+
+	ctx := context.Background()
+
+	// Expectations: the new coordinates replace the ride's old ones
+	mockRepo.On("Update", ctx, int32(1), &repository.Ride{
+		Source:              "New York",
+		Destination:         "Boston",
+		Distance:            200,
+		Cost:                pkr(150),
+		VehicleClass:        "ECONOMY",
+		TariffVersion:       1,
+		SourceLocation:      &geo.Point{Lat: 40.7128, Lng: -74.0060},
+		DestinationLocation: &geo.Point{Lat: 42.3601, Lng: -71.0589},
+	}).Return("Ride 1 updated successfully", nil)
+
+	// Action
+	_, err := rideServer.UpdateRide(ctx, &pb.UpdateRideRequest{
+		RideId: 1,
+		Ride: &pb.Ride{
+			Source:              "New York",
+			Destination:         "Boston",
+			Distance:            200,
+			SourceLocation:      newYork,
+			DestinationLocation: boston,
+		},
+		Quote: signedQuote(150),
+	})
+
+	// Assertions
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateRide_IgnoresCallerPrice(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.RideRepository)
+	rideServer := newTestRideServer(mockRepo)
+
+	ctx := context.Background()
+	req := &pb.UpdateRideRequest{
+		RideId: 1,
+		Ride: &pb.Ride{
+			Source:              "New York",
+			Destination:         "Boston",
+			Distance:            200,
+			Cost:                1,
+			Price:               &moneypb.Money{CurrencyCode: "PKR", MinorUnits: 100},
+			SourceLocation:      newYork,
+			DestinationLocation: boston,
+		},
+		Quote: signedQuote(150),
+	}
+
+	// Expectations: a price on the ride cannot undercut the quoted fare
+	mockRepo.On("Update", ctx, int32(1), mock.MatchedBy(func(ride *repository.Ride) bool {
+		return ride.Cost == pkr(150)
+	})).Return("Ride 1 updated successfully", nil)
+
+	// Action
+	_, err := rideServer.UpdateRide(ctx, req)
+
+	// Assertions
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateRide_KnownPlaces(t *testing.T) {
+	// Setup
+	mockRepo := new(mocks.RideRepository)
+	rideServer := newTestRideServer(mockRepo)
+
+	ctx := context.Background()
+
+	// Expectations: the new places are resolved as on CreateRide, replacing
+	// the ride's old place IDs and coordinates
+	mockRepo.On("Update", ctx, int32(1), &repository.Ride{
+		Source:              "KHI",
+		Destination:         "lahore ",
+		Distance:            1200,
+		Cost:                pkr(5300),
+		VehicleClass:        "ECONOMY",
+		TariffVersion:       1,
+		SourceLocation:      &geo.Point{Lat: 24.8607, Lng: 67.0011},
+		DestinationLocation: &geo.Point{Lat: 31.5204, Lng: 74.3587},
+		SourcePlaceID:       "pk-khi",
+		DestinationPlaceID:  "pk-lhe",
+	}).Return("Ride 1 updated successfully", nil)
+
+	// Action
+	_, err := rideServer.UpdateRide(ctx, &pb.UpdateRideRequest{
+		RideId: 1,
+		Ride: &pb.Ride{
+			Source:      "KHI",
+			Destination: "lahore ",
+			Distance:    1200,
+		},
+		Quote: khiToLahore(1200),
+	})
+
+	// Assertions
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestUpdateRide_InvalidRequest(t *testing.T) {
+	tampered := signedQuote(150)
+	tampered.Price.MinorUnits = 100
+
+	expired := &pricing.Quote{
+		Source:        "New York",
+		Destination:   "Boston",
+		Distance:      200,
+		VehicleClass:  "ECONOMY",
+		Fare:          pkr(150),
+		TariffVersion: 1,
+		ExpiresAt:     time.Now().Add(-time.Minute),
+	}
+	testSigner.Sign(expired)
+
+	newYorkToBoston := func() *pb.Ride {
+		return &pb.Ride{Source: "New York", Destination: "Boston", Distance: 200}
+	}
+
 	// Create a set of test cases for different validation failures
 	testCases := []struct {
 		name string
 		req  *pb.UpdateRideRequest
+		code codes.Code
 	}{
 		{
 			name: "Invalid Ride ID",
 			req: &pb.UpdateRideRequest{
 				RideId: 0,
-				Ride: &pb.Ride{
-					Source:      "New York",
-					Destination: "Boston",
-					Distance:    200,
-					Cost:        150,
-				},
+				Ride:   newYorkToBoston(),
+				Quote:  signedQuote(150),
 			},
+			code: codes.InvalidArgument,
 		},
 		{
 			name: "Missing Ride Details",
 			req: &pb.UpdateRideRequest{
 				RideId: 1,
 				Ride:   nil,
+				Quote:  signedQuote(150),
 			},
+			code: codes.InvalidArgument,
 		},
 		{
 			name: "Empty Source",
@@ -269,45 +1015,138 @@ func TestUpdateRide_InvalidRequest(t *testing.T) {
 					Source:      "",
 					Destination: "Boston",
 					Distance:    200,
-					Cost:        150,
 				},
+				Quote: signedQuote(150),
 			},
+			code: codes.InvalidArgument,
 		},
 		{
 			name: "Empty Destination",
 			req: &pb.UpdateRideRequest{
 				RideId: 1,
 				Ride: &pb.Ride{
-					Source:      "New York",
-					Destination: "",
-					Distance:    200,
-					Cost:        150,
+					Source:              "New York",
+					Destination:         "",
+					Distance:            200,
+					SourceLocation:      newYork,
+					DestinationLocation: boston,
 				},
+				Quote: signedQuote(150),
 			},
+			code: codes.InvalidArgument,
 		},
 		{
 			name: "Zero Distance",
 			req: &pb.UpdateRideRequest{
 				RideId: 1,
 				Ride: &pb.Ride{
-					Source:      "New York",
-					Destination: "Boston",
-					Distance:    0,
-					Cost:        150,
+					Source:              "New York",
+					Destination:         "Boston",
+					Distance:            0,
+					SourceLocation:      newYork,
+					DestinationLocation: boston,
 				},
+				Quote: signedQuote(150),
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Missing Quote",
+			req: &pb.UpdateRideRequest{
+				RideId: 1,
+				Ride:   newYorkToBoston(),
 			},
+			code: codes.InvalidArgument,
 		},
 		{
-			name: "Zero Cost",
+			name: "Tampered Quote",
+			req: &pb.UpdateRideRequest{
+				RideId: 1,
+				Ride:   newYorkToBoston(),
+				Quote:  tampered,
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Quote For Different Ride",
 			req: &pb.UpdateRideRequest{
 				RideId: 1,
 				Ride: &pb.Ride{
-					Source:      "New York",
-					Destination: "Boston",
-					Distance:    200,
-					Cost:        0,
+					Source:              "New York",
+					Destination:         "Boston",
+					Distance:            20,
+					SourceLocation:      newYork,
+					DestinationLocation: boston,
+				},
+				Quote: signedQuote(150),
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Expired Quote",
+			req: &pb.UpdateRideRequest{
+				RideId: 1,
+				Ride:   newYorkToBoston(),
+				Quote:  quoteToProto(expired),
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "Source Location Without Destination",
+			req: &pb.UpdateRideRequest{
+				RideId: 1,
+				Ride: &pb.Ride{
+					Source:         "New York",
+					Destination:    "Boston",
+					Distance:       200,
+					SourceLocation: newYork,
 				},
+				Quote: signedQuote(150),
 			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Distance Far From Route",
+			req: &pb.UpdateRideRequest{
+				RideId: 1,
+				Ride: &pb.Ride{
+					Source:              "New York",
+					Destination:         "Boston",
+					Distance:            200,
+					SourceLocation:      newYork,
+					DestinationLocation: lahore,
+				},
+				Quote: signedQuote(150),
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Distance Far From Place Coordinates",
+			req: &pb.UpdateRideRequest{
+				RideId: 1,
+				Ride: &pb.Ride{
+					Source:      "KHI",
+					Destination: "lahore ",
+					Distance:    50,
+				},
+				Quote: khiToLahore(50),
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Invalid Latitude",
+			req: &pb.UpdateRideRequest{
+				RideId: 1,
+				Ride: &pb.Ride{
+					Source:              "New York",
+					Destination:         "Boston",
+					Distance:            200,
+					SourceLocation:      &pb.LatLng{Lat: 140, Lng: -74.0060},
+					DestinationLocation: boston,
+				},
+				Quote: signedQuote(150),
+			},
+			code: codes.InvalidArgument,
 		},
 	}
 
@@ -315,13 +1154,13 @@ func TestUpdateRide_InvalidRequest(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Setup
 			mockRepo := new(mocks.RideRepository)
-			rideServer := NewRideServer(mockRepo)
+			rideServer := newTestRideServer(mockRepo)
 
 			// Action
 			resp, err := rideServer.UpdateRide(context.Background(), tc.req)
 
 			// Assertions
-			assert.Error(t, err)
+			assert.Equal(t, tc.code, status.Code(err))
 			assert.Nil(t, resp)
 			// Repository should not be called when validation fails
 			mockRepo.AssertNotCalled(t, "Update")
@@ -332,21 +1171,23 @@ func TestUpdateRide_InvalidRequest(t *testing.T) {
 func TestUpdateRide_RepositoryError(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.RideRepository)
-	rideServer := NewRideServer(mockRepo)
+	rideServer := newTestRideServer(mockRepo)
 
 	ctx := context.Background()
 	req := &pb.UpdateRideRequest{
 		RideId: 1,
 		Ride: &pb.Ride{
-			Source:      "New York",
-			Destination: "Boston",
-			Distance:    200,
-			Cost:        150,
+			Source:              "New York",
+			Destination:         "Boston",
+			Distance:            200,
+			SourceLocation:      newYork,
+			DestinationLocation: boston,
 		},
+		Quote: signedQuote(150),
 	}
 
 	// Expectations
-	mockRepo.On("Update", ctx, int32(1), "New York", "Boston", int32(200), int32(150)).Return("", errors.New("database error"))
+	mockRepo.On("Update", ctx, int32(1), mock.AnythingOfType("*repository.Ride")).Return("", errors.New("database error"))
 
 	// Action
 	resp, err := rideServer.UpdateRide(ctx, req)
@@ -356,3 +1197,46 @@ func TestUpdateRide_RepositoryError(t *testing.T) {
 	assert.Nil(t, resp)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestQueryAuditLog(t *testing.T) {
+	// Setup
+	mockLog := new(auditmocks.Log)
+	rideServer := NewRideServer(new(mocks.RideRepository), newTestPricingEngine(new(mocks.TariffRepository)), geo.HaversineProvider{}, testPlaces,
+		WithAuditLog(mockLog))
+
+	ctx := audit.WithActor(context.Background(), audit.Admin)
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := since.Add(time.Hour)
+	mockLog.On("Query", ctx, audit.Filter{Actor: audit.Anonymous, Since: since, Limit: 10}).Return([]*audit.Entry{{
+		ID:        3,
+		Method:    "UpdateRide",
+		Actor:     audit.Anonymous,
+		Before:    []byte(`{"ride_id":5,"distance":10}`),
+		After:     []byte(`{"ride_id":5,"distance":12}`),
+		CreatedAt: updatedAt,
+		Hash:      "cc",
+	}}, nil)
+
+	// Execute
+	res, err := rideServer.QueryAuditLog(ctx, &auditpb.QueryAuditLogRequest{Actor: audit.Anonymous, Since: timestamppb.New(since), Limit: 10})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []*auditpb.AuditEntry{{
+		EntryId:   3,
+		Method:    "UpdateRide",
+		Actor:     audit.Anonymous,
+		Before:    `{"ride_id":5,"distance":10}`,
+		After:     `{"ride_id":5,"distance":12}`,
+		CreatedAt: timestamppb.New(updatedAt),
+		Hash:      "cc",
+	}}, res.Entries)
+	mockLog.AssertNotCalled(t, "Verify", mock.Anything)
+
+	// Only admins may read the log
+	_, err = rideServer.QueryAuditLog(context.Background(), &auditpb.QueryAuditLogRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = newTestRideServer(new(mocks.RideRepository)).QueryAuditLog(ctx, &auditpb.QueryAuditLogRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -12,6 +12,10 @@ fi
 cd $(dirname $0)/..
 PROJECT_ROOT=$(pwd)
 
+echo "Generating mocks for common audit log..."
+cd $PROJECT_ROOT/common
+mockery --name=Log --dir=audit --output=audit/mocks --outpkg=mocks
+
 echo "Generating mocks for user-service repositories..."
 cd $PROJECT_ROOT/user-service
 mockery --name=UserRepository --dir=repository --output=repository/mocks --outpkg=mocks
@@ -23,6 +27,7 @@ mockery --name=UserServiceClient --dir=pb/proto/user --output=pb/proto/user/mock
 echo "Generating mocks for ride-service repositories..."
 cd $PROJECT_ROOT/ride-service
 mockery --name=RideRepository --dir=repository --output=repository/mocks --outpkg=mocks
+mockery --name=TariffRepository --dir=repository --output=repository/mocks --outpkg=mocks
 
 echo "Generating mocks for ride-service client..."
 cd $PROJECT_ROOT/ride-service
@@ -32,4 +37,19 @@ echo "Generating mocks for booking-service repositories..."
 cd $PROJECT_ROOT/booking-service
 mockery --name=BookingRepository --dir=repository --output=repository/mocks --outpkg=mocks
 
+echo "Generating mocks for driver-service repositories..."
+cd $PROJECT_ROOT/driver-service
+mockery --name=DriverRepository --dir=repository --output=repository/mocks --outpkg=mocks
+
+echo "Generating mocks for driver-service client..."
+cd $PROJECT_ROOT/driver-service
+mockery --name=DriverServiceClient --dir=pb/proto/driver --output=pb/proto/driver/mocks --outpkg=mocks
+
+echo "Generating mocks for notification-service repositories..."
+cd $PROJECT_ROOT/notification-service
+mockery --name=PreferenceRepository --dir=repository --output=repository/mocks --outpkg=mocks
+mockery --name=DeliveryRepository --dir=repository --output=repository/mocks --outpkg=mocks
+mockery --name=WebhookRepository --dir=repository --output=repository/mocks --outpkg=mocks
+mockery --name=WebhookDeliveryRepository --dir=repository --output=repository/mocks --outpkg=mocks
+
 echo "All mocks generated successfully!"
@@ -0,0 +1,38 @@
+#!/bin/bash
+
+# Exit on error
+set -e
+
+# Ensure protoc plugins are installed
+if ! command -v protoc-gen-go &> /dev/null; then
+    echo "protoc-gen-go is not installed. Installing..."
+    go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.36.6
+fi
+if ! command -v protoc-gen-go-grpc &> /dev/null; then
+    echo "protoc-gen-go-grpc is not installed. Installing..."
+    go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.5.1
+fi
+
+cd $(dirname $0)/..
+
+generate() {
+    local proto=$1
+    local out=$2
+    echo "Generating $proto into $out..."
+    protoc -I . \
+        --go_out=$out --go_opt=paths=source_relative \
+        --go-grpc_out=$out --go-grpc_opt=paths=source_relative \
+        $proto
+}
+
+generate proto/money/money.proto common/pb
+generate proto/audit/audit.proto common/pb
+generate proto/logging/logging.proto common/pb
+generate proto/user/user.proto user-service/pb
+generate proto/ride/ride.proto ride-service/pb
+generate proto/booking/booking.proto booking-service/pb
+generate proto/driver/driver.proto driver-service/pb
+generate proto/payment/payment.proto payment-service/pb
+generate proto/notification/notification.proto notification-service/pb
+
+echo "All protos generated successfully!"
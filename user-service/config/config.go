@@ -5,10 +5,22 @@ import (
 	"os"
 	"log"
 	"github.com/joho/godotenv"
+
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
 )
 
 type Config struct {
-	DBUrl string
+	DBUrl     string
+	BrokerURL string
+
+	// DBPool holds the DB_* connection pool settings.
+	DBPool database.PoolConfig
+	// Log holds the LOG_* logging settings.
+	Log logger.Settings
+	// AdminToken is the bearer token of the admin server's debug endpoints
+	// and of QueryAuditLog, which are disabled while it is empty.
+	AdminToken string
 }
 
 func Load() Config {
@@ -26,5 +38,22 @@ func Load() Config {
 	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		dbUser, dbPass, dbHost, dbPort, dbName)
 
-	return Config{DBUrl: dbUrl}
+	dbPool, err := database.PoolConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logSettings, err := logger.SettingsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return Config{
+		DBUrl:     dbUrl,
+		BrokerURL: os.Getenv("BROKER_URL"),
+
+		DBPool:     dbPool,
+		Log:        logSettings,
+		AdminToken: os.Getenv("ADMIN_TOKEN"),
+	}
 }
@@ -0,0 +1,10 @@
+-- The hash of the latest audit_log entry. Appending an entry locks this one
+-- row rather than the whole log, so the chain stays in order without holding
+-- up reads of the log.
+CREATE TABLE audit_log_head (
+  id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+  hash TEXT NOT NULL
+);
+
+INSERT INTO audit_log_head (hash)
+SELECT COALESCE((SELECT hash FROM audit_log ORDER BY entry_id DESC LIMIT 1), '');
@@ -1,14 +1,12 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
 	"log"
 	"net"
-	"net/http"
 
 	_ "github.com/lib/pq"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
@@ -17,41 +15,76 @@ import (
 	"user-service/repository"
 	"user-service/server"
 
+	"github.com/hasnain-zafar/go-microservices/common/admin"
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	"github.com/hasnain-zafar/go-microservices/common/database"
+	"github.com/hasnain-zafar/go-microservices/common/logger"
 	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	"github.com/hasnain-zafar/go-microservices/common/outbox"
 )
 
 func main() {
 	// Initialize Prometheus metrics
 	metrics.Init()
-
-	// Start metrics HTTP server in a goroutine
-	go startMetricsServer("user-service", 2112)
+	registry := metrics.NewRegistry("user-service")
 
 	cfg := config.Load()
+	logger.Configure(cfg.Log)
 
-	db, err := sql.Open("postgres", cfg.DBUrl)
+	// Wait for Postgres, which may still be starting
+	db, err := database.Open(context.Background(), "postgres", cfg.DBUrl, cfg.DBPool)
 	if err != nil {
-		log.Fatalf("❌ Could not connect to DB: %v", err)
+		log.Fatalf("❌ DB not reachable: %v", err)
 	}
 	defer db.Close()
+	registry.RegisterDBStats(db, "users_db")
+
+	fmt.Println("✅ Connected to users_db successfully")
+
+	// Serve metrics and, with ADMIN_TOKEN set, the debug endpoints
+	conns := admin.NewConnTracker()
+	adminServer := admin.NewServer("user-service", cfg.AdminToken, registry,
+		admin.WithConfig(cfg),
+		admin.WithDB(db),
+		admin.WithConnections(conns),
+	)
+	go startAdminServer(adminServer, "user-service", 2112)
 
-	err = db.Ping()
+	broker, err := outbox.NewBroker(context.Background(), cfg.BrokerURL)
 	if err != nil {
-		log.Fatalf("❌ DB not reachable: %v", err)
+		log.Fatalf("❌ Failed to connect to message broker: %v", err)
 	}
+	defer broker.Close()
 
-	fmt.Println("✅ Connected to users_db successfully")
+	// Publish domain events written to the outbox by the repository
+	relay := outbox.NewRelay(db, broker, logger.NewLogger("user-service"))
+	go relay.Run(context.Background())
 
 	userRepo := repository.NewPostgresUserRepository(db)
 
-	userServer := server.NewUserServer(userRepo)
+	auditLog := audit.NewPostgresLog(db)
+
+	userServer := server.NewUserServer(userRepo,
+		server.WithAuditLog(auditLog),
+		server.WithMetrics(registry),
+	)
 
 	listener, err := net.Listen("tcp", ":50051")
 	if err != nil {
 		log.Fatalf("❌ Failed to listen on port 50051: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	// Tag every call with a request ID for its log lines, and with the actor
+	// its caller authenticated as for the audit log
+	requestLogger := logger.NewLogger("user-service")
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(conns),
+		grpc.ChainUnaryInterceptor(
+			logger.UnaryServerInterceptor(requestLogger),
+			audit.NewAuthenticator(cfg.AdminToken).UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(logger.StreamServerInterceptor(requestLogger)),
+	)
 	pb.RegisterUserServiceServer(grpcServer, userServer)
 
 	reflection.Register(grpcServer)
@@ -62,11 +95,9 @@ func main() {
 	}
 }
 
-func startMetricsServer(serviceName string, port int) {
-	fmt.Printf("📊 Metrics server for %s starting on :%d\n", serviceName, port)
-	http.Handle("/metrics", promhttp.Handler())
-	err := http.ListenAndServe(fmt.Sprintf(":%d", port), nil)
-	if err != nil {
-		log.Fatalf("❌ Failed to start metrics server: %v", err)
+func startAdminServer(adminServer *admin.Server, serviceName string, port int) {
+	fmt.Printf("📊 Admin server for %s starting on :%d\n", serviceName, port)
+	if err := adminServer.ListenAndServe(port); err != nil {
+		log.Fatalf("❌ Failed to start admin server: %v", err)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	context "context"
 
 	grpc "google.golang.org/grpc"
+	audit "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
 	mock "github.com/stretchr/testify/mock"
 	pb "user-service/pb/proto/user"
 )
@@ -104,3 +105,33 @@ func (_m *UserServiceClient) GetUser(ctx context.Context, in *pb.GetUserRequest,
 
 	return r0, r1
 }
+
+// QueryAuditLog provides a mock function with given fields: ctx, in, opts
+func (_m *UserServiceClient) QueryAuditLog(ctx context.Context, in *audit.QueryAuditLogRequest, opts ...grpc.CallOption) (*audit.QueryAuditLogResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *audit.QueryAuditLogResponse
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.QueryAuditLogRequest, ...grpc.CallOption) *audit.QueryAuditLogResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*audit.QueryAuditLogResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *audit.QueryAuditLogRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
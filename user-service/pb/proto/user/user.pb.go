@@ -7,6 +7,8 @@
 package pb
 
 import (
+	audit "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+	_ "github.com/hasnain-zafar/go-microservices/common/pb/proto/logging"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -289,25 +291,26 @@ var File_proto_user_user_proto protoreflect.FileDescriptor
 
 const file_proto_user_user_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/user/user.proto\x12\x04user\")\n" +
+	"\x15proto/user/user.proto\x12\x04user\x1a\x17proto/audit/audit.proto\x1a\x1bproto/logging/logging.proto\")\n" +
 	"\x0eGetUserRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x05R\x06userId\"%\n" +
-	"\x0fGetUserResponse\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"'\n" +
-	"\x11CreateUserRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"-\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\"+\n" +
+	"\x0fGetUserResponse\x12\x18\n" +
+	"\x04name\x18\x01 \x01(\tB\x04\x88\xb5\x18\x01R\x04name\"-\n" +
+	"\x11CreateUserRequest\x12\x18\n" +
+	"\x04name\x18\x01 \x01(\tB\x04\x88\xb5\x18\x01R\x04name\"-\n" +
 	"\x12CreateUserResponse\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x05R\x06userId\",\n" +
 	"\x11DeleteUserRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\x05R\x06userId\".\n" +
 	"\x12DeleteUserResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2\xc7\x01\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage2\x93\x02\n" +
 	"\vUserService\x126\n" +
 	"\aGetUser\x12\x14.user.GetUserRequest\x1a\x15.user.GetUserResponse\x12?\n" +
 	"\n" +
 	"CreateUser\x12\x17.user.CreateUserRequest\x1a\x18.user.CreateUserResponse\x12?\n" +
 	"\n" +
-	"DeleteUser\x12\x17.user.DeleteUserRequest\x1a\x18.user.DeleteUserResponseB\x11Z\x0fuser-service/pbb\x06proto3"
+	"DeleteUser\x12\x17.user.DeleteUserRequest\x1a\x18.user.DeleteUserResponse\x12J\n" +
+	"\rQueryAuditLog\x12\x1b.audit.QueryAuditLogRequest\x1a\x1c.audit.QueryAuditLogResponseB\x11Z\x0fuser-service/pbb\x06proto3"
 
 var (
 	file_proto_user_user_proto_rawDescOnce sync.Once
@@ -323,22 +326,26 @@ func file_proto_user_user_proto_rawDescGZIP() []byte {
 
 var file_proto_user_user_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_proto_user_user_proto_goTypes = []any{
-	(*GetUserRequest)(nil),     // 0: user.GetUserRequest
-	(*GetUserResponse)(nil),    // 1: user.GetUserResponse
-	(*CreateUserRequest)(nil),  // 2: user.CreateUserRequest
-	(*CreateUserResponse)(nil), // 3: user.CreateUserResponse
-	(*DeleteUserRequest)(nil),  // 4: user.DeleteUserRequest
-	(*DeleteUserResponse)(nil), // 5: user.DeleteUserResponse
+	(*GetUserRequest)(nil),              // 0: user.GetUserRequest
+	(*GetUserResponse)(nil),             // 1: user.GetUserResponse
+	(*CreateUserRequest)(nil),           // 2: user.CreateUserRequest
+	(*CreateUserResponse)(nil),          // 3: user.CreateUserResponse
+	(*DeleteUserRequest)(nil),           // 4: user.DeleteUserRequest
+	(*DeleteUserResponse)(nil),          // 5: user.DeleteUserResponse
+	(*audit.QueryAuditLogRequest)(nil),  // 6: audit.QueryAuditLogRequest
+	(*audit.QueryAuditLogResponse)(nil), // 7: audit.QueryAuditLogResponse
 }
 var file_proto_user_user_proto_depIdxs = []int32{
 	0, // 0: user.UserService.GetUser:input_type -> user.GetUserRequest
 	2, // 1: user.UserService.CreateUser:input_type -> user.CreateUserRequest
 	4, // 2: user.UserService.DeleteUser:input_type -> user.DeleteUserRequest
-	1, // 3: user.UserService.GetUser:output_type -> user.GetUserResponse
-	3, // 4: user.UserService.CreateUser:output_type -> user.CreateUserResponse
-	5, // 5: user.UserService.DeleteUser:output_type -> user.DeleteUserResponse
-	3, // [3:6] is the sub-list for method output_type
-	0, // [0:3] is the sub-list for method input_type
+	6, // 3: user.UserService.QueryAuditLog:input_type -> audit.QueryAuditLogRequest
+	1, // 4: user.UserService.GetUser:output_type -> user.GetUserResponse
+	3, // 5: user.UserService.CreateUser:output_type -> user.CreateUserResponse
+	5, // 6: user.UserService.DeleteUser:output_type -> user.DeleteUserResponse
+	7, // 7: user.UserService.QueryAuditLog:output_type -> audit.QueryAuditLogResponse
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
 	0, // [0:0] is the sub-list for extension type_name
 	0, // [0:0] is the sub-list for extension extendee
 	0, // [0:0] is the sub-list for field type_name
@@ -5,11 +5,34 @@ import (
     "database/sql"
     "fmt"
     "log"
+    "strconv"
+
+    "github.com/hasnain-zafar/go-microservices/common/audit"
+    "github.com/hasnain-zafar/go-microservices/common/outbox"
+)
+
+// Domain events written to the outbox alongside user state changes.
+const (
+    AggregateUser    = "user"
+    EventUserDeleted = "UserDeleted"
+)
+
+// Audited methods, recorded in the audit log alongside user state changes.
+const (
+    MethodCreateUser = "CreateUser"
+    MethodDeleteUser = "DeleteUser"
 )
 
+// User is also the snapshot recorded in the audit log.
 type User struct {
-    ID   int32
-    Name string
+    ID   int32  `json:"user_id"`
+    Name string `json:"name"`
+}
+
+// UserEvent is the payload of user domain events.
+type UserEvent struct {
+    UserID int32  `json:"user_id"`
+    Name   string `json:"name"`
 }
 
 type UserRepository interface {
@@ -27,13 +50,32 @@ func NewPostgresUserRepository(db *sql.DB) UserRepository {
 }
 
 func (r *PostgresUserRepository) Create(ctx context.Context, name string) (int32, error) {
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        log.Printf("Create user failed: %v", err)
+        return 0, err
+    }
+    defer tx.Rollback()
+
     query := `INSERT INTO users (name) VALUES ($1) RETURNING user_id`
     var userID int32
-    err := r.db.QueryRowContext(ctx, query, name).Scan(&userID)
+    err = tx.QueryRowContext(ctx, query, name).Scan(&userID)
     if err != nil {
         log.Printf("Create user failed: %v", err)
         return 0, err
     }
+
+    // Users sign themselves up, so the call is the new user's unless an
+    // admin made it
+    if err := audit.Record(audit.WithUser(ctx, userID), tx, MethodCreateUser, nil, User{ID: userID, Name: name}); err != nil {
+        log.Printf("Create user failed: %v", err)
+        return 0, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        log.Printf("Create user failed: %v", err)
+        return 0, err
+    }
     return userID, nil
 }
 
@@ -52,16 +94,39 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id int32) (string,
 }
 
 func (r *PostgresUserRepository) Delete(ctx context.Context, id int32) (string, error) {
-    query := `DELETE FROM users WHERE user_id = $1`
-    res, err := r.db.ExecContext(ctx, query, id)
+    tx, err := r.db.BeginTx(ctx, nil)
+    if err != nil {
+        log.Printf("Delete user failed: %v", err)
+        return "", err
+    }
+    defer tx.Rollback()
+
+    query := `DELETE FROM users WHERE user_id = $1 RETURNING name`
+    var name string
+    err = tx.QueryRowContext(ctx, query, id).Scan(&name)
     if err != nil {
+        if err == sql.ErrNoRows {
+            return "", fmt.Errorf("no user found to delete")
+        }
         log.Printf("Delete user failed: %v", err)
         return "", err
     }
 
-    rowsAffected, _ := res.RowsAffected()
-    if rowsAffected == 0 {
-        return "", fmt.Errorf("no user found to delete")
+    event := UserEvent{UserID: id, Name: name}
+    if err := outbox.Record(ctx, tx, AggregateUser, strconv.Itoa(int(id)), EventUserDeleted, event); err != nil {
+        log.Printf("Delete user failed: %v", err)
+        return "", err
+    }
+
+    // Users delete their own accounts
+    if err := audit.Record(audit.WithUser(ctx, id), tx, MethodDeleteUser, User{ID: id, Name: name}, nil); err != nil {
+        log.Printf("Delete user failed: %v", err)
+        return "", err
+    }
+
+    if err := tx.Commit(); err != nil {
+        log.Printf("Delete user failed: %v", err)
+        return "", err
     }
 
     return fmt.Sprintf("User with ID %d deleted successfully", id), nil
@@ -7,7 +7,9 @@ import (
 	pb "user-service/pb/proto/user"
 	"user-service/repository"
 
+	"github.com/hasnain-zafar/go-microservices/common/audit"
 	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	auditpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
 
 	"github.com/hasnain-zafar/go-microservices/common/errors"
 	"github.com/hasnain-zafar/go-microservices/common/logger"
@@ -19,23 +21,50 @@ type UserServer struct {
 	logger       *logger.Logger
 	errorHandler *errors.ErrorHandler
 	serviceName  string
+
+	// auditLog is nil when QueryAuditLog is not enabled.
+	auditLog audit.Log
+}
+
+// Option configures optional UserServer features.
+type Option func(*UserServer)
+
+// WithMetrics counts the server's errors in reg rather than in a registry of
+// its own.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(s *UserServer) {
+		s.logger = s.logger.WithMetrics(reg)
+		s.errorHandler = errors.NewErrorHandler(s.logger)
+	}
+}
+
+// WithAuditLog enables QueryAuditLog for callers authenticated as
+// audit.Admin.
+func WithAuditLog(log audit.Log) Option {
+	return func(s *UserServer) {
+		s.auditLog = log
+	}
 }
 
-func NewUserServer(repo repository.UserRepository) *UserServer {
+func NewUserServer(repo repository.UserRepository, opts ...Option) *UserServer {
 	serviceName := "user-service"
 	log := logger.NewLogger(serviceName)
-	return &UserServer{
+	s := &UserServer{
 		repo:         repo,
 		logger:       log,
 		errorHandler: errors.NewErrorHandler(log),
 		serviceName:  serviceName,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
 	method := "CreateUser"
 	metrics.IncrementRequestCounter(s.serviceName, method)
-	s.logger.LogRequest(method, req)
+	s.logger.LogRequest(ctx, method, req)
 
 	if req.GetName() == "" {
 		return nil, s.errorHandler.HandleInvalidArgument("invalid user name", fmt.Errorf("name cannot be empty"))
@@ -48,7 +77,7 @@ func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 
 	res := &pb.CreateUserResponse{UserId: userID}
 
-	s.logger.LogResponse(method, res)
+	s.logger.LogResponse(ctx, method, res)
 
 	return res, nil
 }
@@ -56,7 +85,7 @@ func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
 	method := "GetUser"
 	metrics.IncrementRequestCounter(s.serviceName, method)
-	s.logger.LogRequest(method, req)
+	s.logger.LogRequest(ctx, method, req)
 
 	if req.GetUserId() <= 0 {
 		return nil, s.errorHandler.HandleInvalidArgument("invalid user ID", fmt.Errorf("user ID must be positive"))
@@ -72,7 +101,7 @@ func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.G
 
 	res := &pb.GetUserResponse{Name: name}
 
-	s.logger.LogResponse(method, res)
+	s.logger.LogResponse(ctx, method, res)
 
 	return res, nil
 }
@@ -80,7 +109,7 @@ func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.G
 func (s *UserServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
 	method := "DeleteUser"
 	metrics.IncrementRequestCounter(s.serviceName, method)
-	s.logger.LogRequest(method, req)
+	s.logger.LogRequest(ctx, method, req)
 
 	if req.GetUserId() <= 0 {
 		return nil, s.errorHandler.HandleInvalidArgument("invalid user ID", fmt.Errorf("user ID must be positive"))
@@ -96,7 +125,34 @@ func (s *UserServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest)
 
 	res := &pb.DeleteUserResponse{Message: message}
 
-	s.logger.LogResponse(method, res)
+	s.logger.LogResponse(ctx, method, res)
+
+	return res, nil
+}
+
+func (s *UserServer) QueryAuditLog(ctx context.Context, req *auditpb.QueryAuditLogRequest) (*auditpb.QueryAuditLogResponse, error) {
+	method := "QueryAuditLog"
+	metrics.IncrementRequestCounter(s.serviceName, method)
+	s.logger.LogRequest(ctx, method, req)
+
+	if s.auditLog == nil {
+		return nil, s.errorHandler.HandleFailedPrecondition("audit log is not enabled", fmt.Errorf("no audit log configured"))
+	}
+	if !audit.IsAdmin(ctx) {
+		return nil, s.errorHandler.HandlePermissionDenied("audit log is for admins only", fmt.Errorf("caller is %s, not %s", audit.Actor(ctx), audit.Admin))
+	}
+
+	filter, err := audit.FilterFromProto(req)
+	if err != nil {
+		return nil, s.errorHandler.HandleInvalidArgument("invalid audit log query", err)
+	}
+
+	res, err := audit.Query(ctx, s.auditLog, filter, req.GetVerify())
+	if err != nil {
+		return nil, s.errorHandler.HandleDatabaseError("failed to query audit log", err)
+	}
+
+	s.logger.LogResponse(ctx, method, res)
 
 	return res, nil
 }
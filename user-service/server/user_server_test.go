@@ -4,11 +4,21 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	pb "user-service/pb/proto/user"
 	"user-service/repository/mocks"
 
+	"github.com/hasnain-zafar/go-microservices/common/audit"
+	auditmocks "github.com/hasnain-zafar/go-microservices/common/audit/mocks"
+	"github.com/hasnain-zafar/go-microservices/common/metrics"
+	auditpb "github.com/hasnain-zafar/go-microservices/common/pb/proto/audit"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestCreateUser_Success(t *testing.T) {
@@ -48,12 +58,14 @@ func TestCreateUser_EmptyName(t *testing.T) {
 	assert.Nil(t, resp)
 	// Repository should not be called when validation fails
 	mockRepo.AssertNotCalled(t, "Create")
+	assert.Equal(t, map[string]int64{"invalid_argument": 1}, userServer.logger.Metrics().ErrorCounts())
 }
 
 func TestCreateUser_RepositoryError(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.UserRepository)
-	userServer := NewUserServer(mockRepo)
+	registry := metrics.NewRegistry("user-service")
+	userServer := NewUserServer(mockRepo, WithMetrics(registry))
 
 	ctx := context.Background()
 	req := &pb.CreateUserRequest{Name: "John Doe"}
@@ -68,6 +80,7 @@ func TestCreateUser_RepositoryError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 	mockRepo.AssertExpectations(t)
+	assert.Equal(t, int64(1), registry.ErrorCount("database"))
 }
 
 func TestGetUser_Success(t *testing.T) {
@@ -188,3 +201,76 @@ func TestDeleteUser_NotFound(t *testing.T) {
 	assert.Nil(t, resp)
 	mockRepo.AssertExpectations(t)
 }
+
+func adminContext() context.Context {
+	return audit.WithActor(context.Background(), audit.Admin)
+}
+
+func TestQueryAuditLog_Success(t *testing.T) {
+	// Setup
+	mockLog := new(auditmocks.Log)
+	userServer := NewUserServer(new(mocks.UserRepository), WithAuditLog(mockLog))
+
+	ctx := adminContext()
+	createdAt := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	entry := &audit.Entry{
+		ID:        7,
+		Method:    "DeleteUser",
+		Actor:     audit.Admin,
+		RequestID: "req-42",
+		Peer:      "10.0.0.7:41234",
+		Before:    []byte(`{"user_id":42,"name":"Ali"}`),
+		CreatedAt: createdAt,
+		PrevHash:  "aa",
+		Hash:      "bb",
+	}
+
+	// Expectations
+	mockLog.On("Query", ctx, audit.Filter{Method: "DeleteUser", Limit: 50}).Return([]*audit.Entry{entry}, nil)
+	mockLog.On("Verify", ctx).Return(int64(0), nil)
+
+	// Action
+	resp, err := userServer.QueryAuditLog(ctx, &auditpb.QueryAuditLogRequest{Method: "DeleteUser", Verify: true})
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, resp.Entries, 1)
+	assert.Equal(t, audit.Admin, resp.Entries[0].Actor)
+	assert.Equal(t, `{"user_id":42,"name":"Ali"}`, resp.Entries[0].Before)
+	assert.Empty(t, resp.Entries[0].After)
+	assert.True(t, resp.ChainIntact)
+	mockLog.AssertExpectations(t)
+}
+
+func TestQueryAuditLog_NotAdmin(t *testing.T) {
+	mockLog := new(auditmocks.Log)
+	userServer := NewUserServer(new(mocks.UserRepository), WithAuditLog(mockLog))
+
+	// Claiming to be an admin in the metadata is not authenticating as one
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-actor-id", audit.Admin))
+	_, err := userServer.QueryAuditLog(ctx, &auditpb.QueryAuditLogRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = userServer.QueryAuditLog(audit.WithActor(context.Background(), audit.Anonymous), &auditpb.QueryAuditLogRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = userServer.QueryAuditLog(context.Background(), &auditpb.QueryAuditLogRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	mockLog.AssertNotCalled(t, "Query")
+}
+
+func TestQueryAuditLog_Errors(t *testing.T) {
+	mockLog := new(auditmocks.Log)
+	userServer := NewUserServer(new(mocks.UserRepository), WithAuditLog(mockLog))
+	ctx := adminContext()
+
+	_, err := userServer.QueryAuditLog(ctx, &auditpb.QueryAuditLogRequest{Limit: 1000})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockLog.On("Query", ctx, audit.Filter{Limit: 50}).Return(nil, errors.New("connection refused"))
+	_, err = userServer.QueryAuditLog(ctx, &auditpb.QueryAuditLogRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	_, err = NewUserServer(new(mocks.UserRepository)).QueryAuditLog(ctx, &auditpb.QueryAuditLogRequest{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}